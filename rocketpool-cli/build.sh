@@ -1,8 +1,15 @@
 #!/bin/bash
 
-CGO_ENABLED=0 GOOS=linux GOARCH=amd64 go build -o rocketpool-cli-linux-amd64 rocketpool-cli.go
-CGO_ENABLED=0 GOOS=darwin GOARCH=amd64 go build -o rocketpool-cli-darwin-amd64 rocketpool-cli.go
-#CGO_ENABLED=0 GOOS=windows GOARCH=amd64 go build -o rocketpool-cli-windows-amd64.exe rocketpool-cli.go
+# Release bundles are verified with the release key pinned in shared/services/bundle
+# For testing only, a build can verify bundles with another key by setting TEST_RELEASE_PUBLIC_KEY
+LDFLAGS=""
+if [ -n "$TEST_RELEASE_PUBLIC_KEY" ]; then
+    LDFLAGS="-X github.com/rocket-pool/smartnode/shared/services/bundle.ReleasePublicKey=${TEST_RELEASE_PUBLIC_KEY}"
+fi
 
-CGO_ENABLED=0 GOOS=linux GOARCH=arm64 go build -o rocketpool-cli-linux-arm64 rocketpool-cli.go
-CGO_ENABLED=0 GOOS=darwin GOARCH=arm64 go build -o rocketpool-cli-darwin-arm64 rocketpool-cli.go
+CGO_ENABLED=0 GOOS=linux GOARCH=amd64 go build -ldflags "$LDFLAGS" -o rocketpool-cli-linux-amd64 rocketpool-cli.go
+CGO_ENABLED=0 GOOS=darwin GOARCH=amd64 go build -ldflags "$LDFLAGS" -o rocketpool-cli-darwin-amd64 rocketpool-cli.go
+#CGO_ENABLED=0 GOOS=windows GOARCH=amd64 go build -ldflags "$LDFLAGS" -o rocketpool-cli-windows-amd64.exe rocketpool-cli.go
+
+CGO_ENABLED=0 GOOS=linux GOARCH=arm64 go build -ldflags "$LDFLAGS" -o rocketpool-cli-linux-arm64 rocketpool-cli.go
+CGO_ENABLED=0 GOOS=darwin GOARCH=arm64 go build -ldflags "$LDFLAGS" -o rocketpool-cli-darwin-arm64 rocketpool-cli.go
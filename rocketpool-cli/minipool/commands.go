@@ -239,7 +239,15 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
                 Flags: []cli.Flag{
                     cli.StringFlag{
                         Name:  "prefix, p",
-                        Usage: "The prefix of the address to search for (must start with 0x)",
+                        Usage: "The prefix of the address to search for (must start with 0x; use upper case letters to match the checksummed address)",
+                    },
+                    cli.StringFlag{
+                        Name:  "suffix, x",
+                        Usage: "The suffix of the address to search for (use upper case letters to match the checksummed address)",
+                    },
+                    cli.StringFlag{
+                        Name:  "amount, a",
+                        Usage: "The amount of ETH the minipool will be created with (16, 32, or 0 for trusted nodes)",
                     },
                     cli.StringFlag{
                         Name:  "salt, s",
@@ -260,6 +268,12 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
                     if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
 
                     // Validate flags
+                    if c.String("amount") != "" {
+                        if _, err := cliutils.ValidateDepositEthAmount("deposit amount", c.String("amount")); err != nil { return err }
+                    }
+                    if c.String("salt") != "" {
+                        if _, err := cliutils.ValidateBigInt("salt", c.String("salt")); err != nil { return err }
+                    }
 
                     // Run
                     return findVanitySalt(c)
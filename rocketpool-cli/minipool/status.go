@@ -158,6 +158,21 @@ func printMinipoolDetails(minipool api.MinipoolDetails, latestDelegate common.Ad
         }
     }
 
+    // Beacon deposit details - prelaunch minipools
+    if minipool.DepositCheck != nil {
+        check := minipool.DepositCheck
+        if check.Error != "" {
+    fmt.Printf("Beacon deposits:      unknown (%s)\n", check.Error)
+        } else if !check.ValidDepositFound {
+    fmt.Printf("Beacon deposits:      %d (none valid yet)\n", check.DepositCount)
+        } else if check.WithdrawalCredentialsMatch {
+    fmt.Printf("Beacon deposits:      %d (first valid deposit in block %d has the minipool's withdrawal credentials)\n", check.DepositCount, check.BlockNumber)
+        } else {
+    fmt.Printf("Beacon deposits:      %d\n", check.DepositCount)
+    fmt.Printf("%s*The first valid deposit for this validator (transaction %s) has withdrawal credentials %s instead of the minipool's %s; the minipool will be scrubbed!%s\n", colorYellow, check.TxHash.Hex(), check.WithdrawalCredentials.Hex(), check.ExpectedWithdrawalCredentials.Hex(), colorReset)
+        }
+    }
+
     // Withdrawal details - withdrawable minipools
     if minipool.Status.Status == types.Withdrawable {
     fmt.Printf("Withdrawal available: yes\n")
@@ -0,0 +1,321 @@
+package minipool
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Config
+const VanityChunkSize = 1 << 16
+
+
+// A vanity address pattern
+// Patterns containing upper case letters are matched against the EIP-55 checksummed address
+type vanityPattern struct {
+    prefix string
+    suffix string
+    prefixNibbles []byte
+    suffixNibbles []byte
+    caseSensitive bool
+}
+
+
+// Vanity address search parameters
+type vanitySearch struct {
+    pattern *vanityPattern
+    nodeAddress common.Address
+    minipoolManagerAddress common.Address
+    initHash common.Hash
+    startSalt *big.Int
+    threads int
+
+    // Progress
+    attempts uint64
+    nextChunk uint64
+    workerChunks []uint64
+    lock sync.Mutex
+}
+
+
+// A vanity address search result
+type vanityResult struct {
+    salt *big.Int
+    address common.Address
+}
+
+
+// Create a vanity pattern from a prefix and suffix; both must be hex strings, and the prefix must start with 0x
+func newVanityPattern(prefix, suffix string) (*vanityPattern, error) {
+
+    // Check prefix
+    if prefix != "" {
+        if !strings.HasPrefix(prefix, "0x") {
+            return nil, fmt.Errorf("Prefix must start with 0x.")
+        }
+        prefix = prefix[2:]
+    }
+
+    // Check total length
+    if len(prefix) + len(suffix) == 0 {
+        return nil, fmt.Errorf("Please specify a prefix or suffix to search for.")
+    }
+    if len(prefix) + len(suffix) > common.AddressLength * 2 {
+        return nil, fmt.Errorf("The prefix and suffix cannot be longer than an address.")
+    }
+
+    // Get nibbles
+    prefixNibbles, err := getVanityNibbles(prefix)
+    if err != nil {
+        return nil, fmt.Errorf("Invalid prefix: %w", err)
+    }
+    suffixNibbles, err := getVanityNibbles(suffix)
+    if err != nil {
+        return nil, fmt.Errorf("Invalid suffix: %w", err)
+    }
+
+    // Return
+    return &vanityPattern{
+        prefix: prefix,
+        suffix: suffix,
+        prefixNibbles: prefixNibbles,
+        suffixNibbles: suffixNibbles,
+        caseSensitive: (strings.ToLower(prefix + suffix) != prefix + suffix),
+    }, nil
+
+}
+
+
+// Get the nibble values of a hex string
+func getVanityNibbles(value string) ([]byte, error) {
+    nibbles := make([]byte, len(value))
+    for i := 0; i < len(value); i++ {
+        char := value[i]
+        switch {
+            case char >= '0' && char <= '9': nibbles[i] = char - '0'
+            case char >= 'a' && char <= 'f': nibbles[i] = char - 'a' + 10
+            case char >= 'A' && char <= 'F': nibbles[i] = char - 'A' + 10
+            default: return nil, fmt.Errorf("'%c' is not a hex character", char)
+        }
+    }
+    return nibbles, nil
+}
+
+
+// Check whether an address matches the pattern
+func (p *vanityPattern) matches(address *common.Address) bool {
+
+    // Check nibbles
+    for i, nibble := range p.prefixNibbles {
+        if getAddressNibble(address, i) != nibble {
+            return false
+        }
+    }
+    offset := common.AddressLength * 2 - len(p.suffixNibbles)
+    for i, nibble := range p.suffixNibbles {
+        if getAddressNibble(address, offset + i) != nibble {
+            return false
+        }
+    }
+
+    // Check letter case against the checksummed address
+    if p.caseSensitive {
+        checksummed := address.Hex()[2:]
+        if !strings.HasPrefix(checksummed, p.prefix) || !strings.HasSuffix(checksummed, p.suffix) {
+            return false
+        }
+    }
+
+    // Return
+    return true
+
+}
+
+
+// Get the expected number of attempts required to find a match
+func (p *vanityPattern) getExpectedAttempts() float64 {
+    attempts := math.Pow(16, float64(len(p.prefixNibbles) + len(p.suffixNibbles)))
+    if p.caseSensitive {
+        for _, char := range p.prefix + p.suffix {
+            if (char >= 'a' && char <= 'f') || (char >= 'A' && char <= 'F') {
+                attempts *= 2
+            }
+        }
+    }
+    return attempts
+}
+
+
+// Get the nibble of an address at an index
+func getAddressNibble(address *common.Address, index int) byte {
+    b := address[index / 2]
+    if index % 2 == 0 {
+        return b >> 4
+    }
+    return b & 0x0f
+}
+
+
+// Create a vanity address search
+func newVanitySearch(pattern *vanityPattern, nodeAddress, minipoolManagerAddress common.Address, initHash common.Hash, startSalt *big.Int, threads int) *vanitySearch {
+    return &vanitySearch{
+        pattern: pattern,
+        nodeAddress: nodeAddress,
+        minipoolManagerAddress: minipoolManagerAddress,
+        initHash: initHash,
+        startSalt: startSalt,
+        threads: threads,
+        workerChunks: make([]uint64, threads),
+    }
+}
+
+
+// Run the search until a match is found or the context is cancelled
+func (s *vanitySearch) run(ctx context.Context) *vanityResult {
+
+    // Cancel the remaining workers once a match is found
+    ctx, cancel := context.WithCancel(ctx)
+    defer cancel()
+
+    // Run workers
+    var result *vanityResult
+    var resultLock sync.Mutex
+    wg := new(sync.WaitGroup)
+    wg.Add(s.threads)
+    for i := 0; i < s.threads; i++ {
+        go func(worker int) {
+            defer wg.Done()
+            if found := s.runWorker(ctx, worker); found != nil {
+                resultLock.Lock()
+                if result == nil || found.salt.Cmp(result.salt) < 0 {
+                    result = found
+                }
+                resultLock.Unlock()
+                cancel()
+            }
+        }(i)
+    }
+    wg.Wait()
+
+    // Return
+    return result
+
+}
+
+
+// Search chunks of salts until a match is found or the context is cancelled
+func (s *vanitySearch) runWorker(ctx context.Context, worker int) *vanityResult {
+
+    // Initialize buffers; the node salt input is the node address followed by the salt,
+    // and the CREATE2 input is 0xff, the deployer address, the node salt, and the init code hash
+    keccak := crypto.NewKeccakState()
+    saltInput := make([]byte, common.AddressLength + common.HashLength)
+    copy(saltInput, s.nodeAddress.Bytes())
+    salt := saltInput[common.AddressLength:]
+    create2Input := make([]byte, 1 + common.AddressLength + common.HashLength * 2)
+    create2Input[0] = 0xff
+    copy(create2Input[1:], s.minipoolManagerAddress.Bytes())
+    nodeSalt := create2Input[1 + common.AddressLength : 1 + common.AddressLength + common.HashLength]
+    copy(create2Input[1 + common.AddressLength + common.HashLength:], s.initHash.Bytes())
+    addressHash := make([]byte, common.HashLength)
+    var address common.Address
+
+    for {
+
+        // Get the next chunk
+        chunk := s.claimChunk(worker)
+        chunkSalt := new(big.Int).Add(s.startSalt, new(big.Int).Mul(new(big.Int).SetUint64(chunk), big.NewInt(VanityChunkSize)))
+        chunkSalt.FillBytes(salt)
+
+        // Search the chunk
+        for i := 0; i < VanityChunkSize; i++ {
+
+            // Get the minipool address
+            keccak.Reset()
+            keccak.Write(saltInput)
+            keccak.Read(nodeSalt)
+            keccak.Reset()
+            keccak.Write(create2Input)
+            keccak.Read(addressHash)
+            copy(address[:], addressHash[12:])
+
+            // Check for a match
+            if s.pattern.matches(&address) {
+                atomic.AddUint64(&s.attempts, uint64(i + 1))
+                return &vanityResult{
+                    salt: new(big.Int).SetBytes(salt),
+                    address: address,
+                }
+            }
+
+            // Increment the salt
+            for b := len(salt) - 1; b >= 0; b-- {
+                salt[b]++
+                if salt[b] != 0 { break }
+            }
+
+        }
+        atomic.AddUint64(&s.attempts, VanityChunkSize)
+
+        // Check for cancellation
+        select {
+            case <-ctx.Done():
+                return nil
+            default:
+        }
+
+    }
+
+}
+
+
+// Claim the next chunk of salts for a worker
+func (s *vanitySearch) claimChunk(worker int) uint64 {
+    s.lock.Lock()
+    defer s.lock.Unlock()
+    chunk := s.nextChunk
+    s.nextChunk++
+    s.workerChunks[worker] = chunk
+    return chunk
+}
+
+
+// Get the salt below which every salt has been searched, for resuming the search later
+func (s *vanitySearch) getCheckpointSalt() *big.Int {
+    s.lock.Lock()
+    defer s.lock.Unlock()
+    lowestChunk := s.nextChunk
+    for _, chunk := range s.workerChunks {
+        if chunk < lowestChunk {
+            lowestChunk = chunk
+        }
+    }
+    return new(big.Int).Add(s.startSalt, new(big.Int).Mul(new(big.Int).SetUint64(lowestChunk), big.NewInt(VanityChunkSize)))
+}
+
+
+// Get the number of salts searched so far
+func (s *vanitySearch) getAttempts() uint64 {
+    return atomic.LoadUint64(&s.attempts)
+}
+
+
+// Get the expected time to find a match at a hash rate
+func getVanityExpectedTime(expectedAttempts, hashRate float64) time.Duration {
+    if hashRate <= 0 {
+        return 0
+    }
+    seconds := expectedAttempts / hashRate
+    if seconds > float64(math.MaxInt64 / int64(time.Second)) {
+        return time.Duration(math.MaxInt64)
+    }
+    return time.Duration(seconds * float64(time.Second)).Round(time.Second)
+}
@@ -0,0 +1,178 @@
+package minipool
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// EIP-55 test vector address
+const testVanityAddress = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+
+
+func TestNewVanityPattern(t *testing.T) {
+    tests := []struct {
+        name string
+        prefix string
+        suffix string
+        caseSensitive bool
+        errorContains string
+    }{
+        {
+            name: "prefix",
+            prefix: "0xbeef",
+        },
+        {
+            name: "suffix",
+            suffix: "cafe",
+        },
+        {
+            name: "checksummed",
+            prefix: "0xBeef",
+            caseSensitive: true,
+        },
+        {
+            name: "missing 0x",
+            prefix: "beef",
+            errorContains: "Prefix must start with 0x",
+        },
+        {
+            name: "empty",
+            prefix: "0x",
+            errorContains: "Please specify a prefix or suffix",
+        },
+        {
+            name: "too long",
+            prefix: "0x" + strings.Repeat("0", 30),
+            suffix: strings.Repeat("0", 11),
+            errorContains: "cannot be longer than an address",
+        },
+        {
+            name: "invalid prefix",
+            prefix: "0xbeeg",
+            errorContains: "Invalid prefix: 'g' is not a hex character",
+        },
+        {
+            name: "invalid suffix",
+            suffix: "x1",
+            errorContains: "Invalid suffix: 'x' is not a hex character",
+        },
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            pattern, err := newVanityPattern(test.prefix, test.suffix)
+            if test.errorContains != "" {
+                if err == nil || !strings.Contains(err.Error(), test.errorContains) {
+                    t.Fatalf("expected an error containing '%s', got %v", test.errorContains, err)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            if pattern.caseSensitive != test.caseSensitive {
+                t.Errorf("case sensitive is %t, expected %t", pattern.caseSensitive, test.caseSensitive)
+            }
+        })
+    }
+}
+
+
+func TestVanityPatternMatches(t *testing.T) {
+    address := common.HexToAddress(testVanityAddress)
+    tests := []struct {
+        name string
+        prefix string
+        suffix string
+        expected bool
+    }{
+        {"lower case prefix", "0x5aaeb6", "", true},
+        {"checksummed prefix", "0x5aAeb6", "", true},
+        {"wrong case prefix", "0x5AAeb6", "", false},
+        {"wrong prefix", "0x5aaeb7", "", false},
+        {"lower case suffix", "", "1beaed", true},
+        {"checksummed suffix", "", "1BeAed", true},
+        {"wrong case suffix", "", "1BEAED", false},
+        {"odd length suffix", "", "eaed", true},
+        {"wrong suffix", "", "1beaee", false},
+        {"prefix and suffix", "0x5aa", "aed", true},
+        {"prefix and wrong suffix", "0x5aa", "aee", false},
+        {"whole address", testVanityAddress, "", true},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            pattern, err := newVanityPattern(test.prefix, test.suffix)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if matches := pattern.matches(&address); matches != test.expected {
+                t.Errorf("match is %t, expected %t", matches, test.expected)
+            }
+        })
+    }
+}
+
+
+func TestVanityPatternExpectedAttempts(t *testing.T) {
+    tests := []struct {
+        name string
+        prefix string
+        suffix string
+        expected float64
+    }{
+        {"lower case", "0xbe", "ef", 65536},
+        {"checksummed letters", "0xBe", "", 1024},
+        {"checksummed digits", "0x1A", "2", 8192},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            pattern, err := newVanityPattern(test.prefix, test.suffix)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if attempts := pattern.getExpectedAttempts(); attempts != test.expected {
+                t.Errorf("expected attempts are %f, expected %f", attempts, test.expected)
+            }
+        })
+    }
+}
+
+
+func TestVanitySearch(t *testing.T) {
+    nodeAddress := common.HexToAddress("0x1111111111111111111111111111111111111111")
+    minipoolManagerAddress := common.HexToAddress("0x2222222222222222222222222222222222222222")
+    initHash := crypto.Keccak256Hash([]byte("minipool"))
+    pattern, err := newVanityPattern("0xab", "")
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // Search
+    search := newVanitySearch(pattern, nodeAddress, minipoolManagerAddress, initHash, big.NewInt(1000), 2)
+    result := search.run(context.Background())
+    if result == nil {
+        t.Fatal("no result found")
+    }
+
+    // Check the result is the CREATE2 address for the salt
+    salt := common.LeftPadBytes(result.salt.Bytes(), common.HashLength)
+    nodeSalt := crypto.Keccak256Hash(nodeAddress.Bytes(), salt)
+    expectedAddress := crypto.CreateAddress2(minipoolManagerAddress, nodeSalt, initHash.Bytes())
+    if result.address != expectedAddress {
+        t.Errorf("address is %s, expected %s", result.address.Hex(), expectedAddress.Hex())
+    }
+    if !pattern.matches(&result.address) {
+        t.Errorf("address %s does not match the pattern", result.address.Hex())
+    }
+    if result.salt.Cmp(big.NewInt(1000)) < 0 {
+        t.Errorf("salt %s is below the start salt", result.salt.String())
+    }
+    if checkpoint := search.getCheckpointSalt(); checkpoint.Cmp(big.NewInt(1000)) < 0 || checkpoint.Cmp(result.salt) > 0 {
+        t.Errorf("checkpoint salt %s is outside the searched range", checkpoint.String())
+    }
+
+}
@@ -1,17 +1,23 @@
 package minipool
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"math"
 	"math/big"
+	"os"
+	"os/signal"
+	"path/filepath"
 	"runtime"
 	"strconv"
-	"strings"
-	"sync"
+	"syscall"
 	"time"
 
 	"github.com/dustin/go-humanize"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mitchellh/go-homedir"
 	"github.com/rocket-pool/rocketpool-go/utils/eth"
 	"github.com/urfave/cli"
 
@@ -19,6 +25,26 @@ import (
 	cliutils "github.com/rocket-pool/smartnode/shared/utils/cli"
 )
 
+// Config
+const (
+    VanityCheckpointFile = "vanity-checkpoint.json"
+    VanityCheckpointFileMode = 0600
+    VanityReportInterval = 5 * time.Second
+    VanityCheckpointInterval = 30 * time.Second
+)
+
+
+// A vanity search checkpoint, used to resume a search with the same parameters
+type vanityCheckpoint struct {
+    NodeAddress common.Address              `json:"nodeAddress"`
+    MinipoolManagerAddress common.Address   `json:"minipoolManagerAddress"`
+    InitHash common.Hash                    `json:"initHash"`
+    Prefix string                           `json:"prefix"`
+    Suffix string                           `json:"suffix"`
+    Salt string                             `json:"salt"`
+    Attempts uint64                         `json:"attempts"`
+}
+
 
 func findVanitySalt(c *cli.Context) error {
 
@@ -27,28 +53,24 @@ func findVanitySalt(c *cli.Context) error {
     if err != nil { return err }
     defer rp.Close()
 
-    // Get the target prefix
+    // Get the target pattern
     prefix := c.String("prefix")
-    if prefix == "" {
-        prefix = cliutils.Prompt("Please specify the address prefix you would like to search for (must start with 0x):", "^0x[0-9a-fA-F]+$", "Invalid hex string")
+    suffix := c.String("suffix")
+    if prefix == "" && suffix == "" {
+        prefix = cliutils.Prompt("Please specify the address prefix you would like to search for (must start with 0x; use upper case letters to match the checksummed address):", "^0x[0-9a-fA-F]+$", "Invalid hex string")
     }
-    if !strings.HasPrefix(prefix, "0x") {
-        return fmt.Errorf("Prefix must start with 0x.")
-    }
-    targetPrefix, success := big.NewInt(0).SetString(prefix, 0)
-    if !success {
-        return fmt.Errorf("Invalid prefix: %s", prefix)
+    pattern, err := newVanityPattern(prefix, suffix)
+    if err != nil {
+        return err
     }
 
     // Get the starting salt
-    saltString := c.String("salt")
     var salt *big.Int
-    if saltString == "" {
-        salt = big.NewInt(0)
-    } else {
-        salt, success = big.NewInt(0).SetString(saltString, 0)
+    if c.String("salt") != "" {
+        var success bool
+        salt, success = big.NewInt(0).SetString(c.String("salt"), 0)
         if !success {
-            return fmt.Errorf("Invalid starting salt: %s", salt)
+            return fmt.Errorf("Invalid starting salt: %s", c.String("salt"))
         }
     }
 
@@ -113,41 +135,109 @@ func findVanitySalt(c *cli.Context) error {
         return err
     }
 
-    // Set up some variables
-    nodeAddress := vanityArtifacts.NodeAddress.Bytes()
-    minipoolManagerAddress := vanityArtifacts.MinipoolManagerAddress
-    initHash := vanityArtifacts.InitHash.Bytes()
-    shiftAmount := uint(42 - len(prefix))
-
-    // Run the search
-    fmt.Printf("Running with %d threads.\n", threads)
+    // Get the checkpoint to resume from, if one exists for this search and no starting salt was given
+    checkpointPath, err := homedir.Expand(filepath.Join(c.GlobalString("config-path"), VanityCheckpointFile))
+    if err != nil {
+        return fmt.Errorf("Could not get the vanity checkpoint path: %w", err)
+    }
+    checkpoint := vanityCheckpoint{
+        NodeAddress: vanityArtifacts.NodeAddress,
+        MinipoolManagerAddress: vanityArtifacts.MinipoolManagerAddress,
+        InitHash: vanityArtifacts.InitHash,
+        Prefix: pattern.prefix,
+        Suffix: pattern.suffix,
+    }
+    previousAttempts := uint64(0)
+    if salt == nil {
+        salt = big.NewInt(0)
+        if saved, err := loadVanityCheckpoint(checkpointPath); err == nil && saved.matches(checkpoint) {
+            if savedSalt, success := big.NewInt(0).SetString(saved.Salt, 0); success {
+                salt = savedSalt
+                previousAttempts = saved.Attempts
+                fmt.Printf("Resuming the previous search from salt 0x%x (%s salts already searched).\n", salt, humanize.Comma(int64(previousAttempts)))
+            }
+        }
+    }
 
-    wg := new(sync.WaitGroup)
-    wg.Add(threads)
-    stop := false
-    stopPtr := &stop
+    // Print the search info
+    expectedAttempts := pattern.getExpectedAttempts()
+    fmt.Printf("Searching for a minipool address for node %s with prefix '0x%s' and suffix '%s'", vanityArtifacts.NodeAddress.Hex(), pattern.prefix, pattern.suffix)
+    if pattern.caseSensitive {
+        fmt.Print(" (matching the checksummed address)")
+    }
+    fmt.Printf(".\nThis will take %s attempts on average.\n", humanize.Comma(int64(math.Min(expectedAttempts, math.MaxInt64))))
+    fmt.Printf("Running with %d threads; press CTRL+C to stop and save your progress.\n\n", threads)
+
+    // Stop the search on interrupt
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    interrupt := make(chan os.Signal, 1)
+    signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+    defer signal.Stop(interrupt)
+    go func() {
+        select {
+            case <-interrupt: cancel()
+            case <-ctx.Done():
+        }
+    }()
 
-    // Spawn worker threads
+    // Run the search and report progress until it stops
+    search := newVanitySearch(pattern, vanityArtifacts.NodeAddress, vanityArtifacts.MinipoolManagerAddress, vanityArtifacts.InitHash, salt, threads)
+    done := make(chan *vanityResult, 1)
     start := time.Now()
-    for i := 0; i < threads; i++ {
-        saltOffset := big.NewInt(int64(i))
-        workerSalt := big.NewInt(0).Add(salt, saltOffset)
-
-        go func (i int) {
-            foundSalt, foundAddress := runWorker(i == 0, stopPtr, targetPrefix, nodeAddress, minipoolManagerAddress, initHash, workerSalt, int64(threads), shiftAmount)
-            if foundSalt != nil {
-                fmt.Printf("Found on thread %d: salt 0x%x = %s\n", i, foundSalt, foundAddress.Hex())
-                *stopPtr = true
-            }
-            wg.Done()
-        }(i)
+    go func() {
+        done <- search.run(ctx)
+    }()
+    var result *vanityResult
+    reportTicker := time.NewTicker(VanityReportInterval)
+    defer reportTicker.Stop()
+    checkpointTicker := time.NewTicker(VanityCheckpointInterval)
+    defer checkpointTicker.Stop()
+    lastAttempts := uint64(0)
+    lastReport := start
+    for running := true; running; {
+        select {
+            case result = <-done:
+                running = false
+            case now := <-reportTicker.C:
+                attempts := search.getAttempts()
+                hashRate := float64(attempts - lastAttempts) / now.Sub(lastReport).Seconds()
+                lastAttempts, lastReport = attempts, now
+                hashRateValue, hashRateSuffix := humanize.ComputeSI(hashRate)
+                fmt.Printf("%s elapsed, %s salts searched (%s%s salts/sec); expected time to find a match: %s\n",
+                    time.Since(start).Round(time.Second),
+                    humanize.Comma(int64(previousAttempts + attempts)),
+                    humanize.FtoaWithDigits(hashRateValue, 2), hashRateSuffix,
+                    getVanityExpectedTime(expectedAttempts, hashRate))
+            case <-checkpointTicker.C:
+                checkpoint.Salt = fmt.Sprintf("0x%x", search.getCheckpointSalt())
+                checkpoint.Attempts = previousAttempts + search.getAttempts()
+                if err := saveVanityCheckpoint(checkpointPath, checkpoint); err != nil {
+                    fmt.Printf("WARNING: could not save the search checkpoint: %s\n", err.Error())
+                }
+        }
+    }
+    elapsed := time.Since(start).Round(time.Millisecond)
+
+    // Handle interruption
+    if result == nil {
+        checkpoint.Salt = fmt.Sprintf("0x%x", search.getCheckpointSalt())
+        checkpoint.Attempts = previousAttempts + search.getAttempts()
+        if err := saveVanityCheckpoint(checkpointPath, checkpoint); err != nil {
+            return fmt.Errorf("Search stopped after %s, but the checkpoint could not be saved: %w", elapsed, err)
+        }
+        fmt.Printf("\nSearch stopped after %s. Run the same command again to resume from salt %s.\n", elapsed, checkpoint.Salt)
+        return nil
     }
 
-    // Wait for the workers to finish and print the elapsed time 
-    wg.Wait()
-    end := time.Now()
-    elapsed := end.Sub(start)
-    fmt.Printf("Finished in %s\n", elapsed)
+    // Remove the completed search's checkpoint
+    if err := os.Remove(checkpointPath); err != nil && !os.IsNotExist(err) {
+        fmt.Printf("WARNING: could not remove the search checkpoint: %s\n", err.Error())
+    }
+
+    // Print the result
+    fmt.Printf("\nFound a match in %s after %s salts: salt 0x%x = %s\n", elapsed, humanize.Comma(int64(previousAttempts + search.getAttempts())), result.salt, result.address.Hex())
+    fmt.Printf("To create this minipool, run:\n    rocketpool node deposit --amount %s --salt 0x%x\n", strconv.FormatFloat(amount, 'f', -1, 64), result.salt)
 
     // Return
     return nil
@@ -155,59 +245,35 @@ func findVanitySalt(c *cli.Context) error {
 }
 
 
-func runWorker(report bool, stop *bool, targetPrefix *big.Int, nodeAddress []byte, minipoolManagerAddress common.Address, initHash []byte, salt *big.Int, increment int64, shiftAmount uint) (*big.Int, common.Address) {
-    saltBytes := [32]byte{}
-    hashInt := big.NewInt(0)
-    zero := big.NewInt(0)
-    incrementInt := big.NewInt(increment)
-
-    // Set up the reporting ticker if requested
-    var ticker *time.Ticker
-    var tickerChan chan struct{}
-    lastSalt := big.NewInt(0).Set(salt)
-    if report {
-        start := time.Now()
-        reportInterval := 5 * time.Second
-        ticker = time.NewTicker(reportInterval)
-        tickerChan = make(chan struct{})
-        go func() {
-            for {
-            select {
-                case <- ticker.C:
-                    delta := big.NewInt(0).Sub(salt, lastSalt)
-                    deltaFloat, suffix := humanize.ComputeSI(float64(delta.Uint64()) / 5.0)
-                    deltaString := humanize.FtoaWithDigits(deltaFloat, 2) + suffix
-                    fmt.Printf("At salt 0x%x... %s (%s salts/sec)\n", salt, time.Since(start), deltaString)
-                    lastSalt.Set(salt)
-                case <- tickerChan:
-                    ticker.Stop()
-                    return
-                }
-            }
-        }()
-    }
+// Check whether a checkpoint was saved by a search with the same parameters
+func (cp vanityCheckpoint) matches(other vanityCheckpoint) bool {
+    return cp.NodeAddress == other.NodeAddress &&
+        cp.MinipoolManagerAddress == other.MinipoolManagerAddress &&
+        cp.InitHash == other.InitHash &&
+        cp.Prefix == other.Prefix &&
+        cp.Suffix == other.Suffix
+}
 
-    // Run the main salt finder loop
-    for {
-        if *stop {
-            return nil, common.Address{}
-        }
 
-        salt.FillBytes(saltBytes[:])
-        nodeSalt := crypto.Keccak256Hash(nodeAddress, saltBytes[:])
-    
-        address := crypto.CreateAddress2(minipoolManagerAddress, nodeSalt, initHash)
-        hashInt.SetBytes(address.Bytes())
-        hashInt.Rsh(hashInt, shiftAmount * 4)
-        hashInt.Xor(hashInt, targetPrefix)
-        if hashInt.Cmp(zero) == 0 {
-            if report {
-                close(tickerChan)
-            }
-            return salt, address
-        } else {
-            salt.Add(salt, incrementInt)
-        }
+// Load a vanity search checkpoint
+func loadVanityCheckpoint(path string) (vanityCheckpoint, error) {
+    bytes, err := ioutil.ReadFile(path)
+    if err != nil {
+        return vanityCheckpoint{}, err
     }
+    var checkpoint vanityCheckpoint
+    if err := json.Unmarshal(bytes, &checkpoint); err != nil {
+        return vanityCheckpoint{}, fmt.Errorf("Could not decode vanity checkpoint: %w", err)
+    }
+    return checkpoint, nil
 }
 
+
+// Save a vanity search checkpoint
+func saveVanityCheckpoint(path string, checkpoint vanityCheckpoint) error {
+    bytes, err := json.Marshal(checkpoint)
+    if err != nil {
+        return fmt.Errorf("Could not encode vanity checkpoint: %w", err)
+    }
+    return ioutil.WriteFile(path, bytes, VanityCheckpointFileMode)
+}
@@ -1,6 +1,9 @@
 package node
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/urfave/cli"
 
 	cliutils "github.com/rocket-pool/smartnode/shared/utils/cli"
@@ -89,6 +92,86 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
                 },
             },
 
+            cli.Command{
+                Name:      "tx-history",
+                Aliases:   []string{"x"},
+                Usage:     "Get the history of transactions sent by the node",
+                UsageText: "rocketpool node tx-history [options]",
+                Flags: []cli.Flag{
+                    cli.StringFlag{
+                        Name:  "format, f",
+                        Usage: "The output format ('table', 'csv' or 'json')",
+                        Value: "table",
+                    },
+                    cli.StringFlag{
+                        Name:  "output, o",
+                        Usage: "Write the transaction history to a `file` instead of the terminal",
+                    },
+                },
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
+                    // Validate flags
+                    format, err := cliutils.ValidateExportFormat("format", c.String("format"))
+                    if err != nil { return err }
+
+                    // Run
+                    return getTxHistory(c, format)
+
+                },
+            },
+
+            cli.Command{
+                Name:      "report",
+                Aliases:   []string{"o"},
+                Usage:     "Generate a report of the node's income and costs over a period for tax and accounting purposes",
+                UsageText: "rocketpool node report --from date [options]",
+                Flags: []cli.Flag{
+                    cli.StringFlag{
+                        Name:  "from",
+                        Usage: "The start of the period (YYYY-MM-DD or RFC3339 time)",
+                    },
+                    cli.StringFlag{
+                        Name:  "to",
+                        Usage: "The end of the period (YYYY-MM-DD or RFC3339 time; defaults to now)",
+                    },
+                    cli.StringFlag{
+                        Name:  "format, f",
+                        Usage: "The output format ('table', 'csv' or 'json')",
+                        Value: "table",
+                    },
+                    cli.StringFlag{
+                        Name:  "output, o",
+                        Usage: "Write the report to a `file` instead of the terminal",
+                    },
+                },
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
+                    // Validate flags
+                    from, err := cliutils.ValidateDate("from", c.String("from"))
+                    if err != nil { return err }
+                    to := time.Now()
+                    if c.String("to") != "" {
+                        to, err = cliutils.ValidateDate("to", c.String("to"))
+                        if err != nil { return err }
+                    }
+                    if !from.Before(to) {
+                        return fmt.Errorf("The start of the period must be before its end")
+                    }
+                    format, err := cliutils.ValidateExportFormat("format", c.String("format"))
+                    if err != nil { return err }
+
+                    // Run
+                    return getReport(c, from, to, format)
+
+                },
+            },
+
             cli.Command{
                 Name:      "set-withdrawal-address",
                 Aliases:   []string{"w"},
@@ -0,0 +1,135 @@
+package node
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services/rocketpool"
+	"github.com/rocket-pool/smartnode/shared/types/api"
+)
+
+// Report columns
+var reportHeader = []string{"Time", "Block", "Type", "Minipool", "Tx Hash", "ETH Amount", "RPL Amount", "RPL Price (ETH)", "ETH Value"}
+
+
+func getReport(c *cli.Context, from, to time.Time, format string) error {
+
+    // Get RP client
+    rp, err := rocketpool.NewClientFromCtx(c)
+    if err != nil { return err }
+    defer rp.Close()
+
+    // Get report
+    if format == "table" || c.String("output") != "" {
+        fmt.Println("Building the report; this may take a while for long periods...")
+    }
+    report, err := rp.NodeReport(from, to)
+    if err != nil {
+        return err
+    }
+
+    // Get output writer
+    output, closeOutput, err := openExportOutput(c.String("output"))
+    if err != nil {
+        return err
+    }
+    defer closeOutput()
+
+    // Print report
+    switch format {
+        case "json":
+            encoder := json.NewEncoder(output)
+            encoder.SetIndent("", "    ")
+            if err := encoder.Encode(report); err != nil {
+                return fmt.Errorf("Could not encode report: %w", err)
+            }
+        case "csv":
+            writer := csv.NewWriter(output)
+            if err := writer.Write(reportHeader); err != nil {
+                return err
+            }
+            for _, entry := range report.Entries {
+                if err := writer.Write(getReportRow(entry)); err != nil {
+                    return err
+                }
+            }
+            writer.Flush()
+            if err := writer.Error(); err != nil {
+                return fmt.Errorf("Could not write report: %w", err)
+            }
+        default:
+            fmt.Fprintf(output, "Report for node %s from %s (block %d, epoch %d) to %s (block %d, epoch %d):\n\n",
+                report.AccountAddress.Hex(),
+                report.From.Format(time.RFC822), report.FromBlock, report.FromEpoch,
+                report.To.Format(time.RFC822), report.ToBlock, report.ToEpoch)
+            if len(report.Totals) == 0 {
+                fmt.Fprintln(output, "There was no income or costs during this period.")
+                break
+            }
+            types := make([]string, 0, len(report.Totals))
+            for entryType := range report.Totals {
+                types = append(types, entryType)
+            }
+            sort.Strings(types)
+            writer := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+            fmt.Fprintln(writer, "Type\tETH Amount\tRPL Amount\tETH Value")
+            for _, entryType := range types {
+                total := report.Totals[entryType]
+                fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", entryType, formatWei(total.EthAmount), formatWei(total.RplAmount), formatWei(total.EthValue))
+            }
+            if err := writer.Flush(); err != nil {
+                return err
+            }
+            fmt.Fprintln(output, "\nNote: beacon-rewards is the node's share of its validators' balance growth and includes commission.")
+            fmt.Fprintln(output, "Gas costs only include transactions sent by this smartnode. Use --format csv or json for the individual entries.")
+    }
+
+    // Log
+    if c.String("output") != "" {
+        fmt.Printf("Wrote %d report entries to %s.\n", len(report.Entries), c.String("output"))
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Get a report row for CSV export
+func getReportRow(entry api.NodeReportEntry) []string {
+
+    // Get optional values
+    minipool := ""
+    if entry.Minipool != nil {
+        minipool = entry.Minipool.Hex()
+    }
+    txHash := ""
+    if entry.TxHash != nil {
+        txHash = entry.TxHash.Hex()
+    }
+    rplPrice := ""
+    if entry.RplPrice != nil {
+        rplPrice = formatWei(entry.RplPrice)
+    }
+
+    // Return
+    return []string{
+        entry.Time.UTC().Format(time.RFC3339),
+        strconv.FormatUint(entry.Block, 10),
+        entry.Type,
+        minipool,
+        txHash,
+        formatWei(entry.EthAmount),
+        formatWei(entry.RplAmount),
+        rplPrice,
+        formatWei(entry.EthValue),
+    }
+
+}
@@ -17,6 +17,11 @@ import (
 
 func getStatus(c *cli.Context) error {
 
+    // Get the status of all nodes
+    if c.GlobalBool("all-nodes") {
+        return getAllNodesStatus(c)
+    }
+
     // Get RP client
     rp, err := rocketpool.NewClientFromCtx(c)
     if err != nil { return err }
@@ -138,3 +143,29 @@ func getStatus(c *cli.Context) error {
 
 }
 
+
+// Print a summary of every node's status in a single table
+func getAllNodesStatus(c *cli.Context) error {
+    return cliutils.PrintAllNodes(c, []string{"Network", "Account", "ETH", "RPL", "Registered", "RPL Stake", "Collateral", "Minipools"}, func(rp *rocketpool.Client) ([]string, error) {
+        network, err := cliutils.GetNetworkName(rp)
+        if err != nil { return nil, err }
+        status, err := rp.NodeStatus()
+        if err != nil { return nil, err }
+        row := []string{
+            network,
+            status.AccountAddress.Hex(),
+            fmt.Sprintf("%.6f", math.RoundDown(eth.WeiToEth(status.AccountBalances.ETH), 6)),
+            fmt.Sprintf("%.6f", math.RoundDown(eth.WeiToEth(status.AccountBalances.RPL), 6)),
+            fmt.Sprintf("%t", status.Registered),
+            "-",
+            "-",
+            "-",
+        }
+        if status.Registered {
+            row[5] = fmt.Sprintf("%.6f", math.RoundDown(eth.WeiToEth(status.RplStake), 6))
+            row[6] = fmt.Sprintf("%.2f%%", status.CollateralRatio * 100)
+            row[7] = fmt.Sprintf("%d staking / %d total", status.MinipoolCounts.Staking, status.MinipoolCounts.Total)
+        }
+        return row, nil
+    })
+}
@@ -15,6 +15,11 @@ var ethClientRecentBlockThreshold, _ = time.ParseDuration("5m")
 
 func getSyncProgress(c *cli.Context) error {
 
+    // Get the sync progress of all nodes
+    if c.GlobalBool("all-nodes") {
+        return getAllNodesSyncProgress(c)
+    }
+
     // Get RP client
     rp, err := rocketpool.NewClientFromCtx(c)
     if err != nil { return err }
@@ -80,3 +85,27 @@ func getSyncProgress(c *cli.Context) error {
 
 }
 
+
+// Print every node's sync progress in a single table
+func getAllNodesSyncProgress(c *cli.Context) error {
+    return cliutils.PrintAllNodes(c, []string{"Network", "Eth1", "Eth2"}, func(rp *rocketpool.Client) ([]string, error) {
+        network, err := cliutils.GetNetworkName(rp)
+        if err != nil { return nil, err }
+        status, err := rp.NodeSync()
+        if err != nil { return nil, err }
+        eth1Status := fmt.Sprintf("syncing (%0.2f%%)", status.Eth1Progress * 100)
+        if status.Eth1Synced {
+            eth1Status = "synced"
+            if status.Eth1LatestBlockTime + uint64(ethClientRecentBlockThreshold.Seconds()) <= uint64(time.Now().Unix()) {
+                eth1Status = "synced (stale latest block)"
+            }
+        }
+        eth2Status := "syncing"
+        if status.Eth2Synced {
+            eth2Status = "synced"
+        } else if status.Eth2Progress != -1 {
+            eth2Status = fmt.Sprintf("syncing (%0.2f%%)", status.Eth2Progress * 100)
+        }
+        return []string{network, eth1Status, eth2Status}, nil
+    })
+}
@@ -0,0 +1,131 @@
+package node
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services/rocketpool"
+	"github.com/rocket-pool/smartnode/shared/types/api"
+)
+
+// Transaction history columns
+var txHistoryHeader = []string{"Time", "Origin", "Hash", "Status", "Block", "Nonce", "To", "ETH Sent", "Gas Used", "Gas Price (gwei)", "Gas Cost (ETH)", "RPL Sent", "RPL Received"}
+
+
+func getTxHistory(c *cli.Context, format string) error {
+
+    // Get RP client
+    rp, err := rocketpool.NewClientFromCtx(c)
+    if err != nil { return err }
+    defer rp.Close()
+
+    // Get transaction history
+    history, err := rp.NodeTxHistory()
+    if err != nil {
+        return err
+    }
+
+    // Get output writer
+    output, closeOutput, err := openExportOutput(c.String("output"))
+    if err != nil {
+        return err
+    }
+    defer closeOutput()
+
+    // Print transactions
+    switch format {
+        case "json":
+            encoder := json.NewEncoder(output)
+            encoder.SetIndent("", "    ")
+            if err := encoder.Encode(history.Transactions); err != nil {
+                return fmt.Errorf("Could not encode transaction history: %w", err)
+            }
+        case "csv":
+            writer := csv.NewWriter(output)
+            if err := writer.Write(txHistoryHeader); err != nil {
+                return err
+            }
+            for _, tx := range history.Transactions {
+                if err := writer.Write(getTxHistoryRow(tx)); err != nil {
+                    return err
+                }
+            }
+            writer.Flush()
+            if err := writer.Error(); err != nil {
+                return fmt.Errorf("Could not write transaction history: %w", err)
+            }
+        default:
+            if len(history.Transactions) == 0 {
+                fmt.Fprintln(output, "The node has not sent any transactions.")
+                break
+            }
+            writer := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+            fmt.Fprintln(writer, "Time\tOrigin\tHash\tStatus\tETH Sent\tGas Cost (ETH)\tRPL Sent\tRPL Received")
+            for _, tx := range history.Transactions {
+                fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+                    tx.Time.Format(time.RFC822),
+                    tx.Origin,
+                    tx.Hash.Hex(),
+                    tx.Status,
+                    formatWei(tx.Value),
+                    formatWei(tx.GasCost),
+                    formatWei(tx.RplSent),
+                    formatWei(tx.RplReceived))
+            }
+            if err := writer.Flush(); err != nil {
+                return err
+            }
+    }
+
+    // Log
+    if c.String("output") != "" {
+        fmt.Printf("Wrote %d transaction(s) to %s.\n", len(history.Transactions), c.String("output"))
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Get a transaction history row for CSV export
+func getTxHistoryRow(tx api.NodeTransaction) []string {
+
+    // Get optional values
+    to := ""
+    if tx.To != nil {
+        to = tx.To.Hex()
+    }
+    block := ""
+    if tx.BlockNumber != 0 {
+        block = strconv.FormatUint(tx.BlockNumber, 10)
+    }
+    gasPrice := ""
+    if tx.EffectiveGasPrice != nil {
+        gasPrice = formatGwei(tx.EffectiveGasPrice)
+    }
+
+    // Return
+    return []string{
+        tx.Time.UTC().Format(time.RFC3339),
+        tx.Origin,
+        tx.Hash.Hex(),
+        tx.Status,
+        block,
+        strconv.FormatUint(tx.Nonce, 10),
+        to,
+        formatWei(tx.Value),
+        strconv.FormatUint(tx.GasUsed, 10),
+        gasPrice,
+        formatWei(tx.GasCost),
+        formatWei(tx.RplSent),
+        formatWei(tx.RplReceived),
+    }
+
+}
@@ -3,8 +3,11 @@ package node
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"math/big"
 	"net/http"
+	"os"
 	"os/exec"
 	"strconv"
 	"strings"
@@ -12,6 +15,9 @@ import (
 	cliutils "github.com/rocket-pool/smartnode/shared/utils/cli"
 )
 
+// Config
+const ExportFileMode = 0644
+
 // FreeGeoIP config
 const FreeGeoIPURL = "https://freegeoip.app/json/"
 
@@ -114,3 +120,45 @@ func promptMinNodeFee(networkCurrentNodeFee, networkMinNodeFee float64) float64
 
 }
 
+
+
+// Format a wei amount as an exact decimal amount of ether
+func formatWei(amount *big.Int) string {
+    if amount == nil {
+        return "0"
+    }
+    sign := ""
+    if amount.Sign() < 0 {
+        sign = "-"
+    }
+    digits := new(big.Int).Abs(amount).String()
+    if len(digits) < 19 {
+        digits = strings.Repeat("0", 19 - len(digits)) + digits
+    }
+    whole := strings.TrimLeft(digits[:len(digits) - 18], "0")
+    if whole == "" { whole = "0" }
+    fraction := strings.TrimRight(digits[len(digits) - 18:], "0")
+    if fraction == "" {
+        return sign + whole
+    }
+    return fmt.Sprintf("%s%s.%s", sign, whole, fraction)
+}
+
+
+// Format a wei amount as an exact decimal amount of gwei
+func formatGwei(amount *big.Int) string {
+    return formatWei(new(big.Int).Mul(amount, big.NewInt(1e9)))
+}
+
+
+// Open the writer for an exported report; writes to the terminal if no file path is given
+func openExportOutput(path string) (io.Writer, func(), error) {
+    if path == "" {
+        return os.Stdout, func() {}, nil
+    }
+    file, err := os.OpenFile(path, os.O_CREATE | os.O_TRUNC | os.O_WRONLY, ExportFileMode)
+    if err != nil {
+        return nil, nil, fmt.Errorf("Could not open output file: %w", err)
+    }
+    return file, func() { file.Close() }, nil
+}
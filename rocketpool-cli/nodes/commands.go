@@ -0,0 +1,94 @@
+package nodes
+
+import (
+    "github.com/urfave/cli"
+
+    cliutils "github.com/rocket-pool/smartnode/shared/utils/cli"
+)
+
+
+// Register commands
+func RegisterCommands(app *cli.App, name string, aliases []string) {
+    app.Commands = append(app.Commands, cli.Command{
+        Name:      name,
+        Aliases:   aliases,
+        Usage:     "Manage the named node profiles used with --node and --all-nodes",
+        Subcommands: []cli.Command{
+
+            cli.Command{
+                Name:      "list",
+                Aliases:   []string{"l"},
+                Usage:     "List the node profiles",
+                UsageText: "rocketpool nodes list",
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
+                    // Run
+                    return listNodes(c)
+
+                },
+            },
+
+            cli.Command{
+                Name:      "add",
+                Aliases:   []string{"a"},
+                Usage:     "Add a node profile, or replace an existing profile with the same name",
+                UsageText: "rocketpool nodes add name --host address --user name --key file [options]",
+                Flags: []cli.Flag{
+                    cli.StringFlag{
+                        Name:  "host, o",
+                        Usage: "The node's SSH host `address`",
+                    },
+                    cli.StringFlag{
+                        Name:  "user, u",
+                        Usage: "The node's SSH user `name`",
+                    },
+                    cli.StringFlag{
+                        Name:  "key, k",
+                        Usage: "The node's SSH key `file`",
+                    },
+                    cli.StringFlag{
+                        Name:  "known-hosts, n",
+                        Usage: "SSH known_hosts `file` (default: current user's ~/.ssh/known_hosts)",
+                    },
+                    cli.StringFlag{
+                        Name:  "config-path, c",
+                        Usage: "Rocket Pool config asset `path` on the node (default: ~/.rocketpool)",
+                    },
+                    cli.StringFlag{
+                        Name:  "daemon-path, d",
+                        Usage: "Rocket Pool service daemon `path` on the node, if running outside of docker",
+                    },
+                },
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
+
+                    // Run
+                    return addNode(c, c.Args().Get(0))
+
+                },
+            },
+
+            cli.Command{
+                Name:      "remove",
+                Aliases:   []string{"r"},
+                Usage:     "Remove a node profile",
+                UsageText: "rocketpool nodes remove name",
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
+
+                    // Run
+                    return removeNode(c, c.Args().Get(0))
+
+                },
+            },
+
+        },
+    })
+}
@@ -0,0 +1,90 @@
+package nodes
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services/rocketpool"
+)
+
+
+// List the node profiles
+func listNodes(c *cli.Context) error {
+
+    // Get node profiles
+    profiles, err := rocketpool.LoadNodeProfilesFromCtx(c)
+    if err != nil { return err }
+    if len(profiles.Nodes) == 0 {
+        fmt.Printf("There are no nodes in %s.\n", c.GlobalString("nodes-file"))
+        return nil
+    }
+
+    // Print profiles
+    writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+    fmt.Fprintln(writer, "Name\tHost\tUser\tKey\tConfig Path")
+    for _, profile := range profiles.Nodes {
+        configPath := profile.ConfigPath
+        if configPath == "" {
+            configPath = rocketpool.DefaultNodeConfigPath
+        }
+        fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", profile.Name, profile.Host, profile.User, profile.Key, configPath)
+    }
+    return writer.Flush()
+
+}
+
+
+// Add or replace a node profile
+func addNode(c *cli.Context, name string) error {
+
+    // Get node profiles
+    profiles, err := rocketpool.LoadNodeProfilesFromCtx(c)
+    if err != nil { return err }
+    replaced := profiles.Get(name) != nil
+
+    // Add profile
+    if err := profiles.Set(rocketpool.NodeProfile{
+        Name: name,
+        Host: c.String("host"),
+        User: c.String("user"),
+        Key: c.String("key"),
+        KnownHosts: c.String("known-hosts"),
+        ConfigPath: c.String("config-path"),
+        DaemonPath: c.String("daemon-path"),
+    }); err != nil {
+        return err
+    }
+    if err := profiles.Save(c.GlobalString("nodes-file")); err != nil { return err }
+
+    // Log & return
+    if replaced {
+        fmt.Printf("The node '%s' was updated.\n", name)
+    } else {
+        fmt.Printf("The node '%s' was added; select it with 'rocketpool --node %s'.\n", name, name)
+    }
+    return nil
+
+}
+
+
+// Remove a node profile
+func removeNode(c *cli.Context, name string) error {
+
+    // Get node profiles
+    profiles, err := rocketpool.LoadNodeProfilesFromCtx(c)
+    if err != nil { return err }
+
+    // Remove profile
+    if !profiles.Remove(name) {
+        return fmt.Errorf("Unknown node '%s'.", name)
+    }
+    if err := profiles.Save(c.GlobalString("nodes-file")); err != nil { return err }
+
+    // Log & return
+    fmt.Printf("The node '%s' was removed.\n", name)
+    return nil
+
+}
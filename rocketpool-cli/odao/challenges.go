@@ -0,0 +1,157 @@
+package odao
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/rocketpool-go/dao/trustednode"
+	"github.com/rocket-pool/rocketpool-go/utils/eth"
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services/gas"
+	"github.com/rocket-pool/smartnode/shared/services/rocketpool"
+	cliutils "github.com/rocket-pool/smartnode/shared/utils/cli"
+	"github.com/rocket-pool/smartnode/shared/utils/math"
+)
+
+
+func getChallenges(c *cli.Context) error {
+
+    // Get RP client
+    rp, err := rocketpool.NewClientFromCtx(c)
+    if err != nil { return err }
+    defer rp.Close()
+
+    // Get active challenges
+    response, err := rp.TNDAOChallenges()
+    if err != nil {
+        return err
+    }
+
+    // Print & return
+    if len(response.Challenges) == 0 {
+        fmt.Println("There are no active challenges against oracle DAO members.")
+        return nil
+    }
+    fmt.Printf("There are %d active challenges against oracle DAO members (challenge window: %d blocks):\n", len(response.Challenges), response.ChallengeWindow)
+    fmt.Println("")
+    for _, challenge := range response.Challenges {
+        fmt.Printf("--------------------\n")
+        fmt.Printf("\n")
+        fmt.Printf("Member ID:            %s\n", challenge.MemberId)
+        fmt.Printf("Node address:         %s\n", challenge.MemberAddress.Hex())
+        fmt.Printf("Challenged by:        %s\n", challenge.ChallengerAddress.Hex())
+        fmt.Printf("Challenged at block:  %d\n", challenge.ChallengedBlock)
+        fmt.Printf("Response deadline:    block %d (%d blocks remaining)\n", challenge.DeadlineBlock, challenge.BlocksRemaining)
+        if challenge.Expired {
+            fmt.Printf("Status:               EXPIRED - the member can be removed from the oracle DAO\n")
+        } else {
+            fmt.Printf("Status:               awaiting response\n")
+        }
+        fmt.Printf("\n")
+    }
+    return nil
+
+}
+
+
+func makeChallenge(c *cli.Context) error {
+
+    // Get RP client
+    rp, err := rocketpool.NewClientFromCtx(c)
+    if err != nil { return err }
+    defer rp.Close()
+
+    // Get DAO members
+    members, err := rp.TNDAOMembers()
+    if err != nil {
+        return err
+    }
+
+    // Get member to challenge
+    var selectedMember trustednode.MemberDetails
+    if c.String("member") != "" {
+
+        // Get matching member
+        selectedAddress := common.HexToAddress(c.String("member"))
+        for _, member := range members.Members {
+            if bytes.Equal(member.Address.Bytes(), selectedAddress.Bytes()) {
+                selectedMember = member
+                break
+            }
+        }
+        if !selectedMember.Exists {
+            return fmt.Errorf("The oracle DAO member %s does not exist.", selectedAddress.Hex())
+        }
+
+    } else {
+
+        // Prompt for member selection
+        options := make([]string, len(members.Members))
+        for mi, member := range members.Members {
+            options[mi] = fmt.Sprintf("%s (URL: %s, node: %s)", member.ID, member.Url, member.Address)
+        }
+        selected, _ := cliutils.Select("Please select a member to challenge:", options)
+        selectedMember = members.Members[selected]
+
+    }
+
+    // Check if the challenge can be made
+    canChallenge, err := rp.CanMakeTNDAOChallenge(selectedMember.Address)
+    if err != nil {
+        return err
+    }
+    if !canChallenge.CanChallenge {
+        fmt.Println("Cannot challenge member:")
+        if canChallenge.MemberDoesNotExist {
+            fmt.Printf("The oracle DAO member %s does not exist.\n", selectedMember.Address.Hex())
+        }
+        if canChallenge.ChallengingSelf {
+            fmt.Println("The node cannot challenge itself.")
+        }
+        if canChallenge.AlreadyChallenged {
+            fmt.Println("The member already has an active challenge against it.")
+        }
+        if canChallenge.ChallengeCooldownActive {
+            fmt.Println("The node must wait for the challenge cooldown period to pass before making another challenge.")
+        }
+        if canChallenge.InsufficientBalance {
+            fmt.Printf("The node does not have enough ETH to pay the challenge cost of %.6f ETH.\n", math.RoundDown(eth.WeiToEth(canChallenge.ChallengeCost), 6))
+        }
+        return nil
+    }
+
+    // Assign max fees
+    err = gas.AssignMaxFeeAndLimit(canChallenge.GasInfo, rp, c.Bool("yes"))
+    if err != nil{
+        return err
+    }
+
+    // Prompt for confirmation
+    fmt.Printf("This will challenge %s (%s) to respond within the challenge window; if it does not, anyone can remove it from the oracle DAO.\n", selectedMember.ID, selectedMember.Address.Hex())
+    if canChallenge.ChallengeCost.Sign() > 0 {
+        fmt.Printf("As the node is not an oracle DAO member, it must pay a non-refundable challenge cost of %.6f ETH.\n", math.RoundDown(eth.WeiToEth(canChallenge.ChallengeCost), 6))
+    }
+    if !(c.Bool("yes") || cliutils.Confirm("Are you sure you want to make this challenge?")) {
+        fmt.Println("Cancelled.")
+        return nil
+    }
+
+    // Make challenge
+    response, err := rp.MakeTNDAOChallenge(selectedMember.Address)
+    if err != nil {
+        return err
+    }
+
+    fmt.Printf("Challenging %s...\n", selectedMember.Address.Hex())
+    cliutils.PrintTransactionHash(rp, response.TxHash)
+    if _, err = rp.WaitForTransaction(response.TxHash); err != nil {
+        return err
+    }
+
+    // Log & return
+    fmt.Printf("Successfully challenged node %s.\n", selectedMember.Address.Hex())
+    return nil
+
+}
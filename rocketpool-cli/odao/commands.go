@@ -1,9 +1,11 @@
 package odao
 
 import (
+	"github.com/rocket-pool/rocketpool-go/settings/trustednode"
 	"github.com/urfave/cli"
 
 	cliutils "github.com/rocket-pool/smartnode/shared/utils/cli"
+	rputils "github.com/rocket-pool/smartnode/shared/utils/rp"
 )
 
 // Register commands
@@ -47,40 +49,65 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
             },
 
             cli.Command{
-                Name:       "member-settings",
-                Aliases:    []string{"b"},
-                Usage:      "Get the oracle DAO settings related to oracle DAO members",
-                UsageText:  "rocketpool odao member-settings",
+                Name:      "get-settings",
+                Aliases:   []string{"g"},
+                Usage:     "Get the current values of the oracle DAO settings",
+                UsageText: "rocketpool odao get-settings",
                 Action: func(c *cli.Context) error {
 
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
                     // Run
-                    return getMemberSettings(c)
+                    return getSettings(c, "")
 
                 },
             },
 
             cli.Command{
-                Name:       "proposal-settings",
-                Aliases:    []string{"a"},
-                Usage:      "Get the oracle DAO settings related to oracle DAO proposals",
-                UsageText:  "rocketpool odao proposal-settings",
+                Name:      "member-settings",
+                Aliases:   []string{"b"},
+                Usage:     "Get the oracle DAO settings related to oracle DAO members",
+                UsageText: "rocketpool odao member-settings",
                 Action: func(c *cli.Context) error {
 
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
                     // Run
-                    return getProposalSettings(c)
+                    return getSettings(c, trustednode.MembersSettingsContractName)
 
                 },
             },
 
             cli.Command{
-                Name:       "minipool-settings",
-                Aliases:    []string{"i"},
-                Usage:      "Get the oracle DAO settings related to minipools",
-                UsageText:  "rocketpool odao minipool-settings",
+                Name:      "proposal-settings",
+                Aliases:   []string{"a"},
+                Usage:     "Get the oracle DAO settings related to oracle DAO proposals",
+                UsageText: "rocketpool odao proposal-settings",
                 Action: func(c *cli.Context) error {
 
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
                     // Run
-                    return getMinipoolSettings(c)
+                    return getSettings(c, trustednode.ProposalsSettingsContractName)
+
+                },
+            },
+
+            cli.Command{
+                Name:      "minipool-settings",
+                Aliases:   []string{"i"},
+                Usage:     "Get the oracle DAO settings related to minipools",
+                UsageText: "rocketpool odao minipool-settings",
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
+                    // Run
+                    return getSettings(c, trustednode.MinipoolSettingsContractName)
 
                 },
             },
@@ -173,150 +200,26 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
                     cli.Command{
                         Name:      "setting",
                         Aliases:   []string{"s"},
-                        Usage:     "Make an oracle DAO setting proposal",
-                        Subcommands: []cli.Command{
-
-                            cli.Command{
-                                Name:       "members-quorum",
-                                Aliases:    []string{"q"},
-                                Usage:      "Propose updating the members.quorum setting - takes a percent, from 0 to 100",
-                                UsageText:  "rocketpool odao propose setting members-quorum value",
-                                Action: func(c *cli.Context) error {
-
-                                    // Validate args
-                                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-                                    quorumPercent, err := cliutils.ValidatePercentage("quorum percentage", c.Args().Get(0))
-                                    if err != nil { return err }
-
-                                    // Run
-                                    return proposeSettingMembersQuorum(c, quorumPercent)
-
-                                },
-                            },
-                            cli.Command{
-                                Name:       "members-rplbond",
-                                Aliases:    []string{"b"},
-                                Usage:      "Propose updating the members.rplbond setting - takes an RPL amount (e.g. 5000)",
-                                UsageText:  "rocketpool odao propose setting members-rplbond value",
-                                Action: func(c *cli.Context) error {
-
-                                    // Validate args
-                                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-                                    bondAmountEth, err := cliutils.ValidateEthAmount("RPL bond amount", c.Args().Get(0))
-                                    if err != nil { return err }
-
-                                    // Run
-                                    return proposeSettingMembersRplBond(c, bondAmountEth)
-
-                                },
-                            },
-                            cli.Command{
-                                Name:       "members-minipool-unbonded-max",
-                                Aliases:    []string{"u"},
-                                Usage:      "Propose updating the members.minipool.unbonded.max setting - takes a number (e.g 100)",
-                                UsageText:  "rocketpool odao propose setting members-minipool-unbonded-max value",
-                                Action: func(c *cli.Context) error {
-
-                                    // Validate args
-                                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-                                    unbondedMinipoolMax, err := cliutils.ValidateUint("maximum unbonded minipool count", c.Args().Get(0))
-                                    if err != nil { return err }
-
-                                    // Run
-                                    return proposeSettingMinipoolUnbondedMax(c, unbondedMinipoolMax)
-
-                                },
-                            },
-                            cli.Command{
-                                Name:       "proposal-cooldown",
-                                Aliases:    []string{"c"},
-                                Usage:      "Propose updating the proposal.cooldown.time setting - format is e.g. 1h30m45s",
-                                UsageText:  "rocketpool odao propose setting proposal-cooldown value",
-                                Action: func(c *cli.Context) error {
-
-                                    // Validate args
-                                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-
-                                    // Run
-                                    return proposeSettingProposalCooldown(c, c.Args().Get(0))
-
-                                },
-                            },
-                            cli.Command{
-                                Name:       "proposal-vote-timespan",
-                                Aliases:    []string{"v"},
-                                Usage:      "Propose updating the proposal.vote.time setting - format is e.g. 1h30m45s",
-                                UsageText:  "rocketpool odao propose setting proposal-vote-timespan value",
-                                Action: func(c *cli.Context) error {
-
-                                    // Validate args
-                                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-
-                                    // Run
-                                    return proposeSettingProposalVoteTimespan(c, c.Args().Get(0))
-
-                                },
-                            },
-                            cli.Command{
-                                Name:       "proposal-vote-delay-timespan",
-                                Aliases:    []string{"d"},
-                                Usage:      "Propose updating the proposal.vote.delay.time setting - format is e.g. 1h30m45s",
-                                UsageText:  "rocketpool odao propose setting proposal-vote-delay-timespan value",
-                                Action: func(c *cli.Context) error {
-
-                                    // Validate args
-                                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-
-                                    // Run
-                                    return proposeSettingProposalVoteDelayTimespan(c, c.Args().Get(0))
-
-                                },
-                            },
-                            cli.Command{
-                                Name:       "proposal-execute-timespan",
-                                Aliases:    []string{"x"},
-                                Usage:      "Propose updating the proposal.execute.time setting - format is e.g. 1h30m45s",
-                                UsageText:  "rocketpool odao propose setting proposal-execute-timespan value",
-                                Action: func(c *cli.Context) error {
-
-                                    // Validate args
-                                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-
-                                    // Run
-                                    return proposeSettingProposalExecuteTimespan(c, c.Args().Get(0))
-
-                                },
-                            },
-                            cli.Command{
-                                Name:       "proposal-action-timespan",
-                                Aliases:    []string{"a"},
-                                Usage:      "Propose updating the proposal.action.time setting - format is e.g. 1h30m45s",
-                                UsageText:  "rocketpool odao propose setting proposal-action-timespan value",
-                                Action: func(c *cli.Context) error {
-
-                                    // Validate args
-                                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-
-                                    // Run
-                                    return proposeSettingProposalActionTimespan(c, c.Args().Get(0))
-
-                                },
+                        Usage:     "Propose updating an oracle DAO setting; run 'rocketpool odao get-settings' to list the settings",
+                        UsageText: "rocketpool odao propose setting [options] name value",
+                        Flags: []cli.Flag{
+                            cli.BoolFlag{
+                                Name:  "yes, y",
+                                Usage: "Automatically confirm the proposal",
                             },
-                            cli.Command{
-                                Name:       "scrub-period",
-                                Aliases:    []string{"s"},
-                                Usage:      "Propose updating the minipool.scrub.period setting - format is e.g. 1h30m45s",
-                                UsageText:  "rocketpool odao propose setting scrub-period value",
-                                Action: func(c *cli.Context) error {
-
-                                    // Validate args
-                                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
+                        },
+                        Action: func(c *cli.Context) error {
 
-                                    // Run
-                                    return proposeSettingScrubPeriod(c, c.Args().Get(0))
+                            // Validate args
+                            if err := cliutils.ValidateArgCount(c, 2); err != nil { return err }
+                            setting, err := rputils.GetTNDAOSetting(c.Args().Get(0))
+                            if err != nil { return err }
+                            value, err := setting.ParseValue(c.Args().Get(1))
+                            if err != nil { return err }
+                            if err := setting.ValidateValue(value); err != nil { return err }
 
-                                },
-                            },
+                            // Run
+                            return proposeSetting(c, setting, value)
 
                         },
                     },
@@ -526,6 +429,123 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
                 },
             },
 
+            cli.Command{
+                Name:      "challenges",
+                Aliases:   []string{"h"},
+                Usage:     "List the active challenges against oracle DAO members",
+                UsageText: "rocketpool odao challenges",
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
+                    // Run
+                    return getChallenges(c)
+
+                },
+            },
+
+            cli.Command{
+                Name:      "submissions",
+                Aliases:   []string{"u"},
+                Usage:     "Audit the members' balances or prices submissions, flagging late, diverged and missing submissions",
+                UsageText: "rocketpool odao submissions [options]",
+                Flags: []cli.Flag{
+                    cli.StringFlag{
+                        Name:  "type, t",
+                        Usage: "The submissions to audit ('balances' or 'prices')",
+                        Value: rputils.TNDAOSubmissionTypeBalances,
+                    },
+                    cli.Uint64Flag{
+                        Name:  "from, f",
+                        Usage: "The block to start auditing from (defaults to the last 10 submission rounds)",
+                    },
+                    cli.Uint64Flag{
+                        Name:  "to",
+                        Usage: "The block to stop auditing at (defaults to the latest block)",
+                    },
+                    cli.Uint64Flag{
+                        Name:  "late-blocks, l",
+                        Usage: "The number of blocks after a round's block that a submission is considered late",
+                        Value: rputils.TNDAOSubmissionsDefaultLateBlocks,
+                    },
+                    cli.BoolFlag{
+                        Name:  "verbose, v",
+                        Usage: "Show each member's result for every round",
+                    },
+                },
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
+                    // Validate flags
+                    if _, err := cliutils.ValidateSubmissionType("submission type", c.String("type")); err != nil { return err }
+
+                    // Run
+                    return getSubmissions(c)
+
+                },
+            },
+
+            cli.Command{
+                Name:      "scrub-reports",
+                Aliases:   []string{"r"},
+                Usage:     "View the evidence reports for the minipools the watchtower has voted to scrub",
+                UsageText: "rocketpool odao scrub-reports [options]",
+                Flags: []cli.Flag{
+                    cli.StringFlag{
+                        Name:  "minipool, m",
+                        Usage: "The address of a minipool to show the full evidence report for",
+                    },
+                },
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
+                    // Validate flags
+                    if c.String("minipool") != "" {
+                        if _, err := cliutils.ValidateAddress("minipool address", c.String("minipool")); err != nil { return err }
+                    }
+
+                    // Run
+                    return getScrubReports(c)
+
+                },
+            },
+
+            cli.Command{
+                Name:      "challenge",
+                Aliases:   []string{"c"},
+                Usage:     "Challenge a member to prove that its node is online",
+                UsageText: "rocketpool odao challenge [options]",
+                Flags: []cli.Flag{
+                    cli.StringFlag{
+                        Name:  "member, m",
+                        Usage: "The address of the member to challenge",
+                    },
+                    cli.BoolFlag{
+                        Name:  "yes, y",
+                        Usage: "Automatically confirm the challenge",
+                    },
+                },
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
+                    // Validate flags
+                    if c.String("member") != "" {
+                        if _, err := cliutils.ValidateAddress("member address", c.String("member")); err != nil { return err }
+                    }
+
+                    // Run
+                    return makeChallenge(c)
+
+                },
+            },
+
         },
     })
 }
@@ -2,80 +2,49 @@ package odao
 
 import (
 	"fmt"
-	"time"
+	"os"
+	"text/tabwriter"
 
 	"github.com/urfave/cli"
 
-	"github.com/rocket-pool/rocketpool-go/utils/eth"
 	"github.com/rocket-pool/smartnode/shared/services/rocketpool"
+	rputils "github.com/rocket-pool/smartnode/shared/utils/rp"
 )
 
 
-func getMemberSettings(c *cli.Context) error {
+// Print the current oracle DAO setting values, optionally limited to the settings stored in a single contract
+func getSettings(c *cli.Context, contractName string) error {
 
     // Get RP client
     rp, err := rocketpool.NewClientFromCtx(c)
     if err != nil { return err }
     defer rp.Close()
 
-    // Get oracle DAO member settings
-    response, err := rp.GetTNDAOMemberSettings()
+    // Get oracle DAO settings
+    response, err := rp.GetTNDAOSettings()
     if err != nil {
         return err
     }
 
-    // Log & return
-    fmt.Printf("ODAO Voting Quorum Threshold: %f%%\n", response.Quorum * 100)
-    fmt.Printf("Required Member RPL Bond: %f RPL\n", eth.WeiToEth(response.RPLBond))
-    fmt.Printf("Max Number of Unbonded Minipools: %d\n", response.MinipoolUnbondedMax)
-    fmt.Printf("Consecutive Challenge Cooldown: %d Blocks\n", response.ChallengeCooldown)
-    fmt.Printf("Challenge Meeting Window: %d Blocks\n", response.ChallengeWindow)
-    fmt.Printf("Cost for Non-members to Challenge Members: %f ETH\n", eth.WeiToEth(response.ChallengeCost))
-    return nil
-
-}
-
-
-func getProposalSettings(c *cli.Context) error {
-
-    // Get RP client
-    rp, err := rocketpool.NewClientFromCtx(c)
-    if err != nil { return err }
-    defer rp.Close()
-
-    // Get oracle DAO proposal settings
-    response, err := rp.GetTNDAOProposalSettings()
-    if err != nil {
-        return err
+    // Print settings
+    writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+    fmt.Fprintln(writer, "Setting\tValue\tDescription")
+    for _, value := range response.Settings {
+        setting, err := rputils.GetTNDAOSetting(value.Name)
+        if err != nil {
+            fmt.Fprintf(writer, "%s\t%s\t\n", value.Name, value.Value.String())
+            continue
+        }
+        if contractName != "" && setting.ContractName != contractName {
+            continue
+        }
+        fmt.Fprintf(writer, "%s\t%s\t%s\n", setting.Name, setting.FormatValue(value.Value), setting.Description)
     }
-
-    // Log & return
-    fmt.Printf("Cooldown Between Proposals: %s\n", time.Duration(response.Cooldown * 1000000000))
-    fmt.Printf("Proposal Voting Window: %s\n", time.Duration(response.VoteTime * 1000000000))
-    fmt.Printf("Delay Before Voting on a Proposal is Allowed: %s\n", time.Duration(response.VoteDelayTime * 1000000000))
-    fmt.Printf("Window to Execute an Accepted Proposal: %s\n", time.Duration(response.ExecuteTime * 1000000000))
-    fmt.Printf("Window to Act on an Executed Proposal: %s\n", time.Duration(response.ActionTime * 1000000000))
-    return nil
-
-}
-
-
-func getMinipoolSettings(c *cli.Context) error {
-
-    // Get RP client
-    rp, err := rocketpool.NewClientFromCtx(c)
-    if err != nil { return err }
-    defer rp.Close()
-
-    // Get oracle DAO proposal settings
-    response, err := rp.GetTNDAOMinipoolSettings()
-    if err != nil {
+    if err := writer.Flush(); err != nil {
         return err
     }
 
-    // Log & return
-    fmt.Printf("Scrub Period: %s\n", time.Duration(response.ScrubPeriod * 1000000000))
+    // Return
     return nil
 
 }
-
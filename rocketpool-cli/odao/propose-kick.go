@@ -103,6 +103,11 @@ func proposeKick(c *cli.Context) error {
         return nil
     }
 
+    // Print the simulated effect of the proposal
+    if err := printProposalSimulation(canPropose.Simulation); err != nil {
+        return err
+    }
+
     // Assign max fees
     err = gas.AssignMaxFeeAndLimit(canPropose.GasInfo, rp, c.Bool("yes"))
     if err != nil{
@@ -0,0 +1,140 @@
+package odao
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services/rocketpool"
+	"github.com/rocket-pool/smartnode/shared/services/scrubs"
+	"github.com/rocket-pool/smartnode/shared/types/api"
+)
+
+
+func getScrubReports(c *cli.Context) error {
+
+    // Get RP client
+    rp, err := rocketpool.NewClientFromCtx(c)
+    if err != nil { return err }
+    defer rp.Close()
+
+    // Get scrub reports
+    response, err := rp.TNDAOScrubReports()
+    if err != nil {
+        return err
+    }
+
+    // Print a single minipool's report
+    if c.String("minipool") != "" {
+        minipoolAddress := common.HexToAddress(c.String("minipool"))
+        for _, report := range response.Reports {
+            if report.Minipool == minipoolAddress {
+                printScrubReport(report)
+                return nil
+            }
+        }
+        fmt.Printf("There is no scrub report for minipool %s.\n", minipoolAddress.Hex())
+        return nil
+    }
+
+    // Print & return
+    if len(response.Reports) == 0 {
+        fmt.Println("The watchtower has not voted to scrub any minipools.")
+        return nil
+    }
+    writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+    fmt.Fprintln(writer, "Detected\tMinipool\tNode\tFailed check\tVote\tStatus")
+    for _, report := range response.Reports {
+        fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\n", report.DetectedTime.Format(time.RFC822), report.Minipool.Hex(), report.NodeAddress.Hex(), report.Step, getScrubVoteDescription(report), report.MinipoolStatus.String())
+    }
+    if err := writer.Flush(); err != nil {
+        return err
+    }
+    fmt.Println("")
+    fmt.Println("Use `rocketpool odao scrub-reports --minipool <address>` to view the full evidence for a minipool.")
+    return nil
+
+}
+
+
+// Print a minipool's full scrub evidence report
+func printScrubReport(report api.TNDAOScrubReport) {
+
+    // Summary
+    fmt.Printf("Minipool:                        %s (currently %s)\n", report.Minipool.Hex(), report.MinipoolStatus.String())
+    fmt.Printf("Node:                            %s\n", report.NodeAddress.Hex())
+    fmt.Printf("Validator pubkey:                %s\n", report.Pubkey.Hex())
+    fmt.Printf("Detected:                        %s\n", report.DetectedTime.Format(time.RFC822))
+    fmt.Printf("Failed check:                    %s\n", report.Step)
+    fmt.Printf("Reason:                          %s\n", report.Reason)
+    fmt.Printf("Expected withdrawal credentials: %s\n", report.ExpectedWithdrawalCredentials.Hex())
+    if report.ObservedWithdrawalCredentials != nil {
+        fmt.Printf("Observed withdrawal credentials: %s\n", report.ObservedWithdrawalCredentials.Hex())
+    }
+    fmt.Printf("Vote:                            %s\n", getScrubVoteDescription(report))
+
+    // Prestake event
+    if report.Prestake != nil {
+        fmt.Println("")
+        fmt.Println("Prestake event:")
+        fmt.Printf("    Time:                        %s\n", report.Prestake.Time.Format(time.RFC822))
+        fmt.Printf("    Amount:                      %d gwei\n", report.Prestake.Amount)
+        fmt.Printf("    Withdrawal credentials:      %s\n", report.Prestake.WithdrawalCredentials.Hex())
+        fmt.Printf("    Signature:                   %s\n", report.Prestake.Signature.Hex())
+        fmt.Printf("    Signature error:             %s\n", report.SignatureError)
+    }
+
+    // Deposit event
+    if report.Deposit != nil {
+        fmt.Println("")
+        fmt.Println("Deposit contract event:")
+        printScrubDeposit(*report.Deposit)
+    }
+
+    // Invalid deposits
+    if len(report.InvalidDeposits) > 0 {
+        fmt.Println("")
+        fmt.Printf("Ignored deposits with invalid signatures (%d):\n", len(report.InvalidDeposits))
+        for _, deposit := range report.InvalidDeposits {
+            printScrubDeposit(deposit)
+            fmt.Printf("    Error:                       %s\n", deposit.Error)
+        }
+    }
+
+    // Safety scrub
+    if report.PrelaunchTime != nil {
+        fmt.Println("")
+        fmt.Println("Safety scrub:")
+        fmt.Printf("    In prelaunch since:          %s\n", report.PrelaunchTime.Format(time.RFC822))
+        if report.LatestBlockTime != nil {
+            fmt.Printf("    Latest block time:           %s\n", report.LatestBlockTime.Format(time.RFC822))
+        }
+        fmt.Printf("    Safety scrub period:         %s\n", report.SafetyPeriod)
+    }
+
+}
+
+
+// Print a deposit contract event from a scrub report
+func printScrubDeposit(deposit scrubs.DepositEvidence) {
+    fmt.Printf("    Transaction:                 %s\n", deposit.TxHash.Hex())
+    fmt.Printf("    Block:                       %d (transaction index %d, deposit index %d)\n", deposit.BlockNumber, deposit.TxIndex, deposit.DepositIndex)
+    fmt.Printf("    Amount:                      %d gwei\n", deposit.Amount)
+    fmt.Printf("    Withdrawal credentials:      %s\n", deposit.WithdrawalCredentials.Hex())
+    fmt.Printf("    Signature:                   %s\n", deposit.Signature.Hex())
+}
+
+
+// Get a description of the watchtower's scrub vote for a report
+func getScrubVoteDescription(report api.TNDAOScrubReport) string {
+    switch {
+        case report.DryRun: return "none (dry run)"
+        case report.VoteTxHash != nil: return fmt.Sprintf("transaction %s", report.VoteTxHash.Hex())
+        case report.VoteError != "": return fmt.Sprintf("failed (%s)", report.VoteError)
+    }
+    return "pending"
+}
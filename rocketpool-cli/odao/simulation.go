@@ -0,0 +1,38 @@
+package odao
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/rocket-pool/smartnode/shared/types/api"
+)
+
+
+// Print the simulated effect of executing a proposal
+func printProposalSimulation(simulation api.TNDAOProposalSimulation) error {
+
+    // Print changes
+    fmt.Println("If executed, this proposal will make the following changes:")
+    writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+    fmt.Fprintln(writer, "Item\tBefore\tAfter")
+    for _, change := range simulation.Changes {
+        fmt.Fprintf(writer, "%s\t%s\t%s\n", change.Item, change.Before, change.After)
+    }
+    if err := writer.Flush(); err != nil {
+        return err
+    }
+    fmt.Println("")
+
+    // Print simulation status
+    if simulation.Executable && !simulation.Simulated {
+        fmt.Printf("NOTE: the execution client could not trace the proposal's execution (debug_traceCall), so these changes are decoded from the proposal payload rather than read from the simulated chain state.\n\n")
+    }
+    if !simulation.Executable {
+        fmt.Printf("WARNING: executing this proposal against the current chain state would fail: %s\n\n", simulation.RevertReason)
+    }
+
+    // Return
+    return nil
+
+}
@@ -0,0 +1,100 @@
+package odao
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services/rocketpool"
+)
+
+
+func getSubmissions(c *cli.Context) error {
+
+    // Get RP client
+    rp, err := rocketpool.NewClientFromCtx(c)
+    if err != nil { return err }
+    defer rp.Close()
+
+    // Audit submissions
+    response, err := rp.TNDAOSubmissions(strings.ToLower(c.String("type")), c.Uint64("from"), c.Uint64("to"), c.Uint64("late-blocks"))
+    if err != nil {
+        return err
+    }
+    audit := response.Audit
+
+    // Print & return
+    fmt.Printf("Oracle DAO %s submissions from block %d to block %d:\n", audit.Type, audit.FromBlock, audit.ToBlock)
+    fmt.Println("")
+    if len(audit.Rounds) == 0 {
+        fmt.Println("There were no submissions in this block range.")
+        return nil
+    }
+
+    // Print rounds
+    writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+    fmt.Fprintf(writer, "Round block\tConsensus\t%s\n", strings.Join(audit.ValueNames, "\t"))
+    for _, round := range audit.Rounds {
+        consensus := fmt.Sprintf("block %d", round.ConsensusBlock)
+        if !round.Consensus {
+            consensus = "not reached"
+        }
+        values := make([]string, len(round.Values))
+        for vi, value := range round.Values {
+            values[vi] = value.String()
+        }
+        fmt.Fprintf(writer, "%d\t%s\t%s\n", round.Block, consensus, strings.Join(values, "\t"))
+    }
+    if err := writer.Flush(); err != nil {
+        return err
+    }
+    fmt.Println("")
+
+    // Print member summary
+    writer = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+    if c.Bool("verbose") {
+        fmt.Fprint(writer, "Member\tAddress\tOK\tLate\tDiverged\tMissing\tLast submitted")
+        for _, round := range audit.Rounds {
+            fmt.Fprintf(writer, "\t%d", round.Block)
+        }
+        fmt.Fprintln(writer, "")
+    } else {
+        fmt.Fprintln(writer, "Member\tAddress\tOK\tLate\tDiverged\tMissing\tLast submitted")
+    }
+    flagged := 0
+    for _, member := range audit.Members {
+        memberId := member.Id
+        if memberId == "" {
+            memberId = "(former member)"
+        }
+        lastSubmitted := "never"
+        if member.LatestSubmittedBlock > 0 {
+            lastSubmitted = fmt.Sprintf("%d", member.LatestSubmittedBlock)
+        }
+        fmt.Fprintf(writer, "%s\t%s\t%d\t%d\t%d\t%d\t%s", memberId, member.Address.Hex(), member.OnTime, member.Late, member.Diverged, member.Missing, lastSubmitted)
+        if c.Bool("verbose") {
+            fmt.Fprintf(writer, "\t%s", strings.Join(member.Results, "\t"))
+        }
+        fmt.Fprintln(writer, "")
+        if member.Late > 0 || member.Diverged > 0 || member.Missing > 0 {
+            flagged++
+        }
+    }
+    if err := writer.Flush(); err != nil {
+        return err
+    }
+    fmt.Println("")
+
+    // Print flagged members
+    if flagged > 0 {
+        fmt.Printf("%d members were late, diverged from consensus or missed submissions (a submission is late if mined more than %d blocks after its round block).\n", flagged, c.Uint64("late-blocks"))
+    } else {
+        fmt.Println("All members submitted on time and in agreement with consensus.")
+    }
+    return nil
+
+}
+
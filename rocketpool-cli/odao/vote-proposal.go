@@ -119,6 +119,15 @@ func voteOnProposal(c *cli.Context) error {
         return nil
     }
 
+    // Simulate proposal execution and print its effect
+    simulation, err := rp.SimulateTNDAOProposal(selectedProposal.ID)
+    if err != nil {
+        return err
+    }
+    if err := printProposalSimulation(simulation.Simulation); err != nil {
+        return err
+    }
+
     // Assign max fees
     err = gas.AssignMaxFeeAndLimit(canVote.GasInfo, rp, c.Bool("yes"))
     if err != nil{
@@ -13,6 +13,7 @@ import (
 	"github.com/rocket-pool/smartnode/rocketpool-cli/minipool"
 	"github.com/rocket-pool/smartnode/rocketpool-cli/network"
 	"github.com/rocket-pool/smartnode/rocketpool-cli/node"
+	"github.com/rocket-pool/smartnode/rocketpool-cli/nodes"
 	"github.com/rocket-pool/smartnode/rocketpool-cli/odao"
 	"github.com/rocket-pool/smartnode/rocketpool-cli/queue"
 	"github.com/rocket-pool/smartnode/rocketpool-cli/service"
@@ -93,12 +94,25 @@ ______           _        _    ______           _
         },
         cli.StringFlag{
             Name:  "passphrase, p",
-            Usage: "DEPRECATED - Smart node SSH key passphrase `file`",
+            Usage: "Smart node SSH key passphrase `file`, also used for the node selected with --node",
         },
         cli.StringFlag{
             Name:  "known-hosts, n",
             Usage: "DEPRECATED - Smart node SSH known_hosts `file` (default: current user's ~/.ssh/known_hosts)",
         },
+        cli.StringFlag{
+            Name:  "node",
+            Usage: "Interact with the node with this `name` in the nodes file",
+        },
+        cli.BoolFlag{
+            Name:  "all-nodes",
+            Usage: "Run the command on every node in the nodes file in parallel and print the results as a table (supported by 'node status', 'node sync' and 'service version')",
+        },
+        cli.StringFlag{
+            Name:  "nodes-file",
+            Usage: "Node profiles `file` used by --node and --all-nodes",
+            Value: rocketpool.DefaultNodesFile,
+        },
         cli.StringFlag{
             Name:  "gasPrice, g",
             Usage: "OBSOLETE - No longer used, please use --maxFee and --maxPrioFee instead",
@@ -175,6 +189,7 @@ ______           _        _    ______           _
     minipool.RegisterCommands(app, "minipool", []string{"m"})
      network.RegisterCommands(app, "network",  []string{"e"})
         node.RegisterCommands(app, "node",     []string{"n"})
+       nodes.RegisterCommands(app, "nodes",    []string{"x"})
         odao.RegisterCommands(app, "odao",     []string{"o"})
        queue.RegisterCommands(app, "queue",    []string{"q"})
      service.RegisterCommands(app, "service",  []string{"s"})
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services/backup"
+	"github.com/rocket-pool/smartnode/shared/services/passwords"
+	"github.com/rocket-pool/smartnode/shared/services/rocketpool"
+	cliutils "github.com/rocket-pool/smartnode/shared/utils/cli"
+)
+
+// Back up the node's state to an encrypted archive
+func backupService(c *cli.Context) error {
+
+    // Get RP client
+    rp, err := rocketpool.NewClientFromCtx(c)
+    if err != nil { return err }
+    defer rp.Close()
+
+    // Get output path
+    outputPath := c.String("output")
+    if outputPath == "" {
+        outputPath = fmt.Sprintf("rocketpool-backup-%s%s", time.Now().Format("20060102-150405"), backup.FileExtension)
+    }
+    if _, err := os.Stat(outputPath); err == nil {
+        if !(c.Bool("yes") || cliutils.Confirm(fmt.Sprintf("%s already exists. Would you like to overwrite it?", outputPath))) {
+            fmt.Println("Cancelled.")
+            return nil
+        }
+    }
+
+    // Prompt for backup passphrase
+    passphrase := promptBackupPassphrase()
+
+    // Create backup
+    fmt.Println("Backing up the node...")
+    nodeBackup, err := rp.BackupService(getComposeFiles(c), !c.Bool("exclude-password"))
+    if err != nil { return err }
+    for _, warning := range nodeBackup.Warnings {
+        fmt.Printf("%sWARNING: %s%s\n", colorYellow, warning, colorReset)
+    }
+
+    // Encrypt & write backup
+    backupBytes, err := backup.Encrypt(nodeBackup.Archive, passphrase, nodeBackup.Manifest.CreatedAt)
+    if err != nil { return err }
+    if err := ioutil.WriteFile(outputPath, backupBytes, 0600); err != nil {
+        return fmt.Errorf("Could not write backup to %s: %w", outputPath, err)
+    }
+
+    // Print summary & return
+    manifest := nodeBackup.Manifest
+    fmt.Println("")
+    fmt.Printf("The node was backed up to %s.\n", outputPath)
+    fmt.Printf("Files: %d, validator keys: %d, node password: %t, slashing protection interchange data: %t\n", len(manifest.Files), len(manifest.ValidatorPubkeys), manifest.IncludesPassword, manifest.SlashingProtection)
+    fmt.Println("Please store the backup and its passphrase securely; anyone with both has full control of your node wallet and validators.")
+    return nil
+
+}
+
+
+// Restore the node's state from an encrypted archive
+func restoreService(c *cli.Context, backupPath string) error {
+
+    // Get RP client
+    rp, err := rocketpool.NewClientFromCtx(c)
+    if err != nil { return err }
+    defer rp.Close()
+
+    // Read & decrypt backup
+    backupBytes, err := ioutil.ReadFile(backupPath)
+    if err != nil {
+        return fmt.Errorf("Could not read backup %s: %w", backupPath, err)
+    }
+    passphrase := cliutils.PromptPassword("Please enter the backup passphrase:", "^.*$", "")
+    archive, err := backup.Decrypt(backupBytes, passphrase)
+    if err != nil { return err }
+    restoreKeys := !c.Bool("no-keys")
+    manifest, data, composeFiles, err := backup.Unpack(archive, !restoreKeys)
+    if err != nil { return err }
+
+    // Print backup details
+    serviceVersion := manifest.ServiceVersion
+    if serviceVersion == "" {
+        serviceVersion = "(unknown)"
+    }
+    fmt.Printf("Backup created %s with service version %s for chain ID %s (%s client).\n", manifest.CreatedAt.Format(time.RFC1123), serviceVersion, manifest.ChainID, manifest.Eth2Client)
+    fmt.Printf("Files: %d, validator keys: %d, node password: %t, slashing protection interchange data: %t\n", len(manifest.Files), len(manifest.ValidatorPubkeys), manifest.IncludesPassword, manifest.SlashingProtection)
+    fmt.Println("")
+
+    // Check compatibility
+    warnings, err := rp.CheckBackupCompatibility(manifest)
+    if err != nil { return err }
+    for _, warning := range warnings {
+        fmt.Printf("%sWARNING: %s%s\n", colorYellow, warning, colorReset)
+    }
+
+    // Make sure the validator keys aren't in use before restoring them
+    if restoreKeys {
+        if err := checkValidatorKeysNotLive(rp, manifest); err != nil { return err }
+    } else {
+        fmt.Println("Validator keys and slashing protection data will not be restored.")
+    }
+
+    // Prompt for the node password if it wasn't backed up
+    var password string
+    if !manifest.IncludesPassword {
+        fmt.Println("The backup does not include the node password. Please enter the password the backed up wallet was secured with.")
+        password = cliutils.PromptPassword(
+            "Node password:",
+            fmt.Sprintf("^.{%d,}$", passwords.MinPasswordLength),
+            fmt.Sprintf("The password must be at least %d characters long. Please try again:", passwords.MinPasswordLength),
+        )
+    }
+
+    // Prompt for confirmation
+    if !(c.Bool("yes") || cliutils.Confirm("The node's existing wallet, settings and validator keys will be overwritten by the backup. Are you sure you want to continue?")) {
+        fmt.Println("Cancelled.")
+        return nil
+    }
+
+    // Restore backup
+    if err := rp.RestoreService(data, composeFiles, password); err != nil { return err }
+
+    // Migrate restored settings
+    backupPath, steps, err := rp.MigrateUserConfig()
+    if err != nil { return err }
+    if len(steps) > 0 {
+        fmt.Printf("The restored settings were migrated to the current version (backed up to %s):\n", backupPath)
+        for _, step := range steps {
+            fmt.Printf("- %s\n", step)
+        }
+    }
+
+    // Print success message & return
+    fmt.Println("")
+    fmt.Println("The node was successfully restored.")
+    if restoreKeys && manifest.SlashingProtection {
+        fmt.Printf("The validator client's slashing protection data was exported to %s in EIP-3076 interchange format; if you switch Eth 2.0 clients, please import it into the new client before starting it.\n", backup.SlashingProtectionPath)
+    }
+    if len(composeFiles) > 0 {
+        fmt.Println("Custom compose files were restored; please continue to pass them to the CLI with --compose-file.")
+    }
+    fmt.Println("Please run 'rocketpool service start' to start the service with the restored state.")
+    return nil
+
+}
+
+
+// Check that the validators in a backup are not being run locally or elsewhere
+func checkValidatorKeysNotLive(rp *rocketpool.Client, manifest *backup.Manifest) error {
+
+    // Check the local validator client
+    running, name, err := rp.IsValidatorRunning()
+    if err != nil { return err }
+    if running {
+        return fmt.Errorf("The validator client (%s) is running. Please stop it before restoring validator keys, or restore with --no-keys.", name)
+    }
+    if len(manifest.ValidatorPubkeys) == 0 {
+        return nil
+    }
+
+    // Check the beacon chain for recent validator activity
+    fmt.Printf("Checking the beacon chain for recent activity by the backup's %d validators...\n", len(manifest.ValidatorPubkeys))
+    liveness, err := rp.NodeValidatorLiveness(manifest.ValidatorPubkeys)
+    if err != nil {
+        return fmt.Errorf("%w\nValidator keys can only be restored once the Eth 2.0 client is synced and can confirm they are not running elsewhere. Please wait for it to sync, or restore with --no-keys.", err)
+    }
+    if len(liveness.LiveValidators) > 0 {
+        pubkeys := make([]string, len(liveness.LiveValidators))
+        for vi, pubkey := range liveness.LiveValidators {
+            pubkeys[vi] = pubkey.Hex()
+        }
+        return fmt.Errorf("The following validators were active between epochs %d and %d, so another instance may still be running them:\n%s\nRestoring their keys while another instance is live would get them slashed. Please make sure the other instance is shut down and wait for at least %d minutes (%d epochs), or restore with --no-keys.", liveness.FromEpoch, liveness.ToEpoch, strings.Join(pubkeys, "\n"), (liveness.WaitSeconds + 59) / 60, liveness.WindowEpochs + 1)
+    }
+    fmt.Printf("None of the validators were active between epochs %d and %d.\n", liveness.FromEpoch, liveness.ToEpoch)
+    return nil
+
+}
+
+
+// Prompt for a passphrase to encrypt a backup with
+func promptBackupPassphrase() string {
+    for {
+        passphrase := cliutils.PromptPassword(
+            "Please enter a passphrase to encrypt the backup with:",
+            fmt.Sprintf("^.{%d,}$", passwords.MinPasswordLength),
+            fmt.Sprintf("The passphrase must be at least %d characters long. Please try again:", passwords.MinPasswordLength),
+        )
+        confirmation := cliutils.PromptPassword("Please confirm the passphrase:", "^.*$", "")
+        if passphrase == confirmation {
+            return passphrase
+        }
+        fmt.Println("The passphrases did not match. Please try again.")
+    }
+}
@@ -55,6 +55,23 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
                         Usage: "The smart node package version to install",
                         Value: fmt.Sprintf("v%s", shared.RocketPoolVersion),
                     },
+                    cli.StringFlag{
+                        Name:  "bundle, b",
+                        Usage: "Install from a local release bundle instead of downloading it; the bundle's .sha256 and .sig files must be alongside it",
+                    },
+                    cli.BoolFlag{
+                        Name:  "legacy-installer",
+                        Usage: "Download and run the release's install script without verifying it, instead of installing a verified release bundle",
+                    },
+                    cli.BoolFlag{
+                        Name:  "native",
+                        Usage: "Run the clients and daemons natively as systemd services instead of in docker",
+                    },
+                    cli.StringFlag{
+                        Name:  "daemon-binary",
+                        Usage: "The path of the Rocket Pool daemon binary to run in native mode",
+                        Value: "/usr/local/bin/rocketpoold",
+                    },
                 },
                 Action: func(c *cli.Context) error {
 
@@ -67,16 +84,116 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
                 },
             },
 
+            cli.Command{
+                Name:      "rollback",
+                Usage:     "Roll the Rocket Pool service back to the previously installed version",
+                UsageText: "rocketpool service rollback [options]",
+                Flags: []cli.Flag{
+                    cli.BoolFlag{
+                        Name:  "yes, y",
+                        Usage: "Automatically confirm service rollback",
+                    },
+                    cli.BoolFlag{
+                        Name:  "verbose, r",
+                        Usage: "Print installation script command output",
+                    },
+                },
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
+                    // Run command
+                    return rollbackService(c)
+
+                },
+            },
+
+            cli.Command{
+                Name:      "backup",
+                Usage:     "Back up the node's wallet, settings, validator keys and slashing protection data to an encrypted archive",
+                UsageText: "rocketpool service backup [options]",
+                Flags: []cli.Flag{
+                    cli.StringFlag{
+                        Name:  "output, o",
+                        Usage: "The path to write the backup to (defaults to a timestamped file in the current folder)",
+                    },
+                    cli.BoolFlag{
+                        Name:  "exclude-password, x",
+                        Usage: "Leave the node password out of the backup; it must be entered when restoring",
+                    },
+                    cli.BoolFlag{
+                        Name:  "yes, y",
+                        Usage: "Automatically confirm overwriting an existing backup file",
+                    },
+                },
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
+                    // Run command
+                    return backupService(c)
+
+                },
+            },
+
+            cli.Command{
+                Name:      "restore",
+                Usage:     "Restore the node's wallet, settings, validator keys and slashing protection data from an encrypted archive",
+                UsageText: "rocketpool service restore [options] backup-path",
+                Flags: []cli.Flag{
+                    cli.BoolFlag{
+                        Name:  "no-keys, k",
+                        Usage: "Don't restore validator keys and slashing protection data",
+                    },
+                    cli.BoolFlag{
+                        Name:  "yes, y",
+                        Usage: "Automatically confirm overwriting the node's existing state",
+                    },
+                },
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
+                    backupPath := c.Args().Get(0)
+
+                    // Run command
+                    return restoreService(c, backupPath)
+
+                },
+            },
+
             cli.Command{
                 Name:      "config",
                 Aliases:   []string{"c"},
                 Usage:     "Configure the Rocket Pool service",
-                UsageText: "rocketpool service config",
+                UsageText: "rocketpool service config [options]",
                 Flags: []cli.Flag{
                     cli.BoolFlag{
                         Name:  "advanced, a",
                         Usage: "Show all settings during configuration for advanced users",
                     },
+                    cli.StringFlag{
+                        Name:  "from-file",
+                        Usage: "Configure the service without prompting, using the settings in a file in the format of settings.yml",
+                    },
+                    cli.StringSliceFlag{
+                        Name:  "set, s",
+                        Usage: "Configure the service without prompting, setting a value in the format key=value (e.g. eth1.client=geth or eth2.params.CUSTOM_GRAFFITI=hello); this flag may be defined multiple times",
+                    },
+                    cli.BoolFlag{
+                        Name:  "validate",
+                        Usage: "Check the current settings for unknown or obsolete keys without changing them",
+                    },
+                    cli.BoolFlag{
+                        Name:  "diff",
+                        Usage: "Show the settings which have changed since the service was started and the containers they affect",
+                    },
+                    cli.BoolFlag{
+                        Name:  "restart",
+                        Usage: "Restart the containers affected by changed settings without confirmation",
+                    },
                 },
                 Action: func(c *cli.Context) error {
 
@@ -84,6 +201,15 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
                     if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
 
                     // Run command
+                    if c.Bool("validate") {
+                        return validateServiceConfig(c)
+                    }
+                    if c.Bool("diff") {
+                        return showConfigDiff(c)
+                    }
+                    if c.String("from-file") != "" || len(c.StringSlice("set")) > 0 {
+                        return configureServiceFromSettings(c)
+                    }
                     return configureService(c)
 
                 },
@@ -228,6 +354,29 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
                 },
             },
 
+            cli.Command{
+                Name:      "doctor",
+                Usage:     "Run health checks against the Rocket Pool service and node",
+                UsageText: "rocketpool service doctor [options]",
+                Flags: []cli.Flag{
+                    cli.StringFlag{
+                        Name:  "format, f",
+                        Usage: "The output format ('text' or 'json')",
+                        Value: "text",
+                    },
+                },
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+                    if _, err := cliutils.ValidateOutputFormat("format", c.String("format")); err != nil { return err }
+
+                    // Run command
+                    return serviceDoctor(c)
+
+                },
+            },
+
             cli.Command{
                 Name:      "version",
                 Aliases:   []string{"v"},
@@ -263,6 +412,14 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
                         Usage: "The update tracker package version to install",
                         Value: fmt.Sprintf("v%s", shared.RocketPoolVersion),
                     },
+                    cli.StringFlag{
+                        Name:  "bundle, b",
+                        Usage: "Install from a local release bundle instead of downloading it; the bundle's .sha256 and .sig files must be alongside it",
+                    },
+                    cli.BoolFlag{
+                        Name:  "legacy-installer",
+                        Usage: "Download and run the release's install script without verifying it, instead of installing a verified release bundle",
+                    },
                 },
                 Action: func(c *cli.Context) error {
 
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/rocketpool"
+	cliutils "github.com/rocket-pool/smartnode/shared/utils/cli"
+)
+
+
+// Show the differences between the config the service is running with and the current settings
+func showConfigDiff(c *cli.Context) error {
+
+    // Get RP client
+    rp, err := rocketpool.NewClientFromCtx(c)
+    if err != nil { return err }
+    defer rp.Close()
+
+    // Load configs
+    appliedConfig, applied, err := rp.LoadAppliedConfig()
+    if err != nil {
+        return err
+    }
+    if !applied {
+        fmt.Println("There is no record of the configuration the service was last started with. Please run 'rocketpool service start' to apply your settings.")
+        return nil
+    }
+    currentConfig, err := rp.LoadMergedConfig()
+    if err != nil {
+        return err
+    }
+
+    // Print changes & restart
+    return applyConfigChanges(c, rp, appliedConfig, currentConfig, true)
+
+}
+
+
+// Print the changes between the config the service is running with (or the previous config if it hasn't been started) and a new config,
+// then restart the affected containers if requested
+func applyConfigChanges(c *cli.Context, rp *rocketpool.Client, previousConfig, newConfig config.RocketPoolConfig, prompt bool) error {
+
+    // Compare against the running config if available
+    appliedConfig, applied, err := rp.LoadAppliedConfig()
+    if err != nil {
+        return err
+    }
+    if applied {
+        previousConfig = appliedConfig
+    }
+
+    // Get changes
+    changes := config.DiffConfigs(previousConfig, newConfig)
+    if len(changes) == 0 {
+        fmt.Println("No settings have changed; there is nothing to restart.")
+        return nil
+    }
+
+    // Print changes
+    fmt.Printf("%d setting(s) changed:\n", len(changes))
+    writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+    fmt.Fprintln(writer, "Setting\tPrevious\tNew\tContainers")
+    for _, change := range changes {
+        containers := strings.Join(change.Containers, ", ")
+        if change.FullRestart {
+            containers = "all (full restart)"
+        }
+        fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", change.Setting, getDiffValue(change.OldValue), getDiffValue(change.NewValue), containers)
+    }
+    if err := writer.Flush(); err != nil {
+        return err
+    }
+    fmt.Println("")
+
+    // Print affected containers
+    containers, fullRestart := config.GetAffectedContainers(changes)
+    if fullRestart || !applied {
+        fmt.Println("Please run 'rocketpool service stop' and 'rocketpool service start' to apply these changes.")
+        return nil
+    }
+    fmt.Printf("The following containers must be recreated to apply these changes: %s\n", strings.Join(containers, ", "))
+    fmt.Println("")
+
+    // Restart affected containers
+    if !(c.Bool("restart") || (prompt && cliutils.Confirm("Would you like to restart them now?"))) {
+        fmt.Println("Please run 'rocketpool service config --diff --restart' or restart the service to apply these changes.")
+        return nil
+    }
+    if containsString(containers, config.ContainerEth2) || containsString(containers, config.ContainerValidator) {
+        fmt.Printf("%sNOTE: If you changed your ETH2 client, your validator may be slashed unless it has been stopped for at least 15 minutes. Use 'rocketpool service stop' and 'rocketpool service start' instead to run the anti-slashing safety check.%s\n", colorYellow, colorReset)
+        if !(c.Bool("restart") || cliutils.Confirm("Are you sure you want to restart the validator now?")) {
+            fmt.Println("Cancelled.")
+            return nil
+        }
+    }
+    return rp.RestartServiceContainers(getComposeFiles(c), containers...)
+
+}
+
+
+// Get a config value for display
+func getDiffValue(value string) string {
+    if value == "" {
+        return "(blank)"
+    }
+    return value
+}
+
+
+// Check whether a string slice contains a value
+func containsString(values []string, value string) bool {
+    for _, v := range values {
+        if v == value {
+            return true
+        }
+    }
+    return false
+}
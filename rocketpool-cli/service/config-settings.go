@@ -0,0 +1,340 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli"
+	"gopkg.in/yaml.v2"
+
+	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/rocketpool"
+	"github.com/rocket-pool/smartnode/shared/utils/log"
+)
+
+
+// Configure the Rocket Pool service without prompting, from a settings file and / or individual settings
+func configureServiceFromSettings(c *cli.Context) error {
+
+    // Get RP client
+    rp, err := rocketpool.NewClientFromCtx(c)
+    if err != nil { return err }
+    defer rp.Close()
+
+    // Migrate settings to the current schema version
+    if err := migrateUserSettings(rp); err != nil {
+        return err
+    }
+
+    // Load configs
+    globalConfig, err := rp.LoadGlobalConfig()
+    if err != nil {
+        return err
+    }
+    userConfig, err := rp.LoadUserConfig()
+    if err != nil {
+        return err
+    }
+    previousConfig, err := rp.LoadMergedConfig()
+    if err != nil {
+        return err
+    }
+
+    // Replace the user config with the settings file
+    if c.String("from-file") != "" {
+        userConfig, err = loadSettingsFile(c.String("from-file"))
+        if err != nil {
+            return err
+        }
+    }
+
+    // Apply individual settings
+    settingErrors := []error{}
+    for _, setting := range c.StringSlice("set") {
+        if err := applyServiceSetting(&globalConfig, &userConfig, setting); err != nil {
+            settingErrors = append(settingErrors, err)
+        }
+    }
+
+    // Resolve and validate the settings
+    settingErrors = append(settingErrors, resolveChainSettings(&(globalConfig.Chains.Eth1), &(userConfig.Chains.Eth1), "eth1", []string{})...)
+    var compatibleEth2Clients []string
+    if eth1Client := globalConfig.Chains.Eth1.GetClientById(userConfig.Chains.Eth1.Client.Selected); eth1Client != nil && eth1Client.CompatibleEth2Clients != "" {
+        compatibleEth2Clients = strings.Split(eth1Client.CompatibleEth2Clients, ";")
+    }
+    settingErrors = append(settingErrors, resolveChainSettings(&(globalConfig.Chains.Eth2), &(userConfig.Chains.Eth2), "eth2", compatibleEth2Clients)...)
+    settingErrors = append(settingErrors, resolveMetricsSettings(&(globalConfig.Metrics), &(userConfig.Metrics))...)
+    if len(settingErrors) > 0 {
+        fmt.Printf("Found %d invalid setting(s):\n", len(settingErrors))
+        for _, err := range settingErrors {
+            fmt.Printf("- %s\n", err.Error())
+        }
+        fmt.Println("")
+        return errors.New("The settings are invalid; settings.yml has not been changed.")
+    }
+
+    // Save user config
+    if err := rp.SaveUserConfig(userConfig); err != nil {
+        return err
+    }
+
+    // Print settings
+    printServiceSettings(globalConfig, userConfig)
+
+    // Log
+    fmt.Println("Done!")
+    fmt.Println("")
+
+    // Summarize changes & restart affected containers if requested
+    newConfig, err := rp.LoadMergedConfig()
+    if err != nil {
+        return err
+    }
+    return applyConfigChanges(c, rp, previousConfig, newConfig, false)
+
+}
+
+
+// Load a user settings file in the format of settings.yml, migrating it to the current schema version
+func loadSettingsFile(path string) (config.RocketPoolConfig, error) {
+    bytes, err := ioutil.ReadFile(os.ExpandEnv(path))
+    if err != nil {
+        return config.RocketPoolConfig{}, fmt.Errorf("Could not read settings file at %s: %w", path, err)
+    }
+    var userConfig config.RocketPoolConfig
+    if err := yaml.Unmarshal(bytes, &userConfig); err != nil {
+        return config.RocketPoolConfig{}, fmt.Errorf("Could not parse settings file at %s: %w", path, err)
+    }
+    if _, err := config.Migrate(&userConfig); err != nil {
+        return config.RocketPoolConfig{}, fmt.Errorf("Could not migrate settings file at %s: %w", path, err)
+    }
+    return userConfig, nil
+}
+
+
+// Apply an individual setting in the format key=value to the user config
+// Keys are eth1.client, eth1.params.<ENV>, eth2.client, eth2.params.<ENV>, metrics.enabled, metrics.params.<ENV>, logging.level, logging.format, logging.path, logging.maxSize and logging.maxBackups
+func applyServiceSetting(globalConfig, userConfig *config.RocketPoolConfig, setting string) error {
+
+    // Parse setting
+    keyValue := strings.SplitN(setting, "=", 2)
+    if len(keyValue) != 2 {
+        return fmt.Errorf("Invalid setting '%s'; settings must be in the format key=value", setting)
+    }
+    key, value := keyValue[0], keyValue[1]
+    keyParts := strings.SplitN(key, ".", 3)
+
+    // Apply setting
+    var globalChain, chain *config.Chain
+    switch keyParts[0] {
+        case "eth1": globalChain, chain = &(globalConfig.Chains.Eth1), &(userConfig.Chains.Eth1)
+        case "eth2": globalChain, chain = &(globalConfig.Chains.Eth2), &(userConfig.Chains.Eth2)
+        case "metrics":
+            if len(keyParts) == 2 && keyParts[1] == "enabled" {
+                enabled, err := strconv.ParseBool(value)
+                if err != nil {
+                    return fmt.Errorf("Invalid value '%s' for %s; must be true or false", value, key)
+                }
+                userConfig.Metrics.Enabled = enabled
+                return nil
+            }
+            if len(keyParts) == 3 && keyParts[1] == "params" {
+                if globalConfig.Metrics.GetParamByEnvName(keyParts[2]) == nil {
+                    return fmt.Errorf("Unknown metrics parameter '%s'", keyParts[2])
+                }
+                setUserParam(&(userConfig.Metrics.Settings), keyParts[2], value)
+                return nil
+            }
+        case "logging":
+            if len(keyParts) == 2 {
+                return applyLoggingSetting(&(userConfig.Logging), key, keyParts[1], value)
+            }
+    }
+    if chain != nil {
+        if len(keyParts) == 2 && keyParts[1] == "client" {
+            chain.Client.Selected = value
+            return nil
+        }
+        if len(keyParts) == 3 && keyParts[1] == "params" {
+            if !isChainParam(globalChain, keyParts[2]) {
+                return fmt.Errorf("Unknown %s parameter '%s'", keyParts[0], keyParts[2])
+            }
+            setUserParam(&(chain.Client.Params), keyParts[2], value)
+            return nil
+        }
+    }
+    return fmt.Errorf("Unknown setting '%s'; valid settings are eth1.client, eth1.params.<ENV>, eth2.client, eth2.params.<ENV>, metrics.enabled, metrics.params.<ENV>, logging.level, logging.format, logging.path, logging.maxSize and logging.maxBackups", key)
+
+}
+
+
+// Apply a logging setting; blank values restore the defaults
+func applyLoggingSetting(logging *config.Logging, key, name, value string) error {
+    switch name {
+        case "level":
+            if value != "" {
+                if _, err := log.ParseLevel(value); err != nil { return err }
+            }
+            logging.Level = value
+            return nil
+        case "format":
+            if value != "" && !log.IsValidFormat(value) {
+                return fmt.Errorf("Invalid value '%s' for %s; must be %s, %s or %s", value, key, log.FormatText, log.FormatJSON, log.FormatLogfmt)
+            }
+            logging.Format = value
+            return nil
+        case "path":
+            logging.Path = value
+            return nil
+        case "maxSize", "maxBackups":
+            var number uint64
+            if value != "" {
+                var err error
+                if number, err = strconv.ParseUint(value, 10, 64); err != nil {
+                    return fmt.Errorf("Invalid value '%s' for %s; must be a whole number", value, key)
+                }
+            }
+            if name == "maxSize" {
+                logging.MaxSize = number
+            } else {
+                logging.MaxBackups = number
+            }
+            return nil
+    }
+    return fmt.Errorf("Unknown setting '%s'; valid logging settings are logging.level, logging.format, logging.path, logging.maxSize and logging.maxBackups", key)
+}
+
+
+// Resolve the selected client and parameter values for a chain, validating them against the client's parameters
+// Optional parameters without a value use their defaults, and unselected clients' parameters are set to blank strings
+func resolveChainSettings(globalChain, userChain *config.Chain, chainName string, compatibleClients []string) []error {
+
+    // Get the selected client
+    if userChain.Client.Selected == "" {
+        return []error{fmt.Errorf("No %s client is selected; set %s.client", chainName, chainName)}
+    }
+    client := globalChain.GetClientById(userChain.Client.Selected)
+    if client == nil {
+        clientIds := make([]string, len(globalChain.Client.Options))
+        for oi, option := range globalChain.Client.Options {
+            clientIds[oi] = option.ID
+        }
+        return []error{fmt.Errorf("Unknown %s client '%s'; options are %s", chainName, userChain.Client.Selected, strings.Join(clientIds, ", "))}
+    }
+    globalChain.Client.Selected = client.ID
+
+    // Check client compatibility
+    settingErrors := []error{}
+    if len(compatibleClients) > 0 {
+        compatible := false
+        for _, compatibleId := range compatibleClients {
+            if compatibleId == client.ID {
+                compatible = true
+                break
+            }
+        }
+        if !compatible {
+            settingErrors = append(settingErrors, fmt.Errorf("The %s client '%s' is not compatible with the selected eth1 client; options are %s", chainName, client.ID, strings.Join(compatibleClients, ", ")))
+        }
+    }
+
+    // Resolve & validate params
+    params, paramErrors := resolveParams(client.Params, userChain.Client.Params, fmt.Sprintf("%s client %s", chainName, client.ID))
+    settingErrors = append(settingErrors, paramErrors...)
+
+    // Set unselected client params to blank strings to prevent docker-compose warnings
+    for _, option := range globalChain.Client.Options {
+        if option.ID == client.ID { continue }
+        for _, param := range option.Params {
+            setUserParam(&params, param.Env, getUserParam(params, param.Env))
+        }
+    }
+
+    // Set config params & return
+    userChain.Client.Params = params
+    return settingErrors
+
+}
+
+
+// Resolve and validate the metrics parameter values
+func resolveMetricsSettings(globalMetrics, userMetrics *config.Metrics) []error {
+
+    // Set all params to blank strings if metrics are disabled
+    if !userMetrics.Enabled {
+        params := []config.UserParam{}
+        for _, param := range globalMetrics.Params {
+            setUserParam(&params, param.Env, "")
+        }
+        userMetrics.Settings = params
+        return []error{}
+    }
+
+    // Resolve & validate params
+    params, paramErrors := resolveParams(globalMetrics.Params, userMetrics.Settings, "metrics")
+    userMetrics.Settings = params
+    return paramErrors
+
+}
+
+
+// Resolve parameter values from user values and defaults, and validate them
+func resolveParams(params []config.ClientParam, userParams []config.UserParam, description string) ([]config.UserParam, []error) {
+    resolved := []config.UserParam{}
+    paramErrors := []error{}
+    for _, param := range params {
+        value := getUserParam(userParams, param.Env)
+        if value == "" && !param.Required {
+            value = param.Default
+        }
+        if err := param.ValidateValue(value); err != nil {
+            paramErrors = append(paramErrors, fmt.Errorf("%s (%s in %s)", err.Error(), param.Env, description))
+        }
+        resolved = append(resolved, config.UserParam{
+            Env: param.Env,
+            Value: value,
+        })
+    }
+    return resolved, paramErrors
+}
+
+
+// Check whether a parameter belongs to any of a chain's client options
+func isChainParam(chain *config.Chain, env string) bool {
+    for _, option := range chain.Client.Options {
+        if option.GetParamByEnvName(env) != nil {
+            return true
+        }
+    }
+    return false
+}
+
+
+// Get a user parameter value by its environment variable name, or a blank string if it isn't set
+func getUserParam(params []config.UserParam, env string) string {
+    for _, param := range params {
+        if param.Env == env {
+            return param.Value
+        }
+    }
+    return ""
+}
+
+
+// Set a user parameter value by its environment variable name
+func setUserParam(params *[]config.UserParam, env string, value string) {
+    for pi := range *params {
+        if (*params)[pi].Env == env {
+            (*params)[pi].Value = value
+            return
+        }
+    }
+    *params = append(*params, config.UserParam{
+        Env: env,
+        Value: value,
+    })
+}
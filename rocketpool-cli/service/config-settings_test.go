@@ -0,0 +1,120 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rocket-pool/smartnode/shared/services/config"
+)
+
+// Client and metrics options the settings are validated against
+const testGlobalSettings = `
+chains:
+  eth1:
+    client:
+      options:
+      - id: geth
+        params:
+        - env: ETH1_MAX_PEERS
+  eth2:
+    client:
+      options:
+      - id: lighthouse
+        params:
+        - env: ETH2_MAX_PEERS
+metrics:
+  params:
+  - env: GRAFANA_PORT
+`
+
+// Existing user settings
+const testUserSettings = `
+chains:
+  eth1:
+    client:
+      selected: geth
+      params:
+      - env: ETH1_MAX_PEERS
+        value: "50"
+`
+
+
+// Parse the test global config and user settings
+func parseTestSettings(t *testing.T) (config.RocketPoolConfig, config.RocketPoolConfig) {
+    globalConfig, err := config.Parse([]byte(testGlobalSettings))
+    if err != nil {
+        t.Fatal(err)
+    }
+    userConfig, err := config.Parse([]byte(testUserSettings))
+    if err != nil {
+        t.Fatal(err)
+    }
+    return globalConfig, userConfig
+}
+
+
+func TestApplyServiceSetting(t *testing.T) {
+    globalConfig, userConfig := parseTestSettings(t)
+
+    // Apply settings in order, as with repeated --set flags
+    for _, setting := range []string{
+        "eth1.params.ETH1_MAX_PEERS=25",
+        "eth2.client=lighthouse",
+        "eth2.params.ETH2_MAX_PEERS=a=b",
+        "metrics.enabled=true",
+        "metrics.params.GRAFANA_PORT=3100",
+        "metrics.params.GRAFANA_PORT=3200",
+    } {
+        if err := applyServiceSetting(&globalConfig, &userConfig, setting); err != nil {
+            t.Fatalf("could not apply %s: %v", setting, err)
+        }
+    }
+
+    // Check settings
+    if userConfig.Chains.Eth1.Client.Selected != "geth" {
+        t.Errorf("eth1 client is %s, expected geth", userConfig.Chains.Eth1.Client.Selected)
+    }
+    if value := getUserParam(userConfig.Chains.Eth1.Client.Params, "ETH1_MAX_PEERS"); value != "25" {
+        t.Errorf("ETH1_MAX_PEERS is %s, expected 25", value)
+    }
+    if len(userConfig.Chains.Eth1.Client.Params) != 1 {
+        t.Errorf("eth1 has %d params, expected the existing param to be replaced", len(userConfig.Chains.Eth1.Client.Params))
+    }
+    if userConfig.Chains.Eth2.Client.Selected != "lighthouse" {
+        t.Errorf("eth2 client is %s, expected lighthouse", userConfig.Chains.Eth2.Client.Selected)
+    }
+    if value := getUserParam(userConfig.Chains.Eth2.Client.Params, "ETH2_MAX_PEERS"); value != "a=b" {
+        t.Errorf("ETH2_MAX_PEERS is %s, expected a=b", value)
+    }
+    if !userConfig.Metrics.Enabled {
+        t.Error("metrics are not enabled")
+    }
+    if value := getUserParam(userConfig.Metrics.Settings, "GRAFANA_PORT"); value != "3200" || len(userConfig.Metrics.Settings) != 1 {
+        t.Errorf("metrics settings are %v, expected GRAFANA_PORT 3200", userConfig.Metrics.Settings)
+    }
+
+}
+
+
+func TestApplyServiceSettingErrors(t *testing.T) {
+    globalConfig, userConfig := parseTestSettings(t)
+    tests := []struct {
+        setting string
+        errorContains string
+    }{
+        {"eth1.client", "settings must be in the format key=value"},
+        {"eth1.params.ETH2_MAX_PEERS=10", "Unknown eth1 parameter 'ETH2_MAX_PEERS'"},
+        {"metrics.params.PROMETHEUS_PORT=9091", "Unknown metrics parameter 'PROMETHEUS_PORT'"},
+        {"metrics.enabled=yes", "Invalid value 'yes' for metrics.enabled"},
+        {"eth1.provider=http://localhost:8545", "Unknown setting 'eth1.provider'"},
+        {"smartnode.image=rocketpool/smartnode:latest", "Unknown setting 'smartnode.image'"},
+    }
+    for _, test := range tests {
+        t.Run(test.setting, func(t *testing.T) {
+            err := applyServiceSetting(&globalConfig, &userConfig, test.setting)
+            if err == nil || !strings.Contains(err.Error(), test.errorContains) {
+                t.Fatalf("expected an error containing '%s', got %v", test.errorContains, err)
+            }
+        })
+    }
+}
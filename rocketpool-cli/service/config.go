@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
 	"strconv"
@@ -17,14 +18,16 @@ import (
 // Configure the Rocket Pool service
 func configureService(c *cli.Context) error {
 
-    colorReset := "\033[0m"
-    colorYellow := "\033[33m"
-
     // Get RP client
     rp, err := rocketpool.NewClientFromCtx(c)
     if err != nil { return err }
     defer rp.Close()
 
+    // Migrate settings to the current schema version
+    if err := migrateUserSettings(rp); err != nil {
+        return err
+    }
+
     // Load configs
     globalConfig, err := rp.LoadGlobalConfig()
     if err != nil {
@@ -34,6 +37,10 @@ func configureService(c *cli.Context) error {
     if err != nil {
         return err
     }
+    previousConfig, err := rp.LoadMergedConfig()
+    if err != nil {
+        return err
+    }
 
     showAdvanced := c.Bool("advanced")
 
@@ -75,6 +82,54 @@ func configureService(c *cli.Context) error {
     }
 
     // Print settings
+    printServiceSettings(globalConfig, userConfig)
+
+    // Log
+    fmt.Println("Done!\n")
+
+    // Summarize changes & restart affected containers
+    newConfig, err := rp.LoadMergedConfig()
+    if err != nil {
+        return err
+    }
+    return applyConfigChanges(c, rp, previousConfig, newConfig, true)
+
+}
+
+
+// Validate the user settings against the global config
+func validateServiceConfig(c *cli.Context) error {
+
+    // Get RP client
+    rp, err := rocketpool.NewClientFromCtx(c)
+    if err != nil { return err }
+    defer rp.Close()
+
+    // Validate settings
+    problems, err := rp.ValidateUserConfig()
+    if err != nil {
+        return err
+    }
+
+    // Print & return
+    if len(problems) == 0 {
+        fmt.Println("Your settings are valid.")
+        return nil
+    }
+    fmt.Printf("Found %d problem(s) with your settings:\n", len(problems))
+    for _, problem := range problems {
+        fmt.Printf("- %s\n", problem)
+    }
+    fmt.Println("")
+    fmt.Println("Run 'rocketpool service start' to migrate outdated settings, or 'rocketpool service config' to reconfigure the service.")
+    return errors.New("The settings are invalid.")
+
+}
+
+
+// Print the selected clients and their settings
+func printServiceSettings(globalConfig, userConfig config.RocketPoolConfig) {
+
     fmt.Println("=== ETH1 Settings ===")
     eth1Client := globalConfig.Chains.Eth1.GetClientById(userConfig.Chains.Eth1.Client.Selected)
     fmt.Printf("Selected client: %s\n", eth1Client.Name)
@@ -105,12 +160,6 @@ func configureService(c *cli.Context) error {
         fmt.Println()
     }
 
-    // Log & return
-    fmt.Println("Done!\n")
-    fmt.Printf("%sNOTE:\n", colorYellow)
-    fmt.Printf("Please run 'rocketpool service stop' and 'rocketpool service start' to apply any changes you made.%s\n", colorReset)
-    return nil
-
 }
 
 
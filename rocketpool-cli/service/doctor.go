@@ -0,0 +1,435 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/rocket-pool/rocketpool-go/utils/eth"
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/native"
+	"github.com/rocket-pool/smartnode/shared/services/rocketpool"
+	"github.com/rocket-pool/smartnode/shared/types/api"
+)
+
+// Health check statuses
+const (
+    checkOk = "ok"
+    checkWarning = "warning"
+    checkError = "error"
+    checkSkipped = "skipped"
+)
+
+// Health check thresholds
+const (
+    minDiskSpaceWarning = 50 * 1024 * 1024 * 1024
+    minDiskSpaceError = 10 * 1024 * 1024 * 1024
+    maxClockSkewEpochs = 1
+    maxGraffitiLength = 32
+    rplCollateralWarningPercent = 110
+)
+
+// Client P2P ports, by param env name with their defaults
+var p2pPorts = []struct{
+    name string
+    env string
+    defaultPort uint64
+}{
+    {"Eth 1.0", "ETH1_P2P_PORT", 30303},
+    {"Eth 2.0", "ETH2_P2P_PORT", 9001},
+}
+
+const colorGreen string = "\033[32m"
+
+
+// The result of a single health check
+type doctorCheck struct {
+    Name string                         `json:"name"`
+    Status string                       `json:"status"`
+    Message string                      `json:"message"`
+}
+
+
+// A complete health check report
+type doctorReport struct {
+    Healthy bool                        `json:"healthy"`
+    Checks []doctorCheck                `json:"checks"`
+}
+
+
+// Add a check result to the report
+func (r *doctorReport) add(name, status, format string, args ...interface{}) {
+    r.Checks = append(r.Checks, doctorCheck{
+        Name: name,
+        Status: status,
+        Message: fmt.Sprintf(format, args...),
+    })
+    if status == checkError {
+        r.Healthy = false
+    }
+}
+
+
+// Run the Rocket Pool service health checks
+func serviceDoctor(c *cli.Context) error {
+
+    // Get RP client
+    rp, err := rocketpool.NewClientFromCtx(c)
+    if err != nil { return err }
+    defer rp.Close()
+
+    // Get config
+    cfg, err := rp.LoadMergedConfig()
+    if err != nil { return err }
+
+    // Run checks
+    report := &doctorReport{Healthy: true}
+    synced := checkClientSync(rp, report)
+    checkChain(rp, report, synced)
+    walletReady := checkWallet(rp, report)
+    var details *doctorDetails
+    if synced && walletReady {
+        details = getDoctorDetails(rp, report)
+    }
+    checkValidatorKeys(report, details)
+    checkValidatorClient(rp, cfg, report)
+    checkFeesAndGraffiti(cfg, report)
+    checkDiskSpace(rp, report)
+    checkClock(report, details)
+    checkP2PPorts(rp, cfg, report)
+    checkRplCollateral(report, details)
+
+    // Print report
+    if strings.ToLower(c.String("format")) == "json" {
+        reportBytes, err := json.MarshalIndent(report, "", "    ")
+        if err != nil {
+            return fmt.Errorf("Could not encode health check report: %w", err)
+        }
+        fmt.Println(string(reportBytes))
+        return nil
+    }
+    for _, check := range report.Checks {
+        var color string
+        switch check.Status {
+            case checkOk: color = colorGreen
+            case checkWarning: color = colorYellow
+            case checkError: color = colorRed
+        }
+        fmt.Printf("%s%-7s%s  %-20s %s\n", color, check.Status, colorReset, check.Name, check.Message)
+    }
+    fmt.Println("")
+    if report.Healthy {
+        fmt.Printf("%sNo problems were found.%s\n", colorGreen, colorReset)
+    } else {
+        fmt.Printf("%sProblems were found; please resolve the errors above.%s\n", colorRed, colorReset)
+    }
+    return nil
+
+}
+
+
+// Node details used by several checks, which require synced clients & an initialized wallet
+type doctorDetails struct {
+    doctor *api.NodeDoctorResponse
+    status *api.NodeStatusResponse
+}
+
+
+// Get the node details used by the validator key, clock & collateral checks
+func getDoctorDetails(rp *rocketpool.Client, report *doctorReport) *doctorDetails {
+    details := &doctorDetails{}
+    if doctor, err := rp.NodeDoctor(); err != nil {
+        report.add("node details", checkError, "%s", err.Error())
+    } else {
+        details.doctor = &doctor
+    }
+    if status, err := rp.NodeStatus(); err != nil {
+        report.add("node status", checkError, "%s", err.Error())
+    } else {
+        details.status = &status
+    }
+    return details
+}
+
+
+// Check that both clients are synced; returns whether they are
+func checkClientSync(rp *rocketpool.Client, report *doctorReport) bool {
+    sync, err := rp.NodeSync()
+    if err != nil {
+        report.add("client sync", checkError, "Could not get client sync status: %s", err.Error())
+        return false
+    }
+    for _, client := range []struct{ name string; synced bool; progress float64 }{
+        {"eth1 sync", sync.Eth1Synced, sync.Eth1Progress},
+        {"eth2 sync", sync.Eth2Synced, sync.Eth2Progress},
+    } {
+        if client.synced {
+            report.add(client.name, checkOk, "Synced")
+        } else {
+            report.add(client.name, checkError, "Syncing (%.2f%%)", client.progress * 100)
+        }
+    }
+    return sync.Eth1Synced && sync.Eth2Synced
+}
+
+
+// Check that the eth2 client is on the chain Rocket Pool expects
+func checkChain(rp *rocketpool.Client, report *doctorReport, synced bool) {
+    if !synced {
+        report.add("chain", checkSkipped, "Clients are not synced")
+        return
+    }
+    info, err := rp.DepositContractInfo()
+    if err != nil {
+        report.add("chain", checkError, "Could not get deposit contract info: %s", err.Error())
+        return
+    }
+    if !info.SufficientSync {
+        report.add("chain", checkSkipped, "The Eth 1.0 client is not synced far enough to find the Rocket Pool contracts")
+        return
+    }
+    if info.RPNetwork != info.BeaconNetwork || info.RPDepositContract != info.BeaconDepositContract {
+        report.add("chain", checkError, "Rocket Pool expects deposit contract %s on chain %d, but the Eth 2.0 client is using deposit contract %s on chain %d",
+            info.RPDepositContract.Hex(), info.RPNetwork, info.BeaconDepositContract.Hex(), info.BeaconNetwork)
+        return
+    }
+    report.add("chain", checkOk, "Chain %d, deposit contract %s", info.RPNetwork, info.RPDepositContract.Hex())
+}
+
+
+// Check that the wallet & password are present; returns whether they are
+func checkWallet(rp *rocketpool.Client, report *doctorReport) bool {
+    status, err := rp.WalletStatus()
+    if err != nil {
+        report.add("wallet", checkError, "Could not get wallet status: %s", err.Error())
+        return false
+    }
+    switch {
+        case !status.PasswordSet:
+            report.add("wallet", checkError, "The node password has not been set")
+        case !status.WalletInitialized:
+            report.add("wallet", checkError, "The node wallet has not been initialized")
+        default:
+            report.add("wallet", checkOk, "Node account %s", status.AccountAddress.Hex())
+            return true
+    }
+    return false
+}
+
+
+// Check that a validator keystore is present for every staking minipool
+func checkValidatorKeys(report *doctorReport, details *doctorDetails) {
+    if details == nil || details.doctor == nil {
+        report.add("validator keys", checkSkipped, "Node details are unavailable")
+        return
+    }
+    doctor := details.doctor
+    missingKeys := []string{}
+    for _, key := range doctor.ValidatorKeys {
+        if !key.Stored {
+            missingKeys = append(missingKeys, key.Pubkey.Hex())
+        }
+    }
+    if len(missingKeys) > 0 {
+        report.add("validator keys", checkError, "%d of %d staking minipool validator keys are missing from the %s keystore; please run 'rocketpool wallet rebuild': %s",
+            len(missingKeys), len(doctor.ValidatorKeys), doctor.ValidatorKeystore, strings.Join(missingKeys, ", "))
+        return
+    }
+    report.add("validator keys", checkOk, "%d staking minipool validator keys present in the %s keystore", len(doctor.ValidatorKeys), doctor.ValidatorKeystore)
+}
+
+
+// Check that the process responsible for validator duties is running
+func checkValidatorClient(rp *rocketpool.Client, cfg config.RocketPoolConfig, report *doctorReport) {
+
+    // Check eth2 client
+    eth2Client := cfg.GetSelectedEth2Client()
+    if eth2Client == nil {
+        report.add("validator client", checkError, "No Eth 2.0 client selected")
+        return
+    }
+
+    // Check native unit
+    if cfg.Native.Enabled {
+        unitName := native.GetUnitName(cfg, native.GetValidatorService(cfg))
+        state, err := rp.GetNativeUnitState(unitName)
+        if err != nil {
+            report.add("validator client", checkError, "%s", err.Error())
+        } else if state.ActiveState != "active" {
+            report.add("validator client", checkError, "Unit %s is %s", unitName, state.Summary())
+        } else {
+            report.add("validator client", checkOk, "Unit %s is %s", unitName, state.Summary())
+        }
+        return
+    }
+
+    // Check container
+    containerName := cfg.Smartnode.ProjectName + "_" + config.ContainerValidator
+    if getContainerNameForValidatorDuties(eth2Client.ID, rp) != "rocketpool_validator" {
+        containerName = cfg.Smartnode.ProjectName + "_" + config.ContainerEth2
+    }
+    status, err := rp.GetDockerStatus(containerName)
+    if err != nil {
+        report.add("validator client", checkError, "%s", err.Error())
+    } else if status != "running" {
+        report.add("validator client", checkError, "Container %s is not running", containerName)
+    } else {
+        report.add("validator client", checkOk, "Container %s is running %s", containerName, eth2Client.Name)
+    }
+
+}
+
+
+// Check the gas fee & graffiti settings
+func checkFeesAndGraffiti(cfg config.RocketPoolConfig, report *doctorReport) {
+
+    // Check fees
+    maxFee := cfg.Smartnode.MaxFee
+    maxPriorityFee := cfg.Smartnode.MaxPriorityFee
+    switch {
+        case maxFee < 0 || maxPriorityFee < 0:
+            report.add("gas fees", checkError, "The max fee (%.2f gwei) and max priority fee (%.2f gwei) must not be negative", maxFee, maxPriorityFee)
+        case maxFee > 0 && maxPriorityFee > maxFee:
+            report.add("gas fees", checkError, "The max priority fee (%.2f gwei) is greater than the max fee (%.2f gwei)", maxPriorityFee, maxFee)
+        case maxFee == 0:
+            report.add("gas fees", checkOk, "The max fee is set automatically; max priority fee %.2f gwei", maxPriorityFee)
+        default:
+            report.add("gas fees", checkOk, "Max fee %.2f gwei, max priority fee %.2f gwei", maxFee, maxPriorityFee)
+    }
+
+    // Check graffiti; the graffiti is prefixed with the Rocket Pool version and limited to 32 bytes
+    var customGraffiti string
+    for _, param := range cfg.Chains.Eth2.Client.Params {
+        if param.Env == "CUSTOM_GRAFFITI" {
+            customGraffiti = param.Value
+        }
+    }
+    graffiti := cfg.Smartnode.GraffitiVersion
+    if customGraffiti != "" {
+        graffiti = fmt.Sprintf("%s (%s)", graffiti, customGraffiti)
+    }
+    if len(graffiti) > maxGraffitiLength {
+        report.add("graffiti", checkWarning, "Graffiti '%s' is %d bytes and will be truncated to %d bytes", graffiti, len(graffiti), maxGraffitiLength)
+    } else {
+        report.add("graffiti", checkOk, "'%s'", graffiti)
+    }
+
+}
+
+
+// Check the free disk space on the service's data volumes
+func checkDiskSpace(rp *rocketpool.Client, report *doctorReport) {
+    paths, err := rp.GetServiceDataPaths()
+    if err != nil {
+        report.add("disk space", checkSkipped, "Could not get data paths: %s", err.Error())
+        return
+    }
+    usage, err := rp.GetDiskUsage(paths...)
+    if err != nil {
+        report.add("disk space", checkSkipped, "%s", err.Error())
+        return
+    }
+    checked := map[string]bool{}
+    for _, disk := range usage {
+        if checked[disk.MountPoint] { continue }
+        checked[disk.MountPoint] = true
+        status := checkOk
+        if disk.Available < minDiskSpaceError {
+            status = checkError
+        } else if disk.Available < minDiskSpaceWarning {
+            status = checkWarning
+        }
+        report.add("disk space", status, "%s free of %s on %s (%s)", humanize.IBytes(disk.Available), humanize.IBytes(disk.Total), disk.MountPoint, disk.Path)
+    }
+}
+
+
+// Check the node's clock against the beacon chain genesis schedule
+func checkClock(report *doctorReport, details *doctorDetails) {
+    if details == nil || details.doctor == nil || details.doctor.SecondsPerEpoch == 0 || details.doctor.ClockTime < details.doctor.GenesisTime {
+        report.add("clock", checkSkipped, "Node details are unavailable")
+        return
+    }
+    expected := int64((details.doctor.ClockTime - details.doctor.GenesisTime) / details.doctor.SecondsPerEpoch)
+    head := int64(details.doctor.HeadEpoch)
+    skew := expected - head
+    if skew > maxClockSkewEpochs || skew < -maxClockSkewEpochs {
+        report.add("clock", checkWarning, "The system clock places the chain at epoch %d, but the beacon head is at epoch %d; please check that the system clock is synchronized (e.g. with NTP)", expected, head)
+        return
+    }
+    report.add("clock", checkOk, "The system clock matches the beacon chain schedule (epoch %d at %s)", expected, time.Now().Format(time.RFC3339))
+}
+
+
+// Check that the clients' P2P ports are being listened on
+func checkP2PPorts(rp *rocketpool.Client, cfg config.RocketPoolConfig, report *doctorReport) {
+    for _, p2pPort := range p2pPorts {
+
+        // Get port from the settings, the client defaults or the standard port
+        port := p2pPort.defaultPort
+        value := ""
+        for _, client := range []*config.ClientOption{cfg.GetSelectedEth1Client(), cfg.GetSelectedEth2Client()} {
+            if client == nil { continue }
+            if param := client.GetParamByEnvName(p2pPort.env); param != nil && param.Default != "" {
+                value = param.Default
+            }
+        }
+        for _, params := range [][]config.UserParam{cfg.Chains.Eth1.Client.Params, cfg.Chains.Eth2.Client.Params} {
+            for _, param := range params {
+                if param.Env == p2pPort.env && param.Value != "" {
+                    value = param.Value
+                }
+            }
+        }
+        if value != "" {
+            if parsed, err := strconv.ParseUint(value, 10, 16); err == nil {
+                port = parsed
+            }
+        }
+
+        // Check port
+        name := fmt.Sprintf("%s p2p port", p2pPort.name)
+        listening, err := rp.IsPortListening(port)
+        if err != nil {
+            report.add(name, checkSkipped, "%s", err.Error())
+        } else if !listening {
+            report.add(name, checkWarning, "Nothing is listening on port %d; the client will have trouble finding peers", port)
+        } else {
+            report.add(name, checkOk, "Listening on port %d; make sure it is forwarded by your router and allowed by your firewall", port)
+        }
+
+    }
+}
+
+
+// Check that the node's RPL stake is above the minimum collateral
+func checkRplCollateral(report *doctorReport, details *doctorDetails) {
+    if details == nil || details.status == nil {
+        report.add("rpl collateral", checkSkipped, "Node details are unavailable")
+        return
+    }
+    status := details.status
+    if !status.Registered {
+        report.add("rpl collateral", checkSkipped, "The node is not registered")
+        return
+    }
+    if status.MinipoolCounts.Staking == 0 || status.MinimumRplStake == nil || status.RplStake == nil {
+        report.add("rpl collateral", checkOk, "The node has no staking minipools")
+        return
+    }
+    warningStake := new(big.Int).Div(new(big.Int).Mul(status.MinimumRplStake, big.NewInt(rplCollateralWarningPercent)), big.NewInt(100))
+    switch {
+        case status.RplStake.Cmp(status.MinimumRplStake) < 0:
+            report.add("rpl collateral", checkError, "The node's RPL stake of %.6f RPL is below the minimum of %.6f RPL and will not earn RPL rewards", eth.WeiToEth(status.RplStake), eth.WeiToEth(status.MinimumRplStake))
+        case status.RplStake.Cmp(warningStake) < 0:
+            report.add("rpl collateral", checkWarning, "The node's RPL stake of %.6f RPL is close to the minimum of %.6f RPL", eth.WeiToEth(status.RplStake), eth.WeiToEth(status.MinimumRplStake))
+        default:
+            report.add("rpl collateral", checkOk, "%.6f RPL staked (minimum %.6f RPL)", eth.WeiToEth(status.RplStake), eth.WeiToEth(status.MinimumRplStake))
+    }
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	rptypes "github.com/rocket-pool/rocketpool-go/types"
+	"github.com/rocket-pool/rocketpool-go/utils/eth"
+
+	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/types/api"
+)
+
+
+// Run a check against a new report and get its single result
+func runDoctorCheck(t *testing.T, check func(report *doctorReport)) (doctorCheck, bool) {
+    report := &doctorReport{Healthy: true}
+    check(report)
+    if len(report.Checks) != 1 {
+        t.Fatalf("check added %d results, expected 1", len(report.Checks))
+    }
+    return report.Checks[0], report.Healthy
+}
+
+
+func TestDoctorReport(t *testing.T) {
+    report := &doctorReport{Healthy: true}
+    report.add("a", checkOk, "ok")
+    report.add("b", checkWarning, "warning")
+    report.add("c", checkSkipped, "skipped")
+    if !report.Healthy {
+        t.Error("report with warnings is unhealthy")
+    }
+    report.add("d", checkError, "error %d", 1)
+    if report.Healthy {
+        t.Error("report with an error is healthy")
+    }
+    if check := report.Checks[3]; check.Name != "d" || check.Message != "error 1" {
+        t.Errorf("check is %+v", check)
+    }
+}
+
+
+func TestCheckValidatorKeys(t *testing.T) {
+    stored := api.ValidatorKeyDetails{Pubkey: rptypes.BytesToValidatorPubkey([]byte{0x01}), Stored: true}
+    missing := api.ValidatorKeyDetails{Pubkey: rptypes.BytesToValidatorPubkey([]byte{0x02})}
+    tests := []struct {
+        name string
+        details *doctorDetails
+        status string
+        messageContains string
+    }{
+        {"no details", nil, checkSkipped, "unavailable"},
+        {"no doctor details", &doctorDetails{}, checkSkipped, "unavailable"},
+        {"no keys", &doctorDetails{doctor: &api.NodeDoctorResponse{ValidatorKeystore: "lighthouse"}}, checkOk, "0 staking minipool validator keys present in the lighthouse keystore"},
+        {"stored keys", &doctorDetails{doctor: &api.NodeDoctorResponse{ValidatorKeystore: "lighthouse", ValidatorKeys: []api.ValidatorKeyDetails{stored, stored}}}, checkOk, "2 staking minipool"},
+        {"missing key", &doctorDetails{doctor: &api.NodeDoctorResponse{ValidatorKeystore: "prysm", ValidatorKeys: []api.ValidatorKeyDetails{stored, missing}}}, checkError, "1 of 2 staking minipool validator keys are missing from the prysm keystore"},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            check, _ := runDoctorCheck(t, func(report *doctorReport) { checkValidatorKeys(report, test.details) })
+            if check.Status != test.status || !strings.Contains(check.Message, test.messageContains) {
+                t.Errorf("check is %s (%s), expected %s containing '%s'", check.Status, check.Message, test.status, test.messageContains)
+            }
+        })
+    }
+}
+
+
+func TestCheckFeesAndGraffiti(t *testing.T) {
+    tests := []struct {
+        name string
+        maxFee float64
+        maxPriorityFee float64
+        graffiti string
+        feeStatus string
+        graffitiStatus string
+    }{
+        {"automatic fee", 0, 2, "", checkOk, checkOk},
+        {"set fees", 100, 2, "", checkOk, checkOk},
+        {"negative fee", -1, 2, "", checkError, checkOk},
+        {"priority fee above max fee", 10, 20, "", checkError, checkOk},
+        {"short graffiti", 0, 2, "rocket", checkOk, checkOk},
+        {"long graffiti", 0, 2, "a graffiti longer than thirty two bytes", checkOk, checkWarning},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            cfg := config.RocketPoolConfig{}
+            cfg.Smartnode.MaxFee = test.maxFee
+            cfg.Smartnode.MaxPriorityFee = test.maxPriorityFee
+            cfg.Smartnode.GraffitiVersion = "RP v1.2.0"
+            cfg.Chains.Eth2.Client.Params = []config.UserParam{{Env: "CUSTOM_GRAFFITI", Value: test.graffiti}}
+            report := &doctorReport{Healthy: true}
+            checkFeesAndGraffiti(cfg, report)
+            if len(report.Checks) != 2 {
+                t.Fatalf("got %d results, expected 2", len(report.Checks))
+            }
+            if report.Checks[0].Status != test.feeStatus || report.Checks[1].Status != test.graffitiStatus {
+                t.Errorf("checks are %+v, expected %s and %s", report.Checks, test.feeStatus, test.graffitiStatus)
+            }
+        })
+    }
+}
+
+
+func TestCheckClock(t *testing.T) {
+    details := func(clockTime, headEpoch uint64) *doctorDetails {
+        return &doctorDetails{doctor: &api.NodeDoctorResponse{GenesisTime: 1000, SecondsPerEpoch: 384, ClockTime: clockTime, HeadEpoch: headEpoch}}
+    }
+    tests := []struct {
+        name string
+        details *doctorDetails
+        status string
+    }{
+        {"no details", nil, checkSkipped},
+        {"before genesis", details(999, 0), checkSkipped},
+        {"in sync", details(1000 + 384 * 100, 100), checkOk},
+        {"one epoch ahead", details(1000 + 384 * 101, 100), checkOk},
+        {"one epoch behind", details(1000 + 384 * 99, 100), checkOk},
+        {"ahead", details(1000 + 384 * 102, 100), checkWarning},
+        {"behind", details(1000 + 384 * 98, 100), checkWarning},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            check, _ := runDoctorCheck(t, func(report *doctorReport) { checkClock(report, test.details) })
+            if check.Status != test.status {
+                t.Errorf("check is %s (%s), expected %s", check.Status, check.Message, test.status)
+            }
+        })
+    }
+}
+
+
+func TestCheckRplCollateral(t *testing.T) {
+    status := func(registered bool, staking int, rplStake float64) *doctorDetails {
+        response := &api.NodeStatusResponse{Registered: registered, RplStake: eth.EthToWei(rplStake), MinimumRplStake: eth.EthToWei(100)}
+        response.MinipoolCounts.Staking = staking
+        return &doctorDetails{status: response}
+    }
+    tests := []struct {
+        name string
+        details *doctorDetails
+        status string
+    }{
+        {"no details", nil, checkSkipped},
+        {"not registered", status(false, 1, 0), checkSkipped},
+        {"no staking minipools", status(true, 0, 0), checkOk},
+        {"below minimum", status(true, 1, 99.9), checkError},
+        {"at minimum", status(true, 1, 100), checkWarning},
+        {"near minimum", status(true, 1, 109.9), checkWarning},
+        {"above margin", status(true, 1, 110), checkOk},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            check, healthy := runDoctorCheck(t, func(report *doctorReport) { checkRplCollateral(report, test.details) })
+            if check.Status != test.status || healthy != (test.status != checkError) {
+                t.Errorf("check is %s (%s), healthy %t; expected %s", check.Status, check.Message, healthy, test.status)
+            }
+        })
+    }
+}
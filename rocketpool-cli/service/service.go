@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
 	"time"
@@ -8,6 +9,7 @@ import (
 	"github.com/urfave/cli"
 
 	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/native"
 	"github.com/rocket-pool/smartnode/shared/services/rocketpool"
 	cliutils "github.com/rocket-pool/smartnode/shared/utils/cli"
 )
@@ -18,6 +20,19 @@ const colorRed string = "\033[31m"
 const colorYellow string = "\033[33m"
 const clearLine string = "\033[2K"
 
+// Check the legacy installer options, and warn that the legacy installer is unverified
+func checkLegacyInstaller(c *cli.Context) error {
+    if !c.Bool("legacy-installer") {
+        return nil
+    }
+    if c.String("bundle") != "" {
+        return errors.New("The --legacy-installer and --bundle options cannot be used together.")
+    }
+    fmt.Printf("%sWARNING: the legacy installer downloads and runs the release's install script without verifying its signature or checksum.%s\n\n", colorYellow, colorReset)
+    return nil
+}
+
+
 // Install the Rocket Pool service
 func installService(c *cli.Context) error {
 
@@ -29,10 +44,19 @@ func installService(c *cli.Context) error {
         location = fmt.Sprintf("at %s", c.GlobalString("host"))
     }
 
+    // Get install mode
+    mode := "docker"
+    if c.Bool("native") {
+        mode = fmt.Sprintf("native (systemd, daemon at %s)", c.String("daemon-binary"))
+    }
+
+    // Check installer
+    if err := checkLegacyInstaller(c); err != nil { return err }
+
     // Prompt for confirmation
     if !(c.Bool("yes") || cliutils.Confirm(fmt.Sprintf(
-        "The Rocket Pool service will be installed %s --\nNetwork: %s\nVersion: %s\n\nAny existing configuration will be overwritten.\nAre you sure you want to continue?",
-        location, c.String("network"), c.String("version"),
+        "The Rocket Pool service will be installed %s --\nNetwork: %s\nVersion: %s\nMode: %s\n\nAny existing configuration will be overwritten.\nAre you sure you want to continue?",
+        location, c.String("network"), c.String("version"), mode,
     ))) {
         fmt.Println("Cancelled.")
         return nil
@@ -43,16 +67,40 @@ func installService(c *cli.Context) error {
     if err != nil { return err }
     defer rp.Close()
 
-    // Install service
-    err = rp.InstallService(c.Bool("verbose"), c.Bool("no-deps"), c.String("network"), c.String("version"), c.String("path"))
+    // Install service; docker is not required in native mode
+    if c.Bool("legacy-installer") {
+        err = rp.InstallServiceLegacy(c.Bool("verbose"), c.Bool("no-deps") || c.Bool("native"), c.String("network"), c.String("version"), c.String("path"))
+    } else {
+        err = rp.InstallService(c.Bool("verbose"), c.Bool("no-deps") || c.Bool("native"), c.String("network"), c.String("version"), c.String("path"), c.String("bundle"))
+    }
     if err != nil { return err }
 
+    // Migrate existing settings to the installed version
+    if c.GlobalString("host") == "" {
+        if err := migrateUserSettings(rp); err != nil {
+            return err
+        }
+    }
+
+    // Configure native mode
+    if c.Bool("native") {
+        if err := rp.ConfigureNativeService(c.String("daemon-binary")); err != nil {
+            return err
+        }
+    }
+
     // Print success message & return
     colorReset := "\033[0m"
     colorYellow := "\033[33m"
     fmt.Println("")
     fmt.Printf("The Rocket Pool service was successfully installed %s!\n", location)
-    if c.GlobalString("host") == "" {
+    if c.Bool("native") {
+        fmt.Println("")
+        fmt.Printf("%sNOTE:\nIn native mode, the Rocket Pool daemon must be installed at %s and your Eth 1.0 & Eth 2.0 clients must be installed on the system path.\n", colorYellow, c.String("daemon-binary"))
+        fmt.Println("Clients without a native command in config.yml can be configured with the 'native' section of settings.yml.")
+        fmt.Printf("Starting the service installs systemd units, which requires sudo access.%s\n", colorReset)
+        fmt.Println("")
+    } else if c.GlobalString("host") == "" {
         fmt.Println("")
         fmt.Printf("%sNOTE:\nIf this is your first time installing Rocket Pool, please start a new shell session by logging out and back in or restarting the machine.\n", colorYellow)
         fmt.Println("This is necessary for your user account to have permissions to use Docker.")
@@ -65,6 +113,48 @@ func installService(c *cli.Context) error {
 }
 
 
+// Roll the Rocket Pool service back to the previously installed version
+func rollbackService(c *cli.Context) error {
+
+    // Get RP client
+    rp, err := rocketpool.NewClientFromCtx(c)
+    if err != nil { return err }
+    defer rp.Close()
+
+    // Get installations
+    current, previous, err := rp.GetInstalls()
+    if err != nil { return err }
+    if previous == nil {
+        fmt.Println("There is no previous installation to roll back to.")
+        return nil
+    }
+    currentVersion := "(unknown)"
+    if current != nil {
+        currentVersion = current.Version
+    }
+
+    // Prompt for confirmation
+    if !(c.Bool("yes") || cliutils.Confirm(fmt.Sprintf(
+        "The Rocket Pool service will be rolled back --\nCurrent version: %s\nPrevious version: %s (%s, installed %s)\n\nYour settings will be restored to those in place when %s was replaced.\nAre you sure you want to continue?",
+        currentVersion, previous.Version, previous.Network, previous.InstalledAt.Format(time.RFC1123), previous.Version,
+    ))) {
+        fmt.Println("Cancelled.")
+        return nil
+    }
+
+    // Roll back service
+    info, err := rp.RollbackService(c.Bool("verbose"))
+    if err != nil { return err }
+
+    // Print success message & return
+    fmt.Println("")
+    fmt.Printf("The Rocket Pool service was successfully rolled back to %s.\n", info.Version)
+    fmt.Println("Please run 'rocketpool service start' to restart the service with the previous version.")
+    return nil
+
+}
+
+
 // Install the Rocket Pool update tracker for the metrics dashboard
 func installUpdateTracker(c *cli.Context) error {
 
@@ -76,6 +166,9 @@ func installUpdateTracker(c *cli.Context) error {
         location = fmt.Sprintf("at %s", c.GlobalString("host"))
     }
 
+    // Check installer
+    if err := checkLegacyInstaller(c); err != nil { return err }
+
     // Prompt for confirmation
     if !(c.Bool("yes") || cliutils.Confirm(
         "This will add the ability to display any available Operating System updates or new Rocket Pool versions on the metrics dashboard. " +
@@ -90,7 +183,11 @@ func installUpdateTracker(c *cli.Context) error {
     defer rp.Close()
 
     // Install service
-    err = rp.InstallUpdateTracker(c.Bool("verbose"), c.String("version"))
+    if c.Bool("legacy-installer") {
+        err = rp.InstallUpdateTrackerLegacy(c.Bool("verbose"), c.String("version"))
+    } else {
+        err = rp.InstallUpdateTracker(c.Bool("verbose"), c.String("version"), c.String("bundle"))
+    }
     if err != nil { return err }
 
     // Print success message & return
@@ -136,6 +233,11 @@ func startService(c *cli.Context) error {
     if err != nil { return err }
     defer rp.Close()
 
+    // Migrate settings to the current schema version
+    if err := migrateUserSettings(rp); err != nil {
+        return err
+    }
+
     // Update the Prometheus template with the assigned ports
     userConfig, err := rp.LoadUserConfig()
     if err != nil {
@@ -151,7 +253,15 @@ func startService(c *cli.Context) error {
 
     if !c.Bool("ignore-slash-timer") {
         // Do the client swap check
-        err := checkForValidatorChange(rp, userConfig)
+        isNative, err := rp.IsNativeMode()
+        if err != nil {
+            return err
+        }
+        if isNative {
+            err = checkForNativeValidatorChange(rp)
+        } else {
+            err = checkForValidatorChange(rp, userConfig)
+        }
         if err != nil {
             fmt.Printf("%sWarning: couldn't verify that the validator container can be safely restarted:\n\t%s\n", colorYellow, err.Error())
             fmt.Println("If you are changing to a different ETH2 client, it may resubmit an attestation you have already submitted.")
@@ -173,6 +283,24 @@ func startService(c *cli.Context) error {
 }
 
 
+// Migrate the user settings to the current schema version, backing up the original settings
+func migrateUserSettings(rp *rocketpool.Client) error {
+    backupPath, steps, err := rp.MigrateUserConfig()
+    if err != nil {
+        return fmt.Errorf("Error migrating user settings: %w", err)
+    }
+    if backupPath == "" {
+        return nil
+    }
+    fmt.Printf("Migrated your settings to schema version %d:\n", config.CurrentSchemaVersion)
+    for _, step := range steps {
+        fmt.Printf("- %s\n", step)
+    }
+    fmt.Printf("Your previous settings were backed up to %s.\n\n", backupPath)
+    return nil
+}
+
+
 func checkForValidatorChange(rp *rocketpool.Client, userConfig config.RocketPoolConfig) (error) {
 
     // Get the current validator client
@@ -230,32 +358,98 @@ func checkForValidatorChange(rp *rocketpool.Client, userConfig config.RocketPool
         }
 
         // Print the warning and start the time lockout
-        safeStartTime := validatorFinishTime.Add(15 * time.Minute)
-        remainingTime := time.Until(safeStartTime)
-        if remainingTime <= 0 {
-            fmt.Printf("The validator has been offline for %s, which is long enough to prevent slashing.\n", time.Since(validatorFinishTime))
-            fmt.Println("The new client can be safely started.")
-        } else {
-            fmt.Printf("%s=== WARNING ===\n", colorRed)
-            fmt.Printf("You have changed your validator client from %s to %s.\n", currentValidatorName, pendingValidatorName)
-            fmt.Println("If you have active validators, starting the new client immediately will cause them to be slashed due to duplicate attestations!")
-            fmt.Println("To prevent slashing, Rocket Pool will delay activating the new client for 15 minutes.")
-            fmt.Printf("If you want to bypass this cooldown and understand the risks, rerun this command with the `--ignore-slash-timer` flag.%s\n\n", colorReset)
-
-            // Wait for 15 minutes
-            for remainingTime > 0 {
-                fmt.Printf("Remaining time: %s", remainingTime)
-                time.Sleep(1 * time.Second)
-                remainingTime = time.Until(safeStartTime)
-                fmt.Printf("%s\r", clearLine)
-            }
+        waitForSlashingDelay(validatorFinishTime, currentValidatorName, pendingValidatorName)
+    }
+
+    return nil
+}
+
+
+// Check for a validator client change in native mode, stopping the old client's unit and waiting if necessary
+func checkForNativeValidatorChange(rp *rocketpool.Client) error {
+
+    // Get the eth2 client the service was last started with
+    appliedConfig, exists, err := rp.LoadAppliedConfig()
+    if err != nil {
+        return fmt.Errorf("Error loading applied settings: %w", err)
+    }
+    if exists && !appliedConfig.Native.Enabled {
+        return fmt.Errorf("The service was last started in docker mode. Please make sure the docker validator container is no longer running before starting the native validator.")
+    }
+    currentClient := appliedConfig.GetSelectedEth2Client()
+    if !exists || currentClient == nil {
+        fmt.Println("This is the first time starting Rocket Pool in native mode - no slashing prevention delay necessary.")
+        return nil
+    }
+
+    // Get the new eth2 client
+    cfg, err := rp.LoadMergedConfig()
+    if err != nil {
+        return fmt.Errorf("Error loading settings: %w", err)
+    }
+    newClient := cfg.GetSelectedEth2Client()
+    if newClient == nil {
+        return fmt.Errorf("Error getting selected client - either it does not exist (user has not run `rocketpool service config` yet) or the selected client is invalid.")
+    }
+    if currentClient.ID == newClient.ID {
+        fmt.Printf("Validator client [%s] was previously used - no slashing prevention delay necessary.\n", currentClient.Name)
+        return nil
+    }
 
-            fmt.Println(colorReset)
-            fmt.Println("You may now safely start the validator without fear of being slashed.")
+    // Get the state of the unit responsible for validator duties under the previous config
+    validatorUnitName := native.GetUnitName(appliedConfig, native.GetValidatorService(appliedConfig))
+    state, err := rp.GetNativeUnitState(validatorUnitName)
+    if err != nil {
+        return fmt.Errorf("Error getting unit [%s] state: %w", validatorUnitName, err)
+    }
+
+    // If it hasn't exited yet, shut it down
+    validatorFinishTime := state.InactiveEnterTime
+    if state.ActiveState != "inactive" && state.ActiveState != "failed" {
+        fmt.Printf("%sValidator is currently running, stopping it...%s\n", colorYellow, colorReset)
+        if err := rp.StopNativeUnit(validatorUnitName); err != nil {
+            return err
         }
+        validatorFinishTime = time.Now()
+    } else if validatorFinishTime.IsZero() && state.LoadState != "not-found" {
+        validatorFinishTime = time.Now()
     }
 
+    // Print the warning and start the time lockout
+    waitForSlashingDelay(validatorFinishTime, currentClient.Name, newClient.Name)
     return nil
+
+}
+
+
+// Wait until the validator has been offline for long enough to safely start a new client
+func waitForSlashingDelay(validatorFinishTime time.Time, currentValidatorName, pendingValidatorName string) {
+
+    safeStartTime := validatorFinishTime.Add(15 * time.Minute)
+    remainingTime := time.Until(safeStartTime)
+    if remainingTime <= 0 {
+        fmt.Printf("The validator has been offline for %s, which is long enough to prevent slashing.\n", time.Since(validatorFinishTime))
+        fmt.Println("The new client can be safely started.")
+        return
+    }
+
+    fmt.Printf("%s=== WARNING ===\n", colorRed)
+    fmt.Printf("You have changed your validator client from %s to %s.\n", currentValidatorName, pendingValidatorName)
+    fmt.Println("If you have active validators, starting the new client immediately will cause them to be slashed due to duplicate attestations!")
+    fmt.Println("To prevent slashing, Rocket Pool will delay activating the new client for 15 minutes.")
+    fmt.Printf("If you want to bypass this cooldown and understand the risks, rerun this command with the `--ignore-slash-timer` flag.%s\n\n", colorReset)
+
+    // Wait for 15 minutes
+    for remainingTime > 0 {
+        fmt.Printf("Remaining time: %s", remainingTime)
+        time.Sleep(1 * time.Second)
+        remainingTime = time.Until(safeStartTime)
+        fmt.Printf("%s\r", clearLine)
+    }
+
+    fmt.Println(colorReset)
+    fmt.Println("You may now safely start the validator without fear of being slashed.")
+
 }
 
 
@@ -334,17 +528,23 @@ func pauseService(c *cli.Context) error {
 // Stop the Rocket Pool service
 func stopService(c *cli.Context) error {
 
-    // Prompt for confirmation
-    if !(c.Bool("yes") || cliutils.Confirm("Are you sure you want to terminate the Rocket Pool service? Any staking minipools will be penalized, chain databases will be deleted, and ethereum nodes will lose ALL sync progress!")) {
-        fmt.Println("Cancelled.")
-        return nil
-    }
-
     // Get RP client
     rp, err := rocketpool.NewClientFromCtx(c)
     if err != nil { return err }
     defer rp.Close()
 
+    // Prompt for confirmation; chain data is kept in native mode
+    isNative, err := rp.IsNativeMode()
+    if err != nil { return err }
+    prompt := "Are you sure you want to terminate the Rocket Pool service? Any staking minipools will be penalized, chain databases will be deleted, and ethereum nodes will lose ALL sync progress!"
+    if isNative {
+        prompt = "Are you sure you want to terminate the Rocket Pool service? Any staking minipools will be penalized, and the service's systemd units will be removed."
+    }
+    if !(c.Bool("yes") || cliutils.Confirm(prompt)) {
+        fmt.Println("Cancelled.")
+        return nil
+    }
+
     // Stop service
     return rp.StopService(getComposeFiles(c))
 
@@ -382,6 +582,21 @@ func serviceStats(c *cli.Context) error {
 // View the Rocket Pool service version information
 func serviceVersion(c *cli.Context) error {
 
+    // Get the version information of all nodes
+    if c.GlobalBool("all-nodes") {
+        fmt.Printf("Rocket Pool client version: %s\n\n", c.App.Version)
+        return cliutils.PrintAllNodes(c, []string{"Network", "Service", "Eth 1.0 Client", "Eth 2.0 Client"}, func(rp *rocketpool.Client) ([]string, error) {
+            network, err := cliutils.GetNetworkName(rp)
+            if err != nil { return nil, err }
+            serviceVersion, err := rp.GetServiceVersion()
+            if err != nil { return nil, err }
+            cfg, err := rp.LoadMergedConfig()
+            if err != nil { return nil, err }
+            eth1ClientVersion, eth2ClientVersion := getClientVersions(&cfg)
+            return []string{network, serviceVersion, eth1ClientVersion, eth2ClientVersion}, nil
+        })
+    }
+
     // Get RP client
     rp, err := rocketpool.NewClientFromCtx(c)
     if err != nil { return err }
@@ -400,10 +615,24 @@ func serviceVersion(c *cli.Context) error {
     // Get config
     cfg, err := rp.LoadMergedConfig()
     if err != nil { return err }
-    eth1Client := cfg.GetSelectedEth1Client()
-    eth2Client := cfg.GetSelectedEth2Client()
 
     // Get client versions
+    eth1ClientVersion, eth2ClientVersion := getClientVersions(&cfg)
+
+    // Print version info
+    fmt.Printf("Rocket Pool client version: %s\n", c.App.Version)
+    fmt.Printf("Rocket Pool service version: %s\n", serviceVersion)
+    fmt.Printf("Selected Eth 1.0 client: %s\n", eth1ClientVersion)
+    fmt.Printf("Selected Eth 2.0 client: %s\n", eth2ClientVersion)
+    return nil
+
+}
+
+
+// Get the names and images of the selected eth1 and eth2 clients
+func getClientVersions(cfg *config.RocketPoolConfig) (string, string) {
+    eth1Client := cfg.GetSelectedEth1Client()
+    eth2Client := cfg.GetSelectedEth2Client()
     var eth1ClientVersion string
     var eth2ClientVersion string
     var eth2ClientImage string
@@ -422,14 +651,7 @@ func serviceVersion(c *cli.Context) error {
     } else {
         eth2ClientVersion = "(none)"
     }
-
-    // Print version info
-    fmt.Printf("Rocket Pool client version: %s\n", c.App.Version)
-    fmt.Printf("Rocket Pool service version: %s\n", serviceVersion)
-    fmt.Printf("Selected Eth 1.0 client: %s\n", eth1ClientVersion)
-    fmt.Printf("Selected Eth 2.0 client: %s\n", eth2ClientVersion)
-    return nil
-
+    return eth1ClientVersion, eth2ClientVersion
 }
 
 
@@ -6,13 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"math"
 	"net/http"
 	"os"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/rocket-pool/smartnode/shared/utils/log"
 )
 
 // Config
@@ -21,6 +22,10 @@ const PocketURL = "https://%s.gateway.pokt.network/v1/%s"
 const HandleRequestRecursionLimit = 3
 
 
+// Logger
+var httpLog = log.NewLogger("http-proxy", 0)
+
+
 // Proxy server
 type HttpProxyServer struct {
     Port string
@@ -72,7 +77,7 @@ func NewHttpProxyServer(port string, providerUrl string, network string, project
 func (p *HttpProxyServer) Start() error {
 
     // Log
-    log.Printf("Proxy server listening on port %s\n", p.Port)
+    httpLog.Infof("Proxy server listening on port %s", p.Port)
 
     // Listen on RPC port
     return http.ListenAndServe(":" + p.Port, p)
@@ -89,12 +94,12 @@ func (p *HttpProxyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
     p.idLock.Unlock()
 
     // Log request
-    log.Printf("New %s request received from %s\n", r.Method, r.RemoteAddr)
+    httpLog.Infof("New %s request received from %s", r.Method, r.RemoteAddr)
 
     // Get request content type
     contentTypes, ok := r.Header["Content-Type"]
     if !ok || len(contentTypes) == 0 {
-        log.Println(errors.New("Request Content-Type header not specified"))
+        httpLog.Error(errors.New("Request Content-Type header not specified"))
         _,_ = fmt.Fprintln(w, errors.New("Request Content-Type header not specified"))
         return
     }
@@ -103,7 +108,7 @@ func (p *HttpProxyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
     requestBuffer := new(bytes.Buffer)
     _, err := requestBuffer.ReadFrom(r.Body)
     if err != nil {
-        log.Println(fmt.Errorf("Error getting request body string: %w", err))
+        httpLog.Error(fmt.Errorf("Error getting request body string: %w", err))
         _, _ = fmt.Fprintln(w, fmt.Errorf("Error getting request body string: %w", err))
         return
     }
@@ -111,13 +116,13 @@ func (p *HttpProxyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
     // Log request if in verbose mode
     if p.Verbose {
-        fmt.Printf("(< %d) %s\n", messageId, requestBody)
+        httpLog.Debugf("(< %d) %s", messageId, requestBody)
     }
 
     // Handle the request
     responseReader, err := p.handleRequest(contentTypes[0], requestBody, messageId, 0)
     if err != nil {
-        log.Println(err.Error())
+        httpLog.Error(err.Error())
         _, _ =fmt.Fprintln(w, err.Error())
         return
     }
@@ -128,13 +133,13 @@ func (p *HttpProxyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
     // Copy provider response body to response writer
     _, err = io.Copy(w, responseReader)
     if err != nil {
-        log.Println(fmt.Errorf("Error reading response from remote server: %w", err))
+        httpLog.Error(fmt.Errorf("Error reading response from remote server: %w", err))
         _, _ =fmt.Fprintln(w, fmt.Errorf("Error reading response from remote server: %w", err))
         return
     }
 
     // Log success
-    log.Printf("Response sent to %s successfully\n", r.RemoteAddr)
+    httpLog.Infof("Response sent to %s successfully", r.RemoteAddr)
 }
 
 
@@ -167,7 +172,7 @@ func (p *HttpProxyServer) handleRequest(contentType, requestBody string, message
 
     // Log response if in verbose mode
     if p.Verbose {
-        fmt.Printf("(> %d) %s\n", messageId, responseBody)
+        httpLog.Debugf("(> %d) %s", messageId, responseBody)
     }
     
     // If using Infura, check for a rate limit error
@@ -182,11 +187,11 @@ func (p *HttpProxyServer) handleRequest(contentType, requestBody string, message
         
         // Wait for the requested number of seconds, then try again
         secondsToWait := int(math.Ceil(infuraError.Error.Data.Rate.BackoffSeconds))
-        log.Printf("Infura rate limit hit, waiting %d seconds... (Attempt %d of %d)\n", secondsToWait, recursionCount + 1, HandleRequestRecursionLimit)
+        httpLog.Warnf("Infura rate limit hit, waiting %d seconds... (Attempt %d of %d)", secondsToWait, recursionCount + 1, HandleRequestRecursionLimit)
         time.Sleep(time.Duration(secondsToWait) * time.Second)
         return p.handleRequest(contentType, requestBody, messageId, recursionCount + 1)
     } else if p.ProviderType == "pocket" && response.StatusCode == 502 {
-        log.Printf("Pocket returned a 502 gateway error, trying again... (Attempt %d of %d)\n", recursionCount + 1, HandleRequestRecursionLimit)
+        httpLog.Warnf("Pocket returned a 502 gateway error, trying again... (Attempt %d of %d)", recursionCount + 1, HandleRequestRecursionLimit)
         return p.handleRequest(contentType, requestBody, messageId, recursionCount + 1)
     }
     
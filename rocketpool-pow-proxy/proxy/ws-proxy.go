@@ -2,17 +2,22 @@ package proxy
 
 import (
 	"fmt"
-	"log"
 	"net/http"
 	"sync"
 
 	"github.com/gorilla/websocket"
+
+	"github.com/rocket-pool/smartnode/shared/utils/log"
 )
 
 // Config
 const InfuraWsURL = "wss://%s.infura.io/ws/v3/%s"
 
 
+// Logger
+var wsLog = log.NewLogger("ws-proxy", 0)
+
+
 // Proxy server
 type WsProxyServer struct {
     Port string
@@ -43,7 +48,7 @@ func NewWsProxyServer(port string, providerUrl string, network string, projectId
 func (p *WsProxyServer) Start() error {
 
     // Log
-    log.Printf("Proxy server listening on port %s\n", p.Port)
+    wsLog.Infof("Proxy server listening on port %s", p.Port)
 
     // Listen on RPC port
     return http.ListenAndServe(":" + p.Port, p)
@@ -60,7 +65,7 @@ func (p *WsProxyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
     // Establish a websocket with the requester
     eth2Connection, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
-        log.Println(fmt.Errorf("Error upgrading websocket: %w", err))
+        wsLog.Error(fmt.Errorf("Error upgrading websocket: %w", err))
         _, _ = fmt.Fprintln(w, fmt.Errorf("Error upgrading websocket: %w", err))
 		return
 	}
@@ -71,7 +76,7 @@ func (p *WsProxyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
     // Connect to Infura
     infuraConnection, _, err := websocket.DefaultDialer.Dial(p.ProviderUrl, nil)
     if err != nil {
-        log.Println(fmt.Errorf("Error connecting to remote websocket: %w", err))
+        wsLog.Error(fmt.Errorf("Error connecting to remote websocket: %w", err))
         _, _ = fmt.Fprintln(w, fmt.Errorf("Error connecting to remote websocket: %w", err))
 	}
 	defer func() {
@@ -88,19 +93,19 @@ func (p *WsProxyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
             // Read from eth2
             mt, message, err := eth2Connection.ReadMessage()
 		    if err != nil {
-                log.Println(fmt.Errorf("Error reading from eth2: %w", err))
+                wsLog.Error(fmt.Errorf("Error reading from eth2: %w", err))
                 _, _ = fmt.Fprintln(w, fmt.Errorf("Error reading from eth2: %w", err))
 			    break
 		    }
 
 		    // Log it if in verbose mode
 		    if p.Verbose {
-		    	fmt.Printf("< %d %s\n", mt, message)
+		    	wsLog.Debugf("< %d %s", mt, message)
 			}
 
             // Send it to the remote server
             if err = infuraConnection.WriteMessage(mt, message); err != nil {
-                log.Println(fmt.Errorf("Error writing to remote websocket: %w", err))
+                wsLog.Error(fmt.Errorf("Error writing to remote websocket: %w", err))
                 _, _ = fmt.Fprintln(w, fmt.Errorf("Error writing to remote websocket: %w", err))
 			    break
 		    }
@@ -115,19 +120,19 @@ func (p *WsProxyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
             // Read from the remote server
             mt, message, err := infuraConnection.ReadMessage()
 		    if err != nil {
-                log.Println(fmt.Errorf("Error reading from remote websocket: %w", err))
+                wsLog.Error(fmt.Errorf("Error reading from remote websocket: %w", err))
                 _, _ = fmt.Fprintln(w, fmt.Errorf("Error reading from remote websocket: %w", err))
 			    break
 		    }
 
 			// Log it if in verbose mode
 			if p.Verbose {
-				fmt.Printf("> %d %s\n", mt, message)
+				wsLog.Debugf("> %d %s", mt, message)
 			}
 
 			// Send it to eth2
             if err = eth2Connection.WriteMessage(mt, message); err != nil {
-                log.Println(fmt.Errorf("Error writing to eth2: %w", err))
+                wsLog.Error(fmt.Errorf("Error writing to eth2: %w", err))
                 _, _ = fmt.Fprintln(w, fmt.Errorf("Error writing to eth2: %w", err))
 			    break
 		    }
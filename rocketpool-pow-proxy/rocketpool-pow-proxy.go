@@ -1,7 +1,6 @@
 package main
 
 import (
-	"log"
 	"os"
 	"sync"
 
@@ -9,6 +8,7 @@ import (
 
 	"github.com/rocket-pool/smartnode/rocketpool-pow-proxy/proxy"
 	"github.com/rocket-pool/smartnode/shared"
+	"github.com/rocket-pool/smartnode/shared/utils/log"
 )
 
 // Run
@@ -80,13 +80,54 @@ func main() {
         },
         cli.BoolFlag{
             Name:  "verbose, V",
-            Usage: "Enables logging of all incoming and outgoing proxied data",
+            Usage: "Enables logging of all incoming and outgoing proxied data (implies the debug log level)",
+        },
+        cli.StringFlag{
+            Name:  "logLevel",
+            Usage: "Minimum log `level`: debug, info, warn or error",
+            Value: "info",
+        },
+        cli.StringFlag{
+            Name:  "logFormat",
+            Usage: "Log `format`: text, json or logfmt",
+            Value: "text",
+        },
+        cli.StringFlag{
+            Name:  "logPath",
+            Usage: "Folder to write rotated log files to instead of stderr",
+            Value: "",
+        },
+        cli.Uint64Flag{
+            Name:  "logMaxSize",
+            Usage: "Maximum size of a log file in MB before it is rotated",
+            Value: log.DefaultMaxSize,
+        },
+        cli.Uint64Flag{
+            Name:  "logMaxBackups",
+            Usage: "Number of rotated log files to keep",
+            Value: log.DefaultMaxBackups,
         },
     }
 
     // Set application action
     app.Action = func(c *cli.Context) error {
 
+        // Configure logging
+        logLevel := c.GlobalString("logLevel")
+        if c.GlobalBool("verbose") {
+            logLevel = "debug"
+        }
+        if err := log.Configure("pow-proxy", log.Options{
+            Level: logLevel,
+            Format: c.GlobalString("logFormat"),
+            Path: c.GlobalString("logPath"),
+            MaxSize: c.GlobalUint64("logMaxSize"),
+            MaxBackups: c.GlobalUint64("logMaxBackups"),
+        }); err != nil {
+            return err
+        }
+        logger := log.NewLogger("pow-proxy", 0)
+
         // We need a wait group since we have 2 HTTP listeners
         wg := new(sync.WaitGroup)
         wg.Add(2)
@@ -96,8 +137,8 @@ func main() {
             proxyServer := proxy.NewHttpProxyServer(c.GlobalString("httpPort"), c.GlobalString("httpProviderUrl"), c.GlobalString("network"), c.GlobalString("projectId"), c.GlobalString("providerType"), c.GlobalBool("verbose"))
             err := proxyServer.Start()
             if err != nil {
-                log.Fatalf("Could not start HTTP proxy server %v", err)
-                return
+                logger.Errorf("Could not start HTTP proxy server %v", err)
+                os.Exit(1)
             }
             wg.Done()
         }()
@@ -108,11 +149,11 @@ func main() {
                 proxyServer := proxy.NewWsProxyServer(c.GlobalString("wsPort"), c.GlobalString("wsProviderUrl"), c.GlobalString("network"), c.GlobalString("projectId"), c.GlobalBool("verbose"))
                 err := proxyServer.Start()
                 if err != nil {
-                    log.Fatalf("Could not start websocket proxy server %v", err)
-                    return
+                    logger.Errorf("Could not start websocket proxy server %v", err)
+                    os.Exit(1)
                 }
             } else {
-                log.Println("No websocket URL provided, running in HTTP-only mode.")
+                logger.Info("No websocket URL provided, running in HTTP-only mode.")
             }
             wg.Done()
         }()
@@ -125,7 +166,8 @@ func main() {
 
     // Run application
     if err := app.Run(os.Args); err != nil {
-        log.Fatal(err)
+        log.NewLogger("pow-proxy", 0).Error(err)
+        os.Exit(1)
     }
 
 }
@@ -18,6 +18,7 @@ import (
 	apitypes "github.com/rocket-pool/smartnode/shared/types/api"
 	"github.com/rocket-pool/smartnode/shared/utils/api"
 	cliutils "github.com/rocket-pool/smartnode/shared/utils/cli"
+	"github.com/rocket-pool/smartnode/shared/utils/log"
 )
 
 // Waits for an auction transaction
@@ -25,10 +26,20 @@ func waitForTransaction(c *cli.Context, hash common.Hash) (*apitypes.APIResponse
     
     rp, err := services.GetRocketPool(c)
     if err != nil { return nil, err }
+    txLog, err := services.GetTxLog(c)
+    if err != nil { return nil, err }
 
     // Response
     response := apitypes.APIResponse{}
-    _, err = utils.WaitForTransaction(rp.Client, hash)
+    if err := txLog.RecordSentTransactions(rp.Client); err != nil {
+        log.NewLogger("api", 0).WithTx(hash).Warnf("Could not record sent transaction: %s", err.Error())
+    }
+    receipt, err := utils.WaitForTransaction(rp.Client, hash)
+    if receipt != nil {
+        if err := txLog.AddReceipt(rp.Client, receipt); err != nil {
+            log.NewLogger("api", 0).WithTx(hash).Warnf("Could not record transaction result: %s", err.Error())
+        }
+    }
     if err != nil {
         return nil, err
     }
@@ -55,6 +66,28 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
         return err
     }
 
+    // Configure logging; invalid settings fall back to the defaults so responses are still printed
+    command.Before = func(c *cli.Context) error {
+        _ = services.ConfigureLogging(c, "api")
+        return nil
+    }
+
+    // Record the transactions sent by the command once it has completed
+    command.After = func(c *cli.Context) error {
+        txLog, err := services.GetTxLog(c)
+        if err != nil || !txLog.HasSignedTransactions() {
+            return nil
+        }
+        ec, err := services.GetEthClient(c)
+        if err == nil {
+            err = txLog.RecordSentTransactions(ec)
+        }
+        if err != nil {
+            log.NewLogger("api", 0).Warnf("Could not record sent transactions: %s", err.Error())
+        }
+        return nil
+    }
+
     // Register subcommands
      auction.RegisterSubcommands(&command, "auction",  []string{"a"})
       faucet.RegisterSubcommands(&command, "faucet",   []string{"f"})
@@ -38,6 +38,10 @@ func ExportValidators(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	mc, err := services.GetMultiCaller(c)
+	if err != nil {
+		return err
+	}
 
 	// Data
 	var wg1 errgroup.Group
@@ -49,7 +53,7 @@ func ExportValidators(c *cli.Context) error {
 	// Get minipool addresses
 	wg1.Go(func() error {
 		var err error
-		addresses, err = minipool.GetMinipoolAddresses(rpl, opts)
+		addresses, err = rp.GetMinipoolAddresses(rpl, mc, opts)
 		return err
 	})
 
@@ -88,7 +92,7 @@ func ExportValidators(c *cli.Context) error {
 	}
 
 	// Get minipool validator statuses
-	validators, err := rp.GetMinipoolValidators(rpl, bc, addresses, opts, &beacon.ValidatorStatusOptions{Epoch: blockEpoch})
+	validators, err := rp.GetMinipoolValidators(rpl, mc, bc, addresses, opts, &beacon.ValidatorStatusOptions{Epoch: blockEpoch})
 	if err != nil {
 		return err
 	}
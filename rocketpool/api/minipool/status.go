@@ -21,6 +21,10 @@ func getStatus(c *cli.Context) (*api.MinipoolStatusResponse, error) {
     if err != nil { return nil, err }
     bc, err := services.GetBeaconClient(c)
     if err != nil { return nil, err }
+    mc, err := services.GetMultiCaller(c)
+    if err != nil { return nil, err }
+    depositIndexer, err := services.GetDepositIndexer(c)
+    if err != nil { return nil, err }
 
     // Response
     response := api.MinipoolStatusResponse{}
@@ -30,10 +34,11 @@ func getStatus(c *cli.Context) (*api.MinipoolStatusResponse, error) {
     if err != nil {
         return nil, err
     }
-    details, err := getNodeMinipoolDetails(rp, bc, nodeAccount.Address)
+    details, err := getNodeMinipoolDetails(rp, mc, bc, nodeAccount.Address)
     if err != nil {
         return nil, err
     }
+    checkMinipoolDeposits(rp, bc, depositIndexer, details)
     response.Minipools = details
 
     delegate, err := rp.GetContract("rocketMinipoolDelegate")
@@ -2,28 +2,24 @@ package minipool
 
 import (
 	"bytes"
-	"context"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/rocket-pool/rocketpool-go/minipool"
 	"github.com/rocket-pool/rocketpool-go/rocketpool"
-	"github.com/rocket-pool/rocketpool-go/tokens"
 	"github.com/rocket-pool/rocketpool-go/types"
 	"github.com/rocket-pool/rocketpool-go/utils/eth"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/rocket-pool/smartnode/shared/services/beacon"
+	"github.com/rocket-pool/smartnode/shared/services/deposits"
+	"github.com/rocket-pool/smartnode/shared/services/multicall"
 	"github.com/rocket-pool/smartnode/shared/types/api"
-	"github.com/rocket-pool/smartnode/shared/utils/eth2"
 	rputils "github.com/rocket-pool/smartnode/shared/utils/rp"
 )
 
-// Settings
-const MinipoolDetailsBatchSize = 10
-
-
 // Validate that a minipool belongs to a node
 func validateMinipoolOwner(mp *minipool.Minipool, nodeAddress common.Address) error {
     owner, err := mp.GetNodeAddress(nil)
@@ -38,26 +34,17 @@ func validateMinipoolOwner(mp *minipool.Minipool, nodeAddress common.Address) er
 
 
 // Get all node minipool details
-func getNodeMinipoolDetails(rp *rocketpool.RocketPool, bc beacon.Client, nodeAddress common.Address) ([]api.MinipoolDetails, error) {
+func getNodeMinipoolDetails(rp *rocketpool.RocketPool, mc *multicall.MultiCaller, bc beacon.Client, nodeAddress common.Address) ([]api.MinipoolDetails, error) {
 
     // Data
     var wg1 errgroup.Group
     var addresses []common.Address
-    var eth2Config beacon.Eth2Config
     var currentEpoch uint64
-    var currentBlock uint64
 
     // Get minipool addresses
     wg1.Go(func() error {
         var err error
-        addresses, err = minipool.GetNodeMinipoolAddresses(rp, nodeAddress, nil)
-        return err
-    })
-
-    // Get eth2 config
-    wg1.Go(func() error {
-        var err error
-        eth2Config, err = bc.GetEth2Config()
+        addresses, err = rputils.GetNodeMinipoolAddresses(rp, mc, nodeAddress, nil)
         return err
     })
 
@@ -70,51 +57,45 @@ func getNodeMinipoolDetails(rp *rocketpool.RocketPool, bc beacon.Client, nodeAdd
         return err
     })
 
-    // Get current block
-    wg1.Go(func() error {
-        header, err := rp.Client.HeaderByNumber(context.Background(), nil)
-        if err == nil {
-            currentBlock = header.Number.Uint64()
-        }
-        return err
-    })
-
     // Wait for data
     if err := wg1.Wait(); err != nil {
         return []api.MinipoolDetails{}, err
     }
 
     // Get minipool validator statuses
-    validators, err := rputils.GetMinipoolValidators(rp, bc, addresses, nil, nil)
+    validators, err := rputils.GetMinipoolValidators(rp, mc, bc, addresses, nil, nil)
     if err != nil {
         return []api.MinipoolDetails{}, err
     }
 
-    // Load details in batches
-    details := make([]api.MinipoolDetails, len(addresses))
-    for bsi := 0; bsi < len(addresses); bsi += MinipoolDetailsBatchSize {
-
-        // Get batch start & end index
-        msi := bsi
-        mei := bsi + MinipoolDetailsBatchSize
-        if mei > len(addresses) { mei = len(addresses) }
-
-        // Load details
-        var wg errgroup.Group
-        for mi := msi; mi < mei; mi++ {
-            mi := mi
-            wg.Go(func() error {
-                address := addresses[mi]
-                validator := validators[address]
-                mpDetails, err := getMinipoolDetails(rp, address, validator, eth2Config, currentEpoch, currentBlock)
-                if err == nil { details[mi] = mpDetails }
-                return err
-            })
+    // Get minipool contracts
+    minipools, err := rputils.GetMinipools(rp, addresses)
+    if err != nil {
+        return []api.MinipoolDetails{}, err
+    }
+
+    // Load details
+    details, err := getMinipoolDetails(rp, mc, minipools)
+    if err != nil {
+        return []api.MinipoolDetails{}, err
+    }
+
+    // Get validator details for staking minipools; their node shares are calculated in a second batch
+    shareBatch := mc.NewBatch()
+    for mi, mp := range minipools {
+        if details[mi].Status.Status != types.Staking {
+            continue
         }
-        if err := wg.Wait(); err != nil {
-            return []api.MinipoolDetails{}, err
+        validatorDetails, calculateNodeShare := getMinipoolValidatorDetails(details[mi], validators[mp.Address], currentEpoch)
+        details[mi].Validator = validatorDetails
+        if calculateNodeShare {
+            if err := shareBatch.AddCall(mp.Contract, &details[mi].Validator.NodeBalance, "calculateNodeShare", validatorDetails.Balance); err != nil {
+                return []api.MinipoolDetails{}, err
+            }
         }
-
+    }
+    if err := shareBatch.Execute(nil); err != nil {
+        return []api.MinipoolDetails{}, err
     }
 
     // Return
@@ -123,109 +104,165 @@ func getNodeMinipoolDetails(rp *rocketpool.RocketPool, bc beacon.Client, nodeAdd
 }
 
 
-// Get a minipool's details
-func getMinipoolDetails(rp *rocketpool.RocketPool, minipoolAddress common.Address, validator beacon.ValidatorStatus, eth2Config beacon.Eth2Config, currentEpoch, currentBlock uint64) (api.MinipoolDetails, error) {
+// Check the beacon deposits of prelaunch minipools' validators against their withdrawal credentials
+// Failures are recorded on each check rather than returned so that they do not prevent the status from loading
+func checkMinipoolDeposits(rp *rocketpool.RocketPool, bc beacon.Client, depositIndexer *deposits.Indexer, details []api.MinipoolDetails) {
 
-    // Create minipool
-    mp, err := minipool.NewMinipool(rp, minipoolAddress)
-    if err != nil {
-        return api.MinipoolDetails{}, err
+    // Get prelaunch minipool pubkeys
+    pubkeys := map[types.ValidatorPubkey]bool{}
+    for mi := range details {
+        if details[mi].Status.Status == types.Prelaunch {
+            details[mi].DepositCheck = &api.MinipoolDepositCheck{}
+            pubkeys[details[mi].ValidatorPubkey] = true
+        }
+    }
+    if len(pubkeys) == 0 {
+        return
     }
 
-    // Data
-    var wg errgroup.Group
-    details := api.MinipoolDetails{Address: minipoolAddress}
+    // Get the deposits & signing domain
+    depositMap, err := depositIndexer.UpdateAndGetDeposits(pubkeys)
+    var depositDomain []byte
+    if err == nil {
+        depositDomain, err = deposits.GetDepositDomain(bc)
+    }
 
-    // Load data
-    wg.Go(func() error {
-        var err error
-        details.ValidatorPubkey, err = minipool.GetMinipoolPubkey(rp, minipoolAddress, nil)
-        return err
-    })
-    wg.Go(func() error {
-        var err error
-        details.Status, err = mp.GetStatusDetails(nil)
-        return err
-    })
-    wg.Go(func() error {
-        var err error
-        details.DepositType, err = mp.GetDepositType(nil)
-        return err
-    })
-    wg.Go(func() error {
-        var err error
-        details.Node, err = mp.GetNodeDetails(nil)
-        return err
-    })
-    wg.Go(func() error {
-        var err error
-        details.User, err = mp.GetUserDetails(nil)
-        return err
-    })
-    wg.Go(func() error {
-        var err error
-        details.Balances, err = tokens.GetBalances(rp, minipoolAddress, nil)
-        return err
-    })
-    wg.Go(func() error {
-        var err error
-        details.UseLatestDelegate, err = mp.GetUseLatestDelegate(nil)
-        return err
-    })
-    wg.Go(func() error {
-        var err error
-        details.Delegate, err = mp.GetDelegate(nil)
-        return err
-    })
-    wg.Go(func() error {
-        var err error
-        details.PreviousDelegate, err = mp.GetPreviousDelegate(nil)
-        return err
-    })
-    wg.Go(func() error {
-        var err error
-        details.EffectiveDelegate, err = mp.GetEffectiveDelegate(nil)
-        return err
-    })
-    wg.Go(func() error {
-        var err error
-        details.Finalised, err = mp.GetFinalised(nil)
-        return err
-    })
+    // Check each minipool's deposits
+    for mi := range details {
+        check := details[mi].DepositCheck
+        if check == nil {
+            continue
+        }
+        if err != nil {
+            check.Error = err.Error()
+            continue
+        }
+        expectedCreds, err := minipool.GetMinipoolWithdrawalCredentials(rp, details[mi].Address, nil)
+        if err != nil {
+            check.Error = err.Error()
+            continue
+        }
+        check.ExpectedWithdrawalCredentials = expectedCreds
+        validatorDeposits := depositMap[details[mi].ValidatorPubkey]
+        check.DepositCount = len(validatorDeposits)
+        if di := deposits.GetFirstValidDeposit(validatorDeposits, depositDomain); di >= 0 {
+            deposit := validatorDeposits[di]
+            check.ValidDepositFound = true
+            check.TxHash = deposit.TxHash
+            check.BlockNumber = deposit.BlockNumber
+            check.WithdrawalCredentials = deposit.WithdrawalCredentials
+            check.WithdrawalCredentialsMatch = (deposit.WithdrawalCredentials == expectedCreds)
+        }
+    }
 
-    // Wait for data
-    if err := wg.Wait(); err != nil {
-        return api.MinipoolDetails{}, err
+}
+
+
+// Get minipool details
+func getMinipoolDetails(rp *rocketpool.RocketPool, mc *multicall.MultiCaller, minipools []*minipool.Minipool) ([]api.MinipoolDetails, error) {
+
+    // Get token contracts
+    rocketTokenRETH, err := rp.GetContract("rocketTokenRETH")
+    if err != nil {
+        return []api.MinipoolDetails{}, err
+    }
+    rocketTokenRPL, err := rp.GetContract("rocketTokenRPL")
+    if err != nil {
+        return []api.MinipoolDetails{}, err
+    }
+    rocketTokenRPLFixedSupply, err := rp.GetContract("rocketTokenRPLFixedSupply")
+    if err != nil {
+        return []api.MinipoolDetails{}, err
+    }
+    rocketMinipoolManager, err := rp.GetContract("rocketMinipoolManager")
+    if err != nil {
+        return []api.MinipoolDetails{}, err
     }
 
-    // Get validator details if staking
-    if details.Status.Status == types.Staking {
-        validatorDetails, err := getMinipoolValidatorDetails(rp, details, validator, eth2Config, currentEpoch)
-        if err != nil {
-            return api.MinipoolDetails{}, err
+    // Raw call results
+    type minipoolCallResults struct {
+        status uint8
+        statusBlock *big.Int
+        statusTime *big.Int
+        depositType uint8
+        nodeFee *big.Int
+        userDepositAssignedTime *big.Int
+    }
+
+    // Load data
+    details := make([]api.MinipoolDetails, len(minipools))
+    results := make([]minipoolCallResults, len(minipools))
+    batch := mc.NewBatch()
+    for mi, mp := range minipools {
+        d := &details[mi]
+        r := &results[mi]
+        d.Address = mp.Address
+        calls := []struct {
+            contract *rocketpool.Contract
+            output interface{}
+            method string
+            params []interface{}
+        }{
+            {rocketMinipoolManager, &d.ValidatorPubkey, "getMinipoolPubkey", []interface{}{mp.Address}},
+            {mp.Contract, &r.status, "getStatus", nil},
+            {mp.Contract, &r.statusBlock, "getStatusBlock", nil},
+            {mp.Contract, &r.statusTime, "getStatusTime", nil},
+            {mp.Contract, &r.depositType, "getDepositType", nil},
+            {mp.Contract, &d.Node.Address, "getNodeAddress", nil},
+            {mp.Contract, &r.nodeFee, "getNodeFee", nil},
+            {mp.Contract, &d.Node.DepositBalance, "getNodeDepositBalance", nil},
+            {mp.Contract, &d.Node.RefundBalance, "getNodeRefundBalance", nil},
+            {mp.Contract, &d.Node.DepositAssigned, "getNodeDepositAssigned", nil},
+            {mp.Contract, &d.User.DepositBalance, "getUserDepositBalance", nil},
+            {mp.Contract, &d.User.DepositAssigned, "getUserDepositAssigned", nil},
+            {mp.Contract, &r.userDepositAssignedTime, "getUserDepositAssignedTime", nil},
+            {mp.Contract, &d.UseLatestDelegate, "getUseLatestDelegate", nil},
+            {mp.Contract, &d.Delegate, "getDelegate", nil},
+            {mp.Contract, &d.PreviousDelegate, "getPreviousDelegate", nil},
+            {mp.Contract, &d.EffectiveDelegate, "getEffectiveDelegate", nil},
+            {mp.Contract, &d.Finalised, "getFinalised", nil},
+            {rocketTokenRETH, &d.Balances.RETH, "balanceOf", []interface{}{mp.Address}},
+            {rocketTokenRPL, &d.Balances.RPL, "balanceOf", []interface{}{mp.Address}},
+            {rocketTokenRPLFixedSupply, &d.Balances.FixedSupplyRPL, "balanceOf", []interface{}{mp.Address}},
+        }
+        for _, call := range calls {
+            if err := batch.AddCall(call.contract, call.output, call.method, call.params...); err != nil {
+                return []api.MinipoolDetails{}, err
+            }
+        }
+        if err := batch.AddBalanceCall(mp.Address, &d.Balances.ETH); err != nil {
+            return []api.MinipoolDetails{}, err
         }
-        details.Validator = validatorDetails
+    }
+    if err := batch.Execute(nil); err != nil {
+        return []api.MinipoolDetails{}, err
     }
 
     // Update & return
-    details.RefundAvailable = (details.Node.RefundBalance.Cmp(big.NewInt(0)) > 0)
-    details.CloseAvailable = (details.Status.Status == types.Dissolved)
-    if details.Status.Status == types.Withdrawable {
-        details.WithdrawalAvailable = true
+    for mi := range details {
+        d := &details[mi]
+        r := results[mi]
+        d.Status = minipool.StatusDetails{
+            Status: types.MinipoolStatus(r.status),
+            StatusBlock: r.statusBlock.Uint64(),
+            StatusTime: time.Unix(r.statusTime.Int64(), 0),
+        }
+        d.DepositType = types.MinipoolDeposit(r.depositType)
+        d.Node.Fee = eth.WeiToEth(r.nodeFee)
+        d.User.DepositAssignedTime = time.Unix(r.userDepositAssignedTime.Int64(), 0)
+        d.RefundAvailable = (d.Node.RefundBalance.Cmp(big.NewInt(0)) > 0)
+        d.CloseAvailable = (d.Status.Status == types.Dissolved)
+        if d.Status.Status == types.Withdrawable {
+            d.WithdrawalAvailable = true
+        }
     }
     return details, nil
 
 }
 
 
-// Get a minipool's validator details
-func getMinipoolValidatorDetails(rp *rocketpool.RocketPool, minipoolDetails api.MinipoolDetails, validator beacon.ValidatorStatus, eth2Config beacon.Eth2Config, currentEpoch uint64) (api.ValidatorDetails, error) {
-
-    // Create minipool
-    mp, err := minipool.NewMinipool(rp, minipoolDetails.Address)
-    if err != nil {
-        return api.ValidatorDetails{}, err
-    }
+// Get a minipool's validator details, and whether its node share of the validator balance must be calculated
+func getMinipoolValidatorDetails(minipoolDetails api.MinipoolDetails, validator beacon.ValidatorStatus, currentEpoch uint64) (api.ValidatorDetails, bool) {
 
     // Validator details
     details := api.ValidatorDetails{}
@@ -245,30 +282,13 @@ func getMinipoolValidatorDetails(rp *rocketpool.RocketPool, minipoolDetails api.
         details.Balance.Add(minipoolDetails.Node.DepositBalance, minipoolDetails.User.DepositBalance)
         details.NodeBalance = new(big.Int)
         details.NodeBalance.Set(minipoolDetails.Node.DepositBalance)
-        return details, nil
+        return details, false
     }
 
-    // Set validator balance
+    // Set validator balance; the expected node balance is calculated from it by the minipool contract
     details.Balance = eth.GweiToWei(float64(validator.Balance))
 
-    // Get start epoch for expected node balance calculation
-    startEpoch := eth2.EpochAt(eth2Config, uint64(minipoolDetails.User.DepositAssignedTime.Unix()))
-    if startEpoch < validator.ActivationEpoch {
-        startEpoch = validator.ActivationEpoch
-    } else if startEpoch > currentEpoch {
-        startEpoch = currentEpoch
-    }
-
-    // Get expected node balance
-    blockBalance := eth.GweiToWei(float64(validator.Balance))
-    nodeBalance, err := mp.CalculateNodeShare(blockBalance, nil)
-    if err != nil {
-        return api.ValidatorDetails{}, err
-    }
-    details.NodeBalance = nodeBalance
-
     // Return
-    return details, nil
+    return details, true
 
 }
-
@@ -1,6 +1,8 @@
 package node
 
 import (
+	"time"
+
 	"github.com/urfave/cli"
 
 	"github.com/rocket-pool/smartnode/shared/utils/api"
@@ -615,6 +617,42 @@ func RegisterSubcommands(command *cli.Command, name string, aliases []string) {
                 },
             },
 
+            cli.Command{
+                Name:      "tx-history",
+                Usage:     "Get the history of transactions sent by the node",
+                UsageText: "rocketpool api node tx-history",
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
+                    // Run
+                    api.PrintResponse(getTxHistory(c))
+                    return nil
+
+                },
+            },
+
+            cli.Command{
+                Name:      "report",
+                Usage:     "Get a report of the node's income and costs over a period",
+                UsageText: "rocketpool api node report from-time to-time",
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 2); err != nil { return err }
+                    fromTime, err := cliutils.ValidateUint("from time", c.Args().Get(0))
+                    if err != nil { return err }
+                    toTime, err := cliutils.ValidateUint("to time", c.Args().Get(1))
+                    if err != nil { return err }
+
+                    // Run
+                    api.PrintResponse(getReport(c, time.Unix(int64(fromTime), 0), time.Unix(int64(toTime), 0)))
+                    return nil
+
+                },
+            },
+
             cli.Command{
                 Name:      "deposit-contract-info",
                 Usage:     "Get information about the deposit contract specified by Rocket Pool and the Beacon Chain client",
@@ -631,6 +669,40 @@ func RegisterSubcommands(command *cli.Command, name string, aliases []string) {
                 },
             },
 
+            cli.Command{
+                Name:      "doctor",
+                Usage:     "Get the node's validator keystore and beacon clock details for the service health check",
+                UsageText: "rocketpool api node doctor",
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
+                    // Run
+                    api.PrintResponse(getDoctorDetails(c))
+                    return nil
+
+                },
+            },
+
+            cli.Command{
+                Name:      "validator-liveness",
+                Usage:     "Check whether any of a set of validators have been active on the beacon chain in recent epochs",
+                UsageText: "rocketpool api node validator-liveness pubkeys",
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
+                    pubkeys, err := cliutils.ValidatePubkeys("pubkeys", c.Args().Get(0))
+                    if err != nil { return err }
+
+                    // Run
+                    api.PrintResponse(getValidatorLiveness(c, pubkeys))
+                    return nil
+
+                },
+            },
+
         },
     })
 }
@@ -260,6 +260,8 @@ func nodeDeposit(c *cli.Context, amountWei *big.Int, minNodeFee float64, salt *b
     if err != nil { return nil, err }
     bc, err := services.GetBeaconClient(c)
     if err != nil { return nil, err }
+    depositIndexer, err := services.GetDepositIndexer(c)
+    if err != nil { return nil, err }
 
     // Get eth2 config
     eth2Config, err := bc.GetEth2Config()
@@ -358,6 +360,20 @@ func nodeDeposit(c *cli.Context, amountWei *big.Int, minNodeFee float64, salt *b
             "***************\n", minipoolAddress.Hex(), pubKey.Hex(), status.Index);
     }
 
+    // Make sure the deposit contract doesn't already have a deposit for this pubkey which the beacon chain hasn't processed yet
+    existingDeposits, err := depositIndexer.UpdateAndGetDeposits(map[rptypes.ValidatorPubkey]bool{pubKey: true})
+    if err != nil {
+        return nil, fmt.Errorf("Error checking for existing deposits: %w\nYour funds have not been deposited for your own safety.", err)
+    }
+    if len(existingDeposits[pubKey]) > 0 {
+        return nil, fmt.Errorf("**** ALERT ****\n" +
+            "Your minipool %s has the following as a validator pubkey:\n\t%s\n" +
+            "This key already has %d deposit(s) in the Beacon deposit contract, the first in transaction %s!\n" +
+            "Rocket Pool will not allow you to deposit this validator for your own safety so your deposit is not front-run.\n" +
+            "PLEASE REPORT THIS TO THE ROCKET POOL DEVELOPERS.\n" +
+            "***************\n", minipoolAddress.Hex(), pubKey.Hex(), len(existingDeposits[pubKey]), existingDeposits[pubKey][0].TxHash.Hex());
+    }
+
     // Do a final sanity check
     err = validateDepositInfo(eth2Config, uint64(validator.DepositAmount), pubKey, withdrawalCredentials, signature)
     if err != nil {
@@ -0,0 +1,73 @@
+package node
+
+import (
+	"time"
+
+	"github.com/rocket-pool/rocketpool-go/minipool"
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services"
+	"github.com/rocket-pool/smartnode/shared/types/api"
+)
+
+
+func getDoctorDetails(c *cli.Context) (*api.NodeDoctorResponse, error) {
+
+    // Get services
+    if err := services.RequireNodeWallet(c); err != nil { return nil, err }
+    if err := services.RequireRocketStorage(c); err != nil { return nil, err }
+    cfg, err := services.GetConfig(c)
+    if err != nil { return nil, err }
+    w, err := services.GetWallet(c)
+    if err != nil { return nil, err }
+    rp, err := services.GetRocketPool(c)
+    if err != nil { return nil, err }
+    bc, err := services.GetBeaconClient(c)
+    if err != nil { return nil, err }
+
+    // Response
+    response := api.NodeDoctorResponse{
+        ValidatorKeystore: cfg.Chains.Eth2.Client.Selected,
+        ValidatorKeys: []api.ValidatorKeyDetails{},
+    }
+
+    // Get node account
+    nodeAccount, err := w.GetNodeAccount()
+    if err != nil {
+        return nil, err
+    }
+
+    // Check the selected client's keystore for each staking minipool's validator key
+    pubkeys, err := minipool.GetNodeValidatingMinipoolPubkeys(rp, nodeAccount.Address, nil)
+    if err != nil {
+        return nil, err
+    }
+    for _, pubkey := range pubkeys {
+        stored, err := w.HasValidatorKey(response.ValidatorKeystore, pubkey)
+        if err != nil {
+            return nil, err
+        }
+        response.ValidatorKeys = append(response.ValidatorKeys, api.ValidatorKeyDetails{
+            Pubkey: pubkey,
+            Stored: stored,
+        })
+    }
+
+    // Get the beacon chain schedule & head for the clock check
+    eth2Config, err := bc.GetEth2Config()
+    if err != nil {
+        return nil, err
+    }
+    head, err := bc.GetBeaconHead()
+    if err != nil {
+        return nil, err
+    }
+    response.ClockTime = uint64(time.Now().Unix())
+    response.GenesisTime = eth2Config.GenesisTime
+    response.SecondsPerEpoch = eth2Config.SecondsPerEpoch
+    response.HeadEpoch = head.Epoch
+
+    // Return response
+    return &response, nil
+
+}
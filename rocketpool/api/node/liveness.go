@@ -0,0 +1,75 @@
+package node
+
+import (
+	rptypes "github.com/rocket-pool/rocketpool-go/types"
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services"
+	"github.com/rocket-pool/smartnode/shared/services/beacon"
+	"github.com/rocket-pool/smartnode/shared/types/api"
+)
+
+// The number of epochs to check validator balances over
+// Attestation rewards are applied at epoch boundaries, so an increase over this window indicates a validator is being run
+// The window spans several epochs so that a validator missing some attestations while live is still detected
+const LivenessEpochs = 4
+
+
+func getValidatorLiveness(c *cli.Context, pubkeys []rptypes.ValidatorPubkey) (*api.NodeValidatorLivenessResponse, error) {
+
+    // Get services
+    if err := services.RequireBeaconClientSynced(c); err != nil { return nil, err }
+    bc, err := services.GetBeaconClient(c)
+    if err != nil { return nil, err }
+
+    // Response
+    response := api.NodeValidatorLivenessResponse{
+        LiveValidators: []rptypes.ValidatorPubkey{},
+    }
+
+    // Get the time to wait after a validator stops before the window no longer covers its activity
+    // The current epoch is still in progress, so the window is extended by one epoch
+    eth2Config, err := bc.GetEth2Config()
+    if err != nil {
+        return nil, err
+    }
+    response.WindowEpochs = LivenessEpochs
+    response.WaitSeconds = (LivenessEpochs + 1) * eth2Config.SecondsPerEpoch
+
+    // Get the epochs to compare balances at
+    head, err := bc.GetBeaconHead()
+    if err != nil {
+        return nil, err
+    }
+    if head.Epoch < LivenessEpochs || len(pubkeys) == 0 {
+        return &response, nil
+    }
+    response.FromEpoch = head.Epoch - LivenessEpochs
+    response.ToEpoch = head.Epoch
+
+    // Get validator balances
+    fromStatuses, err := bc.GetValidatorStatuses(pubkeys, &beacon.ValidatorStatusOptions{Epoch: response.FromEpoch})
+    if err != nil {
+        return nil, err
+    }
+    toStatuses, err := bc.GetValidatorStatuses(pubkeys, &beacon.ValidatorStatusOptions{Epoch: response.ToEpoch})
+    if err != nil {
+        return nil, err
+    }
+
+    // Check for active validators with increasing balances
+    for _, pubkey := range pubkeys {
+        fromStatus, ok := fromStatuses[pubkey]
+        if !ok || !fromStatus.Exists { continue }
+        toStatus, ok := toStatuses[pubkey]
+        if !ok || !toStatus.Exists { continue }
+        if toStatus.ActivationEpoch > response.FromEpoch || toStatus.ExitEpoch <= response.ToEpoch { continue }
+        if toStatus.Balance > fromStatus.Balance {
+            response.LiveValidators = append(response.LiveValidators, pubkey)
+        }
+    }
+
+    // Return response
+    return &response, nil
+
+}
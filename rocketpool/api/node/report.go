@@ -0,0 +1,532 @@
+package node
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rocket-pool/rocketpool-go/minipool"
+	"github.com/rocket-pool/rocketpool-go/network"
+	"github.com/rocket-pool/rocketpool-go/rocketpool"
+	rptypes "github.com/rocket-pool/rocketpool-go/types"
+	"github.com/rocket-pool/rocketpool-go/utils/eth"
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services"
+	"github.com/rocket-pool/smartnode/shared/services/beacon"
+	"github.com/rocket-pool/smartnode/shared/types/api"
+	apiutils "github.com/rocket-pool/smartnode/shared/utils/api"
+	"github.com/rocket-pool/smartnode/shared/utils/eth1"
+	"github.com/rocket-pool/smartnode/shared/utils/eth2"
+)
+
+// Report entry types
+const (
+    ReportRplRewardsClaimed = "rpl-rewards-claimed"
+    ReportRplStaked = "rpl-staked"
+    ReportRplWithdrawn = "rpl-withdrawn"
+    ReportRplSlashed = "rpl-slashed"
+    ReportBeaconRewards = "beacon-rewards"
+    ReportCommission = "commission"
+    ReportRefund = "refund"
+    ReportGasCost = "gas-cost"
+)
+
+// The balance a validator starts with, in gwei
+const ValidatorDepositBalanceGwei = 32000000000
+
+
+// Report builder
+type reportBuilder struct {
+    rp *rocketpool.RocketPool
+    nodeAddress common.Address
+    fromBlock *big.Int
+    toBlock *big.Int
+    eventLogInterval *big.Int
+    rplPrices map[uint64]*big.Int
+}
+
+
+func getReport(c *cli.Context, from, to time.Time) (*api.NodeReportResponse, error) {
+
+    // Get services
+    if err := services.RequireNodeRegistered(c); err != nil { return nil, err }
+    cfg, err := services.GetConfig(c)
+    if err != nil { return nil, err }
+    w, err := services.GetWallet(c)
+    if err != nil { return nil, err }
+    rp, err := services.GetRocketPool(c)
+    if err != nil { return nil, err }
+    bc, err := services.GetBeaconClient(c)
+    if err != nil { return nil, err }
+    txLog, err := services.GetTxLog(c)
+    if err != nil { return nil, err }
+
+    // Response
+    response := api.NodeReportResponse{
+        From: from,
+        To: to,
+        Entries: []api.NodeReportEntry{},
+        Totals: make(map[string]api.NodeReportTotal),
+    }
+
+    // Get node account
+    nodeAccount, err := w.GetNodeAccount()
+    if err != nil {
+        return nil, err
+    }
+    response.AccountAddress = nodeAccount.Address
+
+    // Get the block range; events in the first block belong to the previous period
+    fromBlock, err := eth1.GetBlockAtTime(rp.Client, from)
+    if err != nil {
+        return nil, err
+    }
+    toBlock, err := eth1.GetBlockAtTime(rp.Client, to)
+    if err != nil {
+        return nil, err
+    }
+    response.FromBlock = fromBlock + 1
+    response.ToBlock = toBlock
+    if response.FromBlock > response.ToBlock {
+        return nil, fmt.Errorf("No blocks were mined between %s and %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+    }
+
+    // Get the epoch range, clamped to the finalized epoch
+    eth2Config, err := bc.GetEth2Config()
+    if err != nil {
+        return nil, err
+    }
+    beaconHead, err := bc.GetBeaconHead()
+    if err != nil {
+        return nil, err
+    }
+    response.FromEpoch = getReportEpoch(eth2Config, from)
+    response.ToEpoch = getReportEpoch(eth2Config, to)
+    if response.ToEpoch > beaconHead.FinalizedEpoch {
+        response.ToEpoch = beaconHead.FinalizedEpoch
+    }
+    if response.FromEpoch > response.ToEpoch {
+        response.FromEpoch = response.ToEpoch
+    }
+
+    // Get the event log interval
+    eventLogInterval, err := apiutils.GetEventLogInterval(cfg)
+    if err != nil {
+        return nil, err
+    }
+
+    // Initialize report builder
+    rb := &reportBuilder{
+        rp: rp,
+        nodeAddress: nodeAccount.Address,
+        fromBlock: new(big.Int).SetUint64(response.FromBlock),
+        toBlock: new(big.Int).SetUint64(response.ToBlock),
+        eventLogInterval: eventLogInterval,
+        rplPrices: make(map[uint64]*big.Int),
+    }
+
+    // Get RPL reward claims & stake changes
+    rplEvents := []struct{
+        contractName string
+        eventName string
+        nodeTopic int
+        entryType string
+    }{
+        {"rocketRewardsPool", "RPLTokensClaimed", 2, ReportRplRewardsClaimed},
+        {"rocketNodeStaking", "RPLStaked", 1, ReportRplStaked},
+        {"rocketNodeStaking", "RPLWithdrawn", 1, ReportRplWithdrawn},
+        {"rocketNodeStaking", "RPLSlashed", 1, ReportRplSlashed},
+    }
+    for _, event := range rplEvents {
+        entries, err := rb.getRplEventEntries(event.contractName, event.eventName, event.nodeTopic, event.entryType)
+        if err != nil {
+            return nil, err
+        }
+        response.Entries = append(response.Entries, entries...)
+    }
+
+    // Get node minipools at the end of the period
+    minipoolAddresses, err := minipool.GetNodeMinipoolAddresses(rp, nodeAccount.Address, &bind.CallOpts{BlockNumber: rb.toBlock})
+    if err != nil {
+        return nil, err
+    }
+
+    // Get refunds
+    refundEntries, err := rb.getRefundEntries(minipoolAddresses)
+    if err != nil {
+        return nil, err
+    }
+    response.Entries = append(response.Entries, refundEntries...)
+
+    // Get beacon rewards & commission
+    beaconEntries, err := rb.getBeaconEntries(bc, minipoolAddresses, response.FromEpoch, response.ToEpoch, to)
+    if err != nil {
+        return nil, err
+    }
+    response.Entries = append(response.Entries, beaconEntries...)
+
+    // Get gas costs from the transaction log
+    if err := txLog.ResolvePending(rp.Client); err != nil {
+        return nil, err
+    }
+    records, err := txLog.GetRecords()
+    if err != nil {
+        return nil, err
+    }
+    for _, record := range records {
+        if record.EffectiveGasPrice == nil || record.BlockNumber < response.FromBlock || record.BlockNumber > response.ToBlock {
+            continue
+        }
+        txHash := record.Hash
+        gasCost := new(big.Int).Mul(record.EffectiveGasPrice, new(big.Int).SetUint64(record.GasUsed))
+        response.Entries = append(response.Entries, api.NodeReportEntry{
+            Time: record.BlockTime,
+            Block: record.BlockNumber,
+            Type: ReportGasCost,
+            TxHash: &txHash,
+            EthAmount: gasCost,
+            RplAmount: big.NewInt(0),
+            EthValue: gasCost,
+        })
+    }
+
+    // Sort entries & get totals
+    response.Totals = sortReportEntries(response.Entries)
+
+    // Return response
+    return &response, nil
+
+}
+
+
+// Get report entries for node RPL events on a network contract
+func (rb *reportBuilder) getRplEventEntries(contractName, eventName string, nodeTopic int, entryType string) ([]api.NodeReportEntry, error) {
+
+    // Get contract
+    contract, err := rb.rp.GetContract(contractName)
+    if err != nil {
+        return []api.NodeReportEntry{}, err
+    }
+    event, exists := contract.ABI.Events[eventName]
+    if !exists {
+        return []api.NodeReportEntry{}, fmt.Errorf("Contract %s has no %s event", contractName, eventName)
+    }
+
+    // Get logs
+    topicFilter := make([][]common.Hash, nodeTopic + 1)
+    topicFilter[0] = []common.Hash{event.ID}
+    topicFilter[nodeTopic] = []common.Hash{common.BytesToHash(rb.nodeAddress.Bytes())}
+    logs, err := eth.FilterContractLogs(rb.rp, contractName, eth.FilterQuery{
+        FromBlock: new(big.Int).Set(rb.fromBlock),
+        ToBlock: new(big.Int).Set(rb.toBlock),
+        Topics: topicFilter,
+    }, rb.getEventLogInterval())
+    if err != nil {
+        return []api.NodeReportEntry{}, fmt.Errorf("Could not get %s events: %w", eventName, err)
+    }
+
+    // Create entries
+    entries := make([]api.NodeReportEntry, 0, len(logs))
+    for _, log := range logs {
+        values, err := rb.unpackLog(contract, eventName, log)
+        if err != nil {
+            return []api.NodeReportEntry{}, err
+        }
+        amount, ok := values["amount"].(*big.Int)
+        if !ok {
+            return []api.NodeReportEntry{}, fmt.Errorf("Could not decode %s event amount", eventName)
+        }
+        rplPrice, err := rb.getRplPrice(log.BlockNumber)
+        if err != nil {
+            return []api.NodeReportEntry{}, err
+        }
+        txHash := log.TxHash
+        entries = append(entries, api.NodeReportEntry{
+            Time: getEventTime(values),
+            Block: log.BlockNumber,
+            Type: entryType,
+            TxHash: &txHash,
+            EthAmount: big.NewInt(0),
+            RplAmount: amount,
+            RplPrice: rplPrice,
+            EthValue: getRplEthValue(amount, rplPrice),
+        })
+    }
+
+    // Return
+    return entries, nil
+
+}
+
+
+// Get report entries for minipool refunds
+func (rb *reportBuilder) getRefundEntries(minipoolAddresses []common.Address) ([]api.NodeReportEntry, error) {
+
+    // Check minipools
+    if len(minipoolAddresses) == 0 {
+        return []api.NodeReportEntry{}, nil
+    }
+
+    // Get minipool contract
+    mp, err := minipool.NewMinipool(rb.rp, minipoolAddresses[0])
+    if err != nil {
+        return []api.NodeReportEntry{}, err
+    }
+    event, exists := mp.Contract.ABI.Events["EtherWithdrawn"]
+    if !exists {
+        return []api.NodeReportEntry{}, fmt.Errorf("Minipool contract has no EtherWithdrawn event")
+    }
+
+    // Get logs
+    logs, err := eth.GetLogs(rb.rp, minipoolAddresses, [][]common.Hash{{event.ID}}, rb.getEventLogInterval(), new(big.Int).Set(rb.fromBlock), new(big.Int).Set(rb.toBlock), nil)
+    if err != nil {
+        return []api.NodeReportEntry{}, fmt.Errorf("Could not get minipool refund events: %w", err)
+    }
+
+    // Create entries
+    entries := make([]api.NodeReportEntry, 0, len(logs))
+    for _, log := range logs {
+        values, err := rb.unpackLog(mp.Contract, "EtherWithdrawn", log)
+        if err != nil {
+            return []api.NodeReportEntry{}, err
+        }
+        amount, ok := values["amount"].(*big.Int)
+        if !ok {
+            return []api.NodeReportEntry{}, fmt.Errorf("Could not decode EtherWithdrawn event amount")
+        }
+        minipoolAddress := log.Address
+        txHash := log.TxHash
+        entries = append(entries, api.NodeReportEntry{
+            Time: getEventTime(values),
+            Block: log.BlockNumber,
+            Type: ReportRefund,
+            Minipool: &minipoolAddress,
+            TxHash: &txHash,
+            EthAmount: amount,
+            RplAmount: big.NewInt(0),
+            EthValue: amount,
+        })
+    }
+
+    // Return
+    return entries, nil
+
+}
+
+
+// Get report entries for the node's share of each minipool's beacon balance growth, and the commission it earned on the user share
+// Validators which exited during the period are measured up to their exit epoch
+func (rb *reportBuilder) getBeaconEntries(bc beacon.Client, minipoolAddresses []common.Address, fromEpoch, toEpoch uint64, to time.Time) ([]api.NodeReportEntry, error) {
+
+    // Get minipool pubkeys
+    opts := &bind.CallOpts{BlockNumber: rb.toBlock}
+    pubkeys := make([]rptypes.ValidatorPubkey, len(minipoolAddresses))
+    for mi, minipoolAddress := range minipoolAddresses {
+        pubkey, err := minipool.GetMinipoolPubkey(rb.rp, minipoolAddress, opts)
+        if err != nil {
+            return []api.NodeReportEntry{}, err
+        }
+        pubkeys[mi] = pubkey
+    }
+    if len(pubkeys) == 0 {
+        return []api.NodeReportEntry{}, nil
+    }
+
+    // Get validator statuses at the start & end of the period
+    startStatuses, err := bc.GetValidatorStatuses(pubkeys, &beacon.ValidatorStatusOptions{Epoch: fromEpoch})
+    if err != nil {
+        return []api.NodeReportEntry{}, fmt.Errorf("Could not get validator balances at epoch %d: %w", fromEpoch, err)
+    }
+    endStatuses, err := bc.GetValidatorStatuses(pubkeys, &beacon.ValidatorStatusOptions{Epoch: toEpoch})
+    if err != nil {
+        return []api.NodeReportEntry{}, fmt.Errorf("Could not get validator balances at epoch %d: %w", toEpoch, err)
+    }
+
+    // Get the balances of validators which exited during the period at their exit epochs
+    exitedPubkeys := make(map[uint64][]rptypes.ValidatorPubkey)
+    for _, pubkey := range pubkeys {
+        endStatus := endStatuses[pubkey]
+        if endStatus.Exists && endStatus.ExitEpoch > fromEpoch && endStatus.ExitEpoch < toEpoch {
+            exitedPubkeys[endStatus.ExitEpoch] = append(exitedPubkeys[endStatus.ExitEpoch], pubkey)
+        }
+    }
+    for exitEpoch, epochPubkeys := range exitedPubkeys {
+        exitStatuses, err := bc.GetValidatorStatuses(epochPubkeys, &beacon.ValidatorStatusOptions{Epoch: exitEpoch})
+        if err != nil {
+            return []api.NodeReportEntry{}, fmt.Errorf("Could not get validator balances at epoch %d: %w", exitEpoch, err)
+        }
+        for _, pubkey := range epochPubkeys {
+            endStatuses[pubkey] = exitStatuses[pubkey]
+        }
+    }
+
+    // Create entries
+    entries := []api.NodeReportEntry{}
+    for mi, minipoolAddress := range minipoolAddresses {
+
+        // Get balance growth; validators created during the period start from their deposit balance, and those which exited before it have none
+        startStatus := startStatuses[pubkeys[mi]]
+        endStatus := endStatuses[pubkeys[mi]]
+        if !endStatus.Exists {
+            continue
+        }
+        startBalance := uint64(ValidatorDepositBalanceGwei)
+        if startStatus.Exists {
+            startBalance = startStatus.Balance
+        }
+        endBalance := endStatus.Balance
+        if endStatus.ExitEpoch <= fromEpoch {
+            endBalance = startBalance
+        }
+        growth := new(big.Int).Sub(new(big.Int).SetUint64(endBalance), new(big.Int).SetUint64(startBalance))
+        growth.Mul(growth, big.NewInt(1e9))
+
+        // Get minipool deposit details
+        mp, err := minipool.NewMinipool(rb.rp, minipoolAddress)
+        if err != nil {
+            return []api.NodeReportEntry{}, err
+        }
+        nodeDetails, err := mp.GetNodeDetails(opts)
+        if err != nil {
+            return []api.NodeReportEntry{}, err
+        }
+        userDetails, err := mp.GetUserDetails(opts)
+        if err != nil {
+            return []api.NodeReportEntry{}, err
+        }
+        totalDeposit := new(big.Int).Add(nodeDetails.DepositBalance, userDetails.DepositBalance)
+        if totalDeposit.Sign() == 0 {
+            continue
+        }
+
+        // Get the node's share of the growth; commission is only earned on the user share of rewards
+        nodeShare := new(big.Int).Mul(growth, nodeDetails.DepositBalance)
+        nodeShare.Quo(nodeShare, totalDeposit)
+        commission := big.NewInt(0)
+        if growth.Sign() > 0 {
+            commission.Mul(growth, userDetails.DepositBalance)
+            commission.Mul(commission, eth.EthToWei(nodeDetails.Fee))
+            commission.Quo(commission, totalDeposit)
+            commission.Quo(commission, eth.EthToWei(1))
+        }
+
+        // Add entries
+        address := minipoolAddress
+        entries = append(entries, api.NodeReportEntry{
+            Time: to,
+            Block: rb.toBlock.Uint64(),
+            Type: ReportBeaconRewards,
+            Minipool: &address,
+            EthAmount: nodeShare,
+            RplAmount: big.NewInt(0),
+            EthValue: nodeShare,
+        }, api.NodeReportEntry{
+            Time: to,
+            Block: rb.toBlock.Uint64(),
+            Type: ReportCommission,
+            Minipool: &address,
+            EthAmount: commission,
+            RplAmount: big.NewInt(0),
+            EthValue: commission,
+        })
+
+    }
+
+    // Return
+    return entries, nil
+
+}
+
+
+// Sort report entries by time and get the totals for each entry type
+func sortReportEntries(entries []api.NodeReportEntry) map[string]api.NodeReportTotal {
+
+    // Sort entries
+    sort.SliceStable(entries, func(i, j int) bool {
+        return entries[i].Time.Before(entries[j].Time)
+    })
+
+    // Get totals
+    totals := make(map[string]api.NodeReportTotal)
+    for _, entry := range entries {
+        total, exists := totals[entry.Type]
+        if !exists {
+            total = api.NodeReportTotal{
+                EthAmount: big.NewInt(0),
+                RplAmount: big.NewInt(0),
+                EthValue: big.NewInt(0),
+            }
+        }
+        total.EthAmount.Add(total.EthAmount, entry.EthAmount)
+        total.RplAmount.Add(total.RplAmount, entry.RplAmount)
+        total.EthValue.Add(total.EthValue, entry.EthValue)
+        totals[entry.Type] = total
+    }
+
+    // Return
+    return totals
+
+}
+
+
+// Get the epoch at a time, clamped to the genesis epoch for times before genesis
+func getReportEpoch(eth2Config beacon.Eth2Config, t time.Time) uint64 {
+    if t.Unix() <= int64(eth2Config.GenesisTime) {
+        return eth2Config.GenesisEpoch
+    }
+    return eth2.EpochAt(eth2Config, uint64(t.Unix()))
+}
+
+
+// Get the RPL price at a block
+func (rb *reportBuilder) getRplPrice(blockNumber uint64) (*big.Int, error) {
+    if rplPrice, ok := rb.rplPrices[blockNumber]; ok {
+        return rplPrice, nil
+    }
+    rplPrice, err := network.GetRPLPrice(rb.rp, &bind.CallOpts{BlockNumber: new(big.Int).SetUint64(blockNumber)})
+    if err != nil {
+        return nil, fmt.Errorf("Could not get RPL price at block %d: %w", blockNumber, err)
+    }
+    rb.rplPrices[blockNumber] = rplPrice
+    return rplPrice, nil
+}
+
+
+// Get a copy of the event log interval, as log queries modify it (the block range is copied for the same reason)
+func (rb *reportBuilder) getEventLogInterval() *big.Int {
+    if rb.eventLogInterval == nil {
+        return nil
+    }
+    return new(big.Int).Set(rb.eventLogInterval)
+}
+
+
+// Unpack an event log's values
+func (rb *reportBuilder) unpackLog(contract *rocketpool.Contract, eventName string, log types.Log) (map[string]interface{}, error) {
+    values := make(map[string]interface{})
+    if err := contract.Contract.UnpackLogIntoMap(values, eventName, log); err != nil {
+        return nil, fmt.Errorf("Could not decode %s event: %w", eventName, err)
+    }
+    return values, nil
+}
+
+
+// Get the time an event was emitted at
+func getEventTime(values map[string]interface{}) time.Time {
+    if eventTime, ok := values["time"].(*big.Int); ok {
+        return time.Unix(eventTime.Int64(), 0)
+    }
+    return time.Time{}
+}
+
+
+// Get the ETH value of an RPL amount
+func getRplEthValue(rplAmount, rplPrice *big.Int) *big.Int {
+    value := new(big.Int).Mul(rplAmount, rplPrice)
+    return value.Quo(value, eth.EthToWei(1))
+}
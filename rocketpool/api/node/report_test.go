@@ -0,0 +1,115 @@
+package node
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/rocket-pool/rocketpool-go/utils/eth"
+
+	"github.com/rocket-pool/smartnode/shared/services/beacon"
+	"github.com/rocket-pool/smartnode/shared/types/api"
+)
+
+
+func TestSortReportEntries(t *testing.T) {
+    start := time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC)
+    entries := []api.NodeReportEntry{
+        {Time: start.Add(3 * time.Hour), Type: ReportGasCost, EthAmount: big.NewInt(3), RplAmount: big.NewInt(0), EthValue: big.NewInt(3)},
+        {Time: start.Add(time.Hour), Type: ReportRplStaked, EthAmount: big.NewInt(0), RplAmount: big.NewInt(100), EthValue: big.NewInt(50)},
+        {Time: start.Add(2 * time.Hour), Type: ReportGasCost, EthAmount: big.NewInt(4), RplAmount: big.NewInt(0), EthValue: big.NewInt(4)},
+        {Time: start.Add(time.Hour), Type: ReportRplStaked, EthAmount: big.NewInt(0), RplAmount: big.NewInt(10), EthValue: big.NewInt(6)},
+        {Time: start, Type: ReportBeaconRewards, EthAmount: big.NewInt(-2), RplAmount: big.NewInt(0), EthValue: big.NewInt(-2)},
+    }
+    totals := sortReportEntries(entries)
+
+    // Entries are sorted by time, keeping the order of entries at the same time
+    expectedOrder := []struct {
+        entryType string
+        rplAmount int64
+    }{
+        {ReportBeaconRewards, 0},
+        {ReportRplStaked, 100},
+        {ReportRplStaked, 10},
+        {ReportGasCost, 0},
+        {ReportGasCost, 0},
+    }
+    for ei, entry := range entries {
+        if entry.Type != expectedOrder[ei].entryType || entry.RplAmount.Int64() != expectedOrder[ei].rplAmount {
+            t.Errorf("entry %d is %s with %s RPL, expected %s with %d RPL", ei, entry.Type, entry.RplAmount.String(), expectedOrder[ei].entryType, expectedOrder[ei].rplAmount)
+        }
+    }
+    if !entries[3].Time.Before(entries[4].Time) {
+        t.Errorf("gas cost entries are out of order")
+    }
+
+    // Totals are summed per type, including losses
+    expectedTotals := map[string][3]int64{
+        ReportBeaconRewards: {-2, 0, -2},
+        ReportRplStaked: {0, 110, 56},
+        ReportGasCost: {7, 0, 7},
+    }
+    if len(totals) != len(expectedTotals) {
+        t.Fatalf("got %d totals, expected %d", len(totals), len(expectedTotals))
+    }
+    for entryType, expected := range expectedTotals {
+        total, ok := totals[entryType]
+        if !ok {
+            t.Errorf("missing %s total", entryType)
+            continue
+        }
+        if total.EthAmount.Int64() != expected[0] || total.RplAmount.Int64() != expected[1] || total.EthValue.Int64() != expected[2] {
+            t.Errorf("%s total is %s ETH, %s RPL, %s value, expected %d ETH, %d RPL, %d value", entryType,
+                total.EthAmount.String(), total.RplAmount.String(), total.EthValue.String(), expected[0], expected[1], expected[2])
+        }
+    }
+
+    // Totals do not modify entry amounts
+    if entries[1].RplAmount.Int64() != 100 {
+        t.Errorf("entry RPL amount was modified to %s", entries[1].RplAmount.String())
+    }
+
+    // No entries have no totals
+    if totals := sortReportEntries([]api.NodeReportEntry{}); len(totals) != 0 {
+        t.Errorf("got %d totals for no entries, expected none", len(totals))
+    }
+
+}
+
+
+func TestGetReportEpoch(t *testing.T) {
+    eth2Config := beacon.Eth2Config{
+        GenesisEpoch: 0,
+        GenesisTime: 1606824023,
+        SecondsPerEpoch: 384,
+    }
+    tests := []struct {
+        name string
+        time time.Time
+        expected uint64
+    }{
+        {"before genesis", time.Unix(1600000000, 0), 0},
+        {"at genesis", time.Unix(1606824023, 0), 0},
+        {"within first epoch", time.Unix(1606824023 + 383, 0), 0},
+        {"second epoch", time.Unix(1606824023 + 384, 0), 1},
+        {"later epoch", time.Unix(1606824023 + 384 * 1000 + 10, 0), 1000},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            if epoch := getReportEpoch(eth2Config, test.time); epoch != test.expected {
+                t.Errorf("epoch is %d, expected %d", epoch, test.expected)
+            }
+        })
+    }
+}
+
+
+func TestGetRplEthValue(t *testing.T) {
+    value := getRplEthValue(eth.EthToWei(150), eth.EthToWei(0.02))
+    if value.Cmp(eth.EthToWei(3)) != 0 {
+        t.Errorf("value is %s, expected %s", value.String(), eth.EthToWei(3).String())
+    }
+    if value := getRplEthValue(big.NewInt(0), eth.EthToWei(0.02)); value.Sign() != 0 {
+        t.Errorf("value of no RPL is %s, expected 0", value.String())
+    }
+}
@@ -0,0 +1,86 @@
+package node
+
+import (
+	"math/big"
+
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services"
+	"github.com/rocket-pool/smartnode/shared/types/api"
+)
+
+
+func getTxHistory(c *cli.Context) (*api.NodeTxHistoryResponse, error) {
+
+    // Get services
+    if err := services.RequireNodeWallet(c); err != nil { return nil, err }
+    w, err := services.GetWallet(c)
+    if err != nil { return nil, err }
+    rp, err := services.GetRocketPool(c)
+    if err != nil { return nil, err }
+    txLog, err := services.GetTxLog(c)
+    if err != nil { return nil, err }
+
+    // Response
+    response := api.NodeTxHistoryResponse{}
+
+    // Get node account
+    nodeAccount, err := w.GetNodeAccount()
+    if err != nil {
+        return nil, err
+    }
+    response.AccountAddress = nodeAccount.Address
+
+    // Get RPL token address
+    rplAddress, err := rp.GetAddress("rocketTokenRPL")
+    if err != nil {
+        return nil, err
+    }
+
+    // Record the results of any transactions which were not waited on
+    if err := txLog.ResolvePending(rp.Client); err != nil {
+        return nil, err
+    }
+
+    // Get transactions
+    records, err := txLog.GetRecords()
+    if err != nil {
+        return nil, err
+    }
+    response.Transactions = make([]api.NodeTransaction, len(records))
+    for ri, record := range records {
+
+        // Get gas cost
+        gasCost := big.NewInt(0)
+        if record.EffectiveGasPrice != nil {
+            gasCost.Mul(record.EffectiveGasPrice, new(big.Int).SetUint64(record.GasUsed))
+        }
+
+        // Get RPL transfers to & from the node account
+        rplSent := big.NewInt(0)
+        rplReceived := big.NewInt(0)
+        for _, transfer := range record.TokenTransfers {
+            if transfer.Token != *rplAddress {
+                continue
+            }
+            if transfer.From == nodeAccount.Address {
+                rplSent.Add(rplSent, transfer.Amount)
+            }
+            if transfer.To == nodeAccount.Address {
+                rplReceived.Add(rplReceived, transfer.Amount)
+            }
+        }
+
+        response.Transactions[ri] = api.NodeTransaction{
+            Record: record,
+            GasCost: gasCost,
+            RplSent: rplSent,
+            RplReceived: rplReceived,
+        }
+
+    }
+
+    // Return response
+    return &response, nil
+
+}
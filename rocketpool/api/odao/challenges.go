@@ -0,0 +1,206 @@
+package odao
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/rocketpool-go/dao/trustednode"
+	tnsettings "github.com/rocket-pool/rocketpool-go/settings/trustednode"
+	"github.com/urfave/cli"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/rocket-pool/smartnode/shared/services"
+	"github.com/rocket-pool/smartnode/shared/types/api"
+	"github.com/rocket-pool/smartnode/shared/utils/eth1"
+	rputils "github.com/rocket-pool/smartnode/shared/utils/rp"
+)
+
+
+func getChallenges(c *cli.Context) (*api.TNDAOChallengesResponse, error) {
+
+    // Get services
+    if err := services.RequireRocketStorage(c); err != nil { return nil, err }
+    rp, err := services.GetRocketPool(c)
+    if err != nil { return nil, err }
+    mc, err := services.GetMultiCaller(c)
+    if err != nil { return nil, err }
+
+    // Response
+    response := api.TNDAOChallengesResponse{}
+
+    // Get challenges
+    challenges, challengeWindow, err := rputils.GetTNDAOChallenges(rp, mc, nil)
+    if err != nil {
+        return nil, err
+    }
+    response.Challenges = challenges
+    response.ChallengeWindow = challengeWindow
+
+    // Return response
+    return &response, nil
+
+}
+
+
+func canMakeChallenge(c *cli.Context, memberAddress common.Address) (*api.CanMakeTNDAOChallengeResponse, error) {
+
+    // Get services
+    if err := services.RequireNodeRegistered(c); err != nil { return nil, err }
+    w, err := services.GetWallet(c)
+    if err != nil { return nil, err }
+    ec, err := services.GetEthClient(c)
+    if err != nil { return nil, err }
+    rp, err := services.GetRocketPool(c)
+    if err != nil { return nil, err }
+
+    // Response
+    response := api.CanMakeTNDAOChallengeResponse{}
+
+    // Get node account
+    nodeAccount, err := w.GetNodeAccount()
+    if err != nil {
+        return nil, err
+    }
+    response.ChallengingSelf = (nodeAccount.Address == memberAddress)
+
+    // Sync
+    var wg errgroup.Group
+    var isTrusted bool
+    var ethBalanceWei *big.Int
+
+    // Check the challenged member exists
+    wg.Go(func() error {
+        exists, err := trustednode.GetMemberExists(rp, memberAddress, nil)
+        if err == nil {
+            response.MemberDoesNotExist = !exists
+        }
+        return err
+    })
+
+    // Check if the member is already challenged
+    wg.Go(func() error {
+        isChallenged, err := trustednode.GetMemberIsChallenged(rp, memberAddress, nil)
+        if err == nil {
+            response.AlreadyChallenged = isChallenged
+        }
+        return err
+    })
+
+    // Get node trusted status
+    wg.Go(func() error {
+        var err error
+        isTrusted, err = trustednode.GetMemberExists(rp, nodeAccount.Address, nil)
+        return err
+    })
+
+    // Get challenge cost
+    wg.Go(func() error {
+        var err error
+        response.ChallengeCost, err = tnsettings.GetChallengeCost(rp, nil)
+        return err
+    })
+
+    // Get node balance
+    wg.Go(func() error {
+        var err error
+        ethBalanceWei, err = ec.BalanceAt(context.Background(), nodeAccount.Address, nil)
+        return err
+    })
+
+    // Wait for data
+    if err := wg.Wait(); err != nil {
+        return nil, err
+    }
+
+    // Members are subject to the challenge cooldown; other nodes must pay the challenge cost
+    if isTrusted {
+        response.ChallengeCost = big.NewInt(0)
+        response.ChallengeCooldownActive, err = getChallengeCooldownActive(rp, nodeAccount.Address)
+        if err != nil {
+            return nil, err
+        }
+    } else {
+        response.InsufficientBalance = (response.ChallengeCost.Cmp(ethBalanceWei) > 0)
+    }
+
+    // Update response
+    response.CanChallenge = !(response.MemberDoesNotExist || response.ChallengingSelf || response.AlreadyChallenged || response.ChallengeCooldownActive || response.InsufficientBalance)
+    if !response.CanChallenge {
+        return &response, nil
+    }
+
+    // Get gas estimate
+    opts, err := w.GetNodeAccountTransactor()
+    if err != nil {
+        return nil, err
+    }
+    opts.Value = response.ChallengeCost
+    gasInfo, err := trustednode.EstimateMakeChallengeGas(rp, memberAddress, opts)
+    if err != nil {
+        return nil, err
+    }
+    response.GasInfo = gasInfo
+
+    // Return response
+    return &response, nil
+
+}
+
+
+func makeChallenge(c *cli.Context, memberAddress common.Address) (*api.MakeTNDAOChallengeResponse, error) {
+
+    // Get services
+    if err := services.RequireNodeRegistered(c); err != nil { return nil, err }
+    w, err := services.GetWallet(c)
+    if err != nil { return nil, err }
+    rp, err := services.GetRocketPool(c)
+    if err != nil { return nil, err }
+
+    // Response
+    response := api.MakeTNDAOChallengeResponse{}
+
+    // Get node account
+    nodeAccount, err := w.GetNodeAccount()
+    if err != nil {
+        return nil, err
+    }
+
+    // Get the challenge cost; members do not pay to challenge
+    isTrusted, err := trustednode.GetMemberExists(rp, nodeAccount.Address, nil)
+    if err != nil {
+        return nil, err
+    }
+    challengeCost := big.NewInt(0)
+    if !isTrusted {
+        challengeCost, err = tnsettings.GetChallengeCost(rp, nil)
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    // Get transactor
+    opts, err := w.GetNodeAccountTransactor()
+    if err != nil {
+        return nil, err
+    }
+    opts.Value = challengeCost
+
+    // Override the provided pending TX if requested
+    err = eth1.CheckForNonceOverride(c, opts)
+    if err != nil {
+        return nil, fmt.Errorf("Error checking for nonce override: %w", err)
+    }
+
+    // Make challenge
+    hash, err := trustednode.MakeChallenge(rp, memberAddress, opts)
+    if err != nil {
+        return nil, err
+    }
+    response.TxHash = hash
+
+    // Return response
+    return &response, nil
+
+}
@@ -88,6 +88,24 @@ func RegisterSubcommands(command *cli.Command, name string, aliases []string) {
                 },
             },
 
+            cli.Command{
+                Name:      "simulate-proposal",
+                Usage:     "Simulate the execution of a proposal and get the changes it would make",
+                UsageText: "rocketpool api odao simulate-proposal proposal-id",
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
+                    id, err := cliutils.ValidateUint("proposal-id", c.Args().Get(0))
+                    if err != nil { return err }
+
+                    // Run
+                    api.PrintResponse(simulateProposal(c, id))
+                    return nil
+
+                },
+            },
+
             cli.Command{
                 Name:      "can-propose-invite",
                 Usage:     "Check whether the node can propose inviting a new member",
@@ -395,352 +413,141 @@ func RegisterSubcommands(command *cli.Command, name string, aliases []string) {
             },
 
             cli.Command{
-                Name:      "can-propose-members-quorum",
-                Usage:     "Check whether the node can propose the members.quorum setting",
-                UsageText: "rocketpool api odao can-propose-members-quorum value",
-                Action: func(c *cli.Context) error {
-
-                    // Validate args
-                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-                    quorum, err := cliutils.ValidateFraction("quorum", c.Args().Get(0))
-                    if err != nil { return err }
-
-                    // Run
-                    api.PrintResponse(canProposeSettingMembersQuorum(c, quorum))
-                    return nil
-
-                },
-            },
-            cli.Command{
-                Name:      "propose-members-quorum",
-                Usage:     "Propose updating the members.quorum setting",
-                UsageText: "rocketpool api odao propose-members-quorum value",
-                Action: func(c *cli.Context) error {
-
-                    // Validate args
-                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-                    quorum, err := cliutils.ValidateFraction("quorum", c.Args().Get(0))
-                    if err != nil { return err }
-
-                    // Run
-                    api.PrintResponse(proposeSettingMembersQuorum(c, quorum))
-                    return nil
-
-                },
-            },
-
-            cli.Command{
-                Name:      "can-propose-members-rplbond",
-                Usage:     "Check whether the node can propose the members.rplbond setting",
-                UsageText: "rocketpool api odao can-propose-members-rplbond value",
-                Action: func(c *cli.Context) error {
-
-                    // Validate args
-                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-                    bondAmountWei, err := cliutils.ValidateWeiAmount("RPL bond amount", c.Args().Get(0))
-                    if err != nil { return err }
-
-                    // Run
-                    api.PrintResponse(canProposeSettingMembersRplBond(c, bondAmountWei))
-                    return nil
-
-                },
-            },
-            cli.Command{
-                Name:      "propose-members-rplbond",
-                Usage:     "Propose updating the members.rplbond setting",
-                UsageText: "rocketpool api odao propose-members-rplbond value",
-                Action: func(c *cli.Context) error {
-
-                    // Validate args
-                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-                    bondAmountWei, err := cliutils.ValidateWeiAmount("RPL bond amount", c.Args().Get(0))
-                    if err != nil { return err }
-
-                    // Run
-                    api.PrintResponse(proposeSettingMembersRplBond(c, bondAmountWei))
-                    return nil
-
-                },
-            },
-
-            cli.Command{
-                Name:      "can-propose-members-minipool-unbonded-max",
-                Usage:     "Check whether the node can propose the members.minipool.unbonded.max setting",
-                UsageText: "rocketpool api odao can-propose-members-minipool-unbonded-max value",
+                Name:      "can-propose-setting",
+                Usage:     "Check whether the node can propose an oracle DAO setting update",
+                UsageText: "rocketpool api odao can-propose-setting name value",
                 Action: func(c *cli.Context) error {
 
                     // Validate args
-                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-                    unbondedMinipoolMax, err := cliutils.ValidateUint("maximum unbonded minipool count", c.Args().Get(0))
+                    if err := cliutils.ValidateArgCount(c, 2); err != nil { return err }
+                    value, err := cliutils.ValidateBigInt("setting value", c.Args().Get(1))
                     if err != nil { return err }
 
                     // Run
-                    api.PrintResponse(canProposeSettingMinipoolUnbondedMax(c, unbondedMinipoolMax))
+                    api.PrintResponse(canProposeSetting(c, c.Args().Get(0), value))
                     return nil
 
                 },
             },
             cli.Command{
-                Name:      "propose-members-minipool-unbonded-max",
-                Usage:     "Propose updating the members.minipool.unbonded.max setting",
-                UsageText: "rocketpool api odao propose-members-minipool-unbonded-max value",
+                Name:      "propose-setting",
+                Usage:     "Propose an oracle DAO setting update",
+                UsageText: "rocketpool api odao propose-setting name value",
                 Action: func(c *cli.Context) error {
 
                     // Validate args
-                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-                    unbondedMinipoolMax, err := cliutils.ValidateUint("maximum unbonded minipool count", c.Args().Get(0))
+                    if err := cliutils.ValidateArgCount(c, 2); err != nil { return err }
+                    value, err := cliutils.ValidateBigInt("setting value", c.Args().Get(1))
                     if err != nil { return err }
 
                     // Run
-                    api.PrintResponse(proposeSettingMinipoolUnbondedMax(c, unbondedMinipoolMax))
+                    api.PrintResponse(proposeSetting(c, c.Args().Get(0), value))
                     return nil
 
                 },
             },
 
             cli.Command{
-                Name:      "can-propose-proposal-cooldown",
-                Usage:     "Check whether the node can propose the proposal.cooldown setting",
-                UsageText: "rocketpool api odao can-propose-proposal-cooldown value",
-                Action: func(c *cli.Context) error {
-
-                    // Validate args
-                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-                    proposalCooldownBlocks, err := cliutils.ValidateUint("proposal cooldown period", c.Args().Get(0))
-                    if err != nil { return err }
-
-                    // Run
-                    api.PrintResponse(canProposeSettingProposalCooldown(c, proposalCooldownBlocks))
-                    return nil
-
-                },
-            },
-            cli.Command{
-                Name:      "propose-proposal-cooldown",
-                Usage:     "Propose updating the proposal.cooldown setting",
-                UsageText: "rocketpool api odao propose-proposal-cooldown value",
+                Name:      "get-settings",
+                Usage:     "Get the current values of the oracle DAO settings",
+                UsageText: "rocketpool api odao get-settings",
                 Action: func(c *cli.Context) error {
 
                     // Validate args
-                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-                    proposalCooldownBlocks, err := cliutils.ValidateUint("proposal cooldown period", c.Args().Get(0))
-                    if err != nil { return err }
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
 
                     // Run
-                    api.PrintResponse(proposeSettingProposalCooldown(c, proposalCooldownBlocks))
+                    api.PrintResponse(getSettings(c))
                     return nil
 
                 },
             },
 
             cli.Command{
-                Name:      "can-propose-proposal-vote-timespan",
-                Usage:     "Check whether the node can propose the proposal.vote.time setting",
-                UsageText: "rocketpool api odao can-propose-proposal-vote-timespan value",
+                Name:      "challenges",
+                Usage:     "Get the active challenges against oracle DAO members",
+                UsageText: "rocketpool api odao challenges",
                 Action: func(c *cli.Context) error {
 
                     // Validate args
-                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-                    proposalVoteTimespan, err := cliutils.ValidateUint("proposal voting period", c.Args().Get(0))
-                    if err != nil { return err }
-
-                    // Run
-                    api.PrintResponse(canProposeSettingProposalVoteTimespan(c, proposalVoteTimespan))
-                    return nil
-
-                },
-            },
-            cli.Command{
-                Name:      "propose-proposal-vote-timespan",
-                Usage:     "Propose updating the proposal.vote.time setting",
-                UsageText: "rocketpool api odao propose-proposal-vote-timespan value",
-                Action: func(c *cli.Context) error {
-
-                    // Validate args
-                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-                    proposalVoteTimespan, err := cliutils.ValidateUint("proposal voting period", c.Args().Get(0))
-                    if err != nil { return err }
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
 
                     // Run
-                    api.PrintResponse(proposeSettingProposalVoteTimespan(c, proposalVoteTimespan))
+                    api.PrintResponse(getChallenges(c))
                     return nil
 
                 },
             },
-
             cli.Command{
-                Name:      "can-propose-proposal-vote-delay-timespan",
-                Usage:     "Check whether the node can propose the proposal.vote.delay.time setting",
-                UsageText: "rocketpool api odao can-propose-proposal-vote-delay-timespan value",
+                Name:      "can-make-challenge",
+                Usage:     "Check whether the node can challenge a member",
+                UsageText: "rocketpool api odao can-make-challenge member-address",
                 Action: func(c *cli.Context) error {
 
                     // Validate args
                     if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-                    proposalDelayTimespan, err := cliutils.ValidateUint("proposal delay period", c.Args().Get(0))
+                    memberAddress, err := cliutils.ValidateAddress("member address", c.Args().Get(0))
                     if err != nil { return err }
 
                     // Run
-                    api.PrintResponse(canProposeSettingProposalVoteDelayTimespan(c, proposalDelayTimespan))
+                    api.PrintResponse(canMakeChallenge(c, memberAddress))
                     return nil
 
                 },
             },
             cli.Command{
-                Name:      "propose-proposal-vote-delay-timespan",
-                Usage:     "Propose updating the proposal.vote.delay.time setting",
-                UsageText: "rocketpool api odao propose-proposal-vote-delay-timespan value",
+                Name:      "make-challenge",
+                Usage:     "Challenge a member to prove that its node is online",
+                UsageText: "rocketpool api odao make-challenge member-address",
                 Action: func(c *cli.Context) error {
 
                     // Validate args
                     if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-                    proposalDelayTimespan, err := cliutils.ValidateUint("proposal delay period", c.Args().Get(0))
+                    memberAddress, err := cliutils.ValidateAddress("member address", c.Args().Get(0))
                     if err != nil { return err }
 
                     // Run
-                    api.PrintResponse(proposeSettingProposalVoteDelayTimespan(c, proposalDelayTimespan))
+                    api.PrintResponse(makeChallenge(c, memberAddress))
                     return nil
 
                 },
             },
 
             cli.Command{
-                Name:      "can-propose-proposal-execute-timespan",
-                Usage:     "Check whether the node can propose the proposal.execute.time setting",
-                UsageText: "rocketpool api odao can-propose-proposal-execute-timespan value",
+                Name:      "submissions",
+                Usage:     "Audit the members' balances or prices submissions over a block range",
+                UsageText: "rocketpool api odao submissions type from-block to-block late-blocks",
                 Action: func(c *cli.Context) error {
 
                     // Validate args
-                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-                    proposalExecuteTimespan, err := cliutils.ValidateUint("proposal execution period", c.Args().Get(0))
+                    if err := cliutils.ValidateArgCount(c, 4); err != nil { return err }
+                    submissionType, err := cliutils.ValidateSubmissionType("submission type", c.Args().Get(0))
                     if err != nil { return err }
-
-                    // Run
-                    api.PrintResponse(canProposeSettingProposalExecuteTimespan(c, proposalExecuteTimespan))
-                    return nil
-
-                },
-            },
-            cli.Command{
-                Name:      "propose-proposal-execute-timespan",
-                Usage:     "Propose updating the proposal.execute.time setting",
-                UsageText: "rocketpool api odao propose-proposal-execute-timespan value",
-                Action: func(c *cli.Context) error {
-
-                    // Validate args
-                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-                    proposalExecuteTimespan, err := cliutils.ValidateUint("proposal execution period", c.Args().Get(0))
+                    fromBlock, err := cliutils.ValidateUint("from block", c.Args().Get(1))
                     if err != nil { return err }
-
-                    // Run
-                    api.PrintResponse(proposeSettingProposalExecuteTimespan(c, proposalExecuteTimespan))
-                    return nil
-
-                },
-            },
-
-            cli.Command{
-                Name:      "can-propose-proposal-action-timespan",
-                Usage:     "Check whether the node can propose the proposal.action.time setting",
-                UsageText: "rocketpool api odao can-propose-proposal-action-timespan value",
-                Action: func(c *cli.Context) error {
-
-                    // Validate args
-                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-                    proposalActionTimespan, err := cliutils.ValidateUint("proposal action period", c.Args().Get(0))
+                    toBlock, err := cliutils.ValidateUint("to block", c.Args().Get(2))
                     if err != nil { return err }
-
-                    // Run
-                    api.PrintResponse(canProposeSettingProposalActionTimespan(c, proposalActionTimespan))
-                    return nil
-
-                },
-            },
-            cli.Command{
-                Name:      "propose-proposal-action-timespan",
-                Usage:     "Propose updating the proposal.action.time setting",
-                UsageText: "rocketpool api odao propose-proposal-action-timespan value",
-                Action: func(c *cli.Context) error {
-
-                    // Validate args
-                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-                    proposalActionTimespan, err := cliutils.ValidateUint("proposal action period", c.Args().Get(0))
+                    lateBlocks, err := cliutils.ValidateUint("late blocks", c.Args().Get(3))
                     if err != nil { return err }
 
                     // Run
-                    api.PrintResponse(proposeSettingProposalActionTimespan(c, proposalActionTimespan))
+                    api.PrintResponse(getSubmissions(c, submissionType, fromBlock, toBlock, lateBlocks))
                     return nil
 
                 },
             },
 
             cli.Command{
-                Name:      "can-propose-scrub-period",
-                Usage:     "Check whether the node can propose the minipool.scrub.period setting",
-                UsageText: "rocketpool api odao can-propose-scrub-period value",
-                Action: func(c *cli.Context) error {
-
-                    // Validate args
-                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-                    scrubPeriod, err := cliutils.ValidateUint("scrub period", c.Args().Get(0))
-                    if err != nil { return err }
-
-                    // Run
-                    api.PrintResponse(canProposeSettingScrubPeriod(c, scrubPeriod))
-                    return nil
-
-                },
-            },
-            cli.Command{
-                Name:      "propose-scrub-period",
-                Usage:     "Propose updating the minipool.scrub.period setting",
-                UsageText: "rocketpool api odao propose-scrub-period value",
+                Name:      "scrub-reports",
+                Usage:     "Get the evidence reports for the minipools the watchtower has voted to scrub",
+                UsageText: "rocketpool api odao scrub-reports",
                 Action: func(c *cli.Context) error {
 
                     // Validate args
-                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
-                    scrubPeriod, err := cliutils.ValidateUint("scrub period", c.Args().Get(0))
-                    if err != nil { return err }
-
-                    // Run
-                    api.PrintResponse(proposeSettingScrubPeriod(c, scrubPeriod))
-                    return nil
-
-                },
-            },
-            
-            cli.Command{
-                Name:      "get-member-settings",
-                Usage:     "Get the ODAO settings related to ODAO members",
-                UsageText: "rocketpool api odao get-member-settings",
-                Action: func(c *cli.Context) error {
-
-                    // Run
-                    api.PrintResponse(getMemberSettings(c))
-                    return nil
-
-                },
-            },
-            cli.Command{
-                Name:      "get-proposal-settings",
-                Usage:     "Get the ODAO settings related to ODAO proposals",
-                UsageText: "rocketpool api odao get-proposal-settings",
-                Action: func(c *cli.Context) error {
-
-                    // Run
-                    api.PrintResponse(getProposalSettings(c))
-                    return nil
-
-                },
-            },
-            cli.Command{
-                Name:      "get-minipool-settings",
-                Usage:     "Get the ODAO settings related to minipools",
-                UsageText: "rocketpool api odao get-minipool-settings",
-                Action: func(c *cli.Context) error {
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
 
                     // Run
-                    api.PrintResponse(getMinipoolSettings(c))
+                    api.PrintResponse(getScrubReports(c))
                     return nil
 
                 },
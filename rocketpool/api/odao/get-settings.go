@@ -1,133 +1,41 @@
 package odao
 
 import (
-	"fmt"
-
-	"github.com/rocket-pool/rocketpool-go/settings/trustednode"
 	"github.com/urfave/cli"
 
 	"github.com/rocket-pool/smartnode/shared/services"
 	"github.com/rocket-pool/smartnode/shared/types/api"
+	rputils "github.com/rocket-pool/smartnode/shared/utils/rp"
 )
 
 
-func getMemberSettings(c *cli.Context) (*api.GetTNDAOMemberSettingsResponse, error) {
+func getSettings(c *cli.Context) (*api.GetTNDAOSettingsResponse, error) {
 
     // Get services
     if err := services.RequireNodeTrusted(c); err != nil { return nil, err }
     rp, err := services.GetRocketPool(c)
     if err != nil { return nil, err }
-
-    // Response
-    response := api.GetTNDAOMemberSettingsResponse{}
-
-    quorum, err := trustednode.GetQuorum(rp, nil)
-    if(err != nil) {
-        return nil, fmt.Errorf("Error getting quorum: %w", err)
-    }
-
-    rplBond, err := trustednode.GetRPLBond(rp, nil)
-    if(err != nil) {
-        return nil, fmt.Errorf("Error getting RPL Bond: %w", err)
-    }
-
-    minipoolUnbondedMax, err := trustednode.GetMinipoolUnbondedMax(rp, nil)
-    if(err != nil) {
-        return nil, fmt.Errorf("Error getting minipool unbonded max: %w", err)
-    }
-
-    challengeCooldown, err := trustednode.GetChallengeCooldown(rp, nil)
-    if(err != nil) {
-        return nil, fmt.Errorf("Error getting challenge cooldown: %w", err)
-    }
-
-    challengeWindow, err := trustednode.GetChallengeWindow(rp, nil)
-    if(err != nil) {
-        return nil, fmt.Errorf("Error getting challenge window: %w", err)
-    }
-
-    challengeCost, err := trustednode.GetChallengeCost(rp, nil)
-    if(err != nil) {
-        return nil, fmt.Errorf("Error getting challenge cost: %w", err)
-    }
-
-    response.Quorum = quorum
-    response.RPLBond = rplBond
-    response.MinipoolUnbondedMax = minipoolUnbondedMax
-    response.ChallengeCooldown = challengeCooldown
-    response.ChallengeWindow = challengeWindow
-    response.ChallengeCost = challengeCost
-    
-    // Return response
-    return &response, nil
-}
-
-
-func getProposalSettings(c *cli.Context) (*api.GetTNDAOProposalSettingsResponse, error) {
-
-    // Get services
-    if err := services.RequireNodeTrusted(c); err != nil { return nil, err }
-    rp, err := services.GetRocketPool(c)
+    mc, err := services.GetMultiCaller(c)
     if err != nil { return nil, err }
 
     // Response
-    response := api.GetTNDAOProposalSettingsResponse{}
-
-    cooldown, err := trustednode.GetProposalCooldownTime(rp, nil)
-    if(err != nil) {
-        return nil, fmt.Errorf("Error getting proposal cooldown: %w", err)
-    }
-
-    voteTime, err := trustednode.GetProposalVoteTime(rp, nil)
-    if(err != nil) {
-        return nil, fmt.Errorf("Error getting proposal vote time: %w", err)
-    }
-
-    voteDelayTime, err := trustednode.GetProposalVoteDelayTime(rp, nil)
-    if(err != nil) {
-        return nil, fmt.Errorf("Error getting proposal vote delay time: %w", err)
-    }
-
-    executeTime, err := trustednode.GetProposalExecuteTime(rp, nil)
-    if(err != nil) {
-        return nil, fmt.Errorf("Error getting proposal execute time: %w", err)
-    }
+    response := api.GetTNDAOSettingsResponse{}
 
-    actionTime, err := trustednode.GetProposalActionTime(rp, nil)
-    if(err != nil) {
-        return nil, fmt.Errorf("Error getting proposal action time: %w", err)
+    // Get setting values
+    settings := rputils.GetTNDAOSettings()
+    values, err := rputils.GetTNDAOSettingValues(rp, mc, settings, nil)
+    if err != nil {
+        return nil, err
     }
-
-    response.Cooldown = cooldown
-    response.VoteTime = voteTime
-    response.VoteDelayTime = voteDelayTime
-    response.ExecuteTime = executeTime
-    response.ActionTime = actionTime
-    
-    // Return response
-    return &response, nil
-}
-
-
-func getMinipoolSettings(c *cli.Context) (*api.GetTNDAOMinipoolSettingsResponse, error) {
-
-    // Get services
-    if err := services.RequireNodeTrusted(c); err != nil { return nil, err }
-    rp, err := services.GetRocketPool(c)
-    if err != nil { return nil, err }
-
-    // Response
-    response := api.GetTNDAOMinipoolSettingsResponse{}
-
-    scrubPeriod, err := trustednode.GetScrubPeriod(rp, nil)
-    if(err != nil) {
-        return nil, fmt.Errorf("Error getting scrub period: %w", err)
+    response.Settings = make([]api.TNDAOSettingValue, len(settings))
+    for si, setting := range settings {
+        response.Settings[si] = api.TNDAOSettingValue{
+            Name: setting.Name,
+            Value: values[si],
+        }
     }
-    
-    response.ScrubPeriod = scrubPeriod
 
     // Return response
     return &response, nil
 
 }
-
@@ -6,6 +6,7 @@ import (
 
     "github.com/rocket-pool/smartnode/shared/services"
     "github.com/rocket-pool/smartnode/shared/types/api"
+    rputils "github.com/rocket-pool/smartnode/shared/utils/rp"
 )
 
 
@@ -73,3 +74,34 @@ func getProposal(c *cli.Context, id uint64) (*api.TNDAOProposalResponse, error)
 
 }
 
+
+
+func simulateProposal(c *cli.Context, id uint64) (*api.SimulateTNDAOProposalResponse, error) {
+
+    // Get services
+    if err := services.RequireRocketStorage(c); err != nil { return nil, err }
+    rp, err := services.GetRocketPool(c)
+    if err != nil { return nil, err }
+    rpcClient, err := services.GetEthRPCClient(c)
+    if err != nil { return nil, err }
+
+    // Response
+    response := api.SimulateTNDAOProposalResponse{}
+
+    // Get proposal payload
+    payload, err := dao.GetProposalPayload(rp, id, nil)
+    if err != nil {
+        return nil, err
+    }
+
+    // Simulate proposal execution
+    simulation, err := rputils.SimulateTNDAOProposal(rp, rpcClient, payload, nil)
+    if err != nil {
+        return nil, err
+    }
+    response.Simulation = simulation
+
+    // Return response
+    return &response, nil
+
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/rocket-pool/smartnode/shared/types/api"
 	"github.com/rocket-pool/smartnode/shared/utils/eth1"
 	"github.com/rocket-pool/smartnode/shared/utils/math"
+	rputils "github.com/rocket-pool/smartnode/shared/utils/rp"
 )
 
 
@@ -25,6 +26,8 @@ func canProposeKick(c *cli.Context, memberAddress common.Address, fineAmountWei
     if err != nil { return nil, err }
     rp, err := services.GetRocketPool(c)
     if err != nil { return nil, err }
+    rpcClient, err := services.GetEthRPCClient(c)
+    if err != nil { return nil, err }
 
     // Response
     response := api.CanProposeTNDAOKickResponse{}
@@ -54,6 +57,19 @@ func canProposeKick(c *cli.Context, memberAddress common.Address, fineAmountWei
         return err
     })
 
+    // Simulate proposal execution
+    wg.Go(func() error {
+        payload, err := rputils.EncodeTNDAOProposalPayload(rp, "proposalKick", memberAddress, fineAmountWei)
+        if err != nil {
+            return err
+        }
+        simulation, err := rputils.SimulateTNDAOProposal(rp, rpcClient, payload, nil)
+        if err == nil {
+            response.Simulation = simulation
+        }
+        return err
+    })
+
     // Get gas estimate
     wg.Go(func() error {
         opts, err := w.GetNodeAccountTransactor()
@@ -4,73 +4,17 @@ import (
 	"fmt"
 	"math/big"
 
-	"github.com/rocket-pool/rocketpool-go/rocketpool"
-	"github.com/rocket-pool/rocketpool-go/settings/trustednode"
+	"github.com/rocket-pool/rocketpool-go/dao/trustednode"
 	"github.com/urfave/cli"
 
 	"github.com/rocket-pool/smartnode/shared/services"
-	"github.com/rocket-pool/smartnode/shared/services/wallet"
 	"github.com/rocket-pool/smartnode/shared/types/api"
 	"github.com/rocket-pool/smartnode/shared/utils/eth1"
+	rputils "github.com/rocket-pool/smartnode/shared/utils/rp"
 )
 
 
-func canProposeSetting(c *cli.Context, w *wallet.Wallet, rp *rocketpool.RocketPool) (*api.CanProposeTNDAOSettingResponse, error) {
-
-    // Response
-    response := api.CanProposeTNDAOSettingResponse{}
-
-    // Get node account
-    nodeAccount, err := w.GetNodeAccount()
-    if err != nil {
-        return nil, err
-    }
-
-    // Check if proposal cooldown is active
-    proposalCooldownActive, err := getProposalCooldownActive(rp, nodeAccount.Address)
-    if err != nil {
-        return nil, err
-    }
-    response.ProposalCooldownActive = proposalCooldownActive
-
-    // Update & return response
-    response.CanPropose = !response.ProposalCooldownActive
-    return &response, nil
-
-}
-
-
-func canProposeSettingMembersQuorum(c *cli.Context, quorum float64) (*api.CanProposeTNDAOSettingResponse, error) {
-
-    // Get services
-    if err := services.RequireNodeTrusted(c); err != nil { return nil, err }
-    w, err := services.GetWallet(c)
-    if err != nil { return nil, err }
-    rp, err := services.GetRocketPool(c)
-    if err != nil { return nil, err }
-
-    response, err := canProposeSetting(c, w, rp)
-    if err != nil {
-        return nil, err
-    }
-
-    // Get gas estimate
-    opts, err := w.GetNodeAccountTransactor()
-    if err != nil { 
-        return nil, err 
-    }
-    gasInfo, err := trustednode.EstimateProposeQuorumGas(rp, quorum, opts)
-    if err != nil {
-        return nil, err
-    }
-
-    response.GasInfo = gasInfo
-    return response, nil
-
-}
-
-
-func proposeSettingMembersQuorum(c *cli.Context, quorum float64) (*api.ProposeTNDAOSettingMembersQuorumResponse, error) {
+func canProposeSetting(c *cli.Context, name string, value *big.Int) (*api.CanProposeTNDAOSettingResponse, error) {
 
     // Get services
     if err := services.RequireNodeTrusted(c); err != nil { return nil, err }
@@ -78,445 +22,67 @@ func proposeSettingMembersQuorum(c *cli.Context, quorum float64) (*api.ProposeTN
     if err != nil { return nil, err }
     rp, err := services.GetRocketPool(c)
     if err != nil { return nil, err }
-
-    // Response
-    response := api.ProposeTNDAOSettingMembersQuorumResponse{}
-
-    // Get transactor
-    opts, err := w.GetNodeAccountTransactor()
-    if err != nil {
-        return nil, err
-    }
-
-    // Override the provided pending TX if requested 
-    err = eth1.CheckForNonceOverride(c, opts)
-    if err != nil {
-        return nil, fmt.Errorf("Error checking for nonce override: %w", err)
-    }
-
-    // Submit proposal
-    proposalId, hash, err := trustednode.ProposeQuorum(rp, quorum, opts)
-    if err != nil {
-        return nil, err
-    }
-    response.ProposalId = proposalId
-    response.TxHash = hash
-
-    // Return response
-    return &response, nil
-
-}
-
-
-func canProposeSettingMembersRplBond(c *cli.Context, bondAmountWei *big.Int) (*api.CanProposeTNDAOSettingResponse, error) {
-
-    // Get services
-    if err := services.RequireNodeTrusted(c); err != nil { return nil, err }
-    w, err := services.GetWallet(c)
-    if err != nil { return nil, err }
-    rp, err := services.GetRocketPool(c)
-    if err != nil { return nil, err }
-
-    response, err := canProposeSetting(c, w, rp)
-    if err != nil {
-        return nil, err
-    }
-
-    // Get gas estimate
-    opts, err := w.GetNodeAccountTransactor()
-    if err != nil { 
-        return nil, err 
-    }
-    gasInfo, err := trustednode.EstimateProposeRPLBondGas(rp, bondAmountWei, opts)
-    if err != nil {
-        return nil, err
-    }
-
-    response.GasInfo = gasInfo
-    return response, nil
-
-}
-
-
-func proposeSettingMembersRplBond(c *cli.Context, bondAmountWei *big.Int) (*api.ProposeTNDAOSettingMembersRplBondResponse, error) {
-
-    // Get services
-    if err := services.RequireNodeTrusted(c); err != nil { return nil, err }
-    w, err := services.GetWallet(c)
-    if err != nil { return nil, err }
-    rp, err := services.GetRocketPool(c)
-    if err != nil { return nil, err }
-
-    // Response
-    response := api.ProposeTNDAOSettingMembersRplBondResponse{}
-
-    // Get transactor
-    opts, err := w.GetNodeAccountTransactor()
-    if err != nil {
-        return nil, err
-    }
-
-    // Override the provided pending TX if requested 
-    err = eth1.CheckForNonceOverride(c, opts)
-    if err != nil {
-        return nil, fmt.Errorf("Error checking for nonce override: %w", err)
-    }
-
-    // Submit proposal
-    proposalId, hash, err := trustednode.ProposeRPLBond(rp, bondAmountWei, opts)
-    if err != nil {
-        return nil, err
-    }
-    response.ProposalId = proposalId
-    response.TxHash = hash
-
-    // Return response
-    return &response, nil
-
-}
-
-
-func canProposeSettingMinipoolUnbondedMax(c *cli.Context, unbondedMinipoolMax uint64) (*api.CanProposeTNDAOSettingResponse, error) {
-
-    // Get services
-    if err := services.RequireNodeTrusted(c); err != nil { return nil, err }
-    w, err := services.GetWallet(c)
-    if err != nil { return nil, err }
-    rp, err := services.GetRocketPool(c)
-    if err != nil { return nil, err }
-
-    response, err := canProposeSetting(c, w, rp)
-    if err != nil {
-        return nil, err
-    }
-
-    // Get gas estimate
-    opts, err := w.GetNodeAccountTransactor()
-    if err != nil { 
-        return nil, err 
-    }
-    gasInfo, err := trustednode.EstimateProposeMinipoolUnbondedMaxGas(rp, unbondedMinipoolMax, opts)
-    if err != nil {
-        return nil, err
-    }
-
-    response.GasInfo = gasInfo
-    return response, nil
-
-}
-
-
-func proposeSettingMinipoolUnbondedMax(c *cli.Context, unbondedMinipoolMax uint64) (*api.ProposeTNDAOSettingMinipoolUnbondedMaxResponse, error) {
-
-    // Get services
-    if err := services.RequireNodeTrusted(c); err != nil { return nil, err }
-    w, err := services.GetWallet(c)
-    if err != nil { return nil, err }
-    rp, err := services.GetRocketPool(c)
-    if err != nil { return nil, err }
-
-    // Response
-    response := api.ProposeTNDAOSettingMinipoolUnbondedMaxResponse{}
-
-    // Get transactor
-    opts, err := w.GetNodeAccountTransactor()
-    if err != nil {
-        return nil, err
-    }
-
-    // Override the provided pending TX if requested 
-    err = eth1.CheckForNonceOverride(c, opts)
-    if err != nil {
-        return nil, fmt.Errorf("Error checking for nonce override: %w", err)
-    }
-
-    // Submit proposal
-    proposalId, hash, err := trustednode.ProposeMinipoolUnbondedMax(rp, unbondedMinipoolMax, opts)
-    if err != nil {
-        return nil, err
-    }
-    response.ProposalId = proposalId
-    response.TxHash = hash
-
-    // Return response
-    return &response, nil
-
-}
-
-
-func canProposeSettingProposalCooldown(c *cli.Context, proposalCooldownTimespan uint64) (*api.CanProposeTNDAOSettingResponse, error) {
-
-    // Get services
-    if err := services.RequireNodeTrusted(c); err != nil { return nil, err }
-    w, err := services.GetWallet(c)
-    if err != nil { return nil, err }
-    rp, err := services.GetRocketPool(c)
-    if err != nil { return nil, err }
-
-    response, err := canProposeSetting(c, w, rp)
-    if err != nil {
-        return nil, err
-    }
-
-    // Get gas estimate
-    opts, err := w.GetNodeAccountTransactor()
-    if err != nil { 
-        return nil, err 
-    }
-    gasInfo, err := trustednode.EstimateProposeProposalCooldownTimeGas(rp, proposalCooldownTimespan, opts)
-    if err != nil {
-        return nil, err
-    }
-
-    response.GasInfo = gasInfo
-    return response, nil
-
-}
-
-
-func proposeSettingProposalCooldown(c *cli.Context, proposalCooldownTimespan uint64) (*api.ProposeTNDAOSettingProposalCooldownResponse, error) {
-
-    // Get services
-    if err := services.RequireNodeTrusted(c); err != nil { return nil, err }
-    w, err := services.GetWallet(c)
-    if err != nil { return nil, err }
-    rp, err := services.GetRocketPool(c)
-    if err != nil { return nil, err }
-
-    // Response
-    response := api.ProposeTNDAOSettingProposalCooldownResponse{}
-
-    // Get transactor
-    opts, err := w.GetNodeAccountTransactor()
-    if err != nil {
-        return nil, err
-    }
-
-    // Override the provided pending TX if requested 
-    err = eth1.CheckForNonceOverride(c, opts)
-    if err != nil {
-        return nil, fmt.Errorf("Error checking for nonce override: %w", err)
-    }
-
-    // Submit proposal
-    proposalId, hash, err := trustednode.ProposeProposalCooldownTime(rp, proposalCooldownTimespan, opts)
-    if err != nil {
-        return nil, err
-    }
-    response.ProposalId = proposalId
-    response.TxHash = hash
-
-    // Return response
-    return &response, nil
-
-}
-
-
-func canProposeSettingProposalVoteTimespan(c *cli.Context, proposalVoteTimespan uint64) (*api.CanProposeTNDAOSettingResponse, error) {
-
-    // Get services
-    if err := services.RequireNodeTrusted(c); err != nil { return nil, err }
-    w, err := services.GetWallet(c)
-    if err != nil { return nil, err }
-    rp, err := services.GetRocketPool(c)
+    rpcClient, err := services.GetEthRPCClient(c)
     if err != nil { return nil, err }
 
-    response, err := canProposeSetting(c, w, rp)
+    // Get & validate setting
+    setting, err := rputils.GetTNDAOSetting(name)
     if err != nil {
         return nil, err
     }
-
-    // Get gas estimate
-    opts, err := w.GetNodeAccountTransactor()
-    if err != nil { 
-        return nil, err 
-    }
-    gasInfo, err := trustednode.EstimateProposeProposalVoteTimeGas(rp, proposalVoteTimespan, opts)
-    if err != nil {
+    if err := setting.ValidateValue(value); err != nil {
         return nil, err
     }
 
-    response.GasInfo = gasInfo
-    return response, nil
-
-}
-
-
-func proposeSettingProposalVoteTimespan(c *cli.Context, proposalVoteTimespan uint64) (*api.ProposeTNDAOSettingProposalVoteTimespanResponse, error) {
-
-    // Get services
-    if err := services.RequireNodeTrusted(c); err != nil { return nil, err }
-    w, err := services.GetWallet(c)
-    if err != nil { return nil, err }
-    rp, err := services.GetRocketPool(c)
-    if err != nil { return nil, err }
-
     // Response
-    response := api.ProposeTNDAOSettingProposalVoteTimespanResponse{}
-
-    // Get transactor
-    opts, err := w.GetNodeAccountTransactor()
-    if err != nil {
-        return nil, err
-    }
-
-    // Override the provided pending TX if requested 
-    err = eth1.CheckForNonceOverride(c, opts)
-    if err != nil {
-        return nil, fmt.Errorf("Error checking for nonce override: %w", err)
-    }
-
-    // Submit proposal
-    proposalId, hash, err := trustednode.ProposeProposalVoteTime(rp, proposalVoteTimespan, opts)
-    if err != nil {
-        return nil, err
-    }
-    response.ProposalId = proposalId
-    response.TxHash = hash
-
-    // Return response
-    return &response, nil
-
-}
-
-
-func canProposeSettingProposalVoteDelayTimespan(c *cli.Context, proposalDelayTimespan uint64) (*api.CanProposeTNDAOSettingResponse, error) {
-
-    // Get services
-    if err := services.RequireNodeTrusted(c); err != nil { return nil, err }
-    w, err := services.GetWallet(c)
-    if err != nil { return nil, err }
-    rp, err := services.GetRocketPool(c)
-    if err != nil { return nil, err }
+    response := api.CanProposeTNDAOSettingResponse{}
 
-    response, err := canProposeSetting(c, w, rp)
+    // Get node account
+    nodeAccount, err := w.GetNodeAccount()
     if err != nil {
         return nil, err
     }
 
-    // Get gas estimate
-    opts, err := w.GetNodeAccountTransactor()
-    if err != nil { 
-        return nil, err 
-    }
-    gasInfo, err := trustednode.EstimateProposeProposalVoteDelayTimeGas(rp, proposalDelayTimespan, opts)
+    // Check if proposal cooldown is active
+    proposalCooldownActive, err := getProposalCooldownActive(rp, nodeAccount.Address)
     if err != nil {
         return nil, err
     }
+    response.ProposalCooldownActive = proposalCooldownActive
 
-    response.GasInfo = gasInfo
-    return response, nil
-
-}
-
-
-func proposeSettingProposalVoteDelayTimespan(c *cli.Context, proposalDelayTimespan uint64) (*api.ProposeTNDAOSettingProposalVoteDelayTimespanResponse, error) {
-
-    // Get services
-    if err := services.RequireNodeTrusted(c); err != nil { return nil, err }
-    w, err := services.GetWallet(c)
-    if err != nil { return nil, err }
-    rp, err := services.GetRocketPool(c)
-    if err != nil { return nil, err }
-
-    // Response
-    response := api.ProposeTNDAOSettingProposalVoteDelayTimespanResponse{}
-
-    // Get transactor
-    opts, err := w.GetNodeAccountTransactor()
+    // Simulate proposal execution
+    payload, err := rputils.EncodeTNDAOSettingProposalPayload(rp, setting, value)
     if err != nil {
         return nil, err
     }
-
-    // Override the provided pending TX if requested 
-    err = eth1.CheckForNonceOverride(c, opts)
-    if err != nil {
-        return nil, fmt.Errorf("Error checking for nonce override: %w", err)
-    }
-
-    // Submit proposal
-    proposalId, hash, err := trustednode.ProposeProposalVoteDelayTime(rp, proposalDelayTimespan, opts)
-    if err != nil {
-        return nil, err
-    }
-    response.ProposalId = proposalId
-    response.TxHash = hash
-
-    // Return response
-    return &response, nil
-
-}
-
-
-func canProposeSettingProposalExecuteTimespan(c *cli.Context, proposalExecuteTimespan uint64) (*api.CanProposeTNDAOSettingResponse, error) {
-
-    // Get services
-    if err := services.RequireNodeTrusted(c); err != nil { return nil, err }
-    w, err := services.GetWallet(c)
-    if err != nil { return nil, err }
-    rp, err := services.GetRocketPool(c)
-    if err != nil { return nil, err }
-
-    response, err := canProposeSetting(c, w, rp)
+    response.Simulation, err = rputils.SimulateTNDAOProposal(rp, rpcClient, payload, nil)
     if err != nil {
         return nil, err
     }
 
     // Get gas estimate
     opts, err := w.GetNodeAccountTransactor()
-    if err != nil { 
-        return nil, err 
-    }
-    gasInfo, err := trustednode.EstimateProposeProposalExecuteTimeGas(rp, proposalExecuteTimespan, opts)
     if err != nil {
         return nil, err
     }
-
-    response.GasInfo = gasInfo
-    return response, nil
-
-}
-
-
-func proposeSettingProposalExecuteTimespan(c *cli.Context, proposalExecuteTimespan uint64) (*api.ProposeTNDAOSettingProposalExecuteTimespanResponse, error) {
-
-    // Get services
-    if err := services.RequireNodeTrusted(c); err != nil { return nil, err }
-    w, err := services.GetWallet(c)
-    if err != nil { return nil, err }
-    rp, err := services.GetRocketPool(c)
-    if err != nil { return nil, err }
-
-    // Response
-    response := api.ProposeTNDAOSettingProposalExecuteTimespanResponse{}
-
-    // Get transactor
-    opts, err := w.GetNodeAccountTransactor()
-    if err != nil {
-        return nil, err
+    message := fmt.Sprintf("set %s", setting.Name)
+    if setting.Type == rputils.TNDAOSettingTypeBool {
+        response.GasInfo, err = trustednode.EstimateProposeSetBoolGas(rp, message, setting.ContractName, setting.Name, (value.Sign() != 0), opts)
+    } else {
+        response.GasInfo, err = trustednode.EstimateProposeSetUintGas(rp, message, setting.ContractName, setting.Name, value, opts)
     }
-
-    // Override the provided pending TX if requested 
-    err = eth1.CheckForNonceOverride(c, opts)
-    if err != nil {
-        return nil, fmt.Errorf("Error checking for nonce override: %w", err)
-    }
-
-    // Submit proposal
-    proposalId, hash, err := trustednode.ProposeProposalExecuteTime(rp, proposalExecuteTimespan, opts)
     if err != nil {
         return nil, err
     }
-    response.ProposalId = proposalId
-    response.TxHash = hash
 
-    // Return response
+    // Update & return response
+    response.CanPropose = !response.ProposalCooldownActive
     return &response, nil
 
 }
 
 
-func canProposeSettingProposalActionTimespan(c *cli.Context, proposalActionTimespan uint64) (*api.CanProposeTNDAOSettingResponse, error) {
+func proposeSetting(c *cli.Context, name string, value *big.Int) (*api.ProposeTNDAOSettingResponse, error) {
 
     // Get services
     if err := services.RequireNodeTrusted(c); err != nil { return nil, err }
@@ -525,38 +91,17 @@ func canProposeSettingProposalActionTimespan(c *cli.Context, proposalActionTimes
     rp, err := services.GetRocketPool(c)
     if err != nil { return nil, err }
 
-    response, err := canProposeSetting(c, w, rp)
+    // Get & validate setting
+    setting, err := rputils.GetTNDAOSetting(name)
     if err != nil {
         return nil, err
     }
-
-    // Get gas estimate
-    opts, err := w.GetNodeAccountTransactor()
-    if err != nil { 
-        return nil, err 
-    }
-    gasInfo, err := trustednode.EstimateProposeProposalActionTimeGas(rp, proposalActionTimespan, opts)
-    if err != nil {
+    if err := setting.ValidateValue(value); err != nil {
         return nil, err
     }
 
-    response.GasInfo = gasInfo
-    return response, nil
-
-}
-
-
-func proposeSettingProposalActionTimespan(c *cli.Context, proposalActionTimespan uint64) (*api.ProposeTNDAOSettingProposalActionTimespanResponse, error) {
-
-    // Get services
-    if err := services.RequireNodeTrusted(c); err != nil { return nil, err }
-    w, err := services.GetWallet(c)
-    if err != nil { return nil, err }
-    rp, err := services.GetRocketPool(c)
-    if err != nil { return nil, err }
-
     // Response
-    response := api.ProposeTNDAOSettingProposalActionTimespanResponse{}
+    response := api.ProposeTNDAOSettingResponse{}
 
     // Get transactor
     opts, err := w.GetNodeAccountTransactor()
@@ -564,90 +109,24 @@ func proposeSettingProposalActionTimespan(c *cli.Context, proposalActionTimespan
         return nil, err
     }
 
-    // Override the provided pending TX if requested 
+    // Override the provided pending TX if requested
     err = eth1.CheckForNonceOverride(c, opts)
     if err != nil {
         return nil, fmt.Errorf("Error checking for nonce override: %w", err)
     }
 
     // Submit proposal
-    proposalId, hash, err := trustednode.ProposeProposalActionTime(rp, proposalActionTimespan, opts)
-    if err != nil {
-        return nil, err
-    }
-    response.ProposalId = proposalId
-    response.TxHash = hash
-
-    // Return response
-    return &response, nil
-
-}
-
-
-func canProposeSettingScrubPeriod(c *cli.Context, scrubPeriod uint64) (*api.CanProposeTNDAOSettingResponse, error) {
-
-    // Get services
-    if err := services.RequireNodeTrusted(c); err != nil { return nil, err }
-    w, err := services.GetWallet(c)
-    if err != nil { return nil, err }
-    rp, err := services.GetRocketPool(c)
-    if err != nil { return nil, err }
-
-    response, err := canProposeSetting(c, w, rp)
-    if err != nil {
-        return nil, err
-    }
-
-    // Get gas estimate
-    opts, err := w.GetNodeAccountTransactor()
-    if err != nil { 
-        return nil, err 
+    message := fmt.Sprintf("set %s", setting.Name)
+    if setting.Type == rputils.TNDAOSettingTypeBool {
+        response.ProposalId, response.TxHash, err = trustednode.ProposeSetBool(rp, message, setting.ContractName, setting.Name, (value.Sign() != 0), opts)
+    } else {
+        response.ProposalId, response.TxHash, err = trustednode.ProposeSetUint(rp, message, setting.ContractName, setting.Name, value, opts)
     }
-    gasInfo, err := trustednode.EstimateProposeScrubPeriodGas(rp, scrubPeriod, opts)
     if err != nil {
         return nil, err
     }
 
-    response.GasInfo = gasInfo
-    return response, nil
-
-}
-
-
-func proposeSettingScrubPeriod(c *cli.Context, scrubPeriod uint64) (*api.ProposeTNDAOSettingScrubPeriodResponse, error) {
-
-    // Get services
-    if err := services.RequireNodeTrusted(c); err != nil { return nil, err }
-    w, err := services.GetWallet(c)
-    if err != nil { return nil, err }
-    rp, err := services.GetRocketPool(c)
-    if err != nil { return nil, err }
-
-    // Response
-    response := api.ProposeTNDAOSettingScrubPeriodResponse{}
-
-    // Get transactor
-    opts, err := w.GetNodeAccountTransactor()
-    if err != nil {
-        return nil, err
-    }
-
-    // Override the provided pending TX if requested 
-    err = eth1.CheckForNonceOverride(c, opts)
-    if err != nil {
-        return nil, fmt.Errorf("Error checking for nonce override: %w", err)
-    }
-
-    // Submit proposal
-    proposalId, hash, err := trustednode.ProposeScrubPeriod(rp, scrubPeriod, opts)
-    if err != nil {
-        return nil, err
-    }
-    response.ProposalId = proposalId
-    response.TxHash = hash
-
     // Return response
     return &response, nil
 
 }
-
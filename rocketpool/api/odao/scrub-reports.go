@@ -0,0 +1,56 @@
+package odao
+
+import (
+	"github.com/rocket-pool/rocketpool-go/minipool"
+	"github.com/urfave/cli"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/rocket-pool/smartnode/shared/services"
+	"github.com/rocket-pool/smartnode/shared/types/api"
+)
+
+
+func getScrubReports(c *cli.Context) (*api.TNDAOScrubReportsResponse, error) {
+
+    // Get services
+    if err := services.RequireRocketStorage(c); err != nil { return nil, err }
+    rp, err := services.GetRocketPool(c)
+    if err != nil { return nil, err }
+    evidence, err := services.GetScrubEvidenceStore(c)
+    if err != nil { return nil, err }
+
+    // Response
+    response := api.TNDAOScrubReportsResponse{}
+
+    // Get evidence reports
+    reports, err := evidence.GetAll()
+    if err != nil {
+        return nil, err
+    }
+    response.Reports = make([]api.TNDAOScrubReport, len(reports))
+
+    // Get the current status of each minipool
+    var wg errgroup.Group
+    for ri, report := range reports {
+        ri, report := ri, report
+        response.Reports[ri].Evidence = report
+        wg.Go(func() error {
+            mp, err := minipool.NewMinipool(rp, report.Minipool)
+            if err != nil {
+                return err
+            }
+            status, err := mp.GetStatus(nil)
+            if err == nil {
+                response.Reports[ri].MinipoolStatus = status
+            }
+            return err
+        })
+    }
+    if err := wg.Wait(); err != nil {
+        return nil, err
+    }
+
+    // Return response
+    return &response, nil
+
+}
@@ -0,0 +1,41 @@
+package odao
+
+import (
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services"
+	"github.com/rocket-pool/smartnode/shared/types/api"
+	apiutils "github.com/rocket-pool/smartnode/shared/utils/api"
+	rputils "github.com/rocket-pool/smartnode/shared/utils/rp"
+)
+
+
+func getSubmissions(c *cli.Context, submissionType string, fromBlock, toBlock, lateBlocks uint64) (*api.TNDAOSubmissionsResponse, error) {
+
+    // Get services
+    if err := services.RequireRocketStorage(c); err != nil { return nil, err }
+    cfg, err := services.GetConfig(c)
+    if err != nil { return nil, err }
+    rp, err := services.GetRocketPool(c)
+    if err != nil { return nil, err }
+
+    // Response
+    response := api.TNDAOSubmissionsResponse{}
+
+    // Get the event log interval
+    eventLogInterval, err := apiutils.GetEventLogInterval(cfg)
+    if err != nil {
+        return nil, err
+    }
+
+    // Audit submissions
+    audit, err := rputils.AuditTNDAOSubmissions(rp, submissionType, fromBlock, toBlock, lateBlocks, eventLogInterval)
+    if err != nil {
+        return nil, err
+    }
+    response.Audit = audit
+
+    // Return response
+    return &response, nil
+
+}
@@ -49,6 +49,39 @@ func getProposalCooldownActive(rp *rocketpool.RocketPool, nodeAddress common.Add
 }
 
 
+// Check if the challenge cooldown for an oracle node is active
+func getChallengeCooldownActive(rp *rocketpool.RocketPool, nodeAddress common.Address) (bool, error) {
+
+    // Data
+    var wg errgroup.Group
+    var lastProposalTime uint64
+    var challengeCooldown uint64
+
+    // Get last proposal time; making a challenge also updates it
+    wg.Go(func() error {
+        var err error
+        lastProposalTime, err = tndao.GetMemberLastProposalTime(rp, nodeAddress, nil)
+        return err
+    })
+
+    // Get challenge cooldown
+    wg.Go(func() error {
+        var err error
+        challengeCooldown, err = tnsettings.GetChallengeCooldown(rp, nil)
+        return err
+    })
+
+    // Wait for data
+    if err := wg.Wait(); err != nil {
+        return false, err
+    }
+
+    // Return
+    return ((uint64(time.Now().Unix()) - lastProposalTime) < challengeCooldown), nil
+
+}
+
+
 // Check if a proposal for a node exists & is actionable
 func getProposalIsActionable(rp *rocketpool.RocketPool, nodeAddress common.Address, proposalType string) (bool, error) {
 
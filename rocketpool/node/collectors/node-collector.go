@@ -21,15 +21,12 @@ import (
 	"github.com/rocket-pool/rocketpool-go/utils/eth"
 	"github.com/rocket-pool/smartnode/shared/services/beacon"
 	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/multicall"
 	"github.com/rocket-pool/smartnode/shared/utils/api"
 	"github.com/rocket-pool/smartnode/shared/utils/rp"
 	"golang.org/x/sync/errgroup"
 )
 
-// Settings
-const MinipoolBalanceDetailsBatchSize = 20
-
-
 // Beacon chain balance info for a minipool
 type minipoolBalanceDetails struct {
     IsStaking bool
@@ -83,6 +80,9 @@ type NodeCollector struct {
 	// The beacon client
 	bc 					    beacon.Client
 
+    // The multicaller for batched contract reads
+    mc                      *multicall.MultiCaller
+
     // The node's address
     nodeAddress             common.Address
 
@@ -98,7 +98,7 @@ type NodeCollector struct {
 
 
 // Create a new NodeCollector instance
-func NewNodeCollector(rp *rocketpool.RocketPool, bc beacon.Client, nodeAddress common.Address, cfg config.RocketPoolConfig) *NodeCollector {
+func NewNodeCollector(rp *rocketpool.RocketPool, bc beacon.Client, mc *multicall.MultiCaller, nodeAddress common.Address, cfg config.RocketPoolConfig) *NodeCollector {
 	
     // Get the event log interval
     eventLogInterval, err := api.GetEventLogInterval(cfg)
@@ -159,6 +159,7 @@ func NewNodeCollector(rp *rocketpool.RocketPool, bc beacon.Client, nodeAddress c
 		),
 		rp: rp,
         bc: bc,
+        mc: mc,
         nodeAddress: nodeAddress,
         eventLogInterval: eventLogInterval,
 	}
@@ -327,7 +328,7 @@ func (collector *NodeCollector) Collect(channel chan<- prometheus.Metric) {
 
     // Get the list of minipool addresses for this node
     wg.Go(func() error {
-        _addresses, err := minipool.GetNodeMinipoolAddresses(collector.rp, collector.nodeAddress, nil)
+        _addresses, err := rp.GetNodeMinipoolAddresses(collector.rp, collector.mc, collector.nodeAddress, nil)
         if err != nil {
             return fmt.Errorf("Error getting node minipool addresses: %w", err)
         }
@@ -434,125 +435,87 @@ func (collector *NodeCollector) Collect(channel chan<- prometheus.Metric) {
 func (collector *NodeCollector) getBeaconBalances(addresses []common.Address, beaconHead beacon.BeaconHead, opts *bind.CallOpts) ([]minipoolBalanceDetails, error) {
 
     // Get minipool validator statuses
-    validators, err := rp.GetMinipoolValidators(collector.rp, collector.bc, addresses, opts, &beacon.ValidatorStatusOptions{Epoch: beaconHead.Epoch})
+    validators, err := rp.GetMinipoolValidators(collector.rp, collector.mc, collector.bc, addresses, opts, &beacon.ValidatorStatusOptions{Epoch: beaconHead.Epoch})
+    if err != nil {
+        return []minipoolBalanceDetails{}, err
+    }
+
+    // Get minipool contracts
+    minipools, err := rp.GetMinipools(collector.rp, addresses)
     if err != nil {
         return []minipoolBalanceDetails{}, err
     }
 
-    // Load details in batches
-    details := make([]minipoolBalanceDetails, len(addresses))
-    for bsi := 0; bsi < len(addresses); bsi += MinipoolBalanceDetailsBatchSize {
-
-        // Get batch start & end index
-        msi := bsi
-        mei := bsi + MinipoolBalanceDetailsBatchSize
-        if mei > len(addresses) { mei = len(addresses) }
-
-        // Load details
-        var wg errgroup.Group
-        for mi := msi; mi < mei; mi++ {
-            mi := mi
-            wg.Go(func() error {
-                address := addresses[mi]
-                validator := validators[address]
-                mpDetails, err := collector.getMinipoolBalanceDetails(address, opts, validator, beaconHead.Epoch)
-                if err == nil { details[mi] = mpDetails }
-                return err
-            })
+    // Load minipool statuses, node deposit balances & finalized states
+    statuses := make([]uint8, len(minipools))
+    nodeDepositBalances := make([]*big.Int, len(minipools))
+    finalized := make([]bool, len(minipools))
+    batch := collector.mc.NewBatch()
+    for mi, mp := range minipools {
+        if err := batch.AddCall(mp.Contract, &statuses[mi], "getStatus"); err != nil {
+            return []minipoolBalanceDetails{}, err
         }
-        if err := wg.Wait(); err != nil {
+        if err := batch.AddCall(mp.Contract, &nodeDepositBalances[mi], "getNodeDepositBalance"); err != nil {
+            return []minipoolBalanceDetails{}, err
+        }
+        if err := batch.AddCall(mp.Contract, &finalized[mi], "getFinalised"); err != nil {
             return []minipoolBalanceDetails{}, err
         }
-
     }
-    
-    // Return
-    return details, nil
-}
-
-
-// Get minipool balance details
-func (collector *NodeCollector) getMinipoolBalanceDetails(minipoolAddress common.Address, opts *bind.CallOpts, validator beacon.ValidatorStatus, blockEpoch uint64) (minipoolBalanceDetails, error) {
-
-    // Create minipool
-    mp, err := minipool.NewMinipool(collector.rp, minipoolAddress)
-    if err != nil {
-        return minipoolBalanceDetails{}, err
+    if err := batch.Execute(opts); err != nil {
+        return []minipoolBalanceDetails{}, err
     }
-    blockBalance := eth.GweiToWei(float64(validator.Balance))
-
-    // Data
-    var wg errgroup.Group
-    var status types.MinipoolStatus
-    var nodeDepositBalance *big.Int
-    var finalized bool
 
-    // Load data
-    wg.Go(func() error {
-        var err error
-        status, err = mp.GetStatus(opts)
-        return err
-    })
-    wg.Go(func() error {
-        var err error
-        nodeDepositBalance, err = mp.GetNodeDepositBalance(opts)
-        return err
-    })
-    wg.Go(func() error {
-        var err error
-        finalized, err = mp.GetFinalised(opts)
-        return err
-    })
-
-    // Wait for data
-    if err := wg.Wait(); err != nil {
-        return minipoolBalanceDetails{}, err
-    }
+    // Get balance details; the node shares of active validators' balances are calculated in a second batch
+    details := make([]minipoolBalanceDetails, len(minipools))
+    shareBatch := collector.mc.NewBatch()
+    for mi, mp := range minipools {
+        validator := validators[mp.Address]
+        status := types.MinipoolStatus(statuses[mi])
+        blockBalance := eth.GweiToWei(float64(validator.Balance))
+
+        // Deal with pools that haven't received deposits yet so their balance is still 0
+        nodeDepositBalance := nodeDepositBalances[mi]
+        if nodeDepositBalance == nil {
+            nodeDepositBalance = big.NewInt(0)
+        }
 
-    // Deal with pools that haven't received deposits yet so their balance is still 0
-    if nodeDepositBalance == nil {
-        nodeDepositBalance = big.NewInt(0)
-    }
+        // Ignore finalized minipools
+        if finalized[mi] {
+            details[mi] = minipoolBalanceDetails{
+                NodeDeposit: big.NewInt(0),
+                NodeBalance: big.NewInt(0),
+                TotalBalance: big.NewInt(0),
+            }
+            continue
+        }
 
-    // Ignore finalized minipools
-    if finalized {
-        return minipoolBalanceDetails{
-            NodeDeposit: big.NewInt(0),
-            NodeBalance: big.NewInt(0),
-            TotalBalance: big.NewInt(0),
-        }, nil
-    }
+        // Use node deposit balance if initialized or prelaunch, or if validator not yet active on beacon chain at block
+        if status == types.Initialized || status == types.Prelaunch || !validator.Exists || validator.ActivationEpoch >= beaconHead.Epoch {
+            details[mi] = minipoolBalanceDetails{
+                NodeDeposit: nodeDepositBalance,
+                NodeBalance: nodeDepositBalance,
+                TotalBalance: blockBalance,
+            }
+            continue
+        }
 
-    // Use node deposit balance if initialized or prelaunch
-    if status == types.Initialized || status == types.Prelaunch {
-        return minipoolBalanceDetails{
+        // Get node balance at block
+        details[mi] = minipoolBalanceDetails{
+            IsStaking: (validator.ExitEpoch > beaconHead.Epoch),
             NodeDeposit: nodeDepositBalance,
-            NodeBalance: nodeDepositBalance,
             TotalBalance: blockBalance,
-        }, nil
-    }
+        }
+        if err := shareBatch.AddCall(mp.Contract, &details[mi].NodeBalance, "calculateNodeShare", blockBalance); err != nil {
+            return []minipoolBalanceDetails{}, err
+        }
 
-    // Use node deposit balance if validator not yet active on beacon chain at block
-    if !validator.Exists || validator.ActivationEpoch >= blockEpoch {
-        return minipoolBalanceDetails{
-            NodeDeposit: nodeDepositBalance,
-            NodeBalance: nodeDepositBalance,
-            TotalBalance: blockBalance,
-        }, nil
     }
-
-    // Get node balance at block
-    nodeBalance, err := mp.CalculateNodeShare(blockBalance, opts)
-    if err != nil {
-        return minipoolBalanceDetails{}, err
+    if err := shareBatch.Execute(opts); err != nil {
+        return []minipoolBalanceDetails{}, err
     }
 
     // Return
-    return minipoolBalanceDetails{
-        IsStaking: (validator.ExitEpoch > blockEpoch),
-        NodeDeposit: nodeDepositBalance,
-        NodeBalance: nodeBalance,
-        TotalBalance: blockBalance,
-    }, nil
+    return details, nil
 
 }
@@ -0,0 +1,122 @@
+package collectors
+
+import (
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rocket-pool/rocketpool-go/rocketpool"
+	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/utils/api"
+	rputils "github.com/rocket-pool/smartnode/shared/utils/rp"
+)
+
+// How often the submissions are audited
+const submissionsAuditInterval = 5 * time.Minute
+
+// Represents the collector for the ODAO submissions audit
+type SubmissionsCollector struct {
+	// The number of each submission result per member over the latest rounds
+	submissionResults *prometheus.Desc
+
+	// The round block of each member's latest submission
+	latestSubmittedBlock *prometheus.Desc
+
+	// The Rocket Pool contract manager
+	rp *rocketpool.RocketPool
+
+	// Cached data
+	cacheLock sync.Mutex
+	cacheTime time.Time
+	cachedMetrics []prometheus.Metric
+
+	// The event log interval for the current eth1 client
+	eventLogInterval *big.Int
+}
+
+// Create a new SubmissionsCollector instance
+func NewSubmissionsCollector(rp *rocketpool.RocketPool, cfg config.RocketPoolConfig) *SubmissionsCollector {
+
+	// Get the event log interval
+	eventLogInterval, err := api.GetEventLogInterval(cfg)
+	if err != nil {
+		log.Printf("Error getting event log interval: %s\n", err.Error())
+		return nil
+	}
+
+	subsystem := "odao_submissions"
+	return &SubmissionsCollector{
+		submissionResults: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "results"),
+			"The number of on-time, late, diverged and missing submissions by each member over the latest rounds",
+			[]string{"type", "member", "result"}, nil,
+		),
+		latestSubmittedBlock: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "latest_submitted_block"),
+			"The round block of each member's latest submission",
+			[]string{"type", "member"}, nil,
+		),
+		rp:               rp,
+		eventLogInterval: eventLogInterval,
+	}
+}
+
+// Write metric descriptions to the Prometheus channel
+func (collector *SubmissionsCollector) Describe(channel chan<- *prometheus.Desc) {
+	channel <- collector.submissionResults
+	channel <- collector.latestSubmittedBlock
+}
+
+// Audits the latest submissions; this scans event logs, so the results are cached
+func (collector *SubmissionsCollector) collectSlowMetrics() {
+
+	// Create a new cached metrics array to populate
+	metrics := make([]prometheus.Metric, 0)
+
+	for _, submissionType := range []string{rputils.TNDAOSubmissionTypeBalances, rputils.TNDAOSubmissionTypePrices} {
+
+		// Audit submissions
+		audit, err := rputils.AuditTNDAOSubmissions(collector.rp, submissionType, 0, 0, rputils.TNDAOSubmissionsDefaultLateBlocks, collector.eventLogInterval)
+		if err != nil {
+			log.Printf("Error auditing %s submissions: %s\n", submissionType, err.Error())
+			return
+		}
+
+		// Submission results
+		for _, member := range audit.Members {
+			memberId := member.Id
+			if memberId == "" {
+				memberId = member.Address.Hex()
+			}
+			metrics = append(metrics,
+				prometheus.MustNewConstMetric(collector.submissionResults, prometheus.GaugeValue, float64(member.OnTime), submissionType, memberId, rputils.TNDAOSubmissionOnTime),
+				prometheus.MustNewConstMetric(collector.submissionResults, prometheus.GaugeValue, float64(member.Late), submissionType, memberId, rputils.TNDAOSubmissionLate),
+				prometheus.MustNewConstMetric(collector.submissionResults, prometheus.GaugeValue, float64(member.Diverged), submissionType, memberId, rputils.TNDAOSubmissionDiverged),
+				prometheus.MustNewConstMetric(collector.submissionResults, prometheus.GaugeValue, float64(member.Missing), submissionType, memberId, rputils.TNDAOSubmissionMissing),
+				prometheus.MustNewConstMetric(collector.latestSubmittedBlock, prometheus.GaugeValue, float64(member.LatestSubmittedBlock), submissionType, memberId),
+			)
+		}
+
+	}
+
+	collector.cachedMetrics = metrics
+}
+
+// Collect the latest metric values and pass them to Prometheus
+func (collector *SubmissionsCollector) Collect(channel chan<- prometheus.Metric) {
+
+	collector.cacheLock.Lock()
+	defer collector.cacheLock.Unlock()
+
+	// Only audit submissions periodically as they update infrequently and take a while to collect
+	now := time.Now()
+	if now.After(collector.cacheTime.Add(submissionsAuditInterval)) {
+		collector.collectSlowMetrics()
+		collector.cacheTime = now
+	}
+
+	// Include cached metrics
+	for _, metric := range collector.cachedMetrics {
+		channel <- metric
+	}
+}
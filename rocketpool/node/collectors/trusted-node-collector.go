@@ -19,7 +19,10 @@ import (
 	"github.com/rocket-pool/rocketpool-go/utils/eth"
 	"github.com/rocket-pool/smartnode/shared/services/beacon"
 	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/multicall"
+	apitypes "github.com/rocket-pool/smartnode/shared/types/api"
 	"github.com/rocket-pool/smartnode/shared/utils/api"
+	rputils "github.com/rocket-pool/smartnode/shared/utils/rp"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -43,12 +46,24 @@ type TrustedNodeCollector struct {
 	// The prices submission participation of the ODAO members
 	pricesParticipation *prometheus.Desc
 
+	// The number of active challenges against ODAO members
+	challengeCount *prometheus.Desc
+
+	// Whether each ODAO member has an active challenge against it
+	memberChallenged *prometheus.Desc
+
+	// The blocks remaining for each challenged ODAO member to respond
+	challengeBlocksRemaining *prometheus.Desc
+
 	// The Rocket Pool contract manager
 	rp *rocketpool.RocketPool
 
 	// The beacon client
 	bc beacon.Client
 
+	// The multicaller
+	mc *multicall.MultiCaller
+
 	// The node's address
 	nodeAddress common.Address
 
@@ -61,7 +76,7 @@ type TrustedNodeCollector struct {
 }
 
 // Create a new NodeCollector instance
-func NewTrustedNodeCollector(rp *rocketpool.RocketPool, bc beacon.Client, nodeAddress common.Address, cfg config.RocketPoolConfig) *TrustedNodeCollector {
+func NewTrustedNodeCollector(rp *rocketpool.RocketPool, bc beacon.Client, mc *multicall.MultiCaller, nodeAddress common.Address, cfg config.RocketPoolConfig) *TrustedNodeCollector {
 	
     // Get the event log interval
     eventLogInterval, err := api.GetEventLogInterval(cfg)
@@ -96,8 +111,21 @@ func NewTrustedNodeCollector(rp *rocketpool.RocketPool, bc beacon.Client, nodeAd
 			"Whether each member has participated in the current prices update interval",
 			[]string{"member"}, nil,
 		),
+		challengeCount: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "challenge_count"),
+			"The number of active challenges against members",
+			nil, nil,
+		),
+		memberChallenged: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "member_challenged"),
+			"Whether each member has an active challenge against it",
+			[]string{"member"}, nil,
+		),
+		challengeBlocksRemaining: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "challenge_blocks_remaining"),
+			"The number of blocks each challenged member has left to respond (0 once the member can be removed)",
+			[]string{"member"}, nil,
+		),
 		rp:          rp,
 		bc:          bc,
+		mc:          mc,
 		nodeAddress: nodeAddress,
         eventLogInterval: eventLogInterval,
 	}
@@ -111,6 +139,9 @@ func (collector *TrustedNodeCollector) Describe(channel chan<- *prometheus.Desc)
 	channel <- collector.ethBalance
 	channel <- collector.balancesParticipation
 	channel <- collector.pricesParticipation
+	channel <- collector.challengeCount
+	channel <- collector.memberChallenged
+	channel <- collector.challengeBlocksRemaining
 }
 
 // Caches slow to process metrics so it doesn't have to be processed every second
@@ -178,6 +209,7 @@ func (collector *TrustedNodeCollector) Collect(channel chan<- prometheus.Metric)
 	var err error
 
 	var proposals []dao.ProposalDetails
+	var challenges []apitypes.TNDAOChallenge
 	memberIds := make(map[common.Address]string)
 	ethBalances := make(map[string]float64)
 
@@ -211,6 +243,16 @@ func (collector *TrustedNodeCollector) Collect(channel chan<- prometheus.Metric)
 		return nil
 	})
 
+	// Get active challenges
+	wg.Go(func() error {
+		var err error
+		challenges, _, err = rputils.GetTNDAOChallenges(collector.rp, collector.mc, nil)
+		if err != nil {
+			return fmt.Errorf("Error getting challenges: %w", err)
+		}
+		return nil
+	})
+
 	// Only collect fresh participation metrics from chain every 60 seconds as it updates infrequently and takes longer to collect
 	now := time.Now()
 	if now.Unix() > collector.cacheTime.Add(time.Second*60).Unix() {
@@ -296,6 +338,29 @@ func (collector *TrustedNodeCollector) Collect(channel chan<- prometheus.Metric)
 			collector.ethBalance, prometheus.GaugeValue, balance, memberId)
 	}
 
+	// Update challenge metrics
+	challengedMembers := make(map[common.Address]apitypes.TNDAOChallenge)
+	for _, challenge := range challenges {
+		challengedMembers[challenge.MemberAddress] = challenge
+	}
+	channel <- prometheus.MustNewConstMetric(
+		collector.challengeCount, prometheus.GaugeValue, float64(len(challenges)))
+	for address, memberId := range memberIds {
+		challenge, challenged := challengedMembers[address]
+		value := float64(0)
+		blocksRemaining := float64(0)
+		if challenged {
+			value = 1
+			blocksRemaining = float64(challenge.BlocksRemaining)
+		}
+		channel <- prometheus.MustNewConstMetric(
+			collector.memberChallenged, prometheus.GaugeValue, value, memberId)
+		if challenged {
+			channel <- prometheus.MustNewConstMetric(
+				collector.challengeBlocksRemaining, prometheus.GaugeValue, blocksRemaining, memberId)
+		}
+	}
+
 	// Update proposal metrics
 	for _, proposal := range proposals {
 		if proposal.State != types.Active {
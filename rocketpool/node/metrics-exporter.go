@@ -12,7 +12,7 @@ import (
 	"github.com/urfave/cli"
 )
 
-func runMetricsServer(c *cli.Context, logger log.ColorLogger) (error) {
+func runMetricsServer(c *cli.Context, logger log.Logger) (error) {
 
     // Get services
     cfg, err := services.GetConfig(c)
@@ -25,6 +25,8 @@ func runMetricsServer(c *cli.Context, logger log.ColorLogger) (error) {
     if err != nil { return err }
     ec, err := services.GetEthClient(c)
     if err != nil { return err }
+    mc, err := services.GetMultiCaller(c)
+    if err != nil { return err }
 
     // Return if metrics are disabled
     if !cfg.Metrics.Enabled {
@@ -42,9 +44,10 @@ func runMetricsServer(c *cli.Context, logger log.ColorLogger) (error) {
     supplyCollector := collectors.NewSupplyCollector(rp)
     rplCollector := collectors.NewRplCollector(rp)
     odaoCollector := collectors.NewOdaoCollector(rp)
-    nodeCollector := collectors.NewNodeCollector(rp, bc, nodeAccount.Address, cfg)
-    trustedNodeCollector := collectors.NewTrustedNodeCollector(rp, bc, nodeAccount.Address, cfg)
+    nodeCollector := collectors.NewNodeCollector(rp, bc, mc, nodeAccount.Address, cfg)
+    trustedNodeCollector := collectors.NewTrustedNodeCollector(rp, bc, mc, nodeAccount.Address, cfg)
     beaconCollector := collectors.NewBeaconCollector(rp, bc, ec, nodeAccount.Address)
+    submissionsCollector := collectors.NewSubmissionsCollector(rp, cfg)
 
     // Set up Prometheus
     registry := prometheus.NewRegistry()
@@ -56,12 +59,13 @@ func runMetricsServer(c *cli.Context, logger log.ColorLogger) (error) {
     registry.MustRegister(nodeCollector)
     registry.MustRegister(trustedNodeCollector)
     registry.MustRegister(beaconCollector)
+    registry.MustRegister(submissionsCollector)
     handler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
 
     // Start the HTTP server
     metricsAddress := c.GlobalString("metricsAddress")
     metricsPort := c.GlobalUint("metricsPort")
-    logger.Printlnf("Starting metrics exporter on %s:%d.", metricsAddress, metricsPort)
+    logger.Infof("Starting metrics exporter on %s:%d.", metricsAddress, metricsPort)
     metricsPath := "/metrics"
     http.Handle(metricsPath, handler)
     http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
@@ -42,19 +42,24 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 func run(c *cli.Context) error {
 
     // Configure
+    if err := services.ConfigureLogging(c, "node"); err != nil { return err }
     configureHTTP()
 
     // Wait until node is registered
     if err := services.WaitNodeRegistered(c, true); err != nil { return err }
 
+    // Get services
+    w, err := services.GetWallet(c)
+    if err != nil { return err }
+
     // Initialize tasks
-    claimRplRewards, err := newClaimRplRewards(c, log.NewColorLogger(ClaimRplRewardsColor))
+    claimRplRewards, err := newClaimRplRewards(c, log.NewLogger("claim-rpl-rewards", ClaimRplRewardsColor))
     if err != nil { return err }
-    stakePrelaunchMinipools, err := newStakePrelaunchMinipools(c, log.NewColorLogger(StakePrelaunchMinipoolsColor))
+    stakePrelaunchMinipools, err := newStakePrelaunchMinipools(c, log.NewLogger("stake-prelaunch-minipools", StakePrelaunchMinipoolsColor))
     if err != nil { return err }
 
     // Initialize error logger
-    errorLog := log.NewColorLogger(ErrorColor)
+    errorLog := log.NewLogger("error", ErrorColor)
         
     // Wait group to handle the various threads
     wg := new(sync.WaitGroup)
@@ -63,12 +68,14 @@ func run(c *cli.Context) error {
     // Run task loop
     go func() {
        for {
+           w.SetTxOrigin("node claim-rpl-rewards")
            if err := claimRplRewards.run(); err != nil {
-               errorLog.Println(err)
+               errorLog.Error(err)
            }
            time.Sleep(taskCooldown)
+           w.SetTxOrigin("node stake-prelaunch-minipools")
            if err := stakePrelaunchMinipools.run(); err != nil {
-               errorLog.Println(err)
+               errorLog.Error(err)
            }
            time.Sleep(tasksInterval)
        }
@@ -77,9 +84,9 @@ func run(c *cli.Context) error {
 
     // Run metrics loop
     go func() {
-        err := runMetricsServer(c, log.NewColorLogger(MetricsColor))
+        err := runMetricsServer(c, log.NewLogger("metrics", MetricsColor))
         if err != nil {
-            errorLog.Println(err)
+            errorLog.Error(err)
         }
         wg.Done()
     }()
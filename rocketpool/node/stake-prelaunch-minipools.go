@@ -17,15 +17,18 @@ import (
 	"github.com/rocket-pool/rocketpool-go/settings/trustednode"
 	rptypes "github.com/rocket-pool/rocketpool-go/types"
 	"github.com/urfave/cli"
-	"golang.org/x/sync/errgroup"
 
 	"github.com/rocket-pool/smartnode/shared/services"
 	"github.com/rocket-pool/smartnode/shared/services/beacon"
 	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/deposits"
+	"github.com/rocket-pool/smartnode/shared/services/multicall"
 	rpgas "github.com/rocket-pool/smartnode/shared/services/gas"
+	"github.com/rocket-pool/smartnode/shared/services/notify"
 	"github.com/rocket-pool/smartnode/shared/services/wallet"
 	"github.com/rocket-pool/smartnode/shared/utils/api"
 	"github.com/rocket-pool/smartnode/shared/utils/log"
+	rputils "github.com/rocket-pool/smartnode/shared/utils/rp"
 	"github.com/rocket-pool/smartnode/shared/utils/validator"
 )
 
@@ -38,12 +41,15 @@ var validatorRestartTimeout, _ = time.ParseDuration("5s")
 // Stake prelaunch minipools task
 type stakePrelaunchMinipools struct {
     c *cli.Context
-    log log.ColorLogger
+    log log.Logger
     cfg config.RocketPoolConfig
     w *wallet.Wallet
     rp *rocketpool.RocketPool
     bc beacon.Client
+    mc *multicall.MultiCaller
     d *client.Client
+    deposits *deposits.Indexer
+    notifier *notify.Notifier
     gasThreshold float64
     maxFee *big.Int
     maxPriorityFee *big.Int
@@ -52,7 +58,7 @@ type stakePrelaunchMinipools struct {
 
 
 // Create stake prelaunch minipools task
-func newStakePrelaunchMinipools(c *cli.Context, logger log.ColorLogger) (*stakePrelaunchMinipools, error) {
+func newStakePrelaunchMinipools(c *cli.Context, logger log.Logger) (*stakePrelaunchMinipools, error) {
 
     // Get services
     cfg, err := services.GetConfig(c)
@@ -63,13 +69,19 @@ func newStakePrelaunchMinipools(c *cli.Context, logger log.ColorLogger) (*stakeP
     if err != nil { return nil, err }
     bc, err := services.GetBeaconClient(c)
     if err != nil { return nil, err }
+    mc, err := services.GetMultiCaller(c)
+    if err != nil { return nil, err }
     d, err := services.GetDocker(c)
     if err != nil { return nil, err }
+    depositIndexer, err := services.GetDepositIndexer(c)
+    if err != nil { return nil, err }
+    notifier, err := services.GetNotifier(c)
+    if err != nil { return nil, err }
 
     // Check if auto-staking is disabled
     gasThreshold := cfg.Smartnode.RplClaimGasThreshold
     if gasThreshold == 0 {
-        logger.Println("RPL claim gas threshold is set to 0, automatic staking of prelaunch minipools will be disabled.")
+        logger.Info("RPL claim gas threshold is set to 0, automatic staking of prelaunch minipools will be disabled.")
     }
 
     // Get the user-requested max fee
@@ -84,7 +96,7 @@ func newStakePrelaunchMinipools(c *cli.Context, logger log.ColorLogger) (*stakeP
         return nil, fmt.Errorf("Error getting max priority fee in configuration: %w", err)
     }
     if maxPriorityFee == nil || maxPriorityFee.Uint64() == 0 {
-        logger.Println("WARNING: priority fee was missing or 0, setting a default of 2.");
+        logger.Warn("Priority fee was missing or 0, setting a default of 2.");
         maxPriorityFee = big.NewInt(2)
     }
 
@@ -102,7 +114,10 @@ func newStakePrelaunchMinipools(c *cli.Context, logger log.ColorLogger) (*stakeP
         w: w,
         rp: rp,
         bc: bc,
+        mc: mc,
         d: d,
+        deposits: depositIndexer,
+        notifier: notifier,
         gasThreshold: gasThreshold,
         maxFee: maxFee,
         maxPriorityFee: maxPriorityFee,
@@ -126,7 +141,7 @@ func (t *stakePrelaunchMinipools) run() error {
     }
 
     // Log
-    t.log.Println("Checking for minipools to launch...")
+    t.log.Info("Checking for minipools to launch...")
 
     // Get node account
     nodeAccount, err := t.w.GetNodeAccount()
@@ -150,14 +165,14 @@ func (t *stakePrelaunchMinipools) run() error {
     }
 
     // Log
-    t.log.Printlnf("%d minipool(s) are ready for staking...", len(minipools))
+    t.log.Infof("%d minipool(s) are ready for staking...", len(minipools))
 
     // Stake minipools
     successCount := 0
     for _, mp := range minipools {
         success, err := t.stakeMinipool(mp, eth2Config)
         if err != nil {
-            t.log.Println(fmt.Errorf("Could not stake minipool %s: %w", mp.Address.Hex(), err))
+            t.log.WithMinipool(mp.Address).Error(fmt.Errorf("Could not stake minipool %s: %w", mp.Address.Hex(), err))
             return err
         }
         if success {
@@ -182,37 +197,30 @@ func (t *stakePrelaunchMinipools) run() error {
 func (t *stakePrelaunchMinipools) getPrelaunchMinipools(nodeAddress common.Address) ([]*minipool.Minipool, error) {
 
     // Get node minipool addresses
-    addresses, err := minipool.GetNodeMinipoolAddresses(t.rp, nodeAddress, nil)
+    addresses, err := rputils.GetNodeMinipoolAddresses(t.rp, t.mc, nodeAddress, nil)
     if err != nil {
         return []*minipool.Minipool{}, err
     }
 
-    // Create minipool contracts
-    minipools := make([]*minipool.Minipool, len(addresses))
-    for mi, address := range addresses {
-        mp, err := minipool.NewMinipool(t.rp, address)
-        if err != nil {
-            return []*minipool.Minipool{}, err
-        }
-        minipools[mi] = mp
+    // Get minipool contracts
+    minipools, err := rputils.GetMinipools(t.rp, addresses)
+    if err != nil {
+        return []*minipool.Minipool{}, err
     }
 
-    // Data
-    var wg errgroup.Group
-    statuses := make([]minipool.StatusDetails, len(minipools))
-
     // Load minipool statuses
+    statuses := make([]uint8, len(minipools))
+    statusTimes := make([]*big.Int, len(minipools))
+    batch := t.mc.NewBatch()
     for mi, mp := range minipools {
-        mi, mp := mi, mp
-        wg.Go(func() error {
-            status, err := mp.GetStatusDetails(nil)
-            if err == nil { statuses[mi] = status }
-            return err
-        })
+        if err := batch.AddCall(mp.Contract, &statuses[mi], "getStatus"); err != nil {
+            return []*minipool.Minipool{}, err
+        }
+        if err := batch.AddCall(mp.Contract, &statusTimes[mi], "getStatusTime"); err != nil {
+            return []*minipool.Minipool{}, err
+        }
     }
-
-    // Wait for data
-    if err := wg.Wait(); err != nil {
+    if err := batch.Execute(nil); err != nil {
         return []*minipool.Minipool{}, err
     }
 
@@ -233,13 +241,13 @@ func (t *stakePrelaunchMinipools) getPrelaunchMinipools(nodeAddress common.Addre
     // Filter minipools by status
     prelaunchMinipools := []*minipool.Minipool{}
     for mi, mp := range minipools {
-        if statuses[mi].Status == rptypes.Prelaunch {
-            creationTime := statuses[mi].StatusTime
+        if rptypes.MinipoolStatus(statuses[mi]) == rptypes.Prelaunch {
+            creationTime := time.Unix(statusTimes[mi].Int64(), 0)
             remainingTime := creationTime.Add(scrubPeriod).Sub(latestBlockTime)
             if remainingTime < 0 {
                 prelaunchMinipools = append(prelaunchMinipools, mp)
             } else {
-                t.log.Printlnf("Minipool %s has %s left until it can be staked.", mp.Address.Hex(), remainingTime)
+                t.log.WithMinipool(mp.Address).Infof("Minipool %s has %s left until it can be staked.", mp.Address.Hex(), remainingTime)
             }
         }
     }
@@ -254,7 +262,7 @@ func (t *stakePrelaunchMinipools) getPrelaunchMinipools(nodeAddress common.Addre
 func (t *stakePrelaunchMinipools) stakeMinipool(mp *minipool.Minipool, eth2Config beacon.Eth2Config) (bool, error) {
 
     // Log
-    t.log.Printlnf("Staking minipool %s...", mp.Address.Hex())
+    t.log.WithMinipool(mp.Address).Infof("Staking minipool %s...", mp.Address.Hex())
 
     // Get minipool withdrawal credentials
     withdrawalCredentials, err := minipool.GetMinipoolWithdrawalCredentials(t.rp, mp.Address, nil)
@@ -272,6 +280,15 @@ func (t *stakePrelaunchMinipools) stakeMinipool(mp *minipool.Minipool, eth2Confi
         return false, err
     }
 
+    // Make sure the validator hasn't been front-run with a deposit to other withdrawal credentials; skip the minipool if it has
+    conflicted, err := t.checkForConflictingDeposit(mp, validatorPubkey, withdrawalCredentials)
+    if err != nil {
+        return false, err
+    }
+    if conflicted {
+        return false, nil
+    }
+
     // Get validator deposit data
     depositData, depositDataRoot, err := validator.GetDepositData(validatorKey, withdrawalCredentials, eth2Config)
     if err != nil {
@@ -311,17 +328,17 @@ func (t *stakePrelaunchMinipools) stakeMinipool(mp *minipool.Minipool, eth2Confi
         // Check for the timeout buffer
         prelaunchTime, err := mp.GetStatusTime(nil)
         if err != nil {
-            t.log.Printlnf("Error checking minipool launch time: %s\nStaking now for safety...", err.Error())
+            t.log.Warnf("Error checking minipool launch time: %s\nStaking now for safety...", err.Error())
         }
         isDue, timeUntilDue, err := api.IsTransactionDue(t.rp, prelaunchTime)
         if err != nil {
-            t.log.Printlnf("Error checking if minipool is due: %s\nStaking now for safety...", err.Error())
+            t.log.Warnf("Error checking if minipool is due: %s\nStaking now for safety...", err.Error())
         }
         if !isDue {
-            t.log.Printlnf("Time until staking will be forced for safety: %s", timeUntilDue)
+            t.log.Infof("Time until staking will be forced for safety: %s", timeUntilDue)
             return false, nil
         } else {
-            t.log.Println("NOTICE: The minipool has exceeded half of the timeout period, so it will be force-staked at the current gas price.")
+            t.log.Info("NOTICE: The minipool has exceeded half of the timeout period, so it will be force-staked at the current gas price.")
         }
     }
 
@@ -340,13 +357,42 @@ func (t *stakePrelaunchMinipools) stakeMinipool(mp *minipool.Minipool, eth2Confi
     }
 
     // Print TX info and wait for it to be mined
-    err = api.PrintAndWaitForTransaction(t.cfg, hash, t.rp.Client, t.log)
+    err = api.PrintAndWaitForTransaction(t.cfg, hash, t.rp.Client, t.w.GetTxLog(), t.log)
     if err != nil {
         return false, err
     }
 
     // Log
-    t.log.Printlnf("Successfully staked minipool %s.", mp.Address.Hex())
+    t.log.WithMinipool(mp.Address).Infof("Successfully staked minipool %s.", mp.Address.Hex())
+
+    // Return
+    return true, nil
+
+}
+
+
+// Check for a prior deposit which would lock a minipool's validator balance to other withdrawal credentials
+// Staking would send the user deposit to the attacker's credentials, so the minipool must be skipped if one is found
+// The alert is raised on every check, as the minipool needs operator attention until it is scrubbed
+func (t *stakePrelaunchMinipools) checkForConflictingDeposit(mp *minipool.Minipool, pubkey rptypes.ValidatorPubkey, withdrawalCredentials common.Hash) (bool, error) {
+
+    // Check deposits
+    conflict, err := t.deposits.CheckForConflictingDeposit(t.bc, pubkey, withdrawalCredentials)
+    if err != nil {
+        return false, fmt.Errorf("Could not check for conflicting deposits, refusing to stake: %w", err)
+    }
+    if conflict == nil {
+        return false, nil
+    }
+
+    // Alert
+    logger := t.log.WithMinipool(mp.Address).WithPubkey(pubkey)
+    logger.Error("=== CONFLICTING DEPOSIT DETECTED ===")
+    logger.Errorf("Minipool %s will not be staked: %s", mp.Address.Hex(), conflict.String())
+    logger.Error("The validator key may be compromised; the minipool will be scrubbed by the Oracle DAO.")
+    if err := t.notifier.Notify("ALERT: Conflicting deposit detected for minipool %s, it will not be staked.\n%s", mp.Address.Hex(), conflict.String()); err != nil {
+        t.log.Warnf("Could not send conflicting deposit notification: %s", err.Error())
+    }
 
     // Return
     return true, nil
@@ -378,7 +424,7 @@ func (t *stakePrelaunchMinipools) restartValidator() error {
         }
 
         // Log
-        t.log.Printlnf("Restarting %s container (%s)...", clientTypeLabel, containerName)
+        t.log.Infof("Restarting %s container (%s)...", clientTypeLabel, containerName)
 
         // Get all containers
         containers, err := t.d.ContainerList(context.Background(), types.ContainerListOptions{All: true})
@@ -410,7 +456,7 @@ func (t *stakePrelaunchMinipools) restartValidator() error {
         restartCommand := os.ExpandEnv(t.cfg.Smartnode.ValidatorRestartCommand)
 
         // Log
-        t.log.Printlnf("Restarting validator process with command '%s'...", restartCommand)
+        t.log.Infof("Restarting validator process with command '%s'...", restartCommand)
 
         // Run validator restart command bound to os stdout/stderr
         cmd := exec.Command(restartCommand)
@@ -423,7 +469,7 @@ func (t *stakePrelaunchMinipools) restartValidator() error {
     }
 
     // Log & return
-    t.log.Println("Successfully restarted validator")
+    t.log.Info("Successfully restarted validator")
     return nil
 
 }
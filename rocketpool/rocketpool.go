@@ -67,10 +67,18 @@ func main() {
             Name:  "rplTokenAddress, t",
             Usage: "RPL token contract `address`",
         },
+        cli.StringFlag{
+            Name:  "wethTokenAddress",
+            Usage: "WETH token contract `address`, which Uniswap RPL price sources must be paired with",
+        },
         cli.StringFlag{
             Name:  "rplFaucetAddress, f",
             Usage: "Rocket Pool RPL token faucet `address`",
         },
+        cli.StringFlag{
+            Name:  "multicallAddress",
+            Usage: "Multicall2 contract `address` used to batch contract reads (defaults to the network's Multicall2 contract; set to 0x0 to read contracts individually)",
+        },
         cli.StringFlag{
             Name:  "password, p",
             Usage: "Rocket Pool wallet password file absolute `path`",
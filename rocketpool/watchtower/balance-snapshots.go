@@ -0,0 +1,333 @@
+package watchtower
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services"
+	"github.com/rocket-pool/smartnode/shared/utils/api"
+	"github.com/rocket-pool/smartnode/shared/utils/log"
+	"github.com/rocket-pool/smartnode/shared/utils/rp"
+)
+
+// Config
+const (
+    BalanceSnapshotsFolder = "balance-snapshots"
+    BalanceSnapshotDirMode = 0700
+    BalanceSnapshotFileMode = 0600
+)
+
+
+// Get the path of the balance snapshot for a block
+func getBalanceSnapshotPath(watchtowerPath string, blockNumber uint64) string {
+    return filepath.Join(watchtowerPath, BalanceSnapshotsFolder, strconv.FormatUint(blockNumber, 10) + ".json")
+}
+
+
+// Save a snapshot of the network balances and every minipool's balance details used to calculate them
+// Failures are logged rather than returned so that they do not prevent balances from being submitted
+func (t *submitNetworkBalances) saveBalanceSnapshot(balances networkBalances) {
+
+    // Encode snapshot
+    snapshotBytes, err := json.MarshalIndent(balances, "", "  ")
+    if err != nil {
+        t.log.Warnf("Could not encode balance snapshot for block %d: %s", balances.Block, err.Error())
+        return
+    }
+
+    // Write snapshot
+    path := getBalanceSnapshotPath(t.cfg.GetWatchtowerPath(), balances.Block)
+    if err := os.MkdirAll(filepath.Dir(path), BalanceSnapshotDirMode); err != nil {
+        t.log.Warnf("Could not create balance snapshot folder: %s", err.Error())
+        return
+    }
+    if err := ioutil.WriteFile(path, snapshotBytes, BalanceSnapshotFileMode); err != nil {
+        t.log.Warnf("Could not write balance snapshot to %s: %s", path, err.Error())
+        return
+    }
+
+    // Log
+    t.log.Infof("Saved balance snapshot for block %d to %s.", balances.Block, path)
+
+    // Prune old snapshots
+    pruned, err := pruneBalanceSnapshots(filepath.Dir(path), t.snapshotsKept, t.snapshotMaxAge, time.Now())
+    if err != nil {
+        t.log.Warnf("Could not prune balance snapshots: %s", err.Error())
+    }
+    if pruned > 0 {
+        t.log.Infof("Pruned %d old balance snapshot(s).", pruned)
+    }
+
+}
+
+
+// Delete balance snapshots beyond the number kept or older than the max age, returning the number deleted
+// The latest snapshot is always kept; a negative count or a zero max age disables that limit
+func pruneBalanceSnapshots(snapshotsPath string, kept int, maxAge time.Duration, now time.Time) (int, error) {
+
+    // Get snapshot files
+    files, err := ioutil.ReadDir(snapshotsPath)
+    if err != nil {
+        return 0, fmt.Errorf("Could not read balance snapshot folder %s: %w", snapshotsPath, err)
+    }
+    type snapshotFile struct {
+        block uint64
+        info os.FileInfo
+    }
+    snapshots := []snapshotFile{}
+    for _, file := range files {
+        if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+            continue
+        }
+        block, err := strconv.ParseUint(strings.TrimSuffix(file.Name(), ".json"), 10, 64)
+        if err != nil {
+            continue
+        }
+        snapshots = append(snapshots, snapshotFile{block: block, info: file})
+    }
+
+    // Sort snapshots from latest to earliest
+    sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].block > snapshots[j].block })
+
+    // Delete snapshots outside the retention limits
+    pruned := 0
+    for si, snapshot := range snapshots {
+        if si == 0 {
+            continue
+        }
+        tooMany := (kept > 0 && si >= kept)
+        tooOld := (maxAge > 0 && now.Sub(snapshot.info.ModTime()) > maxAge)
+        if !tooMany && !tooOld {
+            continue
+        }
+        if err := os.Remove(filepath.Join(snapshotsPath, snapshot.info.Name())); err != nil {
+            return pruned, fmt.Errorf("Could not delete balance snapshot %s: %w", snapshot.info.Name(), err)
+        }
+        pruned++
+    }
+
+    // Return
+    return pruned, nil
+
+}
+
+
+// Load a balance snapshot
+func loadBalanceSnapshot(path string) (networkBalances, error) {
+    snapshotBytes, err := ioutil.ReadFile(path)
+    if err != nil {
+        return networkBalances{}, fmt.Errorf("Could not read balance snapshot at %s: %w", path, err)
+    }
+    var balances networkBalances
+    if err := json.Unmarshal(snapshotBytes, &balances); err != nil {
+        return networkBalances{}, fmt.Errorf("Could not decode balance snapshot at %s: %w", path, err)
+    }
+    return balances, nil
+}
+
+
+// Recompute the network balances for a block and explain how they differ from a snapshot or another member's submission
+func explainBalances(c *cli.Context, blockNumber uint64) error {
+
+    // Get services
+    if err := services.RequireEthClientSynced(c); err != nil { return err }
+    if err := services.RequireBeaconClientSynced(c); err != nil { return err }
+    cfg, err := services.GetConfig(c)
+    if err != nil { return err }
+
+    // Create the balances task; its logger is only used for warnings
+    t, err := newSubmitNetworkBalances(c, log.NewLogger("submit-network-balances", SubmitNetworkBalancesColor), nil)
+    if err != nil {
+        return err
+    }
+
+    // Recompute network balances
+    fmt.Printf("Recomputing network balances for block %d...\n", blockNumber)
+    balances, err := t.getNetworkBalances(blockNumber)
+    if err != nil {
+        return err
+    }
+
+    // Compare with a member's submission
+    if c.String("member") != "" {
+        memberAddress := common.HexToAddress(c.String("member"))
+        return explainMemberBalances(t, balances, memberAddress)
+    }
+
+    // Load the snapshot
+    snapshotPath := c.String("snapshot")
+    if snapshotPath == "" {
+        snapshotPath = getBalanceSnapshotPath(cfg.GetWatchtowerPath(), blockNumber)
+    }
+    if _, err := os.Stat(snapshotPath); os.IsNotExist(err) {
+        return fmt.Errorf("No balance snapshot was found for block %d at %s; use --member to compare against a member's submission instead.", blockNumber, snapshotPath)
+    }
+    snapshot, err := loadBalanceSnapshot(snapshotPath)
+    if err != nil {
+        return err
+    }
+    if snapshot.Block != blockNumber {
+        return fmt.Errorf("The balance snapshot at %s is for block %d, not block %d.", snapshotPath, snapshot.Block, blockNumber)
+    }
+
+    // Compare totals
+    fmt.Printf("\nComparing against the balance snapshot at %s:\n\n", snapshotPath)
+    printBalanceComparison("Epoch", new(big.Int).SetUint64(balances.Epoch), new(big.Int).SetUint64(snapshot.Epoch))
+    printBalanceComparison("Deposit pool balance", balances.DepositPool, snapshot.DepositPool)
+    printBalanceComparison("rETH contract balance", balances.RETHContract, snapshot.RETHContract)
+    printBalanceComparison("Total minipool user balance", balances.MinipoolsTotal, snapshot.MinipoolsTotal)
+    printBalanceComparison("Staking minipool user balance", balances.MinipoolsStaking, snapshot.MinipoolsStaking)
+    printBalanceComparison("Total ETH balance", balances.TotalEth, snapshot.TotalEth)
+    printBalanceComparison("rETH token supply", balances.RETHSupply, snapshot.RETHSupply)
+
+    // Compare minipools
+    snapshotMinipools := make(map[common.Address]minipoolBalanceDetails, len(snapshot.Minipools))
+    for _, mp := range snapshot.Minipools {
+        snapshotMinipools[mp.Address] = mp
+    }
+    differences := 0
+    fmt.Printf("\nMinipool differences:\n")
+    for _, mp := range balances.Minipools {
+        snapshotMp, exists := snapshotMinipools[mp.Address]
+        delete(snapshotMinipools, mp.Address)
+        if !exists {
+            fmt.Printf("%s: not in snapshot (recomputed user balance %s wei from %s)\n", mp.Address.Hex(), mp.UserBalance.String(), mp.UserBalanceSource)
+            differences++
+            continue
+        }
+        for _, difference := range getMinipoolBalanceDifferences(mp, snapshotMp) {
+            fmt.Printf("%s: %s\n", mp.Address.Hex(), difference)
+            differences++
+        }
+    }
+    for _, snapshotMp := range snapshot.Minipools {
+        if _, exists := snapshotMinipools[snapshotMp.Address]; exists {
+            fmt.Printf("%s: only in snapshot (snapshot user balance %s wei from %s)\n", snapshotMp.Address.Hex(), snapshotMp.UserBalance.String(), snapshotMp.UserBalanceSource)
+            differences++
+        }
+    }
+    if differences == 0 {
+        fmt.Println("None; all minipool balance details match the snapshot.")
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Compare recomputed network balances against the totals a member submitted
+func explainMemberBalances(t *submitNetworkBalances, balances networkBalances, memberAddress common.Address) error {
+
+    // Get the member's submission
+    eventLogInterval, err := api.GetEventLogInterval(t.cfg)
+    if err != nil {
+        return err
+    }
+    valueNames, values, submitted, err := rp.GetTNDAOMemberSubmission(t.rp, rp.TNDAOSubmissionTypeBalances, memberAddress, balances.Block, eventLogInterval)
+    if err != nil {
+        return err
+    }
+    if !submitted {
+        return fmt.Errorf("Member %s did not submit balances for block %d.", memberAddress.Hex(), balances.Block)
+    }
+    submission := make(map[string]*big.Int, len(valueNames))
+    for vi, name := range valueNames {
+        submission[name] = values[vi]
+    }
+
+    // Compare totals
+    fmt.Printf("\nComparing against the balances submitted by member %s:\n\n", memberAddress.Hex())
+    matched := true
+    matched = printBalanceComparison("Total ETH balance", balances.TotalEth, submission["totalEth"]) && matched
+    matched = printBalanceComparison("Staking ETH balance", balances.MinipoolsStaking, submission["stakingEth"]) && matched
+    matched = printBalanceComparison("rETH token supply", balances.RETHSupply, submission["rethSupply"]) && matched
+
+    // Log the recomputed components of the total ETH balance
+    fmt.Printf("\nRecomputed total ETH balance components:\n")
+    fmt.Printf("Deposit pool balance:        %s wei\n", balances.DepositPool.String())
+    fmt.Printf("rETH contract balance:       %s wei\n", balances.RETHContract.String())
+    fmt.Printf("Total minipool user balance: %s wei (%d minipools)\n", balances.MinipoolsTotal.String(), len(balances.Minipools))
+    if !matched {
+        fmt.Println("\nThe member's totals differ from the recomputed totals. The deposit pool and rETH contract balances are read directly from the chain, so differences are usually caused by beacon chain balances; compare the snapshots of both nodes to find the minipools responsible.")
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Print a comparison of a recomputed value against a reference value, returning whether they match
+func printBalanceComparison(name string, value, reference *big.Int) bool {
+    if reference == nil {
+        fmt.Printf("%-30s %s (not in reference)\n", name + ":", value.String())
+        return false
+    }
+    if value.Cmp(reference) == 0 {
+        fmt.Printf("%-30s %s (matches)\n", name + ":", value.String())
+        return true
+    }
+    fmt.Printf("%-30s %s (reference %s, difference %s)\n", name + ":", value.String(), reference.String(), new(big.Int).Sub(value, reference).String())
+    return false
+}
+
+
+// Get the differences between a minipool's recomputed balance details and its details in a snapshot
+func getMinipoolBalanceDifferences(mp, snapshotMp minipoolBalanceDetails) []string {
+    differences := []string{}
+    if mp.Status != snapshotMp.Status {
+        differences = append(differences, fmt.Sprintf("status %s, snapshot %s", mp.Status.String(), snapshotMp.Status.String()))
+    }
+    if !bigIntsEqual(mp.UserDepositBalance, snapshotMp.UserDepositBalance) {
+        differences = append(differences, fmt.Sprintf("user deposit balance %s wei, snapshot %s wei", bigIntString(mp.UserDepositBalance), bigIntString(snapshotMp.UserDepositBalance)))
+    }
+    if mp.ValidatorExists != snapshotMp.ValidatorExists {
+        differences = append(differences, fmt.Sprintf("validator exists %t, snapshot %t", mp.ValidatorExists, snapshotMp.ValidatorExists))
+    }
+    if mp.ActivationEpoch != snapshotMp.ActivationEpoch || mp.ExitEpoch != snapshotMp.ExitEpoch {
+        differences = append(differences, fmt.Sprintf("activation / exit epochs %d / %d, snapshot %d / %d", mp.ActivationEpoch, mp.ExitEpoch, snapshotMp.ActivationEpoch, snapshotMp.ExitEpoch))
+    }
+    if mp.BeaconBalance != snapshotMp.BeaconBalance {
+        differences = append(differences, fmt.Sprintf("beacon balance %d gwei, snapshot %d gwei", mp.BeaconBalance, snapshotMp.BeaconBalance))
+    }
+    if mp.UserBalanceSource != snapshotMp.UserBalanceSource {
+        differences = append(differences, fmt.Sprintf("user balance from %s, snapshot from %s", mp.UserBalanceSource, snapshotMp.UserBalanceSource))
+    }
+    if mp.IsStaking != snapshotMp.IsStaking {
+        differences = append(differences, fmt.Sprintf("staking %t, snapshot %t", mp.IsStaking, snapshotMp.IsStaking))
+    }
+    if !bigIntsEqual(mp.UserBalance, snapshotMp.UserBalance) {
+        differences = append(differences, fmt.Sprintf("user balance %s wei, snapshot %s wei", bigIntString(mp.UserBalance), bigIntString(snapshotMp.UserBalance)))
+    }
+    return differences
+}
+
+
+// Check whether two optional big ints are equal
+func bigIntsEqual(a, b *big.Int) bool {
+    if a == nil || b == nil {
+        return a == b
+    }
+    return a.Cmp(b) == 0
+}
+
+
+// Get the string value of an optional big int
+func bigIntString(value *big.Int) string {
+    if value == nil {
+        return "none"
+    }
+    return value.String()
+}
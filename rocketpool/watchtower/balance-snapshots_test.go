@@ -0,0 +1,109 @@
+package watchtower
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+)
+
+
+func TestPruneBalanceSnapshots(t *testing.T) {
+    now := time.Unix(1700000000, 0)
+    tests := []struct {
+        name string
+        ages map[uint64]time.Duration
+        kept int
+        maxAge time.Duration
+        expected []uint64
+    }{
+        {
+            name: "within limits",
+            ages: map[uint64]time.Duration{100: time.Hour, 200: 0},
+            kept: 5,
+            expected: []uint64{100, 200},
+        },
+        {
+            name: "keeps the latest snapshots",
+            ages: map[uint64]time.Duration{100: 3 * time.Hour, 200: 2 * time.Hour, 300: time.Hour, 1000: 0},
+            kept: 2,
+            expected: []uint64{300, 1000},
+        },
+        {
+            name: "orders by block rather than name",
+            ages: map[uint64]time.Duration{99: 0, 100: 0, 1000: 0},
+            kept: 1,
+            expected: []uint64{1000},
+        },
+        {
+            name: "deletes old snapshots",
+            ages: map[uint64]time.Duration{100: 72 * time.Hour, 200: 30 * time.Hour, 300: time.Hour},
+            maxAge: 24 * time.Hour,
+            expected: []uint64{300},
+        },
+        {
+            name: "keeps the latest snapshot however old",
+            ages: map[uint64]time.Duration{100: 72 * time.Hour, 200: 48 * time.Hour},
+            maxAge: 24 * time.Hour,
+            expected: []uint64{200},
+        },
+        {
+            name: "count and age limits combine",
+            ages: map[uint64]time.Duration{100: 2 * time.Hour, 200: 30 * time.Hour, 300: time.Hour, 400: 0},
+            kept: 3,
+            maxAge: 24 * time.Hour,
+            expected: []uint64{300, 400},
+        },
+        {
+            name: "no limits",
+            ages: map[uint64]time.Duration{100: 72 * time.Hour, 200: 0},
+            kept: -1,
+            expected: []uint64{100, 200},
+        },
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+
+            // Write snapshots and unrelated files
+            dir, err := ioutil.TempDir("", "balance-snapshots")
+            if err != nil { t.Fatal(err) }
+            defer os.RemoveAll(dir)
+            for block, age := range test.ages {
+                path := filepath.Join(dir, strconv.FormatUint(block, 10) + ".json")
+                if err := ioutil.WriteFile(path, []byte("{}"), BalanceSnapshotFileMode); err != nil { t.Fatal(err) }
+                if err := os.Chtimes(path, now.Add(-age), now.Add(-age)); err != nil { t.Fatal(err) }
+            }
+            if err := ioutil.WriteFile(filepath.Join(dir, "notes.json"), []byte("{}"), BalanceSnapshotFileMode); err != nil { t.Fatal(err) }
+
+            // Prune
+            pruned, err := pruneBalanceSnapshots(dir, test.kept, test.maxAge, now)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if pruned != len(test.ages) - len(test.expected) {
+                t.Errorf("pruned %d snapshots, expected %d", pruned, len(test.ages) - len(test.expected))
+            }
+
+            // Check remaining snapshots
+            files, err := ioutil.ReadDir(dir)
+            if err != nil { t.Fatal(err) }
+            remaining := []uint64{}
+            for _, file := range files {
+                if block, err := strconv.ParseUint(file.Name()[:len(file.Name()) - len(".json")], 10, 64); err == nil {
+                    remaining = append(remaining, block)
+                } else if file.Name() != "notes.json" {
+                    t.Errorf("unexpected file %s", file.Name())
+                }
+            }
+            sort.Slice(remaining, func(i, j int) bool { return remaining[i] < remaining[j] })
+            if !reflect.DeepEqual(remaining, test.expected) {
+                t.Errorf("remaining snapshots are %v, expected %v", remaining, test.expected)
+            }
+
+        })
+    }
+}
@@ -22,7 +22,7 @@ import (
 // Claim RPL rewards task
 type claimRplRewards struct {
     c *cli.Context
-    log log.ColorLogger
+    log log.Logger
     cfg config.RocketPoolConfig
     w *wallet.Wallet
     rp *rocketpool.RocketPool
@@ -34,7 +34,7 @@ type claimRplRewards struct {
 
 
 // Create claim RPL rewards task
-func newClaimRplRewards(c *cli.Context, logger log.ColorLogger) (*claimRplRewards, error) {
+func newClaimRplRewards(c *cli.Context, logger log.Logger) (*claimRplRewards, error) {
 
     // Get services
     cfg, err := services.GetConfig(c)
@@ -47,7 +47,7 @@ func newClaimRplRewards(c *cli.Context, logger log.ColorLogger) (*claimRplReward
     // Check if auto-claiming is disabled
     gasThreshold := cfg.Smartnode.RplClaimGasThreshold
     if gasThreshold == 0 {
-        logger.Println("RPL claim gas threshold is set to 0, automatic claims will be disabled.")
+        logger.Info("RPL claim gas threshold is set to 0, automatic claims will be disabled.")
     }
 
     // Get the user-requested max fee
@@ -62,7 +62,7 @@ func newClaimRplRewards(c *cli.Context, logger log.ColorLogger) (*claimRplReward
         return nil, fmt.Errorf("Error getting max priority fee in configuration: %w", err)
     }
     if maxPriorityFee == nil || maxPriorityFee.Uint64() == 0 {
-        logger.Println("WARNING: priority fee was missing or 0, setting a default of 2.");
+        logger.Warn("Priority fee was missing or 0, setting a default of 2.");
         maxPriorityFee = big.NewInt(2)
     }
 
@@ -117,7 +117,7 @@ func (t *claimRplRewards) run() error {
     }
 
     // Log
-    t.log.Println("Checking for RPL rewards to claim...")
+    t.log.Info("Checking for RPL rewards to claim...")
 
     // Check for rewards
     rewardsAmountWei, err := rewards.GetTrustedNodeClaimRewardsAmount(t.rp, nodeAccount.Address, nil)
@@ -129,7 +129,7 @@ func (t *claimRplRewards) run() error {
     }
 
     // Log
-    t.log.Printlnf("%.6f RPL is available to claim...", math.RoundDown(eth.WeiToEth(rewardsAmountWei), 6))
+    t.log.Infof("%.6f RPL is available to claim...", math.RoundDown(eth.WeiToEth(rewardsAmountWei), 6))
 
     // Get transactor
     opts, err := t.w.GetNodeAccountTransactor()
@@ -174,13 +174,13 @@ func (t *claimRplRewards) run() error {
     }
 
     // Print TX info and wait for it to be mined
-    err = api.PrintAndWaitForTransaction(t.cfg, hash, t.rp.Client, t.log)
+    err = api.PrintAndWaitForTransaction(t.cfg, hash, t.rp.Client, t.w.GetTxLog(), t.log)
     if err != nil {
         return err
     }
 
     // Log & return
-    t.log.Printlnf("Successfully claimed %.6f RPL in rewards.", math.RoundDown(eth.WeiToEth(rewardsAmountWei), 6))
+    t.log.Infof("Successfully claimed %.6f RPL in rewards.", math.RoundDown(eth.WeiToEth(rewardsAmountWei), 6))
     return nil
 
 }
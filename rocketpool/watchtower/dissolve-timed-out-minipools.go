@@ -19,32 +19,42 @@ import (
 
 	"github.com/rocket-pool/smartnode/shared/services"
 	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/multicall"
 	rpgas "github.com/rocket-pool/smartnode/shared/services/gas"
 	"github.com/rocket-pool/smartnode/shared/services/wallet"
 	"github.com/rocket-pool/smartnode/shared/utils/api"
 	"github.com/rocket-pool/smartnode/shared/utils/log"
+	"github.com/rocket-pool/smartnode/shared/utils/rp"
 )
 
-// Settings
-const MinipoolStatusBatchSize = 20
-
-
 // Dissolve timed out minipools task
 type dissolveTimedOutMinipools struct {
     c *cli.Context
-    log log.ColorLogger
+    log log.Logger
     cfg config.RocketPoolConfig
     w *wallet.Wallet
     ec *ethclient.Client
     rp *rocketpool.RocketPool
+    mc *multicall.MultiCaller
     maxFee *big.Int
     maxPriorityFee *big.Int
     gasLimit uint64
+    dryRun *dryRunRecorder
+    dryRunPending map[common.Address]*minipool.Minipool
+}
+
+
+// A timed out minipool and its on-chain status
+type timedOutMinipool struct {
+    mp *minipool.Minipool
+    status rptypes.MinipoolStatus
+    statusTime time.Time
+    launchTimeout time.Duration
 }
 
 
 // Create dissolve timed out minipools task
-func newDissolveTimedOutMinipools(c *cli.Context, logger log.ColorLogger) (*dissolveTimedOutMinipools, error) {
+func newDissolveTimedOutMinipools(c *cli.Context, logger log.Logger, dryRun *dryRunRecorder) (*dissolveTimedOutMinipools, error) {
 
     // Get services
     cfg, err := services.GetConfig(c)
@@ -55,6 +65,8 @@ func newDissolveTimedOutMinipools(c *cli.Context, logger log.ColorLogger) (*diss
     if err != nil { return nil, err }
     rp, err := services.GetRocketPool(c)
     if err != nil { return nil, err }
+    mc, err := services.GetMultiCaller(c)
+    if err != nil { return nil, err }
 
     // Get the user-requested max fee
     maxFee, err := cfg.GetMaxFee()
@@ -68,7 +80,7 @@ func newDissolveTimedOutMinipools(c *cli.Context, logger log.ColorLogger) (*diss
         return nil, fmt.Errorf("Error getting max priority fee in configuration: %w", err)
     }
     if maxPriorityFee == nil || maxPriorityFee.Uint64() == 0 {
-        logger.Println("WARNING: priority fee was missing or 0, setting a default of 2.");
+        logger.Warn("Priority fee was missing or 0, setting a default of 2.");
         maxPriorityFee = big.NewInt(2)
     }
 
@@ -86,9 +98,12 @@ func newDissolveTimedOutMinipools(c *cli.Context, logger log.ColorLogger) (*diss
         w: w,
         ec: ec,
         rp: rp,
+        mc: mc,
         maxFee: maxFee,
         maxPriorityFee: maxPriorityFee,
         gasLimit: gasLimit,
+        dryRun: dryRun,
+        dryRunPending: make(map[common.Address]*minipool.Minipool),
     }, nil
 
 }
@@ -113,12 +128,19 @@ func (t *dissolveTimedOutMinipools) run() error {
     if err != nil {
         return err
     }
-    if !nodeTrusted {
+    if !nodeTrusted && t.dryRun == nil {
         return nil
     }
 
+    // In dry-run mode, compare the minipools we would have dissolved with their current on-chain status
+    if t.dryRun != nil {
+        if err := t.recordDryRunOutcomes(); err != nil {
+            return err
+        }
+    }
+
     // Log
-    t.log.Println("Checking for timed out minipools to dissolve...")
+    t.log.Info("Checking for timed out minipools to dissolve...")
 
     // Get timed out minipools
     minipools, err := t.getTimedOutMinipools()
@@ -130,13 +152,93 @@ func (t *dissolveTimedOutMinipools) run() error {
     }
 
     // Log
-    t.log.Printlnf("%d minipool(s) have timed out and will be dissolved...", len(minipools))
+    t.log.Infof("%d minipool(s) have timed out and will be dissolved...", len(minipools))
+
+    // In dry-run mode, record each timed out minipool once instead of dissolving it
+    // Its outcome is recorded once another member dissolves it or its status otherwise changes
+    if t.dryRun != nil {
+        for _, timedOut := range minipools {
+            mp := timedOut.mp
+            if _, pending := t.dryRunPending[mp.Address]; pending {
+                continue
+            }
+            t.log.WithMinipool(mp.Address).Infof("DRY RUN: Would dissolve minipool %s.", mp.Address.Hex())
+            minipoolAddress := mp.Address
+            if err := t.dryRun.record(t.log, dryRunRecord{
+                Task: "dissolveTimedOutMinipools",
+                Minipool: &minipoolAddress,
+                Values: map[string]string{
+                    "status": rptypes.Dissolved.String(),
+                },
+                OnChain: map[string]string{
+                    "status": timedOut.status.String(),
+                    "statusTime": timedOut.statusTime.UTC().Format(time.RFC3339),
+                    "launchTimeout": timedOut.launchTimeout.String(),
+                },
+                Members: []dryRunMemberSubmission{},
+            }); err != nil {
+                t.log.WithMinipool(mp.Address).Error(fmt.Errorf("Could not record dissolving minipool %s: %w", mp.Address.Hex(), err))
+                continue
+            }
+            t.dryRunPending[mp.Address] = mp
+        }
+        return nil
+    }
 
     // Dissolve minipools
-    for _, mp := range minipools {
-        if err := t.dissolveMinipool(mp); err != nil {
-            t.log.Println(fmt.Errorf("Could not dissolve minipool %s: %w", mp.Address.Hex(), err))
+    for _, timedOut := range minipools {
+        if err := t.dissolveMinipool(timedOut.mp); err != nil {
+            t.log.WithMinipool(timedOut.mp.Address).Error(fmt.Errorf("Could not dissolve minipool %s: %w", timedOut.mp.Address.Hex(), err))
+        }
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Record the outcome of minipools we would have dissolved in dry-run mode once their on-chain status changes
+// Dissolving is a single transaction by any member rather than a vote, so the outcome is compared with the chain instead of member submissions
+func (t *dissolveTimedOutMinipools) recordDryRunOutcomes() error {
+
+    // Get pending minipool statuses
+    if len(t.dryRunPending) == 0 {
+        return nil
+    }
+    minipools := make([]*minipool.Minipool, 0, len(t.dryRunPending))
+    for _, mp := range t.dryRunPending {
+        minipools = append(minipools, mp)
+    }
+    statuses, statusTimes, err := t.getMinipoolStatuses(minipools)
+    if err != nil {
+        return err
+    }
+
+    // Record minipools whose status has changed
+    for mi, mp := range minipools {
+        if statuses[mi] == rptypes.Prelaunch {
+            continue
+        }
+        minipoolAddress := mp.Address
+        if err := t.dryRun.record(t.log.WithMinipool(mp.Address), dryRunRecord{
+            Task: "dissolveTimedOutMinipools",
+            Minipool: &minipoolAddress,
+            Values: map[string]string{
+                "status": rptypes.Dissolved.String(),
+            },
+            OnChain: map[string]string{
+                "status": statuses[mi].String(),
+                "statusTime": statusTimes[mi].UTC().Format(time.RFC3339),
+            },
+            ConsensusReached: true,
+            ConsensusMatched: (statuses[mi] == rptypes.Dissolved),
+            Members: []dryRunMemberSubmission{},
+        }); err != nil {
+            t.log.WithMinipool(mp.Address).Error(fmt.Errorf("Could not record the outcome for minipool %s: %w", mp.Address.Hex(), err))
+            continue
         }
+        delete(t.dryRunPending, mp.Address)
     }
 
     // Return
@@ -146,7 +248,7 @@ func (t *dissolveTimedOutMinipools) run() error {
 
 
 // Get timed out minipools
-func (t *dissolveTimedOutMinipools) getTimedOutMinipools() ([]*minipool.Minipool, error) {
+func (t *dissolveTimedOutMinipools) getTimedOutMinipools() ([]timedOutMinipool, error) {
 
     // Data
     var wg1 errgroup.Group
@@ -157,7 +259,7 @@ func (t *dissolveTimedOutMinipools) getTimedOutMinipools() ([]*minipool.Minipool
     // Get minipool addresses
     wg1.Go(func() error {
         var err error
-        addresses, err = minipool.GetMinipoolAddresses(t.rp, nil)
+        addresses, err = rp.GetMinipoolAddresses(t.rp, t.mc, nil)
         return err
     })
 
@@ -177,51 +279,32 @@ func (t *dissolveTimedOutMinipools) getTimedOutMinipools() ([]*minipool.Minipool
 
     // Wait for data
     if err := wg1.Wait(); err != nil {
-        return []*minipool.Minipool{}, err
+        return []timedOutMinipool{}, err
     }
 
-    // Create minipool contracts
-    minipools := make([]*minipool.Minipool, len(addresses))
-    for mi, address := range addresses {
-        mp, err := minipool.NewMinipool(t.rp, address)
-        if err != nil {
-            return []*minipool.Minipool{}, err
-        }
-        minipools[mi] = mp
+    // Get minipool contracts
+    minipools, err := rp.GetMinipools(t.rp, addresses)
+    if err != nil {
+        return []timedOutMinipool{}, err
     }
 
-    // Load minipool statuses in batches
-    statuses := make([]minipool.StatusDetails, len(minipools))
-    for bsi := 0; bsi < len(minipools); bsi += MinipoolStatusBatchSize {
-
-        // Get batch start & end index
-        msi := bsi
-        mei := bsi + MinipoolStatusBatchSize
-        if mei > len(minipools) { mei = len(minipools) }
-
-        // Load statuses
-        var wg errgroup.Group
-        for mi := msi; mi < mei; mi++ {
-            mi := mi
-            wg.Go(func() error {
-                mp := minipools[mi]
-                status, err := mp.GetStatusDetails(nil)
-                if err == nil { statuses[mi] = status }
-                return err
-            })
-        }
-        if err := wg.Wait(); err != nil {
-            return []*minipool.Minipool{}, err
-        }
-
+    // Load minipool statuses
+    statuses, statusTimes, err := t.getMinipoolStatuses(minipools)
+    if err != nil {
+        return []timedOutMinipool{}, err
     }
 
     // Filter minipools by status
     latestBlockTime := time.Unix(int64(latestEth1Block.Time), 0)
-    timedOutMinipools := []*minipool.Minipool{}
+    timedOutMinipools := []timedOutMinipool{}
     for mi, mp := range minipools {
-        if statuses[mi].Status == rptypes.Prelaunch && latestBlockTime.Sub(statuses[mi].StatusTime) >= launchTimeout {
-            timedOutMinipools = append(timedOutMinipools, mp)
+        if statuses[mi] == rptypes.Prelaunch && latestBlockTime.Sub(statusTimes[mi]) >= launchTimeout {
+            timedOutMinipools = append(timedOutMinipools, timedOutMinipool{
+                mp: mp,
+                status: statuses[mi],
+                statusTime: statusTimes[mi],
+                launchTimeout: launchTimeout,
+            })
         }
     }
 
@@ -231,11 +314,37 @@ func (t *dissolveTimedOutMinipools) getTimedOutMinipools() ([]*minipool.Minipool
 }
 
 
+// Get the statuses and status times of minipools
+func (t *dissolveTimedOutMinipools) getMinipoolStatuses(minipools []*minipool.Minipool) ([]rptypes.MinipoolStatus, []time.Time, error) {
+    rawStatuses := make([]uint8, len(minipools))
+    rawStatusTimes := make([]*big.Int, len(minipools))
+    batch := t.mc.NewBatch()
+    for mi, mp := range minipools {
+        if err := batch.AddCall(mp.Contract, &rawStatuses[mi], "getStatus"); err != nil {
+            return nil, nil, err
+        }
+        if err := batch.AddCall(mp.Contract, &rawStatusTimes[mi], "getStatusTime"); err != nil {
+            return nil, nil, err
+        }
+    }
+    if err := batch.Execute(nil); err != nil {
+        return nil, nil, err
+    }
+    statuses := make([]rptypes.MinipoolStatus, len(minipools))
+    statusTimes := make([]time.Time, len(minipools))
+    for mi := range minipools {
+        statuses[mi] = rptypes.MinipoolStatus(rawStatuses[mi])
+        statusTimes[mi] = time.Unix(rawStatusTimes[mi].Int64(), 0)
+    }
+    return statuses, statusTimes, nil
+}
+
+
 // Dissolve a minipool
 func (t *dissolveTimedOutMinipools) dissolveMinipool(mp *minipool.Minipool) error {
 
     // Log
-    t.log.Printlnf("Dissolving minipool %s...", mp.Address.Hex())
+    t.log.WithMinipool(mp.Address).Infof("Dissolving minipool %s...", mp.Address.Hex())
 
     // Get transactor
     opts, err := t.w.GetNodeAccountTransactor()
@@ -280,13 +389,13 @@ func (t *dissolveTimedOutMinipools) dissolveMinipool(mp *minipool.Minipool) erro
     }
 
     // Print TX info and wait for it to be mined
-    err = api.PrintAndWaitForTransaction(t.cfg, hash, t.rp.Client, t.log)
+    err = api.PrintAndWaitForTransaction(t.cfg, hash, t.rp.Client, t.w.GetTxLog(), t.log)
     if err != nil {
         return err
     }
 
     // Log
-    t.log.Printlnf("Successfully dissolved minipool %s.", mp.Address.Hex())
+    t.log.WithMinipool(mp.Address).Infof("Successfully dissolved minipool %s.", mp.Address.Hex())
 
     // Return
     return nil
@@ -0,0 +1,154 @@
+package watchtower
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/rocketpool-go/dao/trustednode"
+	"github.com/rocket-pool/rocketpool-go/rocketpool"
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services"
+	"github.com/rocket-pool/smartnode/shared/utils/log"
+)
+
+// Config
+const (
+    DryRunFile = "dry-run.jsonl"
+    DryRunDirMode = 0700
+    DryRunFileMode = 0600
+)
+
+
+// Records the submissions the watchtower would have made in dry-run mode
+type dryRunRecorder struct {
+    path string
+    lock sync.Mutex
+}
+
+
+// A submission the watchtower would have made, compared against the existing members' submissions
+type dryRunRecord struct {
+    Time time.Time                      `json:"time"`
+    Task string                         `json:"task"`
+    Block uint64                        `json:"block,omitempty"`
+    Minipool *common.Address            `json:"minipool,omitempty"`
+    Values map[string]string            `json:"values,omitempty"`
+    OnChain map[string]string           `json:"onChain,omitempty"`
+    ConsensusReached bool               `json:"consensusReached"`
+    ConsensusMatched bool               `json:"consensusMatched"`
+    Members []dryRunMemberSubmission    `json:"members"`
+}
+type dryRunMemberSubmission struct {
+    Address common.Address              `json:"address"`
+    Submitted bool                      `json:"submitted"`
+    Matched bool                        `json:"matched"`
+}
+
+
+// Create dry-run recorder; returns nil if the watchtower is not running in dry-run mode
+func newDryRunRecorder(c *cli.Context) (*dryRunRecorder, error) {
+
+    // Check dry-run mode
+    if !c.Bool("dry-run") {
+        return nil, nil
+    }
+
+    // Get services
+    cfg, err := services.GetConfig(c)
+    if err != nil { return nil, err }
+
+    // Return recorder
+    return &dryRunRecorder{
+        path: filepath.Join(cfg.GetWatchtowerPath(), DryRunFile),
+    }, nil
+
+}
+
+
+// Log a dry-run record and append it to the dry-run file
+func (r *dryRunRecorder) record(logger log.Logger, record dryRunRecord) error {
+
+    // Count matching member submissions
+    submitted := 0
+    matched := 0
+    for _, member := range record.Members {
+        if member.Submitted { submitted++ }
+        if member.Matched { matched++ }
+    }
+
+    // Log
+    if len(record.Members) > 0 {
+        logger.Infof("DRY RUN: %d of %d member(s) submitted, %d with values matching ours.", submitted, len(record.Members), matched)
+    }
+    if record.ConsensusReached {
+        if record.ConsensusMatched {
+            logger.Info("DRY RUN: Our values match the consensus values on chain.")
+        } else {
+            logger.Warn("DRY RUN: our values DO NOT match the consensus values on chain.")
+        }
+    }
+    if submitted > matched {
+        logger.Warnf("DRY RUN: %d member submission(s) diverged from our values.", submitted - matched)
+    }
+
+    // Encode record
+    record.Time = time.Now()
+    recordBytes, err := json.Marshal(record)
+    if err != nil {
+        return fmt.Errorf("Could not encode dry-run record: %w", err)
+    }
+
+    // Append record to file
+    r.lock.Lock()
+    defer r.lock.Unlock()
+    if err := os.MkdirAll(filepath.Dir(r.path), DryRunDirMode); err != nil {
+        return fmt.Errorf("Could not create dry-run folder: %w", err)
+    }
+    file, err := os.OpenFile(r.path, os.O_APPEND | os.O_CREATE | os.O_WRONLY, DryRunFileMode)
+    if err != nil {
+        return fmt.Errorf("Could not open dry-run file at %s: %w", r.path, err)
+    }
+    defer file.Close()
+    if _, err := file.Write(append(recordBytes, '\n')); err != nil {
+        return fmt.Errorf("Could not write dry-run record to %s: %w", r.path, err)
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Get member submission statuses using a callback which checks whether a member submitted anything, and whether it matched our values
+func getDryRunMemberSubmissions(rp *rocketpool.RocketPool, check func(memberAddress common.Address) (bool, bool, error)) ([]dryRunMemberSubmission, error) {
+
+    // Get member addresses
+    memberAddresses, err := trustednode.GetMemberAddresses(rp, nil)
+    if err != nil {
+        return []dryRunMemberSubmission{}, err
+    }
+
+    // Check each member's submission
+    members := make([]dryRunMemberSubmission, len(memberAddresses))
+    for mi, memberAddress := range memberAddresses {
+        submitted, matched, err := check(memberAddress)
+        if err != nil {
+            return []dryRunMemberSubmission{}, err
+        }
+        members[mi] = dryRunMemberSubmission{
+            Address: memberAddress,
+            Submitted: submitted,
+            Matched: matched,
+        }
+    }
+
+    // Return
+    return members, nil
+
+}
@@ -13,7 +13,7 @@ import (
 )
 
 
-func runMetricsServer(c *cli.Context, logger log.ColorLogger, scrubCollector *collectors.ScrubCollector) (error) {
+func runMetricsServer(c *cli.Context, logger log.Logger, scrubCollector *collectors.ScrubCollector) (error) {
 
     // Get services
     cfg, err := services.GetConfig(c)
@@ -32,7 +32,7 @@ func runMetricsServer(c *cli.Context, logger log.ColorLogger, scrubCollector *co
     // Start the HTTP server
     metricsAddress := c.GlobalString("metricsAddress")
     metricsPort := c.GlobalUint("metricsPort")
-    logger.Printlnf("Starting metrics exporter on %s:%d.", metricsAddress, metricsPort)
+    logger.Infof("Starting metrics exporter on %s:%d.", metricsAddress, metricsPort)
     metricsPath := "/metrics"
     http.Handle(metricsPath, handler)
     http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
@@ -13,14 +13,14 @@ import (
 // Process withdrawals task
 type processWithdrawals struct {
     c *cli.Context
-    log log.ColorLogger
+    log log.Logger
     w *wallet.Wallet
     rp *rocketpool.RocketPool
 }
 
 
 // Create process withdrawals task
-func newProcessWithdrawals(c *cli.Context, logger log.ColorLogger) (*processWithdrawals, error) {
+func newProcessWithdrawals(c *cli.Context, logger log.Logger) (*processWithdrawals, error) {
 
     // Get services
     w, err := services.GetWallet(c)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/rocket-pool/rocketpool-go/dao/trustednode"
 	"github.com/rocket-pool/rocketpool-go/rocketpool"
 	"github.com/urfave/cli"
@@ -11,26 +12,35 @@ import (
 	"github.com/rocket-pool/smartnode/shared/services"
 	"github.com/rocket-pool/smartnode/shared/services/config"
 	rpgas "github.com/rocket-pool/smartnode/shared/services/gas"
+	"github.com/rocket-pool/smartnode/shared/services/multicall"
+	"github.com/rocket-pool/smartnode/shared/services/notify"
 	"github.com/rocket-pool/smartnode/shared/services/wallet"
+	apitypes "github.com/rocket-pool/smartnode/shared/types/api"
 	"github.com/rocket-pool/smartnode/shared/utils/api"
 	"github.com/rocket-pool/smartnode/shared/utils/log"
+	rputils "github.com/rocket-pool/smartnode/shared/utils/rp"
 )
 
 // Respond to challenges task
 type respondChallenges struct {
     c *cli.Context
-    log log.ColorLogger
+    log log.Logger
     cfg config.RocketPoolConfig
     w *wallet.Wallet
     rp *rocketpool.RocketPool
+    mc *multicall.MultiCaller
+    notifier *notify.Notifier
     maxFee *big.Int
     maxPriorityFee *big.Int
     gasLimit uint64
+    dryRun *dryRunRecorder
+    reported map[common.Address]uint64
+    reportedExpired map[common.Address]uint64
 }
 
 
 // Create respond to challenges task
-func newRespondChallenges(c *cli.Context, logger log.ColorLogger) (*respondChallenges, error) {
+func newRespondChallenges(c *cli.Context, logger log.Logger, dryRun *dryRunRecorder) (*respondChallenges, error) {
 
     // Get services
     cfg, err := services.GetConfig(c)
@@ -39,6 +49,10 @@ func newRespondChallenges(c *cli.Context, logger log.ColorLogger) (*respondChall
     if err != nil { return nil, err }
     rp, err := services.GetRocketPool(c)
     if err != nil { return nil, err }
+    mc, err := services.GetMultiCaller(c)
+    if err != nil { return nil, err }
+    notifier, err := services.GetNotifier(c)
+    if err != nil { return nil, err }
 
     // Get the user-requested max fee
     maxFee, err := cfg.GetMaxFee()
@@ -52,7 +66,7 @@ func newRespondChallenges(c *cli.Context, logger log.ColorLogger) (*respondChall
         return nil, fmt.Errorf("Error getting max priority fee in configuration: %w", err)
     }
     if maxPriorityFee == nil || maxPriorityFee.Uint64() == 0 {
-        logger.Println("WARNING: priority fee was missing or 0, setting a default of 2.");
+        logger.Warn("Priority fee was missing or 0, setting a default of 2.");
         maxPriorityFee = big.NewInt(2)
     }
 
@@ -69,15 +83,21 @@ func newRespondChallenges(c *cli.Context, logger log.ColorLogger) (*respondChall
         cfg: cfg,
         w: w,
         rp: rp,
+        mc: mc,
+        notifier: notifier,
         maxFee: maxFee,
         maxPriorityFee: maxPriorityFee,
         gasLimit: gasLimit,
+        dryRun: dryRun,
+        reported: map[common.Address]uint64{},
+        reportedExpired: map[common.Address]uint64{},
     }, nil
 
 }
 
 
-// Respond to challenges
+// Monitor challenges against oracle DAO members, and respond to challenges against the node
+// In dry-run mode, challenges are monitored and reported but never responded to
 func (t *respondChallenges) run() error {
 
     // Wait for eth client to sync
@@ -96,24 +116,61 @@ func (t *respondChallenges) run() error {
     if err != nil {
         return err
     }
-    if !nodeTrusted {
+    if !nodeTrusted && t.dryRun == nil {
         return nil
     }
 
     // Log
-    t.log.Println("Checking for challenges to respond to...")
+    t.log.Info("Checking for challenges against oracle DAO members...")
 
-    // Check for active challenges
-    isChallenged, err := trustednode.GetMemberIsChallenged(t.rp, nodeAccount.Address, nil)
+    // Get active challenges
+    challenges, _, err := rputils.GetTNDAOChallenges(t.rp, t.mc, nil)
     if err != nil {
         return err
     }
-    if !isChallenged {
-        return nil
+
+    // Handle challenges
+    for _, challenge := range challenges {
+        if challenge.MemberAddress == nodeAccount.Address && nodeTrusted && t.dryRun == nil {
+            if err := t.respondToChallenge(nodeAccount.Address); err != nil {
+                return err
+            }
+        } else {
+            t.reportChallenge(challenge)
+        }
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Alert the operator to a challenge against another member, once when it is made and once when its window expires
+func (t *respondChallenges) reportChallenge(challenge apitypes.TNDAOChallenge) {
+
+    // Report new challenges
+    if t.reported[challenge.MemberAddress] != challenge.ChallengedBlock {
+        t.reported[challenge.MemberAddress] = challenge.ChallengedBlock
+        t.log.Infof("Member %s (%s) has been challenged by %s and must respond by block %d.", challenge.MemberId, challenge.MemberAddress.Hex(), challenge.ChallengerAddress.Hex(), challenge.DeadlineBlock)
+        t.notify("Oracle DAO member %s (%s) has been challenged by %s and must respond by block %d.", challenge.MemberId, challenge.MemberAddress.Hex(), challenge.ChallengerAddress.Hex(), challenge.DeadlineBlock)
+    }
+
+    // Report expired challenges; the member is at risk of being removed
+    if challenge.Expired && t.reportedExpired[challenge.MemberAddress] != challenge.ChallengedBlock {
+        t.reportedExpired[challenge.MemberAddress] = challenge.ChallengedBlock
+        t.log.Warnf("Member %s (%s) did not respond to its challenge in time and can now be removed from the oracle DAO.", challenge.MemberId, challenge.MemberAddress.Hex())
+        t.notify("Oracle DAO member %s (%s) did not respond to its challenge in time and can now be removed from the oracle DAO.", challenge.MemberId, challenge.MemberAddress.Hex())
     }
 
+}
+
+
+// Respond to a challenge against the node
+func (t *respondChallenges) respondToChallenge(nodeAddress common.Address) error {
+
     // Log
-    t.log.Printlnf("Node %s has an active challenge against it, responding...", nodeAccount.Address.Hex())
+    t.log.Infof("Node %s has an active challenge against it, responding...", nodeAddress.Hex())
 
     // Get transactor
     opts, err := t.w.GetNodeAccountTransactor()
@@ -122,7 +179,7 @@ func (t *respondChallenges) run() error {
     }
 
     // Get the gas limit
-    gasInfo, err := trustednode.EstimateDecideChallengeGas(t.rp, nodeAccount.Address, opts)
+    gasInfo, err := trustednode.EstimateDecideChallengeGas(t.rp, nodeAddress, opts)
     if err != nil {
         return fmt.Errorf("Could not estimate the gas required to respond to the challenge: %w", err)
     }
@@ -152,20 +209,28 @@ func (t *respondChallenges) run() error {
     opts.GasLimit = gas.Uint64()
 
     // Respond to challenge
-    hash, err := trustednode.DecideChallenge(t.rp, nodeAccount.Address, opts)
+    hash, err := trustednode.DecideChallenge(t.rp, nodeAddress, opts)
     if err != nil {
         return err
     }
 
     // Print TX info and wait for it to be mined
-    err = api.PrintAndWaitForTransaction(t.cfg, hash, t.rp.Client, t.log)
+    err = api.PrintAndWaitForTransaction(t.cfg, hash, t.rp.Client, t.w.GetTxLog(), t.log)
     if err != nil {
         return err
     }
 
     // Log & return
-    t.log.Printlnf("Successfully responded to challenge against node %s.", nodeAccount.Address.Hex())
+    t.log.Infof("Successfully responded to challenge against node %s.", nodeAddress.Hex())
+    t.notify("Responded to a challenge against node %s.", nodeAddress.Hex())
     return nil
 
 }
 
+
+// Send an operator notification, logging any errors
+func (t *respondChallenges) notify(format string, args ...interface{}) {
+    if err := t.notifier.Notify(format, args...); err != nil {
+        t.log.Warn(err.Error())
+    }
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
@@ -11,7 +12,6 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/rocket-pool/rocketpool-go/dao/trustednode"
 	"github.com/rocket-pool/rocketpool-go/deposit"
-	"github.com/rocket-pool/rocketpool-go/minipool"
 	"github.com/rocket-pool/rocketpool-go/network"
 	"github.com/rocket-pool/rocketpool-go/rocketpool"
 	"github.com/rocket-pool/rocketpool-go/settings/protocol"
@@ -24,6 +24,7 @@ import (
 	"github.com/rocket-pool/smartnode/shared/services"
 	"github.com/rocket-pool/smartnode/shared/services/beacon"
 	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/multicall"
 	rpgas "github.com/rocket-pool/smartnode/shared/services/gas"
 	"github.com/rocket-pool/smartnode/shared/services/wallet"
 	"github.com/rocket-pool/smartnode/shared/utils/api"
@@ -34,7 +35,6 @@ import (
 )
 
 // Settings
-const MinipoolBalanceDetailsBatchSize = 20
 const SubmitFollowDistanceBalances = 2
 const ConfirmDistanceBalances = 30
 
@@ -42,35 +42,60 @@ const ConfirmDistanceBalances = 30
 // Submit network balances task
 type submitNetworkBalances struct {
     c *cli.Context
-    log log.ColorLogger
+    log log.Logger
     cfg config.RocketPoolConfig
     w *wallet.Wallet
     ec *ethclient.Client
     rp *rocketpool.RocketPool
     bc beacon.Client
+    mc *multicall.MultiCaller
     maxFee *big.Int
     maxPriorityFee *big.Int
     gasLimit uint64
+    snapshotsKept int
+    snapshotMaxAge time.Duration
+    dryRun *dryRunRecorder
+    lastDryRunBlock uint64
 }
 
 
-// Network balance info
+// Network balance info, including the details of every minipool used to calculate it
 type networkBalances struct {
-    Block uint64
-    DepositPool *big.Int
-    MinipoolsTotal *big.Int
-    MinipoolsStaking *big.Int
-    RETHContract *big.Int
-    RETHSupply *big.Int
+    Block uint64                            `json:"block"`
+    BlockTime uint64                        `json:"blockTime"`
+    Epoch uint64                            `json:"epoch"`
+    DepositPool *big.Int                    `json:"depositPool"`
+    MinipoolsTotal *big.Int                 `json:"minipoolsTotal"`
+    MinipoolsStaking *big.Int               `json:"minipoolsStaking"`
+    RETHContract *big.Int                   `json:"rethContract"`
+    RETHSupply *big.Int                     `json:"rethSupply"`
+    TotalEth *big.Int                       `json:"totalEth"`
+    Minipools []minipoolBalanceDetails      `json:"minipools"`
 }
 type minipoolBalanceDetails struct {
-    IsStaking bool
-    UserBalance *big.Int
+    Address common.Address                  `json:"address"`
+    Status types.MinipoolStatus             `json:"status"`
+    UserDepositBalance *big.Int             `json:"userDepositBalance"`
+    ValidatorExists bool                    `json:"validatorExists"`
+    ValidatorPubkey types.ValidatorPubkey   `json:"validatorPubkey"`
+    ActivationEpoch uint64                  `json:"activationEpoch"`
+    ExitEpoch uint64                        `json:"exitEpoch"`
+    BeaconBalance uint64                    `json:"beaconBalance"`
+    IsStaking bool                          `json:"isStaking"`
+    UserBalance *big.Int                    `json:"userBalance"`
+    UserBalanceSource string                `json:"userBalanceSource"`
 }
 
+// Minipool user balance sources
+const (
+    UserBalanceSourceNone = "none"
+    UserBalanceSourceDeposit = "userDeposit"
+    UserBalanceSourceShare = "userShare"
+)
+
 
 // Create submit network balances task
-func newSubmitNetworkBalances(c *cli.Context, logger log.ColorLogger) (*submitNetworkBalances, error) {
+func newSubmitNetworkBalances(c *cli.Context, logger log.Logger, dryRun *dryRunRecorder) (*submitNetworkBalances, error) {
 
     // Get services
     cfg, err := services.GetConfig(c)
@@ -83,6 +108,8 @@ func newSubmitNetworkBalances(c *cli.Context, logger log.ColorLogger) (*submitNe
     if err != nil { return nil, err }
     bc, err := services.GetBeaconClient(c)
     if err != nil { return nil, err }
+    mc, err := services.GetMultiCaller(c)
+    if err != nil { return nil, err }
 
     // Get the user-requested max fee
     maxFee, err := cfg.GetMaxFee()
@@ -96,7 +123,7 @@ func newSubmitNetworkBalances(c *cli.Context, logger log.ColorLogger) (*submitNe
         return nil, fmt.Errorf("Error getting max priority fee in configuration: %w", err)
     }
     if maxPriorityFee == nil || maxPriorityFee.Uint64() == 0 {
-        logger.Println("WARNING: priority fee was missing or 0, setting a default of 2.");
+        logger.Warn("Priority fee was missing or 0, setting a default of 2.");
         maxPriorityFee = big.NewInt(2)
     }
 
@@ -106,6 +133,12 @@ func newSubmitNetworkBalances(c *cli.Context, logger log.ColorLogger) (*submitNe
         return nil, fmt.Errorf("Error getting gas limit in configuration: %w", err)
     }
 
+    // Get the balance snapshot retention
+    snapshotsKept, snapshotMaxAge, err := cfg.GetBalanceSnapshotRetention()
+    if err != nil {
+        return nil, fmt.Errorf("Error getting balance snapshot retention in configuration: %w", err)
+    }
+
     // Return task
     return &submitNetworkBalances{
         c: c,
@@ -115,9 +148,13 @@ func newSubmitNetworkBalances(c *cli.Context, logger log.ColorLogger) (*submitNe
         ec: ec,
         rp: rp,
         bc: bc,
+        mc: mc,
         maxFee: maxFee,
         maxPriorityFee: maxPriorityFee,
         gasLimit: gasLimit,
+        snapshotsKept: snapshotsKept,
+        snapshotMaxAge: snapshotMaxAge,
+        dryRun: dryRun,
     }, nil
 
 }
@@ -162,13 +199,13 @@ func (t *submitNetworkBalances) run() error {
         return err
     }
 
-    // Check node trusted status & settings
-    if !(nodeTrusted && submitBalancesEnabled) {
+    // Check node trusted status & settings; membership is not required in dry-run mode
+    if !((nodeTrusted || t.dryRun != nil) && submitBalancesEnabled) {
         return nil
     }
 
     // Log
-    t.log.Println("Checking for network balance checkpoint...")
+    t.log.Info("Checking for network balance checkpoint...")
 
     // Get block to submit balances for
     blockNumber, err := t.getLatestReportableBlock()
@@ -185,6 +222,14 @@ func (t *submitNetworkBalances) run() error {
         return nil
     }
 
+    // In dry-run mode, wait for the members to submit and then compare each reportable block once
+    if t.dryRun != nil {
+        if blockNumber <= t.lastDryRunBlock || blockNumber + ConfirmDistanceBalances > currentBlockNumber {
+            return nil
+        }
+        return t.runDryRun(blockNumber)
+    }
+
     // Check if a submission needs to be made
     balancesBlock, err := network.GetBalancesBlock(t.rp, nil)
     if err != nil {
@@ -206,7 +251,7 @@ func (t *submitNetworkBalances) run() error {
     }
 
     // Log
-    t.log.Printlnf("Calculating network balances for block %d...", blockNumber)
+    t.log.Infof("Calculating network balances for block %d...", blockNumber)
 
     // Get network balances at block
     balances, err := t.getNetworkBalances(blockNumber)
@@ -214,12 +259,15 @@ func (t *submitNetworkBalances) run() error {
         return err
     }
 
+    // Save a snapshot of the balances
+    t.saveBalanceSnapshot(balances)
+
     // Log
-    t.log.Printlnf("Deposit pool balance: %.6f ETH", math.RoundDown(eth.WeiToEth(balances.DepositPool), 6))
-    t.log.Printlnf("Total minipool user balance: %.6f ETH", math.RoundDown(eth.WeiToEth(balances.MinipoolsTotal), 6))
-    t.log.Printlnf("Staking minipool user balance: %.6f ETH", math.RoundDown(eth.WeiToEth(balances.MinipoolsStaking), 6))
-    t.log.Printlnf("rETH contract balance: %.6f ETH", math.RoundDown(eth.WeiToEth(balances.RETHContract), 6))
-    t.log.Printlnf("rETH token supply: %.6f rETH", math.RoundDown(eth.WeiToEth(balances.RETHSupply), 6))
+    t.log.Infof("Deposit pool balance: %.6f ETH", math.RoundDown(eth.WeiToEth(balances.DepositPool), 6))
+    t.log.Infof("Total minipool user balance: %.6f ETH", math.RoundDown(eth.WeiToEth(balances.MinipoolsTotal), 6))
+    t.log.Infof("Staking minipool user balance: %.6f ETH", math.RoundDown(eth.WeiToEth(balances.MinipoolsStaking), 6))
+    t.log.Infof("rETH contract balance: %.6f ETH", math.RoundDown(eth.WeiToEth(balances.RETHContract), 6))
+    t.log.Infof("rETH token supply: %.6f rETH", math.RoundDown(eth.WeiToEth(balances.RETHSupply), 6))
 
     // Check if we have reported these specific values before
     hasSubmittedSpecific, err := t.hasSubmittedSpecificBlockBalances(nodeAccount.Address, blockNumber, balances)
@@ -236,11 +284,11 @@ func (t *submitNetworkBalances) run() error {
         return err
     }
     if hasSubmitted {
-        t.log.Printlnf("Have previously submitted out-of-date balances for block $d, trying again...", blockNumber)
+        t.log.Infof("Have previously submitted out-of-date balances for block %d, trying again...", blockNumber)
     }
 
     // Log
-    t.log.Println("Submitting balances...")
+    t.log.Info("Submitting balances...")
 
     // Submit balances
     if err := t.submitBalances(balances); err != nil {
@@ -253,6 +301,93 @@ func (t *submitNetworkBalances) run() error {
 }
 
 
+// Calculate network balances for a block and compare them with the existing members' submissions
+func (t *submitNetworkBalances) runDryRun(blockNumber uint64) error {
+
+    // Log
+    t.log.Infof("DRY RUN: Calculating network balances for block %d...", blockNumber)
+
+    // Get network balances at block
+    balances, err := t.getNetworkBalances(blockNumber)
+    if err != nil {
+        return err
+    }
+    t.saveBalanceSnapshot(balances)
+    totalEth := balances.TotalEth
+
+    // Log
+    t.log.Infof("DRY RUN: Would submit total ETH balance %s, staking ETH balance %s and rETH supply %s for block %d.", totalEth.String(), balances.MinipoolsStaking.String(), balances.RETHSupply.String(), blockNumber)
+
+    // Compare with member submissions
+    members, err := getDryRunMemberSubmissions(t.rp, func(memberAddress common.Address) (bool, bool, error) {
+        submitted, err := t.hasSubmittedBlockBalances(memberAddress, blockNumber)
+        if err != nil || !submitted {
+            return false, false, err
+        }
+        matched, err := t.hasSubmittedSpecificBlockBalances(memberAddress, blockNumber, balances)
+        return submitted, matched, err
+    })
+    if err != nil {
+        return err
+    }
+
+    // Compare with the consensus balances if they have been updated for this block
+    record := dryRunRecord{
+        Task: "submitNetworkBalances",
+        Block: blockNumber,
+        Values: map[string]string{
+            "totalEth": totalEth.String(),
+            "stakingEth": balances.MinipoolsStaking.String(),
+            "rethSupply": balances.RETHSupply.String(),
+        },
+        Members: members,
+    }
+    balancesBlock, err := network.GetBalancesBlock(t.rp, nil)
+    if err != nil {
+        return err
+    }
+    if balancesBlock == blockNumber {
+        var wg errgroup.Group
+        var consensusTotalEth, consensusStakingEth, consensusRethSupply *big.Int
+        wg.Go(func() error {
+            var err error
+            consensusTotalEth, err = network.GetTotalETHBalance(t.rp, nil)
+            return err
+        })
+        wg.Go(func() error {
+            var err error
+            consensusStakingEth, err = network.GetStakingETHBalance(t.rp, nil)
+            return err
+        })
+        wg.Go(func() error {
+            var err error
+            consensusRethSupply, err = network.GetTotalRETHSupply(t.rp, nil)
+            return err
+        })
+        if err := wg.Wait(); err != nil {
+            return err
+        }
+        record.ConsensusReached = true
+        record.ConsensusMatched = (consensusTotalEth.Cmp(totalEth) == 0 && consensusStakingEth.Cmp(balances.MinipoolsStaking) == 0 && consensusRethSupply.Cmp(balances.RETHSupply) == 0)
+        record.OnChain = map[string]string{
+            "totalEth": consensusTotalEth.String(),
+            "stakingEth": consensusStakingEth.String(),
+            "rethSupply": consensusRethSupply.String(),
+        }
+    }
+
+    // Record
+    if err := t.dryRun.record(t.log, record); err != nil {
+        return err
+    }
+    t.lastDryRunBlock = blockNumber
+
+    // Return
+    return nil
+
+}
+
+
 // Get the latest block number to report balances for
 func (t *submitNetworkBalances) getLatestReportableBlock() (uint64, error) {
 
@@ -283,11 +418,8 @@ func (t *submitNetworkBalances) hasSubmittedBlockBalances(nodeAddress common.Add
 // Check whether specific balances for a block has already been submitted by the node
 func (t *submitNetworkBalances) hasSubmittedSpecificBlockBalances(nodeAddress common.Address, blockNumber uint64, balances networkBalances) (bool, error) {
 
-    // Calculate total ETH balance
-    totalEth := big.NewInt(0)
-    totalEth.Add(totalEth, balances.DepositPool)
-    totalEth.Add(totalEth, balances.MinipoolsTotal)
-    totalEth.Add(totalEth, balances.RETHContract)
+    // Get total ETH balance
+    totalEth := balances.TotalEth
 
     blockNumberBuf := make([]byte, 32)
     big.NewInt(int64(blockNumber)).FillBytes(blockNumberBuf)
@@ -318,6 +450,7 @@ func (t *submitNetworkBalances) getNetworkBalances(blockNumber uint64) (networkB
     var wg errgroup.Group
     var depositPoolBalance *big.Int
     var minipoolBalanceDetails []minipoolBalanceDetails
+    var blockTime, blockEpoch uint64
     var rethContractBalance *big.Int
     var rethTotalSupply *big.Int
 
@@ -331,7 +464,7 @@ func (t *submitNetworkBalances) getNetworkBalances(blockNumber uint64) (networkB
     // Get minipool balance details
     wg.Go(func() error {
         var err error
-        minipoolBalanceDetails, err = t.getNetworkMinipoolBalanceDetails(opts)
+        minipoolBalanceDetails, blockTime, blockEpoch, err = t.getNetworkMinipoolBalanceDetails(opts)
         return err
     })
 
@@ -360,11 +493,14 @@ func (t *submitNetworkBalances) getNetworkBalances(blockNumber uint64) (networkB
     // Balances
     balances := networkBalances{
         Block: blockNumber,
+        BlockTime: blockTime,
+        Epoch: blockEpoch,
         DepositPool: depositPoolBalance,
         MinipoolsTotal: big.NewInt(0),
         MinipoolsStaking: big.NewInt(0),
         RETHContract: rethContractBalance,
         RETHSupply: rethTotalSupply,
+        Minipools: minipoolBalanceDetails,
     }
 
     // Add minipool balances
@@ -375,14 +511,20 @@ func (t *submitNetworkBalances) getNetworkBalances(blockNumber uint64) (networkB
         }
     }
 
+    // Get total ETH balance
+    balances.TotalEth = big.NewInt(0)
+    balances.TotalEth.Add(balances.TotalEth, balances.DepositPool)
+    balances.TotalEth.Add(balances.TotalEth, balances.MinipoolsTotal)
+    balances.TotalEth.Add(balances.TotalEth, balances.RETHContract)
+
     // Return
     return balances, nil
 
 }
 
 
-// Get all minipool balance details
-func (t *submitNetworkBalances) getNetworkMinipoolBalanceDetails(opts *bind.CallOpts) ([]minipoolBalanceDetails, error) {
+// Get all minipool balance details, along with the block time and the epoch at the block
+func (t *submitNetworkBalances) getNetworkMinipoolBalanceDetails(opts *bind.CallOpts) ([]minipoolBalanceDetails, uint64, uint64, error) {
 
     // Data
     var wg1 errgroup.Group
@@ -394,7 +536,7 @@ func (t *submitNetworkBalances) getNetworkMinipoolBalanceDetails(opts *bind.Call
     // Get minipool addresses
     wg1.Go(func() error {
         var err error
-        addresses, err = minipool.GetMinipoolAddresses(t.rp, opts)
+        addresses, err = rp.GetMinipoolAddresses(t.rp, t.mc, opts)
         return err
     })
 
@@ -423,159 +565,90 @@ func (t *submitNetworkBalances) getNetworkMinipoolBalanceDetails(opts *bind.Call
 
     // Wait for data
     if err := wg1.Wait(); err != nil {
-        return []minipoolBalanceDetails{}, err
+        return []minipoolBalanceDetails{}, 0, 0, err
     }
 
     // Get & check epoch at block
     blockEpoch := eth2.EpochAt(eth2Config, blockTime)
     if blockEpoch > beaconHead.Epoch {
-        return []minipoolBalanceDetails{}, fmt.Errorf("Epoch %d at block %s is higher than current epoch %d", blockEpoch, opts.BlockNumber.String(), beaconHead.Epoch)
+        return []minipoolBalanceDetails{}, 0, 0, fmt.Errorf("Epoch %d at block %s is higher than current epoch %d", blockEpoch, opts.BlockNumber.String(), beaconHead.Epoch)
     }
 
     // Get minipool validator statuses
-    validators, err := rp.GetMinipoolValidators(t.rp, t.bc, addresses, opts, &beacon.ValidatorStatusOptions{Epoch: blockEpoch})
+    validators, err := rp.GetMinipoolValidators(t.rp, t.mc, t.bc, addresses, opts, &beacon.ValidatorStatusOptions{Epoch: blockEpoch})
     if err != nil {
-        return []minipoolBalanceDetails{}, err
-    }
-
-    // Load details in batches
-    details := make([]minipoolBalanceDetails, len(addresses))
-    for bsi := 0; bsi < len(addresses); bsi += MinipoolBalanceDetailsBatchSize {
-
-        // Get batch start & end index
-        msi := bsi
-        mei := bsi + MinipoolBalanceDetailsBatchSize
-        if mei > len(addresses) { mei = len(addresses) }
-
-        // Log
-        //t.log.Printlnf("Calculating balances for minipools %d - %d of %d...", msi + 1, mei, len(addresses))
-
-        // Load details
-        var wg errgroup.Group
-        for mi := msi; mi < mei; mi++ {
-            mi := mi
-            wg.Go(func() error {
-                address := addresses[mi]
-                validator := validators[address]
-                mpDetails, err := t.getMinipoolBalanceDetails(address, opts, validator, eth2Config, blockEpoch)
-                if err == nil { details[mi] = mpDetails }
-                return err
-            })
-        }
-        if err := wg.Wait(); err != nil {
-            return []minipoolBalanceDetails{}, err
-        }
-
+        return []minipoolBalanceDetails{}, 0, 0, err
     }
 
-    // Return
-    return details, nil
-
-}
-
-
-// Get minipool balance details
-func (t *submitNetworkBalances) getMinipoolBalanceDetails(minipoolAddress common.Address, opts *bind.CallOpts, validator beacon.ValidatorStatus, eth2Config beacon.Eth2Config, blockEpoch uint64) (minipoolBalanceDetails, error) {
-
-    // Create minipool
-    mp, err := minipool.NewMinipool(t.rp, minipoolAddress)
+    // Get minipool contracts
+    minipools, err := rp.GetMinipools(t.rp, addresses)
     if err != nil {
-        return minipoolBalanceDetails{}, err
+        return []minipoolBalanceDetails{}, 0, 0, err
     }
 
-    // Data
-    var wg errgroup.Group
-    var status types.MinipoolStatus
-    var userDepositBalance *big.Int
-    var userDepositTime uint64
-
-    // Load data
-    wg.Go(func() error {
-        var err error
-        status, err = mp.GetStatus(opts)
-        return err
-    })
-    wg.Go(func() error {
-        var err error
-        userDepositBalance, err = mp.GetUserDepositBalance(opts)
-        return err
-    })
-    wg.Go(func() error {
-        userDepositAssignedTime, err := mp.GetUserDepositAssignedTime(opts)
-        if err == nil {
-            userDepositTime = uint64(userDepositAssignedTime.Unix())
+    // Load minipool statuses & user deposit balances
+    statuses := make([]uint8, len(minipools))
+    userDepositBalances := make([]*big.Int, len(minipools))
+    batch := t.mc.NewBatch()
+    for mi, mp := range minipools {
+        if err := batch.AddCall(mp.Contract, &statuses[mi], "getStatus"); err != nil {
+            return []minipoolBalanceDetails{}, 0, 0, err
+        }
+        if err := batch.AddCall(mp.Contract, &userDepositBalances[mi], "getUserDepositBalance"); err != nil {
+            return []minipoolBalanceDetails{}, 0, 0, err
         }
-        return err
-    })
-
-    // Wait for data
-    if err := wg.Wait(); err != nil {
-        return minipoolBalanceDetails{}, err
-    }
-
-    // No balance if no user deposit assigned
-    if userDepositBalance.Cmp(big.NewInt(0)) == 0 {
-        return minipoolBalanceDetails{
-            UserBalance: big.NewInt(0),
-        }, nil
     }
+    if err := batch.Execute(opts); err != nil {
+        return []minipoolBalanceDetails{}, 0, 0, err
+    }
+
+    // Get balance details; the user shares of active validators' balances are calculated in a second batch
+    details := make([]minipoolBalanceDetails, len(minipools))
+    shareBatch := t.mc.NewBatch()
+    for mi, mp := range minipools {
+        validator := validators[mp.Address]
+        status := types.MinipoolStatus(statuses[mi])
+        userDepositBalance := userDepositBalances[mi]
+        details[mi] = minipoolBalanceDetails{
+            Address: mp.Address,
+            Status: status,
+            UserDepositBalance: userDepositBalance,
+            ValidatorExists: validator.Exists,
+            ValidatorPubkey: validator.Pubkey,
+            ActivationEpoch: validator.ActivationEpoch,
+            ExitEpoch: validator.ExitEpoch,
+            BeaconBalance: validator.Balance,
+        }
 
-    // Use user deposit balance if initialized or prelaunch
-    if status == types.Initialized || status == types.Prelaunch {
-        return minipoolBalanceDetails{
-            UserBalance: userDepositBalance,
-        }, nil
-    }
+        // No balance if no user deposit assigned
+        if userDepositBalance.Cmp(big.NewInt(0)) == 0 {
+            details[mi].UserBalance = big.NewInt(0)
+            details[mi].UserBalanceSource = UserBalanceSourceNone
+            continue
+        }
 
-    // Use user deposit balance if validator not yet active on beacon chain at block
-    if !validator.Exists || validator.ActivationEpoch >= blockEpoch {
-        return minipoolBalanceDetails{
-            UserBalance: userDepositBalance,
-        }, nil
-    }
+        // Use user deposit balance if initialized or prelaunch, or if validator not yet active on beacon chain at block
+        if status == types.Initialized || status == types.Prelaunch || !validator.Exists || validator.ActivationEpoch >= blockEpoch {
+            details[mi].UserBalance = userDepositBalance
+            details[mi].UserBalanceSource = UserBalanceSourceDeposit
+            continue
+        }
 
-    // Get start epoch for node balance calculation
-    startEpoch := eth2.EpochAt(eth2Config, userDepositTime)
-    if startEpoch < validator.ActivationEpoch {
-        startEpoch = validator.ActivationEpoch
-    } else if startEpoch > blockEpoch {
-        startEpoch = blockEpoch
-    }
+        // Get user balance at block
+        details[mi].IsStaking = (validator.ExitEpoch > blockEpoch)
+        details[mi].UserBalanceSource = UserBalanceSourceShare
+        blockBalance := eth.GweiToWei(float64(validator.Balance))
+        if err := shareBatch.AddCall(mp.Contract, &details[mi].UserBalance, "calculateUserShare", blockBalance); err != nil {
+            return []minipoolBalanceDetails{}, 0, 0, err
+        }
 
-    // Get user balance at block
-    blockBalance := eth.GweiToWei(float64(validator.Balance))
-    userBalance, err := mp.CalculateUserShare(blockBalance, opts)
-    if err != nil {
-        return minipoolBalanceDetails{}, err
     }
-    /*
-    nodeBalance, err := mp.CalculateNodeShare(blockBalance, opts)
-    if err != nil {
-        return minipoolBalanceDetails{}, err
+    if err := shareBatch.Execute(opts); err != nil {
+        return []minipoolBalanceDetails{}, 0, 0, err
     }
 
-    // Log debug details
-    finalised, err := mp.GetFinalised(opts)
-    if err != nil {
-        return minipoolBalanceDetails{}, err
-    }
-    t.log.Printlnf("%s %s %s %d %s %s %s %t",
-        minipoolAddress.Hex(),
-        validator.Pubkey.Hex(),
-        blockBalance.String(),
-        blockEpoch,
-        nodeBalance.String(),
-        userBalance.String(),
-        types.MinipoolStatuses[status],
-        finalised,
-    )
-    */
-    
     // Return
-    return minipoolBalanceDetails{
-        IsStaking: (validator.ExitEpoch > blockEpoch),
-        UserBalance: userBalance,
-    }, nil
+    return details, blockTime, blockEpoch, nil
 
 }
 
@@ -584,13 +657,10 @@ func (t *submitNetworkBalances) getMinipoolBalanceDetails(minipoolAddress common
 func (t *submitNetworkBalances) submitBalances(balances networkBalances) error {
 
     // Log
-    t.log.Printlnf("Submitting network balances for block %d...", balances.Block)
+    t.log.Infof("Submitting network balances for block %d...", balances.Block)
 
-    // Calculate total ETH balance
-    totalEth := big.NewInt(0)
-    totalEth.Add(totalEth, balances.DepositPool)
-    totalEth.Add(totalEth, balances.MinipoolsTotal)
-    totalEth.Add(totalEth, balances.RETHContract)
+    // Get total ETH balance
+    totalEth := balances.TotalEth
 
     // Get transactor
     opts, err := t.w.GetNodeAccountTransactor()
@@ -635,13 +705,13 @@ func (t *submitNetworkBalances) submitBalances(balances networkBalances) error {
     }
 
     // Print TX info and wait for it to be mined
-    err = api.PrintAndWaitForTransaction(t.cfg, hash, t.rp.Client, t.log)
+    err = api.PrintAndWaitForTransaction(t.cfg, hash, t.rp.Client, t.w.GetTxLog(), t.log)
     if err != nil {
         return err
     }
 
     // Log
-    t.log.Printlnf("Successfully submitted network balances for block %d.", balances.Block)
+    t.log.Infof("Successfully submitted network balances for block %d.", balances.Block)
 
     // Return
     return nil
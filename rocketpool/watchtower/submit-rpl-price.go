@@ -7,7 +7,6 @@ import (
 
 	"github.com/ethereum/go-ethereum/ethclient"
 
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/rocket-pool/rocketpool-go/dao/trustednode"
@@ -21,8 +20,8 @@ import (
 
 	"github.com/rocket-pool/smartnode/shared/services"
 	"github.com/rocket-pool/smartnode/shared/services/config"
-	"github.com/rocket-pool/smartnode/shared/services/contracts"
 	rpgas "github.com/rocket-pool/smartnode/shared/services/gas"
+	"github.com/rocket-pool/smartnode/shared/services/prices"
 	"github.com/rocket-pool/smartnode/shared/services/wallet"
 	"github.com/rocket-pool/smartnode/shared/utils/api"
 	"github.com/rocket-pool/smartnode/shared/utils/log"
@@ -36,20 +35,22 @@ const ConfirmDistancePrices = 30
 // Submit RPL price task
 type submitRplPrice struct {
     c *cli.Context
-    log log.ColorLogger
+    log log.Logger
     cfg config.RocketPoolConfig
     ec *ethclient.Client
     w *wallet.Wallet
     rp *rocketpool.RocketPool
-    oio *contracts.OneInchOracle
+    prices *prices.Aggregator
     maxFee *big.Int
     maxPriorityFee *big.Int
     gasLimit uint64
+    dryRun *dryRunRecorder
+    lastDryRunBlock uint64
 }
 
 
 // Create submit RPL price task
-func newSubmitRplPrice(c *cli.Context, logger log.ColorLogger) (*submitRplPrice, error) {
+func newSubmitRplPrice(c *cli.Context, logger log.Logger, dryRun *dryRunRecorder) (*submitRplPrice, error) {
 
     // Get services
     cfg, err := services.GetConfig(c)
@@ -60,7 +61,7 @@ func newSubmitRplPrice(c *cli.Context, logger log.ColorLogger) (*submitRplPrice,
     if err != nil { return nil, err }
     rp, err := services.GetRocketPool(c)
     if err != nil { return nil, err }
-    oio, err := services.GetOneInchOracle(c)
+    priceAggregator, err := services.GetRplPriceAggregator(c)
     if err != nil { return nil, err }
 
     // Get the user-requested max fee
@@ -75,7 +76,7 @@ func newSubmitRplPrice(c *cli.Context, logger log.ColorLogger) (*submitRplPrice,
         return nil, fmt.Errorf("Error getting max priority fee in configuration: %w", err)
     }
     if maxPriorityFee == nil || maxPriorityFee.Uint64() == 0 {
-        logger.Println("WARNING: priority fee was missing or 0, setting a default of 2.");
+        logger.Warn("Priority fee was missing or 0, setting a default of 2.");
         maxPriorityFee = big.NewInt(2)
     }
 
@@ -93,10 +94,11 @@ func newSubmitRplPrice(c *cli.Context, logger log.ColorLogger) (*submitRplPrice,
         ec: ec,
         w: w,
         rp: rp,
-        oio: oio,
+        prices: priceAggregator,
         maxFee: maxFee,
         maxPriorityFee: maxPriorityFee,
         gasLimit: gasLimit,
+        dryRun: dryRun,
     }, nil
 
 }
@@ -138,13 +140,13 @@ func (t *submitRplPrice) run() error {
         return err
     }
 
-    // Check node trusted status & settings
-    if !(nodeTrusted && submitPricesEnabled) {
+    // Check node trusted status & settings; membership is not required in dry-run mode
+    if !((nodeTrusted || t.dryRun != nil) && submitPricesEnabled) {
         return nil
     }
 
     // Log
-    t.log.Println("Checking for RPL price checkpoint...")
+    t.log.Info("Checking for RPL price checkpoint...")
 
     // Get block to submit price for
     blockNumber, err := t.getLatestReportableBlock()
@@ -161,6 +163,14 @@ func (t *submitRplPrice) run() error {
         return nil
     }
 
+    // In dry-run mode, wait for the members to submit and then compare each reportable block once
+    if t.dryRun != nil {
+        if blockNumber <= t.lastDryRunBlock || blockNumber + ConfirmDistancePrices > currentBlockNumber {
+            return nil
+        }
+        return t.runDryRun(blockNumber)
+    }
+
     // Check if a submission needs to be made
     pricesBlock, err := network.GetPricesBlock(t.rp, nil)
     if err != nil {
@@ -182,7 +192,7 @@ func (t *submitRplPrice) run() error {
     }
 
     // Log
-    t.log.Printlnf("Getting RPL price for block %d...", blockNumber)
+    t.log.Infof("Getting RPL price for block %d...", blockNumber)
 
     // Get RPL price at block
     rplPrice, err := t.getRplPrice(blockNumber)
@@ -198,7 +208,7 @@ func (t *submitRplPrice) run() error {
     }
 
     // Log
-    t.log.Printlnf("RPL price: %.6f ETH", mathutils.RoundDown(eth.WeiToEth(rplPrice), 6))
+    t.log.Infof("RPL price: %.6f ETH", mathutils.RoundDown(eth.WeiToEth(rplPrice), 6))
 
     // Check if we have reported these specific values before
     hasSubmittedSpecific, err := t.hasSubmittedSpecificBlockPrices(nodeAccount.Address, blockNumber, rplPrice, effectiveRplStake)
@@ -215,11 +225,11 @@ func (t *submitRplPrice) run() error {
         return err
     }
     if hasSubmitted {
-        t.log.Printlnf("Have previously submitted out-of-date prices for block $d, trying again...", blockNumber)
+        t.log.Infof("Have previously submitted out-of-date prices for block %d, trying again...", blockNumber)
     }
 
     // Log
-    t.log.Println("Submitting RPL price...")
+    t.log.Info("Submitting RPL price...")
 
     // Submit RPL price
     if err := t.submitRplPrice(blockNumber, rplPrice, effectiveRplStake); err != nil {
@@ -232,6 +242,79 @@ func (t *submitRplPrice) run() error {
 }
 
 
+// Get the RPL price for a block and compare it with the existing members' submissions
+func (t *submitRplPrice) runDryRun(blockNumber uint64) error {
+
+    // Log
+    t.log.Infof("DRY RUN: Getting RPL price for block %d...", blockNumber)
+
+    // Get RPL price at block
+    rplPrice, err := t.getRplPrice(blockNumber)
+    if err != nil {
+        return err
+    }
+
+    // Calculate the total effective RPL stake on the network
+    zero := new(big.Int).SetUint64(0)
+    effectiveRplStake, err := node.CalculateTotalEffectiveRPLStake(t.rp, zero, zero, rplPrice, nil)
+    if err != nil {
+        return fmt.Errorf("Error getting total effective RPL stake: %w", err)
+    }
+
+    // Log
+    t.log.Infof("DRY RUN: Would submit RPL price %s and effective RPL stake %s for block %d.", rplPrice.String(), effectiveRplStake.String(), blockNumber)
+
+    // Compare with member submissions
+    members, err := getDryRunMemberSubmissions(t.rp, func(memberAddress common.Address) (bool, bool, error) {
+        submitted, err := t.hasSubmittedBlockPrices(memberAddress, blockNumber)
+        if err != nil || !submitted {
+            return false, false, err
+        }
+        matched, err := t.hasSubmittedSpecificBlockPrices(memberAddress, blockNumber, rplPrice, effectiveRplStake)
+        return submitted, matched, err
+    })
+    if err != nil {
+        return err
+    }
+
+    // Compare with the consensus price if it has been updated for this block
+    record := dryRunRecord{
+        Task: "submitRplPrice",
+        Block: blockNumber,
+        Values: map[string]string{
+            "rplPrice": rplPrice.String(),
+            "effectiveRplStake": effectiveRplStake.String(),
+        },
+        Members: members,
+    }
+    pricesBlock, err := network.GetPricesBlock(t.rp, nil)
+    if err != nil {
+        return err
+    }
+    if pricesBlock == blockNumber {
+        consensusRplPrice, err := network.GetRPLPrice(t.rp, nil)
+        if err != nil {
+            return err
+        }
+        record.ConsensusReached = true
+        record.ConsensusMatched = (consensusRplPrice.Cmp(rplPrice) == 0)
+        record.OnChain = map[string]string{
+            "rplPrice": consensusRplPrice.String(),
+        }
+    }
+
+    // Record
+    if err := t.dryRun.record(t.log, record); err != nil {
+        return err
+    }
+    t.lastDryRunBlock = blockNumber
+
+    // Return
+    return nil
+
+}
+
+
 // Get the latest block number to report RPL price for
 func (t *submitRplPrice) getLatestReportableBlock() (uint64, error) {
 
@@ -276,29 +359,32 @@ func (t *submitRplPrice) hasSubmittedSpecificBlockPrices(nodeAddress common.Addr
 }
 
 
-// Get RPL price at block
+// Get RPL price at block from the configured price sources
 func (t *submitRplPrice) getRplPrice(blockNumber uint64) (*big.Int, error) {
 
-    // Require 1inch oracle contract
-    if err := services.RequireOneInchOracle(t.c); err != nil {
-        return nil, err
-    }
-
-    // Get RPL token address
-    rplAddress := common.HexToAddress(t.cfg.Rocketpool.RplTokenAddress)
-
-    // Initialize call options
-    opts := &bind.CallOpts{
-        BlockNumber: big.NewInt(int64(blockNumber)),
+    // Require 1inch oracle contract if used
+    if t.prices.UsesOneInchOracle() {
+        if err := services.RequireOneInchOracle(t.c); err != nil {
+            return nil, err
+        }
     }
 
     // Get RPL price
-    rplPrice, err := t.oio.GetRateToEth(opts, rplAddress, true)
-    if err != nil {
-        return nil, fmt.Errorf("Could not get RPL price at block %d: %w", blockNumber, err)
+    rplPrice, sourcePrices, err := t.prices.GetRplPrice(blockNumber)
+
+    // Log source prices
+    for _, sourcePrice := range sourcePrices {
+        if sourcePrice.Error != nil {
+            t.log.Warnf("RPL price from %s: error (%s)", sourcePrice.Source, sourcePrice.Error.Error())
+        } else if sourcePrice.Price != nil {
+            t.log.Infof("RPL price from %s: %.6f ETH", sourcePrice.Source, mathutils.RoundDown(eth.WeiToEth(sourcePrice.Price), 6))
+        }
     }
 
     // Return
+    if err != nil {
+        return nil, fmt.Errorf("Refusing to submit RPL price for block %d: %w", blockNumber, err)
+    }
     return rplPrice, nil
 
 }
@@ -308,7 +394,7 @@ func (t *submitRplPrice) getRplPrice(blockNumber uint64) (*big.Int, error) {
 func (t *submitRplPrice) submitRplPrice(blockNumber uint64, rplPrice, effectiveRplStake *big.Int) error {
 
     // Log
-    t.log.Printlnf("Submitting RPL price for block %d...", blockNumber)
+    t.log.Infof("Submitting RPL price for block %d...", blockNumber)
 
     // Get transactor
     opts, err := t.w.GetNodeAccountTransactor()
@@ -353,13 +439,13 @@ func (t *submitRplPrice) submitRplPrice(blockNumber uint64, rplPrice, effectiveR
     }
 
     // Print TX info and wait for it to be mined
-    err = api.PrintAndWaitForTransaction(t.cfg, hash, t.rp.Client, t.log)
+    err = api.PrintAndWaitForTransaction(t.cfg, hash, t.rp.Client, t.w.GetTxLog(), t.log)
     if err != nil {
         return err
     }
 
     // Log
-    t.log.Printlnf("Successfully submitted RPL price for block %d.", blockNumber)
+    t.log.Infof("Successfully submitted RPL price for block %d.", blockNumber)
 
     // Return
     return nil
@@ -6,16 +6,16 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/prysmaticlabs/prysm/v2/beacon-chain/core/signing"
 	prdeposit "github.com/prysmaticlabs/prysm/v2/contracts/deposit"
 	"github.com/rocket-pool/rocketpool-go/dao/trustednode"
 	"github.com/rocket-pool/rocketpool-go/minipool"
 	"github.com/rocket-pool/rocketpool-go/rocketpool"
 	tnsettings "github.com/rocket-pool/rocketpool-go/settings/trustednode"
 	"github.com/rocket-pool/rocketpool-go/types"
-	"github.com/rocket-pool/rocketpool-go/utils"
 	"github.com/rocket-pool/rocketpool-go/utils/eth"
 	"github.com/urfave/cli"
 
@@ -24,11 +24,13 @@ import (
 	"github.com/rocket-pool/smartnode/shared/services"
 	"github.com/rocket-pool/smartnode/shared/services/beacon"
 	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/deposits"
 	rpgas "github.com/rocket-pool/smartnode/shared/services/gas"
+	"github.com/rocket-pool/smartnode/shared/services/notify"
+	"github.com/rocket-pool/smartnode/shared/services/scrubs"
 	"github.com/rocket-pool/smartnode/shared/services/wallet"
 	"github.com/rocket-pool/smartnode/shared/utils/api"
 	"github.com/rocket-pool/smartnode/shared/utils/log"
-	eth2types "github.com/wealdtech/go-eth2-types/v2"
 )
 
 // Settings
@@ -41,7 +43,7 @@ const MinScrubSafetyTime = time.Duration(0) * time.Hour
 // Submit scrub minipools task
 type submitScrubMinipools struct {
     c *cli.Context
-    log log.ColorLogger
+    log log.Logger
     cfg config.RocketPoolConfig
     w *wallet.Wallet
     rp *rocketpool.RocketPool
@@ -52,6 +54,12 @@ type submitScrubMinipools struct {
     maxFee *big.Int
     maxPriorityFee *big.Int
     gasLimit uint64
+    dryRun *dryRunRecorder
+    dryRunMinipools map[common.Address]bool
+    evidence *scrubs.EvidenceStore
+    deposits *deposits.Indexer
+    notifier *notify.Notifier
+    notified map[common.Address]bool
 }
 
 
@@ -81,11 +89,12 @@ type iterationData struct {
 type minipoolDetails struct {
     pubkey types.ValidatorPubkey
     expectedWithdrawalCredentials common.Hash
+    invalidDeposits []scrubs.DepositEvidence
 }
 
 
 // Create submit scrub minipools task
-func newSubmitScrubMinipools(c *cli.Context, logger log.ColorLogger, coll *collectors.ScrubCollector) (*submitScrubMinipools, error) {
+func newSubmitScrubMinipools(c *cli.Context, logger log.Logger, coll *collectors.ScrubCollector, dryRun *dryRunRecorder) (*submitScrubMinipools, error) {
 
     // Get services
     cfg, err := services.GetConfig(c)
@@ -98,6 +107,12 @@ func newSubmitScrubMinipools(c *cli.Context, logger log.ColorLogger, coll *colle
     if err != nil { return nil, err }
     bc, err := services.GetBeaconClient(c)
     if err != nil { return nil, err }
+    evidence, err := services.GetScrubEvidenceStore(c)
+    if err != nil { return nil, err }
+    depositIndexer, err := services.GetDepositIndexer(c)
+    if err != nil { return nil, err }
+    notifier, err := services.GetNotifier(c)
+    if err != nil { return nil, err }
 
     // Get the user-requested max fee
     maxFee, err := cfg.GetMaxFee()
@@ -111,7 +126,7 @@ func newSubmitScrubMinipools(c *cli.Context, logger log.ColorLogger, coll *colle
         return nil, fmt.Errorf("Error getting max priority fee in configuration: %w", err)
     }
     if maxPriorityFee == nil || maxPriorityFee.Uint64() == 0 {
-        logger.Println("WARNING: priority fee was missing or 0, setting a default of 2.");
+        logger.Warn("Priority fee was missing or 0, setting a default of 2.");
         maxPriorityFee = big.NewInt(2)
     }
 
@@ -134,6 +149,12 @@ func newSubmitScrubMinipools(c *cli.Context, logger log.ColorLogger, coll *colle
         maxFee: maxFee,
         maxPriorityFee: maxPriorityFee,
         gasLimit: gasLimit,
+        dryRun: dryRun,
+        dryRunMinipools: make(map[common.Address]bool),
+        evidence: evidence,
+        deposits: depositIndexer,
+        notifier: notifier,
+        notified: make(map[common.Address]bool),
     }, nil
 
 }
@@ -161,12 +182,12 @@ func (t *submitScrubMinipools) run() error {
     if err != nil {
         return err
     }
-    if !(nodeTrusted) {
+    if !(nodeTrusted || t.dryRun != nil) {
         return nil
     }
 
     // Log
-    t.log.Println("Checking for minipools to scrub...")
+    t.log.Info("Checking for minipools to scrub...")
     t.it = new(iterationData)
 
     // Get minipools in prelaunch status
@@ -176,7 +197,7 @@ func (t *submitScrubMinipools) run() error {
     }
     t.it.totalMinipools = len(minipoolAddresses)
     if t.it.totalMinipools == 0 {
-        t.log.Println("No minipools in prelaunch.")
+        t.log.Info("No minipools in prelaunch.")
         return nil
     }
     t.it.minipools = make(map[*minipool.Minipool]*minipoolDetails, t.it.totalMinipools)
@@ -246,21 +267,21 @@ func (t *submitScrubMinipools) initializeMinipoolDetails(minipoolAddresses []com
         // Create a minipool contract wrapper for the given address
         mp, err := minipool.NewMinipool(t.rp, minipoolAddress)
         if err != nil {
-            t.log.Printf("Error creating minipool wrapper for %s: %s", minipoolAddress.Hex(), err.Error())
+            t.log.WithMinipool(minipoolAddress).Warnf("Error creating minipool wrapper for %s: %s", minipoolAddress.Hex(), err.Error())
             continue
         }
 
         // Get the correct withdrawal credentials
         expectedCreds, err := minipool.GetMinipoolWithdrawalCredentials(t.rp, minipoolAddress, nil)
         if err != nil {
-            t.log.Printf("Error getting expected withdrawal creds for minipool %s: %s", minipoolAddress.Hex(), err.Error())
+            t.log.WithMinipool(minipoolAddress).Warnf("Error getting expected withdrawal creds for minipool %s: %s", minipoolAddress.Hex(), err.Error())
             continue
         }
 
         // Get the validator pubkey
         pubkey, err := minipool.GetMinipoolPubkey(t.rp, minipoolAddress, nil)
         if err != nil {
-            t.log.Printf("Error getting validator pubkey for minipool %s: %s", minipoolAddress.Hex(), err.Error())
+            t.log.WithMinipool(minipoolAddress).Warnf("Error getting validator pubkey for minipool %s: %s", minipoolAddress.Hex(), err.Error())
             continue
         }
         pubkeys = append(pubkeys, pubkey)
@@ -280,7 +301,7 @@ func (t *submitScrubMinipools) initializeMinipoolDetails(minipoolAddresses []com
 // Step 1: Verify the Beacon Chain credentials for a minipool if they're present
 func (t *submitScrubMinipools) verifyBeaconWithdrawalCredentials(pubkeys []types.ValidatorPubkey) (error) {
 
-    minipoolsToScrub := []*minipool.Minipool{}
+    minipoolsToScrub := map[*minipool.Minipool]scrubs.Evidence{}
 
     // Get the status of the validators on the Beacon chain
     statuses, err := t.bc.GetValidatorStatuses(pubkeys, nil)
@@ -298,12 +319,14 @@ func (t *submitScrubMinipools) verifyBeaconWithdrawalCredentials(pubkeys []types
             expectedCreds := details.expectedWithdrawalCredentials
             beaconCreds := status.WithdrawalCredentials
             if beaconCreds != expectedCreds {
-                t.log.Println("=== SCRUB DETECTED ON BEACON CHAIN ===")
-                t.log.Printlnf("\tMinipool: %s", minipool.Address.Hex())
-                t.log.Printlnf("\tExpected creds: %s", expectedCreds.Hex())
-                t.log.Printlnf("\tActual creds: %s", beaconCreds.Hex())
-                t.log.Println("======================================")
-                minipoolsToScrub = append(minipoolsToScrub, minipool)
+                t.log.Info("=== SCRUB DETECTED ON BEACON CHAIN ===")
+                t.log.Infof("\tMinipool: %s", minipool.Address.Hex())
+                t.log.Infof("\tExpected creds: %s", expectedCreds.Hex())
+                t.log.Infof("\tActual creds: %s", beaconCreds.Hex())
+                t.log.Info("======================================")
+                evidence := t.newScrubEvidence(minipool, details, scrubs.StepBeacon, "The validator's withdrawal credentials on the Beacon Chain do not match the minipool's withdrawal credentials")
+                evidence.ObservedWithdrawalCredentials = &beaconCreds
+                minipoolsToScrub[minipool] = evidence
                 t.it.badOnBeaconCount++
             } else {
                 // This minipool's credentials match, it's clean.
@@ -317,10 +340,10 @@ func (t *submitScrubMinipools) verifyBeaconWithdrawalCredentials(pubkeys []types
     }
 
     // Scrub the offending minipools
-    for _, minipool := range minipoolsToScrub {
-        err = t.submitVoteScrubMinipool(minipool)
+    for minipool, evidence := range minipoolsToScrub {
+        err = t.submitVoteScrubMinipool(minipool, evidence)
         if err != nil {
-            t.log.Printlnf("ALERT: Couldn't scrub minipool %s: %s", minipool.Address.Hex(), err.Error())
+            t.log.WithMinipool(minipool.Address).Errorf("Couldn't scrub minipool %s: %s", minipool.Address.Hex(), err.Error())
         }
     }
 
@@ -364,11 +387,7 @@ func (t *submitScrubMinipools) getEth1SearchArtifacts() (error) {
     t.it.eventLogInterval = eventLogInterval
 
     // Put together the signature validation data
-    eth2Config, err := t.bc.GetEth2Config()
-    if err != nil {
-        return err
-    }
-    depositDomain, err := signing.ComputeDomain(eth2types.DomainDeposit, eth2Config.GenesisForkVersion, eth2types.ZeroGenesisValidatorsRoot)
+    depositDomain, err := deposits.GetDepositDomain(t.bc)
     if err != nil {
         return err
     }
@@ -382,14 +401,14 @@ func (t *submitScrubMinipools) getEth1SearchArtifacts() (error) {
 // Step 2: Verify the MinipoolPrestaked event of each minipool
 func (t *submitScrubMinipools) verifyPrestakeEvents() () {
 
-    minipoolsToScrub := []*minipool.Minipool{}
+    minipoolsToScrub := map[*minipool.Minipool]scrubs.Evidence{}
 
     weiPerGwei := big.NewInt(int64(eth.WeiPerGwei))
-    for minipool := range t.it.minipools {
+    for minipool, details := range t.it.minipools {
         // Get the MinipoolPrestaked event
         prestakeData, err := minipool.GetPrestakeEvent(t.it.eventLogInterval, nil)
         if err != nil {
-            t.log.Printlnf("Error getting prestake event for minipool %s: %s", minipool.Address.Hex(), err.Error())
+            t.log.WithMinipool(minipool.Address).Warnf("Error getting prestake event for minipool %s: %s", minipool.Address.Hex(), err.Error())
             continue
         }
 
@@ -407,13 +426,21 @@ func (t *submitScrubMinipools) verifyPrestakeEvents() () {
         err = prdeposit.VerifyDepositSignature(depositData, t.it.depositDomain)
         if err != nil {
             // The signature is illegal
-            t.log.Println("=== SCRUB DETECTED ON PRESTAKE EVENT ===")
-            t.log.Printlnf("Invalid prestake data for minipool %s:", minipool.Address.Hex())
-            t.log.Printlnf("\tError: %s", err.Error())
-            t.log.Println("========================================")
+            t.log.Info("=== SCRUB DETECTED ON PRESTAKE EVENT ===")
+            t.log.WithMinipool(minipool.Address).Infof("Invalid prestake data for minipool %s:", minipool.Address.Hex())
+            t.log.Infof("\tError: %s", err.Error())
+            t.log.Info("========================================")
 
             // Remove this minipool from the list of things to process in the next step
-            minipoolsToScrub = append(minipoolsToScrub, minipool)
+            evidence := t.newScrubEvidence(minipool, details, scrubs.StepPrestake, "The deposit signature in the minipool's prestake event is invalid")
+            evidence.SignatureError = err.Error()
+            evidence.Prestake = &scrubs.PrestakeEvidence{
+                WithdrawalCredentials: prestakeData.WithdrawalCredentials,
+                Amount: depositData.Amount,
+                Signature: prestakeData.Signature,
+                Time: prestakeData.Time,
+            }
+            minipoolsToScrub[minipool] = evidence
             t.it.badPrestakeCount++
             delete(t.it.minipools, minipool)
         } else {
@@ -423,10 +450,10 @@ func (t *submitScrubMinipools) verifyPrestakeEvents() () {
     }
 
     // Scrub the offending minipools
-    for _, minipool := range minipoolsToScrub {
-        err := t.submitVoteScrubMinipool(minipool)
+    for minipool, evidence := range minipoolsToScrub {
+        err := t.submitVoteScrubMinipool(minipool, evidence)
         if err != nil {
-            t.log.Printlnf("ALERT: Couldn't scrub minipool %s: %s", minipool.Address.Hex(), err.Error())
+            t.log.WithMinipool(minipool.Address).Errorf("Couldn't scrub minipool %s: %s", minipool.Address.Hex(), err.Error())
         }
     }
 
@@ -438,7 +465,7 @@ func (t *submitScrubMinipools) verifyPrestakeEvents() () {
 // Step 3: Verify minipools by their deposits
 func (t *submitScrubMinipools) verifyDeposits() (error) {
 
-    minipoolsToScrub := []*minipool.Minipool{}
+    minipoolsToScrub := map[*minipool.Minipool]scrubs.Evidence{}
 
     // Create a "hashset" of the remaining pubkeys
     pubkeys := make(map[types.ValidatorPubkey]bool, len(t.it.minipools))
@@ -446,8 +473,8 @@ func (t *submitScrubMinipools) verifyDeposits() (error) {
         pubkeys[details.pubkey] = true
     }
 
-    // Get the deposits from the deposit contract
-    depositMap, err := utils.GetDeposits(t.rp, pubkeys, t.it.startBlock, t.it.eventLogInterval, nil)
+    // Get the deposits from the deposit contract index
+    depositMap, err := t.deposits.UpdateAndGetDeposits(pubkeys)
     if err != nil {
         return err
     }
@@ -456,41 +483,49 @@ func (t *submitScrubMinipools) verifyDeposits() (error) {
     for minipool, details := range t.it.minipools {
 
         // Get the deposit list for this minipool
-        deposits, exists := depositMap[details.pubkey]
-        if !exists || len(deposits) == 0 {
+        minipoolDeposits, exists := depositMap[details.pubkey]
+        if !exists || len(minipoolDeposits) == 0 {
             // Somehow this minipool doesn't have a deposit?
             t.it.unknownMinipools++
             continue
         }
 
         // Go through each deposit for this minipool and find the first one that's valid
-        for depositIndex, deposit := range deposits {
-            depositData := new(ethpb.Deposit_Data)
-            depositData.Amount = deposit.Amount
-            depositData.PublicKey = deposit.Pubkey.Bytes()
-            depositData.WithdrawalCredentials = deposit.WithdrawalCredentials.Bytes()
-            depositData.Signature = deposit.Signature.Bytes()
-            
-            err := prdeposit.VerifyDepositSignature(depositData, t.it.depositDomain)
+        for depositIndex, deposit := range minipoolDeposits {
+            depositEvidence := scrubs.DepositEvidence{
+                TxHash: deposit.TxHash,
+                BlockNumber: deposit.BlockNumber,
+                TxIndex: deposit.TxIndex,
+                DepositIndex: depositIndex,
+                Amount: deposit.Amount,
+                WithdrawalCredentials: deposit.WithdrawalCredentials,
+                Signature: deposit.Signature,
+            }
+            err := deposits.VerifyDepositSignature(deposit, t.it.depositDomain)
             if err != nil {
                 // This isn't a valid deposit, so ignore it
-                t.log.Printlnf("Invalid deposit for minipool %s:", minipool.Address.Hex())
-                t.log.Printlnf("\tTX Hash: %s", deposit.TxHash.Hex())
-                t.log.Printlnf("\tBlock: %d, TX Index: %d, Deposit Index: %d", deposit.BlockNumber, deposit.TxIndex, depositIndex)
-                t.log.Printlnf("\tError: %s", err.Error())
+                t.log.WithMinipool(minipool.Address).Infof("Invalid deposit for minipool %s:", minipool.Address.Hex())
+                t.log.Infof("\tTX Hash: %s", deposit.TxHash.Hex())
+                t.log.Infof("\tBlock: %d, TX Index: %d, Deposit Index: %d", deposit.BlockNumber, deposit.TxIndex, depositIndex)
+                t.log.Infof("\tError: %s", err.Error())
+                depositEvidence.Error = err.Error()
+                details.invalidDeposits = append(details.invalidDeposits, depositEvidence)
             } else {
                 // This is a valid deposit
                 expectedCreds := details.expectedWithdrawalCredentials
                 actualCreds := deposit.WithdrawalCredentials
                 if actualCreds != expectedCreds {
-                    t.log.Println("=== SCRUB DETECTED ON DEPOSIT CONTRACT ===")
-                    t.log.Printlnf("\tTX Hash: %s", deposit.TxHash.Hex())
-                    t.log.Printlnf("\tBlock: %d, TX Index: %d, Deposit Index: %d", deposit.BlockNumber, deposit.TxIndex, depositIndex)
-                    t.log.Printlnf("\tMinipool: %s", minipool.Address.Hex())
-                    t.log.Printlnf("\tExpected creds: %s", expectedCreds.Hex())
-                    t.log.Printlnf("\tActual creds: %s", actualCreds.Hex())
-                    t.log.Println("==========================================")
-                    minipoolsToScrub = append(minipoolsToScrub, minipool)
+                    t.log.Info("=== SCRUB DETECTED ON DEPOSIT CONTRACT ===")
+                    t.log.Infof("\tTX Hash: %s", deposit.TxHash.Hex())
+                    t.log.Infof("\tBlock: %d, TX Index: %d, Deposit Index: %d", deposit.BlockNumber, deposit.TxIndex, depositIndex)
+                    t.log.Infof("\tMinipool: %s", minipool.Address.Hex())
+                    t.log.Infof("\tExpected creds: %s", expectedCreds.Hex())
+                    t.log.Infof("\tActual creds: %s", actualCreds.Hex())
+                    t.log.Info("==========================================")
+                    evidence := t.newScrubEvidence(minipool, details, scrubs.StepDepositContract, "The withdrawal credentials of the validator's first valid deposit do not match the minipool's withdrawal credentials")
+                    evidence.ObservedWithdrawalCredentials = &actualCreds
+                    evidence.Deposit = &depositEvidence
+                    minipoolsToScrub[minipool] = evidence
                     t.it.badOnDepositContract++
                 } else {
                     t.it.goodOnDepositContract++
@@ -504,10 +539,10 @@ func (t *submitScrubMinipools) verifyDeposits() (error) {
     }
 
     // Scrub the offending minipools
-    for _, minipool := range minipoolsToScrub {
-        err := t.submitVoteScrubMinipool(minipool)
+    for minipool, evidence := range minipoolsToScrub {
+        err := t.submitVoteScrubMinipool(minipool, evidence)
         if err != nil {
-            t.log.Printlnf("ALERT: Couldn't scrub minipool %s: %s", minipool.Address.Hex(), err.Error())
+            t.log.WithMinipool(minipool.Address).Errorf("Couldn't scrub minipool %s: %s", minipool.Address.Hex(), err.Error())
         }
     }
 
@@ -520,12 +555,12 @@ func (t *submitScrubMinipools) verifyDeposits() (error) {
 // This should never be used, it's simply here as a redundant check
 func (t *submitScrubMinipools) checkSafetyScrub() (error) {
 
-    minipoolsToScrub := []*minipool.Minipool{}
+    minipoolsToScrub := map[*minipool.Minipool]scrubs.Evidence{}
 
     // Warn if there are any remaining minipools - this should never happen
     remainingMinipools := len(t.it.minipools)
     if remainingMinipools > 0 {
-        t.log.Printlnf("WARNING: %d minipools did not have deposit information", remainingMinipools)
+        t.log.Warnf("%d minipools did not have deposit information", remainingMinipools)
     } else {
         return nil
     }
@@ -543,28 +578,32 @@ func (t *submitScrubMinipools) checkSafetyScrub() (error) {
         safetyPeriod = MinScrubSafetyTime
     }
 
-    for minipool := range t.it.minipools {
+    for minipool, details := range t.it.minipools {
         // Get the minipool's status
         statusDetails, err := minipool.GetStatusDetails(nil)
         if err != nil {
-            t.log.Printlnf("Error getting status for minipool %s: %s", minipool.Address.Hex(), err.Error())
+            t.log.WithMinipool(minipool.Address).Warnf("Error getting status for minipool %s: %s", minipool.Address.Hex(), err.Error())
             continue
         }
 
         // Verify this is actually a prelaunch minipool
         if statusDetails.Status != types.Prelaunch {
-            t.log.Printlnf("\tMinipool %s is under review but is in %d status?", minipool.Address.Hex(), types.MinipoolDepositTypes[statusDetails.Status])
+            t.log.WithMinipool(minipool.Address).Infof("\tMinipool %s is under review but is in %s status?", minipool.Address.Hex(), types.MinipoolDepositTypes[statusDetails.Status])
             continue
         }
 
         // Check the time it entered prelaunch against the safety period
         if (t.it.latestBlockTime.Sub(statusDetails.StatusTime)) > safetyPeriod {
-            t.log.Println("=== SAFETY SCRUB DETECTED ===")
-            t.log.Printlnf("\tMinipool: %s", minipool.Address.Hex())
-            t.log.Printlnf("\tTime since prelaunch: %s", time.Since(statusDetails.StatusTime))
-            t.log.Printlnf("\tSafety scrub period: %s", safetyPeriod)
-            t.log.Println("=============================")
-            minipoolsToScrub = append(minipoolsToScrub, minipool)
+            t.log.Info("=== SAFETY SCRUB DETECTED ===")
+            t.log.Infof("\tMinipool: %s", minipool.Address.Hex())
+            t.log.Infof("\tTime since prelaunch: %s", time.Since(statusDetails.StatusTime))
+            t.log.Infof("\tSafety scrub period: %s", safetyPeriod)
+            t.log.Info("=============================")
+            evidence := t.newScrubEvidence(minipool, details, scrubs.StepSafety, "No valid deposit was found for the validator within the safety scrub period")
+            evidence.PrelaunchTime = &statusDetails.StatusTime
+            evidence.LatestBlockTime = &t.it.latestBlockTime
+            evidence.SafetyPeriod = safetyPeriod.String()
+            minipoolsToScrub[minipool] = evidence
             t.it.safetyScrubs++
             // Remove this minipool from the list of things to process in the next step
             delete(t.it.minipools, minipool)
@@ -572,10 +611,10 @@ func (t *submitScrubMinipools) checkSafetyScrub() (error) {
     }
 
     // Scrub the offending minipools
-    for _, minipool := range minipoolsToScrub {
-        err := t.submitVoteScrubMinipool(minipool)
+    for minipool, evidence := range minipoolsToScrub {
+        err := t.submitVoteScrubMinipool(minipool, evidence)
         if err != nil {
-            t.log.Printlnf("ALERT: Couldn't scrub minipool %s: %s", minipool.Address.Hex(), err.Error())
+            t.log.WithMinipool(minipool.Address).Errorf("Couldn't scrub minipool %s: %s", minipool.Address.Hex(), err.Error())
         }
     }
 
@@ -584,22 +623,47 @@ func (t *submitScrubMinipools) checkSafetyScrub() (error) {
 }
 
 
-// Submit minipool scrub status
-func (t *submitScrubMinipools) submitVoteScrubMinipool(mp *minipool.Minipool) error {
+// Submit minipool scrub status, recording the evidence for the vote
+func (t *submitScrubMinipools) submitVoteScrubMinipool(mp *minipool.Minipool, evidence scrubs.Evidence) error {
+
+    // Record the evidence before voting
+    t.saveScrubEvidence(&evidence)
+
+    // In dry-run mode, compare with the members' scrub votes instead
+    if t.dryRun != nil {
+        return t.runDryRun(mp)
+    }
+
+    // Vote and record the result
+    hash, err := t.voteScrubMinipool(mp)
+    if err != nil {
+        evidence.VoteError = err.Error()
+    } else if hash != nil {
+        evidence.VoteTxHash = hash
+        evidence.VoteError = ""
+    }
+    t.saveScrubEvidence(&evidence)
+    return err
+
+}
+
+
+// Vote to scrub a minipool, returning the vote transaction hash if it was sent
+func (t *submitScrubMinipools) voteScrubMinipool(mp *minipool.Minipool) (*common.Hash, error) {
 
     // Log
-    t.log.Printlnf("Voting to scrub minipool %s...", mp.Address.Hex())
+    t.log.WithMinipool(mp.Address).Infof("Voting to scrub minipool %s...", mp.Address.Hex())
 
     // Get transactor
     opts, err := t.w.GetNodeAccountTransactor()
     if err != nil {
-        return err
+        return nil, err
     }
 
     // Get the gas limit
     gasInfo, err := mp.EstimateVoteScrubGas(opts)
     if err != nil {
-        return fmt.Errorf("Could not estimate the gas required to voteScrub the minipool: %w", err)
+        return nil, fmt.Errorf("Could not estimate the gas required to voteScrub the minipool: %w", err)
     }
     var gas *big.Int 
     if t.gasLimit != 0 {
@@ -613,13 +677,13 @@ func (t *submitScrubMinipools) submitVoteScrubMinipool(mp *minipool.Minipool) er
     if maxFee == nil || maxFee.Uint64() == 0 {
         maxFee, err = rpgas.GetHeadlessMaxFeeWei()
         if err != nil {
-            return err
+            return nil, err
         }
     }
 
     // Print the gas info
     if !api.PrintAndCheckGasInfo(gasInfo, false, 0, t.log, maxFee, t.gasLimit) {
-        return nil
+        return nil, nil
     }
 
     opts.GasFeeCap = maxFee
@@ -629,17 +693,82 @@ func (t *submitScrubMinipools) submitVoteScrubMinipool(mp *minipool.Minipool) er
     // Dissolve
     hash, err := mp.VoteScrub(opts)
     if err != nil {
-        return err
+        return nil, err
     }
 
     // Print TX info and wait for it to be mined
-    err = api.PrintAndWaitForTransaction(t.cfg, hash, t.rp.Client, t.log)
+    err = api.PrintAndWaitForTransaction(t.cfg, hash, t.rp.Client, t.w.GetTxLog(), t.log)
     if err != nil {
-        return err
+        return &hash, err
+    }
+
+    // Log
+    t.log.WithMinipool(mp.Address).Infof("Successfully voted to scrub the minipool %s.", mp.Address.Hex())
+
+    // Return
+    return &hash, nil
+
+}
+
+
+// Compare a scrub vote with the existing members' scrub votes
+func (t *submitScrubMinipools) runDryRun(mp *minipool.Minipool) error {
+
+    // Check if the minipool has already been compared
+    if t.dryRunMinipools[mp.Address] {
+        return nil
     }
 
     // Log
-    t.log.Printlnf("Successfully voted to scrub the minipool %s.", mp.Address.Hex())
+    t.log.WithMinipool(mp.Address).Infof("DRY RUN: Would vote to scrub minipool %s.", mp.Address.Hex())
+
+    // Get the members who have voted to scrub the minipool
+    logs, err := t.ec.FilterLogs(context.Background(), ethereum.FilterQuery{
+        Addresses: []common.Address{mp.Address},
+        Topics: [][]common.Hash{{crypto.Keccak256Hash([]byte("ScrubVoted(address,uint256)"))}},
+        FromBlock: t.it.startBlock,
+    })
+    if err != nil {
+        return fmt.Errorf("Could not get scrub votes for minipool %s: %w", mp.Address.Hex(), err)
+    }
+    voters := make(map[common.Address]bool, len(logs))
+    for _, voteLog := range logs {
+        if len(voteLog.Topics) > 1 {
+            voters[common.BytesToAddress(voteLog.Topics[1].Bytes())] = true
+        }
+    }
+
+    // Compare with member votes
+    members, err := getDryRunMemberSubmissions(t.rp, func(memberAddress common.Address) (bool, bool, error) {
+        return voters[memberAddress], voters[memberAddress], nil
+    })
+    if err != nil {
+        return err
+    }
+
+    // Get the minipool's current status
+    status, err := mp.GetStatus(nil)
+    if err != nil {
+        return err
+    }
+
+    // Record
+    if err := t.dryRun.record(t.log, dryRunRecord{
+        Task: "submitScrubMinipools",
+        Minipool: &mp.Address,
+        Values: map[string]string{
+            "status": types.Dissolved.String(),
+        },
+        OnChain: map[string]string{
+            "status": status.String(),
+        },
+        ConsensusReached: (status == types.Dissolved),
+        ConsensusMatched: (status == types.Dissolved),
+        Members: members,
+    }); err != nil {
+        return err
+    }
+    t.dryRunMinipools[mp.Address] = true
 
     // Return
     return nil
@@ -647,16 +776,75 @@ func (t *submitScrubMinipools) submitVoteScrubMinipool(mp *minipool.Minipool) er
 }
 
 
+// Create the evidence for scrubbing a minipool at a check step
+func (t *submitScrubMinipools) newScrubEvidence(mp *minipool.Minipool, details *minipoolDetails, step string, reason string) scrubs.Evidence {
+    evidence := scrubs.Evidence{
+        Minipool: mp.Address,
+        Pubkey: details.pubkey,
+        Step: step,
+        Reason: reason,
+        DetectedTime: time.Now(),
+        ExpectedWithdrawalCredentials: details.expectedWithdrawalCredentials,
+        InvalidDeposits: details.invalidDeposits,
+        DryRun: (t.dryRun != nil),
+    }
+    nodeAddress, err := mp.GetNodeAddress(nil)
+    if err != nil {
+        t.log.WithMinipool(mp.Address).Warnf("Error getting node address for minipool %s: %s", mp.Address.Hex(), err.Error())
+    } else {
+        evidence.NodeAddress = nodeAddress
+    }
+    return evidence
+}
+
+
+// Save a minipool's scrub evidence, keeping the original detection time and vote of a minipool which was already reported,
+// and notify the operator the first time the minipool is reported by this process
+func (t *submitScrubMinipools) saveScrubEvidence(evidence *scrubs.Evidence) {
+
+    // Keep the original detection time & vote
+    existing, exists, err := t.evidence.Get(evidence.Minipool)
+    if err != nil {
+        t.log.Warn(err.Error())
+    }
+    if exists && existing.Step == evidence.Step && existing.DryRun == evidence.DryRun {
+        evidence.DetectedTime = existing.DetectedTime
+        if evidence.VoteTxHash == nil {
+            evidence.VoteTxHash = existing.VoteTxHash
+        }
+    }
+
+    // Save evidence
+    if err := t.evidence.Save(*evidence); err != nil {
+        t.log.Warn(err.Error())
+    }
+
+    // Notify
+    if t.notified[evidence.Minipool] {
+        return
+    }
+    action := "Voting to scrub"
+    if evidence.DryRun {
+        action = "DRY RUN: Would vote to scrub"
+    }
+    if err := t.notifier.Notify("%s minipool %s.\n%s\nRun `rocketpool odao scrub-reports --minipool %s` for the full report.", action, evidence.Minipool.Hex(), evidence.Summary(), evidence.Minipool.Hex()); err != nil {
+        t.log.Warn(err.Error())
+    }
+    t.notified[evidence.Minipool] = true
+
+}
+
+
 // Prints the final tally of minipool counts
 func (t *submitScrubMinipools) printFinalTally() {
 
-    t.log.Println("Scrub check complete.")
-    t.log.Printlnf("\tTotal prelaunch minipools: %d", t.it.totalMinipools)
-    t.log.Printlnf("\tBeacon Chain scrubs: %d/%d", t.it.badOnBeaconCount, (t.it.badOnBeaconCount + t.it.goodOnBeaconCount))
-    t.log.Printlnf("\tPrestake scrubs: %d/%d", t.it.badPrestakeCount, (t.it.badPrestakeCount + t.it.goodPrestakeCount))
-    t.log.Printlnf("\tDeposit Contract scrubs: %d/%d", t.it.badOnDepositContract, (t.it.badOnDepositContract + t.it.goodOnDepositContract))
-    t.log.Printlnf("\tPools without deposits: %d", t.it.unknownMinipools)
-    t.log.Printlnf("\tRemaining uncovered minipools: %d", len(t.it.minipools))
+    t.log.Info("Scrub check complete.")
+    t.log.Infof("\tTotal prelaunch minipools: %d", t.it.totalMinipools)
+    t.log.Infof("\tBeacon Chain scrubs: %d/%d", t.it.badOnBeaconCount, (t.it.badOnBeaconCount + t.it.goodOnBeaconCount))
+    t.log.Infof("\tPrestake scrubs: %d/%d", t.it.badPrestakeCount, (t.it.badPrestakeCount + t.it.goodPrestakeCount))
+    t.log.Infof("\tDeposit Contract scrubs: %d/%d", t.it.badOnDepositContract, (t.it.badOnDepositContract + t.it.goodOnDepositContract))
+    t.log.Infof("\tPools without deposits: %d", t.it.unknownMinipools)
+    t.log.Infof("\tRemaining uncovered minipools: %d", len(t.it.minipools))
 
     // Update the metrics collector
     if t.coll != nil {
@@ -1,7 +1,6 @@
 package watchtower
 
 import (
-	"context"
 	"fmt"
 	"math/big"
 
@@ -11,6 +10,7 @@ import (
 	"github.com/rocket-pool/rocketpool-go/minipool"
 	"github.com/rocket-pool/rocketpool-go/rocketpool"
 	"github.com/rocket-pool/rocketpool-go/settings/protocol"
+	tnsettings "github.com/rocket-pool/rocketpool-go/settings/trustednode"
 	"github.com/rocket-pool/rocketpool-go/types"
 	"github.com/rocket-pool/rocketpool-go/utils/eth"
 	"github.com/urfave/cli"
@@ -19,6 +19,7 @@ import (
 	"github.com/rocket-pool/smartnode/shared/services"
 	"github.com/rocket-pool/smartnode/shared/services/beacon"
 	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/multicall"
 	rpgas "github.com/rocket-pool/smartnode/shared/services/gas"
 	"github.com/rocket-pool/smartnode/shared/services/wallet"
 	"github.com/rocket-pool/smartnode/shared/utils/api"
@@ -27,21 +28,20 @@ import (
 	"github.com/rocket-pool/smartnode/shared/utils/rp"
 )
 
-// Settings
-const MinipoolWithdrawableDetailsBatchSize = 20
-
-
 // Submit withdrawable minipools task
 type submitWithdrawableMinipools struct {
     c *cli.Context
-    log log.ColorLogger
+    log log.Logger
     cfg config.RocketPoolConfig
     w *wallet.Wallet
     rp *rocketpool.RocketPool
     bc beacon.Client
+    mc *multicall.MultiCaller
     maxFee *big.Int
     maxPriorityFee *big.Int
     gasLimit uint64
+    dryRun *dryRunRecorder
+    dryRunMinipools map[common.Address]bool
 }
 
 
@@ -55,7 +55,7 @@ type minipoolWithdrawableDetails struct {
 
 
 // Create submit withdrawable minipools task
-func newSubmitWithdrawableMinipools(c *cli.Context, logger log.ColorLogger) (*submitWithdrawableMinipools, error) {
+func newSubmitWithdrawableMinipools(c *cli.Context, logger log.Logger, dryRun *dryRunRecorder) (*submitWithdrawableMinipools, error) {
 
     // Get services
     cfg, err := services.GetConfig(c)
@@ -66,6 +66,8 @@ func newSubmitWithdrawableMinipools(c *cli.Context, logger log.ColorLogger) (*su
     if err != nil { return nil, err }
     bc, err := services.GetBeaconClient(c)
     if err != nil { return nil, err }
+    mc, err := services.GetMultiCaller(c)
+    if err != nil { return nil, err }
 
     // Get the user-requested max fee
     maxFee, err := cfg.GetMaxFee()
@@ -79,7 +81,7 @@ func newSubmitWithdrawableMinipools(c *cli.Context, logger log.ColorLogger) (*su
         return nil, fmt.Errorf("Error getting max priority fee in configuration: %w", err)
     }
     if maxPriorityFee == nil || maxPriorityFee.Uint64() == 0 {
-        logger.Println("WARNING: priority fee was missing or 0, setting a default of 2.");
+        logger.Warn("Priority fee was missing or 0, setting a default of 2.");
         maxPriorityFee = big.NewInt(2)
     }
 
@@ -97,9 +99,12 @@ func newSubmitWithdrawableMinipools(c *cli.Context, logger log.ColorLogger) (*su
         w: w,
         rp: rp,
         bc: bc,
+        mc: mc,
         maxFee: maxFee,
         maxPriorityFee: maxPriorityFee,
         gasLimit: gasLimit,
+        dryRun: dryRun,
+        dryRunMinipools: make(map[common.Address]bool),
     }, nil
 
 }
@@ -144,13 +149,13 @@ func (t *submitWithdrawableMinipools) run() error {
         return err
     }
 
-    // Check node trusted status & settings
-    if !(nodeTrusted && submitWithdrawableEnabled) {
+    // Check node trusted status & settings; membership is not required in dry-run mode
+    if !((nodeTrusted || t.dryRun != nil) && submitWithdrawableEnabled) {
         return nil
     }
 
     // Log
-    t.log.Println("Checking for withdrawable minipools...")
+    t.log.Info("Checking for withdrawable minipools...")
 
     // Get minipool withdrawable details
     minipools, err := t.getNetworkMinipoolWithdrawableDetails(nodeAccount.Address)
@@ -162,12 +167,25 @@ func (t *submitWithdrawableMinipools) run() error {
     }
 
     // Log
-    t.log.Printlnf("%d minipool(s) are withdrawable...", len(minipools))
+    t.log.Infof("%d minipool(s) are withdrawable...", len(minipools))
+
+    // In dry-run mode, compare each withdrawable minipool with the members' submissions once
+    if t.dryRun != nil {
+        for _, details := range minipools {
+            if t.dryRunMinipools[details.Address] {
+                continue
+            }
+            if err := t.runDryRun(details); err != nil {
+                t.log.WithMinipool(details.Address).Error(fmt.Errorf("Could not compare minipool %s withdrawable status: %w", details.Address.Hex(), err))
+            }
+        }
+        return nil
+    }
 
     // Submit minipools withdrawable status
     for _, details := range minipools {
         if err := t.submitWithdrawableMinipool(details); err != nil {
-            t.log.Println(fmt.Errorf("Could not submit minipool %s withdrawable status: %w", details.Address.Hex(), err))
+            t.log.WithMinipool(details.Address).Error(fmt.Errorf("Could not submit minipool %s withdrawable status: %w", details.Address.Hex(), err))
         }
     }
 
@@ -189,7 +207,7 @@ func (t *submitWithdrawableMinipools) getNetworkMinipoolWithdrawableDetails(node
     // Get minipool addresses
     wg1.Go(func() error {
         var err error
-        addresses, err = minipool.GetMinipoolAddresses(t.rp, nil)
+        addresses, err = rp.GetMinipoolAddresses(t.rp, t.mc, nil)
         return err
     })
 
@@ -213,44 +231,83 @@ func (t *submitWithdrawableMinipools) getNetworkMinipoolWithdrawableDetails(node
     }
 
     // Get minipool validator statuses
-    validators, err := rp.GetMinipoolValidators(t.rp, t.bc, addresses, nil, nil)
+    validators, err := rp.GetMinipoolValidators(t.rp, t.mc, t.bc, addresses, nil, nil)
+    if err != nil {
+        return []minipoolWithdrawableDetails{}, err
+    }
+
+    // Get minipool contracts
+    minipools, err := rp.GetMinipools(t.rp, addresses)
     if err != nil {
         return []minipoolWithdrawableDetails{}, err
     }
 
-    // Load details in batches
-    minipools := make([]minipoolWithdrawableDetails, len(addresses))
-    for bsi := 0; bsi < len(addresses); bsi += MinipoolWithdrawableDetailsBatchSize {
-
-        // Get batch start & end index
-        msi := bsi
-        mei := bsi + MinipoolWithdrawableDetailsBatchSize
-        if mei > len(addresses) { mei = len(addresses) }
-
-        // Log
-        //t.log.Printlnf("Checking minipools %d - %d of %d for withdrawable status...", msi + 1, mei, len(addresses))
-
-        // Load details
-        var wg errgroup.Group
-        for mi := msi; mi < mei; mi++ {
-            mi := mi
-            wg.Go(func() error {
-                address := addresses[mi]
-                validator := validators[address]
-                mpDetails, err := t.getMinipoolWithdrawableDetails(nodeAddress, address, validator, eth2Config, beaconHead)
-                if err == nil { minipools[mi] = mpDetails }
-                return err
-            })
+    // Load minipool statuses & deposit details
+    statuses := make([]uint8, len(minipools))
+    nodeDepositBalances := make([]*big.Int, len(minipools))
+    userDepositBalances := make([]*big.Int, len(minipools))
+    userDepositTimes := make([]*big.Int, len(minipools))
+    batch := t.mc.NewBatch()
+    for mi, mp := range minipools {
+        if err := batch.AddCall(mp.Contract, &statuses[mi], "getStatus"); err != nil {
+            return []minipoolWithdrawableDetails{}, err
+        }
+        if err := batch.AddCall(mp.Contract, &nodeDepositBalances[mi], "getNodeDepositBalance"); err != nil {
+            return []minipoolWithdrawableDetails{}, err
+        }
+        if err := batch.AddCall(mp.Contract, &userDepositBalances[mi], "getUserDepositBalance"); err != nil {
+            return []minipoolWithdrawableDetails{}, err
+        }
+        if err := batch.AddCall(mp.Contract, &userDepositTimes[mi], "getUserDepositAssignedTime"); err != nil {
+            return []minipoolWithdrawableDetails{}, err
+        }
+    }
+    if err := batch.Execute(nil); err != nil {
+        return []minipoolWithdrawableDetails{}, err
+    }
+
+    // Get the staking minipools with withdrawable validators, and their submission status & balances
+    candidates := []int{}
+    submitted := make([]bool, len(minipools))
+    ethBalances := make([]*big.Int, len(minipools))
+    refundBalances := make([]*big.Int, len(minipools))
+    candidateBatch := t.mc.NewBatch()
+    for mi, mp := range minipools {
+        validator := validators[mp.Address]
+
+        // Check minipool & validator status
+        if types.MinipoolStatus(statuses[mi]) != types.Staking {
+            continue
+        }
+        if !validator.Exists || validator.WithdrawableEpoch >= beaconHead.FinalizedEpoch {
+            continue
+        }
+        candidates = append(candidates, mi)
+
+        // Check for existing node submission, and get the current ETH & refund balances
+        submittedKey := crypto.Keccak256Hash([]byte("minipool.withdrawable.submitted.node"), nodeAddress.Bytes(), mp.Address.Bytes())
+        if err := candidateBatch.AddCall(t.rp.RocketStorageContract, &submitted[mi], "getBool", submittedKey); err != nil {
+            return []minipoolWithdrawableDetails{}, err
+        }
+        if err := candidateBatch.AddBalanceCall(mp.Address, &ethBalances[mi]); err != nil {
+            return []minipoolWithdrawableDetails{}, err
         }
-        if err := wg.Wait(); err != nil {
+        if err := candidateBatch.AddCall(mp.Contract, &refundBalances[mi], "getNodeRefundBalance"); err != nil {
             return []minipoolWithdrawableDetails{}, err
         }
 
     }
+    if err := candidateBatch.Execute(nil); err != nil {
+        return []minipoolWithdrawableDetails{}, err
+    }
 
-    // Filter by withdrawable status
+    // Get withdrawable minipool details
     withdrawableMinipools := []minipoolWithdrawableDetails{}
-    for _, details := range minipools {
+    for _, mi := range candidates {
+        if submitted[mi] {
+            continue
+        }
+        details := getMinipoolWithdrawableDetails(minipools[mi].Address, validators[minipools[mi].Address], eth2Config, beaconHead, nodeDepositBalances[mi], userDepositBalances[mi], userDepositTimes[mi].Uint64(), ethBalances[mi], refundBalances[mi])
         if details.Withdrawable {
             withdrawableMinipools = append(withdrawableMinipools, details)
         }
@@ -263,59 +320,7 @@ func (t *submitWithdrawableMinipools) getNetworkMinipoolWithdrawableDetails(node
 
 
 // Get minipool withdrawable details
-func (t *submitWithdrawableMinipools) getMinipoolWithdrawableDetails(nodeAddress common.Address, minipoolAddress common.Address, validator beacon.ValidatorStatus, eth2Config beacon.Eth2Config, beaconHead beacon.BeaconHead) (minipoolWithdrawableDetails, error) {
-
-    // Create minipool
-    mp, err := minipool.NewMinipool(t.rp, minipoolAddress)
-    if err != nil {
-        return minipoolWithdrawableDetails{}, err
-    }
-
-    // Data
-    var wg errgroup.Group
-    var status types.MinipoolStatus
-    var nodeDepositBalance *big.Int
-    var userDepositBalance *big.Int
-    var userDepositTime uint64
-
-    // Load data
-    wg.Go(func() error {
-        var err error
-        status, err = mp.GetStatus(nil)
-        return err
-    })
-    wg.Go(func() error {
-        var err error
-        nodeDepositBalance, err = mp.GetNodeDepositBalance(nil)
-        return err
-    })
-    wg.Go(func() error {
-        var err error
-        userDepositBalance, err = mp.GetUserDepositBalance(nil)
-        return err
-    })
-    wg.Go(func() error {
-        userDepositAssignedTime, err := mp.GetUserDepositAssignedTime(nil)
-        if err == nil {
-            userDepositTime = uint64(userDepositAssignedTime.Unix())
-        }
-        return err
-    })
-
-    // Wait for data
-    if err := wg.Wait(); err != nil {
-        return minipoolWithdrawableDetails{}, err
-    }
-
-    // Check minipool status
-    if status != types.Staking {
-        return minipoolWithdrawableDetails{}, nil
-    }
-
-    // Check validator status
-    if !validator.Exists || validator.WithdrawableEpoch >= beaconHead.FinalizedEpoch {
-        return minipoolWithdrawableDetails{}, nil
-    }
+func getMinipoolWithdrawableDetails(minipoolAddress common.Address, validator beacon.ValidatorStatus, eth2Config beacon.Eth2Config, beaconHead beacon.BeaconHead, nodeDepositBalance, userDepositBalance *big.Int, userDepositTime uint64, ethBalance, refundBalance *big.Int) minipoolWithdrawableDetails {
 
     // Get start epoch for node balance calculation
     startEpoch := eth2.EpochAt(eth2Config, userDepositTime)
@@ -334,32 +339,11 @@ func (t *submitWithdrawableMinipools) getMinipoolWithdrawableDetails(nodeAddress
     startBalance := eth.GweiToWei(activationBalance + (float64(validator.Balance) - activationBalance) * float64(startEpoch - validator.ActivationEpoch) / float64(beaconHead.FinalizedEpoch - validator.ActivationEpoch))
     endBalance := eth.GweiToWei(float64(validator.Balance))
 
-    // Check for existing node submission
-    nodeSubmittedMinipool, err := t.rp.RocketStorage.GetBool(nil, crypto.Keccak256Hash([]byte("minipool.withdrawable.submitted.node"), nodeAddress.Bytes(), minipoolAddress.Bytes()))
-    if err != nil {
-        return minipoolWithdrawableDetails{}, err
-    }
-    if nodeSubmittedMinipool {
-        return minipoolWithdrawableDetails{}, nil
-    }
-
-    // Get the current ETH balance
-    ethBalance, err := t.rp.Client.BalanceAt(context.Background(), minipoolAddress, nil)
-    if err != nil {
-        return minipoolWithdrawableDetails{}, err
-    }
-
-    // Get the refund balance
-    refundBalance, err := mp.GetNodeRefundBalance(nil)
-    if err != nil {
-        return minipoolWithdrawableDetails{}, err
-    }
-
     // Check if there's enough ETH to assume a successful withdrawal)
     remainingBalance := big.NewInt(0)
     remainingBalance.Sub(ethBalance, refundBalance)
     if remainingBalance.Cmp(endBalance) == -1 {
-        return minipoolWithdrawableDetails{}, nil
+        return minipoolWithdrawableDetails{}
     }
 
     // Return
@@ -368,7 +352,83 @@ func (t *submitWithdrawableMinipools) getMinipoolWithdrawableDetails(nodeAddress
         StartBalance: startBalance,
         EndBalance: endBalance,
         Withdrawable: true,
-    }, nil
+    }
+
+}
+
+
+// Compare a minipool's withdrawable status with the existing members' submissions
+func (t *submitWithdrawableMinipools) runDryRun(details minipoolWithdrawableDetails) error {
+
+    // Log
+    t.log.WithMinipool(details.Address).Infof("DRY RUN: Would submit minipool %s withdrawable status.", details.Address.Hex())
+
+    // Get the submission keys for our values
+    startBalanceBuf := make([]byte, 32)
+    details.StartBalance.FillBytes(startBalanceBuf)
+    endBalanceBuf := make([]byte, 32)
+    details.EndBalance.FillBytes(endBalanceBuf)
+
+    // Compare with member submissions
+    members, err := getDryRunMemberSubmissions(t.rp, func(memberAddress common.Address) (bool, bool, error) {
+        submitted, err := t.rp.RocketStorage.GetBool(nil, crypto.Keccak256Hash([]byte("minipool.withdrawable.submitted.node"), memberAddress.Bytes(), details.Address.Bytes()))
+        if err != nil || !submitted {
+            return false, false, err
+        }
+        matched, err := t.rp.RocketStorage.GetBool(nil, crypto.Keccak256Hash([]byte("minipool.withdrawable.submitted.node"), memberAddress.Bytes(), details.Address.Bytes(), startBalanceBuf, endBalanceBuf))
+        return submitted, matched, err
+    })
+    if err != nil {
+        return err
+    }
+    record := dryRunRecord{
+        Task: "submitWithdrawableMinipools",
+        Minipool: &details.Address,
+        Values: map[string]string{
+            "startBalance": details.StartBalance.String(),
+            "endBalance": details.EndBalance.String(),
+        },
+        Members: members,
+    }
+
+    // Compare with the consensus if the minipool has been marked as withdrawable
+    mp, err := minipool.NewMinipool(t.rp, details.Address)
+    if err != nil {
+        return err
+    }
+    status, err := mp.GetStatus(nil)
+    if err != nil {
+        return err
+    }
+    if status == types.Withdrawable {
+        memberCount, err := trustednode.GetMemberCount(t.rp, nil)
+        if err != nil {
+            return err
+        }
+        quorum, err := tnsettings.GetQuorum(t.rp, nil)
+        if err != nil {
+            return err
+        }
+        submissionCount, err := t.rp.RocketStorage.GetUint(nil, crypto.Keccak256Hash([]byte("minipool.withdrawable.submitted.count"), details.Address.Bytes(), startBalanceBuf, endBalanceBuf))
+        if err != nil {
+            return err
+        }
+        record.ConsensusReached = true
+        record.ConsensusMatched = (memberCount > 0 && float64(submissionCount.Uint64()) / float64(memberCount) >= quorum)
+        record.OnChain = map[string]string{
+            "status": status.String(),
+            "matchingSubmissions": submissionCount.String(),
+        }
+    }
+
+    // Record
+    if err := t.dryRun.record(t.log, record); err != nil {
+        return err
+    }
+    t.dryRunMinipools[details.Address] = true
+
+    // Return
+    return nil
 
 }
 
@@ -377,7 +437,7 @@ func (t *submitWithdrawableMinipools) getMinipoolWithdrawableDetails(nodeAddress
 func (t *submitWithdrawableMinipools) submitWithdrawableMinipool(details minipoolWithdrawableDetails) error {
 
     // Log
-    t.log.Printlnf("Submitting minipool %s withdrawable status...", details.Address.Hex())
+    t.log.WithMinipool(details.Address).Infof("Submitting minipool %s withdrawable status...", details.Address.Hex())
 
     // Get transactor
     opts, err := t.w.GetNodeAccountTransactor()
@@ -422,13 +482,13 @@ func (t *submitWithdrawableMinipools) submitWithdrawableMinipool(details minipoo
     }
 
     // Print TX info and wait for it to be mined
-    err = api.PrintAndWaitForTransaction(t.cfg, hash, t.rp.Client, t.log)
+    err = api.PrintAndWaitForTransaction(t.cfg, hash, t.rp.Client, t.w.GetTxLog(), t.log)
     if err != nil {
         return err
     }
 
     // Log
-    t.log.Printlnf("Successfully submitted minipool %s withdrawable status.", details.Address.Hex())
+    t.log.WithMinipool(details.Address).Infof("Successfully submitted minipool %s withdrawable status.", details.Address.Hex())
 
     // Return
     return nil
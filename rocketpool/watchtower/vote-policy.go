@@ -0,0 +1,170 @@
+package watchtower
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/rocketpool-go/utils/eth"
+	"gopkg.in/yaml.v2"
+
+	rputils "github.com/rocket-pool/smartnode/shared/utils/rp"
+)
+
+// Policy votes
+const (
+    PolicyVoteSupport = "support"
+    PolicyVoteOppose = "oppose"
+    PolicyVoteNone = "none"
+)
+
+// Policy rule type matching any proposal
+const PolicyTypeAny = "any"
+
+
+// An oracle DAO proposal voting policy, loaded from a YAML file
+// Proposals are checked against each rule in order, and voted on according to the first rule they match;
+// proposals which match no rule, or a rule with the vote 'none', are left for the operator to vote on manually.
+// For example:
+//
+//   rules:
+//     - name: trusted invites
+//       type: invite
+//       members: ["0x1234..."]
+//       vote: support
+//     - name: large fines
+//       type: kick
+//       minFine: 1000
+//       vote: oppose
+//     - name: settings
+//       type: setting
+//       vote: none
+type votePolicy struct {
+    Rules []votePolicyRule  `yaml:"rules"`
+}
+type votePolicyRule struct {
+    Name string             `yaml:"name"`
+    Type string             `yaml:"type"`
+    Members []string        `yaml:"members"`
+    Settings []string       `yaml:"settings"`
+    MinFine *float64        `yaml:"minFine"`
+    MaxFine *float64        `yaml:"maxFine"`
+    Vote string             `yaml:"vote"`
+}
+
+
+// Load a voting policy; returns nil if the policy file does not exist
+func loadVotePolicy(path string) (*votePolicy, error) {
+
+    // Read policy file
+    bytes, err := ioutil.ReadFile(path)
+    if os.IsNotExist(err) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, fmt.Errorf("Could not read voting policy file at %s: %w", path, err)
+    }
+
+    // Parse policy
+    var policy votePolicy
+    if err := yaml.Unmarshal(bytes, &policy); err != nil {
+        return nil, fmt.Errorf("Could not parse voting policy file at %s: %w", path, err)
+    }
+
+    // Validate rules
+    for ri, rule := range policy.Rules {
+        switch rule.Type {
+            case "", PolicyTypeAny, rputils.TNDAOProposalTypeInvite, rputils.TNDAOProposalTypeLeave, rputils.TNDAOProposalTypeReplace, rputils.TNDAOProposalTypeKick, rputils.TNDAOProposalTypeSetting, rputils.TNDAOProposalTypeUpgrade:
+            default: return nil, fmt.Errorf("Invalid voting policy rule %d: unknown proposal type '%s'", ri + 1, rule.Type)
+        }
+        switch rule.Vote {
+            case PolicyVoteSupport, PolicyVoteOppose, PolicyVoteNone:
+            default: return nil, fmt.Errorf("Invalid voting policy rule %d: vote must be '%s', '%s' or '%s'", ri + 1, PolicyVoteSupport, PolicyVoteOppose, PolicyVoteNone)
+        }
+        for _, member := range rule.Members {
+            if !common.IsHexAddress(member) {
+                return nil, fmt.Errorf("Invalid voting policy rule %d: invalid member address '%s'", ri + 1, member)
+            }
+        }
+        for _, setting := range rule.Settings {
+            if _, err := rputils.GetTNDAOSetting(setting); err != nil {
+                return nil, fmt.Errorf("Invalid voting policy rule %d: %w", ri + 1, err)
+            }
+        }
+    }
+
+    // Return
+    return &policy, nil
+
+}
+
+
+// Get the vote for a proposal action, and the reason for it
+func (p *votePolicy) evaluate(action rputils.TNDAOProposalAction) (string, string) {
+    for ri, rule := range p.Rules {
+        if !rule.matches(action) {
+            continue
+        }
+        ruleName := fmt.Sprintf("rule %d", ri + 1)
+        if rule.Name != "" {
+            ruleName = fmt.Sprintf("%s ('%s')", ruleName, rule.Name)
+        }
+        return rule.Vote, fmt.Sprintf("matched voting policy %s", ruleName)
+    }
+    return PolicyVoteNone, "no voting policy rule matched"
+}
+
+
+// Check whether a rule matches a proposal action
+func (r *votePolicyRule) matches(action rputils.TNDAOProposalAction) bool {
+
+    // Check type
+    if r.Type != "" && r.Type != PolicyTypeAny && r.Type != action.Type {
+        return false
+    }
+
+    // Check members
+    if len(r.Members) > 0 {
+        matched := false
+        for _, member := range r.Members {
+            address := common.HexToAddress(member)
+            if (action.Member != nil && *action.Member == address) || (action.NewMember != nil && *action.NewMember == address) {
+                matched = true
+                break
+            }
+        }
+        if !matched {
+            return false
+        }
+    }
+
+    // Check settings
+    if len(r.Settings) > 0 {
+        matched := false
+        for _, name := range r.Settings {
+            if setting, err := rputils.GetTNDAOSetting(name); err == nil && setting.Name == action.SettingName {
+                matched = true
+                break
+            }
+        }
+        if !matched {
+            return false
+        }
+    }
+
+    // Check fine
+    if r.MinFine != nil || r.MaxFine != nil {
+        if action.RplFine == nil {
+            return false
+        }
+        fine := eth.WeiToEth(action.RplFine)
+        if (r.MinFine != nil && fine < *r.MinFine) || (r.MaxFine != nil && fine > *r.MaxFine) {
+            return false
+        }
+    }
+
+    // Return
+    return true
+
+}
@@ -0,0 +1,176 @@
+package watchtower
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/rocketpool-go/settings/trustednode"
+	"github.com/rocket-pool/rocketpool-go/utils/eth"
+
+	rputils "github.com/rocket-pool/smartnode/shared/utils/rp"
+)
+
+// Test member addresses
+const (
+    testMemberA = "0x1111111111111111111111111111111111111111"
+    testMemberB = "0x2222222222222222222222222222222222222222"
+)
+
+
+func TestVotePolicyRuleMatches(t *testing.T) {
+    memberA := common.HexToAddress(testMemberA)
+    memberB := common.HexToAddress(testMemberB)
+    minFine, maxFine := 100.0, 1000.0
+    invite := rputils.TNDAOProposalAction{Type: rputils.TNDAOProposalTypeInvite, Member: &memberA}
+    replace := rputils.TNDAOProposalAction{Type: rputils.TNDAOProposalTypeReplace, Member: &memberB, NewMember: &memberA}
+    quorum := rputils.TNDAOProposalAction{Type: rputils.TNDAOProposalTypeSetting, SettingName: trustednode.QuorumSettingPath, SettingValue: eth.EthToWei(0.51)}
+    kick := func(fine float64) rputils.TNDAOProposalAction {
+        return rputils.TNDAOProposalAction{Type: rputils.TNDAOProposalTypeKick, Member: &memberB, RplFine: eth.EthToWei(fine)}
+    }
+    tests := []struct {
+        name string
+        rule votePolicyRule
+        action rputils.TNDAOProposalAction
+        expected bool
+    }{
+        {"empty rule", votePolicyRule{}, invite, true},
+        {"any type", votePolicyRule{Type: PolicyTypeAny}, kick(1), true},
+        {"matching type", votePolicyRule{Type: rputils.TNDAOProposalTypeInvite}, invite, true},
+        {"other type", votePolicyRule{Type: rputils.TNDAOProposalTypeLeave}, invite, false},
+        {"matching member", votePolicyRule{Members: []string{testMemberB, strings.ToUpper(testMemberA[2:])}}, invite, true},
+        {"other member", votePolicyRule{Members: []string{testMemberB}}, invite, false},
+        {"matching new member", votePolicyRule{Members: []string{testMemberA}}, replace, true},
+        {"member on a setting proposal", votePolicyRule{Members: []string{testMemberA}}, quorum, false},
+        {"matching setting path", votePolicyRule{Settings: []string{trustednode.QuorumSettingPath}}, quorum, true},
+        {"matching setting alias", votePolicyRule{Settings: []string{"members-rplbond", "q"}}, quorum, true},
+        {"other setting", votePolicyRule{Settings: []string{"members-rplbond"}}, quorum, false},
+        {"setting on an invite", votePolicyRule{Settings: []string{"members-quorum"}}, invite, false},
+        {"fine within bounds", votePolicyRule{MinFine: &minFine, MaxFine: &maxFine}, kick(500), true},
+        {"fine at min", votePolicyRule{MinFine: &minFine}, kick(100), true},
+        {"fine at max", votePolicyRule{MaxFine: &maxFine}, kick(1000), true},
+        {"fine below min", votePolicyRule{MinFine: &minFine}, kick(99.5), false},
+        {"fine above max", votePolicyRule{MaxFine: &maxFine}, kick(1000.5), false},
+        {"fine bounds without a fine", votePolicyRule{MinFine: &minFine}, invite, false},
+        {"type and fine", votePolicyRule{Type: rputils.TNDAOProposalTypeKick, Members: []string{testMemberB}, MinFine: &minFine}, kick(100), true},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            if matches := test.rule.matches(test.action); matches != test.expected {
+                t.Errorf("match is %t, expected %t", matches, test.expected)
+            }
+        })
+    }
+}
+
+
+func TestVotePolicyEvaluate(t *testing.T) {
+    memberA := common.HexToAddress(testMemberA)
+    maxFine := 1000.0
+    policy := &votePolicy{Rules: []votePolicyRule{
+        {Name: "trusted invites", Type: rputils.TNDAOProposalTypeInvite, Members: []string{testMemberA}, Vote: PolicyVoteSupport},
+        {Type: rputils.TNDAOProposalTypeKick, MaxFine: &maxFine, Vote: PolicyVoteOppose},
+        {Name: "settings", Type: rputils.TNDAOProposalTypeSetting, Vote: PolicyVoteNone},
+        {Name: "other kicks", Type: rputils.TNDAOProposalTypeKick, Vote: PolicyVoteSupport},
+    }}
+    tests := []struct {
+        name string
+        action rputils.TNDAOProposalAction
+        vote string
+        reason string
+    }{
+        {
+            name: "named rule",
+            action: rputils.TNDAOProposalAction{Type: rputils.TNDAOProposalTypeInvite, Member: &memberA},
+            vote: PolicyVoteSupport,
+            reason: "matched voting policy rule 1 ('trusted invites')",
+        },
+        {
+            name: "unnamed rule",
+            action: rputils.TNDAOProposalAction{Type: rputils.TNDAOProposalTypeKick, Member: &memberA, RplFine: eth.EthToWei(10)},
+            vote: PolicyVoteOppose,
+            reason: "matched voting policy rule 2",
+        },
+        {
+            name: "first matching rule wins",
+            action: rputils.TNDAOProposalAction{Type: rputils.TNDAOProposalTypeKick, Member: &memberA, RplFine: eth.EthToWei(5000)},
+            vote: PolicyVoteSupport,
+            reason: "matched voting policy rule 4 ('other kicks')",
+        },
+        {
+            name: "rule without a vote",
+            action: rputils.TNDAOProposalAction{Type: rputils.TNDAOProposalTypeSetting, SettingName: trustednode.QuorumSettingPath, SettingValue: big.NewInt(0)},
+            vote: PolicyVoteNone,
+            reason: "matched voting policy rule 3 ('settings')",
+        },
+        {
+            name: "no matching rule",
+            action: rputils.TNDAOProposalAction{Type: rputils.TNDAOProposalTypeLeave, Member: &memberA},
+            vote: PolicyVoteNone,
+            reason: "no voting policy rule matched",
+        },
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            vote, reason := policy.evaluate(test.action)
+            if vote != test.vote || reason != test.reason {
+                t.Errorf("vote is %s (%s), expected %s (%s)", vote, reason, test.vote, test.reason)
+            }
+        })
+    }
+}
+
+
+func TestLoadVotePolicy(t *testing.T) {
+    dir, err := ioutil.TempDir("", "vote-policy")
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer os.RemoveAll(dir)
+    path := filepath.Join(dir, "vote-policy.yml")
+
+    // Missing policies disable voting
+    policy, err := loadVotePolicy(path)
+    if err != nil || policy != nil {
+        t.Fatalf("missing policy loaded as %v, %v; expected none", policy, err)
+    }
+
+    // Policies are validated
+    tests := []struct {
+        name string
+        policy string
+        errorContains string
+    }{
+        {"valid", "rules:\n- type: kick\n  minFine: 100\n  vote: oppose\n- settings: [q]\n  vote: none\n", ""},
+        {"unknown type", "rules:\n- type: vote\n  vote: support\n", "rule 1: unknown proposal type 'vote'"},
+        {"missing vote", "rules:\n- type: any\n  vote: none\n- type: invite\n", "rule 2: vote must be"},
+        {"invalid member", "rules:\n- members: [\"0x1234\"]\n  vote: support\n", "invalid member address '0x1234'"},
+        {"unknown setting", "rules:\n- settings: [members-size]\n  vote: support\n", "Unknown oracle DAO setting 'members-size'"},
+        {"invalid yaml", "rules: [", "Could not parse voting policy file"},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            if err := ioutil.WriteFile(path, []byte(test.policy), 0600); err != nil {
+                t.Fatal(err)
+            }
+            policy, err := loadVotePolicy(path)
+            if test.errorContains == "" {
+                if err != nil {
+                    t.Fatal(err)
+                }
+                if len(policy.Rules) != 2 || *policy.Rules[0].MinFine != 100 {
+                    t.Errorf("policy rules are %+v", policy.Rules)
+                }
+                return
+            }
+            if err == nil || !strings.Contains(err.Error(), test.errorContains) {
+                t.Fatalf("expected an error containing '%s', got %v", test.errorContains, err)
+            }
+        })
+    }
+
+}
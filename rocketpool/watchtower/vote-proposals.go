@@ -0,0 +1,298 @@
+package watchtower
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/rocket-pool/rocketpool-go/dao"
+	"github.com/rocket-pool/rocketpool-go/dao/trustednode"
+	"github.com/rocket-pool/rocketpool-go/rocketpool"
+	rptypes "github.com/rocket-pool/rocketpool-go/types"
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services"
+	"github.com/rocket-pool/smartnode/shared/services/config"
+	rpgas "github.com/rocket-pool/smartnode/shared/services/gas"
+	"github.com/rocket-pool/smartnode/shared/services/multicall"
+	"github.com/rocket-pool/smartnode/shared/services/notify"
+	"github.com/rocket-pool/smartnode/shared/services/wallet"
+	"github.com/rocket-pool/smartnode/shared/utils/api"
+	"github.com/rocket-pool/smartnode/shared/utils/log"
+	rputils "github.com/rocket-pool/smartnode/shared/utils/rp"
+)
+
+// Vote on proposals task
+type voteProposals struct {
+    c *cli.Context
+    log log.Logger
+    cfg config.RocketPoolConfig
+    w *wallet.Wallet
+    rp *rocketpool.RocketPool
+    mc *multicall.MultiCaller
+    notifier *notify.Notifier
+    maxFee *big.Int
+    maxPriorityFee *big.Int
+    gasLimit uint64
+    reported map[uint64]bool
+}
+
+
+// Create vote on proposals task
+func newVoteProposals(c *cli.Context, logger log.Logger) (*voteProposals, error) {
+
+    // Get services
+    cfg, err := services.GetConfig(c)
+    if err != nil { return nil, err }
+    w, err := services.GetWallet(c)
+    if err != nil { return nil, err }
+    rp, err := services.GetRocketPool(c)
+    if err != nil { return nil, err }
+    mc, err := services.GetMultiCaller(c)
+    if err != nil { return nil, err }
+    notifier, err := services.GetNotifier(c)
+    if err != nil { return nil, err }
+
+    // Get the user-requested max fee
+    maxFee, err := cfg.GetMaxFee()
+    if err != nil {
+        return nil, fmt.Errorf("Error getting max fee in configuration: %w", err)
+    }
+
+    // Get the user-requested max fee
+    maxPriorityFee, err := cfg.GetMaxPriorityFee()
+    if err != nil {
+        return nil, fmt.Errorf("Error getting max priority fee in configuration: %w", err)
+    }
+    if maxPriorityFee == nil || maxPriorityFee.Uint64() == 0 {
+        logger.Warn("Priority fee was missing or 0, setting a default of 2.");
+        maxPriorityFee = big.NewInt(2)
+    }
+
+    // Get the user-requested gas limit
+    gasLimit, err := cfg.GetGasLimit()
+    if err != nil {
+        return nil, fmt.Errorf("Error getting gas limit in configuration: %w", err)
+    }
+
+    // Return task
+    return &voteProposals{
+        c: c,
+        log: logger,
+        cfg: cfg,
+        w: w,
+        rp: rp,
+        mc: mc,
+        notifier: notifier,
+        maxFee: maxFee,
+        maxPriorityFee: maxPriorityFee,
+        gasLimit: gasLimit,
+        reported: map[uint64]bool{},
+    }, nil
+
+}
+
+
+// Vote on open proposals according to the voting policy
+func (t *voteProposals) run() error {
+
+    // Wait for eth client to sync
+    if err := services.WaitEthClientSynced(t.c, true); err != nil {
+        return err
+    }
+
+    // Get node account
+    nodeAccount, err := t.w.GetNodeAccount()
+    if err != nil {
+        return err
+    }
+
+    // Check node trusted status
+    nodeTrusted, err := trustednode.GetMemberExists(t.rp, nodeAccount.Address, nil)
+    if err != nil {
+        return err
+    }
+    if !nodeTrusted {
+        return nil
+    }
+
+    // Load the voting policy; automatic voting is disabled without one
+    policy, err := loadVotePolicy(t.cfg.GetVotePolicyPath())
+    if err != nil {
+        return err
+    }
+    if policy == nil || len(policy.Rules) == 0 {
+        return nil
+    }
+
+    // Log
+    t.log.Info("Checking for oracle DAO proposals to vote on...")
+
+    // Get active proposal IDs
+    proposalIds, err := t.getActiveProposalIds()
+    if err != nil {
+        return err
+    }
+    if len(proposalIds) == 0 {
+        return nil
+    }
+
+    // Get the time the node joined the oracle DAO; members cannot vote on proposals created before they joined
+    joinedTime, err := trustednode.GetMemberJoinedTime(t.rp, nodeAccount.Address, nil)
+    if err != nil {
+        return err
+    }
+
+    // Evaluate proposals
+    for _, proposalId := range proposalIds {
+
+        // Get proposal details
+        proposal, err := dao.GetProposalDetailsWithMember(t.rp, proposalId, nodeAccount.Address, nil)
+        if err != nil {
+            return err
+        }
+        if proposal.MemberVoted || proposal.CreatedTime <= joinedTime {
+            continue
+        }
+
+        // Get the vote from the policy
+        var vote, reason string
+        action, err := rputils.DecodeTNDAOProposalPayload(t.rp, proposal.Payload)
+        if err != nil {
+            vote, reason = PolicyVoteNone, fmt.Sprintf("the proposal payload could not be decoded (%s)", err.Error())
+        } else {
+            vote, reason = policy.evaluate(action)
+        }
+
+        // Report proposals requiring a manual vote once
+        if vote == PolicyVoteNone {
+            if !t.reported[proposalId] {
+                t.reported[proposalId] = true
+                t.log.Infof("Proposal %d ('%s') requires a manual vote: %s.", proposalId, proposal.Message, reason)
+                t.notify("Oracle DAO proposal %d ('%s') requires a manual vote before %s: %s.", proposalId, proposal.Message, time.Unix(int64(proposal.EndTime), 0).Format(time.RFC822), reason)
+            }
+            continue
+        }
+
+        // Vote
+        support := (vote == PolicyVoteSupport)
+        t.log.Infof("Voting to %s proposal %d ('%s'): %s.", vote, proposalId, proposal.Message, reason)
+        voted, err := t.voteOnProposal(proposalId, support)
+        if err != nil {
+            t.log.Errorf("Could not vote on proposal %d: %s", proposalId, err.Error())
+            continue
+        }
+        if !voted {
+            continue
+        }
+        t.notify("Voted to %s oracle DAO proposal %d ('%s') on behalf of node %s: %s.", vote, proposalId, proposal.Message, nodeAccount.Address.Hex(), reason)
+
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Get the IDs of the proposals currently open for voting
+func (t *voteProposals) getActiveProposalIds() ([]uint64, error) {
+
+    // Get proposal IDs
+    proposalIds, err := dao.GetDAOProposalIDs(t.rp, "rocketDAONodeTrustedProposals", nil)
+    if err != nil {
+        return []uint64{}, err
+    }
+
+    // Get proposal states
+    rocketDAOProposal, err := t.rp.GetContract("rocketDAOProposal")
+    if err != nil {
+        return []uint64{}, err
+    }
+    states := make([]uint8, len(proposalIds))
+    batch := t.mc.NewBatch()
+    for pi, proposalId := range proposalIds {
+        if err := batch.AddCall(rocketDAOProposal, &states[pi], "getState", new(big.Int).SetUint64(proposalId)); err != nil {
+            return []uint64{}, err
+        }
+    }
+    if err := batch.Execute(nil); err != nil {
+        return []uint64{}, fmt.Errorf("Could not get proposal states: %w", err)
+    }
+
+    // Filter by state
+    activeProposalIds := []uint64{}
+    for pi, proposalId := range proposalIds {
+        if rptypes.ProposalState(states[pi]) == rptypes.Active {
+            activeProposalIds = append(activeProposalIds, proposalId)
+        }
+    }
+    return activeProposalIds, nil
+
+}
+
+
+// Vote on a proposal; returns false if the vote was not sent due to the gas price
+func (t *voteProposals) voteOnProposal(proposalId uint64, support bool) (bool, error) {
+
+    // Get transactor
+    opts, err := t.w.GetNodeAccountTransactor()
+    if err != nil {
+        return false, err
+    }
+
+    // Get the gas limit
+    gasInfo, err := trustednode.EstimateVoteOnProposalGas(t.rp, proposalId, support, opts)
+    if err != nil {
+        return false, fmt.Errorf("Could not estimate the gas required to vote on proposal %d: %w", proposalId, err)
+    }
+    var gas *big.Int
+    if t.gasLimit != 0 {
+        gas = new(big.Int).SetUint64(t.gasLimit)
+    } else {
+        gas = new(big.Int).SetUint64(gasInfo.SafeGasLimit)
+    }
+
+    // Get the max fee
+    maxFee := t.maxFee
+    if maxFee == nil || maxFee.Uint64() == 0 {
+        maxFee, err = rpgas.GetHeadlessMaxFeeWei()
+        if err != nil {
+            return false, err
+        }
+    }
+
+    // Print the gas info
+    if !api.PrintAndCheckGasInfo(gasInfo, false, 0, t.log, maxFee, t.gasLimit) {
+        return false, nil
+    }
+
+    opts.GasFeeCap = maxFee
+    opts.GasTipCap = t.maxPriorityFee
+    opts.GasLimit = gas.Uint64()
+
+    // Vote on proposal
+    hash, err := trustednode.VoteOnProposal(t.rp, proposalId, support, opts)
+    if err != nil {
+        return false, err
+    }
+
+    // Print TX info and wait for it to be mined
+    err = api.PrintAndWaitForTransaction(t.cfg, hash, t.rp.Client, t.w.GetTxLog(), t.log)
+    if err != nil {
+        return false, err
+    }
+
+    // Log & return
+    t.log.Infof("Successfully voted on proposal %d.", proposalId)
+    return true, nil
+
+}
+
+
+// Send an operator notification, logging any errors
+func (t *voteProposals) notify(format string, args ...interface{}) {
+    if err := t.notifier.Notify(format, args...); err != nil {
+        t.log.Warn(err.Error())
+    }
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/rocket-pool/smartnode/rocketpool/watchtower/collectors"
 	"github.com/rocket-pool/smartnode/shared/services"
+	cliutils "github.com/rocket-pool/smartnode/shared/utils/cli"
 	"github.com/rocket-pool/smartnode/shared/utils/log"
 )
 
@@ -29,6 +30,7 @@ const (
     DissolveTimedOutMinipoolsColor = color.FgMagenta
     ProcessWithdrawalsColor = color.FgCyan
     SubmitScrubMinipoolsColor = color.FgHiGreen
+    VoteProposalsColor = color.FgHiCyan
     ErrorColor = color.FgRed
     MetricsColor = color.FgHiYellow
 )
@@ -40,9 +42,49 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
         Name:      name,
         Aliases:   aliases,
         Usage:     "Run Rocket Pool watchtower activity daemon",
+        Flags: []cli.Flag{
+            cli.BoolFlag{
+                Name:  "dry-run",
+                Usage: "Compute submissions as normal, but record and compare them against the existing members' submissions instead of sending transactions",
+            },
+        },
         Action: func(c *cli.Context) error {
             return run(c)
         },
+        Subcommands: []cli.Command{
+            cli.Command{
+                Name:      "explain-balances",
+                Aliases:   []string{"e"},
+                Usage:     "Recompute the network balances for a reporting block and compare them with its saved snapshot or another member's submission",
+                UsageText: "rocketpool watchtower explain-balances [options] block-number",
+                Flags: []cli.Flag{
+                    cli.StringFlag{
+                        Name:  "snapshot, s",
+                        Usage: "The balance snapshot file to compare against (defaults to the snapshot saved for the block)",
+                    },
+                    cli.StringFlag{
+                        Name:  "member, m",
+                        Usage: "The address of an oracle DAO member whose submitted balances to compare against instead of a snapshot",
+                    },
+                },
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
+                    blockNumber, err := cliutils.ValidateUint("block number", c.Args().Get(0))
+                    if err != nil { return err }
+
+                    // Validate flags
+                    if c.String("member") != "" {
+                        if _, err := cliutils.ValidateAddress("member address", c.String("member")); err != nil { return err }
+                    }
+
+                    // Run
+                    return explainBalances(c, blockNumber)
+
+                },
+            },
+        },
     })
 }
 
@@ -51,6 +93,7 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 func run(c *cli.Context) error {
 
     // Configure
+    if err := services.ConfigureLogging(c, "watchtower"); err != nil { return err }
     configureHTTP()
 
     // Wait until node is registered
@@ -59,26 +102,36 @@ func run(c *cli.Context) error {
     // Initialize the scrub metrics reporter
     scrubCollector := collectors.NewScrubCollector()
 
+    // Initialize the dry-run recorder
+    dryRun, err := newDryRunRecorder(c)
+    if err != nil { return err }
+
+    // Get services
+    w, err := services.GetWallet(c)
+    if err != nil { return err }
+
     // Initialize tasks
-    respondChallenges, err := newRespondChallenges(c, log.NewColorLogger(RespondChallengesColor))
+    respondChallenges, err := newRespondChallenges(c, log.NewLogger("respond-challenges", RespondChallengesColor), dryRun)
+    if err != nil { return err }
+    claimRplRewards, err := newClaimRplRewards(c, log.NewLogger("claim-rpl-rewards", ClaimRplRewardsColor))
     if err != nil { return err }
-    claimRplRewards, err := newClaimRplRewards(c, log.NewColorLogger(ClaimRplRewardsColor))
+    submitRplPrice, err := newSubmitRplPrice(c, log.NewLogger("submit-rpl-price", SubmitRplPriceColor), dryRun)
     if err != nil { return err }
-    submitRplPrice, err := newSubmitRplPrice(c, log.NewColorLogger(SubmitRplPriceColor))
+    submitNetworkBalances, err := newSubmitNetworkBalances(c, log.NewLogger("submit-network-balances", SubmitNetworkBalancesColor), dryRun)
     if err != nil { return err }
-    submitNetworkBalances, err := newSubmitNetworkBalances(c, log.NewColorLogger(SubmitNetworkBalancesColor))
+    submitWithdrawableMinipools, err := newSubmitWithdrawableMinipools(c, log.NewLogger("submit-withdrawable-minipools", SubmitWithdrawableMinipoolsColor), dryRun)
     if err != nil { return err }
-    submitWithdrawableMinipools, err := newSubmitWithdrawableMinipools(c, log.NewColorLogger(SubmitWithdrawableMinipoolsColor))
+    dissolveTimedOutMinipools, err := newDissolveTimedOutMinipools(c, log.NewLogger("dissolve-timed-out-minipools", DissolveTimedOutMinipoolsColor), dryRun)
     if err != nil { return err }
-    dissolveTimedOutMinipools, err := newDissolveTimedOutMinipools(c, log.NewColorLogger(DissolveTimedOutMinipoolsColor))
+    processWithdrawals, err := newProcessWithdrawals(c, log.NewLogger("process-withdrawals", ProcessWithdrawalsColor))
     if err != nil { return err }
-    processWithdrawals, err := newProcessWithdrawals(c, log.NewColorLogger(ProcessWithdrawalsColor))
+    submitScrubMinipools, err := newSubmitScrubMinipools(c, log.NewLogger("submit-scrub-minipools", SubmitScrubMinipoolsColor), scrubCollector, dryRun)
     if err != nil { return err }
-    submitScrubMinipools, err := newSubmitScrubMinipools(c, log.NewColorLogger(SubmitScrubMinipoolsColor), scrubCollector)
+    voteProposals, err := newVoteProposals(c, log.NewLogger("vote-proposals", VoteProposalsColor))
     if err != nil { return err }
 
     // Initialize error logger
-    errorLog := log.NewColorLogger(ErrorColor)
+    errorLog := log.NewLogger("error", ErrorColor)
 
     intervalDelta := maxTasksInterval - minTasksInterval
     secondsDelta := intervalDelta.Seconds()
@@ -94,36 +147,54 @@ func run(c *cli.Context) error {
             randomSeconds := rand.Intn(int(secondsDelta))
             interval := time.Duration(randomSeconds) * time.Second + minTasksInterval
 
+            // Challenges are monitored in dry-run mode, but only responded to otherwise
+            w.SetTxOrigin("watchtower respond-challenges")
             if err := respondChallenges.run(); err != nil {
-                errorLog.Println(err)
+                errorLog.Error(err)
             }
             time.Sleep(taskCooldown)
-            if err := claimRplRewards.run(); err != nil {
-                errorLog.Println(err)
+
+            // Tasks which only act on behalf of our own node are skipped in dry-run mode
+            if dryRun == nil {
+                w.SetTxOrigin("watchtower claim-rpl-rewards")
+                if err := claimRplRewards.run(); err != nil {
+                    errorLog.Error(err)
+                }
+                time.Sleep(taskCooldown)
+                w.SetTxOrigin("watchtower vote-proposals")
+                if err := voteProposals.run(); err != nil {
+                    errorLog.Error(err)
+                }
+                time.Sleep(taskCooldown)
             }
-            time.Sleep(taskCooldown)
+            w.SetTxOrigin("watchtower submit-rpl-price")
             if err := submitRplPrice.run(); err != nil {
-                errorLog.Println(err)
+                errorLog.Error(err)
             }
             time.Sleep(taskCooldown)
+            w.SetTxOrigin("watchtower submit-network-balances")
             if err := submitNetworkBalances.run(); err != nil {
-                errorLog.Println(err)
+                errorLog.Error(err)
             }
             time.Sleep(taskCooldown)
+            w.SetTxOrigin("watchtower submit-withdrawable-minipools")
             if err := submitWithdrawableMinipools.run(); err != nil {
-                errorLog.Println(err)
+                errorLog.Error(err)
             }
             time.Sleep(taskCooldown)
+            w.SetTxOrigin("watchtower dissolve-timed-out-minipools")
             if err := dissolveTimedOutMinipools.run(); err != nil {
-                errorLog.Println(err)
+                errorLog.Error(err)
             }
             time.Sleep(taskCooldown)
+            w.SetTxOrigin("watchtower process-withdrawals")
             if err := processWithdrawals.run(); err != nil {
-                errorLog.Println(err)
+                errorLog.Error(err)
             }
             time.Sleep(taskCooldown)
+            w.SetTxOrigin("watchtower submit-scrub-minipools")
             if err := submitScrubMinipools.run(); err != nil {
-                errorLog.Println(err)
+                errorLog.Error(err)
             }
             time.Sleep(interval)
         }
@@ -132,9 +203,9 @@ func run(c *cli.Context) error {
 
     // Run metrics loop
     go func() {
-        err := runMetricsServer(c, log.NewColorLogger(MetricsColor), scrubCollector)
+        err := runMetricsServer(c, log.NewLogger("metrics", MetricsColor), scrubCollector)
         if err != nil {
-            errorLog.Println(err)
+            errorLog.Error(err)
         }
         wg.Done()
     }()
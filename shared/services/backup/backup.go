@@ -0,0 +1,382 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/rocket-pool/rocketpool-go/types"
+	eth2ks "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+	"gopkg.in/yaml.v2"
+)
+
+// Backup archive layout
+// A backup is a gzipped tar archive with a manifest as its first entry, encrypted with the backup passphrase
+// Node data is stored relative to the Rocket Pool config folder; custom compose files are stored under the compose folder,
+// with their paths relative to the config folder recorded in the manifest
+const (
+    Version = 1
+    FileExtension = ".rpbackup"
+    ManifestFile = "manifest.yml"
+    ComposeFolder = "compose"
+
+    SettingsPath = "settings.yml"
+    PrometheusPath = "prometheus.yml"
+    PasswordPath = "data/password"
+    WalletPath = "data/wallet"
+    ValidatorsPath = "data/validators"
+    SlashingProtectionPath = ValidatorsPath + "/slashing-protection.json"
+)
+
+
+// Backup manifest
+type Manifest struct {
+    Version int                         `yaml:"version"`
+    CreatedAt time.Time                 `yaml:"createdAt"`
+    ServiceVersion string               `yaml:"serviceVersion"`
+    ConfigVersion int                   `yaml:"configVersion"`
+    ChainID string                      `yaml:"chainID"`
+    Eth2Client string                   `yaml:"eth2Client"`
+    NativeMode bool                     `yaml:"nativeMode,omitempty"`
+    IncludesPassword bool               `yaml:"includesPassword"`
+    SlashingProtection bool             `yaml:"slashingProtection"`
+    ValidatorPubkeys []string           `yaml:"validatorPubkeys,omitempty"`
+    ComposeFiles []ComposeFile          `yaml:"composeFiles,omitempty"`
+    Files []File                        `yaml:"files"`
+}
+type ComposeFile struct {
+    Path string                         `yaml:"path"`
+    HostPath string                     `yaml:"hostPath"`
+}
+type File struct {
+    Path string                         `yaml:"path"`
+    Size int64                          `yaml:"size"`
+    SHA256 string                       `yaml:"sha256"`
+}
+
+
+// An encrypted backup file
+type encryptedBackup struct {
+    Version int                         `json:"version"`
+    CreatedAt time.Time                 `json:"createdAt"`
+    Crypto map[string]interface{}       `json:"crypto"`
+    Name string                         `json:"name"`
+    CryptoVersion uint                  `json:"cryptoVersion"`
+}
+
+
+// Build a backup archive from a gzipped tar archive of node data and custom compose files
+// The manifest's file list and validator pubkeys are populated from the archive contents
+func Pack(manifest *Manifest, data []byte, composeFiles map[string][]byte) ([]byte, error) {
+
+    // Read node data
+    type entry struct {
+        header *tar.Header
+        contents []byte
+    }
+    entries := []entry{}
+    if err := readArchive(data, func(header *tar.Header, contents []byte) error {
+        if header.Typeflag == tar.TypeReg || header.Typeflag == tar.TypeDir {
+            entries = append(entries, entry{header, contents})
+        }
+        return nil
+    }); err != nil {
+        return nil, err
+    }
+    for _, composeFile := range manifest.ComposeFiles {
+        contents := composeFiles[composeFile.HostPath]
+        entries = append(entries, entry{&tar.Header{
+            Name: composeFile.Path,
+            Mode: 0644,
+            Size: int64(len(contents)),
+            ModTime: manifest.CreatedAt,
+            Typeflag: tar.TypeReg,
+        }, contents})
+    }
+
+    // Populate manifest
+    manifest.Version = Version
+    manifest.Files = []File{}
+    manifest.ValidatorPubkeys = []string{}
+    pubkeys := map[string]bool{}
+    for _, e := range entries {
+        if e.header.Typeflag != tar.TypeReg { continue }
+        name := cleanPath(e.header.Name)
+        checksum := sha256.Sum256(e.contents)
+        manifest.Files = append(manifest.Files, File{
+            Path: name,
+            Size: int64(len(e.contents)),
+            SHA256: hex.EncodeToString(checksum[:]),
+        })
+        if name == SlashingProtectionPath {
+            manifest.SlashingProtection = true
+        } else if strings.HasPrefix(name, ValidatorsPath + "/") && strings.HasSuffix(name, ".json") {
+            if pubkey, ok := getKeystorePubkey(e.contents); ok && !pubkeys[pubkey] {
+                pubkeys[pubkey] = true
+                manifest.ValidatorPubkeys = append(manifest.ValidatorPubkeys, pubkey)
+            }
+        }
+    }
+    manifestBytes, err := yaml.Marshal(manifest)
+    if err != nil {
+        return nil, fmt.Errorf("Could not serialize backup manifest: %w", err)
+    }
+
+    // Write archive
+    var archive bytes.Buffer
+    gzw := gzip.NewWriter(&archive)
+    tw := tar.NewWriter(gzw)
+    if err := tw.WriteHeader(&tar.Header{
+        Name: ManifestFile,
+        Mode: 0600,
+        Size: int64(len(manifestBytes)),
+        ModTime: manifest.CreatedAt,
+        Typeflag: tar.TypeReg,
+    }); err != nil {
+        return nil, fmt.Errorf("Could not write backup archive: %w", err)
+    }
+    if _, err := tw.Write(manifestBytes); err != nil {
+        return nil, fmt.Errorf("Could not write backup archive: %w", err)
+    }
+    for _, e := range entries {
+        if err := tw.WriteHeader(e.header); err != nil {
+            return nil, fmt.Errorf("Could not write backup archive: %w", err)
+        }
+        if _, err := tw.Write(e.contents); err != nil {
+            return nil, fmt.Errorf("Could not write backup archive: %w", err)
+        }
+    }
+    if err := tw.Close(); err != nil {
+        return nil, fmt.Errorf("Could not write backup archive: %w", err)
+    }
+    if err := gzw.Close(); err != nil {
+        return nil, fmt.Errorf("Could not write backup archive: %w", err)
+    }
+
+    // Return
+    return archive.Bytes(), nil
+
+}
+
+
+// Read a backup archive, verifying its contents against its manifest
+// Returns the manifest, a gzipped tar archive of the node data to restore and the custom compose files by their path relative to the config folder
+// If excludeValidators is set, the validator keystores & slashing protection data are left out of the node data
+func Unpack(archive []byte, excludeValidators bool) (*Manifest, []byte, map[string][]byte, error) {
+
+    // Read archive
+    var manifest *Manifest
+    verified := map[string]bool{}
+    composeFiles := map[string][]byte{}
+    var data bytes.Buffer
+    gzw := gzip.NewWriter(&data)
+    tw := tar.NewWriter(gzw)
+    if err := readArchive(archive, func(header *tar.Header, contents []byte) error {
+
+        // Read manifest
+        name := cleanPath(header.Name)
+        if manifest == nil {
+            if name != ManifestFile {
+                return errors.New("The backup archive has no manifest.")
+            }
+            manifest = new(Manifest)
+            if err := yaml.Unmarshal(contents, manifest); err != nil {
+                return fmt.Errorf("Could not parse backup manifest: %w", err)
+            }
+            if manifest.Version > Version {
+                return fmt.Errorf("The backup was created with a newer backup format (v%d) than this version of Rocket Pool supports (v%d). Please update the Rocket Pool CLI.", manifest.Version, Version)
+            }
+            for _, composeFile := range manifest.ComposeFiles {
+                if !isRelativePath(composeFile.HostPath) {
+                    return fmt.Errorf("The backup manifest contains an invalid compose file path '%s'.", composeFile.HostPath)
+                }
+            }
+            return nil
+        }
+
+        // Check entries; only regular files & folders within the config folder are restored
+        if !isRelativePath(header.Name) {
+            return fmt.Errorf("The backup archive contains an invalid path '%s'.", header.Name)
+        }
+        if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeDir {
+            return fmt.Errorf("The backup archive contains an unsupported entry '%s'.", header.Name)
+        }
+
+        // Verify file contents
+        if header.Typeflag == tar.TypeReg {
+            file := manifest.getFile(name)
+            if file == nil {
+                return fmt.Errorf("The backup archive contains a file '%s' which is not in its manifest.", name)
+            }
+            checksum := sha256.Sum256(contents)
+            if int64(len(contents)) != file.Size || hex.EncodeToString(checksum[:]) != file.SHA256 {
+                return fmt.Errorf("The backup archive file '%s' does not match its manifest checksum. The backup may be corrupt.", name)
+            }
+            verified[name] = true
+        }
+
+        // Separate compose files
+        if strings.HasPrefix(name, ComposeFolder + "/") {
+            for _, composeFile := range manifest.ComposeFiles {
+                if composeFile.Path == name {
+                    composeFiles[composeFile.HostPath] = contents
+                }
+            }
+            return nil
+        }
+
+        // Add node data
+        if excludeValidators && (name == ValidatorsPath || strings.HasPrefix(name, ValidatorsPath + "/")) {
+            return nil
+        }
+        if err := tw.WriteHeader(header); err != nil {
+            return fmt.Errorf("Could not write node data archive: %w", err)
+        }
+        if _, err := tw.Write(contents); err != nil {
+            return fmt.Errorf("Could not write node data archive: %w", err)
+        }
+        return nil
+
+    }); err != nil {
+        return nil, nil, nil, err
+    }
+    if err := tw.Close(); err != nil {
+        return nil, nil, nil, fmt.Errorf("Could not write node data archive: %w", err)
+    }
+    if err := gzw.Close(); err != nil {
+        return nil, nil, nil, fmt.Errorf("Could not write node data archive: %w", err)
+    }
+
+    // Check that every file in the manifest is present
+    if manifest == nil {
+        return nil, nil, nil, errors.New("The backup archive has no manifest.")
+    }
+    for _, file := range manifest.Files {
+        if !verified[file.Path] {
+            return nil, nil, nil, fmt.Errorf("The backup archive is missing file '%s'. The backup may be corrupt.", file.Path)
+        }
+    }
+
+    // Return
+    return manifest, data.Bytes(), composeFiles, nil
+
+}
+
+
+// Encrypt a backup archive with a passphrase
+func Encrypt(archive []byte, passphrase string, createdAt time.Time) ([]byte, error) {
+    encryptor := eth2ks.New()
+    crypto, err := encryptor.Encrypt(archive, passphrase)
+    if err != nil {
+        return nil, fmt.Errorf("Could not encrypt backup: %w", err)
+    }
+    backupBytes, err := json.Marshal(encryptedBackup{
+        Version: Version,
+        CreatedAt: createdAt,
+        Crypto: crypto,
+        Name: encryptor.Name(),
+        CryptoVersion: encryptor.Version(),
+    })
+    if err != nil {
+        return nil, fmt.Errorf("Could not encode backup: %w", err)
+    }
+    return backupBytes, nil
+}
+
+
+// Decrypt a backup file with a passphrase
+func Decrypt(backupBytes []byte, passphrase string) ([]byte, error) {
+    backup := new(encryptedBackup)
+    if err := json.Unmarshal(backupBytes, backup); err != nil {
+        return nil, fmt.Errorf("Could not decode backup; the file is not a Rocket Pool backup: %w", err)
+    }
+    if backup.Version > Version {
+        return nil, fmt.Errorf("The backup was created with a newer backup format (v%d) than this version of Rocket Pool supports (v%d). Please update the Rocket Pool CLI.", backup.Version, Version)
+    }
+    archive, err := eth2ks.New().Decrypt(backup.Crypto, passphrase)
+    if err != nil {
+        return nil, fmt.Errorf("Could not decrypt backup; please check the passphrase: %w", err)
+    }
+    return archive, nil
+}
+
+
+// Get a file from the manifest by path
+func (m *Manifest) getFile(name string) *File {
+    for fi := range m.Files {
+        if m.Files[fi].Path == name {
+            return &m.Files[fi]
+        }
+    }
+    return nil
+}
+
+
+// Read each entry in a gzipped tar archive
+func readArchive(archive []byte, fn func(*tar.Header, []byte) error) error {
+    gzr, err := gzip.NewReader(bytes.NewReader(archive))
+    if err != nil {
+        return fmt.Errorf("Could not read backup archive: %w", err)
+    }
+    defer gzr.Close()
+    tr := tar.NewReader(gzr)
+    for {
+        header, err := tr.Next()
+        if err == io.EOF { break }
+        if err != nil {
+            return fmt.Errorf("Could not read backup archive: %w", err)
+        }
+        contents, err := ioutil.ReadAll(tr)
+        if err != nil {
+            return fmt.Errorf("Could not read backup archive: %w", err)
+        }
+        if err := fn(header, contents); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+
+// Get the cleaned, relative path of an archive entry
+func cleanPath(name string) string {
+    cleaned := path.Clean(strings.TrimPrefix(name, "./"))
+    if cleaned == "." {
+        return ""
+    }
+    return cleaned
+}
+
+
+// Check whether a path is relative and within the folder it is relative to
+func isRelativePath(name string) bool {
+    cleaned := cleanPath(name)
+    return cleaned != "" && cleaned != ".." && !strings.HasPrefix(cleaned, "../") && !path.IsAbs(name)
+}
+
+
+// Get the validator pubkey from an EIP-2335 keystore file
+func getKeystorePubkey(contents []byte) (string, bool) {
+    var keystore struct {
+        Pubkey string                   `json:"pubkey"`
+        Crypto json.RawMessage          `json:"crypto"`
+    }
+    if err := json.Unmarshal(contents, &keystore); err != nil || keystore.Pubkey == "" || keystore.Crypto == nil {
+        return "", false
+    }
+    pubkey, err := types.HexToValidatorPubkey(strings.TrimPrefix(keystore.Pubkey, "0x"))
+    if err != nil {
+        return "", false
+    }
+    return pubkey.Hex(), true
+}
@@ -0,0 +1,308 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Test validator pubkey
+const testPubkey = "abababababababababababababababababababababababababababababababababababababababababababababababab"
+
+
+// An archive entry
+type testEntry struct {
+    name string
+    typeflag byte
+    contents []byte
+}
+
+
+// Write a gzipped tar archive
+func writeTestArchive(t *testing.T, entries []testEntry) []byte {
+    var archive bytes.Buffer
+    gzw := gzip.NewWriter(&archive)
+    tw := tar.NewWriter(gzw)
+    for _, e := range entries {
+        typeflag := e.typeflag
+        if typeflag == 0 {
+            typeflag = tar.TypeReg
+        }
+        if err := tw.WriteHeader(&tar.Header{Name: e.name, Mode: 0600, Size: int64(len(e.contents)), Typeflag: typeflag}); err != nil {
+            t.Fatal(err)
+        }
+        if _, err := tw.Write(e.contents); err != nil {
+            t.Fatal(err)
+        }
+    }
+    if err := tw.Close(); err != nil {
+        t.Fatal(err)
+    }
+    if err := gzw.Close(); err != nil {
+        t.Fatal(err)
+    }
+    return archive.Bytes()
+}
+
+
+// Read the entries in a gzipped tar archive
+func readTestArchive(t *testing.T, archive []byte) []testEntry {
+    entries := []testEntry{}
+    if err := readArchive(archive, func(header *tar.Header, contents []byte) error {
+        entries = append(entries, testEntry{header.Name, header.Typeflag, contents})
+        return nil
+    }); err != nil {
+        t.Fatal(err)
+    }
+    return entries
+}
+
+
+// Build a test backup archive
+func packTestBackup(t *testing.T) []byte {
+    keystore, err := json.Marshal(map[string]interface{}{"pubkey": "0x" + testPubkey, "crypto": map[string]interface{}{}})
+    if err != nil {
+        t.Fatal(err)
+    }
+    data := writeTestArchive(t, []testEntry{
+        {name: SettingsPath, contents: []byte("chains: {}\n")},
+        {name: "data/", typeflag: tar.TypeDir},
+        {name: WalletPath, contents: []byte("wallet")},
+        {name: "data/validators/lighthouse/keys/key.json", contents: keystore},
+        {name: "data/validators/prysm/keys/key.json", contents: keystore},
+        {name: SlashingProtectionPath, contents: []byte("{}")},
+        {name: "data/validators/link", typeflag: tar.TypeSymlink},
+    })
+    manifest := &Manifest{
+        CreatedAt: time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC),
+        ChainID: "5",
+        ComposeFiles: []ComposeFile{{Path: ComposeFolder + "/0-override.yml", HostPath: "override/override.yml"}},
+    }
+    archive, err := Pack(manifest, data, map[string][]byte{"override/override.yml": []byte("services: {}\n")})
+    if err != nil {
+        t.Fatal(err)
+    }
+    return archive
+}
+
+
+func TestPackUnpack(t *testing.T) {
+    archive := packTestBackup(t)
+
+    // Check manifest
+    manifest, data, composeFiles, err := Unpack(archive, false)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if manifest.Version != Version || manifest.ChainID != "5" || !manifest.SlashingProtection {
+        t.Errorf("manifest is %+v", manifest)
+    }
+    if !reflect.DeepEqual(manifest.ValidatorPubkeys, []string{testPubkey}) {
+        t.Errorf("validator pubkeys are %v, expected [%s]", manifest.ValidatorPubkeys, testPubkey)
+    }
+    if len(manifest.Files) != 6 {
+        t.Errorf("manifest has %d files, expected 6", len(manifest.Files))
+    }
+
+    // Check node data; symlinks are not backed up and compose files are returned separately
+    names := []string{}
+    for _, e := range readTestArchive(t, data) {
+        names = append(names, e.name)
+    }
+    expected := []string{SettingsPath, "data/", WalletPath, "data/validators/lighthouse/keys/key.json", "data/validators/prysm/keys/key.json", SlashingProtectionPath}
+    if !reflect.DeepEqual(names, expected) {
+        t.Errorf("node data is %v, expected %v", names, expected)
+    }
+    if !reflect.DeepEqual(composeFiles, map[string][]byte{"override/override.yml": []byte("services: {}\n")}) {
+        t.Errorf("compose files are %v", composeFiles)
+    }
+
+    // Validator data can be excluded
+    _, data, _, err = Unpack(archive, true)
+    if err != nil {
+        t.Fatal(err)
+    }
+    for _, e := range readTestArchive(t, data) {
+        if strings.HasPrefix(e.name, ValidatorsPath) {
+            t.Errorf("node data without validators includes %s", e.name)
+        }
+    }
+
+}
+
+
+func TestUnpackTampered(t *testing.T) {
+    entries := readTestArchive(t, packTestBackup(t))
+    manifest := new(Manifest)
+    if err := yaml.Unmarshal(entries[0].contents, manifest); err != nil {
+        t.Fatal(err)
+    }
+
+    // Rewrite the archive entries or manifest
+    withEntries := func(modify func([]testEntry) []testEntry) []byte {
+        modified := make([]testEntry, len(entries))
+        copy(modified, entries)
+        return writeTestArchive(t, modify(modified))
+    }
+    withManifest := func(modify func(*Manifest)) []byte {
+        m := *manifest
+        m.Files = append([]File{}, manifest.Files...)
+        m.ComposeFiles = append([]ComposeFile{}, manifest.ComposeFiles...)
+        modify(&m)
+        manifestBytes, err := yaml.Marshal(&m)
+        if err != nil {
+            t.Fatal(err)
+        }
+        return withEntries(func(e []testEntry) []testEntry {
+            e[0].contents = manifestBytes
+            return e
+        })
+    }
+
+    tests := []struct {
+        name string
+        archive []byte
+        errorContains string
+    }{
+        {
+            name: "modified file",
+            archive: withEntries(func(e []testEntry) []testEntry {
+                e[3].contents = []byte("wallet2")
+                return e
+            }),
+            errorContains: "does not match its manifest checksum",
+        },
+        {
+            name: "file not in manifest",
+            archive: withEntries(func(e []testEntry) []testEntry { return append(e, testEntry{name: "data/extra", contents: []byte("extra")}) }),
+            errorContains: "not in its manifest",
+        },
+        {
+            name: "missing file",
+            archive: withEntries(func(e []testEntry) []testEntry { return append(e[:3], e[4:]...) }),
+            errorContains: "missing file 'data/wallet'",
+        },
+        {
+            name: "no manifest",
+            archive: withEntries(func(e []testEntry) []testEntry { return e[1:] }),
+            errorContains: "has no manifest",
+        },
+        {
+            name: "path outside the config folder",
+            archive: withEntries(func(e []testEntry) []testEntry { return append(e, testEntry{name: "data/../../.bashrc", contents: []byte("rm -rf /")}) }),
+            errorContains: "invalid path 'data/../../.bashrc'",
+        },
+        {
+            name: "absolute path",
+            archive: withEntries(func(e []testEntry) []testEntry { return append(e, testEntry{name: "/etc/passwd"}) }),
+            errorContains: "invalid path '/etc/passwd'",
+        },
+        {
+            name: "symlink",
+            archive: withEntries(func(e []testEntry) []testEntry { return append(e, testEntry{name: "data/link", typeflag: tar.TypeSymlink}) }),
+            errorContains: "unsupported entry 'data/link'",
+        },
+        {
+            name: "absolute compose file path",
+            archive: withManifest(func(m *Manifest) { m.ComposeFiles[0].HostPath = "/etc/cron.d/rp" }),
+            errorContains: "invalid compose file path '/etc/cron.d/rp'",
+        },
+        {
+            name: "compose file path outside the config folder",
+            archive: withManifest(func(m *Manifest) { m.ComposeFiles[0].HostPath = "override/../../.profile" }),
+            errorContains: "invalid compose file path 'override/../../.profile'",
+        },
+        {
+            name: "newer version",
+            archive: withManifest(func(m *Manifest) { m.Version = Version + 1 }),
+            errorContains: "newer backup format",
+        },
+        {
+            name: "not an archive",
+            archive: []byte("backup"),
+            errorContains: "Could not read backup archive",
+        },
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            _, _, _, err := Unpack(test.archive, false)
+            if err == nil || !strings.Contains(err.Error(), test.errorContains) {
+                t.Fatalf("expected an error containing '%s', got %v", test.errorContains, err)
+            }
+        })
+    }
+
+}
+
+
+func TestEncryptDecrypt(t *testing.T) {
+    archive := packTestBackup(t)
+    createdAt := time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC)
+    backupBytes, err := Encrypt(archive, "correct horse battery staple", createdAt)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if bytes.Contains(backupBytes, []byte("wallet")) {
+        t.Error("encrypted backup contains plaintext node data")
+    }
+
+    // Decrypt
+    decrypted, err := Decrypt(backupBytes, "correct horse battery staple")
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !bytes.Equal(decrypted, archive) {
+        t.Fatal("decrypted backup does not match the archive")
+    }
+
+    // Tamper with the encrypted backup
+    tamper := func(modify func(backup map[string]interface{})) []byte {
+        backup := map[string]interface{}{}
+        if err := json.Unmarshal(backupBytes, &backup); err != nil {
+            t.Fatal(err)
+        }
+        modify(backup)
+        tampered, err := json.Marshal(backup)
+        if err != nil {
+            t.Fatal(err)
+        }
+        return tampered
+    }
+    tests := []struct {
+        name string
+        backupBytes []byte
+        passphrase string
+        errorContains string
+    }{
+        {"wrong passphrase", backupBytes, "incorrect horse battery staple", "please check the passphrase"},
+        {
+            name: "modified ciphertext",
+            backupBytes: tamper(func(backup map[string]interface{}) {
+                cipher := backup["crypto"].(map[string]interface{})["cipher"].(map[string]interface{})
+                message := []byte(cipher["message"].(string))
+                if message[0] == 'a' { message[0] = 'b' } else { message[0] = 'a' }
+                cipher["message"] = string(message)
+            }),
+            passphrase: "correct horse battery staple",
+            errorContains: "Could not decrypt backup",
+        },
+        {"newer version", tamper(func(backup map[string]interface{}) { backup["version"] = Version + 1 }), "correct horse battery staple", "newer backup format"},
+        {"not a backup", []byte("backup"), "correct horse battery staple", "not a Rocket Pool backup"},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            _, err := Decrypt(test.backupBytes, test.passphrase)
+            if err == nil || !strings.Contains(err.Error(), test.errorContains) {
+                t.Fatalf("expected an error containing '%s', got %v", test.errorContains, err)
+            }
+        })
+    }
+
+}
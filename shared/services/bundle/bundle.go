@@ -0,0 +1,102 @@
+package bundle
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Release bundle files
+// A bundle is published with a checksum file in sha256sum format, and a signature file containing the base64-encoded
+// ed25519 signature of the checksum file by the release key
+const (
+    BundleFile = "rp-smartnode-install.tar.gz"
+    ChecksumExtension = ".sha256"
+    SignatureExtension = ".sig"
+    InstallScript = "install.sh"
+    UpdateTrackerScript = "install-update-tracker.sh"
+)
+
+// The base64-encoded ed25519 public key release bundles are signed with
+// This must be set to the smartnode-install release key before bundle installs can be verified
+const PinnedReleasePublicKey = "REPLACE_WITH_THE_BASE64_RELEASE_PUBLIC_KEY"
+
+
+// The release key bundles are verified with; this is the pinned key unless overridden for testing with:
+// -ldflags "-X github.com/rocket-pool/smartnode/shared/services/bundle.ReleasePublicKey=<key>"
+var ReleasePublicKey = PinnedReleasePublicKey
+
+
+// Check whether bundles are verified with the pinned release key
+func UsesPinnedKey() bool {
+    return ReleasePublicKey == PinnedReleasePublicKey
+}
+
+
+// Check that this build has a valid release key to verify bundles with
+func CheckReleaseKey() error {
+    _, err := getReleaseKey()
+    return err
+}
+
+
+// Verify a release bundle against its checksum & signature with the release key
+func Verify(bundle, checksum, signature []byte) error {
+
+    // Get the release key
+    publicKey, err := getReleaseKey()
+    if err != nil {
+        return err
+    }
+
+    // Verify the checksum signature
+    signatureBytes, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+    if err != nil {
+        return fmt.Errorf("Could not decode the bundle signature: %w", err)
+    }
+    if !ed25519.Verify(publicKey, checksum, signatureBytes) {
+        return errors.New("The bundle checksum signature is invalid. The bundle may have been tampered with.")
+    }
+
+    // Verify the bundle checksum
+    expectedChecksum, err := parseChecksum(checksum)
+    if err != nil {
+        return err
+    }
+    actualChecksum := sha256.Sum256(bundle)
+    if hex.EncodeToString(actualChecksum[:]) != expectedChecksum {
+        return fmt.Errorf("The bundle SHA-256 checksum %s does not match the signed checksum %s. The bundle may be corrupt or have been tampered with.", hex.EncodeToString(actualChecksum[:]), expectedChecksum)
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Decode the release key
+func getReleaseKey() (ed25519.PublicKey, error) {
+    publicKey, err := base64.StdEncoding.DecodeString(ReleasePublicKey)
+    if err != nil || len(publicKey) != ed25519.PublicKeySize {
+        return nil, errors.New("The release key this build verifies bundles with is invalid.")
+    }
+    return ed25519.PublicKey(publicKey), nil
+}
+
+
+// Parse the SHA-256 checksum from a checksum file in sha256sum format
+func parseChecksum(checksum []byte) (string, error) {
+    fields := strings.Fields(string(checksum))
+    if len(fields) == 0 {
+        return "", errors.New("The bundle checksum file is empty.")
+    }
+    expected := strings.ToLower(fields[0])
+    if decoded, err := hex.DecodeString(expected); err != nil || len(decoded) != sha256.Size {
+        return "", fmt.Errorf("The bundle checksum file contains an invalid SHA-256 checksum: %s", fields[0])
+    }
+    return expected, nil
+}
@@ -0,0 +1,131 @@
+package bundle
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+
+func TestVerify(t *testing.T) {
+
+    // Sign a test bundle with a test key
+    publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+    _, otherKey, err := ed25519.GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+    bundle := []byte("bundle contents")
+    bundleChecksum := sha256.Sum256(bundle)
+    checksum := []byte(hex.EncodeToString(bundleChecksum[:]) + "  " + BundleFile + "\n")
+    sign := func(key ed25519.PrivateKey, message []byte) []byte {
+        return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(key, message)) + "\n")
+    }
+
+    tests := []struct {
+        name string
+        publicKey string
+        bundle []byte
+        checksum []byte
+        signature []byte
+        errorContains string
+    }{
+        {
+            name: "valid bundle",
+            publicKey: base64.StdEncoding.EncodeToString(publicKey),
+            bundle: bundle,
+            checksum: checksum,
+            signature: sign(privateKey, checksum),
+        },
+        {
+            name: "modified bundle",
+            publicKey: base64.StdEncoding.EncodeToString(publicKey),
+            bundle: []byte("modified contents"),
+            checksum: checksum,
+            signature: sign(privateKey, checksum),
+            errorContains: "does not match the signed checksum",
+        },
+        {
+            name: "signed by another key",
+            publicKey: base64.StdEncoding.EncodeToString(publicKey),
+            bundle: bundle,
+            checksum: checksum,
+            signature: sign(otherKey, checksum),
+            errorContains: "signature is invalid",
+        },
+        {
+            name: "invalid signature encoding",
+            publicKey: base64.StdEncoding.EncodeToString(publicKey),
+            bundle: bundle,
+            checksum: checksum,
+            signature: []byte("not base64!"),
+            errorContains: "Could not decode the bundle signature",
+        },
+        {
+            name: "invalid checksum",
+            publicKey: base64.StdEncoding.EncodeToString(publicKey),
+            bundle: bundle,
+            checksum: []byte("abc  " + BundleFile),
+            signature: sign(privateKey, []byte("abc  " + BundleFile)),
+            errorContains: "invalid SHA-256 checksum",
+        },
+        {
+            name: "invalid release key",
+            publicKey: "invalid",
+            bundle: bundle,
+            checksum: checksum,
+            signature: sign(privateKey, checksum),
+            errorContains: "release key this build verifies bundles with is invalid",
+        },
+    }
+    defer func(key string) { ReleasePublicKey = key }(ReleasePublicKey)
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            ReleasePublicKey = test.publicKey
+            err := Verify(test.bundle, test.checksum, test.signature)
+            if test.errorContains != "" {
+                if err == nil || !strings.Contains(err.Error(), test.errorContains) {
+                    t.Fatalf("expected an error containing '%s', got %v", test.errorContains, err)
+                }
+            } else if err != nil {
+                t.Fatal(err)
+            }
+        })
+    }
+
+}
+
+
+func TestCheckReleaseKey(t *testing.T) {
+    defer func(key string) { ReleasePublicKey = key }(ReleasePublicKey)
+
+    // The placeholder key is rejected until the release key is pinned
+    ReleasePublicKey = PinnedReleasePublicKey
+    if _, err := base64.StdEncoding.DecodeString(PinnedReleasePublicKey); err != nil {
+        if err := CheckReleaseKey(); err == nil {
+            t.Error("placeholder release key was accepted")
+        }
+    }
+
+    // Valid keys are accepted
+    publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+    ReleasePublicKey = base64.StdEncoding.EncodeToString(publicKey)
+    if err := CheckReleaseKey(); err != nil {
+        t.Error(err)
+    }
+    ReleasePublicKey = base64.StdEncoding.EncodeToString(publicKey[1:])
+    if err := CheckReleaseKey(); err == nil {
+        t.Error("short release key was accepted")
+    }
+
+}
@@ -5,7 +5,10 @@ import (
 	"io/ioutil"
 	"math/big"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/imdario/mergo"
 	"github.com/urfave/cli"
@@ -14,13 +17,20 @@ import (
 	"github.com/rocket-pool/rocketpool-go/utils/eth"
 )
 
+// Settings
+const DefaultBalanceSnapshotsKept = 100
+
+
 // Rocket Pool config
 type RocketPoolConfig struct {
+    Version int                         `yaml:"version,omitempty"`
     Rocketpool struct {
         StorageAddress string           `yaml:"storageAddress,omitempty"`
         OneInchOracleAddress string     `yaml:"oneInchOracleAddress,omitempty"`
         RplTokenAddress string          `yaml:"rplTokenAddress,omitempty"`
+        WethTokenAddress string         `yaml:"wethTokenAddress,omitempty"`
         RPLFaucetAddress string         `yaml:"rplFaucetAddress,omitempty"`
+        MulticallAddress string         `yaml:"multicallAddress,omitempty"`
     }                                   `yaml:"rocketpool,omitempty"`
     Smartnode struct {
         ProjectName string              `yaml:"projectName,omitempty"`
@@ -29,6 +39,15 @@ type RocketPoolConfig struct {
         PasswordPath string             `yaml:"passwordPath,omitempty"`
         WalletPath string               `yaml:"walletPath,omitempty"`
         ValidatorKeychainPath string    `yaml:"validatorKeychainPath,omitempty"`
+        WatchtowerPath string           `yaml:"watchtowerPath,omitempty"`
+        BalanceSnapshotsKept int        `yaml:"balanceSnapshotsKept,omitempty"`
+        BalanceSnapshotsMaxAge string   `yaml:"balanceSnapshotsMaxAge,omitempty"`
+        TxHistoryPath string            `yaml:"txHistoryPath,omitempty"`
+        VotePolicyPath string           `yaml:"votePolicyPath,omitempty"`
+        NotificationUrl string          `yaml:"notificationUrl,omitempty"`
+        RplPriceSources []RplPriceSource `yaml:"rplPriceSources,omitempty"`
+        RplPriceAggregation string      `yaml:"rplPriceAggregation,omitempty"`
+        RplPriceTolerance float64       `yaml:"rplPriceTolerance,omitempty"`
         ValidatorRestartCommand string  `yaml:"validatorRestartCommand,omitempty"`
         MaxFee float64                  `yaml:"maxFee,omitempty"`
         MaxPriorityFee float64          `yaml:"maxPriorityFee,omitempty"`
@@ -42,6 +61,8 @@ type RocketPoolConfig struct {
         Eth2 Chain                      `yaml:"eth2,omitempty"`
     }                                   `yaml:"chains,omitempty"`
     Metrics Metrics                     `yaml:"metrics,omitempty"`
+    Native Native                       `yaml:"native,omitempty"`
+    Logging Logging                     `yaml:"logging,omitempty"`
 }
 type Chain struct {
     Provider string                     `yaml:"provider,omitempty"`
@@ -64,6 +85,9 @@ type ClientOption struct {
     CompatibleEth2Clients string        `yaml:"compatibleEth2Clients,omitempty"`
     EventLogInterval string             `yaml:"eventLogInterval,omitempty"`
     Supermajority bool                  `yaml:"supermajority,omitempty"`
+    NativeCommand string                `yaml:"nativeCommand,omitempty"`
+    NativeValidatorCommand string       `yaml:"nativeValidatorCommand,omitempty"`
+    SlashingProtectionExport string     `yaml:"slashingProtectionExport,omitempty"`
     Params []ClientParam                `yaml:"params,omitempty"`
 }
 type ClientParam struct {
@@ -82,11 +106,34 @@ type UserParam struct {
     Env string                          `yaml:"env,omitempty"`
     Value string                        `yaml:"value"`
 }
+type RplPriceSource struct {
+    Type string                         `yaml:"type,omitempty"`
+    Address string                      `yaml:"address,omitempty"`
+    TwapPeriod string                   `yaml:"twapPeriod,omitempty"`
+    MaxAge string                       `yaml:"maxAge,omitempty"`
+}
 type Metrics struct {
     Enabled bool                        `yaml:"enabled,omitempty"`
     Params []ClientParam                `yaml:"params,omitempty"`
     Settings []UserParam                `yaml:"settings,omitempty"`
 }
+type Native struct {
+    Enabled bool                        `yaml:"enabled,omitempty"`
+    DaemonPath string                   `yaml:"daemonPath,omitempty"`
+    DataPath string                     `yaml:"dataPath,omitempty"`
+    UnitPath string                     `yaml:"unitPath,omitempty"`
+    User string                         `yaml:"user,omitempty"`
+    Eth1Command string                  `yaml:"eth1Command,omitempty"`
+    Eth2Command string                  `yaml:"eth2Command,omitempty"`
+    ValidatorCommand string             `yaml:"validatorCommand,omitempty"`
+}
+type Logging struct {
+    Level string                        `yaml:"level,omitempty"`
+    Format string                       `yaml:"format,omitempty"`
+    Path string                         `yaml:"path,omitempty"`
+    MaxSize uint64                      `yaml:"maxSize,omitempty"`
+    MaxBackups uint64                   `yaml:"maxBackups,omitempty"`
+}
 
 
 // Get the selected clients from a config
@@ -239,6 +286,70 @@ func ValidateMetricDefaults(Params []ClientParam) error {
 }
 
 
+// Validate a parameter value against the parameter's required, format, type and maximum rules
+// The maximum is a value limit for numeric parameters and a length limit for string parameters
+func (param *ClientParam) ValidateValue(value string) error {
+
+    // Check required params
+    if value == "" {
+        if param.Required {
+            return fmt.Errorf("%s is required", param.Name)
+        }
+        return nil
+    }
+
+    // Check format
+    if param.Regex != "" {
+        matched, err := regexp.MatchString(param.Regex, value)
+        if err != nil {
+            return fmt.Errorf("%s has an invalid format '%s': %w", param.Name, param.Regex, err)
+        }
+        if !matched {
+            return fmt.Errorf("'%s' is not a valid value for %s (must match '%s')", value, param.Name, param.Regex)
+        }
+    }
+
+    // Check type & maximum
+    var number uint64
+    var err error
+    switch param.Type {
+        case "", "string":
+            if param.Max != "" {
+                maxLength, err := strconv.Atoi(param.Max)
+                if err != nil {
+                    return fmt.Errorf("%s has an invalid maximum length '%s': %w", param.Name, param.Max, err)
+                }
+                if len(value) > maxLength {
+                    return fmt.Errorf("'%s' is too long for %s (maximum %d characters)", value, param.Name, maxLength)
+                }
+            }
+            return nil
+        case "uint":
+            number, err = strconv.ParseUint(value, 0, 0)
+        case "uint16":
+            number, err = strconv.ParseUint(value, 0, 16)
+        default:
+            return fmt.Errorf("%s has an unknown type '%s'", param.Name, param.Type)
+    }
+    if err != nil {
+        return fmt.Errorf("'%s' is not a valid %s value for %s", value, param.Type, param.Name)
+    }
+    if param.Max != "" {
+        max, err := strconv.ParseUint(param.Max, 0, 64)
+        if err != nil {
+            return fmt.Errorf("%s has an invalid maximum '%s': %w", param.Name, param.Max, err)
+        }
+        if number > max {
+            return fmt.Errorf("'%s' is too large for %s (maximum %d)", value, param.Name, max)
+        }
+    }
+
+    // Return
+    return nil
+
+}
+
+
 // Merge configs
 func Merge(configs ...*RocketPoolConfig) (RocketPoolConfig, error) {
     var merged RocketPoolConfig
@@ -302,7 +413,9 @@ func getCliConfig(c *cli.Context) RocketPoolConfig {
     config.Rocketpool.StorageAddress = c.GlobalString("storageAddress")
     config.Rocketpool.OneInchOracleAddress = c.GlobalString("oneInchOracleAddress")
     config.Rocketpool.RplTokenAddress = c.GlobalString("rplTokenAddress")
+    config.Rocketpool.WethTokenAddress = c.GlobalString("wethTokenAddress")
     config.Rocketpool.RPLFaucetAddress = c.GlobalString("rplFaucetAddress")
+    config.Rocketpool.MulticallAddress = c.GlobalString("multicallAddress")
     config.Smartnode.PasswordPath = c.GlobalString("password")
     config.Smartnode.WalletPath = c.GlobalString("wallet")
     config.Smartnode.ValidatorKeychainPath = c.GlobalString("validatorKeychain")
@@ -356,3 +469,83 @@ func (config *RocketPoolConfig) GetGasLimit() (uint64, error) {
 
 }
 
+
+
+// Get the path of the watchtower's persistent data folder
+func (config *RocketPoolConfig) GetWatchtowerPath() string {
+
+    // Default to a folder alongside the node wallet
+    if config.Smartnode.WatchtowerPath == "" {
+        return filepath.Join(filepath.Dir(os.ExpandEnv(config.Smartnode.WalletPath)), "watchtower")
+    }
+
+    // Return
+    return os.ExpandEnv(config.Smartnode.WatchtowerPath)
+
+}
+
+
+// Parse and return the watchtower's balance snapshot retention: the number of snapshots kept, and the max age of snapshots (0 if unlimited)
+// Defaults to keeping the latest DefaultBalanceSnapshotsKept snapshots; a negative count keeps all snapshots
+func (config *RocketPoolConfig) GetBalanceSnapshotRetention() (int, time.Duration, error) {
+
+    // Get the count
+    kept := config.Smartnode.BalanceSnapshotsKept
+    if kept == 0 {
+        kept = DefaultBalanceSnapshotsKept
+    }
+
+    // Get the max age
+    var maxAge time.Duration
+    if config.Smartnode.BalanceSnapshotsMaxAge != "" {
+        var err error
+        maxAge, err = time.ParseDuration(config.Smartnode.BalanceSnapshotsMaxAge)
+        if err != nil || maxAge < 0 {
+            return 0, 0, fmt.Errorf("Invalid balance snapshot max age '%s'", config.Smartnode.BalanceSnapshotsMaxAge)
+        }
+    }
+
+    // Return
+    return kept, maxAge, nil
+
+}
+
+
+// Get the path of the watchtower's oracle DAO proposal voting policy
+func (config *RocketPoolConfig) GetVotePolicyPath() string {
+
+    // Default to a file in the watchtower's data folder
+    if config.Smartnode.VotePolicyPath == "" {
+        return filepath.Join(config.GetWatchtowerPath(), "vote-policy.yml")
+    }
+
+    // Return
+    return os.ExpandEnv(config.Smartnode.VotePolicyPath)
+
+}
+
+
+// Get the path of the folder containing the watchtower's scrub evidence reports
+func (config *RocketPoolConfig) GetScrubEvidencePath() string {
+    return filepath.Join(config.GetWatchtowerPath(), "scrub-evidence")
+}
+
+
+// Get the path of the local index of beacon deposit contract events
+func (config *RocketPoolConfig) GetDepositIndexPath() string {
+    return filepath.Join(filepath.Dir(os.ExpandEnv(config.Smartnode.WalletPath)), "deposit-index")
+}
+
+
+// Get the path of the node's transaction history log
+func (config *RocketPoolConfig) GetTxHistoryPath() string {
+
+    // Default to a file alongside the node wallet
+    if config.Smartnode.TxHistoryPath == "" {
+        return filepath.Join(filepath.Dir(os.ExpandEnv(config.Smartnode.WalletPath)), "tx-history.jsonl")
+    }
+
+    // Return
+    return os.ExpandEnv(config.Smartnode.TxHistoryPath)
+
+}
@@ -0,0 +1,239 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Service containers
+const (
+    ContainerEth1 = "eth1"
+    ContainerEth2 = "eth2"
+    ContainerValidator = "validator"
+    ContainerApi = "api"
+    ContainerNode = "node"
+    ContainerWatchtower = "watchtower"
+)
+var MetricsContainers = []string{"prometheus", "grafana", "node-exporter", "exporter"}
+var daemonContainers = []string{ContainerApi, ContainerNode, ContainerWatchtower}
+
+
+// A change between two configs and the containers which must be recreated to apply it
+// Changes which add or remove containers require a full restart of the service
+type ConfigChange struct {
+    Setting string
+    OldValue string
+    NewValue string
+    Containers []string
+    FullRestart bool
+}
+
+
+// Get the changes between two merged configs
+func DiffConfigs(oldConfig, newConfig RocketPoolConfig) []ConfigChange {
+    changes := []ConfigChange{}
+    changes = append(changes, diffChain(oldConfig.Chains.Eth1, newConfig.Chains.Eth1, "eth1", []string{ContainerEth1})...)
+    changes = append(changes, diffChain(oldConfig.Chains.Eth2, newConfig.Chains.Eth2, "eth2", []string{ContainerEth2, ContainerValidator})...)
+    changes = append(changes, diffMetrics(oldConfig.Metrics, newConfig.Metrics)...)
+    changes = append(changes, diffNative(oldConfig.Native, newConfig.Native)...)
+    changes = append(changes, diffLogging(oldConfig.Logging, newConfig.Logging)...)
+    changes = append(changes, diffValue("smartnode.image", oldConfig.Smartnode.Image, newConfig.Smartnode.Image, daemonContainers)...)
+    changes = append(changes, diffValue("smartnode.graffitiVersion", oldConfig.Smartnode.GraffitiVersion, newConfig.Smartnode.GraffitiVersion, []string{ContainerValidator})...)
+    if oldConfig.Smartnode.ProjectName != newConfig.Smartnode.ProjectName {
+        changes = append(changes, ConfigChange{
+            Setting: "smartnode.projectName",
+            OldValue: oldConfig.Smartnode.ProjectName,
+            NewValue: newConfig.Smartnode.ProjectName,
+            FullRestart: true,
+        })
+    }
+    return changes
+}
+
+
+// Get the containers affected by a set of changes, and whether the changes require a full restart
+func GetAffectedContainers(changes []ConfigChange) ([]string, bool) {
+    affected := map[string]bool{}
+    fullRestart := false
+    for _, change := range changes {
+        for _, container := range change.Containers {
+            affected[container] = true
+        }
+        fullRestart = fullRestart || change.FullRestart
+    }
+    containers := []string{}
+    for container := range affected {
+        containers = append(containers, container)
+    }
+    sort.Strings(containers)
+    return containers, fullRestart
+}
+
+
+// Get the changes between two chain configs
+// Client changes also affect the daemons, which use the selected eth2 client's API and the eth1 client's event log settings
+func diffChain(oldChain, newChain Chain, chainName string, containers []string) []ConfigChange {
+
+    // Client & providers
+    clientContainers := append(append([]string{}, containers...), daemonContainers...)
+    providerContainers := append([]string{ContainerEth2}, daemonContainers...)
+    if chainName == "eth2" {
+        providerContainers = append([]string{ContainerValidator}, daemonContainers...)
+    }
+    changes := []ConfigChange{}
+    changes = append(changes, diffValue(chainName + ".client", oldChain.Client.Selected, newChain.Client.Selected, clientContainers)...)
+    changes = append(changes, diffValue(chainName + ".provider", oldChain.Provider, newChain.Provider, providerContainers)...)
+    changes = append(changes, diffValue(chainName + ".wsProvider", oldChain.WsProvider, newChain.WsProvider, providerContainers)...)
+
+    // Client images
+    oldClient := oldChain.GetSelectedClient()
+    newClient := newChain.GetSelectedClient()
+    if oldClient != nil && newClient != nil && oldClient.ID == newClient.ID {
+        changes = append(changes, diffValue(chainName + ".image", oldClient.GetBeaconImage(), newClient.GetBeaconImage(), containers[:1])...)
+        if len(containers) > 1 {
+            changes = append(changes, diffValue(chainName + ".validatorImage", oldClient.GetValidatorImage(), newClient.GetValidatorImage(), containers[1:])...)
+        }
+        changes = append(changes, diffValue(chainName + ".nativeCommand", oldClient.NativeCommand, newClient.NativeCommand, containers[:1])...)
+        if len(containers) > 1 {
+            changes = append(changes, diffValue(chainName + ".nativeValidatorCommand", oldClient.NativeValidatorCommand, newClient.NativeValidatorCommand, containers[1:])...)
+        }
+    }
+
+    // Params
+    changes = append(changes, diffParams(chainName, getEffectiveParams(oldClient, oldChain.Client.Params), getEffectiveParams(newClient, newChain.Client.Params), containers)...)
+    return changes
+
+}
+
+
+// Get the changes between two metrics configs
+func diffMetrics(oldMetrics, newMetrics Metrics) []ConfigChange {
+
+    // Enabling or disabling metrics adds or removes containers
+    if oldMetrics.Enabled != newMetrics.Enabled {
+        return []ConfigChange{{
+            Setting: "metrics.enabled",
+            OldValue: strconv.FormatBool(oldMetrics.Enabled),
+            NewValue: strconv.FormatBool(newMetrics.Enabled),
+            FullRestart: true,
+        }}
+    }
+    if !newMetrics.Enabled {
+        return []ConfigChange{}
+    }
+
+    // Params; the daemons serve metrics on the configured ports
+    oldParams := getEffectiveMetricsParams(oldMetrics)
+    newParams := getEffectiveMetricsParams(newMetrics)
+    return diffParams("metrics", oldParams, newParams, append(append([]string{}, MetricsContainers...), daemonContainers...))
+
+}
+
+
+// Get the changes between two native mode configs
+// Switching modes or moving the data or unit paths requires a full restart
+func diffNative(oldNative, newNative Native) []ConfigChange {
+    changes := []ConfigChange{}
+    for _, value := range []struct{ setting, oldValue, newValue string }{
+        {"native.enabled", strconv.FormatBool(oldNative.Enabled), strconv.FormatBool(newNative.Enabled)},
+        {"native.dataPath", oldNative.DataPath, newNative.DataPath},
+        {"native.unitPath", oldNative.UnitPath, newNative.UnitPath},
+        {"native.user", oldNative.User, newNative.User},
+    } {
+        if value.oldValue != value.newValue {
+            changes = append(changes, ConfigChange{
+                Setting: value.setting,
+                OldValue: value.oldValue,
+                NewValue: value.newValue,
+                FullRestart: true,
+            })
+        }
+    }
+    changes = append(changes, diffValue("native.daemonPath", oldNative.DaemonPath, newNative.DaemonPath, []string{ContainerNode, ContainerWatchtower})...)
+    changes = append(changes, diffValue("native.eth1Command", oldNative.Eth1Command, newNative.Eth1Command, []string{ContainerEth1})...)
+    changes = append(changes, diffValue("native.eth2Command", oldNative.Eth2Command, newNative.Eth2Command, []string{ContainerEth2})...)
+    changes = append(changes, diffValue("native.validatorCommand", oldNative.ValidatorCommand, newNative.ValidatorCommand, []string{ContainerValidator})...)
+    return changes
+}
+
+
+// Get the changes between two logging configs
+// The level and format are also passed to the eth1 container for the pow proxy
+func diffLogging(oldLogging, newLogging Logging) []ConfigChange {
+    changes := []ConfigChange{}
+    proxyContainers := append([]string{ContainerEth1}, daemonContainers...)
+    changes = append(changes, diffValue("logging.level", oldLogging.Level, newLogging.Level, proxyContainers)...)
+    changes = append(changes, diffValue("logging.format", oldLogging.Format, newLogging.Format, proxyContainers)...)
+    changes = append(changes, diffValue("logging.path", oldLogging.Path, newLogging.Path, daemonContainers)...)
+    changes = append(changes, diffValue("logging.maxSize", strconv.FormatUint(oldLogging.MaxSize, 10), strconv.FormatUint(newLogging.MaxSize, 10), daemonContainers)...)
+    changes = append(changes, diffValue("logging.maxBackups", strconv.FormatUint(oldLogging.MaxBackups, 10), strconv.FormatUint(newLogging.MaxBackups, 10), daemonContainers)...)
+    return changes
+}
+
+
+// Get the changes between two sets of effective param values
+func diffParams(section string, oldParams, newParams map[string]string, containers []string) []ConfigChange {
+    envs := []string{}
+    for env := range oldParams {
+        envs = append(envs, env)
+    }
+    for env := range newParams {
+        if _, exists := oldParams[env]; !exists {
+            envs = append(envs, env)
+        }
+    }
+    sort.Strings(envs)
+    changes := []ConfigChange{}
+    for _, env := range envs {
+        changes = append(changes, diffValue(fmt.Sprintf("%s.params.%s", section, env), oldParams[env], newParams[env], containers)...)
+    }
+    return changes
+}
+
+
+// Get the change to a single value, if any
+func diffValue(setting, oldValue, newValue string, containers []string) []ConfigChange {
+    if oldValue == newValue {
+        return []ConfigChange{}
+    }
+    return []ConfigChange{{
+        Setting: setting,
+        OldValue: oldValue,
+        NewValue: newValue,
+        Containers: containers,
+    }}
+}
+
+
+// Get the param values passed to a client's containers, using defaults for params which aren't set
+func getEffectiveParams(client *ClientOption, userParams []UserParam) map[string]string {
+    params := map[string]string{}
+    if client == nil {
+        return params
+    }
+    for _, param := range client.Params {
+        params[param.Env] = param.Default
+    }
+    for _, userParam := range userParams {
+        if _, exists := params[userParam.Env]; exists {
+            params[userParam.Env] = userParam.Value
+        }
+    }
+    return params
+}
+
+
+// Get the param values passed to the metrics containers, using defaults for params which aren't set
+func getEffectiveMetricsParams(metrics Metrics) map[string]string {
+    params := map[string]string{}
+    for _, param := range metrics.Params {
+        params[param.Env] = param.Default
+    }
+    for _, setting := range metrics.Settings {
+        if _, exists := params[setting.Env]; exists {
+            params[setting.Env] = setting.Value
+        }
+    }
+    return params
+}
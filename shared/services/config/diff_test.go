@@ -0,0 +1,175 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Global config the user settings are merged with
+const testDiffGlobalConfig = `
+smartnode:
+  projectName: rocketpool
+  image: rocketpool/smartnode:v1
+chains:
+  eth1:
+    client:
+      options:
+      - id: geth
+        image: ethereum/client-go:v1
+        params:
+        - env: ETH1_MAX_PEERS
+          type: uint
+          default: "50"
+  eth2:
+    client:
+      options:
+      - id: lighthouse
+        image: sigp/lighthouse:v1
+        params:
+        - env: ETH2_MAX_PEERS
+          type: uint
+          default: "100"
+      - id: prysm
+        beaconImage: prysm/beacon:v1
+        validatorImage: prysm/validator:v1
+metrics:
+  params:
+  - env: GRAFANA_PORT
+    type: uint
+    default: "3100"
+`
+
+// User settings selecting geth and lighthouse
+const testDiffUserConfig = `
+chains:
+  eth1:
+    client:
+      selected: geth
+  eth2:
+    client:
+      selected: lighthouse
+`
+
+
+// Merge user settings with the test global config
+func mergeTestDiffConfig(t *testing.T, userSettings string) RocketPoolConfig {
+    globalConfig, err := Parse([]byte(testDiffGlobalConfig))
+    if err != nil {
+        t.Fatal(err)
+    }
+    userConfig, err := Parse([]byte(userSettings))
+    if err != nil {
+        t.Fatal(err)
+    }
+    merged, err := Merge(&globalConfig, &userConfig)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return merged
+}
+
+
+func TestDiffConfigs(t *testing.T) {
+    tests := []struct {
+        name string
+        oldSettings string
+        newSettings string
+        expected []ConfigChange
+    }{
+        {
+            name: "no changes",
+            oldSettings: testDiffUserConfig,
+            newSettings: testDiffUserConfig,
+            expected: []ConfigChange{},
+        },
+        {
+            name: "eth2 param",
+            oldSettings: testDiffUserConfig,
+            newSettings: testDiffUserConfig + "      params:\n      - env: ETH2_MAX_PEERS\n        value: \"50\"\n",
+            expected: []ConfigChange{{Setting: "eth2.params.ETH2_MAX_PEERS", OldValue: "100", NewValue: "50", Containers: []string{ContainerEth2, ContainerValidator}}},
+        },
+        {
+            name: "param set to its default",
+            oldSettings: testDiffUserConfig,
+            newSettings: testDiffUserConfig + "      params:\n      - env: ETH2_MAX_PEERS\n        value: \"100\"\n",
+            expected: []ConfigChange{},
+        },
+        {
+            name: "eth2 client",
+            oldSettings: testDiffUserConfig,
+            newSettings: "chains:\n  eth1:\n    client:\n      selected: geth\n  eth2:\n    client:\n      selected: prysm\n",
+            expected: []ConfigChange{
+                {Setting: "eth2.client", OldValue: "lighthouse", NewValue: "prysm", Containers: []string{ContainerEth2, ContainerValidator, ContainerApi, ContainerNode, ContainerWatchtower}},
+                {Setting: "eth2.params.ETH2_MAX_PEERS", OldValue: "100", NewValue: "", Containers: []string{ContainerEth2, ContainerValidator}},
+            },
+        },
+        {
+            name: "eth1 provider",
+            oldSettings: testDiffUserConfig,
+            newSettings: "chains:\n  eth1:\n    provider: http://eth1:8545\n    client:\n      selected: geth\n  eth2:\n    client:\n      selected: lighthouse\n",
+            expected: []ConfigChange{{Setting: "eth1.provider", OldValue: "", NewValue: "http://eth1:8545", Containers: []string{ContainerEth2, ContainerApi, ContainerNode, ContainerWatchtower}}},
+        },
+        {
+            name: "metrics enabled",
+            oldSettings: testDiffUserConfig,
+            newSettings: testDiffUserConfig + "metrics:\n  enabled: true\n",
+            expected: []ConfigChange{{Setting: "metrics.enabled", OldValue: "false", NewValue: "true", FullRestart: true}},
+        },
+        {
+            name: "metrics param while disabled",
+            oldSettings: testDiffUserConfig,
+            newSettings: testDiffUserConfig + "metrics:\n  settings:\n  - env: GRAFANA_PORT\n    value: \"3200\"\n",
+            expected: []ConfigChange{},
+        },
+        {
+            name: "metrics param while enabled",
+            oldSettings: testDiffUserConfig + "metrics:\n  enabled: true\n",
+            newSettings: testDiffUserConfig + "metrics:\n  enabled: true\n  settings:\n  - env: GRAFANA_PORT\n    value: \"3200\"\n",
+            expected: []ConfigChange{{
+                Setting: "metrics.params.GRAFANA_PORT", OldValue: "3100", NewValue: "3200",
+                Containers: []string{"prometheus", "grafana", "node-exporter", "exporter", ContainerApi, ContainerNode, ContainerWatchtower},
+            }},
+        },
+        {
+            name: "smartnode settings",
+            oldSettings: testDiffUserConfig,
+            newSettings: testDiffUserConfig + "smartnode:\n  projectName: rp\n  image: rocketpool/smartnode:v2\n",
+            expected: []ConfigChange{
+                {Setting: "smartnode.image", OldValue: "rocketpool/smartnode:v1", NewValue: "rocketpool/smartnode:v2", Containers: []string{ContainerApi, ContainerNode, ContainerWatchtower}},
+                {Setting: "smartnode.projectName", OldValue: "rocketpool", NewValue: "rp", FullRestart: true},
+            },
+        },
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            changes := DiffConfigs(mergeTestDiffConfig(t, test.oldSettings), mergeTestDiffConfig(t, test.newSettings))
+            if !reflect.DeepEqual(changes, test.expected) {
+                t.Errorf("changes are %+v, expected %+v", changes, test.expected)
+            }
+        })
+    }
+}
+
+
+func TestGetAffectedContainers(t *testing.T) {
+    changes := []ConfigChange{
+        {Setting: "eth1.image", Containers: []string{ContainerEth1}},
+        {Setting: "eth1.provider", Containers: []string{ContainerEth2, ContainerNode, ContainerApi}},
+    }
+
+    // Containers are merged and sorted
+    containers, fullRestart := GetAffectedContainers(changes)
+    if expected := []string{ContainerApi, ContainerEth1, ContainerEth2, ContainerNode}; !reflect.DeepEqual(containers, expected) {
+        t.Errorf("containers are %v, expected %v", containers, expected)
+    }
+    if fullRestart {
+        t.Error("changes require a full restart, expected none")
+    }
+
+    // Any change requiring a full restart requires it for all changes
+    changes = append(changes, ConfigChange{Setting: "metrics.enabled", FullRestart: true})
+    if _, fullRestart := GetAffectedContainers(changes); !fullRestart {
+        t.Error("changes do not require a full restart, expected one")
+    }
+
+}
@@ -0,0 +1,211 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// The current user settings schema version
+const CurrentSchemaVersion = 2
+
+// Unknown key errors from strict YAML parsing
+var unknownKeyRegex = regexp.MustCompile(`^line (\d+): field (\S+) not found`)
+
+
+// A migration step which upgrades user settings from one schema version to the next
+type Migration struct {
+    FromVersion int
+    Description string
+    Migrate func(userConfig *RocketPoolConfig) error
+}
+
+
+// Registered migration steps, in version order
+// Each release which renames a parameter or drops a client option adds a step and increments CurrentSchemaVersion
+var migrations = []Migration{
+    {
+        FromVersion: 0,
+        Description: "Add the settings schema version",
+        Migrate: func(userConfig *RocketPoolConfig) error { return nil },
+    },
+    {
+        FromVersion: 1,
+        Description: "Remove the obsolete Geth Ethstats parameters",
+        Migrate: RemoveParams(getEth1Chain, "ETHSTATS_LABEL", "ETHSTATS_LOGIN"),
+    },
+}
+
+
+// Get the Eth 1.0 chain settings to migrate
+func getEth1Chain(userConfig *RocketPoolConfig) *Chain { return &userConfig.Chains.Eth1 }
+
+
+// Check whether user settings need to be migrated to the current schema version
+func NeedsMigration(userConfig RocketPoolConfig) bool {
+    return userConfig.Version < CurrentSchemaVersion
+}
+
+
+// Migrate user settings to the current schema version, returning the descriptions of the steps applied
+func Migrate(userConfig *RocketPoolConfig) ([]string, error) {
+
+    // Check the settings version
+    if userConfig.Version > CurrentSchemaVersion {
+        return nil, fmt.Errorf("The settings use schema version %d, but this version of the Smartnode only supports up to version %d; please upgrade the Smartnode", userConfig.Version, CurrentSchemaVersion)
+    }
+
+    // Apply migrations
+    applied := []string{}
+    for _, migration := range migrations {
+        if migration.FromVersion != userConfig.Version {
+            continue
+        }
+        if err := migration.Migrate(userConfig); err != nil {
+            return nil, fmt.Errorf("Could not migrate settings from schema version %d: %w", migration.FromVersion, err)
+        }
+        userConfig.Version = migration.FromVersion + 1
+        applied = append(applied, fmt.Sprintf("v%d -> v%d: %s", migration.FromVersion, userConfig.Version, migration.Description))
+    }
+    if userConfig.Version != CurrentSchemaVersion {
+        return nil, fmt.Errorf("There is no migration for settings schema version %d", userConfig.Version)
+    }
+
+    // Return
+    return applied, nil
+
+}
+
+
+// Create a migration step function which renames a client parameter's environment variable in a chain's settings
+func RenameParam(getChain func(*RocketPoolConfig) *Chain, oldEnv, newEnv string) func(*RocketPoolConfig) error {
+    return func(userConfig *RocketPoolConfig) error {
+        chain := getChain(userConfig)
+        for pi := range chain.Client.Params {
+            if chain.Client.Params[pi].Env == oldEnv {
+                chain.Client.Params[pi].Env = newEnv
+            }
+        }
+        return nil
+    }
+}
+
+
+// Create a migration step function which removes dropped client parameters from a chain's settings
+func RemoveParams(getChain func(*RocketPoolConfig) *Chain, envs ...string) func(*RocketPoolConfig) error {
+    return func(userConfig *RocketPoolConfig) error {
+        chain := getChain(userConfig)
+        params := []UserParam{}
+        for _, param := range chain.Client.Params {
+            removed := false
+            for _, env := range envs {
+                if param.Env == env {
+                    removed = true
+                    break
+                }
+            }
+            if !removed {
+                params = append(params, param)
+            }
+        }
+        chain.Client.Params = params
+        return nil
+    }
+}
+
+
+// Create a migration step function which replaces a dropped client option in a chain's settings
+func ReplaceClient(getChain func(*RocketPoolConfig) *Chain, oldId, newId string) func(*RocketPoolConfig) error {
+    return func(userConfig *RocketPoolConfig) error {
+        chain := getChain(userConfig)
+        if chain.Client.Selected == oldId {
+            chain.Client.Selected = newId
+        }
+        return nil
+    }
+}
+
+
+// Validate user settings against the global config, returning any problems found
+// Reports unknown keys, unknown or obsolete clients and parameters, and settings which need to be migrated
+func ValidateUserConfig(globalConfig RocketPoolConfig, userConfigBytes []byte) ([]string, error) {
+
+    // Check for unknown keys
+    problems := []string{}
+    var userConfig RocketPoolConfig
+    if err := yaml.UnmarshalStrict(userConfigBytes, &userConfig); err != nil {
+        typeErr, ok := err.(*yaml.TypeError)
+        if !ok {
+            return nil, fmt.Errorf("Could not parse settings: %w", err)
+        }
+        for _, keyErr := range typeErr.Errors {
+            if match := unknownKeyRegex.FindStringSubmatch(keyErr); match != nil {
+                problems = append(problems, fmt.Sprintf("Unknown key: %s (line %s)", match[2], match[1]))
+            } else {
+                problems = append(problems, keyErr)
+            }
+        }
+        if err := yaml.Unmarshal(userConfigBytes, &userConfig); err != nil {
+            return nil, fmt.Errorf("Could not parse settings: %w", err)
+        }
+    }
+
+    // Check the schema version
+    if userConfig.Version > CurrentSchemaVersion {
+        problems = append(problems, fmt.Sprintf("The settings use schema version %d, which is newer than the supported version %d", userConfig.Version, CurrentSchemaVersion))
+    } else if NeedsMigration(userConfig) {
+        problems = append(problems, fmt.Sprintf("The settings use schema version %d and need to be migrated to version %d", userConfig.Version, CurrentSchemaVersion))
+    }
+
+    // Check chain clients & params
+    problems = append(problems, validateUserChain(globalConfig.Chains.Eth1, userConfig.Chains.Eth1, "eth1")...)
+    problems = append(problems, validateUserChain(globalConfig.Chains.Eth2, userConfig.Chains.Eth2, "eth2")...)
+
+    // Check metrics params
+    for _, userParam := range userConfig.Metrics.Settings {
+        if globalConfig.Metrics.GetParamByEnvName(userParam.Env) == nil {
+            problems = append(problems, fmt.Sprintf("Obsolete metrics parameter: %s", userParam.Env))
+        }
+    }
+
+    // Return
+    return problems, nil
+
+}
+
+
+// Validate a chain's user settings against its global client options
+func validateUserChain(globalChain, userChain Chain, chainName string) []string {
+
+    // Check the selected client
+    problems := []string{}
+    if userChain.Client.Selected == "" {
+        problems = append(problems, fmt.Sprintf("No %s client is selected", chainName))
+    } else if globalChain.GetClientById(userChain.Client.Selected) == nil {
+        clientIds := make([]string, len(globalChain.Client.Options))
+        for oi, option := range globalChain.Client.Options {
+            clientIds[oi] = option.ID
+        }
+        problems = append(problems, fmt.Sprintf("Obsolete %s client: %s (options are %s)", chainName, userChain.Client.Selected, strings.Join(clientIds, ", ")))
+    }
+
+    // Check params
+    for _, userParam := range userChain.Client.Params {
+        known := false
+        for _, option := range globalChain.Client.Options {
+            if option.GetParamByEnvName(userParam.Env) != nil {
+                known = true
+                break
+            }
+        }
+        if !known {
+            problems = append(problems, fmt.Sprintf("Obsolete %s parameter: %s", chainName, userParam.Env))
+        }
+    }
+
+    // Return
+    return problems
+
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+
+func TestMigrate(t *testing.T) {
+    tests := []struct {
+        name string
+        settings string
+        expectedParams []UserParam
+        expectedSteps int
+        errorContains string
+    }{
+        {
+            name: "unversioned settings",
+            settings: "chains:\n  eth1:\n    client:\n      selected: geth\n      params:\n      - env: ETHSTATS_LABEL\n        value: node\n      - env: ETH1_MAX_PEERS\n        value: \"50\"\n",
+            expectedParams: []UserParam{{Env: "ETH1_MAX_PEERS", Value: "50"}},
+            expectedSteps: 2,
+        },
+        {
+            name: "removes Ethstats params",
+            settings: "version: 1\nchains:\n  eth1:\n    client:\n      selected: geth\n      params:\n      - env: ETHSTATS_LABEL\n        value: node\n      - env: ETHSTATS_LOGIN\n        value: secret@stats\n      - env: ETH1_P2P_PORT\n        value: \"30303\"\n",
+            expectedParams: []UserParam{{Env: "ETH1_P2P_PORT", Value: "30303"}},
+            expectedSteps: 1,
+        },
+        {
+            name: "no params to remove",
+            settings: "version: 1\nchains:\n  eth1:\n    client:\n      selected: infura\n      params:\n      - env: INFURA_PROJECT_ID\n        value: abc\n",
+            expectedParams: []UserParam{{Env: "INFURA_PROJECT_ID", Value: "abc"}},
+            expectedSteps: 1,
+        },
+        {
+            name: "current settings",
+            settings: fmt.Sprintf("version: %d\nchains:\n  eth1:\n    client:\n      selected: geth\n      params:\n      - env: ETHSTATS_LABEL\n        value: node\n", CurrentSchemaVersion),
+            expectedParams: []UserParam{{Env: "ETHSTATS_LABEL", Value: "node"}},
+        },
+        {
+            name: "newer settings",
+            settings: fmt.Sprintf("version: %d\n", CurrentSchemaVersion + 1),
+            errorContains: "please upgrade the Smartnode",
+        },
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            userConfig, err := Parse([]byte(test.settings))
+            if err != nil {
+                t.Fatal(err)
+            }
+            steps, err := Migrate(&userConfig)
+            if test.errorContains != "" {
+                if err == nil || !strings.Contains(err.Error(), test.errorContains) {
+                    t.Fatalf("expected an error containing '%s', got %v", test.errorContains, err)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            if len(steps) != test.expectedSteps {
+                t.Errorf("applied %d steps (%v), expected %d", len(steps), steps, test.expectedSteps)
+            }
+            if userConfig.Version != CurrentSchemaVersion {
+                t.Errorf("migrated to version %d, expected %d", userConfig.Version, CurrentSchemaVersion)
+            }
+            if NeedsMigration(userConfig) {
+                t.Errorf("migrated settings still need migration")
+            }
+            if !reflect.DeepEqual(userConfig.Chains.Eth1.Client.Params, test.expectedParams) {
+                t.Errorf("params are %v, expected %v", userConfig.Chains.Eth1.Client.Params, test.expectedParams)
+            }
+        })
+    }
+}
+
+
+func TestRenameParam(t *testing.T) {
+    var userConfig RocketPoolConfig
+    userConfig.Chains.Eth1.Client.Params = []UserParam{{Env: "OLD_PORT", Value: "1"}, {Env: "OTHER", Value: "2"}}
+    if err := RenameParam(getEth1Chain, "OLD_PORT", "NEW_PORT")(&userConfig); err != nil {
+        t.Fatal(err)
+    }
+    if expected := []UserParam{{Env: "NEW_PORT", Value: "1"}, {Env: "OTHER", Value: "2"}}; !reflect.DeepEqual(userConfig.Chains.Eth1.Client.Params, expected) {
+        t.Errorf("params are %v, expected %v", userConfig.Chains.Eth1.Client.Params, expected)
+    }
+}
+
+
+func TestReplaceClient(t *testing.T) {
+    var userConfig RocketPoolConfig
+    replace := ReplaceClient(getEth1Chain, "old", "new")
+
+    // Other clients are kept
+    userConfig.Chains.Eth1.Client.Selected = "geth"
+    if err := replace(&userConfig); err != nil {
+        t.Fatal(err)
+    }
+    if userConfig.Chains.Eth1.Client.Selected != "geth" {
+        t.Errorf("selected client is %s, expected geth", userConfig.Chains.Eth1.Client.Selected)
+    }
+
+    // The replaced client is switched
+    userConfig.Chains.Eth1.Client.Selected = "old"
+    if err := replace(&userConfig); err != nil {
+        t.Fatal(err)
+    }
+    if userConfig.Chains.Eth1.Client.Selected != "new" {
+        t.Errorf("selected client is %s, expected new", userConfig.Chains.Eth1.Client.Selected)
+    }
+
+}
+
+
+func TestRemoveParams(t *testing.T) {
+    var userConfig RocketPoolConfig
+    userConfig.Chains.Eth1.Client.Params = []UserParam{{Env: "A"}, {Env: "C"}, {Env: "B"}}
+    if err := RemoveParams(getEth1Chain, "A", "B")(&userConfig); err != nil {
+        t.Fatal(err)
+    }
+    if expected := []UserParam{{Env: "C"}}; !reflect.DeepEqual(userConfig.Chains.Eth1.Client.Params, expected) {
+        t.Errorf("params are %v, expected %v", userConfig.Chains.Eth1.Client.Params, expected)
+    }
+}
+
+
+func TestValidateUserConfig(t *testing.T) {
+    var globalConfig RocketPoolConfig
+    globalConfig.Chains.Eth1.Client.Options = []ClientOption{{ID: "geth", Params: []ClientParam{{Env: "ETH1_MAX_PEERS"}}}}
+    globalConfig.Chains.Eth2.Client.Options = []ClientOption{{ID: "lighthouse"}}
+    tests := []struct {
+        name string
+        settings string
+        expected []string
+    }{
+        {
+            name: "valid settings",
+            settings: "version: 2\nchains:\n  eth1:\n    client:\n      selected: geth\n  eth2:\n    client:\n      selected: lighthouse\n",
+            expected: []string{},
+        },
+        {
+            name: "unknown key and old version",
+            settings: "version: 1\nsmartnode:\n  oldSetting: true\nchains:\n  eth1:\n    client:\n      selected: geth\n      params:\n      - env: ETHSTATS_LABEL\n        value: node\n  eth2:\n    client:\n      selected: lighthouse\n",
+            expected: []string{
+                "Unknown key: oldSetting (line 3)",
+                "The settings use schema version 1 and need to be migrated to version 2",
+                "Obsolete eth1 parameter: ETHSTATS_LABEL",
+            },
+        },
+        {
+            name: "obsolete client",
+            settings: "version: 2\nchains:\n  eth1:\n    client:\n      selected: geth\n  eth2:\n    client:\n      selected: infura\n",
+            expected: []string{"Obsolete eth2 client: infura (options are lighthouse)"},
+        },
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            problems, err := ValidateUserConfig(globalConfig, []byte(test.settings))
+            if err != nil {
+                t.Fatal(err)
+            }
+            if !reflect.DeepEqual(problems, test.expected) {
+                t.Errorf("problems are %q, expected %q", problems, test.expected)
+            }
+        })
+    }
+}
@@ -0,0 +1,86 @@
+package deposits
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/rocketpool-go/types"
+
+	"github.com/rocket-pool/smartnode/shared/services/beacon"
+)
+
+// Conflict sources
+const (
+    SourceBeacon = "beacon"
+    SourceDepositContract = "depositContract"
+)
+
+
+// A prior deposit for a validator which locks its balance to withdrawal credentials other than the expected ones
+type Conflict struct {
+    Pubkey types.ValidatorPubkey                    `json:"pubkey"`
+    Source string                                   `json:"source"`
+    ExpectedWithdrawalCredentials common.Hash       `json:"expectedWithdrawalCredentials"`
+    WithdrawalCredentials common.Hash               `json:"withdrawalCredentials"`
+    TxHash *common.Hash                             `json:"txHash,omitempty"`
+    BlockNumber uint64                              `json:"blockNumber,omitempty"`
+}
+
+
+// Check whether a validator already has a deposit with withdrawal credentials other than the expected ones
+// The beacon chain is checked first, then the first valid deposit in the deposit contract index
+// Returns nil if there is no conflicting deposit
+func (idx *Indexer) CheckForConflictingDeposit(bc beacon.Client, pubkey types.ValidatorPubkey, expectedWithdrawalCredentials common.Hash) (*Conflict, error) {
+
+    // Check the validator on the beacon chain
+    status, err := bc.GetValidatorStatus(pubkey, nil)
+    if err != nil {
+        return nil, fmt.Errorf("Could not get the beacon chain status of validator %s: %w", pubkey.Hex(), err)
+    }
+    if status.Exists && status.WithdrawalCredentials != expectedWithdrawalCredentials {
+        return &Conflict{
+            Pubkey: pubkey,
+            Source: SourceBeacon,
+            ExpectedWithdrawalCredentials: expectedWithdrawalCredentials,
+            WithdrawalCredentials: status.WithdrawalCredentials,
+        }, nil
+    }
+
+    // Get the validator's deposits
+    depositMap, err := idx.UpdateAndGetDeposits(map[types.ValidatorPubkey]bool{pubkey: true})
+    if err != nil {
+        return nil, err
+    }
+    depositDomain, err := GetDepositDomain(bc)
+    if err != nil {
+        return nil, fmt.Errorf("Could not get the beacon deposit domain: %w", err)
+    }
+
+    // Check the first valid deposit
+    validatorDeposits := depositMap[pubkey]
+    di := GetFirstValidDeposit(validatorDeposits, depositDomain)
+    if di < 0 || validatorDeposits[di].WithdrawalCredentials == expectedWithdrawalCredentials {
+        return nil, nil
+    }
+    deposit := validatorDeposits[di]
+    return &Conflict{
+        Pubkey: pubkey,
+        Source: SourceDepositContract,
+        ExpectedWithdrawalCredentials: expectedWithdrawalCredentials,
+        WithdrawalCredentials: deposit.WithdrawalCredentials,
+        TxHash: &deposit.TxHash,
+        BlockNumber: deposit.BlockNumber,
+    }, nil
+
+}
+
+
+// Get a description of the conflicting deposit
+func (c Conflict) String() string {
+    switch c.Source {
+        case SourceBeacon:
+            return fmt.Sprintf("Validator %s already exists on the beacon chain with withdrawal credentials %s instead of %s", c.Pubkey.Hex(), c.WithdrawalCredentials.Hex(), c.ExpectedWithdrawalCredentials.Hex())
+        default:
+            return fmt.Sprintf("The first valid deposit for validator %s (transaction %s in block %d) has withdrawal credentials %s instead of %s", c.Pubkey.Hex(), c.TxHash.Hex(), c.BlockNumber, c.WithdrawalCredentials.Hex(), c.ExpectedWithdrawalCredentials.Hex())
+    }
+}
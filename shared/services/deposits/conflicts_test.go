@@ -0,0 +1,144 @@
+package deposits
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/rocketpool-go/types"
+	eth2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/rocket-pool/smartnode/shared/services/beacon"
+	"github.com/rocket-pool/smartnode/shared/utils/validator"
+)
+
+// Test withdrawal credentials
+var (
+    testMinipoolCredentials = common.HexToHash("0x010000000000000000000000aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+    testAttackerCredentials = common.HexToHash("0x010000000000000000000000bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+)
+
+
+// A fake beacon client serving the eth2 config and a single validator's status
+type fakeBeaconClient struct {
+    beacon.Client
+    eth2Config beacon.Eth2Config
+    status beacon.ValidatorStatus
+}
+func (bc *fakeBeaconClient) GetEth2Config() (beacon.Eth2Config, error) {
+    return bc.eth2Config, nil
+}
+func (bc *fakeBeaconClient) GetValidatorStatus(pubkey types.ValidatorPubkey, opts *beacon.ValidatorStatusOptions) (beacon.ValidatorStatus, error) {
+    if bc.status.Pubkey != pubkey {
+        return beacon.ValidatorStatus{}, nil
+    }
+    return bc.status, nil
+}
+
+
+// Get a signed deposit for a validator key
+func getSignedDeposit(t *testing.T, key *eth2types.BLSPrivateKey, withdrawalCredentials common.Hash, eth2Config beacon.Eth2Config, block uint64) fakeDeposit {
+    depositData, _, err := validator.GetDepositData(key, withdrawalCredentials, eth2Config)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return fakeDeposit{
+        block: block,
+        pubkey: types.BytesToValidatorPubkey(depositData.PublicKey),
+        withdrawalCredentials: withdrawalCredentials,
+        amount: depositData.Amount,
+        signature: types.BytesToValidatorSignature(depositData.Signature),
+    }
+}
+
+
+func TestCheckForConflictingDeposit(t *testing.T) {
+    if err := eth2types.InitBLS(); err != nil {
+        t.Fatal(err)
+    }
+    key, err := eth2types.GenerateBLSPrivateKey()
+    if err != nil {
+        t.Fatal(err)
+    }
+    pubkey := types.BytesToValidatorPubkey(key.PublicKey().Marshal())
+    eth2Config := beacon.Eth2Config{GenesisForkVersion: []byte{0x00, 0x00, 0x10, 0x20}}
+
+    // Deposits; a deposit with an invalid signature is ignored by the beacon chain
+    minipoolDeposit := getSignedDeposit(t, key, testMinipoolCredentials, eth2Config, 150)
+    attackerDeposit := getSignedDeposit(t, key, testAttackerCredentials, eth2Config, 140)
+    invalidDeposit := attackerDeposit
+    invalidDeposit.block = 130
+    invalidDeposit.withdrawalCredentials = testMinipoolCredentials
+
+    tests := []struct {
+        name string
+        status beacon.ValidatorStatus
+        deposits []fakeDeposit
+        source string
+        withdrawalCredentials common.Hash
+        blockNumber uint64
+    }{
+        {
+            name: "no deposits",
+        },
+        {
+            name: "minipool deposit",
+            deposits: []fakeDeposit{minipoolDeposit},
+        },
+        {
+            name: "invalid conflicting deposit",
+            deposits: []fakeDeposit{{block: 140, pubkey: pubkey, withdrawalCredentials: testAttackerCredentials, amount: 1000000000}, minipoolDeposit},
+        },
+        {
+            name: "existing validator",
+            status: beacon.ValidatorStatus{Pubkey: pubkey, Exists: true, WithdrawalCredentials: testMinipoolCredentials},
+            deposits: []fakeDeposit{minipoolDeposit},
+        },
+        {
+            name: "beacon conflict",
+            status: beacon.ValidatorStatus{Pubkey: pubkey, Exists: true, WithdrawalCredentials: testAttackerCredentials},
+            deposits: []fakeDeposit{minipoolDeposit},
+            source: SourceBeacon,
+            withdrawalCredentials: testAttackerCredentials,
+        },
+        {
+            name: "deposit contract conflict",
+            deposits: []fakeDeposit{invalidDeposit, attackerDeposit, minipoolDeposit},
+            source: SourceDepositContract,
+            withdrawalCredentials: testAttackerCredentials,
+            blockNumber: 140,
+        },
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            chain := &fakeChain{head: 200, deposits: test.deposits}
+            idx, cleanup := newTestIndexer(t, chain, 100, 0)
+            defer cleanup()
+            bc := &fakeBeaconClient{eth2Config: eth2Config, status: test.status}
+
+            // Check
+            conflict, err := idx.CheckForConflictingDeposit(bc, pubkey, testMinipoolCredentials)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if test.source == "" {
+                if conflict != nil {
+                    t.Fatalf("found conflict: %s", conflict.String())
+                }
+                return
+            }
+            if conflict == nil {
+                t.Fatal("no conflict found")
+            }
+            if conflict.Source != test.source || conflict.Pubkey != pubkey || conflict.WithdrawalCredentials != test.withdrawalCredentials || conflict.ExpectedWithdrawalCredentials != testMinipoolCredentials {
+                t.Errorf("conflict is %+v", conflict)
+            }
+            if conflict.BlockNumber != test.blockNumber || (test.source == SourceDepositContract) != (conflict.TxHash != nil) {
+                t.Errorf("conflict is in block %d with tx %v, expected block %d", conflict.BlockNumber, conflict.TxHash, test.blockNumber)
+            }
+            if !strings.Contains(conflict.String(), testAttackerCredentials.Hex()) {
+                t.Errorf("conflict description '%s' does not include the conflicting credentials", conflict.String())
+            }
+        })
+    }
+}
@@ -0,0 +1,506 @@
+package deposits
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb/leveldb"
+	"github.com/rocket-pool/rocketpool-go/rocketpool"
+	"github.com/rocket-pool/rocketpool-go/types"
+	"github.com/rocket-pool/rocketpool-go/utils"
+
+	"github.com/rocket-pool/smartnode/shared/services/contracts"
+)
+
+// Settings
+const (
+    DefaultStartOffset = 100000
+    MaxCheckpoints = 128
+    DatabaseCache = 16
+    DatabaseHandles = 16
+    OpenRetries = 20
+    OpenRetryInterval = 500 * time.Millisecond
+    ReorgRetries = 3
+)
+
+// Database key prefixes
+var (
+    depositPrefix = []byte("d")
+    blockPrefix = []byte("b")
+    checkpointPrefix = []byte("c")
+    lastBlockKey = []byte("lastBlock")
+)
+
+
+// Incremental index of the beacon deposit contract's deposit events, stored by validator pubkey in a local database
+// The database is opened for each operation so that it can be shared by the daemons and the API
+type Indexer struct {
+    rp *rocketpool.RocketPool
+    path string
+    startOffset uint64
+    intervalSize *big.Int
+    lock sync.Mutex
+}
+
+
+// Create new deposit indexer
+func NewIndexer(rp *rocketpool.RocketPool, path string, intervalSize *big.Int) *Indexer {
+    return &Indexer{
+        rp: rp,
+        path: path,
+        startOffset: DefaultStartOffset,
+        intervalSize: intervalSize,
+    }
+}
+
+
+// Index any deposit events since the last update, rolling back deposits from blocks that were reorged out
+// Each interval is committed separately and the database is only held open while writing, so that other processes can use it during long scans
+// Returns the latest indexed block
+func (idx *Indexer) Update() (uint64, error) {
+    idx.lock.Lock()
+    defer idx.lock.Unlock()
+
+    // Get the chain head
+    head, err := idx.rp.Client.HeaderByNumber(context.Background(), nil)
+    if err != nil {
+        return 0, fmt.Errorf("Could not get the latest block header: %w", err)
+    }
+    headBlock := head.Number.Uint64()
+
+    // Roll back any reorged blocks since the last update
+    lastBlock, indexed, err := idx.rollbackReorgs()
+    if err != nil {
+        return 0, err
+    }
+
+    // Get the deposit contract binding
+    depositAddress, err := idx.rp.GetAddress("casperDeposit")
+    if err != nil {
+        return 0, err
+    }
+    depositContract, err := contracts.NewBeaconDeposit(*depositAddress, idx.rp.Client)
+    if err != nil {
+        return 0, err
+    }
+
+    // Get the interval size
+    var interval uint64
+    if idx.intervalSize != nil {
+        interval = idx.intervalSize.Uint64()
+    }
+
+    // Index deposit events in intervals, checkpointing the hash of the last block of each interval
+    for {
+        var start uint64
+        if indexed {
+            start = lastBlock + 1
+        } else if headBlock > idx.startOffset {
+            start = headBlock - idx.startOffset
+        }
+        if start > headBlock {
+            break
+        }
+        end := headBlock
+        if interval > 0 && start + interval - 1 < headBlock {
+            end = start + interval - 1
+        }
+        lastBlock, indexed, err = idx.indexInterval(depositContract, lastBlock, indexed, start, end)
+        if err != nil {
+            return 0, err
+        }
+    }
+
+    // Return
+    return lastBlock, nil
+
+}
+
+
+// Get the indexed deposits for a set of validator pubkeys, sorted by block and log index
+func (idx *Indexer) GetDeposits(pubkeys map[types.ValidatorPubkey]bool) (map[types.ValidatorPubkey][]utils.DepositData, error) {
+
+    // Open the database read-only
+    idx.lock.Lock()
+    defer idx.lock.Unlock()
+    db, err := idx.open(true)
+    if err != nil {
+        return nil, err
+    }
+    defer db.Close()
+
+    // Get deposits
+    depositMap := make(map[types.ValidatorPubkey][]utils.DepositData, len(pubkeys))
+    for pubkey := range pubkeys {
+        it := db.NewIterator(append(append([]byte{}, depositPrefix...), pubkey.Bytes()...), nil)
+        for it.Next() {
+            var deposit utils.DepositData
+            if err := json.Unmarshal(it.Value(), &deposit); err != nil {
+                it.Release()
+                return nil, fmt.Errorf("Could not decode indexed deposit for validator %s: %w", pubkey.Hex(), err)
+            }
+            depositMap[pubkey] = append(depositMap[pubkey], deposit)
+        }
+        err := it.Error()
+        it.Release()
+        if err != nil {
+            return nil, fmt.Errorf("Could not read indexed deposits for validator %s: %w", pubkey.Hex(), err)
+        }
+    }
+
+    // Return
+    return depositMap, nil
+
+}
+
+
+// Update the index and get the deposits for a set of validator pubkeys
+func (idx *Indexer) UpdateAndGetDeposits(pubkeys map[types.ValidatorPubkey]bool) (map[types.ValidatorPubkey][]utils.DepositData, error) {
+    if _, err := idx.Update(); err != nil {
+        return nil, fmt.Errorf("Could not update the deposit index: %w", err)
+    }
+    return idx.GetDeposits(pubkeys)
+}
+
+
+// Open the database, retrying while it is locked by another process
+// Read-only handles share the lock with each other, but not with a writer
+func (idx *Indexer) open(readOnly bool) (*leveldb.Database, error) {
+    var db *leveldb.Database
+    var err error
+    for attempt := 0; attempt < OpenRetries; attempt++ {
+        db, err = leveldb.New(idx.path, DatabaseCache, DatabaseHandles, "", readOnly)
+        if err == nil {
+            return db, nil
+        }
+        time.Sleep(OpenRetryInterval)
+    }
+    return nil, fmt.Errorf("Could not open the deposit index at %s: %w", idx.path, err)
+}
+
+
+// Roll back the index to the latest checkpoint still on the canonical chain
+// Returns the latest indexed block and whether any blocks are indexed
+func (idx *Indexer) rollbackReorgs() (uint64, bool, error) {
+
+    // Open the database
+    db, err := idx.open(false)
+    if err != nil {
+        return 0, false, err
+    }
+    defer db.Close()
+
+    // Get the latest indexed block
+    lastBlock, indexed, err := getLastBlock(db)
+    if err != nil || !indexed {
+        return 0, false, err
+    }
+
+    // Roll back to the common ancestor
+    ancestor, err := idx.findCommonAncestor(db, lastBlock)
+    if err != nil {
+        return 0, false, err
+    }
+    if ancestor < lastBlock {
+        if err := rollback(db, ancestor); err != nil {
+            return 0, false, err
+        }
+    }
+    return ancestor, (ancestor > 0), nil
+
+}
+
+
+// Index the deposit events in a block range, following on from the latest indexed block
+// The events are retrieved before the database is opened; if another process has updated the index in the meantime, they are discarded
+// Returns the latest indexed block and whether any blocks are indexed
+func (idx *Indexer) indexInterval(depositContract *contracts.BeaconDeposit, lastBlock uint64, indexed bool, start, end uint64) (uint64, bool, error) {
+
+    // Get the deposit events, retrying if the last block in the range is reorged while they are retrieved
+    var batch *leveldbBatch
+    for attempt := 0; batch == nil; attempt++ {
+        if attempt == ReorgRetries {
+            return 0, false, fmt.Errorf("Block %d was reorged while getting deposit events %d times in a row", end, ReorgRetries)
+        }
+        var err error
+        batch, err = idx.getIntervalBatch(depositContract, start, end)
+        if err != nil {
+            return 0, false, err
+        }
+    }
+
+    // Open the database
+    db, err := idx.open(false)
+    if err != nil {
+        return 0, false, err
+    }
+    defer db.Close()
+
+    // Check that the index has not been updated by another process
+    currentBlock, currentIndexed, err := getLastBlock(db)
+    if err != nil {
+        return 0, false, err
+    }
+    if currentBlock != lastBlock || currentIndexed != indexed {
+        return currentBlock, currentIndexed, nil
+    }
+
+    // Commit
+    if err := batch.write(db); err != nil {
+        return 0, false, fmt.Errorf("Could not write to the deposit index: %w", err)
+    }
+    if err := pruneCheckpoints(db); err != nil {
+        return 0, false, err
+    }
+    return end, true, nil
+
+}
+
+
+// Get the database writes for the deposit events in a block range, checkpointing the hash of the last block in the range
+// The last block's header is retrieved before and after the events; returns nil if its hash changed, as the events may be from a reorged chain
+func (idx *Indexer) getIntervalBatch(depositContract *contracts.BeaconDeposit, start, end uint64) (*leveldbBatch, error) {
+
+    // Get the hash of the last block in the range
+    endHeader, err := idx.rp.Client.HeaderByNumber(context.Background(), new(big.Int).SetUint64(end))
+    if err != nil {
+        return nil, fmt.Errorf("Could not get the header for block %d: %w", end, err)
+    }
+
+    // Get the deposit events
+    events, err := depositContract.FilterDepositEvent(&bind.FilterOpts{Start: start, End: &end, Context: context.Background()})
+    if err != nil {
+        return nil, fmt.Errorf("Could not get deposit events for blocks %d - %d: %w", start, end, err)
+    }
+    defer events.Close()
+
+    // Add deposits
+    batch := new(leveldbBatch)
+    for events.Next() {
+        event := events.Event
+        if event.Raw.Removed {
+            continue
+        }
+
+        // Convert the deposit amount from little-endian binary to a uint64
+        var amount uint64
+        if err := binary.Read(bytes.NewReader(event.Amount), binary.LittleEndian, &amount); err != nil {
+            return nil, fmt.Errorf("Could not decode the amount of deposit %s: %w", event.Raw.TxHash.Hex(), err)
+        }
+
+        // Encode deposit
+        deposit := utils.DepositData{
+            Pubkey: types.BytesToValidatorPubkey(event.Pubkey),
+            WithdrawalCredentials: common.BytesToHash(event.WithdrawalCredentials),
+            Amount: amount,
+            Signature: types.BytesToValidatorSignature(event.Signature),
+            TxHash: event.Raw.TxHash,
+            BlockNumber: event.Raw.BlockNumber,
+            TxIndex: event.Raw.TxIndex,
+        }
+        depositBytes, err := json.Marshal(deposit)
+        if err != nil {
+            return nil, fmt.Errorf("Could not encode deposit %s: %w", event.Raw.TxHash.Hex(), err)
+        }
+
+        // Store the deposit under its pubkey and position, and index its position for rollbacks
+        position := getPositionKey(event.Raw.BlockNumber, event.Raw.Index)
+        batch.put(concat(depositPrefix, deposit.Pubkey.Bytes(), position), depositBytes)
+        batch.put(concat(blockPrefix, position), deposit.Pubkey.Bytes())
+
+    }
+    if err := events.Error(); err != nil {
+        return nil, fmt.Errorf("Could not get deposit events for blocks %d - %d: %w", start, end, err)
+    }
+
+    // Check that the last block was not reorged while getting the events
+    currentEndHeader, err := idx.rp.Client.HeaderByNumber(context.Background(), new(big.Int).SetUint64(end))
+    if err != nil {
+        return nil, fmt.Errorf("Could not get the header for block %d: %w", end, err)
+    }
+    if currentEndHeader.Hash() != endHeader.Hash() {
+        return nil, nil
+    }
+
+    // Checkpoint the block
+    batch.put(concat(checkpointPrefix, getBlockKey(end)), endHeader.Hash().Bytes())
+    batch.put(lastBlockKey, getBlockKey(end))
+    return batch, nil
+
+}
+
+
+// A set of database writes collected before the database is opened
+type leveldbBatch struct {
+    keys [][]byte
+    values [][]byte
+}
+func (b *leveldbBatch) put(key, value []byte) {
+    b.keys = append(b.keys, key)
+    b.values = append(b.values, value)
+}
+func (b *leveldbBatch) write(db *leveldb.Database) error {
+    batch := db.NewBatch()
+    for ki, key := range b.keys {
+        if err := batch.Put(key, b.values[ki]); err != nil {
+            return err
+        }
+    }
+    return batch.Write()
+}
+
+
+// Find the latest checkpointed block at or below a block which is still on the canonical chain
+// Returns zero if no checkpoints match, so that the index is rebuilt
+func (idx *Indexer) findCommonAncestor(db *leveldb.Database, lastBlock uint64) (uint64, error) {
+
+    // Get checkpoints, latest first
+    checkpoints, hashes, err := getCheckpoints(db)
+    if err != nil {
+        return 0, err
+    }
+
+    // Compare against the canonical chain
+    for ci := len(checkpoints) - 1; ci >= 0; ci-- {
+        if checkpoints[ci] > lastBlock {
+            continue
+        }
+        header, err := idx.rp.Client.HeaderByNumber(context.Background(), new(big.Int).SetUint64(checkpoints[ci]))
+        if err != nil {
+            return 0, fmt.Errorf("Could not get the header for block %d: %w", checkpoints[ci], err)
+        }
+        if header.Hash() == hashes[ci] {
+            return checkpoints[ci], nil
+        }
+    }
+
+    // No checkpoints match; rebuild the index from the start offset
+    return 0, nil
+
+}
+
+
+// Remove all deposits and checkpoints above a block
+func rollback(db *leveldb.Database, ancestor uint64) error {
+    batch := db.NewBatch()
+    it := db.NewIterator(blockPrefix, getBlockKey(ancestor + 1))
+    for it.Next() {
+        position := it.Key()[len(blockPrefix):]
+        if err := batch.Delete(concat(depositPrefix, it.Value(), position)); err != nil {
+            it.Release()
+            return err
+        }
+        if err := batch.Delete(append([]byte{}, it.Key()...)); err != nil {
+            it.Release()
+            return err
+        }
+    }
+    it.Release()
+    if err := it.Error(); err != nil {
+        return fmt.Errorf("Could not read the deposit index: %w", err)
+    }
+    checkpointIt := db.NewIterator(checkpointPrefix, getBlockKey(ancestor + 1))
+    for checkpointIt.Next() {
+        if err := batch.Delete(append([]byte{}, checkpointIt.Key()...)); err != nil {
+            checkpointIt.Release()
+            return err
+        }
+    }
+    checkpointIt.Release()
+    if ancestor == 0 {
+        if err := batch.Delete(lastBlockKey); err != nil {
+            return err
+        }
+    } else if err := batch.Put(lastBlockKey, getBlockKey(ancestor)); err != nil {
+        return err
+    }
+    if err := batch.Write(); err != nil {
+        return fmt.Errorf("Could not roll back the deposit index to block %d: %w", ancestor, err)
+    }
+    return nil
+}
+
+
+// Remove the oldest checkpoints beyond the checkpoint limit
+func pruneCheckpoints(db *leveldb.Database) error {
+    checkpoints, _, err := getCheckpoints(db)
+    if err != nil {
+        return err
+    }
+    if len(checkpoints) <= MaxCheckpoints {
+        return nil
+    }
+    batch := db.NewBatch()
+    for _, block := range checkpoints[:len(checkpoints) - MaxCheckpoints] {
+        if err := batch.Delete(concat(checkpointPrefix, getBlockKey(block))); err != nil {
+            return err
+        }
+    }
+    return batch.Write()
+}
+
+
+// Get the checkpointed blocks and their hashes, earliest first
+func getCheckpoints(db *leveldb.Database) ([]uint64, []common.Hash, error) {
+    checkpoints := []uint64{}
+    hashes := []common.Hash{}
+    it := db.NewIterator(checkpointPrefix, nil)
+    defer it.Release()
+    for it.Next() {
+        checkpoints = append(checkpoints, binary.BigEndian.Uint64(it.Key()[len(checkpointPrefix):]))
+        hashes = append(hashes, common.BytesToHash(it.Value()))
+    }
+    if err := it.Error(); err != nil {
+        return nil, nil, fmt.Errorf("Could not read deposit index checkpoints: %w", err)
+    }
+    return checkpoints, hashes, nil
+}
+
+
+// Get the latest indexed block
+func getLastBlock(db *leveldb.Database) (uint64, bool, error) {
+    exists, err := db.Has(lastBlockKey)
+    if err != nil || !exists {
+        return 0, false, err
+    }
+    value, err := db.Get(lastBlockKey)
+    if err != nil {
+        return 0, false, err
+    }
+    return binary.BigEndian.Uint64(value), true, nil
+}
+
+
+// Get the key for a block number
+func getBlockKey(block uint64) []byte {
+    key := make([]byte, 8)
+    binary.BigEndian.PutUint64(key, block)
+    return key
+}
+
+
+// Get the key for a deposit's position on chain
+func getPositionKey(block uint64, logIndex uint) []byte {
+    key := make([]byte, 12)
+    binary.BigEndian.PutUint64(key, block)
+    binary.BigEndian.PutUint32(key[8:], uint32(logIndex))
+    return key
+}
+
+
+// Concatenate key parts
+func concat(parts ...[]byte) []byte {
+    key := []byte{}
+    for _, part := range parts {
+        key = append(key, part...)
+    }
+    return key
+}
@@ -0,0 +1,378 @@
+package deposits
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/rocket-pool/rocketpool-go/rocketpool"
+	"github.com/rocket-pool/rocketpool-go/types"
+
+	"github.com/rocket-pool/smartnode/shared/services/contracts"
+)
+
+// Test contract addresses
+var (
+    testStorageAddress = common.HexToAddress("0x1111111111111111111111111111111111111111")
+    testDepositAddress = common.HexToAddress("0x00000000219ab540356cBB839Cbe05303d7705Fa")
+)
+
+
+// A deposit event on the fake chain
+type fakeDeposit struct {
+    block uint64
+    pubkey types.ValidatorPubkey
+    withdrawalCredentials common.Hash
+    amount uint64
+    signature types.ValidatorSignature
+}
+
+
+// A fake eth1 chain serving block headers, the deposit contract address and deposit events
+// Reorging the chain changes the hashes of all blocks from the reorged block
+type fakeChain struct {
+    t *testing.T
+    depositEvent abi.Event
+    lock sync.Mutex
+    head uint64
+    reorgs []uint64
+    deposits []fakeDeposit
+    onGetLogs func(c *fakeChain)
+}
+
+
+// Reorg the chain from a block, removing its deposits from that block
+func (c *fakeChain) reorg(block uint64) {
+    c.reorgs = append(c.reorgs, block)
+    deposits := []fakeDeposit{}
+    for _, deposit := range c.deposits {
+        if deposit.block < block {
+            deposits = append(deposits, deposit)
+        }
+    }
+    c.deposits = deposits
+}
+
+
+// Get the header for a block
+func (c *fakeChain) header(block uint64) *ethtypes.Header {
+    fork := byte(0)
+    for _, reorgBlock := range c.reorgs {
+        if reorgBlock <= block {
+            fork++
+        }
+    }
+    return &ethtypes.Header{Number: new(big.Int).SetUint64(block), Time: 1600000000 + block * 13, Difficulty: big.NewInt(0), Extra: []byte{fork}}
+}
+
+
+// Get the deposit event logs in a block range
+func (c *fakeChain) getLogs(fromBlock, toBlock uint64) []*ethtypes.Log {
+    logs := []*ethtypes.Log{}
+    for di, deposit := range c.deposits {
+        if deposit.block < fromBlock || deposit.block > toBlock {
+            continue
+        }
+        amount := make([]byte, 8)
+        binary.LittleEndian.PutUint64(amount, deposit.amount)
+        data, err := c.depositEvent.Inputs.Pack(deposit.pubkey.Bytes(), deposit.withdrawalCredentials.Bytes(), amount, deposit.signature.Bytes(), make([]byte, 8))
+        if err != nil {
+            c.t.Fatal(err)
+        }
+        logs = append(logs, &ethtypes.Log{
+            Address: testDepositAddress,
+            Topics: []common.Hash{c.depositEvent.ID},
+            Data: data,
+            BlockNumber: deposit.block,
+            TxHash: common.BigToHash(big.NewInt(int64(di + 1))),
+            BlockHash: c.header(deposit.block).Hash(),
+            Index: uint(di),
+        })
+    }
+    return logs
+}
+
+
+// Serve a JSON-RPC request
+func (c *fakeChain) serve(w http.ResponseWriter, r *http.Request) {
+    var request struct {
+        ID json.RawMessage              `json:"id"`
+        Method string                   `json:"method"`
+        Params []json.RawMessage        `json:"params"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+    if request.Method == "eth_getLogs" && c.onGetLogs != nil {
+        c.onGetLogs(c)
+    }
+    c.lock.Lock()
+    defer c.lock.Unlock()
+    var result interface{}
+    switch request.Method {
+        case "eth_call":
+            result = hexutil.Bytes(common.LeftPadBytes(testDepositAddress.Bytes(), 32))
+        case "eth_getBlockByNumber":
+            var blockArg string
+            _ = json.Unmarshal(request.Params[0], &blockArg)
+            block := c.head
+            if blockArg != "latest" {
+                block = hexutil.MustDecodeUint64(blockArg)
+            }
+            if block <= c.head {
+                result = c.header(block)
+            }
+        case "eth_getLogs":
+            var filter struct {
+                FromBlock hexutil.Uint64        `json:"fromBlock"`
+                ToBlock hexutil.Uint64          `json:"toBlock"`
+            }
+            if err := json.Unmarshal(request.Params[0], &filter); err != nil {
+                c.t.Errorf("could not decode log filter: %v", err)
+            }
+            result = c.getLogs(uint64(filter.FromBlock), uint64(filter.ToBlock))
+        default:
+            c.t.Errorf("unexpected %s request", request.Method)
+    }
+    w.Header().Set("Content-Type", "application/json")
+    _ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": request.ID, "result": result})
+}
+
+
+// Start a fake chain and create an indexer for it in a temporary folder
+func newTestIndexer(t *testing.T, chain *fakeChain, startOffset uint64, intervalSize int64) (*Indexer, func()) {
+    depositAbi, err := abi.JSON(strings.NewReader(contracts.BeaconDepositABI))
+    if err != nil {
+        t.Fatal(err)
+    }
+    chain.t = t
+    chain.depositEvent = depositAbi.Events["DepositEvent"]
+    dir, err := ioutil.TempDir("", "deposits")
+    if err != nil {
+        t.Fatal(err)
+    }
+    server := httptest.NewServer(http.HandlerFunc(chain.serve))
+    cleanup := func() {
+        server.Close()
+        os.RemoveAll(dir)
+    }
+    client, err := ethclient.Dial(server.URL)
+    if err != nil {
+        cleanup()
+        t.Fatal(err)
+    }
+    rp, err := rocketpool.NewRocketPool(client, testStorageAddress)
+    if err != nil {
+        cleanup()
+        t.Fatal(err)
+    }
+    idx := NewIndexer(rp, filepath.Join(dir, "deposits"), big.NewInt(intervalSize))
+    idx.startOffset = startOffset
+    return idx, cleanup
+}
+
+
+// Get a test validator pubkey
+func testPubkey(id byte) types.ValidatorPubkey {
+    return types.BytesToValidatorPubkey(append(make([]byte, 47), id))
+}
+
+
+// Get the blocks of the indexed deposits for a set of validators
+func getIndexedBlocks(t *testing.T, idx *Indexer, ids ...byte) map[byte][]uint64 {
+    pubkeys := map[types.ValidatorPubkey]bool{}
+    for _, id := range ids {
+        pubkeys[testPubkey(id)] = true
+    }
+    depositMap, err := idx.GetDeposits(pubkeys)
+    if err != nil {
+        t.Fatal(err)
+    }
+    blocks := map[byte][]uint64{}
+    for _, id := range ids {
+        for _, deposit := range depositMap[testPubkey(id)] {
+            blocks[id] = append(blocks[id], deposit.BlockNumber)
+        }
+    }
+    return blocks
+}
+
+
+func TestIndexerUpdate(t *testing.T) {
+    chain := &fakeChain{
+        head: 200,
+        deposits: []fakeDeposit{
+            {block: 90, pubkey: testPubkey(1), amount: 1000000000},
+            {block: 120, pubkey: testPubkey(1), withdrawalCredentials: common.HexToHash("0x01"), amount: 16000000000},
+            {block: 150, pubkey: testPubkey(2), amount: 16000000000},
+            {block: 190, pubkey: testPubkey(3), amount: 16000000000},
+        },
+    }
+    idx, cleanup := newTestIndexer(t, chain, 100, 50)
+    defer cleanup()
+
+    // Deposits from the start offset are indexed
+    lastBlock, err := idx.Update()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if lastBlock != 200 {
+        t.Errorf("indexed to block %d, expected 200", lastBlock)
+    }
+    blocks := getIndexedBlocks(t, idx, 1, 2, 3)
+    if len(blocks[1]) != 1 || blocks[1][0] != 120 || len(blocks[2]) != 1 || len(blocks[3]) != 1 {
+        t.Errorf("indexed deposits are at blocks %v, expected 1: [120], 2: [150], 3: [190]", blocks)
+    }
+    depositMap, err := idx.GetDeposits(map[types.ValidatorPubkey]bool{testPubkey(1): true})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if deposit := depositMap[testPubkey(1)][0]; deposit.Amount != 16000000000 || deposit.WithdrawalCredentials != common.HexToHash("0x01") {
+        t.Errorf("indexed deposit is %+v", deposit)
+    }
+
+    // Updates continue from the last indexed block
+    chain.head = 230
+    chain.deposits = append(chain.deposits, fakeDeposit{block: 220, pubkey: testPubkey(2), amount: 1000000000})
+    if lastBlock, err := idx.Update(); err != nil || lastBlock != 230 {
+        t.Fatalf("indexed to block %d (%v), expected 230", lastBlock, err)
+    }
+    if blocks := getIndexedBlocks(t, idx, 2); len(blocks[2]) != 2 || blocks[2][1] != 220 {
+        t.Errorf("validator 2 deposits are at blocks %v, expected [150 220]", blocks[2])
+    }
+
+}
+
+
+func TestIndexerReorg(t *testing.T) {
+    chain := &fakeChain{
+        head: 200,
+        deposits: []fakeDeposit{
+            {block: 120, pubkey: testPubkey(1)},
+            {block: 160, pubkey: testPubkey(2)},
+            {block: 190, pubkey: testPubkey(3)},
+        },
+    }
+    idx, cleanup := newTestIndexer(t, chain, 100, 50)
+    defer cleanup()
+    if _, err := idx.Update(); err != nil {
+        t.Fatal(err)
+    }
+
+    // Reorg from block 180; the checkpoints at blocks 199 & 200 are reorged out, so the index rolls back to block 149 and is rebuilt
+    chain.reorg(180)
+    chain.deposits = append(chain.deposits, fakeDeposit{block: 185, pubkey: testPubkey(4)})
+    chain.head = 210
+    if lastBlock, err := idx.Update(); err != nil || lastBlock != 210 {
+        t.Fatalf("indexed to block %d (%v), expected 210", lastBlock, err)
+    }
+    blocks := getIndexedBlocks(t, idx, 1, 2, 3, 4)
+    if len(blocks[1]) != 1 || len(blocks[2]) != 1 || len(blocks[3]) != 0 || len(blocks[4]) != 1 || blocks[4][0] != 185 {
+        t.Errorf("indexed deposits are at blocks %v, expected 1: [120], 2: [160], 4: [185]", blocks)
+    }
+
+    // Reorgs before all checkpoints rebuild the index
+    chain.reorg(110)
+    chain.deposits = append(chain.deposits, fakeDeposit{block: 115, pubkey: testPubkey(5)})
+    if _, err := idx.Update(); err != nil {
+        t.Fatal(err)
+    }
+    blocks = getIndexedBlocks(t, idx, 1, 2, 4, 5)
+    if len(blocks[1]) != 0 || len(blocks[2]) != 0 || len(blocks[4]) != 0 || len(blocks[5]) != 1 {
+        t.Errorf("indexed deposits are at blocks %v, expected 5: [115]", blocks)
+    }
+
+}
+
+
+func TestIndexerReorgWhileFiltering(t *testing.T) {
+    chain := &fakeChain{
+        head: 200,
+        deposits: []fakeDeposit{{block: 190, pubkey: testPubkey(1)}},
+    }
+    idx, cleanup := newTestIndexer(t, chain, 50, 0)
+    defer cleanup()
+
+    // The chain is reorged while the events are retrieved; the interval is retried on the new chain
+    chain.onGetLogs = func(c *fakeChain) {
+        c.lock.Lock()
+        defer c.lock.Unlock()
+        if len(c.reorgs) == 0 {
+            c.reorg(180)
+            c.deposits = append(c.deposits, fakeDeposit{block: 185, pubkey: testPubkey(2)})
+        }
+    }
+    if _, err := idx.Update(); err != nil {
+        t.Fatal(err)
+    }
+    if blocks := getIndexedBlocks(t, idx, 1, 2); len(blocks[1]) != 0 || len(blocks[2]) != 1 {
+        t.Errorf("indexed deposits are at blocks %v, expected 2: [185]", blocks)
+    }
+    checkpoints, hashes, err := idx.getTestCheckpoints()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(checkpoints) != 1 || hashes[0] != chain.header(200).Hash() {
+        t.Errorf("checkpoints are %v, expected block 200 on the new chain", checkpoints)
+    }
+
+    // Repeated reorgs fail the update
+    chain.head = 210
+    chain.onGetLogs = func(c *fakeChain) {
+        c.lock.Lock()
+        defer c.lock.Unlock()
+        c.reorg(205)
+    }
+    if _, err := idx.Update(); err == nil || !strings.Contains(err.Error(), "was reorged while getting deposit events") {
+        t.Errorf("expected a reorg error, got %v", err)
+    }
+
+}
+
+
+func TestIndexerMaxCheckpoints(t *testing.T) {
+    chain := &fakeChain{head: MaxCheckpoints + 20}
+    idx, cleanup := newTestIndexer(t, chain, MaxCheckpoints + 10, 1)
+    defer cleanup()
+
+    // Each block is checkpointed, and only the latest checkpoints are kept
+    if _, err := idx.Update(); err != nil {
+        t.Fatal(err)
+    }
+    checkpoints, _, err := idx.getTestCheckpoints()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(checkpoints) != MaxCheckpoints {
+        t.Fatalf("got %d checkpoints, expected %d", len(checkpoints), MaxCheckpoints)
+    }
+    if checkpoints[0] != 21 || checkpoints[len(checkpoints) - 1] != MaxCheckpoints + 20 {
+        t.Errorf("checkpoints are blocks %d - %d, expected 21 - %d", checkpoints[0], checkpoints[len(checkpoints) - 1], MaxCheckpoints + 20)
+    }
+
+}
+
+
+// Get the index checkpoints
+func (idx *Indexer) getTestCheckpoints() ([]uint64, []common.Hash, error) {
+    db, err := idx.open(true)
+    if err != nil {
+        return nil, nil, err
+    }
+    defer db.Close()
+    return getCheckpoints(db)
+}
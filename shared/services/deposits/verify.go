@@ -0,0 +1,44 @@
+package deposits
+
+import (
+	"github.com/prysmaticlabs/prysm/v2/beacon-chain/core/signing"
+	prdeposit "github.com/prysmaticlabs/prysm/v2/contracts/deposit"
+	ethpb "github.com/prysmaticlabs/prysm/v2/proto/prysm/v1alpha1"
+	"github.com/rocket-pool/rocketpool-go/utils"
+	eth2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/rocket-pool/smartnode/shared/services/beacon"
+)
+
+
+// Get the signing domain for deposits on the beacon chain
+func GetDepositDomain(bc beacon.Client) ([]byte, error) {
+    eth2Config, err := bc.GetEth2Config()
+    if err != nil {
+        return nil, err
+    }
+    return signing.ComputeDomain(eth2types.DomainDeposit, eth2Config.GenesisForkVersion, eth2types.ZeroGenesisValidatorsRoot)
+}
+
+
+// Verify the signature of a deposit; deposits with invalid signatures are ignored by the beacon chain
+func VerifyDepositSignature(deposit utils.DepositData, depositDomain []byte) error {
+    depositData := new(ethpb.Deposit_Data)
+    depositData.Amount = deposit.Amount
+    depositData.PublicKey = deposit.Pubkey.Bytes()
+    depositData.WithdrawalCredentials = deposit.WithdrawalCredentials.Bytes()
+    depositData.Signature = deposit.Signature.Bytes()
+    return prdeposit.VerifyDepositSignature(depositData, depositDomain)
+}
+
+
+// Get the first deposit for a validator with a valid signature, which determines its withdrawal credentials
+// Returns the index of the deposit, or -1 if none of the deposits are valid
+func GetFirstValidDeposit(deposits []utils.DepositData, depositDomain []byte) int {
+    for di, deposit := range deposits {
+        if VerifyDepositSignature(deposit, depositDomain) == nil {
+            return di
+        }
+    }
+    return -1
+}
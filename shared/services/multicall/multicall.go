@@ -0,0 +1,287 @@
+package multicall
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/rocket-pool/rocketpool-go/rocketpool"
+	"golang.org/x/sync/errgroup"
+)
+
+// Settings
+const (
+    MulticallBatchSize = 500
+    FallbackBatchSize = 20
+)
+
+// Default Multicall2 contract addresses by eth1 chain ID
+var defaultAddresses = map[string]string{
+    "1": "0x5BA1e12693Dc8F9c48aAD8770482f4739bEeD696",
+    "5": "0x5BA1e12693Dc8F9c48aAD8770482f4739bEeD696",
+}
+
+// Multicall2 tryAggregate & getEthBalance ABI
+const multicallABI = `[{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"getEthBalance","outputs":[{"internalType":"uint256","name":"balance","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"bool","name":"requireSuccess","type":"bool"},{"components":[{"internalType":"address","name":"target","type":"address"},{"internalType":"bytes","name":"callData","type":"bytes"}],"internalType":"struct Multicall2.Call[]","name":"calls","type":"tuple[]"}],"name":"tryAggregate","outputs":[{"components":[{"internalType":"bool","name":"success","type":"bool"},{"internalType":"bytes","name":"returnData","type":"bytes"}],"internalType":"struct Multicall2.Result[]","name":"returnData","type":"tuple[]"}],"stateMutability":"nonpayable","type":"function"}]`
+
+
+// Aggregates contract reads into batched calls to a Multicall2 contract
+// If no multicall contract address is configured, calls are made individually in concurrent batches instead
+type MultiCaller struct {
+    client *ethclient.Client
+    address common.Address
+    abi abi.ABI
+}
+
+
+// A batch of contract reads
+type Batch struct {
+    mc *MultiCaller
+    calls []call
+}
+type call struct {
+    target common.Address
+    abi *abi.ABI
+    method string
+    input []byte
+    output interface{}
+    balanceAccount *common.Address
+}
+
+
+// Multicall2 types
+type multicallCall struct {
+    Target common.Address
+    CallData []byte
+}
+type multicallResult struct {
+    Success bool
+    ReturnData []byte
+}
+
+
+// Create new multicaller
+func NewMultiCaller(client *ethclient.Client, address common.Address) (*MultiCaller, error) {
+
+    // Parse ABI
+    multicallAbi, err := abi.JSON(strings.NewReader(multicallABI))
+    if err != nil {
+        return nil, fmt.Errorf("Could not decode multicall ABI: %w", err)
+    }
+
+    // Return
+    return &MultiCaller{
+        client: client,
+        address: address,
+        abi: multicallAbi,
+    }, nil
+
+}
+
+
+// Get the multicall contract address to use for a chain
+// The configured address is used if set, otherwise the chain's default address; set it to the zero address to read contracts individually
+func GetAddress(configuredAddress string, chainID string) common.Address {
+    if configuredAddress != "" {
+        return common.HexToAddress(configuredAddress)
+    }
+    return common.HexToAddress(defaultAddresses[chainID])
+}
+
+
+// Check whether reads are aggregated by a multicall contract
+func (mc *MultiCaller) IsEnabled() bool {
+    return mc.address != common.Address{}
+}
+
+
+// Create a new batch of reads
+func (mc *MultiCaller) NewBatch() *Batch {
+    return &Batch{
+        mc: mc,
+        calls: []call{},
+    }
+}
+
+
+// Add a contract read to the batch; the output is unpacked into once the batch is executed
+func (b *Batch) AddCall(contract *rocketpool.Contract, output interface{}, method string, params ...interface{}) error {
+    input, err := contract.ABI.Pack(method, params...)
+    if err != nil {
+        return fmt.Errorf("Could not encode %s call input: %w", method, err)
+    }
+    b.calls = append(b.calls, call{
+        target: *contract.Address,
+        abi: contract.ABI,
+        method: method,
+        input: input,
+        output: output,
+    })
+    return nil
+}
+
+
+// Add an account ETH balance read to the batch
+func (b *Batch) AddBalanceCall(account common.Address, output **big.Int) error {
+    input, err := b.mc.abi.Pack("getEthBalance", account)
+    if err != nil {
+        return fmt.Errorf("Could not encode getEthBalance call input: %w", err)
+    }
+    b.calls = append(b.calls, call{
+        target: b.mc.address,
+        abi: &b.mc.abi,
+        method: "getEthBalance",
+        input: input,
+        output: output,
+        balanceAccount: &account,
+    })
+    return nil
+}
+
+
+// Get the number of reads in the batch
+func (b *Batch) Len() int {
+    return len(b.calls)
+}
+
+
+// Execute the batch and unpack the results of all reads
+func (b *Batch) Execute(opts *bind.CallOpts) error {
+
+    // Get call options
+    if opts == nil {
+        opts = &bind.CallOpts{}
+    }
+    ctx := opts.Context
+    if ctx == nil {
+        ctx = context.Background()
+    }
+
+    // Make calls individually if multicall is disabled
+    if !b.mc.IsEnabled() {
+        return b.executeIndividually(ctx, opts)
+    }
+
+    // Aggregate calls in batches
+    for bsi := 0; bsi < len(b.calls); bsi += MulticallBatchSize {
+
+        // Get batch start & end index
+        csi := bsi
+        cei := bsi + MulticallBatchSize
+        if cei > len(b.calls) { cei = len(b.calls) }
+
+        // Execute
+        if err := b.executeMulticall(ctx, opts, b.calls[csi:cei]); err != nil {
+            return err
+        }
+
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Execute a set of calls in a single multicall
+func (b *Batch) executeMulticall(ctx context.Context, opts *bind.CallOpts, calls []call) error {
+
+    // Encode multicall input
+    multicallCalls := make([]multicallCall, len(calls))
+    for ci, c := range calls {
+        multicallCalls[ci] = multicallCall{
+            Target: c.target,
+            CallData: c.input,
+        }
+    }
+    input, err := b.mc.abi.Pack("tryAggregate", false, multicallCalls)
+    if err != nil {
+        return fmt.Errorf("Could not encode multicall input: %w", err)
+    }
+
+    // Call multicall contract
+    output, err := b.mc.client.CallContract(ctx, ethereum.CallMsg{From: opts.From, To: &b.mc.address, Data: input}, opts.BlockNumber)
+    if err != nil {
+        return fmt.Errorf("Could not call multicall contract %s: %w", b.mc.address.Hex(), err)
+    }
+
+    // Decode multicall output
+    values, err := b.mc.abi.Unpack("tryAggregate", output)
+    if err != nil {
+        return fmt.Errorf("Could not decode multicall output: %w", err)
+    }
+    results := *abi.ConvertType(values[0], new([]multicallResult)).(*[]multicallResult)
+    if len(results) != len(calls) {
+        return fmt.Errorf("Multicall returned %d results for %d calls", len(results), len(calls))
+    }
+
+    // Unpack results
+    for ci, c := range calls {
+        if !results[ci].Success {
+            return fmt.Errorf("Could not call %s on contract %s: execution reverted", c.method, c.target.Hex())
+        }
+        if err := c.unpack(results[ci].ReturnData); err != nil {
+            return err
+        }
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Execute calls individually in concurrent batches
+func (b *Batch) executeIndividually(ctx context.Context, opts *bind.CallOpts) error {
+    for bsi := 0; bsi < len(b.calls); bsi += FallbackBatchSize {
+
+        // Get batch start & end index
+        csi := bsi
+        cei := bsi + FallbackBatchSize
+        if cei > len(b.calls) { cei = len(b.calls) }
+
+        // Make calls
+        var wg errgroup.Group
+        for ci := csi; ci < cei; ci++ {
+            c := b.calls[ci]
+            wg.Go(func() error {
+                if c.balanceAccount != nil {
+                    balance, err := b.mc.client.BalanceAt(ctx, *c.balanceAccount, opts.BlockNumber)
+                    if err != nil {
+                        return fmt.Errorf("Could not get account %s ETH balance: %w", c.balanceAccount.Hex(), err)
+                    }
+                    *c.output.(**big.Int) = balance
+                    return nil
+                }
+                output, err := b.mc.client.CallContract(ctx, ethereum.CallMsg{From: opts.From, To: &c.target, Data: c.input}, opts.BlockNumber)
+                if err != nil {
+                    return fmt.Errorf("Could not call %s on contract %s: %w", c.method, c.target.Hex(), err)
+                }
+                return c.unpack(output)
+            })
+        }
+        if err := wg.Wait(); err != nil {
+            return err
+        }
+
+    }
+    return nil
+}
+
+
+// Unpack a call's return data into its output
+func (c *call) unpack(data []byte) error {
+    if len(data) == 0 {
+        return fmt.Errorf("Could not call %s on contract %s: %w", c.method, c.target.Hex(), bind.ErrNoCode)
+    }
+    if err := c.abi.UnpackIntoInterface(c.output, c.method, data); err != nil {
+        return fmt.Errorf("Could not decode %s output from contract %s: %w", c.method, c.target.Hex(), err)
+    }
+    return nil
+}
@@ -0,0 +1,323 @@
+package multicall
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/rocket-pool/rocketpool-go/rocketpool"
+)
+
+// Test contract ABI; double returns twice its input
+const testContractABI = `[{"inputs":[{"internalType":"uint256","name":"value","type":"uint256"}],"name":"double","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+
+// Test addresses
+var (
+    testMulticallAddress = common.HexToAddress("0x5BA1e12693Dc8F9c48aAD8770482f4739bEeD696")
+    testContractAddress = common.HexToAddress("0x1111111111111111111111111111111111111111")
+    testRevertingAddress = common.HexToAddress("0x2222222222222222222222222222222222222222")
+    testAccountAddress = common.HexToAddress("0x3333333333333333333333333333333333333333")
+)
+
+
+// A fake eth1 client serving the test contract, directly and through a Multicall2 contract
+type fakeMulticallClient struct {
+    t *testing.T
+    multicallAbi abi.ABI
+    contractAbi abi.ABI
+    lock sync.Mutex
+    multicalls int
+    calls int
+}
+
+
+// Serve a JSON-RPC request
+func (f *fakeMulticallClient) serve(w http.ResponseWriter, r *http.Request) {
+    var request struct {
+        ID json.RawMessage              `json:"id"`
+        Method string                   `json:"method"`
+        Params []json.RawMessage        `json:"params"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+    response := map[string]interface{}{"jsonrpc": "2.0", "id": request.ID}
+    switch request.Method {
+        case "eth_getBalance":
+            f.record(false)
+            response["result"] = "0x64"
+        case "eth_call":
+            var msg struct {
+                To common.Address           `json:"to"`
+                Data hexutil.Bytes          `json:"data"`
+            }
+            if err := json.Unmarshal(request.Params[0], &msg); err != nil {
+                f.t.Errorf("could not decode call: %v", err)
+            }
+            if msg.To == testMulticallAddress {
+                f.record(true)
+                response["result"] = hexutil.Bytes(f.tryAggregate(msg.Data))
+            } else if output, ok := f.call(msg.To, msg.Data); ok {
+                f.record(false)
+                response["result"] = hexutil.Bytes(output)
+            } else {
+                f.record(false)
+                response["error"] = map[string]interface{}{"code": 3, "message": "execution reverted"}
+            }
+        default:
+            f.t.Errorf("unexpected %s request", request.Method)
+    }
+    w.Header().Set("Content-Type", "application/json")
+    _ = json.NewEncoder(w).Encode(response)
+}
+
+
+// Record a request
+func (f *fakeMulticallClient) record(multicall bool) {
+    f.lock.Lock()
+    defer f.lock.Unlock()
+    if multicall {
+        f.multicalls++
+    } else {
+        f.calls++
+    }
+}
+
+
+// Execute a call to the test contract; calls to the reverting address fail
+func (f *fakeMulticallClient) call(target common.Address, data []byte) ([]byte, bool) {
+    if target != testContractAddress {
+        return nil, false
+    }
+    values, err := f.contractAbi.Methods["double"].Inputs.Unpack(data[4:])
+    if err != nil {
+        f.t.Errorf("could not decode double input: %v", err)
+        return nil, false
+    }
+    output, err := f.contractAbi.Methods["double"].Outputs.Pack(new(big.Int).Mul(values[0].(*big.Int), big.NewInt(2)))
+    if err != nil {
+        f.t.Errorf("could not encode double output: %v", err)
+        return nil, false
+    }
+    return output, true
+}
+
+
+// Execute a Multicall2 tryAggregate call
+func (f *fakeMulticallClient) tryAggregate(data []byte) []byte {
+    method, err := f.multicallAbi.MethodById(data[:4])
+    if err != nil || method.Name != "tryAggregate" {
+        f.t.Errorf("unexpected multicall method: %v", err)
+        return nil
+    }
+    values, err := method.Inputs.Unpack(data[4:])
+    if err != nil {
+        f.t.Errorf("could not decode multicall input: %v", err)
+        return nil
+    }
+    calls := *abi.ConvertType(values[1], new([]multicallCall)).(*[]multicallCall)
+    results := make([]multicallResult, len(calls))
+    for ci, c := range calls {
+        if c.Target == testMulticallAddress {
+            balance, _ := f.multicallAbi.Methods["getEthBalance"].Outputs.Pack(big.NewInt(100))
+            results[ci] = multicallResult{Success: true, ReturnData: balance}
+        } else if output, ok := f.call(c.Target, c.CallData); ok {
+            results[ci] = multicallResult{Success: true, ReturnData: output}
+        } else {
+            results[ci] = multicallResult{Success: false, ReturnData: []byte{}}
+        }
+    }
+    output, err := method.Outputs.Pack(results)
+    if err != nil {
+        f.t.Errorf("could not encode multicall output: %v", err)
+    }
+    return output
+}
+
+
+// Start a fake client and create a multicaller for it
+func newTestMultiCaller(t *testing.T, address common.Address) (*MultiCaller, *fakeMulticallClient, func()) {
+    multicallAbi, err := abi.JSON(strings.NewReader(multicallABI))
+    if err != nil {
+        t.Fatal(err)
+    }
+    contractAbi, err := abi.JSON(strings.NewReader(testContractABI))
+    if err != nil {
+        t.Fatal(err)
+    }
+    fake := &fakeMulticallClient{t: t, multicallAbi: multicallAbi, contractAbi: contractAbi}
+    server := httptest.NewServer(http.HandlerFunc(fake.serve))
+    client, err := ethclient.Dial(server.URL)
+    if err != nil {
+        server.Close()
+        t.Fatal(err)
+    }
+    mc, err := NewMultiCaller(client, address)
+    if err != nil {
+        client.Close()
+        server.Close()
+        t.Fatal(err)
+    }
+    return mc, fake, func() {
+        client.Close()
+        server.Close()
+    }
+}
+
+
+// Get a test contract binding at an address
+func newTestContract(t *testing.T, address common.Address) *rocketpool.Contract {
+    contractAbi, err := abi.JSON(strings.NewReader(testContractABI))
+    if err != nil {
+        t.Fatal(err)
+    }
+    return &rocketpool.Contract{Address: &address, ABI: &contractAbi}
+}
+
+
+func TestBatchExecute(t *testing.T) {
+    tests := []struct {
+        name string
+        address common.Address
+        expectedMulticalls int
+        expectedCalls int
+    }{
+        {"aggregated", testMulticallAddress, 1, 0},
+        {"individual", common.Address{}, 0, FallbackBatchSize + 6},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            mc, fake, cleanup := newTestMultiCaller(t, test.address)
+            defer cleanup()
+            contract := newTestContract(t, testContractAddress)
+
+            // Add calls; enough to span multiple fallback batches
+            batch := mc.NewBatch()
+            outputs := make([]*big.Int, FallbackBatchSize + 5)
+            for oi := range outputs {
+                if err := batch.AddCall(contract, &outputs[oi], "double", big.NewInt(int64(oi))); err != nil {
+                    t.Fatal(err)
+                }
+            }
+            var balance *big.Int
+            if err := batch.AddBalanceCall(testAccountAddress, &balance); err != nil {
+                t.Fatal(err)
+            }
+            if batch.Len() != len(outputs) + 1 {
+                t.Errorf("batch has %d calls, expected %d", batch.Len(), len(outputs) + 1)
+            }
+
+            // Execute
+            if err := batch.Execute(nil); err != nil {
+                t.Fatal(err)
+            }
+            for oi, output := range outputs {
+                if output == nil || output.Int64() != int64(oi * 2) {
+                    t.Errorf("output %d is %v, expected %d", oi, output, oi * 2)
+                }
+            }
+            if balance == nil || balance.Int64() != 100 {
+                t.Errorf("balance is %v, expected 100", balance)
+            }
+            if fake.multicalls != test.expectedMulticalls || fake.calls != test.expectedCalls {
+                t.Errorf("made %d multicalls and %d calls, expected %d and %d", fake.multicalls, fake.calls, test.expectedMulticalls, test.expectedCalls)
+            }
+        })
+    }
+}
+
+
+func TestBatchExecuteMulticallBatches(t *testing.T) {
+    mc, fake, cleanup := newTestMultiCaller(t, testMulticallAddress)
+    defer cleanup()
+    contract := newTestContract(t, testContractAddress)
+
+    // Calls are split into multicalls of at most the batch size
+    batch := mc.NewBatch()
+    outputs := make([]*big.Int, MulticallBatchSize + 1)
+    for oi := range outputs {
+        if err := batch.AddCall(contract, &outputs[oi], "double", big.NewInt(int64(oi))); err != nil {
+            t.Fatal(err)
+        }
+    }
+    if err := batch.Execute(nil); err != nil {
+        t.Fatal(err)
+    }
+    if fake.multicalls != 2 {
+        t.Errorf("made %d multicalls, expected 2", fake.multicalls)
+    }
+    if last := outputs[MulticallBatchSize]; last == nil || last.Int64() != int64(MulticallBatchSize * 2) {
+        t.Errorf("last output is %v, expected %d", last, MulticallBatchSize * 2)
+    }
+
+}
+
+
+func TestBatchExecuteFailedCall(t *testing.T) {
+    tests := []struct {
+        name string
+        address common.Address
+        errorContains string
+    }{
+        {"failed inside tryAggregate", testMulticallAddress, "Could not call double on contract " + testRevertingAddress.Hex() + ": execution reverted"},
+        {"failed individually", common.Address{}, "Could not call double on contract " + testRevertingAddress.Hex()},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            mc, _, cleanup := newTestMultiCaller(t, test.address)
+            defer cleanup()
+
+            // A failing call among successful calls fails the batch
+            batch := mc.NewBatch()
+            var first, failed, last *big.Int
+            if err := batch.AddCall(newTestContract(t, testContractAddress), &first, "double", big.NewInt(1)); err != nil {
+                t.Fatal(err)
+            }
+            if err := batch.AddCall(newTestContract(t, testRevertingAddress), &failed, "double", big.NewInt(2)); err != nil {
+                t.Fatal(err)
+            }
+            if err := batch.AddCall(newTestContract(t, testContractAddress), &last, "double", big.NewInt(3)); err != nil {
+                t.Fatal(err)
+            }
+            err := batch.Execute(nil)
+            if err == nil || !strings.Contains(err.Error(), test.errorContains) {
+                t.Fatalf("expected an error containing '%s', got %v", test.errorContains, err)
+            }
+            if failed != nil {
+                t.Errorf("failed call output is %v, expected none", failed)
+            }
+        })
+    }
+}
+
+
+func TestGetAddress(t *testing.T) {
+    tests := []struct {
+        name string
+        configuredAddress string
+        chainID string
+        expected common.Address
+    }{
+        {"mainnet default", "", "1", testMulticallAddress},
+        {"goerli default", "", "5", testMulticallAddress},
+        {"unknown chain", "", "1337", common.Address{}},
+        {"configured", testContractAddress.Hex(), "1", testContractAddress},
+        {"disabled", "0x0", "1", common.Address{}},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            if address := GetAddress(test.configuredAddress, test.chainID); address != test.expected {
+                t.Errorf("address is %s, expected %s", address.Hex(), test.expected.Hex())
+            }
+        })
+    }
+}
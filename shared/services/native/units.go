@@ -0,0 +1,213 @@
+package native
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/a8m/envsubst/parse"
+
+	"github.com/rocket-pool/smartnode/shared/services/config"
+)
+
+// Settings
+const (
+    DefaultUnitPath = "/etc/systemd/system"
+    ClientStopTimeout = 180
+    DaemonStopTimeout = 10
+)
+
+// Native services; these share their names with the docker service containers
+const (
+    ServiceEth1 = config.ContainerEth1
+    ServiceEth2 = config.ContainerEth2
+    ServiceValidator = config.ContainerValidator
+    ServiceNode = config.ContainerNode
+    ServiceWatchtower = config.ContainerWatchtower
+)
+
+
+// A systemd unit for a native service
+type Unit struct {
+    Service string
+    Name string
+    Description string
+    ExecStart string
+    Environment []string
+    DataPath string
+    After []string
+    StopTimeout int
+}
+
+
+// Get the systemd units for the native services, in start order
+// Client commands are taken from the native settings if set, or from the selected clients' native commands in config.yml,
+// and environment variables in them (in ${VAR} form) are interpolated from env (in KEY=VALUE format) and the service's DATA_DIR
+func GetUnits(cfg config.RocketPoolConfig, configPath string, env []string) ([]*Unit, error) {
+
+    // Check config
+    if cfg.Native.DaemonPath == "" {
+        return nil, errors.New("The path to the Rocket Pool daemon is not set. Please run 'rocketpool service install --native' and try again.")
+    }
+    if cfg.Native.DataPath == "" {
+        return nil, errors.New("The native data path is not set. Please run 'rocketpool service install --native' and try again.")
+    }
+    eth1Client := cfg.GetSelectedEth1Client()
+    eth2Client := cfg.GetSelectedEth2Client()
+    if eth1Client == nil {
+        return nil, errors.New("No Eth 1.0 client selected. Please run 'rocketpool service config' and try again.")
+    }
+    if eth2Client == nil {
+        return nil, errors.New("No Eth 2.0 client selected. Please run 'rocketpool service config' and try again.")
+    }
+
+    // Get client commands
+    eth1Command := getCommand(cfg.Native.Eth1Command, eth1Client.NativeCommand)
+    eth2Command := getCommand(cfg.Native.Eth2Command, eth2Client.NativeCommand)
+    validatorCommand := getCommand(cfg.Native.ValidatorCommand, eth2Client.NativeValidatorCommand)
+    if eth1Command == "" {
+        return nil, fmt.Errorf("Eth 1.0 client [%s] does not support native mode. Please set 'native.eth1Command' in your settings to the command which runs it.", eth1Client.Name)
+    }
+    if eth2Command == "" {
+        return nil, fmt.Errorf("Eth 2.0 client [%s] does not support native mode. Please set 'native.eth2Command' in your settings to the command which runs it.", eth2Client.Name)
+    }
+
+    // Get daemon command
+    daemonCommand := fmt.Sprintf("%s --config %s --settings %s",
+        quoteArg(cfg.Native.DaemonPath),
+        quoteArg(filepath.Join(configPath, "config.yml")),
+        quoteArg(filepath.Join(configPath, "settings.yml")))
+
+    // Get units
+    units := []*Unit{}
+    addUnit := func(service string, description string, command string, stopTimeout int, after ...string) error {
+        dataPath := filepath.Join(cfg.Native.DataPath, service)
+        serviceEnv := append([]string{
+            fmt.Sprintf("DATA_DIR=%s", dataPath),
+            fmt.Sprintf("CONFIG_PATH=%s", configPath),
+        }, env...)
+        execStart, err := parse.New(service, serviceEnv, parse.Relaxed).Parse(command)
+        if err != nil {
+            return fmt.Errorf("Could not interpolate the %s command: %w", service, err)
+        }
+        afterUnits := make([]string, len(after))
+        for ai, afterService := range after {
+            afterUnits[ai] = GetUnitName(cfg, afterService)
+        }
+        units = append(units, &Unit{
+            Service: service,
+            Name: GetUnitName(cfg, service),
+            Description: description,
+            ExecStart: execStart,
+            Environment: serviceEnv,
+            DataPath: dataPath,
+            After: afterUnits,
+            StopTimeout: stopTimeout,
+        })
+        return nil
+    }
+    if err := addUnit(ServiceEth1, fmt.Sprintf("Rocket Pool Eth 1.0 client (%s)", eth1Client.Name), eth1Command, ClientStopTimeout); err != nil {
+        return nil, err
+    }
+    if err := addUnit(ServiceEth2, fmt.Sprintf("Rocket Pool Eth 2.0 beacon client (%s)", eth2Client.Name), eth2Command, ClientStopTimeout, ServiceEth1); err != nil {
+        return nil, err
+    }
+    if validatorCommand != "" {
+        if err := addUnit(ServiceValidator, fmt.Sprintf("Rocket Pool Eth 2.0 validator client (%s)", eth2Client.Name), validatorCommand, ClientStopTimeout, ServiceEth2); err != nil {
+            return nil, err
+        }
+    }
+    if err := addUnit(ServiceNode, "Rocket Pool node daemon", daemonCommand + " node", DaemonStopTimeout, ServiceEth1, ServiceEth2); err != nil {
+        return nil, err
+    }
+    if err := addUnit(ServiceWatchtower, "Rocket Pool watchtower daemon", daemonCommand + " watchtower", DaemonStopTimeout, ServiceEth1, ServiceEth2); err != nil {
+        return nil, err
+    }
+
+    // Return
+    return units, nil
+
+}
+
+
+// Get the name of a native service's systemd unit
+func GetUnitName(cfg config.RocketPoolConfig, service string) string {
+    return fmt.Sprintf("%s-%s.service", cfg.Smartnode.ProjectName, service)
+}
+
+
+// Get the directory the systemd units are installed to
+func GetUnitPath(cfg config.RocketPoolConfig) string {
+    if cfg.Native.UnitPath == "" {
+        return DefaultUnitPath
+    }
+    return cfg.Native.UnitPath
+}
+
+
+// Get the service responsible for validator duties; single process eth2 clients have no validator service
+func GetValidatorService(cfg config.RocketPoolConfig) string {
+    eth2Client := cfg.GetSelectedEth2Client()
+    if eth2Client == nil || getCommand(cfg.Native.ValidatorCommand, eth2Client.NativeValidatorCommand) == "" {
+        return ServiceEth2
+    }
+    return ServiceValidator
+}
+
+
+// Render the unit file
+func (u *Unit) Render(user string) string {
+    var unit strings.Builder
+    unit.WriteString("# Generated by the Rocket Pool smartnode; changes will be overwritten by 'rocketpool service start'\n")
+    unit.WriteString("[Unit]\n")
+    fmt.Fprintf(&unit, "Description=%s\n", escapeSpecifiers(u.Description))
+    fmt.Fprintf(&unit, "After=%s\n", strings.Join(append([]string{"network-online.target"}, u.After...), " "))
+    unit.WriteString("Wants=network-online.target\n")
+    unit.WriteString("\n[Service]\n")
+    unit.WriteString("Type=simple\n")
+    if user != "" {
+        fmt.Fprintf(&unit, "User=%s\n", user)
+    }
+    for _, variable := range u.Environment {
+        fmt.Fprintf(&unit, "Environment=\"%s\"\n", escapeSpecifiers(strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(variable)))
+    }
+    fmt.Fprintf(&unit, "ExecStartPre=/bin/mkdir -p %s\n", escapeCommand(quoteArg(u.DataPath)))
+    fmt.Fprintf(&unit, "ExecStart=%s\n", escapeCommand(u.ExecStart))
+    unit.WriteString("Restart=always\n")
+    unit.WriteString("RestartSec=5\n")
+    fmt.Fprintf(&unit, "TimeoutStopSec=%d\n", u.StopTimeout)
+    unit.WriteString("\n[Install]\n")
+    unit.WriteString("WantedBy=multi-user.target\n")
+    return unit.String()
+}
+
+
+// Get a command from its override or default
+func getCommand(override, defaultCommand string) string {
+    if override != "" {
+        return override
+    }
+    return defaultCommand
+}
+
+
+// Quote a command line argument for systemd if it contains spaces, quotes or backslashes
+func quoteArg(arg string) string {
+    if !strings.ContainsAny(arg, " \t\"'\\") {
+        return arg
+    }
+    return "\"" + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + "\""
+}
+
+
+// Escape systemd specifiers in a unit file value
+func escapeSpecifiers(value string) string {
+    return strings.ReplaceAll(value, "%", "%%")
+}
+
+
+// Escape systemd specifiers and variable expansion in a command line
+func escapeCommand(command string) string {
+    return strings.ReplaceAll(escapeSpecifiers(command), "$", "$$")
+}
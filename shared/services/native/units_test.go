@@ -0,0 +1,201 @@
+package native
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rocket-pool/smartnode/shared/services/config"
+)
+
+// Native settings with a daemon and data path containing spaces, and clients with native commands
+const testNativeConfig = `
+smartnode:
+  projectName: rocketpool
+chains:
+  eth1:
+    client:
+      selected: geth
+      options:
+      - id: geth
+        name: Geth
+        nativeCommand: geth --datadir "${DATA_DIR}" --cache ${GETH_CACHE}
+  eth2:
+    client:
+      selected: lighthouse
+      options:
+      - id: lighthouse
+        name: Lighthouse
+        nativeCommand: lighthouse bn --datadir "${DATA_DIR}"
+        nativeValidatorCommand: lighthouse vc --datadir "${DATA_DIR}" --graffiti "${GRAFFITI}"
+      - id: nimbus
+        name: Nimbus
+        nativeCommand: nimbus --data-dir="${DATA_DIR}"
+native:
+  daemonPath: /opt/rocket pool/rocketpool
+  dataPath: /var/lib/rocket pool
+`
+
+
+// Parse the test native config, with any overrides applied
+func parseTestNativeConfig(t *testing.T, override func(cfg *config.RocketPoolConfig)) config.RocketPoolConfig {
+    cfg, err := config.Parse([]byte(testNativeConfig))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if override != nil {
+        override(&cfg)
+    }
+    return cfg
+}
+
+
+func TestGetUnits(t *testing.T) {
+    cfg := parseTestNativeConfig(t, nil)
+    units, err := GetUnits(cfg, "/home/node/.rocketpool", []string{"GETH_CACHE=1024", "GRAFFITI=100% $RPL"})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // Check units
+    expected := []struct {
+        service string
+        execStart string
+        after []string
+    }{
+        {ServiceEth1, `geth --datadir "/var/lib/rocket pool/eth1" --cache 1024`, []string{}},
+        {ServiceEth2, `lighthouse bn --datadir "/var/lib/rocket pool/eth2"`, []string{"rocketpool-eth1.service"}},
+        {ServiceValidator, `lighthouse vc --datadir "/var/lib/rocket pool/validator" --graffiti "100% $RPL"`, []string{"rocketpool-eth2.service"}},
+        {ServiceNode, `"/opt/rocket pool/rocketpool" --config /home/node/.rocketpool/config.yml --settings /home/node/.rocketpool/settings.yml node`, []string{"rocketpool-eth1.service", "rocketpool-eth2.service"}},
+        {ServiceWatchtower, `"/opt/rocket pool/rocketpool" --config /home/node/.rocketpool/config.yml --settings /home/node/.rocketpool/settings.yml watchtower`, []string{"rocketpool-eth1.service", "rocketpool-eth2.service"}},
+    }
+    if len(units) != len(expected) {
+        t.Fatalf("got %d units, expected %d", len(units), len(expected))
+    }
+    for ui, unit := range units {
+        e := expected[ui]
+        if unit.Service != e.service || unit.Name != "rocketpool-" + e.service + ".service" {
+            t.Errorf("unit %d is %s (%s), expected %s", ui, unit.Service, unit.Name, e.service)
+            continue
+        }
+        if unit.ExecStart != e.execStart {
+            t.Errorf("%s command is %s, expected %s", unit.Service, unit.ExecStart, e.execStart)
+        }
+        if !reflect.DeepEqual(unit.After, e.after) {
+            t.Errorf("%s starts after %v, expected %v", unit.Service, unit.After, e.after)
+        }
+        if unit.DataPath != "/var/lib/rocket pool/" + e.service {
+            t.Errorf("%s data path is %s", unit.Service, unit.DataPath)
+        }
+    }
+    if units[0].StopTimeout != ClientStopTimeout || units[3].StopTimeout != DaemonStopTimeout {
+        t.Errorf("stop timeouts are %d and %d, expected %d and %d", units[0].StopTimeout, units[3].StopTimeout, ClientStopTimeout, DaemonStopTimeout)
+    }
+
+    // Single process clients have no validator unit, and command overrides are used over client commands
+    cfg = parseTestNativeConfig(t, func(cfg *config.RocketPoolConfig) {
+        cfg.Chains.Eth2.Client.Selected = "nimbus"
+        cfg.Native.Eth1Command = "/usr/local/bin/geth"
+    })
+    units, err = GetUnits(cfg, "/home/node/.rocketpool", nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+    services := []string{}
+    for _, unit := range units {
+        services = append(services, unit.Service)
+    }
+    if expected := []string{ServiceEth1, ServiceEth2, ServiceNode, ServiceWatchtower}; !reflect.DeepEqual(services, expected) {
+        t.Errorf("services are %v, expected %v", services, expected)
+    }
+    if units[0].ExecStart != "/usr/local/bin/geth" {
+        t.Errorf("eth1 command is %s, expected the override", units[0].ExecStart)
+    }
+    if service := GetValidatorService(cfg); service != ServiceEth2 {
+        t.Errorf("validator service is %s, expected %s", service, ServiceEth2)
+    }
+
+}
+
+
+func TestGetUnitsErrors(t *testing.T) {
+    tests := []struct {
+        name string
+        override func(cfg *config.RocketPoolConfig)
+        errorContains string
+    }{
+        {"no daemon path", func(cfg *config.RocketPoolConfig) { cfg.Native.DaemonPath = "" }, "path to the Rocket Pool daemon is not set"},
+        {"no data path", func(cfg *config.RocketPoolConfig) { cfg.Native.DataPath = "" }, "native data path is not set"},
+        {"no eth1 client", func(cfg *config.RocketPoolConfig) { cfg.Chains.Eth1.Client.Selected = "" }, "No Eth 1.0 client selected"},
+        {"no eth2 command", func(cfg *config.RocketPoolConfig) { cfg.Chains.Eth2.Client.Options[1].NativeCommand = ""; cfg.Chains.Eth2.Client.Selected = "nimbus" }, "[Nimbus] does not support native mode"},
+        {"invalid command", func(cfg *config.RocketPoolConfig) { cfg.Native.Eth1Command = "geth ${DATA_DIR" }, "Could not interpolate the eth1 command"},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            _, err := GetUnits(parseTestNativeConfig(t, test.override), "/home/node/.rocketpool", nil)
+            if err == nil || !strings.Contains(err.Error(), test.errorContains) {
+                t.Fatalf("expected an error containing '%s', got %v", test.errorContains, err)
+            }
+        })
+    }
+}
+
+
+func TestUnitRender(t *testing.T) {
+    unit := &Unit{
+        Service: ServiceValidator,
+        Name: "rocketpool-validator.service",
+        Description: "Validator (100% uptime)",
+        ExecStart: `lighthouse vc --datadir "/var/lib/rocket pool/validator" --graffiti "100% $RPL"`,
+        Environment: []string{"DATA_DIR=/var/lib/rocket pool/validator", `GRAFFITI=say "hi" \o/ 100%`},
+        DataPath: "/var/lib/rocket pool/validator",
+        After: []string{"rocketpool-eth2.service"},
+        StopTimeout: 180,
+    }
+    rendered := unit.Render("rp")
+    for _, line := range []string{
+        "Description=Validator (100%% uptime)",
+        "After=network-online.target rocketpool-eth2.service",
+        "User=rp",
+        `Environment="DATA_DIR=/var/lib/rocket pool/validator"`,
+        `Environment="GRAFFITI=say \"hi\" \\o/ 100%%"`,
+        `ExecStartPre=/bin/mkdir -p "/var/lib/rocket pool/validator"`,
+        `ExecStart=lighthouse vc --datadir "/var/lib/rocket pool/validator" --graffiti "100%% $$RPL"`,
+        "TimeoutStopSec=180",
+    } {
+        if !strings.Contains(rendered, line + "\n") {
+            t.Errorf("unit is missing line %s:\n%s", line, rendered)
+        }
+    }
+    if strings.Contains(unit.Render(""), "User=") {
+        t.Error("unit without a user sets one")
+    }
+}
+
+
+func TestEscapeCommand(t *testing.T) {
+    tests := []struct {
+        command string
+        expected string
+    }{
+        {"geth --http", "geth --http"},
+        {"echo 50%", "echo 50%%"},
+        {"echo $HOME ${HOME}", "echo $$HOME $${HOME}"},
+        {"echo %h $$", "echo %%h $$$$"},
+    }
+    for _, test := range tests {
+        if escaped := escapeCommand(test.command); escaped != test.expected {
+            t.Errorf("escaped '%s' to '%s', expected '%s'", test.command, escaped, test.expected)
+        }
+    }
+    for arg, expected := range map[string]string{
+        "/var/lib/rp": "/var/lib/rp",
+        "/var/lib/rocket pool": `"/var/lib/rocket pool"`,
+        `/var/lib/"rp"`: `"/var/lib/\"rp\""`,
+        `C:\rp`: `"C:\\rp"`,
+    } {
+        if quoted := quoteArg(arg); quoted != expected {
+            t.Errorf("quoted '%s' as '%s', expected '%s'", arg, quoted, expected)
+        }
+    }
+}
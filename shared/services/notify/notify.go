@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// Config
+const RequestTimeout = 10 * time.Second
+
+
+// Sends operator notifications to a webhook
+// The message is posted as a JSON object with a "text" field, which is accepted by Slack-compatible webhooks
+type Notifier struct {
+    url string
+    client *http.Client
+}
+
+
+// Webhook request body
+type message struct {
+    Text string `json:"text"`
+}
+
+
+// Create new notifier; notifications are discarded if no webhook URL is set
+func NewNotifier(url string) *Notifier {
+    return &Notifier{
+        url: url,
+        client: &http.Client{Timeout: RequestTimeout},
+    }
+}
+
+
+// Check whether notifications are sent
+func (n *Notifier) IsEnabled() bool {
+    return n.url != ""
+}
+
+
+// Send a notification
+func (n *Notifier) Notify(format string, args ...interface{}) error {
+
+    // Check if enabled
+    if !n.IsEnabled() {
+        return nil
+    }
+
+    // Encode message
+    body, err := json.Marshal(message{Text: fmt.Sprintf(format, args...)})
+    if err != nil {
+        return fmt.Errorf("Could not encode notification: %w", err)
+    }
+
+    // Send message
+    response, err := n.client.Post(n.url, "application/json", bytes.NewReader(body))
+    if err != nil {
+        return fmt.Errorf("Could not send notification: %w", err)
+    }
+    defer response.Body.Close()
+    if response.StatusCode < 200 || response.StatusCode >= 300 {
+        responseBody, _ := ioutil.ReadAll(response.Body)
+        return fmt.Errorf("Could not send notification: webhook returned status %d: %s", response.StatusCode, string(responseBody))
+    }
+
+    // Return
+    return nil
+
+}
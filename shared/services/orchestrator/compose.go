@@ -0,0 +1,371 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Unknown key errors from strict YAML parsing
+var unknownKeyRegex = regexp.MustCompile(`^line (\d+): field (\S+) not found`)
+
+
+// A compose file; only the options used by the Rocket Pool compose definitions are supported
+type composeFile struct {
+    Version string                              `yaml:"version"`
+    Services map[string]*composeService         `yaml:"services"`
+    Networks map[string]*composeResource        `yaml:"networks"`
+    Volumes map[string]*composeResource         `yaml:"volumes"`
+}
+
+
+// A compose network or volume definition
+type composeResource struct {
+    Name string                                 `yaml:"name"`
+    Driver string                               `yaml:"driver"`
+    DriverOpts map[string]string                `yaml:"driver_opts"`
+    External bool                               `yaml:"external"`
+}
+
+
+// A compose service definition
+type composeService struct {
+    Image string                                `yaml:"image"`
+    ContainerName string                        `yaml:"container_name"`
+    Restart string                              `yaml:"restart"`
+    StopSignal string                           `yaml:"stop_signal"`
+    StopGracePeriod string                      `yaml:"stop_grace_period"`
+    User string                                 `yaml:"user"`
+    Pid string                                  `yaml:"pid"`
+    NetworkMode string                          `yaml:"network_mode"`
+    Entrypoint *stringOrList                    `yaml:"entrypoint"`
+    Command *stringOrList                       `yaml:"command"`
+    Environment listOrMap                       `yaml:"environment"`
+    Labels listOrMap                            `yaml:"labels"`
+    Volumes []string                            `yaml:"volumes"`
+    Ports []string                              `yaml:"ports"`
+    Expose []string                             `yaml:"expose"`
+    Networks serviceNetworks                    `yaml:"networks"`
+    DependsOn dependsOn                         `yaml:"depends_on"`
+    CapAdd []string                             `yaml:"cap_add"`
+    CapDrop []string                            `yaml:"cap_drop"`
+    SecurityOpt []string                        `yaml:"security_opt"`
+    ExtraHosts []string                         `yaml:"extra_hosts"`
+    Healthcheck *composeHealthcheck             `yaml:"healthcheck"`
+    Logging *composeLogging                     `yaml:"logging"`
+}
+
+
+// A compose service healthcheck
+type composeHealthcheck struct {
+    Test *stringOrList                          `yaml:"test"`
+    Interval string                             `yaml:"interval"`
+    Timeout string                              `yaml:"timeout"`
+    StartPeriod string                          `yaml:"start_period"`
+    Retries int                                 `yaml:"retries"`
+    Disable bool                                `yaml:"disable"`
+}
+
+
+// A compose service logging config
+type composeLogging struct {
+    Driver string                               `yaml:"driver"`
+    Options map[string]string                   `yaml:"options"`
+}
+
+
+// A value which may be a string (in shell form) or a list
+type stringOrList struct {
+    Shell string
+    List []string
+    IsList bool
+}
+func (s *stringOrList) UnmarshalYAML(unmarshal func(interface{}) error) error {
+    if err := unmarshal(&(s.List)); err == nil {
+        s.IsList = true
+        return nil
+    }
+    return unmarshal(&(s.Shell))
+}
+
+
+// A list of KEY=VALUE strings, which may be defined as a list or a map
+type listOrMap []string
+func (l *listOrMap) UnmarshalYAML(unmarshal func(interface{}) error) error {
+    var list []string
+    if err := unmarshal(&list); err == nil {
+        *l = list
+        return nil
+    }
+    var values map[string]*string
+    if err := unmarshal(&values); err != nil {
+        return err
+    }
+    list = []string{}
+    for key, value := range values {
+        if value == nil {
+            list = append(list, key)
+        } else {
+            list = append(list, fmt.Sprintf("%s=%s", key, *value))
+        }
+    }
+    sort.Strings(list)
+    *l = list
+    return nil
+}
+
+
+// The networks a service is attached to, which may be defined as a list or a map with aliases
+type serviceNetworks struct {
+    Names []string
+    Aliases map[string][]string
+}
+func (n *serviceNetworks) UnmarshalYAML(unmarshal func(interface{}) error) error {
+    n.Aliases = map[string][]string{}
+    if err := unmarshal(&(n.Names)); err == nil {
+        return nil
+    }
+    var networks map[string]*struct {
+        Aliases []string `yaml:"aliases"`
+    }
+    if err := unmarshal(&networks); err != nil {
+        return err
+    }
+    n.Names = []string{}
+    for name, network := range networks {
+        n.Names = append(n.Names, name)
+        if network != nil {
+            n.Aliases[name] = network.Aliases
+        }
+    }
+    sort.Strings(n.Names)
+    return nil
+}
+
+
+// The services a service depends on, which may be defined as a list or a map with start conditions
+type dependsOn map[string]string
+func (d *dependsOn) UnmarshalYAML(unmarshal func(interface{}) error) error {
+    *d = dependsOn{}
+    var names []string
+    if err := unmarshal(&names); err == nil {
+        for _, name := range names {
+            (*d)[name] = ConditionStarted
+        }
+        return nil
+    }
+    var conditions map[string]*struct {
+        Condition string `yaml:"condition"`
+    }
+    if err := unmarshal(&conditions); err != nil {
+        return err
+    }
+    for name, condition := range conditions {
+        if condition == nil || condition.Condition == "" {
+            (*d)[name] = ConditionStarted
+        } else {
+            (*d)[name] = condition.Condition
+        }
+    }
+    return nil
+}
+
+
+// Parse a compose file, interpolating environment variables in its values
+// Returns warnings for unsupported options, which are ignored
+func parseComposeFile(file File, env []string) (*composeFile, []string, error) {
+
+    // Parse the raw definitions
+    var raw interface{}
+    if err := yaml.Unmarshal(file.Contents, &raw); err != nil {
+        return nil, nil, fmt.Errorf("Could not parse compose file %s: %w", file.Path, err)
+    }
+
+    // Interpolate values
+    interpolated, err := interpolate(raw, env)
+    if err != nil {
+        return nil, nil, fmt.Errorf("Could not interpolate compose file %s: %w", file.Path, err)
+    }
+    interpolatedBytes, err := yaml.Marshal(interpolated)
+    if err != nil {
+        return nil, nil, fmt.Errorf("Could not interpolate compose file %s: %w", file.Path, err)
+    }
+
+    // Decode
+    var compose composeFile
+    if err := yaml.Unmarshal(interpolatedBytes, &compose); err != nil {
+        return nil, nil, fmt.Errorf("Could not load compose file %s: %w", file.Path, err)
+    }
+
+    // Check for unsupported options; line numbers are omitted as they refer to the interpolated file
+    warnings := []string{}
+    var strictCompose composeFile
+    if err := yaml.UnmarshalStrict(interpolatedBytes, &strictCompose); err != nil {
+        if typeErr, ok := err.(*yaml.TypeError); ok {
+            for _, keyErr := range typeErr.Errors {
+                if match := unknownKeyRegex.FindStringSubmatch(keyErr); match != nil {
+                    warnings = append(warnings, fmt.Sprintf("Unsupported option '%s' in compose file %s will be ignored", match[2], file.Path))
+                }
+            }
+        }
+    }
+    return &compose, warnings, nil
+
+}
+
+
+// Merge a compose file into another, as docker-compose does when multiple files are specified
+// Scalar options are replaced, list options are extended, and key-value options are merged by key
+func mergeComposeFiles(base, override *composeFile) {
+    if base.Services == nil { base.Services = map[string]*composeService{} }
+    if base.Networks == nil { base.Networks = map[string]*composeResource{} }
+    if base.Volumes == nil { base.Volumes = map[string]*composeResource{} }
+    for name, service := range override.Services {
+        if existing, ok := base.Services[name]; ok && existing != nil && service != nil {
+            mergeServices(existing, service)
+        } else {
+            base.Services[name] = service
+        }
+    }
+    for name, network := range override.Networks {
+        base.Networks[name] = network
+    }
+    for name, volume := range override.Volumes {
+        base.Volumes[name] = volume
+    }
+}
+
+
+// Merge a service definition into another
+func mergeServices(base, override *composeService) {
+    if override.Image != "" { base.Image = override.Image }
+    if override.ContainerName != "" { base.ContainerName = override.ContainerName }
+    if override.Restart != "" { base.Restart = override.Restart }
+    if override.StopSignal != "" { base.StopSignal = override.StopSignal }
+    if override.StopGracePeriod != "" { base.StopGracePeriod = override.StopGracePeriod }
+    if override.User != "" { base.User = override.User }
+    if override.Pid != "" { base.Pid = override.Pid }
+    if override.NetworkMode != "" { base.NetworkMode = override.NetworkMode }
+    if override.Entrypoint != nil { base.Entrypoint = override.Entrypoint }
+    if override.Command != nil { base.Command = override.Command }
+    if override.Healthcheck != nil { base.Healthcheck = override.Healthcheck }
+    if override.Logging != nil { base.Logging = override.Logging }
+    base.Environment = mergeKeyValues(base.Environment, override.Environment)
+    base.Labels = mergeKeyValues(base.Labels, override.Labels)
+    base.Volumes = mergeVolumes(base.Volumes, override.Volumes)
+    base.Ports = appendUnique(base.Ports, override.Ports...)
+    base.Expose = appendUnique(base.Expose, override.Expose...)
+    base.CapAdd = appendUnique(base.CapAdd, override.CapAdd...)
+    base.CapDrop = appendUnique(base.CapDrop, override.CapDrop...)
+    base.SecurityOpt = appendUnique(base.SecurityOpt, override.SecurityOpt...)
+    base.ExtraHosts = appendUnique(base.ExtraHosts, override.ExtraHosts...)
+    base.Networks.Names = appendUnique(base.Networks.Names, override.Networks.Names...)
+    if base.Networks.Aliases == nil { base.Networks.Aliases = map[string][]string{} }
+    for name, aliases := range override.Networks.Aliases {
+        base.Networks.Aliases[name] = aliases
+    }
+    if base.DependsOn == nil { base.DependsOn = dependsOn{} }
+    for name, condition := range override.DependsOn {
+        base.DependsOn[name] = condition
+    }
+}
+
+
+// Merge KEY=VALUE lists by key
+func mergeKeyValues(base, override listOrMap) listOrMap {
+    merged := listOrMap{}
+    overridden := map[string]bool{}
+    for _, value := range override {
+        overridden[strings.SplitN(value, "=", 2)[0]] = true
+    }
+    for _, value := range base {
+        if !overridden[strings.SplitN(value, "=", 2)[0]] {
+            merged = append(merged, value)
+        }
+    }
+    return append(merged, override...)
+}
+
+
+// Merge volume lists by container path
+func mergeVolumes(base, override []string) []string {
+    merged := []string{}
+    overridden := map[string]bool{}
+    for _, volume := range override {
+        overridden[getVolumeTarget(volume)] = true
+    }
+    for _, volume := range base {
+        if !overridden[getVolumeTarget(volume)] {
+            merged = append(merged, volume)
+        }
+    }
+    return append(merged, override...)
+}
+
+
+// Append values to a list if they aren't already present
+func appendUnique(values []string, newValues ...string) []string {
+    for _, newValue := range newValues {
+        exists := false
+        for _, value := range values {
+            if value == newValue {
+                exists = true
+                break
+            }
+        }
+        if !exists {
+            values = append(values, newValue)
+        }
+    }
+    return values
+}
+
+
+// Split a shell form command into its arguments
+// Supports single quotes, double quotes and backslash escapes
+func splitCommand(command string) ([]string, error) {
+    args := []string{}
+    var current strings.Builder
+    inArg := false
+    var quote rune
+    escaped := false
+    for _, r := range command {
+        switch {
+            case escaped:
+                current.WriteRune(r)
+                escaped = false
+            case r == '\\' && quote != '\'':
+                escaped = true
+                inArg = true
+            case quote != 0:
+                if r == quote {
+                    quote = 0
+                } else {
+                    current.WriteRune(r)
+                }
+            case r == '\'' || r == '"':
+                quote = r
+                inArg = true
+            case r == ' ' || r == '\t' || r == '\n':
+                if inArg {
+                    args = append(args, current.String())
+                    current.Reset()
+                    inArg = false
+                }
+            default:
+                current.WriteRune(r)
+                inArg = true
+        }
+    }
+    if quote != 0 || escaped {
+        return nil, errors.New("unterminated quote or escape")
+    }
+    if inArg {
+        args = append(args, current.String())
+    }
+    return args, nil
+}
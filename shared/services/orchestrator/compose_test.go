@@ -0,0 +1,72 @@
+package orchestrator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+
+func TestParseComposeFile(t *testing.T) {
+    tests := []struct {
+        name string
+        contents string
+        env []string
+        expectedImage string
+        expectedWarnings []string
+        errorContains string
+    }{
+        {
+            name: "supported options",
+            contents: "version: \"3.4\"\nservices:\n  eth1:\n    image: ${IMAGE}\n    restart: unless-stopped\n",
+            env: []string{"IMAGE=ethereum/client-go:v1"},
+            expectedImage: "ethereum/client-go:v1",
+            expectedWarnings: []string{},
+        },
+        {
+            name: "unsupported service option",
+            contents: "version: \"3.4\"\nservices:\n  eth1:\n    image: ethereum/client-go:v1\n    mem_limit: 4g\n",
+            expectedImage: "ethereum/client-go:v1",
+            expectedWarnings: []string{"Unsupported option 'mem_limit' in compose file docker-compose.yml will be ignored"},
+        },
+        {
+            name: "unsupported top-level and resource options",
+            contents: "version: \"3.4\"\nx-logging: {}\nservices:\n  eth1:\n    image: ethereum/client-go:v1\nnetworks:\n  net:\n    ipam: {}\n",
+            expectedImage: "ethereum/client-go:v1",
+            expectedWarnings: []string{
+                "Unsupported option 'ipam' in compose file docker-compose.yml will be ignored",
+                "Unsupported option 'x-logging' in compose file docker-compose.yml will be ignored",
+            },
+        },
+        {
+            name: "invalid value",
+            contents: "version: \"3.4\"\nservices:\n  eth1:\n    image: ethereum/client-go:v1\n    ports: 8545\n",
+            errorContains: "Could not load compose file docker-compose.yml",
+        },
+        {
+            name: "invalid YAML",
+            contents: "services: [",
+            errorContains: "Could not parse compose file docker-compose.yml",
+        },
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            compose, warnings, err := parseComposeFile(File{Path: "docker-compose.yml", Contents: []byte(test.contents)}, test.env)
+            if test.errorContains != "" {
+                if err == nil || !strings.Contains(err.Error(), test.errorContains) {
+                    t.Fatalf("expected an error containing '%s', got %v", test.errorContains, err)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            if image := compose.Services["eth1"].Image; image != test.expectedImage {
+                t.Errorf("image is %s, expected %s", image, test.expectedImage)
+            }
+            if !reflect.DeepEqual(warnings, test.expectedWarnings) {
+                t.Errorf("warnings are %q, expected %q", warnings, test.expectedWarnings)
+            }
+        })
+    }
+}
@@ -0,0 +1,311 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+)
+
+
+// A container on the fake Docker daemon
+type fakeContainer struct {
+    id string
+    name string
+    config *container.Config
+    running bool
+    health string
+    networks []string
+}
+
+
+// A fake Docker Engine API, serving the endpoints used by the orchestrator from in-memory state
+// Each state-changing request is recorded as an action, e.g. "create rp_eth1"
+type fakeDocker struct {
+    lock sync.Mutex
+    images map[string]bool
+    containers []*fakeContainer
+    networks map[string]bool
+    volumes map[string]bool
+    pullError string
+    actions []string
+    nextId int
+}
+
+
+// Create a fake Docker daemon and a Docker client connected to it; the server must be closed after use
+func newFakeDocker(t *testing.T) (*fakeDocker, *client.Client, *httptest.Server) {
+    docker := &fakeDocker{
+        images: map[string]bool{},
+        networks: map[string]bool{},
+        volumes: map[string]bool{},
+    }
+    server := httptest.NewServer(http.HandlerFunc(docker.serve))
+    dockerClient, err := client.NewClientWithOpts(
+        client.WithHost("tcp://" + strings.TrimPrefix(server.URL, "http://")),
+        client.WithVersion(APIVersion),
+        client.WithHTTPClient(server.Client()),
+    )
+    if err != nil {
+        server.Close()
+        t.Fatal(err)
+    }
+    return docker, dockerClient, server
+}
+
+
+// Get and clear the recorded actions
+func (d *fakeDocker) takeActions() []string {
+    d.lock.Lock()
+    defer d.lock.Unlock()
+    actions := d.actions
+    d.actions = nil
+    return actions
+}
+
+
+// Get a container by name or ID
+func (d *fakeDocker) getContainer(nameOrId string) *fakeContainer {
+    for _, c := range d.containers {
+        if c.id == nameOrId || c.name == nameOrId {
+            return c
+        }
+    }
+    return nil
+}
+
+
+// Serve a Docker Engine API request
+func (d *fakeDocker) serve(w http.ResponseWriter, r *http.Request) {
+    d.lock.Lock()
+    defer d.lock.Unlock()
+    path := strings.TrimPrefix(r.URL.Path, "/v" + APIVersion)
+    parts := strings.Split(strings.Trim(path, "/"), "/")
+    switch {
+
+        // Images
+        case r.Method == http.MethodGet && parts[0] == "images" && parts[len(parts) - 1] == "json":
+            image := strings.Join(parts[1:len(parts) - 1], "/")
+            if !d.images[image] {
+                writeError(w, http.StatusNotFound, "No such image: " + image)
+                return
+            }
+            writeJSON(w, types.ImageInspect{ID: "sha256:" + image, RepoTags: []string{image}})
+        case r.Method == http.MethodPost && path == "/images/create":
+            image := r.URL.Query().Get("fromImage") + ":" + r.URL.Query().Get("tag")
+            d.actions = append(d.actions, "pull " + image)
+            w.Header().Set("Content-Type", "application/json")
+            encoder := json.NewEncoder(w)
+            _ = encoder.Encode(map[string]interface{}{"status": "Pulling fs layer", "id": "layer1"})
+            if d.pullError != "" {
+                _ = encoder.Encode(map[string]interface{}{"error": d.pullError, "errorDetail": map[string]string{"message": d.pullError}})
+                return
+            }
+            _ = encoder.Encode(map[string]interface{}{"status": "Downloading", "id": "layer1", "progressDetail": map[string]int64{"current": 50, "total": 100}})
+            _ = encoder.Encode(map[string]interface{}{"status": "Pull complete", "id": "layer1"})
+            d.images[image] = true
+
+        // Networks
+        case r.Method == http.MethodGet && path == "/networks":
+            networks := []types.NetworkResource{}
+            for _, name := range getSortedNames(d.networks) {
+                networks = append(networks, types.NetworkResource{Name: name, ID: name})
+            }
+            writeJSON(w, networks)
+        case r.Method == http.MethodPost && path == "/networks/create":
+            var request types.NetworkCreateRequest
+            if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+                writeError(w, http.StatusBadRequest, err.Error())
+                return
+            }
+            d.networks[request.Name] = true
+            d.actions = append(d.actions, "create network " + request.Name)
+            writeJSON(w, types.NetworkCreateResponse{ID: request.Name})
+        case r.Method == http.MethodPost && parts[0] == "networks" && len(parts) == 3 && parts[2] == "connect":
+            var request types.NetworkConnect
+            if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+                writeError(w, http.StatusBadRequest, err.Error())
+                return
+            }
+            c := d.getContainer(request.Container)
+            if c == nil || !d.networks[parts[1]] {
+                writeError(w, http.StatusNotFound, "No such container or network")
+                return
+            }
+            c.networks = append(c.networks, parts[1])
+            d.actions = append(d.actions, fmt.Sprintf("connect %s to %s", c.name, parts[1]))
+        case r.Method == http.MethodDelete && parts[0] == "networks" && len(parts) == 2:
+            if !d.networks[parts[1]] {
+                writeError(w, http.StatusNotFound, "No such network: " + parts[1])
+                return
+            }
+            delete(d.networks, parts[1])
+            d.actions = append(d.actions, "remove network " + parts[1])
+
+        // Volumes
+        case r.Method == http.MethodGet && path == "/volumes":
+            volumes := volumetypes.VolumeListOKBody{Volumes: []*types.Volume{}}
+            for _, name := range getSortedNames(d.volumes) {
+                volumes.Volumes = append(volumes.Volumes, &types.Volume{Name: name})
+            }
+            writeJSON(w, volumes)
+        case r.Method == http.MethodPost && path == "/volumes/create":
+            var request volumetypes.VolumeCreateBody
+            if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+                writeError(w, http.StatusBadRequest, err.Error())
+                return
+            }
+            d.volumes[request.Name] = true
+            d.actions = append(d.actions, "create volume " + request.Name)
+            writeJSON(w, types.Volume{Name: request.Name})
+        case r.Method == http.MethodDelete && parts[0] == "volumes" && len(parts) == 2:
+            if !d.volumes[parts[1]] {
+                writeError(w, http.StatusNotFound, "No such volume: " + parts[1])
+                return
+            }
+            delete(d.volumes, parts[1])
+            d.actions = append(d.actions, "remove volume " + parts[1])
+
+        // Containers
+        case r.Method == http.MethodGet && path == "/containers/json":
+            var filterArgs map[string]map[string]bool
+            if filterParam := r.URL.Query().Get("filters"); filterParam != "" {
+                if err := json.Unmarshal([]byte(filterParam), &filterArgs); err != nil {
+                    writeError(w, http.StatusBadRequest, err.Error())
+                    return
+                }
+            }
+            containers := []types.Container{}
+            for _, c := range d.containers {
+                matches := true
+                for label := range filterArgs["label"] {
+                    keyValue := strings.SplitN(label, "=", 2)
+                    if c.config.Labels[keyValue[0]] != keyValue[1] {
+                        matches = false
+                    }
+                }
+                if !matches { continue }
+                state := "exited"
+                if c.running { state = "running" }
+                containers = append(containers, types.Container{ID: c.id, Names: []string{"/" + c.name}, Image: c.config.Image, Labels: c.config.Labels, State: state})
+            }
+            writeJSON(w, containers)
+        case r.Method == http.MethodPost && path == "/containers/create":
+            var request struct {
+                *container.Config
+                HostConfig *container.HostConfig
+                NetworkingConfig *network.NetworkingConfig
+            }
+            if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+                writeError(w, http.StatusBadRequest, err.Error())
+                return
+            }
+            name := r.URL.Query().Get("name")
+            if d.getContainer(name) != nil {
+                writeError(w, http.StatusConflict, "Conflict. The container name is already in use: " + name)
+                return
+            }
+            if !d.images[request.Config.Image] {
+                writeError(w, http.StatusNotFound, "No such image: " + request.Config.Image)
+                return
+            }
+            d.nextId++
+            c := &fakeContainer{id: fmt.Sprintf("container%d", d.nextId), name: name, config: request.Config}
+            if request.NetworkingConfig != nil {
+                for networkName := range request.NetworkingConfig.EndpointsConfig {
+                    c.networks = append(c.networks, networkName)
+                }
+            }
+            d.containers = append(d.containers, c)
+            d.actions = append(d.actions, "create " + name)
+            writeJSON(w, container.ContainerCreateCreatedBody{ID: c.id})
+        case parts[0] == "containers" && len(parts) >= 2:
+            c := d.getContainer(parts[1])
+            if c == nil {
+                writeError(w, http.StatusNotFound, "No such container: " + parts[1])
+                return
+            }
+            switch {
+                case r.Method == http.MethodGet && len(parts) == 3 && parts[2] == "json":
+                    state := &types.ContainerState{Status: "exited", Running: c.running}
+                    if c.running { state.Status = "running" }
+                    if c.health != "" { state.Health = &types.Health{Status: c.health} }
+                    writeJSON(w, types.ContainerJSON{
+                        ContainerJSONBase: &types.ContainerJSONBase{ID: c.id, Name: "/" + c.name, State: state},
+                        Config: c.config,
+                    })
+                case r.Method == http.MethodPost && len(parts) == 3 && parts[2] == "start":
+                    c.running = true
+                    d.actions = append(d.actions, "start " + c.name)
+                    w.WriteHeader(http.StatusNoContent)
+                case r.Method == http.MethodPost && len(parts) == 3 && parts[2] == "stop":
+                    c.running = false
+                    d.actions = append(d.actions, "stop " + c.name)
+                    w.WriteHeader(http.StatusNoContent)
+                case r.Method == http.MethodDelete && len(parts) == 2:
+                    for ci, existing := range d.containers {
+                        if existing == c {
+                            d.containers = append(d.containers[:ci], d.containers[ci+1:]...)
+                            break
+                        }
+                    }
+                    d.actions = append(d.actions, "remove " + c.name)
+                    w.WriteHeader(http.StatusNoContent)
+                default:
+                    writeError(w, http.StatusNotFound, "page not found")
+            }
+
+        default:
+            writeError(w, http.StatusNotFound, "page not found")
+
+    }
+}
+
+
+// Write a JSON response
+func writeJSON(w http.ResponseWriter, value interface{}) {
+    w.Header().Set("Content-Type", "application/json")
+    _ = json.NewEncoder(w).Encode(value)
+}
+
+
+// Write a Docker Engine API error response
+func writeError(w http.ResponseWriter, status int, message string) {
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(status)
+    _ = json.NewEncoder(w).Encode(types.ErrorResponse{Message: message})
+}
+
+
+// Get the sorted names in a set
+func getSortedNames(set map[string]bool) []string {
+    names := []string{}
+    for name := range set {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+
+// Get the sorted names of a fake daemon's containers
+func (d *fakeDocker) containerNames() []string {
+    d.lock.Lock()
+    defer d.lock.Unlock()
+    names := []string{}
+    for _, c := range d.containers {
+        names = append(names, c.name)
+    }
+    sort.Strings(names)
+    return names
+}
@@ -0,0 +1,135 @@
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// Writes complete lines to a shared writer with a prefix identifying their source
+type prefixWriter struct {
+    out io.Writer
+    lock *sync.Mutex
+    prefix string
+    buffer []byte
+}
+
+
+// Write output, holding back any incomplete line until it is finished
+func (w *prefixWriter) Write(p []byte) (int, error) {
+    w.buffer = append(w.buffer, p...)
+    for {
+        newline := bytes.IndexByte(w.buffer, '\n')
+        if newline < 0 {
+            return len(p), nil
+        }
+        if err := w.writeLine(w.buffer[:newline + 1]); err != nil {
+            return 0, err
+        }
+        w.buffer = w.buffer[newline + 1:]
+    }
+}
+
+
+// Write any remaining incomplete line
+func (w *prefixWriter) Flush() error {
+    if len(w.buffer) == 0 {
+        return nil
+    }
+    line := append(w.buffer, '\n')
+    w.buffer = nil
+    return w.writeLine(line)
+}
+
+
+// Write a prefixed line
+func (w *prefixWriter) writeLine(line []byte) error {
+    w.lock.Lock()
+    defer w.lock.Unlock()
+    if _, err := io.WriteString(w.out, w.prefix); err != nil {
+        return err
+    }
+    _, err := w.out.Write(line)
+    return err
+}
+
+
+// Write service container logs to out, with each line prefixed by its service name
+// If follow is set, logs are streamed until the containers stop or the context is cancelled
+// Gets the logs for all services if no names are given
+func (o *Orchestrator) Logs(ctx context.Context, out io.Writer, tail string, follow bool, serviceNames ...string) error {
+
+    // Get services
+    services, err := o.project.GetServices(true, serviceNames...)
+    if err != nil {
+        return err
+    }
+    prefixLength := 0
+    for _, service := range services {
+        if len(service.Name) > prefixLength {
+            prefixLength = len(service.Name)
+        }
+    }
+
+    // Stream the logs for each container
+    var wg sync.WaitGroup
+    var lock sync.Mutex
+    errs := make(chan error, len(services))
+    for _, service := range services {
+
+        // Get container
+        state, err := InspectContainer(ctx, o.docker, service.ContainerName)
+        if err != nil {
+            return err
+        }
+        if state == nil { continue }
+
+        // Get logs
+        reader, err := o.docker.ContainerLogs(ctx, state.ID, types.ContainerLogsOptions{
+            ShowStdout: true,
+            ShowStderr: true,
+            Follow: follow,
+            Tail: tail,
+        })
+        if err != nil {
+            return fmt.Errorf("Could not get logs for container %s: %w", service.ContainerName, err)
+        }
+
+        // Copy logs; output is multiplexed unless the container has a TTY
+        writer := &prefixWriter{
+            out: out,
+            lock: &lock,
+            prefix: fmt.Sprintf("%-*s | ", prefixLength, service.Name),
+        }
+        tty := service.Config.Tty
+        wg.Add(1)
+        go func(reader io.ReadCloser, containerName string) {
+            defer wg.Done()
+            defer reader.Close()
+            var err error
+            if tty {
+                _, err = io.Copy(writer, reader)
+            } else {
+                _, err = stdcopy.StdCopy(writer, writer, reader)
+            }
+            if flushErr := writer.Flush(); err == nil {
+                err = flushErr
+            }
+            if err != nil && ctx.Err() == nil {
+                errs <- fmt.Errorf("Could not read logs for container %s: %w", containerName, err)
+            }
+        }(reader, service.ContainerName)
+
+    }
+
+    // Wait for logs to finish & return the first error
+    wg.Wait()
+    close(errs)
+    return <-errs
+
+}
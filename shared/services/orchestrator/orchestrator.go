@@ -0,0 +1,480 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+)
+
+// Docker Engine API version
+const APIVersion = "1.40"
+
+// Settings
+const (
+    HealthyTimeout = 5 * time.Minute
+    HealthPollInterval = 2 * time.Second
+)
+
+// Progress statuses
+const (
+    StatusPulling = "Pulling"
+    StatusCreating = "Creating"
+    StatusRecreating = "Recreating"
+    StatusStarting = "Starting"
+    StatusStarted = "Started"
+    StatusRunning = "Running"
+    StatusWaiting = "Waiting"
+    StatusHealthy = "Healthy"
+    StatusStopping = "Stopping"
+    StatusStopped = "Stopped"
+    StatusRemoving = "Removing"
+    StatusRemoved = "Removed"
+)
+
+
+// The Docker Engine API methods used by the orchestrator; implemented by the Docker client
+type DockerClient interface {
+    ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, containerName string) (container.ContainerCreateCreatedBody, error)
+    ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error)
+    ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error)
+    ContainerLogs(ctx context.Context, container string, options types.ContainerLogsOptions) (io.ReadCloser, error)
+    ContainerRemove(ctx context.Context, containerID string, options types.ContainerRemoveOptions) error
+    ContainerStart(ctx context.Context, containerID string, options types.ContainerStartOptions) error
+    ContainerStats(ctx context.Context, containerID string, stream bool) (types.ContainerStats, error)
+    ContainerStop(ctx context.Context, containerID string, timeout *time.Duration) error
+    ImageInspectWithRaw(ctx context.Context, imageID string) (types.ImageInspect, []byte, error)
+    ImagePull(ctx context.Context, refStr string, options types.ImagePullOptions) (io.ReadCloser, error)
+    NetworkConnect(ctx context.Context, networkID, containerID string, config *network.EndpointSettings) error
+    NetworkCreate(ctx context.Context, name string, options types.NetworkCreate) (types.NetworkCreateResponse, error)
+    NetworkList(ctx context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error)
+    NetworkRemove(ctx context.Context, networkID string) error
+    VolumeCreate(ctx context.Context, options volumetypes.VolumeCreateBody) (types.Volume, error)
+    VolumeList(ctx context.Context, filter filters.Args) (volumetypes.VolumeListOKBody, error)
+    VolumeRemove(ctx context.Context, volumeID string, force bool) error
+}
+
+
+// Progress of an operation on a service
+// Image pulls report the layer being pulled and its progress in bytes
+type Progress struct {
+    Service string
+    Status string
+    Image string
+    Layer string
+    Current int64
+    Total int64
+}
+
+
+// Options for starting services
+type UpOptions struct {
+    NoDeps bool
+    ForceRecreate bool
+    Progress func(Progress)
+}
+
+
+// Orchestrates a compose project's containers through the Docker Engine API
+type Orchestrator struct {
+    docker DockerClient
+    project *Project
+}
+
+
+// Create new orchestrator
+func NewOrchestrator(docker DockerClient, project *Project) *Orchestrator {
+    return &Orchestrator{
+        docker: docker,
+        project: project,
+    }
+}
+
+
+// Get the orchestrator's project
+func (o *Orchestrator) Project() *Project {
+    return o.project
+}
+
+
+// Create and start service containers, recreating containers whose definitions have changed
+// Starts all services if no names are given
+func (o *Orchestrator) Up(ctx context.Context, options UpOptions, serviceNames ...string) error {
+
+    // Get services
+    services, err := o.project.GetServices(options.NoDeps, serviceNames...)
+    if err != nil {
+        return err
+    }
+    progress := options.Progress
+    if progress == nil {
+        progress = func(Progress) {}
+    }
+
+    // Create networks & volumes
+    if err := o.createNetworks(ctx); err != nil {
+        return err
+    }
+    if err := o.createVolumes(ctx); err != nil {
+        return err
+    }
+
+    // Pull missing images
+    for _, service := range services {
+        if _, _, err := o.docker.ImageInspectWithRaw(ctx, service.Image); err == nil {
+            continue
+        } else if !client.IsErrNotFound(err) {
+            return fmt.Errorf("Could not inspect image %s: %w", service.Image, err)
+        }
+        if err := o.pullImage(ctx, service, progress); err != nil {
+            return err
+        }
+    }
+
+    // Start containers in dependency order
+    for _, service := range services {
+        if err := o.waitForDependencies(ctx, service, progress); err != nil {
+            return err
+        }
+        if err := o.startContainer(ctx, service, options.ForceRecreate, progress); err != nil {
+            return err
+        }
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Stop service containers in reverse dependency order
+// Stops all services if no names are given
+func (o *Orchestrator) Stop(ctx context.Context, progress func(Progress), serviceNames ...string) error {
+
+    // Get services
+    services, err := o.project.GetServices(true, serviceNames...)
+    if err != nil {
+        return err
+    }
+    if progress == nil {
+        progress = func(Progress) {}
+    }
+
+    // Stop containers
+    for si := len(services) - 1; si >= 0; si-- {
+        service := services[si]
+        state, err := InspectContainer(ctx, o.docker, service.ContainerName)
+        if err != nil {
+            return err
+        }
+        if state == nil || !state.Running {
+            continue
+        }
+        progress(Progress{Service: service.Name, Status: StatusStopping})
+        if err := o.docker.ContainerStop(ctx, state.ID, nil); err != nil {
+            return fmt.Errorf("Could not stop container %s: %w", service.ContainerName, err)
+        }
+        progress(Progress{Service: service.Name, Status: StatusStopped})
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Stop and remove all project containers and networks, and optionally its volumes
+func (o *Orchestrator) Down(ctx context.Context, removeVolumes bool, progress func(Progress)) error {
+
+    // Get project containers
+    if progress == nil {
+        progress = func(Progress) {}
+    }
+    containers, err := o.getProjectContainers(ctx)
+    if err != nil {
+        return err
+    }
+
+    // Order containers so services are removed in reverse dependency order, followed by containers no longer in the project
+    ordered := []types.Container{}
+    for si := len(o.project.Services) - 1; si >= 0; si-- {
+        for ci, projectContainer := range containers {
+            if projectContainer.Labels[ServiceLabel] == o.project.Services[si].Name {
+                ordered = append(ordered, projectContainer)
+                containers = append(containers[:ci], containers[ci+1:]...)
+                break
+            }
+        }
+    }
+    ordered = append(ordered, containers...)
+
+    // Stop & remove containers
+    for _, projectContainer := range ordered {
+        serviceName := projectContainer.Labels[ServiceLabel]
+        if projectContainer.State == "running" {
+            progress(Progress{Service: serviceName, Status: StatusStopping})
+            if err := o.docker.ContainerStop(ctx, projectContainer.ID, nil); err != nil {
+                return fmt.Errorf("Could not stop container %s: %w", getContainerName(projectContainer), err)
+            }
+        }
+        progress(Progress{Service: serviceName, Status: StatusRemoving})
+        if err := o.docker.ContainerRemove(ctx, projectContainer.ID, types.ContainerRemoveOptions{RemoveVolumes: removeVolumes}); err != nil && !client.IsErrNotFound(err) {
+            return fmt.Errorf("Could not remove container %s: %w", getContainerName(projectContainer), err)
+        }
+        progress(Progress{Service: serviceName, Status: StatusRemoved})
+    }
+
+    // Remove networks
+    for _, resource := range o.project.Networks {
+        if resource.External { continue }
+        if err := o.docker.NetworkRemove(ctx, resource.Name); err != nil && !client.IsErrNotFound(err) {
+            return fmt.Errorf("Could not remove network %s: %w", resource.Name, err)
+        }
+    }
+
+    // Remove volumes
+    if removeVolumes {
+        for _, resource := range o.project.Volumes {
+            if resource.External { continue }
+            if err := o.docker.VolumeRemove(ctx, resource.Name, false); err != nil && !client.IsErrNotFound(err) {
+                return fmt.Errorf("Could not remove volume %s: %w", resource.Name, err)
+            }
+        }
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Pull the images for services
+// Pulls the images for all services if no names are given
+func (o *Orchestrator) Pull(ctx context.Context, progress func(Progress), serviceNames ...string) error {
+    services, err := o.project.GetServices(true, serviceNames...)
+    if err != nil {
+        return err
+    }
+    if progress == nil {
+        progress = func(Progress) {}
+    }
+    for _, service := range services {
+        if err := o.pullImage(ctx, service, progress); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+
+// Create the project networks if they don't exist
+func (o *Orchestrator) createNetworks(ctx context.Context) error {
+    for _, resource := range o.project.Networks {
+        networks, err := o.docker.NetworkList(ctx, types.NetworkListOptions{Filters: filters.NewArgs(filters.Arg("name", resource.Name))})
+        if err != nil {
+            return fmt.Errorf("Could not get networks: %w", err)
+        }
+        exists := false
+        for _, existing := range networks {
+            if existing.Name == resource.Name {
+                exists = true
+                break
+            }
+        }
+        if exists { continue }
+        if resource.External {
+            return fmt.Errorf("External network %s does not exist", resource.Name)
+        }
+        if _, err := o.docker.NetworkCreate(ctx, resource.Name, types.NetworkCreate{
+            CheckDuplicate: true,
+            Driver: resource.Driver,
+            Options: resource.DriverOpts,
+            Labels: map[string]string{
+                ProjectLabel: o.project.Name,
+                NetworkLabel: resource.Key,
+            },
+        }); err != nil {
+            return fmt.Errorf("Could not create network %s: %w", resource.Name, err)
+        }
+    }
+    return nil
+}
+
+
+// Create the project volumes if they don't exist
+func (o *Orchestrator) createVolumes(ctx context.Context) error {
+    for _, resource := range o.project.Volumes {
+        volumes, err := o.docker.VolumeList(ctx, filters.NewArgs(filters.Arg("name", resource.Name)))
+        if err != nil {
+            return fmt.Errorf("Could not get volumes: %w", err)
+        }
+        exists := false
+        for _, existing := range volumes.Volumes {
+            if existing.Name == resource.Name {
+                exists = true
+                break
+            }
+        }
+        if exists { continue }
+        if resource.External {
+            return fmt.Errorf("External volume %s does not exist", resource.Name)
+        }
+        if _, err := o.docker.VolumeCreate(ctx, volumetypes.VolumeCreateBody{
+            Name: resource.Name,
+            Driver: resource.Driver,
+            DriverOpts: resource.DriverOpts,
+            Labels: map[string]string{
+                ProjectLabel: o.project.Name,
+                VolumeLabel: resource.Key,
+            },
+        }); err != nil {
+            return fmt.Errorf("Could not create volume %s: %w", resource.Name, err)
+        }
+    }
+    return nil
+}
+
+
+// Wait for a service's dependencies to meet their start conditions
+func (o *Orchestrator) waitForDependencies(ctx context.Context, service *Service, progress func(Progress)) error {
+    for _, dependencyName := range getSortedKeys(service.DependsOn) {
+        if service.DependsOn[dependencyName] != ConditionHealthy {
+            continue
+        }
+        dependency := o.project.GetService(dependencyName)
+        progress(Progress{Service: dependency.Name, Status: StatusWaiting})
+        if err := o.waitForHealthy(ctx, dependency); err != nil {
+            return fmt.Errorf("Could not start service %s: %w", service.Name, err)
+        }
+        progress(Progress{Service: dependency.Name, Status: StatusHealthy})
+    }
+    return nil
+}
+
+
+// Wait for a service container to pass its healthcheck
+func (o *Orchestrator) waitForHealthy(ctx context.Context, service *Service) error {
+    ctx, cancel := context.WithTimeout(ctx, HealthyTimeout)
+    defer cancel()
+    for {
+        state, err := InspectContainer(ctx, o.docker, service.ContainerName)
+        if err != nil {
+            return err
+        }
+        switch {
+            case state == nil:
+                return fmt.Errorf("Service %s has not been created", service.Name)
+            case !state.Running:
+                return fmt.Errorf("Service %s is not running (%s)", service.Name, state.Summary())
+            case state.Health == "":
+                return fmt.Errorf("Service %s has no healthcheck", service.Name)
+            case state.Health == HealthHealthy:
+                return nil
+            case state.Health == HealthUnhealthy:
+                return fmt.Errorf("Service %s is unhealthy: %s", service.Name, state.HealthLog)
+        }
+        select {
+            case <-ctx.Done():
+                return fmt.Errorf("Timed out waiting for service %s to become healthy", service.Name)
+            case <-time.After(HealthPollInterval):
+        }
+    }
+}
+
+
+// Start a service container, creating or recreating it if required
+func (o *Orchestrator) startContainer(ctx context.Context, service *Service, forceRecreate bool, progress func(Progress)) error {
+
+    // Get the existing container
+    state, err := InspectContainer(ctx, o.docker, service.ContainerName)
+    if err != nil {
+        return err
+    }
+
+    // Start the existing container if it is up to date
+    if state != nil && !forceRecreate && state.ConfigHash == service.ConfigHash {
+        if state.Running {
+            progress(Progress{Service: service.Name, Status: StatusRunning})
+            return nil
+        }
+        progress(Progress{Service: service.Name, Status: StatusStarting})
+        if err := o.docker.ContainerStart(ctx, state.ID, types.ContainerStartOptions{}); err != nil {
+            return fmt.Errorf("Could not start container %s: %w", service.ContainerName, err)
+        }
+        progress(Progress{Service: service.Name, Status: StatusStarted})
+        return nil
+    }
+
+    // Remove the existing container; it is stopped first so it can shut down gracefully
+    if state != nil {
+        progress(Progress{Service: service.Name, Status: StatusRecreating})
+        if state.Running {
+            if err := o.docker.ContainerStop(ctx, state.ID, nil); err != nil {
+                return fmt.Errorf("Could not stop container %s: %w", service.ContainerName, err)
+            }
+        }
+        if err := o.docker.ContainerRemove(ctx, state.ID, types.ContainerRemoveOptions{}); err != nil {
+            return fmt.Errorf("Could not remove container %s: %w", service.ContainerName, err)
+        }
+    } else {
+        progress(Progress{Service: service.Name, Status: StatusCreating})
+    }
+
+    // Create the container; it can only be attached to one network on creation
+    networkingConfig := &network.NetworkingConfig{EndpointsConfig: map[string]*network.EndpointSettings{}}
+    primaryNetwork := string(service.HostConfig.NetworkMode)
+    if settings, ok := service.Networks[primaryNetwork]; ok {
+        networkingConfig.EndpointsConfig[primaryNetwork] = settings
+    }
+    created, err := o.docker.ContainerCreate(ctx, service.Config, service.HostConfig, networkingConfig, service.ContainerName)
+    if err != nil {
+        return fmt.Errorf("Could not create container %s: %w", service.ContainerName, err)
+    }
+
+    // Attach the container to its other networks
+    for networkName, settings := range service.Networks {
+        if networkName == primaryNetwork { continue }
+        if err := o.docker.NetworkConnect(ctx, networkName, created.ID, settings); err != nil {
+            return fmt.Errorf("Could not connect container %s to network %s: %w", service.ContainerName, networkName, err)
+        }
+    }
+
+    // Start the container
+    progress(Progress{Service: service.Name, Status: StatusStarting})
+    if err := o.docker.ContainerStart(ctx, created.ID, types.ContainerStartOptions{}); err != nil {
+        return fmt.Errorf("Could not start container %s: %w", service.ContainerName, err)
+    }
+    progress(Progress{Service: service.Name, Status: StatusStarted})
+    return nil
+
+}
+
+
+// Get all containers belonging to the project, including containers for services which are no longer defined
+func (o *Orchestrator) getProjectContainers(ctx context.Context) ([]types.Container, error) {
+    containers, err := o.docker.ContainerList(ctx, types.ContainerListOptions{
+        All: true,
+        Filters: filters.NewArgs(filters.Arg("label", fmt.Sprintf("%s=%s", ProjectLabel, o.project.Name))),
+    })
+    if err != nil {
+        return nil, fmt.Errorf("Could not get containers for project %s: %w", o.project.Name, err)
+    }
+    return containers, nil
+}
+
+
+// Get the name of a listed container
+func getContainerName(listed types.Container) string {
+    if len(listed.Names) == 0 {
+        return listed.ID
+    }
+    return strings.TrimPrefix(listed.Names[0], "/")
+}
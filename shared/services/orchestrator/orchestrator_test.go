@@ -0,0 +1,218 @@
+package orchestrator
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Test compose file; the node service depends on eth1 and is attached to two networks, joining the first on creation
+const testComposeFile = `
+version: "3.4"
+services:
+  eth1:
+    image: ${ETH1_IMAGE}
+    container_name: ${COMPOSE_PROJECT_NAME}_eth1
+    networks:
+      - net
+    volumes:
+      - eth1clientdata:/ethclient
+  node:
+    image: rocketpool/smartnode:v1
+    container_name: ${COMPOSE_PROJECT_NAME}_node
+    networks:
+      - net
+      - metrics
+    depends_on:
+      - eth1
+networks:
+  net:
+  metrics:
+volumes:
+  eth1clientdata:
+`
+
+
+// Load the test project
+func loadTestProject(t *testing.T, eth1Image string) *Project {
+    project, err := LoadProject("rp", "/tmp", []File{{Path: "docker-compose.yml", Contents: []byte(testComposeFile)}}, []string{"COMPOSE_PROJECT_NAME=rp", "ETH1_IMAGE=" + eth1Image})
+    if err != nil {
+        t.Fatal(err)
+    }
+    return project
+}
+
+
+func TestOrchestratorUp(t *testing.T) {
+    tests := []struct {
+        name string
+        setup func(*fakeDocker, *Orchestrator) error
+        eth1Image string
+        options UpOptions
+        services []string
+        pullError string
+        expectedActions []string
+        errorContains string
+    }{
+        {
+            name: "create project",
+            eth1Image: "ethereum/client-go:v1",
+            expectedActions: []string{
+                "create network rp_metrics", "create network rp_net", "create volume rp_eth1clientdata",
+                "pull ethereum/client-go:v1", "pull rocketpool/smartnode:v1",
+                "create rp_eth1", "start rp_eth1",
+                "create rp_node", "connect rp_node to rp_metrics", "start rp_node",
+            },
+        },
+        {
+            name: "up to date",
+            setup: func(d *fakeDocker, o *Orchestrator) error { return o.Up(context.Background(), UpOptions{}) },
+            eth1Image: "ethereum/client-go:v1",
+            expectedActions: []string{},
+        },
+        {
+            name: "start stopped containers",
+            setup: func(d *fakeDocker, o *Orchestrator) error {
+                if err := o.Up(context.Background(), UpOptions{}); err != nil { return err }
+                return o.Stop(context.Background(), nil)
+            },
+            eth1Image: "ethereum/client-go:v1",
+            expectedActions: []string{"start rp_eth1", "start rp_node"},
+        },
+        {
+            name: "recreate changed service",
+            setup: func(d *fakeDocker, o *Orchestrator) error {
+                return NewOrchestrator(o.docker, loadTestProject(t, "ethereum/client-go:v0")).Up(context.Background(), UpOptions{})
+            },
+            eth1Image: "ethereum/client-go:v1",
+            expectedActions: []string{"pull ethereum/client-go:v1", "stop rp_eth1", "remove rp_eth1", "create rp_eth1", "start rp_eth1"},
+        },
+        {
+            name: "force recreate",
+            setup: func(d *fakeDocker, o *Orchestrator) error { return o.Up(context.Background(), UpOptions{}) },
+            eth1Image: "ethereum/client-go:v1",
+            options: UpOptions{ForceRecreate: true, NoDeps: true},
+            services: []string{"node"},
+            expectedActions: []string{"stop rp_node", "remove rp_node", "create rp_node", "connect rp_node to rp_metrics", "start rp_node"},
+        },
+        {
+            name: "start with dependencies",
+            eth1Image: "ethereum/client-go:v1",
+            services: []string{"node"},
+            expectedActions: []string{
+                "create network rp_metrics", "create network rp_net", "create volume rp_eth1clientdata",
+                "pull ethereum/client-go:v1", "pull rocketpool/smartnode:v1",
+                "create rp_eth1", "start rp_eth1",
+                "create rp_node", "connect rp_node to rp_metrics", "start rp_node",
+            },
+        },
+        {
+            name: "pull error",
+            eth1Image: "ethereum/client-go:v1",
+            pullError: "manifest unknown",
+            expectedActions: []string{"create network rp_metrics", "create network rp_net", "create volume rp_eth1clientdata", "pull ethereum/client-go:v1"},
+            errorContains: "Could not pull image ethereum/client-go:v1: manifest unknown",
+        },
+        {
+            name: "unknown service",
+            eth1Image: "ethereum/client-go:v1",
+            services: []string{"watchtower"},
+            expectedActions: []string{},
+            errorContains: "Unknown service 'watchtower'",
+        },
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            docker, dockerClient, server := newFakeDocker(t)
+            defer server.Close()
+            orch := NewOrchestrator(dockerClient, loadTestProject(t, test.eth1Image))
+
+            // Set up the daemon state
+            if test.setup != nil {
+                if err := test.setup(docker, orch); err != nil {
+                    t.Fatal(err)
+                }
+            }
+            docker.takeActions()
+            docker.pullError = test.pullError
+
+            // Start services
+            err := orch.Up(context.Background(), test.options, test.services...)
+            if test.errorContains != "" {
+                if err == nil || !strings.Contains(err.Error(), test.errorContains) {
+                    t.Fatalf("expected an error containing '%s', got %v", test.errorContains, err)
+                }
+            } else if err != nil {
+                t.Fatal(err)
+            }
+
+            // Check actions
+            actions := docker.takeActions()
+            if actions == nil {
+                actions = []string{}
+            }
+            if !reflect.DeepEqual(actions, test.expectedActions) {
+                t.Errorf("actions are %q, expected %q", actions, test.expectedActions)
+            }
+
+        })
+    }
+}
+
+
+func TestOrchestratorStatusAndDown(t *testing.T) {
+    docker, dockerClient, server := newFakeDocker(t)
+    defer server.Close()
+    orch := NewOrchestrator(dockerClient, loadTestProject(t, "ethereum/client-go:v1"))
+    ctx := context.Background()
+
+    // Start the project, then change the eth1 image
+    if err := orch.Up(ctx, UpOptions{}); err != nil {
+        t.Fatal(err)
+    }
+    if err := orch.Stop(ctx, nil, "node"); err != nil {
+        t.Fatal(err)
+    }
+    changed := NewOrchestrator(dockerClient, loadTestProject(t, "ethereum/client-go:v2"))
+
+    // Check states
+    states, err := changed.Status(ctx)
+    if err != nil {
+        t.Fatal(err)
+    }
+    expectedStates := []struct {
+        service string
+        summary string
+        upToDate bool
+    }{
+        {"eth1", "running", false},
+        {"node", "exited (0)", true},
+    }
+    if len(states) != len(expectedStates) {
+        t.Fatalf("got %d states, expected %d", len(states), len(expectedStates))
+    }
+    for si, expected := range expectedStates {
+        if states[si].Service != expected.service || states[si].Summary() != expected.summary || states[si].UpToDate != expected.upToDate {
+            t.Errorf("state %d is %s %s (up to date %t), expected %s %s (up to date %t)", si, states[si].Service, states[si].Summary(), states[si].UpToDate, expected.service, expected.summary, expected.upToDate)
+        }
+    }
+
+    // Remove the project
+    docker.takeActions()
+    if err := changed.Down(ctx, true, nil); err != nil {
+        t.Fatal(err)
+    }
+    expectedActions := []string{"remove rp_node", "stop rp_eth1", "remove rp_eth1", "remove network rp_metrics", "remove network rp_net", "remove volume rp_eth1clientdata"}
+    if actions := docker.takeActions(); !reflect.DeepEqual(actions, expectedActions) {
+        t.Errorf("actions are %q, expected %q", actions, expectedActions)
+    }
+    if names := docker.containerNames(); len(names) != 0 {
+        t.Errorf("containers %v were not removed", names)
+    }
+
+    // Missing resources are ignored
+    if err := changed.Down(ctx, true, nil); err != nil {
+        t.Fatal(err)
+    }
+}
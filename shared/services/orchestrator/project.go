@@ -0,0 +1,604 @@
+package orchestrator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/a8m/envsubst/parse"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+)
+
+// Settings
+const DefaultNetwork = "default"
+
+// Dependency start conditions
+const (
+    ConditionStarted = "service_started"
+    ConditionHealthy = "service_healthy"
+)
+
+// Labels identifying project resources; these match docker-compose so either can manage the service
+const (
+    ProjectLabel = "com.docker.compose.project"
+    ServiceLabel = "com.docker.compose.service"
+    ConfigHashLabel = "com.docker.compose.config-hash"
+    NetworkLabel = "com.docker.compose.network"
+    VolumeLabel = "com.docker.compose.volume"
+    OneoffLabel = "com.docker.compose.oneoff"
+    ContainerNumberLabel = "com.docker.compose.container-number"
+)
+
+
+// A compose file to load
+type File struct {
+    Path string
+    Contents []byte
+}
+
+
+// A compose project rendered into Docker Engine API definitions
+type Project struct {
+    Name string
+    Services []*Service
+    Networks []*Resource
+    Volumes []*Resource
+    Warnings []string
+}
+
+
+// A project service, with its container definition
+type Service struct {
+    Name string
+    ContainerName string
+    Image string
+    DependsOn map[string]string
+    Config *container.Config
+    HostConfig *container.HostConfig
+    Networks map[string]*network.EndpointSettings
+    ConfigHash string
+}
+
+
+// A project network or volume
+type Resource struct {
+    Key string
+    Name string
+    Driver string
+    DriverOpts map[string]string
+    External bool
+}
+
+
+// Load a compose project from one or more compose files
+// Environment variables in the files are interpolated from env (in KEY=VALUE format), and relative paths are resolved from projectDir
+func LoadProject(name string, projectDir string, files []File, env []string) (*Project, error) {
+
+    // Parse & merge compose files
+    if len(files) == 0 {
+        return nil, errors.New("No compose files specified")
+    }
+    var compose *composeFile
+    warnings := []string{}
+    for _, file := range files {
+        fileCompose, fileWarnings, err := parseComposeFile(file, env)
+        if err != nil {
+            return nil, err
+        }
+        warnings = append(warnings, fileWarnings...)
+        if compose == nil {
+            compose = fileCompose
+        } else {
+            mergeComposeFiles(compose, fileCompose)
+        }
+    }
+
+    // Get networks & volumes
+    project := &Project{Name: name, Warnings: warnings}
+    networks := map[string]*Resource{}
+    for key, definition := range compose.Networks {
+        networks[key] = getResource(name, key, definition)
+    }
+    volumes := map[string]*Resource{}
+    for key, definition := range compose.Volumes {
+        volumes[key] = getResource(name, key, definition)
+    }
+
+    // Get services
+    services := map[string]*Service{}
+    for serviceName, definition := range compose.Services {
+        if definition == nil {
+            return nil, fmt.Errorf("Service %s has no definition", serviceName)
+        }
+        service, err := getService(name, projectDir, serviceName, definition, networks, volumes, env)
+        if err != nil {
+            return nil, fmt.Errorf("Could not load service %s: %w", serviceName, err)
+        }
+        services[serviceName] = service
+    }
+
+    // Resolve container network modes now that all container names are known
+    for _, service := range services {
+        mode := string(service.HostConfig.NetworkMode)
+        if strings.HasPrefix(mode, "service:") {
+            target, ok := services[strings.TrimPrefix(mode, "service:")]
+            if !ok {
+                return nil, fmt.Errorf("Service %s uses the network of unknown service %s", service.Name, strings.TrimPrefix(mode, "service:"))
+            }
+            service.HostConfig.NetworkMode = container.NetworkMode("container:" + target.ContainerName)
+            service.DependsOn[target.Name] = ConditionStarted
+        }
+    }
+
+    // Sort services in dependency order
+    sortedServices, err := sortServices(services)
+    if err != nil {
+        return nil, err
+    }
+    project.Services = sortedServices
+
+    // Add networks & volumes in use
+    for _, key := range getSortedKeys(networks) {
+        project.Networks = append(project.Networks, networks[key])
+    }
+    for _, key := range getSortedKeys(volumes) {
+        project.Volumes = append(project.Volumes, volumes[key])
+    }
+
+    // Compute config hashes
+    for _, service := range project.Services {
+        hash, err := getConfigHash(service)
+        if err != nil {
+            return nil, fmt.Errorf("Could not hash the config for service %s: %w", service.Name, err)
+        }
+        service.ConfigHash = hash
+        service.Config.Labels[ConfigHashLabel] = hash
+    }
+
+    // Return
+    return project, nil
+
+}
+
+
+// Get a project service by name
+func (p *Project) GetService(name string) *Service {
+    for _, service := range p.Services {
+        if service.Name == name {
+            return service
+        }
+    }
+    return nil
+}
+
+
+// Get the services with the given names, with the services they depend on unless noDeps is set, in dependency order
+// Returns all services if no names are given
+func (p *Project) GetServices(noDeps bool, names ...string) ([]*Service, error) {
+    if len(names) == 0 {
+        return p.Services, nil
+    }
+    selected := map[string]bool{}
+    var selectService func(name string) error
+    selectService = func(name string) error {
+        service := p.GetService(name)
+        if service == nil {
+            return fmt.Errorf("Unknown service '%s'", name)
+        }
+        if selected[name] {
+            return nil
+        }
+        selected[name] = true
+        if noDeps {
+            return nil
+        }
+        for dependency := range service.DependsOn {
+            if err := selectService(dependency); err != nil {
+                return err
+            }
+        }
+        return nil
+    }
+    for _, name := range names {
+        if err := selectService(name); err != nil {
+            return nil, err
+        }
+    }
+    services := []*Service{}
+    for _, service := range p.Services {
+        if selected[service.Name] {
+            services = append(services, service)
+        }
+    }
+    return services, nil
+}
+
+
+// Get a network or volume resource
+func getResource(projectName string, key string, definition *composeResource) *Resource {
+    resource := &Resource{
+        Key: key,
+        Name: fmt.Sprintf("%s_%s", projectName, key),
+        DriverOpts: map[string]string{},
+    }
+    if definition == nil {
+        return resource
+    }
+    resource.Driver = definition.Driver
+    resource.External = definition.External
+    if definition.DriverOpts != nil {
+        resource.DriverOpts = definition.DriverOpts
+    }
+    if definition.Name != "" {
+        resource.Name = definition.Name
+    } else if definition.External {
+        resource.Name = key
+    }
+    return resource
+}
+
+
+// Render a service definition into a container definition
+func getService(projectName string, projectDir string, serviceName string, definition *composeService, networks map[string]*Resource, volumes map[string]*Resource, env []string) (*Service, error) {
+
+    // Check image
+    if definition.Image == "" {
+        return nil, fmt.Errorf("No image specified")
+    }
+
+    // Initialize service
+    service := &Service{
+        Name: serviceName,
+        ContainerName: definition.ContainerName,
+        Image: definition.Image,
+        DependsOn: map[string]string{},
+        Config: &container.Config{
+            Image: definition.Image,
+            User: definition.User,
+            StopSignal: definition.StopSignal,
+            Env: []string{},
+            Labels: map[string]string{},
+            ExposedPorts: nat.PortSet{},
+            Volumes: map[string]struct{}{},
+        },
+        HostConfig: &container.HostConfig{
+            CapAdd: definition.CapAdd,
+            CapDrop: definition.CapDrop,
+            SecurityOpt: definition.SecurityOpt,
+            ExtraHosts: definition.ExtraHosts,
+            PidMode: container.PidMode(definition.Pid),
+            PortBindings: nat.PortMap{},
+        },
+        Networks: map[string]*network.EndpointSettings{},
+    }
+    if service.ContainerName == "" {
+        service.ContainerName = fmt.Sprintf("%s_%s_1", projectName, serviceName)
+    }
+    for dependency, condition := range definition.DependsOn {
+        service.DependsOn[dependency] = condition
+    }
+
+    // Entrypoint & command
+    if definition.Entrypoint != nil {
+        entrypoint, err := getCommand(definition.Entrypoint)
+        if err != nil {
+            return nil, fmt.Errorf("Invalid entrypoint: %w", err)
+        }
+        service.Config.Entrypoint = entrypoint
+    }
+    if definition.Command != nil {
+        command, err := getCommand(definition.Command)
+        if err != nil {
+            return nil, fmt.Errorf("Invalid command: %w", err)
+        }
+        service.Config.Cmd = command
+    }
+
+    // Environment; variables without a value are taken from the project environment
+    for _, variable := range definition.Environment {
+        if strings.Contains(variable, "=") {
+            service.Config.Env = append(service.Config.Env, variable)
+        } else if value, ok := parse.Env(env).Lookup(variable); ok {
+            service.Config.Env = append(service.Config.Env, fmt.Sprintf("%s=%s", variable, value))
+        }
+    }
+
+    // Labels
+    for _, label := range definition.Labels {
+        keyValue := strings.SplitN(label, "=", 2)
+        if len(keyValue) == 2 {
+            service.Config.Labels[keyValue[0]] = keyValue[1]
+        } else {
+            service.Config.Labels[keyValue[0]] = ""
+        }
+    }
+    service.Config.Labels[ProjectLabel] = projectName
+    service.Config.Labels[ServiceLabel] = serviceName
+    service.Config.Labels[OneoffLabel] = "False"
+    service.Config.Labels[ContainerNumberLabel] = "1"
+
+    // Stop timeout
+    if definition.StopGracePeriod != "" {
+        stopGracePeriod, err := time.ParseDuration(definition.StopGracePeriod)
+        if err != nil {
+            return nil, fmt.Errorf("Invalid stop grace period '%s': %w", definition.StopGracePeriod, err)
+        }
+        stopTimeout := int(stopGracePeriod.Seconds())
+        service.Config.StopTimeout = &stopTimeout
+    }
+
+    // Restart policy
+    restartPolicy, err := getRestartPolicy(definition.Restart)
+    if err != nil {
+        return nil, err
+    }
+    service.HostConfig.RestartPolicy = restartPolicy
+
+    // Ports
+    exposedPorts, portBindings, err := nat.ParsePortSpecs(definition.Ports)
+    if err != nil {
+        return nil, fmt.Errorf("Invalid ports: %w", err)
+    }
+    for port := range exposedPorts {
+        service.Config.ExposedPorts[port] = struct{}{}
+    }
+    for port, bindings := range portBindings {
+        service.HostConfig.PortBindings[port] = bindings
+    }
+    for _, expose := range definition.Expose {
+        proto, port := nat.SplitProtoPort(expose)
+        exposedPort, err := nat.NewPort(proto, port)
+        if err != nil {
+            return nil, fmt.Errorf("Invalid exposed port '%s': %w", expose, err)
+        }
+        service.Config.ExposedPorts[exposedPort] = struct{}{}
+    }
+
+    // Volumes
+    for _, volume := range definition.Volumes {
+        parts := strings.Split(volume, ":")
+        if len(parts) == 1 {
+            service.Config.Volumes[parts[0]] = struct{}{}
+            continue
+        }
+        if len(parts) > 3 {
+            return nil, fmt.Errorf("Invalid volume '%s'", volume)
+        }
+        source := parts[0]
+        switch {
+            case strings.HasPrefix(source, "/"):
+            case strings.HasPrefix(source, "."):
+                source = path.Join(projectDir, source)
+            default:
+                resource, ok := volumes[source]
+                if !ok {
+                    return nil, fmt.Errorf("Volume '%s' is not defined in the top-level volumes", source)
+                }
+                source = resource.Name
+        }
+        parts[0] = source
+        service.HostConfig.Binds = append(service.HostConfig.Binds, strings.Join(parts, ":"))
+    }
+
+    // Networks
+    switch {
+        case definition.NetworkMode != "" && len(definition.Networks.Names) > 0:
+            return nil, fmt.Errorf("network_mode and networks cannot be used together")
+        case definition.NetworkMode != "":
+            service.HostConfig.NetworkMode = container.NetworkMode(definition.NetworkMode)
+        default:
+            serviceNetworks := definition.Networks.Names
+            if len(serviceNetworks) == 0 {
+                serviceNetworks = []string{DefaultNetwork}
+                if _, ok := networks[DefaultNetwork]; !ok {
+                    networks[DefaultNetwork] = getResource(projectName, DefaultNetwork, nil)
+                }
+            }
+            for ni, networkKey := range serviceNetworks {
+                resource, ok := networks[networkKey]
+                if !ok {
+                    return nil, fmt.Errorf("Network '%s' is not defined in the top-level networks", networkKey)
+                }
+                if ni == 0 {
+                    service.HostConfig.NetworkMode = container.NetworkMode(resource.Name)
+                }
+                service.Networks[resource.Name] = &network.EndpointSettings{
+                    Aliases: append([]string{serviceName}, definition.Networks.Aliases[networkKey]...),
+                }
+            }
+    }
+
+    // Healthcheck
+    if definition.Healthcheck != nil {
+        healthcheck, err := getHealthcheck(definition.Healthcheck)
+        if err != nil {
+            return nil, fmt.Errorf("Invalid healthcheck: %w", err)
+        }
+        service.Config.Healthcheck = healthcheck
+    }
+
+    // Logging
+    if definition.Logging != nil {
+        service.HostConfig.LogConfig = container.LogConfig{
+            Type: definition.Logging.Driver,
+            Config: definition.Logging.Options,
+        }
+    }
+
+    // Return
+    return service, nil
+
+}
+
+
+// Get a command from its shell or list form
+func getCommand(command *stringOrList) ([]string, error) {
+    if command.IsList {
+        return command.List, nil
+    }
+    return splitCommand(command.Shell)
+}
+
+
+// Get a container restart policy
+func getRestartPolicy(restart string) (container.RestartPolicy, error) {
+    nameRetries := strings.SplitN(restart, ":", 2)
+    switch nameRetries[0] {
+        case "", "no":
+            return container.RestartPolicy{}, nil
+        case "always", "unless-stopped":
+            return container.RestartPolicy{Name: nameRetries[0]}, nil
+        case "on-failure":
+            policy := container.RestartPolicy{Name: nameRetries[0]}
+            if len(nameRetries) == 2 {
+                retries, err := strconv.Atoi(nameRetries[1])
+                if err != nil {
+                    return container.RestartPolicy{}, fmt.Errorf("Invalid restart policy '%s'", restart)
+                }
+                policy.MaximumRetryCount = retries
+            }
+            return policy, nil
+    }
+    return container.RestartPolicy{}, fmt.Errorf("Invalid restart policy '%s'", restart)
+}
+
+
+// Get a container healthcheck
+func getHealthcheck(definition *composeHealthcheck) (*container.HealthConfig, error) {
+    if definition.Disable {
+        return &container.HealthConfig{Test: []string{"NONE"}}, nil
+    }
+    healthcheck := &container.HealthConfig{Retries: definition.Retries}
+    if definition.Test != nil {
+        if definition.Test.IsList {
+            healthcheck.Test = definition.Test.List
+        } else {
+            healthcheck.Test = []string{"CMD-SHELL", definition.Test.Shell}
+        }
+    }
+    durations := []struct {
+        value string
+        target *time.Duration
+    }{
+        {definition.Interval, &(healthcheck.Interval)},
+        {definition.Timeout, &(healthcheck.Timeout)},
+        {definition.StartPeriod, &(healthcheck.StartPeriod)},
+    }
+    for _, duration := range durations {
+        if duration.value == "" { continue }
+        parsed, err := time.ParseDuration(duration.value)
+        if err != nil {
+            return nil, fmt.Errorf("Invalid duration '%s': %w", duration.value, err)
+        }
+        *(duration.target) = parsed
+    }
+    return healthcheck, nil
+}
+
+
+// Sort services so each service follows the services it depends on
+func sortServices(services map[string]*Service) ([]*Service, error) {
+    sorted := []*Service{}
+    visited := map[string]bool{}
+    visiting := map[string]bool{}
+    var visit func(name string, dependent string) error
+    visit = func(name string, dependent string) error {
+        if visited[name] {
+            return nil
+        }
+        service, ok := services[name]
+        if !ok {
+            return fmt.Errorf("Service %s depends on unknown service %s", dependent, name)
+        }
+        if visiting[name] {
+            return fmt.Errorf("Circular dependency between services %s and %s", dependent, name)
+        }
+        visiting[name] = true
+        for _, dependency := range getSortedKeys(service.DependsOn) {
+            if err := visit(dependency, name); err != nil {
+                return err
+            }
+        }
+        visited[name] = true
+        sorted = append(sorted, service)
+        return nil
+    }
+    for _, name := range getSortedKeys(services) {
+        if err := visit(name, ""); err != nil {
+            return nil, err
+        }
+    }
+    return sorted, nil
+}
+
+
+// Get a hash of a service's container definition, used to detect whether its container must be recreated
+func getConfigHash(service *Service) (string, error) {
+    definition, err := json.Marshal(struct {
+        Name string
+        Config *container.Config
+        HostConfig *container.HostConfig
+        Networks map[string]*network.EndpointSettings
+    }{service.ContainerName, service.Config, service.HostConfig, service.Networks})
+    if err != nil {
+        return "", err
+    }
+    hash := sha256.Sum256(definition)
+    return hex.EncodeToString(hash[:]), nil
+}
+
+
+// Get the container path of a volume definition
+func getVolumeTarget(volume string) string {
+    parts := strings.Split(volume, ":")
+    if len(parts) == 1 {
+        return parts[0]
+    }
+    return parts[1]
+}
+
+
+// Interpolate environment variables in the string values of a parsed YAML document
+func interpolate(value interface{}, env []string) (interface{}, error) {
+    switch v := value.(type) {
+        case string:
+            return parse.New("compose", env, parse.Relaxed).Parse(v)
+        case []interface{}:
+            for i, item := range v {
+                interpolated, err := interpolate(item, env)
+                if err != nil { return nil, err }
+                v[i] = interpolated
+            }
+            return v, nil
+        case map[interface{}]interface{}:
+            for key, item := range v {
+                interpolated, err := interpolate(item, env)
+                if err != nil { return nil, err }
+                v[key] = interpolated
+            }
+            return v, nil
+    }
+    return value, nil
+}
+
+
+// Get the sorted keys of a string-keyed map
+func getSortedKeys(values interface{}) []string {
+    keys := []string{}
+    switch v := values.(type) {
+        case map[string]*Service: for key := range v { keys = append(keys, key) }
+        case map[string]*Resource: for key := range v { keys = append(keys, key) }
+        case map[string]string: for key := range v { keys = append(keys, key) }
+    }
+    sort.Strings(keys)
+    return keys
+}
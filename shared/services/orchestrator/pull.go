@@ -0,0 +1,64 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/docker/docker/api/types"
+)
+
+// An image pull progress message from the Docker Engine API
+type pullMessage struct {
+    Status string                   `json:"status"`
+    ID string                       `json:"id"`
+    ProgressDetail struct {
+        Current int64               `json:"current"`
+        Total int64                 `json:"total"`
+    }                               `json:"progressDetail"`
+    Error string                    `json:"error"`
+    ErrorDetail *struct {
+        Message string              `json:"message"`
+    }                               `json:"errorDetail"`
+}
+
+
+// Pull a service's image, reporting progress as it is received
+func (o *Orchestrator) pullImage(ctx context.Context, service *Service, progress func(Progress)) error {
+
+    // Start pull
+    progress(Progress{Service: service.Name, Status: StatusPulling, Image: service.Image})
+    reader, err := o.docker.ImagePull(ctx, service.Image, types.ImagePullOptions{})
+    if err != nil {
+        return fmt.Errorf("Could not pull image %s: %w", service.Image, err)
+    }
+    defer reader.Close()
+
+    // Read progress messages; pull errors are reported in the message stream
+    decoder := json.NewDecoder(reader)
+    for {
+        var message pullMessage
+        if err := decoder.Decode(&message); errors.Is(err, io.EOF) {
+            return nil
+        } else if err != nil {
+            return fmt.Errorf("Could not read pull progress for image %s: %w", service.Image, err)
+        }
+        if message.ErrorDetail != nil && message.ErrorDetail.Message != "" {
+            return fmt.Errorf("Could not pull image %s: %s", service.Image, message.ErrorDetail.Message)
+        }
+        if message.Error != "" {
+            return fmt.Errorf("Could not pull image %s: %s", service.Image, message.Error)
+        }
+        progress(Progress{
+            Service: service.Name,
+            Status: message.Status,
+            Image: service.Image,
+            Layer: message.ID,
+            Current: message.ProgressDetail.Current,
+            Total: message.ProgressDetail.Total,
+        })
+    }
+
+}
@@ -0,0 +1,101 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/docker/docker/api/types"
+)
+
+// Resource usage of a service container
+type ContainerStats struct {
+    Service string
+    Name string
+    CPUPercent float64
+    MemoryUsage uint64
+    MemoryLimit uint64
+    MemoryPercent float64
+    NetworkRx uint64
+    NetworkTx uint64
+    BlockRead uint64
+    BlockWrite uint64
+    Pids uint64
+}
+
+
+// Get the resource usage of each running service container
+func (o *Orchestrator) Stats(ctx context.Context) ([]*ContainerStats, error) {
+    containerStats := []*ContainerStats{}
+    for _, service := range o.project.Services {
+
+        // Get container
+        state, err := InspectContainer(ctx, o.docker, service.ContainerName)
+        if err != nil {
+            return nil, err
+        }
+        if state == nil || !state.Running { continue }
+
+        // Get stats
+        response, err := o.docker.ContainerStats(ctx, state.ID, false)
+        if err != nil {
+            return nil, fmt.Errorf("Could not get stats for container %s: %w", service.ContainerName, err)
+        }
+        var stats types.StatsJSON
+        err = json.NewDecoder(response.Body).Decode(&stats)
+        response.Body.Close()
+        if err != nil {
+            return nil, fmt.Errorf("Could not decode stats for container %s: %w", service.ContainerName, err)
+        }
+        containerStats = append(containerStats, getContainerStats(service, stats))
+
+    }
+    return containerStats, nil
+}
+
+
+// Calculate container resource usage from raw stats, as the docker stats command does
+func getContainerStats(service *Service, stats types.StatsJSON) *ContainerStats {
+
+    // Get CPU usage
+    containerStats := &ContainerStats{
+        Service: service.Name,
+        Name: service.ContainerName,
+        Pids: stats.PidsStats.Current,
+    }
+    cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+    systemDelta := float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+    onlineCPUs := float64(stats.CPUStats.OnlineCPUs)
+    if onlineCPUs == 0 {
+        onlineCPUs = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+    }
+    if cpuDelta > 0 && systemDelta > 0 {
+        containerStats.CPUPercent = cpuDelta / systemDelta * onlineCPUs * 100
+    }
+
+    // Get memory usage, excluding the page cache
+    containerStats.MemoryUsage = stats.MemoryStats.Usage
+    if cache, ok := stats.MemoryStats.Stats["cache"]; ok && cache < containerStats.MemoryUsage {
+        containerStats.MemoryUsage -= cache
+    }
+    containerStats.MemoryLimit = stats.MemoryStats.Limit
+    if containerStats.MemoryLimit > 0 {
+        containerStats.MemoryPercent = float64(containerStats.MemoryUsage) / float64(containerStats.MemoryLimit) * 100
+    }
+
+    // Get network & block IO
+    for _, network := range stats.Networks {
+        containerStats.NetworkRx += network.RxBytes
+        containerStats.NetworkTx += network.TxBytes
+    }
+    for _, entry := range stats.BlkioStats.IoServiceBytesRecursive {
+        switch entry.Op {
+            case "Read", "read": containerStats.BlockRead += entry.Value
+            case "Write", "write": containerStats.BlockWrite += entry.Value
+        }
+    }
+
+    // Return
+    return containerStats
+
+}
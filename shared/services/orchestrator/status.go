@@ -0,0 +1,152 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/client"
+)
+
+// Container health statuses
+const (
+    HealthStarting = "starting"
+    HealthHealthy = "healthy"
+    HealthUnhealthy = "unhealthy"
+)
+
+
+// The state of a service container
+type ContainerState struct {
+    Service string
+    Name string
+    ID string
+    Image string
+    Status string
+    Running bool
+    Health string
+    HealthLog string
+    ExitCode int
+    StartedAt time.Time
+    FinishedAt time.Time
+    Ports []string
+    ConfigHash string
+    UpToDate bool
+}
+
+
+// Get a summary of the container state, e.g. "running (healthy)" or "exited (1)"
+func (s *ContainerState) Summary() string {
+    switch {
+        case s.Status == "":
+            return "not created"
+        case s.Status == "exited":
+            return fmt.Sprintf("exited (%d)", s.ExitCode)
+        case s.Health != "":
+            return fmt.Sprintf("%s (%s)", s.Status, s.Health)
+    }
+    return s.Status
+}
+
+
+// Get the state of a container by name or ID; returns nil if the container doesn't exist
+func InspectContainer(ctx context.Context, docker DockerClient, name string) (*ContainerState, error) {
+
+    // Inspect container
+    inspected, err := docker.ContainerInspect(ctx, name)
+    if client.IsErrNotFound(err) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, fmt.Errorf("Could not inspect container %s: %w", name, err)
+    }
+
+    // Get state
+    state := &ContainerState{
+        Name: strings.TrimPrefix(inspected.Name, "/"),
+        ID: inspected.ID,
+    }
+    if inspected.Config != nil {
+        state.Service = inspected.Config.Labels[ServiceLabel]
+        state.Image = inspected.Config.Image
+        state.ConfigHash = inspected.Config.Labels[ConfigHashLabel]
+    }
+    if inspected.State != nil {
+        state.Status = inspected.State.Status
+        state.Running = inspected.State.Running
+        state.ExitCode = inspected.State.ExitCode
+        state.StartedAt, _ = time.Parse(time.RFC3339Nano, inspected.State.StartedAt)
+        state.FinishedAt, _ = time.Parse(time.RFC3339Nano, inspected.State.FinishedAt)
+        if health := inspected.State.Health; health != nil {
+            state.Health = health.Status
+            if len(health.Log) > 0 && health.Log[len(health.Log) - 1].ExitCode != 0 {
+                state.HealthLog = strings.TrimSpace(health.Log[len(health.Log) - 1].Output)
+            }
+        }
+    }
+
+    // Get published ports
+    if inspected.NetworkSettings != nil {
+        for port, bindings := range inspected.NetworkSettings.Ports {
+            if len(bindings) == 0 {
+                state.Ports = append(state.Ports, string(port))
+            }
+            for _, binding := range bindings {
+                state.Ports = append(state.Ports, fmt.Sprintf("%s:%s->%s", binding.HostIP, binding.HostPort, port))
+            }
+        }
+        sort.Strings(state.Ports)
+    }
+
+    // Return
+    return state, nil
+
+}
+
+
+// Get the state of each service container, followed by any project containers for services which are no longer defined
+func (o *Orchestrator) Status(ctx context.Context) ([]*ContainerState, error) {
+
+    // Get service container states
+    states := []*ContainerState{}
+    serviceContainers := map[string]bool{}
+    for _, service := range o.project.Services {
+        state, err := InspectContainer(ctx, o.docker, service.ContainerName)
+        if err != nil {
+            return nil, err
+        }
+        if state == nil {
+            state = &ContainerState{
+                Name: service.ContainerName,
+                Image: service.Image,
+            }
+        } else {
+            serviceContainers[state.ID] = true
+            state.UpToDate = (state.ConfigHash == service.ConfigHash)
+        }
+        state.Service = service.Name
+        states = append(states, state)
+    }
+
+    // Get orphaned container states
+    containers, err := o.getProjectContainers(ctx)
+    if err != nil {
+        return nil, err
+    }
+    for _, projectContainer := range containers {
+        if serviceContainers[projectContainer.ID] { continue }
+        state, err := InspectContainer(ctx, o.docker, projectContainer.ID)
+        if err != nil {
+            return nil, err
+        }
+        if state != nil {
+            states = append(states, state)
+        }
+    }
+
+    // Return
+    return states, nil
+
+}
@@ -0,0 +1,179 @@
+package prices
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/contracts"
+)
+
+// Aggregation methods
+const (
+    AggregationMedian = "median"
+    AggregationMean = "mean"
+)
+
+
+// The price reported by a single source
+type SourcePrice struct {
+    Source string
+    Price *big.Int
+    Error error
+}
+
+
+// Aggregates the RPL price from multiple sources
+// All sources must return a price, and if a tolerance is set, every price must be within that percentage of the aggregate price;
+// otherwise, no price is returned so that the node refuses to submit one
+type Aggregator struct {
+    sources []PriceSource
+    aggregation string
+    tolerance float64
+}
+
+
+// Create a new price aggregator from the node configuration
+// If no sources are configured, the 1inch oracle is used alone
+func NewAggregator(cfg config.RocketPoolConfig, client *ethclient.Client, oio *contracts.OneInchOracle) (*Aggregator, error) {
+
+    // Get aggregation settings
+    aggregation := cfg.Smartnode.RplPriceAggregation
+    if aggregation == "" {
+        aggregation = AggregationMedian
+    }
+    if aggregation != AggregationMedian && aggregation != AggregationMean {
+        return nil, fmt.Errorf("Invalid RPL price aggregation '%s'; must be '%s' or '%s'", aggregation, AggregationMedian, AggregationMean)
+    }
+    if cfg.Smartnode.RplPriceTolerance < 0 {
+        return nil, fmt.Errorf("Invalid RPL price tolerance %f; must not be negative", cfg.Smartnode.RplPriceTolerance)
+    }
+
+    // Create sources
+    sourceConfigs := cfg.Smartnode.RplPriceSources
+    if len(sourceConfigs) == 0 {
+        sourceConfigs = []config.RplPriceSource{{Type: SourceTypeOneInch}}
+    }
+    rplAddress := common.HexToAddress(cfg.Rocketpool.RplTokenAddress)
+    wethAddress := common.HexToAddress(cfg.Rocketpool.WethTokenAddress)
+    sources := make([]PriceSource, len(sourceConfigs))
+    for si, sourceConfig := range sourceConfigs {
+        source, err := NewPriceSource(sourceConfig, client, oio, rplAddress, wethAddress)
+        if err != nil {
+            return nil, fmt.Errorf("Invalid RPL price source %d: %w", si + 1, err)
+        }
+        sources[si] = source
+    }
+
+    // Return
+    return &Aggregator{
+        sources: sources,
+        aggregation: aggregation,
+        tolerance: cfg.Smartnode.RplPriceTolerance,
+    }, nil
+
+}
+
+
+// Check whether the 1inch oracle is one of the price sources
+func (a *Aggregator) UsesOneInchOracle() bool {
+    for _, source := range a.sources {
+        if _, ok := source.(*oneInchSource); ok {
+            return true
+        }
+    }
+    return false
+}
+
+
+// Get the aggregate RPL price at a block, along with each source's price
+func (a *Aggregator) GetRplPrice(blockNumber uint64) (*big.Int, []SourcePrice, error) {
+
+    // Get source prices
+    opts := &bind.CallOpts{
+        BlockNumber: new(big.Int).SetUint64(blockNumber),
+    }
+    sourcePrices := make([]SourcePrice, len(a.sources))
+    var wg errgroup.Group
+    for si, source := range a.sources {
+        si, source := si, source
+        wg.Go(func() error {
+            price, err := source.GetRplPrice(opts)
+            sourcePrices[si] = SourcePrice{Source: source.GetName(), Price: price, Error: err}
+            return nil
+        })
+    }
+    wg.Wait()
+
+    // Check all sources returned a price
+    prices := make([]*big.Int, 0, len(sourcePrices))
+    for _, sourcePrice := range sourcePrices {
+        if sourcePrice.Error != nil {
+            return nil, sourcePrices, fmt.Errorf("Could not get RPL price at block %d from %s: %w", blockNumber, sourcePrice.Source, sourcePrice.Error)
+        }
+        if sourcePrice.Price == nil || sourcePrice.Price.Sign() <= 0 {
+            return nil, sourcePrices, fmt.Errorf("%s returned an invalid RPL price at block %d", sourcePrice.Source, blockNumber)
+        }
+        prices = append(prices, sourcePrice.Price)
+    }
+
+    // Aggregate prices
+    var rplPrice *big.Int
+    switch a.aggregation {
+        case AggregationMedian: rplPrice = getMedian(prices)
+        case AggregationMean: rplPrice = getMean(prices)
+    }
+
+    // Check the sources agree within tolerance
+    if a.tolerance > 0 {
+        for _, sourcePrice := range sourcePrices {
+            if deviation := getDeviation(sourcePrice.Price, rplPrice); deviation > a.tolerance {
+                return nil, sourcePrices, fmt.Errorf("RPL price sources disagree at block %d: %s price deviates from the %s price by %.2f%%, which exceeds the tolerance of %.2f%%", blockNumber, sourcePrice.Source, a.aggregation, deviation, a.tolerance)
+            }
+        }
+    }
+
+    // Return
+    return rplPrice, sourcePrices, nil
+
+}
+
+
+// Get the median of a set of prices; the mean of the middle prices is used for an even count
+func getMedian(prices []*big.Int) *big.Int {
+    sorted := make([]*big.Int, len(prices))
+    copy(sorted, prices)
+    sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+    middle := len(sorted) / 2
+    if len(sorted) % 2 == 1 {
+        return new(big.Int).Set(sorted[middle])
+    }
+    return getMean(sorted[middle - 1 : middle + 1])
+}
+
+
+// Get the mean of a set of prices
+func getMean(prices []*big.Int) *big.Int {
+    total := big.NewInt(0)
+    for _, price := range prices {
+        total.Add(total, price)
+    }
+    return total.Div(total, big.NewInt(int64(len(prices))))
+}
+
+
+// Get the percentage deviation of a price from a reference price
+func getDeviation(price, reference *big.Int) float64 {
+    difference := new(big.Float).SetInt(new(big.Int).Sub(price, reference))
+    deviation, _ := difference.Quo(difference, new(big.Float).SetInt(reference)).Float64()
+    if deviation < 0 {
+        deviation = -deviation
+    }
+    return deviation * 100
+}
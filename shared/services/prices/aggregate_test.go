@@ -0,0 +1,239 @@
+package prices
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+
+	"github.com/rocket-pool/smartnode/shared/services/config"
+)
+
+
+// A price source returning a fixed price or error
+type fixedSource struct {
+    name string
+    price *big.Int
+    err error
+}
+func (s *fixedSource) GetName() string { return s.name }
+func (s *fixedSource) GetRplPrice(opts *bind.CallOpts) (*big.Int, error) { return s.price, s.err }
+
+
+// Get a set of prices from int64 values
+func toPrices(values ...int64) []*big.Int {
+    prices := make([]*big.Int, len(values))
+    for vi, value := range values {
+        prices[vi] = big.NewInt(value)
+    }
+    return prices
+}
+
+
+func TestGetMedian(t *testing.T) {
+    tests := []struct {
+        name string
+        prices []*big.Int
+        expected int64
+    }{
+        {"single", toPrices(100), 100},
+        {"odd", toPrices(300, 100, 200), 200},
+        {"even", toPrices(400, 100, 300, 200), 250},
+        {"even rounds down", toPrices(100, 102, 101, 104), 101},
+        {"outlier", toPrices(100, 101, 99, 1000000), 100},
+        {"duplicates", toPrices(5, 5, 5, 1), 5},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            input := make([]*big.Int, len(test.prices))
+            copy(input, test.prices)
+            if median := getMedian(test.prices); median.Cmp(big.NewInt(test.expected)) != 0 {
+                t.Errorf("median is %s, expected %d", median.String(), test.expected)
+            }
+            for pi := range input {
+                if input[pi] != test.prices[pi] {
+                    t.Errorf("input prices were reordered")
+                }
+            }
+        })
+    }
+}
+
+
+func TestGetMean(t *testing.T) {
+    tests := []struct {
+        name string
+        prices []*big.Int
+        expected int64
+    }{
+        {"single", toPrices(100), 100},
+        {"even", toPrices(100, 200), 150},
+        {"rounds down", toPrices(1, 2), 1},
+        {"outlier", toPrices(100, 100, 100, 1000), 325},
+    }
+    for _, test := range tests {
+        if mean := getMean(test.prices); mean.Cmp(big.NewInt(test.expected)) != 0 {
+            t.Errorf("%s: mean is %s, expected %d", test.name, mean.String(), test.expected)
+        }
+    }
+}
+
+
+func TestGetDeviation(t *testing.T) {
+    tests := []struct {
+        price int64
+        reference int64
+        expected float64
+    }{
+        {100, 100, 0},
+        {105, 100, 5},
+        {95, 100, 5},
+        {200, 100, 100},
+        {1, 100, 99},
+    }
+    for _, test := range tests {
+        if deviation := getDeviation(big.NewInt(test.price), big.NewInt(test.reference)); math.Abs(deviation - test.expected) > 1e-9 {
+            t.Errorf("deviation of %d from %d is %f%%, expected %f%%", test.price, test.reference, deviation, test.expected)
+        }
+    }
+}
+
+
+func TestAggregatorGetRplPrice(t *testing.T) {
+    tests := []struct {
+        name string
+        aggregation string
+        tolerance float64
+        sources []PriceSource
+        expected int64
+        errorContains string
+    }{
+        {
+            name: "median within tolerance",
+            aggregation: AggregationMedian,
+            tolerance: 5,
+            sources: []PriceSource{&fixedSource{name: "a", price: big.NewInt(1000)}, &fixedSource{name: "b", price: big.NewInt(1020)}, &fixedSource{name: "c", price: big.NewInt(990)}},
+            expected: 1000,
+        },
+        {
+            name: "median ignores an outlier without tolerance",
+            aggregation: AggregationMedian,
+            sources: []PriceSource{&fixedSource{name: "a", price: big.NewInt(1000)}, &fixedSource{name: "b", price: big.NewInt(1010)}, &fixedSource{name: "c", price: big.NewInt(5000)}},
+            expected: 1010,
+        },
+        {
+            name: "outlier exceeds tolerance",
+            aggregation: AggregationMedian,
+            tolerance: 5,
+            sources: []PriceSource{&fixedSource{name: "a", price: big.NewInt(1000)}, &fixedSource{name: "b", price: big.NewInt(1010)}, &fixedSource{name: "outlier", price: big.NewInt(5000)}},
+            errorContains: "outlier price deviates",
+        },
+        {
+            name: "outlier moves the mean",
+            aggregation: AggregationMean,
+            sources: []PriceSource{&fixedSource{name: "a", price: big.NewInt(1000)}, &fixedSource{name: "b", price: big.NewInt(1000)}, &fixedSource{name: "c", price: big.NewInt(4000)}},
+            expected: 2000,
+        },
+        {
+            name: "every source must agree with the mean",
+            aggregation: AggregationMean,
+            tolerance: 10,
+            sources: []PriceSource{&fixedSource{name: "a", price: big.NewInt(1000)}, &fixedSource{name: "b", price: big.NewInt(1300)}},
+            errorContains: "deviates from the mean price",
+        },
+        {
+            name: "failed source",
+            aggregation: AggregationMedian,
+            sources: []PriceSource{&fixedSource{name: "a", price: big.NewInt(1000)}, &fixedSource{name: "broken", err: errors.New("no data")}},
+            errorContains: "from broken: no data",
+        },
+        {
+            name: "zero price",
+            aggregation: AggregationMedian,
+            sources: []PriceSource{&fixedSource{name: "a", price: big.NewInt(1000)}, &fixedSource{name: "zero", price: big.NewInt(0)}},
+            errorContains: "zero returned an invalid RPL price",
+        },
+        {
+            name: "missing price",
+            aggregation: AggregationMedian,
+            sources: []PriceSource{&fixedSource{name: "missing"}},
+            errorContains: "missing returned an invalid RPL price",
+        },
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            aggregator := &Aggregator{sources: test.sources, aggregation: test.aggregation, tolerance: test.tolerance}
+            price, sourcePrices, err := aggregator.GetRplPrice(mockHeadBlock)
+            if len(sourcePrices) != len(test.sources) {
+                t.Errorf("got %d source prices, expected %d", len(sourcePrices), len(test.sources))
+            }
+            if test.errorContains != "" {
+                if err == nil || !strings.Contains(err.Error(), test.errorContains) {
+                    t.Fatalf("expected an error containing '%s', got %v", test.errorContains, err)
+                }
+                if price != nil {
+                    t.Errorf("expected no price, got %s", price.String())
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            if price.Cmp(big.NewInt(test.expected)) != 0 {
+                t.Errorf("price is %s, expected %d", price.String(), test.expected)
+            }
+        })
+    }
+}
+
+
+func TestNewAggregator(t *testing.T) {
+    client := newMockChainClient(t, nil)
+    tests := []struct {
+        name string
+        aggregation string
+        tolerance float64
+        sources []config.RplPriceSource
+        weth string
+        errorContains string
+    }{
+        {name: "default source", sources: nil},
+        {name: "invalid aggregation", aggregation: "mode", errorContains: "Invalid RPL price aggregation"},
+        {name: "negative tolerance", tolerance: -1, errorContains: "Invalid RPL price tolerance"},
+        {name: "unknown source", sources: []config.RplPriceSource{{Type: "coingecko", Address: mockOtherAddress.Hex()}}, errorContains: "Unknown price source type"},
+        {name: "invalid address", sources: []config.RplPriceSource{{Type: SourceTypeAggregator, Address: "0x1234"}}, errorContains: "Invalid aggregator price source contract address"},
+        {name: "invalid TWAP period", sources: []config.RplPriceSource{{Type: SourceTypeUniswapV3, Address: mockOtherAddress.Hex(), TwapPeriod: "soon"}}, weth: mockWethAddress.Hex(), errorContains: "TWAP period"},
+        {name: "uniswap without WETH", sources: []config.RplPriceSource{{Type: SourceTypeUniswapV2, Address: mockOtherAddress.Hex()}}, errorContains: "requires the WETH token address"},
+        {name: "uniswap with WETH", sources: []config.RplPriceSource{{Type: SourceTypeUniswapV2, Address: mockOtherAddress.Hex()}, {Type: SourceTypeUniswapV3, Address: mockOtherAddress.Hex(), TwapPeriod: "1h"}}, weth: mockWethAddress.Hex()},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            var cfg config.RocketPoolConfig
+            cfg.Rocketpool.RplTokenAddress = mockRplAddress.Hex()
+            cfg.Rocketpool.WethTokenAddress = test.weth
+            cfg.Smartnode.RplPriceAggregation = test.aggregation
+            cfg.Smartnode.RplPriceTolerance = test.tolerance
+            cfg.Smartnode.RplPriceSources = test.sources
+            aggregator, err := NewAggregator(cfg, client, nil)
+            if test.errorContains != "" {
+                if err == nil || !strings.Contains(err.Error(), test.errorContains) {
+                    t.Fatalf("expected an error containing '%s', got %v", test.errorContains, err)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            expectedSources := len(test.sources)
+            if expectedSources == 0 {
+                expectedSources = 1
+            }
+            if len(aggregator.sources) != expectedSources {
+                t.Errorf("got %d sources, expected %d", len(aggregator.sources), expectedSources)
+            }
+        })
+    }
+}
@@ -0,0 +1,94 @@
+package prices
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/rocket-pool/rocketpool-go/rocketpool"
+)
+
+// Chainlink-style price feed aggregator ABI
+const aggregatorABI = `[{"inputs":[],"name":"decimals","outputs":[{"internalType":"uint8","name":"","type":"uint8"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"latestRoundData","outputs":[{"internalType":"uint80","name":"roundId","type":"uint80"},{"internalType":"int256","name":"answer","type":"int256"},{"internalType":"uint256","name":"startedAt","type":"uint256"},{"internalType":"uint256","name":"updatedAt","type":"uint256"},{"internalType":"uint80","name":"answeredInRound","type":"uint80"}],"stateMutability":"view","type":"function"}]`
+
+
+// Gets the RPL price from a Chainlink-style RPL / ETH price feed aggregator
+type aggregatorSource struct {
+    client *ethclient.Client
+    aggregator *rocketpool.Contract
+    maxAge time.Duration
+}
+
+
+// Create price feed aggregator price source
+func newAggregatorSource(client *ethclient.Client, aggregatorAddress common.Address, maxAge time.Duration) (*aggregatorSource, error) {
+    aggregator, err := newContract(client, aggregatorAddress, aggregatorABI)
+    if err != nil {
+        return nil, err
+    }
+    return &aggregatorSource{
+        client: client,
+        aggregator: aggregator,
+        maxAge: maxAge,
+    }, nil
+}
+
+
+// Get the source name
+func (s *aggregatorSource) GetName() string {
+    return fmt.Sprintf("price feed %s", s.aggregator.Address.Hex())
+}
+
+
+// Get the RPL price
+func (s *aggregatorSource) GetRplPrice(opts *bind.CallOpts) (*big.Int, error) {
+
+    // Get the feed decimals
+    decimals := new(uint8)
+    if err := s.aggregator.Call(opts, decimals, "decimals"); err != nil {
+        return nil, fmt.Errorf("Could not get price feed decimals: %w", err)
+    }
+
+    // Get the latest answer
+    roundData, err := callMulti(s.aggregator, opts, "latestRoundData")
+    if err != nil {
+        return nil, fmt.Errorf("Could not get price feed round data: %w", err)
+    }
+    if len(roundData) != 5 {
+        return nil, fmt.Errorf("Could not decode price feed round data")
+    }
+    answer, ok0 := roundData[1].(*big.Int)
+    updatedAt, ok1 := roundData[3].(*big.Int)
+    if !(ok0 && ok1) {
+        return nil, fmt.Errorf("Could not decode price feed round data")
+    }
+    if answer.Sign() <= 0 {
+        return nil, fmt.Errorf("Price feed %s returned an invalid answer of %s", s.aggregator.Address.Hex(), answer.String())
+    }
+
+    // Check the answer is recent enough
+    if s.maxAge > 0 {
+        blockTime, err := getBlockTime(s.client, opts)
+        if err != nil {
+            return nil, err
+        }
+        if age := time.Duration(int64(blockTime) - updatedAt.Int64()) * time.Second; age > s.maxAge {
+            return nil, fmt.Errorf("Price feed %s answer is stale (last updated %s ago, max age %s)", s.aggregator.Address.Hex(), age, s.maxAge)
+        }
+    }
+
+    // Scale the answer to 18 decimals
+    rplPrice := new(big.Int).Set(answer)
+    if *decimals < 18 {
+        rplPrice.Mul(rplPrice, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(18 - *decimals)), nil))
+    } else if *decimals > 18 {
+        rplPrice.Div(rplPrice, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(*decimals - 18)), nil))
+    }
+
+    // Return
+    return rplPrice, nil
+
+}
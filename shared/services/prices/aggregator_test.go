@@ -0,0 +1,69 @@
+package prices
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+
+func TestAggregatorSource(t *testing.T) {
+    feedAddress := common.HexToAddress("0x4e155ed98afe9034b7a5962f6c84c86d869daa9d")
+    tests := []struct {
+        name string
+        decimals uint8
+        answer *big.Int
+        age uint64
+        maxAge time.Duration
+        expected *big.Int
+        errorContains string
+    }{
+        {name: "18 decimals", decimals: 18, answer: big.NewInt(15000000000000000), expected: big.NewInt(15000000000000000)},
+        {name: "8 decimals", decimals: 8, answer: big.NewInt(1500000), expected: big.NewInt(15000000000000000)},
+        {name: "20 decimals", decimals: 20, answer: big.NewInt(1500000000000000000), expected: big.NewInt(15000000000000000)},
+        {name: "recent answer", decimals: 18, answer: big.NewInt(1), age: 3600, maxAge: 2 * time.Hour, expected: big.NewInt(1)},
+        {name: "stale answer", decimals: 18, answer: big.NewInt(1), age: 3 * 3600, maxAge: 2 * time.Hour, errorContains: "answer is stale"},
+        {name: "stale answer without max age", decimals: 18, answer: big.NewInt(1), age: 30 * 86400, expected: big.NewInt(1)},
+        {name: "zero answer", decimals: 18, answer: big.NewInt(0), errorContains: "invalid answer"},
+        {name: "negative answer", decimals: 18, answer: big.NewInt(-1), errorContains: "invalid answer"},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+
+            // Deploy mock feed
+            feed := newMockContract(t, aggregatorABI)
+            feed.methods["decimals"] = func(block uint64, args []interface{}) ([]interface{}, error) {
+                return []interface{}{test.decimals}, nil
+            }
+            feed.methods["latestRoundData"] = func(block uint64, args []interface{}) ([]interface{}, error) {
+                updatedAt := new(big.Int).SetUint64(mockBlockTimestamp(block) - test.age)
+                return []interface{}{big.NewInt(1), test.answer, updatedAt, updatedAt, big.NewInt(1)}, nil
+            }
+            client := newMockChainClient(t, map[common.Address]*mockContract{feedAddress: feed})
+            source, err := newAggregatorSource(client, feedAddress, test.maxAge)
+            if err != nil {
+                t.Fatal(err)
+            }
+
+            // Get price
+            price, err := source.GetRplPrice(&bind.CallOpts{BlockNumber: big.NewInt(mockHeadBlock)})
+            if test.errorContains != "" {
+                if err == nil || !strings.Contains(err.Error(), test.errorContains) {
+                    t.Fatalf("expected an error containing '%s', got %v", test.errorContains, err)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            if price.Cmp(test.expected) != 0 {
+                t.Errorf("price is %s, expected %s", price.String(), test.expected.String())
+            }
+
+        })
+    }
+}
@@ -0,0 +1,155 @@
+package prices
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Mock chain settings
+const (
+    mockHeadBlock = 1000
+    mockGenesisTime = 1600000000
+    mockBlockTime = 12
+)
+
+// Mock token addresses
+var (
+    mockRplAddress = common.HexToAddress("0xd33526068d116ce69f19a9ee46f0bd304f21a51f")
+    mockWethAddress = common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2")
+    mockOtherAddress = common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+)
+
+
+// A mock contract; calls are handled by method name and receive the block they are made at
+type mockContract struct {
+    abi abi.ABI
+    methods map[string]func(block uint64, args []interface{}) ([]interface{}, error)
+}
+
+
+// A mock chain with a fixed head, serving contract calls & block headers over JSON-RPC
+type mockChain struct {
+    contracts map[common.Address]*mockContract
+}
+
+
+// The mock chain's eth namespace
+type mockEthAPI struct {
+    chain *mockChain
+}
+type mockCallArgs struct {
+    From *common.Address    `json:"from"`
+    To *common.Address      `json:"to"`
+    Data hexutil.Bytes      `json:"data"`
+}
+
+
+// Create a mock contract from an ABI
+func newMockContract(t *testing.T, contractAbi string) *mockContract {
+    parsedAbi, err := abi.JSON(strings.NewReader(contractAbi))
+    if err != nil {
+        t.Fatal(err)
+    }
+    return &mockContract{
+        abi: parsedAbi,
+        methods: map[string]func(block uint64, args []interface{}) ([]interface{}, error){},
+    }
+}
+
+
+// Get a client connected to a mock chain with a set of deployed mock contracts
+func newMockChainClient(t *testing.T, contracts map[common.Address]*mockContract) *ethclient.Client {
+    server := rpc.NewServer()
+    if err := server.RegisterName("eth", &mockEthAPI{chain: &mockChain{contracts: contracts}}); err != nil {
+        t.Fatal(err)
+    }
+    client := rpc.DialInProc(server)
+    t.Cleanup(func() {
+        client.Close()
+        server.Stop()
+    })
+    return ethclient.NewClient(client)
+}
+
+
+// Get the mock timestamp of a block
+func mockBlockTimestamp(block uint64) uint64 {
+    return mockGenesisTime + (block * mockBlockTime)
+}
+
+
+// Get a block number from an RPC block argument
+func (api *mockEthAPI) getBlock(blockNumber rpc.BlockNumber) (uint64, error) {
+    if blockNumber < 0 {
+        return mockHeadBlock, nil
+    }
+    if uint64(blockNumber) > mockHeadBlock {
+        return 0, fmt.Errorf("block %d is after the head", blockNumber)
+    }
+    return uint64(blockNumber), nil
+}
+
+
+// eth_blockNumber
+func (api *mockEthAPI) BlockNumber() hexutil.Uint64 {
+    return hexutil.Uint64(mockHeadBlock)
+}
+
+
+// eth_getBlockByNumber
+func (api *mockEthAPI) GetBlockByNumber(blockNumber rpc.BlockNumber, fullTx bool) (*types.Header, error) {
+    block, err := api.getBlock(blockNumber)
+    if err != nil {
+        return nil, err
+    }
+    return &types.Header{
+        Number: new(big.Int).SetUint64(block),
+        Time: mockBlockTimestamp(block),
+        Difficulty: big.NewInt(0),
+    }, nil
+}
+
+
+// eth_call
+func (api *mockEthAPI) Call(args mockCallArgs, blockNumber rpc.BlockNumber) (hexutil.Bytes, error) {
+    block, err := api.getBlock(blockNumber)
+    if err != nil {
+        return nil, err
+    }
+    if args.To == nil {
+        return nil, fmt.Errorf("missing call target")
+    }
+    contract, exists := api.chain.contracts[*args.To]
+    if !exists {
+        return hexutil.Bytes{}, nil
+    }
+    if len(args.Data) < 4 {
+        return nil, fmt.Errorf("execution reverted")
+    }
+    method, err := contract.abi.MethodById(args.Data[:4])
+    if err != nil {
+        return nil, fmt.Errorf("execution reverted")
+    }
+    handler, exists := contract.methods[method.Name]
+    if !exists {
+        return nil, fmt.Errorf("execution reverted")
+    }
+    inputs, err := method.Inputs.Unpack(args.Data[4:])
+    if err != nil {
+        return nil, err
+    }
+    outputs, err := handler(block, inputs)
+    if err != nil {
+        return nil, err
+    }
+    return method.Outputs.Pack(outputs...)
+}
@@ -0,0 +1,43 @@
+package prices
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/rocket-pool/smartnode/shared/services/contracts"
+)
+
+
+// Gets the RPL price from the 1inch off-chain oracle
+type oneInchSource struct {
+    oio *contracts.OneInchOracle
+    rplAddress common.Address
+}
+
+
+// Create 1inch oracle price source
+func newOneInchSource(oio *contracts.OneInchOracle, rplAddress common.Address) *oneInchSource {
+    return &oneInchSource{
+        oio: oio,
+        rplAddress: rplAddress,
+    }
+}
+
+
+// Get the source name
+func (s *oneInchSource) GetName() string {
+    return "1inch oracle"
+}
+
+
+// Get the RPL price
+func (s *oneInchSource) GetRplPrice(opts *bind.CallOpts) (*big.Int, error) {
+    rplPrice, err := s.oio.GetRateToEth(opts, s.rplAddress, true)
+    if err != nil {
+        return nil, fmt.Errorf("Could not get RPL rate from the 1inch oracle: %w", err)
+    }
+    return rplPrice, nil
+}
@@ -0,0 +1,63 @@
+package prices
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/rocket-pool/smartnode/shared/services/contracts"
+)
+
+
+func TestOneInchSource(t *testing.T) {
+    oracleAddress := common.HexToAddress("0x07d91f5fb9bf7798734c3f606db065549f6893bb")
+    tests := []struct {
+        name string
+        rate *big.Int
+        err error
+        expected *big.Int
+    }{
+        {name: "rate", rate: big.NewInt(12345678900000000), expected: big.NewInt(12345678900000000)},
+        {name: "revert", err: errors.New("execution reverted")},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+
+            // Deploy mock oracle
+            oracle := newMockContract(t, contracts.OneInchOracleABI)
+            oracle.methods["getRateToEth"] = func(block uint64, args []interface{}) ([]interface{}, error) {
+                if args[0].(common.Address) != mockRplAddress || !args[1].(bool) {
+                    return nil, errors.New("unexpected arguments")
+                }
+                if test.err != nil {
+                    return nil, test.err
+                }
+                return []interface{}{test.rate}, nil
+            }
+            client := newMockChainClient(t, map[common.Address]*mockContract{oracleAddress: oracle})
+            oio, err := contracts.NewOneInchOracle(oracleAddress, client)
+            if err != nil {
+                t.Fatal(err)
+            }
+
+            // Get price
+            price, err := newOneInchSource(oio, mockRplAddress).GetRplPrice(&bind.CallOpts{BlockNumber: big.NewInt(mockHeadBlock)})
+            if test.expected == nil {
+                if err == nil {
+                    t.Fatalf("expected an error, got price %s", price.String())
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            if price.Cmp(test.expected) != 0 {
+                t.Errorf("price is %s, expected %s", price.String(), test.expected.String())
+            }
+
+        })
+    }
+}
@@ -0,0 +1,124 @@
+package prices
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/rocket-pool/rocketpool-go/rocketpool"
+
+	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/contracts"
+)
+
+// Price source types
+const (
+    SourceTypeOneInch = "oneinch"
+    SourceTypeUniswapV2 = "uniswapv2"
+    SourceTypeUniswapV3 = "uniswapv3"
+    SourceTypeAggregator = "aggregator"
+)
+
+// Settings
+const DefaultTwapPeriod = 30 * time.Minute
+
+
+// A source of the RPL price in ETH
+type PriceSource interface {
+
+    // Get the name of the source, for logging
+    GetName() string
+
+    // Get the RPL price in wei per RPL, at the block set in the call options
+    GetRplPrice(opts *bind.CallOpts) (*big.Int, error)
+
+}
+
+
+// Create a price source from its configuration
+// Uniswap sources must be RPL / WETH pools, so require the WETH token address
+func NewPriceSource(sourceConfig config.RplPriceSource, client *ethclient.Client, oio *contracts.OneInchOracle, rplAddress, wethAddress common.Address) (PriceSource, error) {
+
+    // Parse common settings
+    var address common.Address
+    if sourceConfig.Type != SourceTypeOneInch {
+        if !common.IsHexAddress(sourceConfig.Address) {
+            return nil, fmt.Errorf("Invalid %s price source contract address '%s'", sourceConfig.Type, sourceConfig.Address)
+        }
+        address = common.HexToAddress(sourceConfig.Address)
+    }
+    if (sourceConfig.Type == SourceTypeUniswapV2 || sourceConfig.Type == SourceTypeUniswapV3) && wethAddress == (common.Address{}) {
+        return nil, fmt.Errorf("The %s price source requires the WETH token address to be configured", sourceConfig.Type)
+    }
+    twapPeriod := DefaultTwapPeriod
+    if sourceConfig.TwapPeriod != "" {
+        var err error
+        twapPeriod, err = time.ParseDuration(sourceConfig.TwapPeriod)
+        if err != nil || twapPeriod <= 0 {
+            return nil, fmt.Errorf("Invalid %s price source TWAP period '%s'", sourceConfig.Type, sourceConfig.TwapPeriod)
+        }
+    }
+    var maxAge time.Duration
+    if sourceConfig.MaxAge != "" {
+        var err error
+        maxAge, err = time.ParseDuration(sourceConfig.MaxAge)
+        if err != nil || maxAge < 0 {
+            return nil, fmt.Errorf("Invalid %s price source max age '%s'", sourceConfig.Type, sourceConfig.MaxAge)
+        }
+    }
+
+    // Create source
+    switch sourceConfig.Type {
+        case SourceTypeOneInch:
+            return newOneInchSource(oio, rplAddress), nil
+        case SourceTypeUniswapV2:
+            return newUniswapV2Source(client, address, rplAddress, wethAddress, twapPeriod)
+        case SourceTypeUniswapV3:
+            return newUniswapV3Source(client, address, rplAddress, wethAddress, twapPeriod)
+        case SourceTypeAggregator:
+            return newAggregatorSource(client, address, maxAge)
+    }
+    return nil, fmt.Errorf("Unknown price source type '%s'; must be '%s', '%s', '%s' or '%s'", sourceConfig.Type, SourceTypeOneInch, SourceTypeUniswapV2, SourceTypeUniswapV3, SourceTypeAggregator)
+
+}
+
+
+// Create a contract wrapper from an ABI
+func newContract(client *ethclient.Client, address common.Address, contractAbi string) (*rocketpool.Contract, error) {
+    parsedAbi, err := abi.JSON(strings.NewReader(contractAbi))
+    if err != nil {
+        return nil, fmt.Errorf("Could not decode contract ABI: %w", err)
+    }
+    return &rocketpool.Contract{
+        Contract: bind.NewBoundContract(address, parsedAbi, client, client, client),
+        Address: &address,
+        ABI: &parsedAbi,
+        Client: client,
+    }, nil
+}
+
+
+// Call a contract method with multiple return values
+func callMulti(contract *rocketpool.Contract, opts *bind.CallOpts, method string, params ...interface{}) ([]interface{}, error) {
+    results := []interface{}{}
+    if err := contract.Contract.Call(opts, &results, method, params...); err != nil {
+        return nil, err
+    }
+    return results, nil
+}
+
+
+// Get the timestamp of the block set in the call options
+func getBlockTime(client *ethclient.Client, opts *bind.CallOpts) (uint64, error) {
+    header, err := client.HeaderByNumber(context.Background(), opts.BlockNumber)
+    if err != nil {
+        return 0, fmt.Errorf("Could not get block header: %w", err)
+    }
+    return header.Time, nil
+}
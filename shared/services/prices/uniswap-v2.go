@@ -0,0 +1,160 @@
+package prices
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/rocket-pool/rocketpool-go/rocketpool"
+)
+
+// Uniswap v2 pair ABI
+const uniswapV2PairABI = `[{"inputs":[],"name":"token0","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"token1","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"getReserves","outputs":[{"internalType":"uint112","name":"_reserve0","type":"uint112"},{"internalType":"uint112","name":"_reserve1","type":"uint112"},{"internalType":"uint32","name":"_blockTimestampLast","type":"uint32"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"price0CumulativeLast","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"price1CumulativeLast","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+
+// The average block time, used to find the block at the start of the TWAP period
+const averageBlockTime = 12 * time.Second
+
+
+// Gets the RPL price from the time-weighted average price of a Uniswap v2 RPL / WETH pair
+// The TWAP is computed from the pair's cumulative prices at the target block and at a block approximately one TWAP period earlier
+type uniswapV2Source struct {
+    client *ethclient.Client
+    pair *rocketpool.Contract
+    rplAddress common.Address
+    wethAddress common.Address
+    twapPeriod time.Duration
+}
+
+
+// Create Uniswap v2 price source
+func newUniswapV2Source(client *ethclient.Client, pairAddress, rplAddress, wethAddress common.Address, twapPeriod time.Duration) (*uniswapV2Source, error) {
+    pair, err := newContract(client, pairAddress, uniswapV2PairABI)
+    if err != nil {
+        return nil, err
+    }
+    return &uniswapV2Source{
+        client: client,
+        pair: pair,
+        rplAddress: rplAddress,
+        wethAddress: wethAddress,
+        twapPeriod: twapPeriod,
+    }, nil
+}
+
+
+// Get the source name
+func (s *uniswapV2Source) GetName() string {
+    return fmt.Sprintf("Uniswap v2 pair %s (%s TWAP)", s.pair.Address.Hex(), s.twapPeriod)
+}
+
+
+// Get the RPL price
+func (s *uniswapV2Source) GetRplPrice(opts *bind.CallOpts) (*big.Int, error) {
+
+    // Get the block at the start of the TWAP period
+    if opts.BlockNumber == nil {
+        blockNumber, err := s.client.BlockNumber(context.Background())
+        if err != nil {
+            return nil, fmt.Errorf("Could not get the latest block number: %w", err)
+        }
+        opts = &bind.CallOpts{BlockNumber: new(big.Int).SetUint64(blockNumber), Context: opts.Context}
+    }
+    periodBlocks := big.NewInt(int64(s.twapPeriod / averageBlockTime))
+    if periodBlocks.Sign() == 0 { periodBlocks.SetInt64(1) }
+    startOpts := &bind.CallOpts{BlockNumber: new(big.Int).Sub(opts.BlockNumber, periodBlocks), Context: opts.Context}
+
+    // Check the pair is RPL / WETH and which token is RPL
+    token0 := new(common.Address)
+    if err := s.pair.Call(opts, token0, "token0"); err != nil {
+        return nil, fmt.Errorf("Could not get Uniswap v2 pair token0: %w", err)
+    }
+    token1 := new(common.Address)
+    if err := s.pair.Call(opts, token1, "token1"); err != nil {
+        return nil, fmt.Errorf("Could not get Uniswap v2 pair token1: %w", err)
+    }
+    var rplIsToken0 bool
+    switch {
+        case *token0 == s.rplAddress && *token1 == s.wethAddress: rplIsToken0 = true
+        case *token1 == s.rplAddress && *token0 == s.wethAddress: rplIsToken0 = false
+        default: return nil, fmt.Errorf("Uniswap v2 pair %s is not an RPL / WETH pair (tokens %s and %s)", s.pair.Address.Hex(), token0.Hex(), token1.Hex())
+    }
+
+    // Get the cumulative prices at the start & end of the period
+    startCumulative, startTime, err := s.getCumulativePrice(startOpts, rplIsToken0)
+    if err != nil {
+        return nil, err
+    }
+    endCumulative, endTime, err := s.getCumulativePrice(opts, rplIsToken0)
+    if err != nil {
+        return nil, err
+    }
+    if endTime <= startTime {
+        return nil, fmt.Errorf("Invalid Uniswap v2 TWAP period from %d to %d", startTime, endTime)
+    }
+
+    // Get the average price; cumulative prices are UQ112x112 values and may overflow
+    priceDelta := new(big.Int).Sub(endCumulative, startCumulative)
+    if priceDelta.Sign() < 0 {
+        priceDelta.Add(priceDelta, new(big.Int).Lsh(big.NewInt(1), 256))
+    }
+    averagePrice := priceDelta.Div(priceDelta, new(big.Int).SetUint64(endTime - startTime))
+    rplPrice := averagePrice.Mul(averagePrice, big.NewInt(1e18))
+    rplPrice.Rsh(rplPrice, 112)
+
+    // Return
+    return rplPrice, nil
+
+}
+
+
+// Get the pair's cumulative RPL price and the block time at a block, including price accumulated since the last pair update
+func (s *uniswapV2Source) getCumulativePrice(opts *bind.CallOpts, rplIsToken0 bool) (*big.Int, uint64, error) {
+
+    // Get the block time
+    blockTime, err := getBlockTime(s.client, opts)
+    if err != nil {
+        return nil, 0, err
+    }
+
+    // Get the reserves & last cumulative price
+    reserves, err := callMulti(s.pair, opts, "getReserves")
+    if err != nil {
+        return nil, 0, fmt.Errorf("Could not get Uniswap v2 pair reserves: %w", err)
+    }
+    if len(reserves) != 3 {
+        return nil, 0, fmt.Errorf("Could not decode Uniswap v2 pair reserves")
+    }
+    reserve0, ok0 := reserves[0].(*big.Int)
+    reserve1, ok1 := reserves[1].(*big.Int)
+    lastTime, ok2 := reserves[2].(uint32)
+    if !(ok0 && ok1 && ok2) {
+        return nil, 0, fmt.Errorf("Could not decode Uniswap v2 pair reserves")
+    }
+    cumulative := new(*big.Int)
+    method := "price0CumulativeLast"
+    reserveRpl, reserveEth := reserve0, reserve1
+    if !rplIsToken0 {
+        method = "price1CumulativeLast"
+        reserveRpl, reserveEth = reserve1, reserve0
+    }
+    if err := s.pair.Call(opts, cumulative, method); err != nil {
+        return nil, 0, fmt.Errorf("Could not get Uniswap v2 pair cumulative price: %w", err)
+    }
+    cumulativePrice := new(big.Int).Set(*cumulative)
+
+    // Add the price accumulated since the last update, as the pair would on its next update
+    elapsed := uint32(blockTime) - lastTime
+    if elapsed > 0 && reserveRpl.Sign() > 0 {
+        spotPrice := new(big.Int).Lsh(reserveEth, 112)
+        spotPrice.Div(spotPrice, reserveRpl)
+        cumulativePrice.Add(cumulativePrice, spotPrice.Mul(spotPrice, big.NewInt(int64(elapsed))))
+    }
+
+    // Return
+    return cumulativePrice, blockTime, nil
+
+}
@@ -0,0 +1,192 @@
+package prices
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/rocketpool-go/utils/eth"
+)
+
+
+// Mock Uniswap v2 pair state; reserves change from the initial to the final values after the change block
+type mockUniswapV2Pair struct {
+    token0 common.Address
+    token1 common.Address
+    initialReserves [2]*big.Int
+    finalReserves [2]*big.Int
+    changeBlock uint64
+    updateLag uint64
+    cumulativeOffset *big.Int
+}
+
+
+// Get the UQ112x112 price of one token in the other
+func getUQ112Price(reserveIn, reserveOut *big.Int) *big.Int {
+    price := new(big.Int).Lsh(reserveOut, 112)
+    return price.Div(price, reserveIn)
+}
+
+
+// Get the pair's reserves at a block
+func (p *mockUniswapV2Pair) getReserves(block uint64) [2]*big.Int {
+    if p.finalReserves[0] != nil && block > p.changeBlock {
+        return p.finalReserves
+    }
+    return p.initialReserves
+}
+
+
+// Get the pair's cumulative price of a token at a time, since genesis; cumulative prices wrap at 2^256
+func (p *mockUniswapV2Pair) getCumulativePrice(token int, timestamp uint64) *big.Int {
+    other := 1 - token
+    initialPrice := getUQ112Price(p.initialReserves[token], p.initialReserves[other])
+    cumulative := new(big.Int)
+    changeTime := mockBlockTimestamp(p.changeBlock)
+    if p.finalReserves[0] == nil || timestamp <= changeTime {
+        cumulative.Mul(initialPrice, new(big.Int).SetUint64(timestamp - mockGenesisTime))
+    } else {
+        finalPrice := getUQ112Price(p.finalReserves[token], p.finalReserves[other])
+        cumulative.Mul(initialPrice, new(big.Int).SetUint64(changeTime - mockGenesisTime))
+        cumulative.Add(cumulative, new(big.Int).Mul(finalPrice, new(big.Int).SetUint64(timestamp - changeTime)))
+    }
+    if p.cumulativeOffset != nil {
+        cumulative.Add(cumulative, p.cumulativeOffset)
+    }
+    return cumulative.Mod(cumulative, new(big.Int).Lsh(big.NewInt(1), 256))
+}
+
+
+// Deploy the pair as a mock contract
+func (p *mockUniswapV2Pair) deploy(t *testing.T) *mockContract {
+    pair := newMockContract(t, uniswapV2PairABI)
+    pair.methods["token0"] = func(block uint64, args []interface{}) ([]interface{}, error) {
+        return []interface{}{p.token0}, nil
+    }
+    pair.methods["token1"] = func(block uint64, args []interface{}) ([]interface{}, error) {
+        return []interface{}{p.token1}, nil
+    }
+    pair.methods["getReserves"] = func(block uint64, args []interface{}) ([]interface{}, error) {
+        reserves := p.getReserves(block)
+        return []interface{}{reserves[0], reserves[1], uint32(mockBlockTimestamp(block) - p.updateLag)}, nil
+    }
+    pair.methods["price0CumulativeLast"] = func(block uint64, args []interface{}) ([]interface{}, error) {
+        return []interface{}{p.getCumulativePrice(0, mockBlockTimestamp(block) - p.updateLag)}, nil
+    }
+    pair.methods["price1CumulativeLast"] = func(block uint64, args []interface{}) ([]interface{}, error) {
+        return []interface{}{p.getCumulativePrice(1, mockBlockTimestamp(block) - p.updateLag)}, nil
+    }
+    return pair
+}
+
+
+func TestUniswapV2Source(t *testing.T) {
+    pairAddress := common.HexToAddress("0x70ea56e46266f0137bac6b75710e3546f47c855d")
+    twapPeriod := 30 * time.Minute
+    startBlock := uint64(mockHeadBlock - 150)
+
+    // RPL prices of 0.01 and 0.02 ETH
+    rplReserve := eth.EthToWei(1000)
+    wethReserve := eth.EthToWei(10)
+    wethReserve2 := eth.EthToWei(20)
+    spotPrice := getUQ112Price(rplReserve, wethReserve)
+    spotPrice2 := getUQ112Price(rplReserve, wethReserve2)
+    toRplPrice := func(price *big.Int) *big.Int {
+        rplPrice := new(big.Int).Mul(price, big.NewInt(1e18))
+        return rplPrice.Rsh(rplPrice, 112)
+    }
+
+    // The TWAP across a price change half-way through the period
+    changeBlock := uint64(mockHeadBlock - 75)
+    changedTwap := new(big.Int).Mul(spotPrice, new(big.Int).SetUint64(mockBlockTimestamp(changeBlock) - mockBlockTimestamp(startBlock)))
+    changedTwap.Add(changedTwap, new(big.Int).Mul(spotPrice2, new(big.Int).SetUint64(mockBlockTimestamp(mockHeadBlock) - mockBlockTimestamp(changeBlock))))
+    changedTwap.Div(changedTwap, new(big.Int).SetUint64(mockBlockTimestamp(mockHeadBlock) - mockBlockTimestamp(startBlock)))
+
+    // Offset cumulative prices so they overflow during the period
+    wrapOffset := new(big.Int).Lsh(big.NewInt(1), 256)
+    wrapOffset.Sub(wrapOffset, new(big.Int).Mul(spotPrice, new(big.Int).SetUint64(mockBlockTimestamp(mockHeadBlock - 10) - mockGenesisTime)))
+
+    tests := []struct {
+        name string
+        pair mockUniswapV2Pair
+        expected *big.Int
+        errorContains string
+    }{
+        {
+            name: "RPL is token0",
+            pair: mockUniswapV2Pair{token0: mockRplAddress, token1: mockWethAddress, initialReserves: [2]*big.Int{rplReserve, wethReserve}},
+            expected: toRplPrice(spotPrice),
+        },
+        {
+            name: "RPL is token1",
+            pair: mockUniswapV2Pair{token0: mockWethAddress, token1: mockRplAddress, initialReserves: [2]*big.Int{wethReserve, rplReserve}},
+            expected: toRplPrice(spotPrice),
+        },
+        {
+            name: "accumulates since the last update",
+            pair: mockUniswapV2Pair{token0: mockRplAddress, token1: mockWethAddress, initialReserves: [2]*big.Int{rplReserve, wethReserve}, updateLag: 100},
+            expected: toRplPrice(spotPrice),
+        },
+        {
+            name: "price change during the period",
+            pair: mockUniswapV2Pair{token0: mockRplAddress, token1: mockWethAddress, initialReserves: [2]*big.Int{rplReserve, wethReserve}, finalReserves: [2]*big.Int{rplReserve, wethReserve2}, changeBlock: changeBlock},
+            expected: toRplPrice(changedTwap),
+        },
+        {
+            name: "cumulative price overflow",
+            pair: mockUniswapV2Pair{token0: mockRplAddress, token1: mockWethAddress, initialReserves: [2]*big.Int{rplReserve, wethReserve}, cumulativeOffset: wrapOffset},
+            expected: toRplPrice(spotPrice),
+        },
+        {
+            name: "not paired with WETH",
+            pair: mockUniswapV2Pair{token0: mockRplAddress, token1: mockOtherAddress, initialReserves: [2]*big.Int{rplReserve, wethReserve}},
+            errorContains: "is not an RPL / WETH pair",
+        },
+        {
+            name: "no RPL token",
+            pair: mockUniswapV2Pair{token0: mockOtherAddress, token1: mockWethAddress, initialReserves: [2]*big.Int{rplReserve, wethReserve}},
+            errorContains: "is not an RPL / WETH pair",
+        },
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+
+            // Deploy mock pair
+            client := newMockChainClient(t, map[common.Address]*mockContract{pairAddress: test.pair.deploy(t)})
+            source, err := newUniswapV2Source(client, pairAddress, mockRplAddress, mockWethAddress, twapPeriod)
+            if err != nil {
+                t.Fatal(err)
+            }
+
+            // Get price
+            price, err := source.GetRplPrice(&bind.CallOpts{BlockNumber: big.NewInt(mockHeadBlock)})
+            if test.errorContains != "" {
+                if err == nil || !strings.Contains(err.Error(), test.errorContains) {
+                    t.Fatalf("expected an error containing '%s', got %v", test.errorContains, err)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            if price.Cmp(test.expected) != 0 {
+                t.Errorf("price is %s, expected %s", price.String(), test.expected.String())
+            }
+
+        })
+    }
+
+    // Prices default to the latest block
+    pair := mockUniswapV2Pair{token0: mockRplAddress, token1: mockWethAddress, initialReserves: [2]*big.Int{rplReserve, wethReserve}}
+    client := newMockChainClient(t, map[common.Address]*mockContract{pairAddress: pair.deploy(t)})
+    source, err := newUniswapV2Source(client, pairAddress, mockRplAddress, mockWethAddress, twapPeriod)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if price, err := source.GetRplPrice(&bind.CallOpts{}); err != nil || price.Cmp(toRplPrice(spotPrice)) != 0 {
+        t.Errorf("latest price is %v (%v), expected %s", price, err, toRplPrice(spotPrice).String())
+    }
+}
@@ -0,0 +1,100 @@
+package prices
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/rocket-pool/rocketpool-go/rocketpool"
+)
+
+// Uniswap v3 pool ABI
+const uniswapV3PoolABI = `[{"inputs":[],"name":"token0","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"token1","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint32[]","name":"secondsAgos","type":"uint32[]"}],"name":"observe","outputs":[{"internalType":"int56[]","name":"tickCumulatives","type":"int56[]"},{"internalType":"uint160[]","name":"secondsPerLiquidityCumulativeX128s","type":"uint160[]"}],"stateMutability":"view","type":"function"}]`
+
+
+// Gets the RPL price from the time-weighted average tick of a Uniswap v3 RPL / WETH pool
+type uniswapV3Source struct {
+    pool *rocketpool.Contract
+    rplAddress common.Address
+    wethAddress common.Address
+    twapPeriod time.Duration
+}
+
+
+// Create Uniswap v3 price source
+func newUniswapV3Source(client *ethclient.Client, poolAddress, rplAddress, wethAddress common.Address, twapPeriod time.Duration) (*uniswapV3Source, error) {
+    pool, err := newContract(client, poolAddress, uniswapV3PoolABI)
+    if err != nil {
+        return nil, err
+    }
+    return &uniswapV3Source{
+        pool: pool,
+        rplAddress: rplAddress,
+        wethAddress: wethAddress,
+        twapPeriod: twapPeriod,
+    }, nil
+}
+
+
+// Get the source name
+func (s *uniswapV3Source) GetName() string {
+    return fmt.Sprintf("Uniswap v3 pool %s (%s TWAP)", s.pool.Address.Hex(), s.twapPeriod)
+}
+
+
+// Get the RPL price
+func (s *uniswapV3Source) GetRplPrice(opts *bind.CallOpts) (*big.Int, error) {
+
+    // Check the pool is RPL / WETH and which token is RPL
+    token0 := new(common.Address)
+    if err := s.pool.Call(opts, token0, "token0"); err != nil {
+        return nil, fmt.Errorf("Could not get Uniswap v3 pool token0: %w", err)
+    }
+    token1 := new(common.Address)
+    if err := s.pool.Call(opts, token1, "token1"); err != nil {
+        return nil, fmt.Errorf("Could not get Uniswap v3 pool token1: %w", err)
+    }
+    var rplIsToken0 bool
+    switch {
+        case *token0 == s.rplAddress && *token1 == s.wethAddress: rplIsToken0 = true
+        case *token1 == s.rplAddress && *token0 == s.wethAddress: rplIsToken0 = false
+        default: return nil, fmt.Errorf("Uniswap v3 pool %s is not an RPL / WETH pool (tokens %s and %s)", s.pool.Address.Hex(), token0.Hex(), token1.Hex())
+    }
+
+    // Get the tick cumulatives at the start & end of the period
+    periodSeconds := uint32(s.twapPeriod / time.Second)
+    observations, err := callMulti(s.pool, opts, "observe", []uint32{periodSeconds, 0})
+    if err != nil {
+        return nil, fmt.Errorf("Could not get Uniswap v3 pool observations: %w", err)
+    }
+    if len(observations) != 2 {
+        return nil, fmt.Errorf("Could not decode Uniswap v3 pool observations")
+    }
+    tickCumulatives, ok := observations[0].([]*big.Int)
+    if !ok || len(tickCumulatives) != 2 {
+        return nil, fmt.Errorf("Could not decode Uniswap v3 pool tick cumulatives")
+    }
+
+    // Get the average tick, rounding towards negative infinity
+    tickDelta := new(big.Int).Sub(tickCumulatives[1], tickCumulatives[0])
+    averageTick := new(big.Int).Div(tickDelta, big.NewInt(int64(periodSeconds)))
+    if !averageTick.IsInt64() {
+        return nil, fmt.Errorf("Invalid Uniswap v3 average tick %s", averageTick.String())
+    }
+
+    // Get the price of token0 in token1 (1.0001 ^ tick), inverting it if RPL is token1
+    tick := float64(averageTick.Int64())
+    if !rplIsToken0 {
+        tick = -tick
+    }
+    price := new(big.Float).SetFloat64(math.Pow(1.0001, tick))
+    rplPrice, _ := price.Mul(price, new(big.Float).SetFloat64(1e18)).Int(nil)
+
+    // Return
+    return rplPrice, nil
+
+}
@@ -0,0 +1,91 @@
+package prices
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+
+func TestUniswapV3Source(t *testing.T) {
+    poolAddress := common.HexToAddress("0xe42318ea3b998e8355a3da364eb21f89087a4a3d")
+    twapPeriod := 30 * time.Minute
+    periodSeconds := int64(twapPeriod / time.Second)
+
+    // Get the expected RPL price for an average tick
+    tickPrice := func(tick int64) *big.Float {
+        return new(big.Float).SetFloat64(math.Pow(1.0001, float64(tick)) * 1e18)
+    }
+
+    tests := []struct {
+        name string
+        token0 common.Address
+        token1 common.Address
+        tickDelta int64
+        observeError error
+        expected *big.Float
+        errorContains string
+    }{
+        {name: "RPL is token0", token0: mockRplAddress, token1: mockWethAddress, tickDelta: -46054 * periodSeconds, expected: tickPrice(-46054)},
+        {name: "RPL is token1", token0: mockWethAddress, token1: mockRplAddress, tickDelta: 46054 * periodSeconds, expected: tickPrice(-46054)},
+        {name: "average tick rounds down", token0: mockRplAddress, token1: mockWethAddress, tickDelta: (-46054 * periodSeconds) - 1, expected: tickPrice(-46055)},
+        {name: "positive average tick", token0: mockRplAddress, token1: mockWethAddress, tickDelta: (1000 * periodSeconds) + 1, expected: tickPrice(1000)},
+        {name: "not paired with WETH", token0: mockRplAddress, token1: mockOtherAddress, errorContains: "is not an RPL / WETH pool"},
+        {name: "WETH paired with another token", token0: mockOtherAddress, token1: mockWethAddress, errorContains: "is not an RPL / WETH pool"},
+        {name: "observation too old", token0: mockRplAddress, token1: mockWethAddress, observeError: errors.New("execution reverted: OLD"), errorContains: "Could not get Uniswap v3 pool observations"},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+
+            // Deploy mock pool
+            pool := newMockContract(t, uniswapV3PoolABI)
+            pool.methods["token0"] = func(block uint64, args []interface{}) ([]interface{}, error) {
+                return []interface{}{test.token0}, nil
+            }
+            pool.methods["token1"] = func(block uint64, args []interface{}) ([]interface{}, error) {
+                return []interface{}{test.token1}, nil
+            }
+            pool.methods["observe"] = func(block uint64, args []interface{}) ([]interface{}, error) {
+                if test.observeError != nil {
+                    return nil, test.observeError
+                }
+                secondsAgos := args[0].([]uint32)
+                if len(secondsAgos) != 2 || int64(secondsAgos[0]) != periodSeconds || secondsAgos[1] != 0 {
+                    return nil, errors.New("unexpected observation times")
+                }
+                startCumulative := big.NewInt(-5000000000)
+                endCumulative := new(big.Int).Add(startCumulative, big.NewInt(test.tickDelta))
+                return []interface{}{[]*big.Int{startCumulative, endCumulative}, []*big.Int{big.NewInt(0), big.NewInt(0)}}, nil
+            }
+            client := newMockChainClient(t, map[common.Address]*mockContract{poolAddress: pool})
+            source, err := newUniswapV3Source(client, poolAddress, mockRplAddress, mockWethAddress, twapPeriod)
+            if err != nil {
+                t.Fatal(err)
+            }
+
+            // Get price
+            price, err := source.GetRplPrice(&bind.CallOpts{BlockNumber: big.NewInt(mockHeadBlock)})
+            if test.errorContains != "" {
+                if err == nil || !strings.Contains(err.Error(), test.errorContains) {
+                    t.Fatalf("expected an error containing '%s', got %v", test.errorContains, err)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            expected, _ := test.expected.Int(nil)
+            difference := new(big.Int).Sub(price, expected)
+            if difference.CmpAbs(big.NewInt(1000)) > 0 {
+                t.Errorf("price is %s, expected %s", price.String(), expected.String())
+            }
+
+        })
+    }
+}
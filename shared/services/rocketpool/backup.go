@@ -0,0 +1,325 @@
+package rocketpool
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alessio/shellescape"
+	"github.com/blang/semver/v4"
+	"github.com/mitchellh/go-homedir"
+
+	"github.com/rocket-pool/smartnode/shared/services/backup"
+	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/native"
+)
+
+// Backup settings
+const (
+    InterchangeFileEnv = "INTERCHANGE_FILE"
+    ValidatorContainerInterchangeFile = "/validators/slashing-protection.json"
+)
+
+
+// A backup of the node's state, with any parts which could not be backed up
+type NodeBackup struct {
+    Archive []byte
+    Manifest *backup.Manifest
+    Warnings []string
+}
+
+
+// Create a backup archive of the node's wallet, settings, validator keystores & slashing protection data
+// Slashing protection data is exported in EIP-3076 interchange format if the selected eth2 client supports it
+func (c *Client) BackupService(composeFiles []string, includePassword bool) (*NodeBackup, error) {
+
+    // Load config
+    cfg, err := c.LoadMergedConfig()
+    if err != nil {
+        return nil, err
+    }
+    userConfig, err := c.LoadUserConfig()
+    if err != nil {
+        return nil, err
+    }
+    configPath, err := homedir.Expand(c.configPath)
+    if err != nil {
+        return nil, err
+    }
+    sudo, err := c.getSudo()
+    if err != nil {
+        return nil, err
+    }
+
+    // Initialize backup
+    nodeBackup := &NodeBackup{
+        Manifest: &backup.Manifest{
+            CreatedAt: time.Now().UTC(),
+            ConfigVersion: userConfig.Version,
+            ChainID: cfg.Chains.Eth1.ChainID,
+            Eth2Client: cfg.Chains.Eth2.Client.Selected,
+            NativeMode: cfg.Native.Enabled,
+            IncludesPassword: includePassword,
+        },
+        Warnings: []string{},
+    }
+    if version, err := c.GetServiceVersion(); err != nil {
+        nodeBackup.Warnings = append(nodeBackup.Warnings, fmt.Sprintf("The service version could not be recorded: %s", err.Error()))
+    } else {
+        nodeBackup.Manifest.ServiceVersion = version
+    }
+
+    // Export slashing protection data
+    if err := c.exportSlashingProtection(cfg, configPath, sudo); err != nil {
+        nodeBackup.Warnings = append(nodeBackup.Warnings, fmt.Sprintf("Slashing protection data could not be exported in interchange format, so only the %s client's own slashing protection database is included: %s", cfg.Chains.Eth2.Client.Selected, err.Error()))
+    }
+
+    // Get the node data paths which exist
+    paths := []string{backup.SettingsPath, backup.PrometheusPath, backup.WalletPath, backup.ValidatorsPath}
+    if includePassword {
+        paths = append(paths, backup.PasswordPath)
+    }
+    output, err := c.readOutput(fmt.Sprintf("cd %s && for path in %s; do if [ -e \"$path\" ]; then echo \"$path\"; fi; done", shellescape.Quote(configPath), quoteAll(paths)))
+    if err != nil {
+        return nil, fmt.Errorf("Could not list node data: %w", err)
+    }
+    existingPaths := strings.Fields(string(output))
+    if len(existingPaths) == 0 {
+        return nil, fmt.Errorf("No node data was found in %s.", shellescape.Quote(configPath))
+    }
+    if !containsString(existingPaths, backup.WalletPath) {
+        nodeBackup.Warnings = append(nodeBackup.Warnings, "The node wallet has not been initialized, so the backup does not include a wallet.")
+    }
+    if includePassword && !containsString(existingPaths, backup.PasswordPath) {
+        nodeBackup.Warnings = append(nodeBackup.Warnings, "The node password has not been set, so the backup does not include a password.")
+        nodeBackup.Manifest.IncludesPassword = false
+    }
+
+    // Archive node data
+    data, err := c.readOutput(fmt.Sprintf("%star -czf - -C %s %s", sudo, shellescape.Quote(configPath), quoteAll(existingPaths)))
+    if err != nil {
+        return nil, fmt.Errorf("Could not archive node data: %w", err)
+    }
+
+    // Read custom compose files
+    composeFileContents := map[string][]byte{}
+    for fi, composeFile := range composeFiles {
+        hostPath, err := homedir.Expand(composeFile)
+        if err != nil {
+            return nil, err
+        }
+        relPath, err := filepath.Rel(configPath, hostPath)
+        if err != nil || relPath == ".." || strings.HasPrefix(relPath, "../") {
+            nodeBackup.Warnings = append(nodeBackup.Warnings, fmt.Sprintf("The compose file %s is outside the config folder %s, so it was not backed up.", shellescape.Quote(hostPath), shellescape.Quote(configPath)))
+            continue
+        }
+        contents, err := c.readFile(hostPath)
+        if err != nil {
+            return nil, fmt.Errorf("Could not read compose file %s: %w", shellescape.Quote(hostPath), err)
+        }
+        composeFileContents[relPath] = contents
+        nodeBackup.Manifest.ComposeFiles = append(nodeBackup.Manifest.ComposeFiles, backup.ComposeFile{
+            Path: fmt.Sprintf("%s/%d-%s", backup.ComposeFolder, fi, filepath.Base(hostPath)),
+            HostPath: relPath,
+        })
+    }
+
+    // Build backup archive
+    if nodeBackup.Archive, err = backup.Pack(nodeBackup.Manifest, data, composeFileContents); err != nil {
+        return nil, err
+    }
+    return nodeBackup, nil
+
+}
+
+
+// Check that a backup can be restored to the installed service
+// Returns warnings for differences which don't prevent the backup from being restored
+func (c *Client) CheckBackupCompatibility(manifest *backup.Manifest) ([]string, error) {
+    warnings := []string{}
+
+    // Check the settings schema version
+    if manifest.ConfigVersion > config.CurrentSchemaVersion {
+        return nil, fmt.Errorf("The backup's settings use a newer schema version (v%d) than this version of Rocket Pool supports (v%d). Please update the Rocket Pool CLI and service.", manifest.ConfigVersion, config.CurrentSchemaVersion)
+    }
+
+    // Check the network
+    cfg, err := c.LoadMergedConfig()
+    if err != nil {
+        return nil, err
+    }
+    if manifest.ChainID != "" && cfg.Chains.Eth1.ChainID != "" && manifest.ChainID != cfg.Chains.Eth1.ChainID {
+        return nil, fmt.Errorf("The backup is for chain ID %s, but the installed service is for chain ID %s. Please install the service for the backup's network first.", manifest.ChainID, cfg.Chains.Eth1.ChainID)
+    }
+
+    // Check the service version
+    serviceVersion, err := c.GetServiceVersion()
+    if err != nil {
+        return nil, fmt.Errorf("Could not check the installed service version; please make sure the service is installed: %w", err)
+    }
+    if manifest.ServiceVersion == "" {
+        warnings = append(warnings, "The backup does not record the service version it was created with.")
+    } else {
+        backupVersion, err := semver.Make(manifest.ServiceVersion)
+        if err != nil {
+            return nil, fmt.Errorf("Could not parse the backup's service version '%s': %w", manifest.ServiceVersion, err)
+        }
+        installedVersion, err := semver.Make(serviceVersion)
+        if err != nil {
+            return nil, fmt.Errorf("Could not parse the installed service version '%s': %w", serviceVersion, err)
+        }
+        if backupVersion.GT(installedVersion) {
+            return nil, fmt.Errorf("The backup was created with service version %s, which is newer than the installed version %s. Please install at least version %s with 'rocketpool service install' first.", backupVersion, installedVersion, backupVersion)
+        }
+        if backupVersion.LT(installedVersion) {
+            warnings = append(warnings, fmt.Sprintf("The backup was created with service version %s and will be restored to version %s; its settings will be migrated.", backupVersion, installedVersion))
+        }
+    }
+
+    // Check the service mode
+    if manifest.NativeMode != cfg.Native.Enabled {
+        if manifest.NativeMode {
+            warnings = append(warnings, "The backup was created in native mode; its settings will run the service natively under systemd.")
+        } else {
+            warnings = append(warnings, "The backup was created in docker mode; its settings will run the service with docker.")
+        }
+    }
+
+    // Return
+    return warnings, nil
+
+}
+
+
+// Check whether the process responsible for validator duties is running; returns its name
+func (c *Client) IsValidatorRunning() (bool, string, error) {
+    cfg, err := c.LoadMergedConfig()
+    if err != nil {
+        return false, "", err
+    }
+    if cfg.Native.Enabled {
+        unitName := native.GetUnitName(cfg, native.GetValidatorService(cfg))
+        state, err := c.GetNativeUnitState(unitName)
+        if err != nil {
+            return false, "", err
+        }
+        return state.ActiveState != "inactive" && state.ActiveState != "failed", unitName, nil
+    }
+    containerName := getValidatorContainerName(cfg)
+    state, err := c.inspectContainer(containerName)
+    if err != nil {
+        return false, "", err
+    }
+    return state != nil && state.Running, containerName, nil
+}
+
+
+// Restore node data & custom compose files from a backup
+// Compose files are restored to their paths relative to the config folder; if a password is given, it is written as the node password
+func (c *Client) RestoreService(data []byte, composeFiles map[string][]byte, password string) error {
+
+    // Get config path & sudo prefix
+    configPath, err := homedir.Expand(c.configPath)
+    if err != nil {
+        return err
+    }
+    sudo, err := c.getSudo()
+    if err != nil {
+        return err
+    }
+
+    // Extract node data
+    cmd, err := c.newCommand(fmt.Sprintf("mkdir -p %s && %star -xzf - -C %s", shellescape.Quote(configPath), sudo, shellescape.Quote(configPath)))
+    if err != nil { return err }
+    defer func() {
+        _ = cmd.Close()
+    }()
+    cmd.SetStdin(bytes.NewReader(data))
+    if err := cmd.Run(); err != nil {
+        return fmt.Errorf("Could not extract node data to %s: %w", shellescape.Quote(configPath), err)
+    }
+
+    // Write node password
+    if password != "" {
+        passwordPath := filepath.Join(configPath, backup.PasswordPath)
+        if _, err := c.readOutput(fmt.Sprintf("%smkdir -p %s", sudo, shellescape.Quote(filepath.Dir(passwordPath)))); err != nil {
+            return fmt.Errorf("Could not create folder for %s: %w", shellescape.Quote(passwordPath), err)
+        }
+        if err := c.writeHostFile(passwordPath, password, "600", sudo); err != nil {
+            return err
+        }
+    }
+
+    // Write custom compose files
+    for relPath, contents := range composeFiles {
+        hostPath := filepath.Join(configPath, relPath)
+        if filepath.IsAbs(relPath) || !strings.HasPrefix(hostPath, filepath.Clean(configPath) + string(filepath.Separator)) {
+            return fmt.Errorf("Could not restore compose file '%s': compose files must be restored within the config folder %s.", relPath, shellescape.Quote(configPath))
+        }
+        if _, err := c.readOutput(fmt.Sprintf("mkdir -p %s", shellescape.Quote(filepath.Dir(hostPath)))); err != nil {
+            return fmt.Errorf("Could not create folder for %s: %w", shellescape.Quote(hostPath), err)
+        }
+        if err := c.uploadFile(hostPath, contents); err != nil {
+            return err
+        }
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Export the validator client's slashing protection data to the validators folder in EIP-3076 interchange format
+// The export command is run in the validator container, or on the host in native mode
+func (c *Client) exportSlashingProtection(cfg config.RocketPoolConfig, configPath, sudo string) error {
+
+    // Remove any previous export so a stale file isn't backed up
+    hostInterchangeFile := filepath.Join(configPath, backup.SlashingProtectionPath)
+    if _, err := c.readOutput(fmt.Sprintf("%srm -f %s", sudo, shellescape.Quote(hostInterchangeFile))); err != nil {
+        return fmt.Errorf("Could not remove previous export %s: %w", shellescape.Quote(hostInterchangeFile), err)
+    }
+
+    // Get export command
+    eth2Client := cfg.GetSelectedEth2Client()
+    if eth2Client == nil {
+        return errors.New("No Eth 2.0 client selected")
+    }
+    if eth2Client.SlashingProtectionExport == "" {
+        return fmt.Errorf("The %s client does not support slashing protection export", eth2Client.Name)
+    }
+
+    // Run export
+    var cmd string
+    if cfg.Native.Enabled {
+        cmd = fmt.Sprintf("%s=%s sh -c %s", InterchangeFileEnv, shellescape.Quote(hostInterchangeFile), shellescape.Quote(eth2Client.SlashingProtectionExport))
+    } else {
+        containerName := getValidatorContainerName(cfg)
+        state, err := c.inspectContainer(containerName)
+        if err != nil {
+            return err
+        }
+        if state == nil || !state.Running {
+            return fmt.Errorf("The %s container must be running to export its slashing protection data", containerName)
+        }
+        cmd = fmt.Sprintf("docker exec -e %s=%s %s sh -c %s", InterchangeFileEnv, ValidatorContainerInterchangeFile, shellescape.Quote(containerName), shellescape.Quote(eth2Client.SlashingProtectionExport))
+    }
+    if _, err := c.readOutput(cmd); err != nil {
+        return fmt.Errorf("Could not export slashing protection data: %w", err)
+    }
+    return nil
+
+}
+
+
+// Get the name of the container responsible for validator duties
+// Nimbus runs its validator client in the beacon client container
+func getValidatorContainerName(cfg config.RocketPoolConfig) string {
+    if cfg.Chains.Eth2.Client.Selected == "nimbus" {
+        return cfg.Smartnode.ProjectName + "_" + config.ContainerEth2
+    }
+    return cfg.Smartnode.ProjectName + "_" + config.ContainerValidator
+}
@@ -1,19 +1,26 @@
 package rocketpool
 
 import (
-	"bufio"
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
 	"math/big"
+	gonet "net"
+	"net/http"
 	"os"
 	osUser "os/user"
+	"path/filepath"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/a8m/envsubst"
+	dockerclient "github.com/docker/docker/client"
+	"github.com/dustin/go-humanize"
 	"github.com/fatih/color"
 	"github.com/urfave/cli"
 	"golang.org/x/crypto/ssh"
@@ -23,22 +30,28 @@ import (
 	"github.com/blang/semver/v4"
 	externalip "github.com/glendc/go-external-ip"
 	"github.com/mitchellh/go-homedir"
+	"github.com/rocket-pool/smartnode/shared/services/bundle"
 	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/orchestrator"
 	"github.com/rocket-pool/smartnode/shared/utils/net"
 )
 
 // Config
 const (
+    InstallerBundleURL = "https://github.com/rocket-pool/smartnode-install/releases/download/%s/" + bundle.BundleFile
     InstallerURL = "https://github.com/rocket-pool/smartnode-install/releases/download/%s/install.sh"
     UpdateTrackerURL = "https://github.com/rocket-pool/smartnode-install/releases/download/%s/install-update-tracker.sh"
 
     GlobalConfigFile = "config.yml"
     UserConfigFile = "settings.yml"
+    AppliedConfigFile = ".applied-config.yml"
     ComposeFile = "docker-compose.yml"
     MetricsComposeFile = "docker-compose-metrics.yml"
     PrometheusTemplate = "prometheus.tmpl"
     PrometheusFile = "prometheus.yml"
 
+    DockerSocketPath = "/var/run/docker.sock"
+
     APIContainerSuffix = "_api"
     APIBinPath = "/go/bin/rocketpool"
 
@@ -59,11 +72,23 @@ type Client struct {
     originalMaxPrioFee float64
     originalGasLimit uint64
     debugPrint bool
+    docker *dockerclient.Client
 }
 
 
 // Create new Rocket Pool client from CLI context
+// Connects to the node selected with --node if set; commands which support --all-nodes must handle it before creating a client
 func NewClientFromCtx(c *cli.Context) (*Client, error) {
+    if c.GlobalBool("all-nodes") {
+        return nil, errors.New("This command does not support the --all-nodes option; please select a single node with --node.")
+    }
+    profile, err := getSelectedNode(c)
+    if err != nil {
+        return nil, err
+    }
+    if profile != nil {
+        return NewClientForNode(c, *profile)
+    }
     return NewClient(c.GlobalString("config-path"), 
                      c.GlobalString("daemon-path"), 
                      c.GlobalString("host"), 
@@ -116,6 +141,9 @@ func NewClient(configPath string, daemonPath string, hostAddress string, user st
         } else {
             key, err = ssh.ParsePrivateKeyWithPassphrase(keyBytes, passphrase)
         }
+        if _, ok := err.(*ssh.PassphraseMissingError); ok {
+            return nil, fmt.Errorf("The SSH private key at %s is passphrase-protected; please provide its passphrase file with --passphrase.", keyPath)
+        }
         if err != nil {
             return nil, fmt.Errorf("Could not parse SSH private key at %s: %w", keyPath, err)
         }
@@ -176,6 +204,9 @@ func NewClient(configPath string, daemonPath string, hostAddress string, user st
 
 // Close client remote connection
 func (c *Client) Close() {
+    if c.docker != nil {
+        _ = c.docker.Close()
+    }
     if c.client != nil {
         _ = c.client.Close()
     }
@@ -193,9 +224,109 @@ func (c *Client) LoadUserConfig() (config.RocketPoolConfig, error) {
     return c.loadConfig(fmt.Sprintf("%s/%s", c.configPath, UserConfigFile))
 }
 func (c *Client) SaveUserConfig(cfg config.RocketPoolConfig) error {
+    cfg.Version = config.CurrentSchemaVersion
     return c.saveConfig(cfg, fmt.Sprintf("%s/%s", c.configPath, UserConfigFile))
 }
 
+
+// Migrate the user config to the current schema version, backing up the original settings first
+// Returns the path of the backup and the migration steps applied, or a blank path if no migration was needed
+func (c *Client) MigrateUserConfig() (string, []string, error) {
+
+    // Load the user config; there is nothing to migrate if it doesn't exist yet
+    // The settings are checked and backed up with host commands so that this works over SSH
+    path := fmt.Sprintf("%s/%s", c.configPath, UserConfigFile)
+    output, err := c.readOutput(fmt.Sprintf("if [ -e %s ]; then echo exists; fi", quoteHostPath(path)))
+    if err != nil {
+        return "", nil, fmt.Errorf("Could not check for Rocket Pool settings at %s: %w", shellescape.Quote(path), err)
+    }
+    if strings.TrimSpace(string(output)) != "exists" {
+        return "", nil, nil
+    }
+    userConfig, err := c.LoadUserConfig()
+    if err != nil {
+        return "", nil, err
+    }
+    if !config.NeedsMigration(userConfig) {
+        return "", nil, nil
+    }
+
+    // Back up the settings
+    backupPath := fmt.Sprintf("%s.v%d-%s.bak", path, userConfig.Version, time.Now().Format("20060102-150405"))
+    if _, err := c.readOutput(fmt.Sprintf("cp %s %s && chmod 600 %s", quoteHostPath(path), quoteHostPath(backupPath), quoteHostPath(backupPath))); err != nil {
+        return "", nil, fmt.Errorf("Could not back up Rocket Pool settings to %s: %w", shellescape.Quote(backupPath), err)
+    }
+
+    // Migrate & save
+    steps, err := config.Migrate(&userConfig)
+    if err != nil {
+        return "", nil, err
+    }
+    if err := c.SaveUserConfig(userConfig); err != nil {
+        return "", nil, err
+    }
+
+    // Return
+    return backupPath, steps, nil
+
+}
+
+
+// Save the merged config as the config the service is running with
+func (c *Client) SaveAppliedConfig() error {
+    cfg, err := c.LoadMergedConfig()
+    if err != nil {
+        return err
+    }
+    configBytes, err := cfg.Serialize()
+    if err != nil {
+        return err
+    }
+    path, err := homedir.Expand(fmt.Sprintf("%s/%s", c.configPath, AppliedConfigFile))
+    if err != nil {
+        return err
+    }
+    if err := ioutil.WriteFile(path, configBytes, 0644); err != nil {
+        return fmt.Errorf("Could not write applied Rocket Pool config to %s: %w", shellescape.Quote(path), err)
+    }
+    return nil
+}
+
+
+// Load the config the service was last started with; returns false if it hasn't been recorded
+func (c *Client) LoadAppliedConfig() (config.RocketPoolConfig, bool, error) {
+    path, err := homedir.Expand(fmt.Sprintf("%s/%s", c.configPath, AppliedConfigFile))
+    if err != nil {
+        return config.RocketPoolConfig{}, false, err
+    }
+    if _, err := os.Stat(path); os.IsNotExist(err) {
+        return config.RocketPoolConfig{}, false, nil
+    }
+    cfg, err := c.loadConfig(path)
+    if err != nil {
+        return config.RocketPoolConfig{}, false, err
+    }
+    return cfg, true, nil
+}
+
+
+// Validate the user config against the global config, returning any problems found
+func (c *Client) ValidateUserConfig() ([]string, error) {
+    globalConfig, err := c.LoadGlobalConfig()
+    if err != nil {
+        return nil, err
+    }
+    path, err := homedir.Expand(fmt.Sprintf("%s/%s", c.configPath, UserConfigFile))
+    if err != nil {
+        return nil, err
+    }
+    configBytes, err := ioutil.ReadFile(path)
+    if err != nil {
+        return nil, fmt.Errorf("Could not read Rocket Pool settings at %s: %w", shellescape.Quote(path), err)
+    }
+    return config.ValidateUserConfig(globalConfig, configBytes)
+}
+
 // Load the Prometheus template, do an environment variable substitution, and save it
 func (c *Client) UpdatePrometheusConfiguration(settings []config.UserParam) error {
     prometheusTemplatePath, err := homedir.Expand(fmt.Sprintf("%s/%s", c.configPath, PrometheusTemplate))
@@ -233,7 +364,7 @@ func (c *Client) UpdatePrometheusConfiguration(settings []config.UserParam) erro
     }
     err = os.Chmod(prometheusConfigPath, 0664)
     if err != nil {
-        return fmt.Errorf("Could not set Prometheus config file permissions: %w", shellescape.Quote(prometheusConfigPath), err)
+        return fmt.Errorf("Could not set Prometheus config file permissions on %s: %w", shellescape.Quote(prometheusConfigPath), err)
     }
 
     return nil
@@ -253,17 +384,60 @@ func (c *Client) LoadMergedConfig() (config.RocketPoolConfig, error) {
 }
 
 
-// Install the Rocket Pool service
-func (c *Client) InstallService(verbose, noDeps bool, network, version, path string) error {
+// Install the Rocket Pool service from a verified release bundle
+// The bundle is downloaded for the given version unless a local bundle path is given
+// The current installation is kept for rollback once the new installation succeeds
+func (c *Client) InstallService(verbose, noDeps bool, network, version, path, bundlePath string) error {
 
-    // Get installation script downloader type
-    downloader, err := c.getDownloader()
+    // Get & verify the release bundle
+    bundleFiles, err := c.getVerifiedBundle(version, bundlePath)
     if err != nil { return err }
 
+    // Store the new installation alongside the current one, with the settings it replaces kept with the current installation
+    installsPath, err := c.getInstallsPath()
+    if err != nil { return err }
+    if err := c.saveInstallSettings(installsPath); err != nil {
+        return err
+    }
+    pendingPath := filepath.Join(installsPath, PendingInstallFolder)
+    info := InstallInfo{
+        Version: version,
+        Network: network,
+        Path: path,
+        NoDeps: noDeps,
+        InstalledAt: time.Now(),
+    }
+    if err := c.storeInstall(pendingPath, bundleFiles, &info); err != nil {
+        return err
+    }
+
+    // Run the bundled installer
+    if err := c.runBundleInstaller(verbose, pendingPath, info); err != nil {
+        c.removeInstall(pendingPath)
+        return err
+    }
+
+    // Keep the current installation for rollback
+    return c.archiveCurrentInstall(installsPath)
+
+}
+
+
+// Install the Rocket Pool service with the release's install script, without verifying it
+// The current installation is kept for rollback, but the legacy installation can't be rolled back to
+func (c *Client) InstallServiceLegacy(verbose, noDeps bool, network, version, path string) error {
+
+    // Keep the current installation's settings for rollback
+    installsPath, err := c.getInstallsPath()
+    if err != nil { return err }
+    if err := c.saveInstallSettings(installsPath); err != nil {
+        return err
+    }
+
     // Get installation script flags
     flags := []string{
-        "-n", fmt.Sprintf("%s", shellescape.Quote(network)),
-        "-v", fmt.Sprintf("%s", shellescape.Quote(version)),
+        "-n", shellescape.Quote(network),
+        "-v", shellescape.Quote(version),
     }
     if path != "" {
         flags = append(flags, fmt.Sprintf("-p %s", shellescape.Quote(path)))
@@ -272,162 +446,202 @@ func (c *Client) InstallService(verbose, noDeps bool, network, version, path str
         flags = append(flags, "-d")
     }
 
-    // Initialize installation command
-    cmd, err := c.newCommand(fmt.Sprintf("%s %s | sh -s -- %s", downloader, fmt.Sprintf(InstallerURL, version), strings.Join(flags, " ")))
-    if err != nil { return err }
-    defer func() {
-        _ = cmd.Close()
-    }()
-
-    // Get command output pipes
-    cmdOut, err := cmd.StdoutPipe()
-    if err != nil { return err }
-    cmdErr, err := cmd.StderrPipe()
-    if err != nil { return err }
-
-    // Print progress from stdout
-    go (func() {
-        scanner := bufio.NewScanner(cmdOut)
-        for scanner.Scan() {
-            fmt.Println(scanner.Text())
-        }
-    })()
-
-    // Read command & error output from stderr; render in verbose mode
-    var errMessage string
-    go (func() {
-        c := color.New(DebugColor)
-        scanner := bufio.NewScanner(cmdErr)
-        for scanner.Scan() {
-            errMessage = scanner.Text()
-            if verbose {
-                _, _ = c.Println(scanner.Text())
-            }
-        }
-    })()
-
-    // Run command and return error output
-    err = cmd.Run()
-    if err != nil {
-        return fmt.Errorf("Could not install Rocket Pool service: %s", errMessage)
+    // Run the install script
+    if err := c.runLegacyScript(verbose, fmt.Sprintf(InstallerURL, version), flags); err != nil {
+        return fmt.Errorf("Could not install Rocket Pool service: %w", err)
     }
-    return nil
+
+    // Keep the current installation for rollback
+    return c.archiveCurrentInstall(installsPath)
 
 }
 
 
-// Install the update tracker
-func (c *Client) InstallUpdateTracker(verbose bool, version string) error {
+// Install the update tracker from a verified release bundle
+// The bundle is downloaded for the given version unless a local bundle path is given
+func (c *Client) InstallUpdateTracker(verbose bool, version, bundlePath string) error {
 
-    // Get installation script downloader type
-    downloader, err := c.getDownloader()
+    // Get & verify the release bundle
+    bundleFiles, err := c.getVerifiedBundle(version, bundlePath)
     if err != nil { return err }
 
-    // Get installation script flags
-    flags := []string{
-        "-v", fmt.Sprintf("%s", shellescape.Quote(version)),
+    // Store the bundle
+    installsPath, err := c.getInstallsPath()
+    if err != nil { return err }
+    trackerPath := filepath.Join(installsPath, UpdateTrackerInstallFolder)
+    if err := c.storeInstall(trackerPath, bundleFiles, nil); err != nil {
+        return err
     }
+    defer c.removeInstall(trackerPath)
 
-    // Initialize installation command
-    cmd, err := c.newCommand(fmt.Sprintf("%s %s | sh -s -- %s", downloader, fmt.Sprintf(UpdateTrackerURL, version), strings.Join(flags, " ")))
-    if err != nil { return err }
-    defer func() {
-        _ = cmd.Close()
-    }()
+    // Run the bundled update tracker installer
+    extractPath := filepath.Join(trackerPath, "bundle")
+    flags := []string{
+        "-v", shellescape.Quote(version),
+        "-l", shellescape.Quote(extractPath),
+    }
+    if err := c.runBundleScript(verbose, trackerPath, bundle.UpdateTrackerScript, flags); err != nil {
+        return fmt.Errorf("Could not install Rocket Pool update tracker: %w", err)
+    }
+    return nil
 
-    // Get command output pipes
-    cmdOut, err := cmd.StdoutPipe()
-    if err != nil { return err }
-    cmdErr, err := cmd.StderrPipe()
-    if err != nil { return err }
+}
 
-    // Print progress from stdout
-    go (func() {
-        scanner := bufio.NewScanner(cmdOut)
-        for scanner.Scan() {
-            fmt.Println(scanner.Text())
-        }
-    })()
-
-    // Read command & error output from stderr; render in verbose mode
-    var errMessage string
-    go (func() {
-        c := color.New(DebugColor)
-        scanner := bufio.NewScanner(cmdErr)
-        for scanner.Scan() {
-            errMessage = scanner.Text()
-            if verbose {
-                _, _ = c.Println(scanner.Text())
-            }
-        }
-    })()
 
-    // Run command and return error output
-    err = cmd.Run()
-    if err != nil {
-        return fmt.Errorf("Could not install Rocket Pool update tracker: %s", errMessage)
+// Install the update tracker with the release's install script, without verifying it
+func (c *Client) InstallUpdateTrackerLegacy(verbose bool, version string) error {
+    flags := []string{
+        "-v", shellescape.Quote(version),
+    }
+    if err := c.runLegacyScript(verbose, fmt.Sprintf(UpdateTrackerURL, version), flags); err != nil {
+        return fmt.Errorf("Could not install Rocket Pool update tracker: %w", err)
     }
     return nil
-
 }
 
 
 // Start the Rocket Pool service
 func (c *Client) StartService(composeFiles []string) error {
-    cmd, err := c.compose(composeFiles, "up -d")
+    if native, err := c.IsNativeMode(); err != nil {
+        return err
+    } else if native {
+        return c.startNativeService()
+    }
+    orch, err := c.getOrchestrator(composeFiles)
     if err != nil { return err }
-    return c.printOutput(cmd)
+    if err := orch.Up(context.Background(), orchestrator.UpOptions{Progress: newProgressPrinter()}); err != nil {
+        return err
+    }
+    return c.SaveAppliedConfig()
+}
+
+
+// Recreate a subset of the Rocket Pool service containers to apply config changes
+func (c *Client) RestartServiceContainers(composeFiles []string, serviceNames ...string) error {
+    if native, err := c.IsNativeMode(); err != nil {
+        return err
+    } else if native {
+        return c.restartNativeServices(serviceNames...)
+    }
+    orch, err := c.getOrchestrator(composeFiles)
+    if err != nil { return err }
+    if err := orch.Up(context.Background(), orchestrator.UpOptions{
+        NoDeps: true,
+        ForceRecreate: true,
+        Progress: newProgressPrinter(),
+    }, serviceNames...); err != nil {
+        return err
+    }
+    return c.SaveAppliedConfig()
 }
 
 
 // Pause the Rocket Pool service
 func (c *Client) PauseService(composeFiles []string) error {
-    cmd, err := c.compose(composeFiles, "stop")
+    if native, err := c.IsNativeMode(); err != nil {
+        return err
+    } else if native {
+        return c.pauseNativeService()
+    }
+    orch, err := c.getOrchestrator(composeFiles)
     if err != nil { return err }
-    return c.printOutput(cmd)
+    return orch.Stop(context.Background(), newProgressPrinter())
 }
 
 
 // Stop the Rocket Pool service
 func (c *Client) StopService(composeFiles []string) error {
-    cmd, err := c.compose(composeFiles, "down -v")
+    if native, err := c.IsNativeMode(); err != nil {
+        return err
+    } else if native {
+        return c.stopNativeService()
+    }
+    orch, err := c.getOrchestrator(composeFiles)
     if err != nil { return err }
-    return c.printOutput(cmd)
+    return orch.Down(context.Background(), true, newProgressPrinter())
+}
+
+
+// Get the Rocket Pool service container states
+func (c *Client) GetServiceStatus(composeFiles []string) ([]*orchestrator.ContainerState, error) {
+    orch, err := c.getOrchestrator(composeFiles)
+    if err != nil { return nil, err }
+    return orch.Status(context.Background())
 }
 
 
 // Print the Rocket Pool service status
 func (c *Client) PrintServiceStatus(composeFiles []string) error {
-    cmd, err := c.compose(composeFiles, "ps")
+
+    // Print unit states in native mode
+    if native, err := c.IsNativeMode(); err != nil {
+        return err
+    } else if native {
+        return c.printNativeServiceStatus()
+    }
+
+    // Get container states
+    states, err := c.GetServiceStatus(composeFiles)
     if err != nil { return err }
-    return c.printOutput(cmd)
+
+    // Print states
+    writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+    fmt.Fprintln(writer, "NAME\tSERVICE\tIMAGE\tSTATUS\tPORTS")
+    for _, state := range states {
+        status := state.Summary()
+        if state.Status != "" && !state.UpToDate {
+            status += ", outdated"
+        }
+        fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", state.Name, state.Service, state.Image, status, strings.Join(state.Ports, ", "))
+    }
+    return writer.Flush()
+
 }
 
 
 // Print the Rocket Pool service logs
 func (c *Client) PrintServiceLogs(composeFiles []string, tail string, serviceNames ...string) error {
-    sanitizedStrings := make([]string, len(serviceNames))
-    for i, serviceName := range serviceNames {
-        sanitizedStrings[i] = fmt.Sprintf("%s", shellescape.Quote(serviceName))
+    if native, err := c.IsNativeMode(); err != nil {
+        return err
+    } else if native {
+        return c.printNativeServiceLogs(tail, serviceNames...)
     }
-    cmd, err := c.compose(composeFiles, fmt.Sprintf("logs -f --tail %s %s", shellescape.Quote(tail), strings.Join(sanitizedStrings, " ")))
+    orch, err := c.getOrchestrator(composeFiles)
     if err != nil { return err }
-    return c.printOutput(cmd)
+    return orch.Logs(context.Background(), os.Stdout, tail, true, serviceNames...)
 }
 
 
 // Print the Rocket Pool service stats
 func (c *Client) PrintServiceStats(composeFiles []string) error {
 
-    // Get service container IDs
-    cmd, err := c.compose(composeFiles, "ps -q")
+    // Print unit stats in native mode
+    if native, err := c.IsNativeMode(); err != nil {
+        return err
+    } else if native {
+        return c.printNativeServiceStats()
+    }
+
+    // Get container stats
+    orch, err := c.getOrchestrator(composeFiles)
     if err != nil { return err }
-    containers, err := c.readOutput(cmd)
+    containerStats, err := orch.Stats(context.Background())
     if err != nil { return err }
-    containerIds := strings.Split(strings.TrimSpace(string(containers)), "\n")
 
     // Print stats
-    return c.printOutput(fmt.Sprintf("docker stats %s", strings.Join(containerIds, " ")))
+    writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+    fmt.Fprintln(writer, "NAME\tCPU %\tMEM USAGE / LIMIT\tMEM %\tNET I/O\tBLOCK I/O\tPIDS")
+    for _, stats := range containerStats {
+        fmt.Fprintf(writer, "%s\t%.2f%%\t%s / %s\t%.2f%%\t%s / %s\t%s / %s\t%d\n",
+            stats.Name,
+            stats.CPUPercent,
+            humanize.IBytes(stats.MemoryUsage), humanize.IBytes(stats.MemoryLimit),
+            stats.MemoryPercent,
+            humanize.Bytes(stats.NetworkRx), humanize.Bytes(stats.NetworkTx),
+            humanize.Bytes(stats.BlockRead), humanize.Bytes(stats.BlockWrite),
+            stats.Pids)
+    }
+    return writer.Flush()
 
 }
 
@@ -436,15 +650,19 @@ func (c *Client) PrintServiceStats(composeFiles []string) error {
 func (c *Client) GetServiceVersion() (string, error) {
 
     // Get service container version output
+    daemonPath, err := c.getDaemonPath()
+    if err != nil {
+        return "", err
+    }
     var cmd string
-    if c.daemonPath == "" {
+    if daemonPath == "" {
         containerName, err := c.getAPIContainerName()
         if err != nil {
             return "", err
         }
         cmd = fmt.Sprintf("docker exec %s %s --version", shellescape.Quote(containerName), shellescape.Quote(APIBinPath))
     } else {
-        cmd = fmt.Sprintf("%s --version", shellescape.Quote(c.daemonPath))
+        cmd = fmt.Sprintf("%s --version", shellescape.Quote(daemonPath))
     }
     versionBytes, err := c.readOutput(cmd)
     if err != nil {
@@ -478,63 +696,55 @@ func (c *Client) IncrementCustomNonce() {
 }
 
 
-// Get the current Docker image used by the given container
+// Get the current Docker image used by the given container; returns a blank string if the container doesn't exist
 func (c *Client) GetDockerImage(container string) (string, error) {
-
-    cmd := fmt.Sprintf("docker container inspect --format={{.Config.Image}} %s", container)
-    image, err := c.readOutput(cmd)
+    state, err := c.inspectContainer(container)
     if err != nil {
         return "", err
     }
-
-    return strings.TrimSpace(string(image)), nil
-
+    if state == nil {
+        return "", nil
+    }
+    return state.Image, nil
 }
 
 
-// Get the current Docker image used by the given container
+// Get the current status of the given container
 func (c *Client) GetDockerStatus(container string) (string, error) {
-
-    cmd := fmt.Sprintf("docker container inspect --format={{.State.Status}} %s", container)
-    status, err := c.readOutput(cmd)
+    state, err := c.inspectContainer(container)
     if err != nil {
         return "", err
     }
-
-    return strings.TrimSpace(string(status)), nil
-
+    if state == nil {
+        return "", fmt.Errorf("Container %s does not exist", container)
+    }
+    return state.Status, nil
 }
 
 
 // Get the time that the given container shut down
 func (c *Client) GetDockerContainerShutdownTime(container string) (time.Time, error) {
-
-    cmd := fmt.Sprintf("docker container inspect --format={{.State.FinishedAt}} %s", container)
-    finishTimeBytes, err := c.readOutput(cmd)
+    state, err := c.inspectContainer(container)
     if err != nil {
         return time.Time{}, err
     }
-
-    finishTime, err := time.Parse(time.RFC3339, strings.TrimSpace(string(finishTimeBytes)))
-    if err != nil {
-        return time.Time{}, fmt.Errorf("Error parsing validator container exit time [%s]: %w", string(finishTimeBytes), err)
+    if state == nil {
+        return time.Time{}, fmt.Errorf("Container %s does not exist", container)
     }
-
-    return finishTime, nil
-    
+    return state.FinishedAt, nil
 }
 
 
 // Shut down a container
 func (c *Client) StopContainer(container string) (string, error) {
-
-    cmd := fmt.Sprintf("docker stop %s", container)
-    output, err := c.readOutput(cmd)
+    docker, err := c.getDocker()
     if err != nil {
         return "", err
     }
-    return strings.TrimSpace(string(output)), nil
-    
+    if err := docker.ContainerStop(context.Background(), container, nil); err != nil {
+        return "", fmt.Errorf("Could not stop container %s: %w", container, err)
+    }
+    return container, nil
 }
 
 
@@ -554,11 +764,19 @@ func (c *Client) AssignGasSettings(maxFee float64, maxPrioFee float64, gasLimit
 
 // Load a config file
 func (c *Client) loadConfig(path string) (config.RocketPoolConfig, error) {
+    var configBytes []byte
+    if c.client != nil {
+        var err error
+        if configBytes, err = c.readOutput(fmt.Sprintf("cat %s", quoteHostPath(path))); err != nil {
+            return config.RocketPoolConfig{}, fmt.Errorf("Could not read Rocket Pool config at %s: %w", shellescape.Quote(path), err)
+        }
+        return config.Parse(configBytes)
+    }
     expandedPath, err := homedir.Expand(path)
     if err != nil {
         return config.RocketPoolConfig{}, err
     }
-    configBytes, err := ioutil.ReadFile(expandedPath)
+    configBytes, err = ioutil.ReadFile(expandedPath)
     if err != nil {
         return config.RocketPoolConfig{}, fmt.Errorf("Could not read Rocket Pool config at %s: %w", shellescape.Quote(path), err)
     }
@@ -572,6 +790,20 @@ func (c *Client) saveConfig(cfg config.RocketPoolConfig, path string) error {
     if err != nil {
         return err
     }
+    if c.client != nil {
+        // Write to a temporary file and move it into place, so an interrupted write can't leave a truncated config
+        tempPath := path + ".tmp"
+        cmd, err := c.newCommand(fmt.Sprintf("cat > %s && mv %s %s", quoteHostPath(tempPath), quoteHostPath(tempPath), quoteHostPath(path)))
+        if err != nil { return err }
+        defer func() {
+            _ = cmd.Close()
+        }()
+        cmd.SetStdin(bytes.NewReader(configBytes))
+        if err := cmd.Run(); err != nil {
+            return fmt.Errorf("Could not write Rocket Pool config to %s: %w", shellescape.Quote(path), err)
+        }
+        return nil
+    }
     expandedPath, err := homedir.Expand(path)
     if err != nil {
         return err
@@ -583,28 +815,28 @@ func (c *Client) saveConfig(cfg config.RocketPoolConfig, path string) error {
 }
 
 
-// Build a docker-compose command
-func (c *Client) compose(composeFiles []string, args string) (string, error) {
+// Get an orchestrator for the Rocket Pool service, rendering the compose files with the current config
+func (c *Client) getOrchestrator(composeFiles []string) (*orchestrator.Orchestrator, error) {
 
     // Cancel if running in non-docker mode
     if c.daemonPath != "" {
-        return "", errors.New("Command unavailable with '--daemon-path' option specified.")
+        return nil, errors.New("Command unavailable with '--daemon-path' option specified.")
     }
 
     // Load config
     cfg, err := c.LoadMergedConfig()
     if err != nil {
-        return "", err
+        return nil, err
     }
 
     // Check config
     eth1Client := cfg.GetSelectedEth1Client()
     eth2Client := cfg.GetSelectedEth2Client()
     if eth1Client == nil {
-        return "", errors.New("No Eth 1.0 client selected. Please run 'rocketpool service config' and try again.")
+        return nil, errors.New("No Eth 1.0 client selected. Please run 'rocketpool service config' and try again.")
     }
     if eth2Client == nil {
-        return "", errors.New("No Eth 2.0 client selected. Please run 'rocketpool service config' and try again.")
+        return nil, errors.New("No Eth 2.0 client selected. Please run 'rocketpool service config' and try again.")
     }
 
     // Make sure the selected eth2 is compatible with the selected eth1
@@ -621,7 +853,7 @@ func (c *Client) compose(composeFiles []string, args string) (string, error) {
         }
     }
     if !isCompatible {
-        return "", fmt.Errorf("Eth 2.0 client [%s] is incompatible with Eth 1.0 client [%s]. Please run 'rocketpool service config' and select compatible clients.", eth2Client.Name, eth1Client.Name)
+        return nil, fmt.Errorf("Eth 2.0 client [%s] is incompatible with Eth 1.0 client [%s]. Please run 'rocketpool service config' and select compatible clients.", eth2Client.Name, eth1Client.Name)
     }
 
     // Get the external IP address
@@ -635,21 +867,76 @@ func (c *Client) compose(composeFiles []string, args string) (string, error) {
         externalIP = ip.String()
     }
 
+    // Get the compose files; docker-compose-metrics.yml is included if metrics are enabled
+    expandedConfigPath, err := homedir.Expand(c.configPath)
+    if err != nil {
+        return nil, err
+    }
+    composePaths := []string{fmt.Sprintf("%s/%s", expandedConfigPath, ComposeFile)}
+    if cfg.Metrics.Enabled {
+        composePaths = append(composePaths, fmt.Sprintf("%s/%s", expandedConfigPath, MetricsComposeFile))
+    }
+    for _, composeFile := range composeFiles {
+        expandedFile, err := homedir.Expand(composeFile)
+        if err != nil {
+            return nil, err
+        }
+        composePaths = append(composePaths, expandedFile)
+    }
+    files := make([]orchestrator.File, len(composePaths))
+    for fi, composePath := range composePaths {
+        contents, err := c.readFile(composePath)
+        if err != nil {
+            return nil, fmt.Errorf("Could not read compose file %s: %w", shellescape.Quote(composePath), err)
+        }
+        files[fi] = orchestrator.File{Path: composePath, Contents: contents}
+    }
+
+    // Render the project; the config takes precedence over the local environment
+    env := getComposeEnv(cfg, externalIP)
+    if c.client == nil {
+        env = append(env, os.Environ()...)
+    }
+    project, err := orchestrator.LoadProject(cfg.Smartnode.ProjectName, expandedConfigPath, files, env)
+    if err != nil {
+        return nil, err
+    }
+    for _, warning := range project.Warnings {
+        fmt.Fprintf(os.Stderr, "Warning: %s.\n", warning)
+    }
+
+    // Get Docker client
+    docker, err := c.getDocker()
+    if err != nil {
+        return nil, err
+    }
+
+    // Return
+    return orchestrator.NewOrchestrator(docker, project), nil
+
+}
+
+
+// Get the environment variables used to render the compose files from the config, in KEY=VALUE format
+func getComposeEnv(cfg config.RocketPoolConfig, externalIP string) []string {
+
     // Set environment variables from config
     env := []string{
-        fmt.Sprintf("COMPOSE_PROJECT_NAME=%s",    shellescape.Quote(cfg.Smartnode.ProjectName)),
-        fmt.Sprintf("ROCKET_POOL_VERSION=%s",     shellescape.Quote(cfg.Smartnode.GraffitiVersion)),
-        fmt.Sprintf("SMARTNODE_IMAGE=%s",         shellescape.Quote(cfg.Smartnode.Image)),
-        fmt.Sprintf("ETH1_CLIENT=%s",             shellescape.Quote(cfg.GetSelectedEth1Client().ID)),
-        fmt.Sprintf("ETH1_IMAGE=%s",              shellescape.Quote(cfg.GetSelectedEth1Client().Image)),
-        fmt.Sprintf("ETH2_CLIENT=%s",             shellescape.Quote(cfg.GetSelectedEth2Client().ID)),
-        fmt.Sprintf("ETH2_IMAGE=%s",              shellescape.Quote(cfg.GetSelectedEth2Client().GetBeaconImage())),
-        fmt.Sprintf("VALIDATOR_CLIENT=%s",        shellescape.Quote(cfg.GetSelectedEth2Client().ID)),
-        fmt.Sprintf("VALIDATOR_IMAGE=%s",         shellescape.Quote(cfg.GetSelectedEth2Client().GetValidatorImage())),
-        fmt.Sprintf("ETH1_PROVIDER=%s",           shellescape.Quote(cfg.Chains.Eth1.Provider)),
-        fmt.Sprintf("ETH1_WS_PROVIDER=%s",        shellescape.Quote(cfg.Chains.Eth1.WsProvider)),
-        fmt.Sprintf("ETH2_PROVIDER=%s",           shellescape.Quote(cfg.Chains.Eth2.Provider)),
-        fmt.Sprintf("EXTERNAL_IP=%s",             shellescape.Quote(externalIP)),
+        fmt.Sprintf("COMPOSE_PROJECT_NAME=%s",    cfg.Smartnode.ProjectName),
+        fmt.Sprintf("ROCKET_POOL_VERSION=%s",     cfg.Smartnode.GraffitiVersion),
+        fmt.Sprintf("SMARTNODE_IMAGE=%s",         cfg.Smartnode.Image),
+        fmt.Sprintf("ETH1_CLIENT=%s",             cfg.GetSelectedEth1Client().ID),
+        fmt.Sprintf("ETH1_IMAGE=%s",              cfg.GetSelectedEth1Client().Image),
+        fmt.Sprintf("ETH2_CLIENT=%s",             cfg.GetSelectedEth2Client().ID),
+        fmt.Sprintf("ETH2_IMAGE=%s",              cfg.GetSelectedEth2Client().GetBeaconImage()),
+        fmt.Sprintf("VALIDATOR_CLIENT=%s",        cfg.GetSelectedEth2Client().ID),
+        fmt.Sprintf("VALIDATOR_IMAGE=%s",         cfg.GetSelectedEth2Client().GetValidatorImage()),
+        fmt.Sprintf("ETH1_PROVIDER=%s",           cfg.Chains.Eth1.Provider),
+        fmt.Sprintf("ETH1_WS_PROVIDER=%s",        cfg.Chains.Eth1.WsProvider),
+        fmt.Sprintf("ETH2_PROVIDER=%s",           cfg.Chains.Eth2.Provider),
+        fmt.Sprintf("EXTERNAL_IP=%s",             externalIP),
+        fmt.Sprintf("LOG_LEVEL=%s",               cfg.Logging.Level),
+        fmt.Sprintf("LOG_FORMAT=%s",              cfg.Logging.Format),
     }
     if cfg.Metrics.Enabled {
         env = append(env, "ENABLE_METRICS=1")
@@ -658,15 +945,15 @@ func (c *Client) compose(composeFiles []string, args string) (string, error) {
     }
     paramsSet := map[string]bool{}
     for _, param := range cfg.Chains.Eth1.Client.Params {
-        env = append(env, fmt.Sprintf("%s=%s", param.Env, shellescape.Quote(param.Value)))
+        env = append(env, fmt.Sprintf("%s=%s", param.Env, param.Value))
         paramsSet[param.Env] = true
     }
     for _, param := range cfg.Chains.Eth2.Client.Params {
-        env = append(env, fmt.Sprintf("%s=%s", param.Env, shellescape.Quote(param.Value)))
+        env = append(env, fmt.Sprintf("%s=%s", param.Env, param.Value))
         paramsSet[param.Env] = true
     }
     for _, setting := range cfg.Metrics.Settings {
-        env = append(env, fmt.Sprintf("%s=%s", setting.Env, shellescape.Quote(setting.Value)))
+        env = append(env, fmt.Sprintf("%s=%s", setting.Env, setting.Value))
         paramsSet[setting.Env] = true
     }
 
@@ -674,51 +961,96 @@ func (c *Client) compose(composeFiles []string, args string) (string, error) {
     for _, param := range cfg.GetSelectedEth1Client().Params {
         if _, ok := paramsSet[param.Env]; ok { continue }
         if param.Default == "" { continue }
-        env = append(env, fmt.Sprintf("%s=%s", param.Env, shellescape.Quote(param.Default)))
+        env = append(env, fmt.Sprintf("%s=%s", param.Env, param.Default))
     }
     for _, param := range cfg.GetSelectedEth2Client().Params {
         if _, ok := paramsSet[param.Env]; ok { continue }
         if param.Default == "" { continue }
-        env = append(env, fmt.Sprintf("%s=%s", param.Env, shellescape.Quote(param.Default)))
+        env = append(env, fmt.Sprintf("%s=%s", param.Env, param.Default))
     }
     for _, param := range cfg.Metrics.Params {
         if _, ok := paramsSet[param.Env]; ok { continue }
         if param.Default == "" { continue }
-        env = append(env, fmt.Sprintf("%s=%s", param.Env, shellescape.Quote(param.Default)))
+        env = append(env, fmt.Sprintf("%s=%s", param.Env, param.Default))
     }
 
-    // How many built-in compose files are we using
-    builtInFileCount := 1
-    if cfg.Metrics.Enabled {
-        builtInFileCount = 2
+    // Return
+    return env
+
+}
+
+
+// Get the Docker Engine API client, connecting to the Docker socket over SSH if configured
+// Locally, the DOCKER_HOST and DOCKER_API_VERSION environment variables are respected
+func (c *Client) getDocker() (*dockerclient.Client, error) {
+    if c.docker != nil {
+        return c.docker, nil
+    }
+    var docker *dockerclient.Client
+    var err error
+    if c.client == nil {
+        docker, err = dockerclient.NewClientWithOpts(dockerclient.WithVersion(orchestrator.APIVersion), dockerclient.FromEnv)
+    } else {
+        docker, err = dockerclient.NewClientWithOpts(dockerclient.WithVersion(orchestrator.APIVersion), dockerclient.WithHTTPClient(&http.Client{
+            Transport: &http.Transport{
+                DialContext: func(ctx context.Context, network, addr string) (gonet.Conn, error) {
+                    return c.client.Dial("unix", DockerSocketPath)
+                },
+            },
+        }))
+    }
+    if err != nil {
+        return nil, fmt.Errorf("Could not create Docker client: %w", err)
     }
+    c.docker = docker
+    return docker, nil
+}
 
-    // Set compose file flags
-    composeFileFlags := make([]string, len(composeFiles) + builtInFileCount)
-    expandedConfigPath, err := homedir.Expand(c.configPath)
+
+// Get the state of a container; returns nil if it doesn't exist
+func (c *Client) inspectContainer(container string) (*orchestrator.ContainerState, error) {
+    docker, err := c.getDocker()
     if err != nil {
-        return "", err
+        return nil, err
     }
+    return orchestrator.InspectContainer(context.Background(), docker, container)
+}
 
-    // Add the default docker-compose.yml
-    composeFileFlags[0] = fmt.Sprintf("-f %s", shellescape.Quote((fmt.Sprintf("%s/%s", expandedConfigPath, ComposeFile))))
 
-    // Add docker-compose-metrics.yml if metrics are enabled
-    if cfg.Metrics.Enabled {
-        composeFileFlags[1] = fmt.Sprintf("-f %s", shellescape.Quote((fmt.Sprintf("%s/%s", expandedConfigPath, MetricsComposeFile))))
+// Read a file on the Rocket Pool host
+func (c *Client) readFile(path string) ([]byte, error) {
+    if c.client == nil {
+        return ioutil.ReadFile(path)
     }
+    return c.readOutput(fmt.Sprintf("cat %s", quoteHostPath(path)))
+}
 
-    for fi, composeFile := range composeFiles {
-        expandedFile, err := homedir.Expand(composeFile)
-        if err != nil {
-            return "", err
-        }
-        composeFileFlags[fi+builtInFileCount] = fmt.Sprintf("-f %s", shellescape.Quote(expandedFile))
+
+// Quote a path for the host shell, leaving a leading ~/ unquoted so it is expanded to the host user's home folder
+func quoteHostPath(path string) string {
+    if strings.HasPrefix(path, "~/") {
+        return "~/" + shellescape.Quote(path[2:])
     }
+    return shellescape.Quote(path)
+}
 
-    // Return command
-    return fmt.Sprintf("%s docker-compose --project-directory %s %s %s", strings.Join(env, " "), shellescape.Quote(expandedConfigPath), strings.Join(composeFileFlags, " "), args), nil
 
+// Create a function which prints orchestration progress
+// Image pull progress is printed when a layer's status changes
+func newProgressPrinter() func(orchestrator.Progress) {
+    layerStatuses := map[string]string{}
+    return func(progress orchestrator.Progress) {
+        switch {
+            case progress.Layer != "":
+                if layerStatuses[progress.Layer] == progress.Status { return }
+                layerStatuses[progress.Layer] = progress.Status
+                fmt.Printf("%s: %s %s\n", progress.Service, progress.Layer, progress.Status)
+            case progress.Image != "":
+                fmt.Printf("%s: %s %s\n", progress.Service, progress.Status, progress.Image)
+            default:
+                fmt.Printf("%s: %s\n", progress.Service, progress.Status)
+        }
+    }
 }
 
 
@@ -739,8 +1071,12 @@ func (c *Client) callAPI(args string, otherArgs ...string) ([]byte, error) {
     }
 
     // Run the command
+    daemonPath, err := c.getDaemonPath()
+    if err != nil {
+        return []byte{}, err
+    }
     var cmd string
-    if c.daemonPath == "" {
+    if daemonPath == "" {
         containerName, err := c.getAPIContainerName()
         if err != nil {
             return []byte{}, err
@@ -748,7 +1084,7 @@ func (c *Client) callAPI(args string, otherArgs ...string) ([]byte, error) {
         cmd = fmt.Sprintf("docker exec %s %s %s %s api %s", shellescape.Quote(containerName), shellescape.Quote(APIBinPath), c.getGasOpts(), c.getCustomNonce(), args)
     } else {
         cmd = fmt.Sprintf("%s --config %s --settings %s %s %s api %s", 
-            c.daemonPath, 
+            daemonPath, 
             shellescape.Quote(fmt.Sprintf("%s/%s", c.configPath, GlobalConfigFile)), 
             shellescape.Quote(fmt.Sprintf("%s/%s", c.configPath, UserConfigFile)),
             c.getGasOpts(),
@@ -816,27 +1152,6 @@ func (c *Client) getCustomNonce() string {
 }
 
 
-// Get the first downloader available to the system
-func (c *Client) getDownloader() (string, error) {
-
-    // Check for cURL
-    hasCurl, err := c.readOutput("command -v curl")
-    if err == nil && len(hasCurl) > 0 {
-        return "curl -sL", nil
-    }
-
-    // Check for wget
-    hasWget, err := c.readOutput("command -v wget")
-    if err == nil && len(hasWget) > 0 {
-        return "wget -qO-", nil
-    }
-
-    // Return error
-    return "", errors.New("Either cURL or wget is required to begin installation.")
-
-}
-
-
 // Run a command and print its output
 func (c *Client) printOutput(cmdText string) error {
 
@@ -84,3 +84,13 @@ func (c *command) StderrPipe() (io.Reader, error) {
     }
 }
 
+
+
+// Set the command's stdin
+func (c *command) SetStdin(stdin io.Reader) {
+    if c.cmd != nil {
+        c.cmd.Stdin = stdin
+    } else {
+        c.session.Stdin = stdin
+    }
+}
@@ -0,0 +1,109 @@
+package rocketpool
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alessio/shellescape"
+	"github.com/mitchellh/go-homedir"
+)
+
+// Disk usage of the filesystem containing a path on the Rocket Pool host
+type DiskUsage struct {
+    Path string
+    Filesystem string
+    MountPoint string
+    Total uint64
+    Available uint64
+}
+
+
+// Get the paths on the Rocket Pool host which the service stores data in
+// In docker mode, chain data is stored in docker volumes under the docker root folder
+func (c *Client) GetServiceDataPaths() ([]string, error) {
+
+    // Get config path
+    configPath, err := homedir.Expand(c.configPath)
+    if err != nil {
+        return nil, err
+    }
+    paths := []string{configPath}
+
+    // Get chain data path
+    cfg, err := c.LoadMergedConfig()
+    if err != nil {
+        return nil, err
+    }
+    if cfg.Native.Enabled {
+        return append(paths, cfg.Native.DataPath), nil
+    }
+    docker, err := c.getDocker()
+    if err != nil {
+        return nil, err
+    }
+    info, err := docker.Info(context.Background())
+    if err != nil {
+        return nil, fmt.Errorf("Could not get Docker info: %w", err)
+    }
+    return append(paths, info.DockerRootDir), nil
+
+}
+
+
+// Get the disk usage of the filesystems containing paths on the Rocket Pool host
+func (c *Client) GetDiskUsage(paths ...string) ([]DiskUsage, error) {
+
+    // Run df
+    quotedPaths := make([]string, len(paths))
+    for pi, path := range paths {
+        quotedPaths[pi] = shellescape.Quote(path)
+    }
+    output, err := c.readOutput(fmt.Sprintf("df -P -k %s", strings.Join(quotedPaths, " ")))
+    if err != nil {
+        return nil, fmt.Errorf("Could not get disk usage: %w", err)
+    }
+
+    // Parse output; the first line is a header and each path has one line in order
+    lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+    if len(lines) != len(paths) + 1 {
+        return nil, fmt.Errorf("Unexpected df output:\n%s", string(output))
+    }
+    usage := make([]DiskUsage, len(paths))
+    for pi, path := range paths {
+        fields := strings.Fields(lines[pi + 1])
+        if len(fields) < 6 {
+            return nil, fmt.Errorf("Unexpected df output line: %s", lines[pi + 1])
+        }
+        total, err := strconv.ParseUint(fields[1], 10, 64)
+        if err != nil {
+            return nil, fmt.Errorf("Unexpected df output line: %s", lines[pi + 1])
+        }
+        available, err := strconv.ParseUint(fields[3], 10, 64)
+        if err != nil {
+            return nil, fmt.Errorf("Unexpected df output line: %s", lines[pi + 1])
+        }
+        usage[pi] = DiskUsage{
+            Path: path,
+            Filesystem: fields[0],
+            MountPoint: fields[5],
+            Total: total * 1024,
+            Available: available * 1024,
+        }
+    }
+
+    // Return
+    return usage, nil
+
+}
+
+
+// Check whether a TCP port is being listened on by any process on the Rocket Pool host
+func (c *Client) IsPortListening(port uint64) (bool, error) {
+    output, err := c.readOutput(fmt.Sprintf("ss -H -l -t -n %s", shellescape.Quote(fmt.Sprintf("sport = :%d", port))))
+    if err != nil {
+        return false, fmt.Errorf("Could not list listening ports: %w", err)
+    }
+    return strings.TrimSpace(string(output)) != "", nil
+}
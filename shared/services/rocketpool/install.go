@@ -0,0 +1,443 @@
+package rocketpool
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alessio/shellescape"
+	"github.com/fatih/color"
+	"github.com/mitchellh/go-homedir"
+	"gopkg.in/yaml.v2"
+
+	"github.com/rocket-pool/smartnode/shared/services/bundle"
+)
+
+// Installation settings
+const (
+    InstallsFolder = ".installs"
+    CurrentInstallFolder = "current"
+    PreviousInstallFolder = "previous"
+    PendingInstallFolder = "pending"
+    UpdateTrackerInstallFolder = "update-tracker"
+    InstallInfoFile = "install.yml"
+    InstallSettingsFile = "settings.yml"
+    BundleDownloadTimeout = 5 * time.Minute
+)
+
+
+// A release bundle with its checksum & signature
+type BundleFiles struct {
+    Bundle []byte
+    Checksum []byte
+    Signature []byte
+}
+
+
+// The options a release bundle was installed with
+type InstallInfo struct {
+    Version string                      `yaml:"version"`
+    Network string                      `yaml:"network"`
+    Path string                         `yaml:"path,omitempty"`
+    NoDeps bool                         `yaml:"noDeps,omitempty"`
+    InstalledAt time.Time               `yaml:"installedAt"`
+}
+
+
+// Get the current & previous installations; either is nil if it doesn't exist
+func (c *Client) GetInstalls() (*InstallInfo, *InstallInfo, error) {
+    installsPath, err := c.getInstallsPath()
+    if err != nil {
+        return nil, nil, err
+    }
+    current, err := c.loadInstallInfo(filepath.Join(installsPath, CurrentInstallFolder))
+    if err != nil {
+        return nil, nil, err
+    }
+    previous, err := c.loadInstallInfo(filepath.Join(installsPath, PreviousInstallFolder))
+    if err != nil {
+        return nil, nil, err
+    }
+    return current, previous, nil
+}
+
+
+// Roll the Rocket Pool service back to the previous installation, re-verifying its bundle
+// The settings in place when the previous installation was replaced are restored, and the current installation becomes the previous one
+func (c *Client) RollbackService(verbose bool) (*InstallInfo, error) {
+
+    // Get the previous installation
+    installsPath, err := c.getInstallsPath()
+    if err != nil {
+        return nil, err
+    }
+    currentPath := filepath.Join(installsPath, CurrentInstallFolder)
+    previousPath := filepath.Join(installsPath, PreviousInstallFolder)
+    info, err := c.loadInstallInfo(previousPath)
+    if err != nil {
+        return nil, err
+    }
+    if info == nil {
+        return nil, errors.New("There is no previous installation to roll back to.")
+    }
+
+    // Verify the previous bundle
+    bundleFiles := BundleFiles{}
+    for _, file := range []struct{ path string; contents *[]byte }{
+        {filepath.Join(previousPath, bundle.BundleFile), &bundleFiles.Bundle},
+        {filepath.Join(previousPath, bundle.BundleFile + bundle.ChecksumExtension), &bundleFiles.Checksum},
+        {filepath.Join(previousPath, bundle.BundleFile + bundle.SignatureExtension), &bundleFiles.Signature},
+    } {
+        if *file.contents, err = c.readFile(file.path); err != nil {
+            return nil, fmt.Errorf("Could not read %s: %w", shellescape.Quote(file.path), err)
+        }
+    }
+    if err := bundle.Verify(bundleFiles.Bundle, bundleFiles.Checksum, bundleFiles.Signature); err != nil {
+        return nil, err
+    }
+    fmt.Println("Verified the previous release bundle signature and SHA-256 checksum.")
+
+    // Swap the installations, keeping the current settings with the installation being replaced
+    configPath, err := homedir.Expand(c.configPath)
+    if err != nil {
+        return nil, err
+    }
+    settingsPath := shellescape.Quote(filepath.Join(configPath, UserConfigFile))
+    swapPath := shellescape.Quote(filepath.Join(installsPath, "swap"))
+    // There is no current installation after a legacy install
+    if _, err := c.readOutput(fmt.Sprintf("(cp %s %s 2>/dev/null || true) && rm -rf %s && if [ -d %s ]; then mv %s %s; fi && mv %s %s && if [ -d %s ]; then mv %s %s; fi && (cp %s %s 2>/dev/null || true)",
+        settingsPath, shellescape.Quote(filepath.Join(currentPath, InstallSettingsFile)),
+        swapPath,
+        shellescape.Quote(currentPath),
+        shellescape.Quote(currentPath), swapPath,
+        shellescape.Quote(previousPath), shellescape.Quote(currentPath),
+        swapPath,
+        swapPath, shellescape.Quote(previousPath),
+        shellescape.Quote(filepath.Join(currentPath, InstallSettingsFile)), settingsPath)); err != nil {
+        return nil, fmt.Errorf("Could not swap the current and previous installations: %w", err)
+    }
+
+    // Run the bundled installer
+    if err := c.runBundleInstaller(verbose, currentPath, *info); err != nil {
+        return nil, err
+    }
+    return info, nil
+
+}
+
+
+// Get a release bundle and verify it with the release key
+func (c *Client) getVerifiedBundle(version, bundlePath string) (BundleFiles, error) {
+    if err := bundle.CheckReleaseKey(); err != nil {
+        return BundleFiles{}, fmt.Errorf("%w Release bundles cannot be verified by this build; use --legacy-installer to run the unverified install script instead.", err)
+    }
+    bundleFiles, err := getBundleFiles(version, bundlePath)
+    if err != nil {
+        return BundleFiles{}, err
+    }
+    if !bundle.UsesPinnedKey() {
+        fmt.Println("Warning: this build of the Rocket Pool CLI verifies release bundles with a test key instead of the pinned release key.")
+    }
+    if err := bundle.Verify(bundleFiles.Bundle, bundleFiles.Checksum, bundleFiles.Signature); err != nil {
+        return BundleFiles{}, err
+    }
+    fmt.Println("Verified the release bundle signature and SHA-256 checksum.")
+    return bundleFiles, nil
+}
+
+
+// Get a release bundle, downloading it for a version if no local bundle path is given
+// A local bundle's checksum & signature files must be alongside it
+func getBundleFiles(version, bundlePath string) (BundleFiles, error) {
+
+    // Read a local bundle
+    if bundlePath != "" {
+        expandedPath, err := homedir.Expand(bundlePath)
+        if err != nil {
+            return BundleFiles{}, err
+        }
+        bundleFiles := BundleFiles{}
+        for _, file := range []struct{ path string; contents *[]byte }{
+            {expandedPath, &bundleFiles.Bundle},
+            {expandedPath + bundle.ChecksumExtension, &bundleFiles.Checksum},
+            {expandedPath + bundle.SignatureExtension, &bundleFiles.Signature},
+        } {
+            if *file.contents, err = ioutil.ReadFile(file.path); err != nil {
+                return BundleFiles{}, fmt.Errorf("Could not read %s: %w", shellescape.Quote(file.path), err)
+            }
+        }
+        return bundleFiles, nil
+    }
+
+    // Download the bundle
+    bundleURL := fmt.Sprintf(InstallerBundleURL, version)
+    httpClient := &http.Client{Timeout: BundleDownloadTimeout}
+    bundleFiles := BundleFiles{}
+    for _, file := range []struct{ url string; contents *[]byte }{
+        {bundleURL, &bundleFiles.Bundle},
+        {bundleURL + bundle.ChecksumExtension, &bundleFiles.Checksum},
+        {bundleURL + bundle.SignatureExtension, &bundleFiles.Signature},
+    } {
+        contents, err := download(httpClient, file.url)
+        if err != nil {
+            return BundleFiles{}, err
+        }
+        *file.contents = contents
+    }
+    return bundleFiles, nil
+
+}
+
+
+// Download a file
+func download(httpClient *http.Client, url string) ([]byte, error) {
+    response, err := httpClient.Get(url)
+    if err != nil {
+        return nil, fmt.Errorf("Could not download %s: %w", url, err)
+    }
+    defer response.Body.Close()
+    if response.StatusCode != http.StatusOK {
+        return nil, fmt.Errorf("Could not download %s: %s", url, response.Status)
+    }
+    contents, err := ioutil.ReadAll(response.Body)
+    if err != nil {
+        return nil, fmt.Errorf("Could not download %s: %w", url, err)
+    }
+    return contents, nil
+}
+
+
+// Get the path of the folder installations are kept in on the Rocket Pool host
+func (c *Client) getInstallsPath() (string, error) {
+    configPath, err := homedir.Expand(c.configPath)
+    if err != nil {
+        return "", err
+    }
+    return filepath.Join(configPath, InstallsFolder), nil
+}
+
+
+// Load the info for an installation; returns nil if it doesn't exist
+func (c *Client) loadInstallInfo(installPath string) (*InstallInfo, error) {
+    infoPath := filepath.Join(installPath, InstallInfoFile)
+    output, err := c.readOutput(fmt.Sprintf("[ ! -f %s ] || cat %s", shellescape.Quote(infoPath), shellescape.Quote(infoPath)))
+    if err != nil {
+        return nil, fmt.Errorf("Could not read installation info at %s: %w", shellescape.Quote(infoPath), err)
+    }
+    if len(output) == 0 {
+        return nil, nil
+    }
+    info := new(InstallInfo)
+    if err := yaml.Unmarshal(output, info); err != nil {
+        return nil, fmt.Errorf("Could not parse installation info at %s: %w", shellescape.Quote(infoPath), err)
+    }
+    return info, nil
+}
+
+
+// Keep the current settings with the current installation, so they are restored if it is rolled back to
+func (c *Client) saveInstallSettings(installsPath string) error {
+    configPath, err := homedir.Expand(c.configPath)
+    if err != nil {
+        return err
+    }
+    currentPath := filepath.Join(installsPath, CurrentInstallFolder)
+    if _, err := c.readOutput(fmt.Sprintf("if [ -d %s ]; then (cp %s %s 2>/dev/null || true); fi",
+        shellescape.Quote(currentPath),
+        shellescape.Quote(filepath.Join(configPath, UserConfigFile)), shellescape.Quote(filepath.Join(currentPath, InstallSettingsFile)))); err != nil {
+        return fmt.Errorf("Could not keep the current settings for rollback: %w", err)
+    }
+    return nil
+}
+
+
+// Move the current installation to the previous installation, and the pending installation to the current installation
+// Unverified legacy installs have no pending installation, so rollback returns to the last verified installation
+func (c *Client) archiveCurrentInstall(installsPath string) error {
+    currentPath := shellescape.Quote(filepath.Join(installsPath, CurrentInstallFolder))
+    previousPath := shellescape.Quote(filepath.Join(installsPath, PreviousInstallFolder))
+    pendingPath := shellescape.Quote(filepath.Join(installsPath, PendingInstallFolder))
+    if _, err := c.readOutput(fmt.Sprintf("if [ -d %s ]; then rm -rf %s && mv %s %s; fi && if [ -d %s ]; then mv %s %s; fi",
+        currentPath,
+        previousPath,
+        currentPath, previousPath,
+        pendingPath,
+        pendingPath, currentPath)); err != nil {
+        return fmt.Errorf("Could not keep the current installation for rollback: %w", err)
+    }
+    return nil
+}
+
+
+// Remove a stored installation; failures are ignored as it will be replaced by the next installation
+func (c *Client) removeInstall(installPath string) {
+    _, _ = c.readOutput(fmt.Sprintf("rm -rf %s", shellescape.Quote(installPath)))
+}
+
+
+// Store a verified release bundle and its install info on the Rocket Pool host, replacing any bundle already stored there
+// The install info is omitted if nil
+func (c *Client) storeInstall(installPath string, bundleFiles BundleFiles, info *InstallInfo) error {
+    files := map[string][]byte{
+        bundle.BundleFile: bundleFiles.Bundle,
+        bundle.BundleFile + bundle.ChecksumExtension: bundleFiles.Checksum,
+        bundle.BundleFile + bundle.SignatureExtension: bundleFiles.Signature,
+    }
+    if info != nil {
+        infoBytes, err := yaml.Marshal(info)
+        if err != nil {
+            return fmt.Errorf("Could not serialize installation info: %w", err)
+        }
+        files[InstallInfoFile] = infoBytes
+    }
+    if _, err := c.readOutput(fmt.Sprintf("rm -rf %s && mkdir -p %s", shellescape.Quote(installPath), shellescape.Quote(installPath))); err != nil {
+        return fmt.Errorf("Could not create installation folder %s: %w", shellescape.Quote(installPath), err)
+    }
+    for name, contents := range files {
+        if err := c.uploadFile(filepath.Join(installPath, name), contents); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+
+// Extract a stored release bundle and run its install script
+// The install script is run from the extracted bundle with -l, which installs the bundled package instead of downloading it
+func (c *Client) runBundleInstaller(verbose bool, installPath string, info InstallInfo) error {
+
+    // Get installation script flags
+    extractPath := filepath.Join(installPath, "bundle")
+    flags := []string{
+        "-n", shellescape.Quote(info.Network),
+        "-v", shellescape.Quote(info.Version),
+        "-l", shellescape.Quote(extractPath),
+    }
+    if info.Path != "" {
+        flags = append(flags, fmt.Sprintf("-p %s", shellescape.Quote(info.Path)))
+    }
+    if info.NoDeps {
+        flags = append(flags, "-d")
+    }
+
+    // Run the install script
+    if err := c.runBundleScript(verbose, installPath, bundle.InstallScript, flags); err != nil {
+        return fmt.Errorf("Could not install Rocket Pool service: %w", err)
+    }
+    return nil
+
+}
+
+
+// Extract a stored release bundle into its bundle folder and run one of its scripts
+func (c *Client) runBundleScript(verbose bool, installPath string, script string, flags []string) error {
+
+    // Run the script
+    extractPath := filepath.Join(installPath, "bundle")
+    return c.runScript(verbose, fmt.Sprintf("rm -rf %s && mkdir -p %s && tar -xzf %s -C %s && sh %s %s",
+        shellescape.Quote(extractPath), shellescape.Quote(extractPath),
+        shellescape.Quote(filepath.Join(installPath, bundle.BundleFile)), shellescape.Quote(extractPath),
+        shellescape.Quote(filepath.Join(extractPath, script)), strings.Join(flags, " ")))
+
+}
+
+
+// Download an install script from a release and run it without verification
+func (c *Client) runLegacyScript(verbose bool, scriptURL string, flags []string) error {
+
+    // Get installation script downloader type
+    downloader, err := c.getDownloader()
+    if err != nil { return err }
+
+    // Run the script
+    return c.runScript(verbose, fmt.Sprintf("%s %s | sh -s -- %s", downloader, scriptURL, strings.Join(flags, " ")))
+
+}
+
+
+// Run an install script command, printing its progress and rendering its command output in verbose mode
+func (c *Client) runScript(verbose bool, cmdText string) error {
+
+    // Initialize script command
+    cmd, err := c.newCommand(cmdText)
+    if err != nil { return err }
+    defer func() {
+        _ = cmd.Close()
+    }()
+
+    // Get command output pipes
+    cmdOut, err := cmd.StdoutPipe()
+    if err != nil { return err }
+    cmdErr, err := cmd.StderrPipe()
+    if err != nil { return err }
+
+    // Print progress from stdout
+    go (func() {
+        scanner := bufio.NewScanner(cmdOut)
+        for scanner.Scan() {
+            fmt.Println(scanner.Text())
+        }
+    })()
+
+    // Read command & error output from stderr; render in verbose mode
+    var errMessage string
+    go (func() {
+        c := color.New(DebugColor)
+        scanner := bufio.NewScanner(cmdErr)
+        for scanner.Scan() {
+            errMessage = scanner.Text()
+            if verbose {
+                _, _ = c.Println(scanner.Text())
+            }
+        }
+    })()
+
+    // Run command and return error output
+    if err := cmd.Run(); err != nil {
+        return errors.New(errMessage)
+    }
+    return nil
+
+}
+
+
+// Get the command used to download install scripts
+func (c *Client) getDownloader() (string, error) {
+
+    // Check for cURL
+    hasCurl, err := c.readOutput("command -v curl")
+    if err == nil && len(hasCurl) > 0 {
+        return "curl -sL", nil
+    }
+
+    // Check for wget
+    hasWget, err := c.readOutput("command -v wget")
+    if err == nil && len(hasWget) > 0 {
+        return "wget -qO-", nil
+    }
+
+    // Return error
+    return "", errors.New("Either cURL or wget is required to begin installation.")
+
+}
+
+
+// Write a file on the Rocket Pool host
+func (c *Client) uploadFile(path string, contents []byte) error {
+    cmd, err := c.newCommand(fmt.Sprintf("cat > %s", shellescape.Quote(path)))
+    if err != nil { return err }
+    defer func() {
+        _ = cmd.Close()
+    }()
+    cmd.SetStdin(bytes.NewReader(contents))
+    if err := cmd.Run(); err != nil {
+        return fmt.Errorf("Could not write %s: %w", shellescape.Quote(path), err)
+    }
+    return nil
+}
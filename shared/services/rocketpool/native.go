@@ -0,0 +1,517 @@
+package rocketpool
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alessio/shellescape"
+	"github.com/dustin/go-humanize"
+	externalip "github.com/glendc/go-external-ip"
+	"github.com/mitchellh/go-homedir"
+	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/native"
+)
+
+// Native mode settings
+const (
+    NativeDataFolder = "data"
+    ValidatorRestartScript = "restart-validator.sh"
+
+    NativeEth1Provider = "http://127.0.0.1:8545"
+    NativeEth1WsProvider = "ws://127.0.0.1:8546"
+    NativeEth2Provider = "http://127.0.0.1:5052"
+
+    systemdTimeLayout = "Mon 2006-01-02 15:04:05 MST"
+)
+
+
+// The state of a native service's systemd unit
+type UnitState struct {
+    Service string
+    Name string
+    LoadState string
+    ActiveState string
+    SubState string
+    MainPID int
+    ActiveEnterTime time.Time
+    InactiveEnterTime time.Time
+    MemoryCurrent uint64
+    CPUUsage time.Duration
+    Tasks uint64
+}
+
+
+// Check whether the Rocket Pool service runs natively under systemd instead of docker
+func (c *Client) IsNativeMode() (bool, error) {
+    if c.daemonPath != "" {
+        return false, nil
+    }
+    cfg, err := c.LoadMergedConfig()
+    if err != nil {
+        return false, err
+    }
+    return cfg.Native.Enabled, nil
+}
+
+
+// Get the path of the daemon binary used to call the API directly; returns a blank path if the API container is used
+func (c *Client) getDaemonPath() (string, error) {
+    if c.daemonPath != "" {
+        return c.daemonPath, nil
+    }
+    cfg, err := c.LoadMergedConfig()
+    if err != nil {
+        return "", err
+    }
+    if cfg.Native.Enabled {
+        return cfg.Native.DaemonPath, nil
+    }
+    return "", nil
+}
+
+
+// Configure an installed Rocket Pool service to run in native mode
+// The settings are updated to run the clients & daemon under systemd, with the clients listening on localhost
+func (c *Client) ConfigureNativeService(daemonPath string) error {
+
+    // Get the user to run the services as
+    user, err := c.readOutput("id -un")
+    if err != nil {
+        return fmt.Errorf("Could not get the current user: %w", err)
+    }
+
+    // Load the user settings, which may not exist on a fresh install
+    userConfig := config.RocketPoolConfig{}
+    userConfigPath, err := homedir.Expand(fmt.Sprintf("%s/%s", c.configPath, UserConfigFile))
+    if err != nil {
+        return err
+    }
+    if _, err := os.Stat(userConfigPath); err == nil {
+        if userConfig, err = c.LoadUserConfig(); err != nil {
+            return err
+        }
+    }
+
+    // Update the settings; data is stored under the config folder
+    configPath, err := homedir.Expand(c.configPath)
+    if err != nil {
+        return err
+    }
+    dataPath := filepath.Join(configPath, NativeDataFolder)
+    userConfig.Native.Enabled = true
+    userConfig.Native.DaemonPath = daemonPath
+    userConfig.Native.DataPath = dataPath
+    userConfig.Native.User = strings.TrimSpace(string(user))
+    userConfig.Chains.Eth1.Provider = NativeEth1Provider
+    userConfig.Chains.Eth1.WsProvider = NativeEth1WsProvider
+    userConfig.Chains.Eth2.Provider = NativeEth2Provider
+    userConfig.Smartnode.PasswordPath = filepath.Join(dataPath, "password")
+    userConfig.Smartnode.WalletPath = filepath.Join(dataPath, "wallet")
+    userConfig.Smartnode.ValidatorKeychainPath = filepath.Join(dataPath, "validators")
+    userConfig.Smartnode.ValidatorRestartCommand = filepath.Join(configPath, ValidatorRestartScript)
+    return c.SaveUserConfig(userConfig)
+
+}
+
+
+// Start the native services, regenerating their systemd units from the current config
+func (c *Client) startNativeService() error {
+    _, units, sudo, err := c.writeNativeUnits()
+    if err != nil {
+        return err
+    }
+    if err := c.printOutput(fmt.Sprintf("%ssystemctl enable --now %s", sudo, quoteAll(getUnitNames(units)))); err != nil {
+        return fmt.Errorf("Could not start the Rocket Pool services: %w", err)
+    }
+    return c.SaveAppliedConfig()
+}
+
+
+// Restart a subset of the native services to apply config changes
+// Services which have no native unit (e.g. the api and metrics containers) are ignored
+func (c *Client) restartNativeServices(serviceNames ...string) error {
+
+    // Regenerate units
+    _, units, sudo, err := c.writeNativeUnits()
+    if err != nil {
+        return err
+    }
+
+    // Enable any new units & restart the affected units
+    if _, err := c.readOutput(fmt.Sprintf("%ssystemctl enable %s", sudo, quoteAll(getUnitNames(units)))); err != nil {
+        return fmt.Errorf("Could not enable the Rocket Pool services: %w", err)
+    }
+    unitNames := []string{}
+    for _, unit := range units {
+        if containsString(serviceNames, unit.Service) {
+            unitNames = append(unitNames, unit.Name)
+        }
+    }
+    if len(unitNames) > 0 {
+        if err := c.printOutput(fmt.Sprintf("%ssystemctl restart %s", sudo, quoteAll(unitNames))); err != nil {
+            return fmt.Errorf("Could not restart the Rocket Pool services: %w", err)
+        }
+    }
+    return c.SaveAppliedConfig()
+
+}
+
+
+// Stop the native services, leaving their units installed
+func (c *Client) pauseNativeService() error {
+    _, units, err := c.getNativeUnits()
+    if err != nil {
+        return err
+    }
+    sudo, err := c.getSudo()
+    if err != nil {
+        return err
+    }
+    return c.printOutput(fmt.Sprintf("%ssystemctl stop %s", sudo, quoteAll(getReverseUnitNames(units))))
+}
+
+
+// Stop the native services and remove their units
+// Chain data is kept in the native data folder
+func (c *Client) stopNativeService() error {
+
+    // Get units
+    cfg, units, err := c.getNativeUnits()
+    if err != nil {
+        return err
+    }
+    sudo, err := c.getSudo()
+    if err != nil {
+        return err
+    }
+
+    // Stop & disable units
+    unitNames := getReverseUnitNames(units)
+    if err := c.printOutput(fmt.Sprintf("%ssystemctl disable --now %s", sudo, quoteAll(unitNames))); err != nil {
+        return fmt.Errorf("Could not stop the Rocket Pool services: %w", err)
+    }
+
+    // Remove units
+    unitPaths := make([]string, len(unitNames))
+    for ui, unitName := range unitNames {
+        unitPaths[ui] = filepath.Join(native.GetUnitPath(cfg), unitName)
+    }
+    if err := c.printOutput(fmt.Sprintf("%srm -f %s && %ssystemctl daemon-reload", sudo, quoteAll(unitPaths), sudo)); err != nil {
+        return fmt.Errorf("Could not remove the Rocket Pool service units: %w", err)
+    }
+    fmt.Printf("The Rocket Pool service units were removed. Chain data has been kept in %s.\n", cfg.Native.DataPath)
+    return nil
+
+}
+
+
+// Get the state of the native services' units
+func (c *Client) GetNativeServiceStatus() ([]*UnitState, error) {
+    _, units, err := c.getNativeUnits()
+    if err != nil {
+        return nil, err
+    }
+    states := make([]*UnitState, len(units))
+    for ui, unit := range units {
+        state, err := c.GetNativeUnitState(unit.Name)
+        if err != nil {
+            return nil, err
+        }
+        state.Service = unit.Service
+        states[ui] = state
+    }
+    return states, nil
+}
+
+
+// Stop a native service's systemd unit by name
+func (c *Client) StopNativeUnit(unitName string) error {
+    sudo, err := c.getSudo()
+    if err != nil {
+        return err
+    }
+    if _, err := c.readOutput(fmt.Sprintf("%ssystemctl stop %s", sudo, shellescape.Quote(unitName))); err != nil {
+        return fmt.Errorf("Could not stop unit %s: %w", unitName, err)
+    }
+    return nil
+}
+
+
+// Print the native service status
+func (c *Client) printNativeServiceStatus() error {
+
+    // Get unit states
+    states, err := c.GetNativeServiceStatus()
+    if err != nil { return err }
+
+    // Print states
+    writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+    fmt.Fprintln(writer, "UNIT\tSERVICE\tSTATUS\tPID\tSINCE")
+    for _, state := range states {
+        status := state.Summary()
+        var pid, since string
+        if state.MainPID != 0 {
+            pid = strconv.Itoa(state.MainPID)
+        }
+        if state.ActiveState == "active" && !state.ActiveEnterTime.IsZero() {
+            since = humanize.Time(state.ActiveEnterTime)
+        } else if !state.InactiveEnterTime.IsZero() {
+            since = humanize.Time(state.InactiveEnterTime)
+        }
+        fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", state.Name, state.Service, status, pid, since)
+    }
+    return writer.Flush()
+
+}
+
+
+// Print the native service logs from the journal
+func (c *Client) printNativeServiceLogs(tail string, serviceNames ...string) error {
+
+    // Get units
+    cfg, units, err := c.getNativeUnits()
+    if err != nil {
+        return err
+    }
+    unitFlags := []string{}
+    for _, unit := range units {
+        if len(serviceNames) > 0 && !containsString(serviceNames, unit.Service) { continue }
+        unitFlags = append(unitFlags, "-u " + shellescape.Quote(native.GetUnitName(cfg, unit.Service)))
+    }
+    if len(unitFlags) == 0 {
+        return fmt.Errorf("No native services found matching %s", strings.Join(serviceNames, ", "))
+    }
+
+    // Follow journal
+    lines := "all"
+    if tail != "" && tail != "all" {
+        lines = shellescape.Quote(tail)
+    }
+    return c.printOutput(fmt.Sprintf("journalctl -f -n %s %s", lines, strings.Join(unitFlags, " ")))
+
+}
+
+
+// Print the native service resource usage
+func (c *Client) printNativeServiceStats() error {
+
+    // Get unit states
+    states, err := c.GetNativeServiceStatus()
+    if err != nil { return err }
+
+    // Print stats
+    writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+    fmt.Fprintln(writer, "UNIT\tCPU TIME\tMEM USAGE\tTASKS")
+    for _, state := range states {
+        if state.ActiveState != "active" { continue }
+        fmt.Fprintf(writer, "%s\t%s\t%s\t%d\n", state.Name, state.CPUUsage.Round(time.Second), humanize.IBytes(state.MemoryCurrent), state.Tasks)
+    }
+    return writer.Flush()
+
+}
+
+
+// Get a summary of the unit state, e.g. "active (running)"
+func (s *UnitState) Summary() string {
+    if s.LoadState == "not-found" {
+        return "not installed"
+    }
+    return fmt.Sprintf("%s (%s)", s.ActiveState, s.SubState)
+}
+
+
+// Get the merged config and the native service units generated from it
+func (c *Client) getNativeUnits() (config.RocketPoolConfig, []*native.Unit, error) {
+
+    // Load config
+    cfg, err := c.LoadMergedConfig()
+    if err != nil {
+        return config.RocketPoolConfig{}, nil, err
+    }
+    if !cfg.Native.Enabled {
+        return config.RocketPoolConfig{}, nil, errors.New("The Rocket Pool service is not installed in native mode.")
+    }
+    if cfg.GetSelectedEth1Client() == nil || cfg.GetSelectedEth2Client() == nil {
+        return config.RocketPoolConfig{}, nil, errors.New("No Eth 1.0 or Eth 2.0 client selected. Please run 'rocketpool service config' and try again.")
+    }
+
+    // Get the external IP address
+    var externalIP string
+    consensus := externalip.DefaultConsensus(nil, nil)
+    ip, err := consensus.ExternalIP()
+    if err == nil {
+        externalIP = ip.String()
+    }
+
+    // Get units
+    configPath, err := homedir.Expand(c.configPath)
+    if err != nil {
+        return config.RocketPoolConfig{}, nil, err
+    }
+    units, err := native.GetUnits(cfg, configPath, getComposeEnv(cfg, externalIP))
+    if err != nil {
+        return config.RocketPoolConfig{}, nil, err
+    }
+    return cfg, units, nil
+
+}
+
+
+// Write the native service units and the validator restart script, and reload systemd
+// Returns the merged config, the units, and the command prefix required to manage them
+func (c *Client) writeNativeUnits() (config.RocketPoolConfig, []*native.Unit, string, error) {
+
+    // Get units
+    cfg, units, err := c.getNativeUnits()
+    if err != nil {
+        return config.RocketPoolConfig{}, nil, "", err
+    }
+    sudo, err := c.getSudo()
+    if err != nil {
+        return config.RocketPoolConfig{}, nil, "", err
+    }
+
+    // Write units
+    for _, unit := range units {
+        if err := c.writeHostFile(filepath.Join(native.GetUnitPath(cfg), unit.Name), unit.Render(cfg.Native.User), "0644", sudo); err != nil {
+            return config.RocketPoolConfig{}, nil, "", err
+        }
+    }
+
+    // Write the validator restart script used by the node daemon
+    configPath, err := homedir.Expand(c.configPath)
+    if err != nil {
+        return config.RocketPoolConfig{}, nil, "", err
+    }
+    validatorUnit := native.GetUnitName(cfg, native.GetValidatorService(cfg))
+    restartScript := fmt.Sprintf("#!/bin/sh\nexec %ssystemctl restart %s\n", sudo, shellescape.Quote(validatorUnit))
+    if err := c.writeHostFile(filepath.Join(configPath, ValidatorRestartScript), restartScript, "0755", ""); err != nil {
+        return config.RocketPoolConfig{}, nil, "", err
+    }
+
+    // Reload systemd
+    if _, err := c.readOutput(fmt.Sprintf("%ssystemctl daemon-reload", sudo)); err != nil {
+        return config.RocketPoolConfig{}, nil, "", fmt.Errorf("Could not reload systemd units: %w", err)
+    }
+
+    // Return
+    return cfg, units, sudo, nil
+
+}
+
+
+// Get the state of a native service's systemd unit by name
+func (c *Client) GetNativeUnitState(unitName string) (*UnitState, error) {
+
+    // Get unit properties
+    output, err := c.readOutput(fmt.Sprintf("systemctl show --no-pager -p Id,LoadState,ActiveState,SubState,MainPID,ActiveEnterTimestamp,InactiveEnterTimestamp,MemoryCurrent,CPUUsageNSec,TasksCurrent %s", shellescape.Quote(unitName)))
+    if err != nil {
+        return nil, fmt.Errorf("Could not get the state of unit %s: %w", unitName, err)
+    }
+
+    // Parse properties; unset numeric properties are reported as [not set] or the max uint64
+    state := &UnitState{Name: unitName}
+    for _, line := range strings.Split(string(output), "\n") {
+        property := strings.SplitN(strings.TrimSpace(line), "=", 2)
+        if len(property) != 2 { continue }
+        key, value := property[0], property[1]
+        switch key {
+            case "LoadState": state.LoadState = value
+            case "ActiveState": state.ActiveState = value
+            case "SubState": state.SubState = value
+            case "MainPID": state.MainPID, _ = strconv.Atoi(value)
+            case "ActiveEnterTimestamp": state.ActiveEnterTime, _ = time.Parse(systemdTimeLayout, value)
+            case "InactiveEnterTimestamp": state.InactiveEnterTime, _ = time.Parse(systemdTimeLayout, value)
+            case "MemoryCurrent": state.MemoryCurrent = parseUnitCounter(value)
+            case "CPUUsageNSec": state.CPUUsage = time.Duration(parseUnitCounter(value))
+            case "TasksCurrent": state.Tasks = parseUnitCounter(value)
+        }
+    }
+
+    // Return
+    return state, nil
+
+}
+
+
+// Get the command prefix required to manage systemd units on the host
+func (c *Client) getSudo() (string, error) {
+    uid, err := c.readOutput("id -u")
+    if err != nil {
+        return "", fmt.Errorf("Could not get the current user ID: %w", err)
+    }
+    if strings.TrimSpace(string(uid)) == "0" {
+        return "", nil
+    }
+    return "sudo ", nil
+}
+
+
+// Write a file on the Rocket Pool host with the given permissions
+func (c *Client) writeHostFile(path, contents, mode, sudo string) error {
+    cmd := fmt.Sprintf("printf '%%s' %s | %stee %s > /dev/null && %schmod %s %s",
+        shellescape.Quote(contents),
+        sudo, shellescape.Quote(path),
+        sudo, mode, shellescape.Quote(path))
+    if _, err := c.readOutput(cmd); err != nil {
+        return fmt.Errorf("Could not write %s: %w", shellescape.Quote(path), err)
+    }
+    return nil
+}
+
+
+// Get unit names in start order
+func getUnitNames(units []*native.Unit) []string {
+    unitNames := make([]string, len(units))
+    for ui, unit := range units {
+        unitNames[ui] = unit.Name
+    }
+    return unitNames
+}
+
+
+// Get unit names in reverse start order
+func getReverseUnitNames(units []*native.Unit) []string {
+    unitNames := make([]string, len(units))
+    for ui, unit := range units {
+        unitNames[len(units) - ui - 1] = unit.Name
+    }
+    return unitNames
+}
+
+
+// Parse a systemd counter property; unset counters are zero
+func parseUnitCounter(value string) uint64 {
+    counter, err := strconv.ParseUint(value, 10, 64)
+    if err != nil || counter == ^uint64(0) {
+        return 0
+    }
+    return counter
+}
+
+
+// Quote a list of shell arguments
+func quoteAll(args []string) string {
+    quoted := make([]string, len(args))
+    for ai, arg := range args {
+        quoted[ai] = shellescape.Quote(arg)
+    }
+    return strings.Join(quoted, " ")
+}
+
+
+// Check whether a string slice contains a value
+func containsString(values []string, value string) bool {
+    for _, v := range values {
+        if v == value {
+            return true
+        }
+    }
+    return false
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"math/big"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 
@@ -530,6 +532,40 @@ func (c *Client) NodeSync() (api.NodeSyncProgressResponse, error) {
 }
 
 
+// Get the history of transactions sent by the node
+func (c *Client) NodeTxHistory() (api.NodeTxHistoryResponse, error) {
+    responseBytes, err := c.callAPI("node tx-history")
+    if err != nil {
+        return api.NodeTxHistoryResponse{}, fmt.Errorf("Could not get node transaction history: %w", err)
+    }
+    var response api.NodeTxHistoryResponse
+    if err := json.Unmarshal(responseBytes, &response); err != nil {
+        return api.NodeTxHistoryResponse{}, fmt.Errorf("Could not decode node transaction history response: %w", err)
+    }
+    if response.Error != "" {
+        return api.NodeTxHistoryResponse{}, fmt.Errorf("Could not get node transaction history: %s", response.Error)
+    }
+    return response, nil
+}
+
+
+// Get a report of the node's income and costs over a period
+func (c *Client) NodeReport(from, to time.Time) (api.NodeReportResponse, error) {
+    responseBytes, err := c.callAPI(fmt.Sprintf("node report %d %d", from.Unix(), to.Unix()))
+    if err != nil {
+        return api.NodeReportResponse{}, fmt.Errorf("Could not get node report: %w", err)
+    }
+    var response api.NodeReportResponse
+    if err := json.Unmarshal(responseBytes, &response); err != nil {
+        return api.NodeReportResponse{}, fmt.Errorf("Could not decode node report response: %w", err)
+    }
+    if response.Error != "" {
+        return api.NodeReportResponse{}, fmt.Errorf("Could not get node report: %s", response.Error)
+    }
+    return response, nil
+}
+
+
 // Check whether the node has RPL rewards available to claim
 func (c *Client) CanNodeClaimRpl() (api.CanNodeClaimRplResponse, error) {
     responseBytes, err := c.callAPI("node can-claim-rpl-rewards")
@@ -597,3 +633,39 @@ func (c *Client) DepositContractInfo() (api.DepositContractInfoResponse, error)
     return response, nil
 }
 
+
+// Get the node's validator keystore and beacon clock details for the service health check
+func (c *Client) NodeDoctor() (api.NodeDoctorResponse, error) {
+    responseBytes, err := c.callAPI("node doctor")
+    if err != nil {
+        return api.NodeDoctorResponse{}, fmt.Errorf("Could not get node health check details: %w", err)
+    }
+    var response api.NodeDoctorResponse
+    if err := json.Unmarshal(responseBytes, &response); err != nil {
+        return api.NodeDoctorResponse{}, fmt.Errorf("Could not decode node health check response: %w", err)
+    }
+    if response.Error != "" {
+        return api.NodeDoctorResponse{}, fmt.Errorf("Could not get node health check details: %s", response.Error)
+    }
+    return response, nil
+}
+
+
+// Check whether any of a set of validators have been active on the beacon chain in recent epochs
+func (c *Client) NodeValidatorLiveness(pubkeys []string) (api.NodeValidatorLivenessResponse, error) {
+    if len(pubkeys) == 0 {
+        return api.NodeValidatorLivenessResponse{}, nil
+    }
+    responseBytes, err := c.callAPI(fmt.Sprintf("node validator-liveness %s", strings.Join(pubkeys, ",")))
+    if err != nil {
+        return api.NodeValidatorLivenessResponse{}, fmt.Errorf("Could not check validator liveness: %w", err)
+    }
+    var response api.NodeValidatorLivenessResponse
+    if err := json.Unmarshal(responseBytes, &response); err != nil {
+        return api.NodeValidatorLivenessResponse{}, fmt.Errorf("Could not decode validator liveness response: %w", err)
+    }
+    if response.Error != "" {
+        return api.NodeValidatorLivenessResponse{}, fmt.Errorf("Could not check validator liveness: %s", response.Error)
+    }
+    return response, nil
+}
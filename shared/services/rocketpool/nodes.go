@@ -0,0 +1,175 @@
+package rocketpool
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/urfave/cli"
+	"gopkg.in/yaml.v2"
+)
+
+// Config
+const (
+    DefaultNodesFile = "~/.rocketpool-cli/nodes.yml"
+    DefaultNodeConfigPath = "~/.rocketpool"
+)
+var nodeNameRegex = regexp.MustCompile("^[A-Za-z0-9_.-]+$")
+
+
+// A named Rocket Pool node which the CLI connects to over SSH
+// SSH key passphrases are not stored with profiles; a passphrase-protected key's passphrase file is given with --passphrase
+type NodeProfile struct {
+    Name string                         `yaml:"name"`
+    Host string                         `yaml:"host"`
+    User string                         `yaml:"user"`
+    Key string                          `yaml:"key"`
+    KnownHosts string                   `yaml:"knownHosts,omitempty"`
+    ConfigPath string                   `yaml:"configPath,omitempty"`
+    DaemonPath string                   `yaml:"daemonPath,omitempty"`
+}
+
+
+// The node profiles stored in the CLI's nodes file
+type NodeProfiles struct {
+    Nodes []NodeProfile                 `yaml:"nodes"`
+}
+
+
+// Load the node profiles from a nodes file; a missing file has no profiles
+func LoadNodeProfiles(path string) (*NodeProfiles, error) {
+    expandedPath, err := homedir.Expand(path)
+    if err != nil {
+        return nil, err
+    }
+    profiles := &NodeProfiles{}
+    profileBytes, err := ioutil.ReadFile(expandedPath)
+    if os.IsNotExist(err) {
+        return profiles, nil
+    }
+    if err != nil {
+        return nil, fmt.Errorf("Could not read nodes file at %s: %w", expandedPath, err)
+    }
+    if err := yaml.Unmarshal(profileBytes, profiles); err != nil {
+        return nil, fmt.Errorf("Could not parse nodes file at %s: %w", expandedPath, err)
+    }
+    return profiles, nil
+}
+
+
+// Save the node profiles to a nodes file
+func (p *NodeProfiles) Save(path string) error {
+    expandedPath, err := homedir.Expand(path)
+    if err != nil {
+        return err
+    }
+    sort.Slice(p.Nodes, func(i, j int) bool { return p.Nodes[i].Name < p.Nodes[j].Name })
+    profileBytes, err := yaml.Marshal(p)
+    if err != nil {
+        return fmt.Errorf("Could not serialize node profiles: %w", err)
+    }
+    if err := os.MkdirAll(filepath.Dir(expandedPath), 0700); err != nil {
+        return fmt.Errorf("Could not create nodes file folder %s: %w", filepath.Dir(expandedPath), err)
+    }
+    if err := ioutil.WriteFile(expandedPath, profileBytes, 0600); err != nil {
+        return fmt.Errorf("Could not write nodes file at %s: %w", expandedPath, err)
+    }
+    return nil
+}
+
+
+// Get a node profile by name
+func (p *NodeProfiles) Get(name string) *NodeProfile {
+    for pi := range p.Nodes {
+        if p.Nodes[pi].Name == name {
+            return &p.Nodes[pi]
+        }
+    }
+    return nil
+}
+
+
+// Add a node profile, replacing any existing profile with the same name
+func (p *NodeProfiles) Set(profile NodeProfile) error {
+    if !nodeNameRegex.MatchString(profile.Name) {
+        return fmt.Errorf("Invalid node name '%s' - names may only contain letters, numbers, '.', '_' and '-'", profile.Name)
+    }
+    if profile.Host == "" || profile.User == "" || profile.Key == "" {
+        return errors.New("Node profiles require an SSH host, user and key.")
+    }
+    if existing := p.Get(profile.Name); existing != nil {
+        *existing = profile
+        return nil
+    }
+    p.Nodes = append(p.Nodes, profile)
+    return nil
+}
+
+
+// Remove a node profile by name; returns false if it doesn't exist
+func (p *NodeProfiles) Remove(name string) bool {
+    for pi, profile := range p.Nodes {
+        if profile.Name == name {
+            p.Nodes = append(p.Nodes[:pi], p.Nodes[pi + 1:]...)
+            return true
+        }
+    }
+    return false
+}
+
+
+// Load the node profiles from the nodes file set in a CLI context
+func LoadNodeProfilesFromCtx(c *cli.Context) (*NodeProfiles, error) {
+    return LoadNodeProfiles(c.GlobalString("nodes-file"))
+}
+
+
+// Create new Rocket Pool client for a node profile, using the SSH key passphrase and gas settings from a CLI context
+func NewClientForNode(c *cli.Context, profile NodeProfile) (*Client, error) {
+    configPath := profile.ConfigPath
+    if configPath == "" {
+        configPath = DefaultNodeConfigPath
+    }
+    rp, err := NewClient(configPath,
+                         profile.DaemonPath,
+                         profile.Host,
+                         profile.User,
+                         profile.Key,
+                         c.GlobalString("passphrase"),
+                         profile.KnownHosts,
+                         c.GlobalFloat64("maxFee"),
+                         c.GlobalFloat64("maxPrioFee"),
+                         c.GlobalUint64("gasLimit"),
+                         c.GlobalString("nonce"),
+                         c.GlobalBool("debug"))
+    if err != nil {
+        return nil, fmt.Errorf("Could not connect to node '%s': %w", profile.Name, err)
+    }
+    return rp, nil
+}
+
+
+// Get the node profile selected with --node in a CLI context, or nil if none is selected
+func getSelectedNode(c *cli.Context) (*NodeProfile, error) {
+    name := c.GlobalString("node")
+    if name == "" {
+        return nil, nil
+    }
+    if c.GlobalString("host") != "" {
+        return nil, errors.New("The --node and --host options cannot be used together.")
+    }
+    profiles, err := LoadNodeProfilesFromCtx(c)
+    if err != nil {
+        return nil, err
+    }
+    profile := profiles.Get(name)
+    if profile == nil {
+        return nil, fmt.Errorf("Unknown node '%s'; add it with 'rocketpool nodes add' or check %s", name, c.GlobalString("nodes-file"))
+    }
+    return profile, nil
+}
@@ -0,0 +1,156 @@
+package rocketpool
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+
+// Get a valid test node profile
+func getTestNodeProfile(name string) NodeProfile {
+    return NodeProfile{Name: name, Host: "192.168.1.10:22", User: "rp", Key: "~/.ssh/id_ed25519"}
+}
+
+
+func TestNodeProfilesSet(t *testing.T) {
+    tests := []struct {
+        name string
+        modify func(profile *NodeProfile)
+        errorContains string
+    }{
+        {"valid", func(profile *NodeProfile) {}, ""},
+        {"name with symbols", func(profile *NodeProfile) { profile.Name = "node-1_a.b" }, ""},
+        {"empty name", func(profile *NodeProfile) { profile.Name = "" }, "Invalid node name ''"},
+        {"name with a space", func(profile *NodeProfile) { profile.Name = "node 1" }, "Invalid node name 'node 1'"},
+        {"name with a slash", func(profile *NodeProfile) { profile.Name = "../node" }, "Invalid node name '../node'"},
+        {"no host", func(profile *NodeProfile) { profile.Host = "" }, "require an SSH host, user and key"},
+        {"no user", func(profile *NodeProfile) { profile.User = "" }, "require an SSH host, user and key"},
+        {"no key", func(profile *NodeProfile) { profile.Key = "" }, "require an SSH host, user and key"},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            profiles := &NodeProfiles{}
+            profile := getTestNodeProfile("node1")
+            test.modify(&profile)
+            err := profiles.Set(profile)
+            if test.errorContains == "" {
+                if err != nil {
+                    t.Fatal(err)
+                }
+                if len(profiles.Nodes) != 1 || !reflect.DeepEqual(profiles.Nodes[0], profile) {
+                    t.Errorf("profiles are %+v", profiles.Nodes)
+                }
+                return
+            }
+            if err == nil || !strings.Contains(err.Error(), test.errorContains) {
+                t.Fatalf("expected an error containing '%s', got %v", test.errorContains, err)
+            }
+            if len(profiles.Nodes) != 0 {
+                t.Errorf("invalid profile was added: %+v", profiles.Nodes)
+            }
+        })
+    }
+}
+
+
+func TestNodeProfilesReplace(t *testing.T) {
+    profiles := &NodeProfiles{}
+    for _, name := range []string{"node1", "node2"} {
+        if err := profiles.Set(getTestNodeProfile(name)); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    // Profiles with an existing name replace it
+    replacement := getTestNodeProfile("node1")
+    replacement.Host = "10.0.0.1:2222"
+    if err := profiles.Set(replacement); err != nil {
+        t.Fatal(err)
+    }
+    if len(profiles.Nodes) != 2 || profiles.Get("node1").Host != "10.0.0.1:2222" || profiles.Get("node2").Host != "192.168.1.10:22" {
+        t.Errorf("profiles are %+v", profiles.Nodes)
+    }
+
+    // Invalid replacements leave the existing profile
+    replacement.User = ""
+    if err := profiles.Set(replacement); err == nil {
+        t.Error("invalid replacement was accepted")
+    }
+    if profiles.Get("node1").User != "rp" {
+        t.Errorf("existing profile was changed to %+v", profiles.Get("node1"))
+    }
+}
+
+
+func TestNodeProfilesRemove(t *testing.T) {
+    profiles := &NodeProfiles{}
+    for _, name := range []string{"node1", "node2", "node3"} {
+        if err := profiles.Set(getTestNodeProfile(name)); err != nil {
+            t.Fatal(err)
+        }
+    }
+    if !profiles.Remove("node2") {
+        t.Fatal("existing profile was not removed")
+    }
+    if profiles.Get("node2") != nil || profiles.Get("node1") == nil || profiles.Get("node3") == nil {
+        t.Errorf("profiles are %+v", profiles.Nodes)
+    }
+    if profiles.Remove("node2") || profiles.Remove("node4") || profiles.Remove("") {
+        t.Error("missing profile was removed")
+    }
+    if len(profiles.Nodes) != 2 {
+        t.Errorf("got %d profiles, expected 2", len(profiles.Nodes))
+    }
+}
+
+
+func TestNodeProfilesSaveLoad(t *testing.T) {
+    dir, err := ioutil.TempDir("", "nodes")
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer os.RemoveAll(dir)
+    path := filepath.Join(dir, "cli", "nodes.yml")
+
+    // Missing files have no profiles
+    profiles, err := LoadNodeProfiles(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(profiles.Nodes) != 0 {
+        t.Errorf("missing nodes file has profiles %+v", profiles.Nodes)
+    }
+
+    // Profiles are saved sorted by name
+    for _, name := range []string{"node2", "node1"} {
+        if err := profiles.Set(getTestNodeProfile(name)); err != nil {
+            t.Fatal(err)
+        }
+    }
+    if err := profiles.Save(path); err != nil {
+        t.Fatal(err)
+    }
+    if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+        t.Errorf("nodes file is %v, %v; expected mode 0600", info, err)
+    }
+    loaded, err := LoadNodeProfiles(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if expected := []NodeProfile{getTestNodeProfile("node1"), getTestNodeProfile("node2")}; !reflect.DeepEqual(loaded.Nodes, expected) {
+        t.Errorf("loaded profiles are %+v, expected %+v", loaded.Nodes, expected)
+    }
+
+    // Invalid files are rejected
+    if err := ioutil.WriteFile(path, []byte("nodes: {"), 0600); err != nil {
+        t.Fatal(err)
+    }
+    if _, err := LoadNodeProfiles(path); err == nil || !strings.Contains(err.Error(), "Could not parse nodes file") {
+        t.Errorf("expected a parse error, got %v", err)
+    }
+
+}
@@ -67,7 +67,7 @@ func (c *Client) TNDAOProposals() (api.TNDAOProposalsResponse, error) {
 
 // Get a single oracle DAO proposal
 func (c *Client) TNDAOProposal(id uint64) (api.TNDAOProposalResponse, error) {
-    responseBytes, err := c.callAPI("odao proposal-details", string(id))
+    responseBytes, err := c.callAPI(fmt.Sprintf("odao proposal-details %d", id))
     if err != nil {
         return api.TNDAOProposalResponse{}, fmt.Errorf("Could not get oracle DAO proposal: %w", err)
     }
@@ -82,6 +82,23 @@ func (c *Client) TNDAOProposal(id uint64) (api.TNDAOProposalResponse, error) {
 }
 
 
+// Simulate the execution of a proposal
+func (c *Client) SimulateTNDAOProposal(id uint64) (api.SimulateTNDAOProposalResponse, error) {
+    responseBytes, err := c.callAPI(fmt.Sprintf("odao simulate-proposal %d", id))
+    if err != nil {
+        return api.SimulateTNDAOProposalResponse{}, fmt.Errorf("Could not simulate oracle DAO proposal: %w", err)
+    }
+    var response api.SimulateTNDAOProposalResponse
+    if err := json.Unmarshal(responseBytes, &response); err != nil {
+        return api.SimulateTNDAOProposalResponse{}, fmt.Errorf("Could not decode simulate oracle DAO proposal response: %w", err)
+    }
+    if response.Error != "" {
+        return api.SimulateTNDAOProposalResponse{}, fmt.Errorf("Could not simulate oracle DAO proposal: %s", response.Error)
+    }
+    return response, nil
+}
+
+
 // Check whether the node can propose inviting a new member
 func (c *Client) CanProposeInviteToTNDAO(memberAddress common.Address, memberId, memberUrl string) (api.CanProposeTNDAOInviteResponse, error) {
     responseBytes, err := c.callAPI("odao can-propose-invite", memberAddress.Hex(), memberId, memberUrl)
@@ -440,326 +457,137 @@ func (c *Client) ReplaceTNDAOMember() (api.ReplaceTNDAOPositionResponse, error)
 
 
 // Check whether the node can propose a setting update
-func (c *Client) CanProposeTNDAOSetting() (api.CanProposeTNDAOSettingResponse, error) {
-    responseBytes, err := c.callAPI("odao can-propose-setting")
-    if err != nil {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not get can propose setting status: %w", err)
-    }
-    var response api.CanProposeTNDAOSettingResponse
-    if err := json.Unmarshal(responseBytes, &response); err != nil {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not decode can propose setting response: %w", err)
-    }
-    if response.Error != "" {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not get can propose setting status: %s", response.Error)
-    }
-    return response, nil
-}
-func (c *Client) CanProposeTNDAOSettingMembersQuorum(quorum float64) (api.CanProposeTNDAOSettingResponse, error) {
-    responseBytes, err := c.callAPI(fmt.Sprintf("odao can-propose-members-quorum %f", quorum))
-    if err != nil {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not get can propose setting members.quorum: %w", err)
-    }
-    var response api.CanProposeTNDAOSettingResponse
-    if err := json.Unmarshal(responseBytes, &response); err != nil {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not decode can propose setting members.quorum response: %w", err)
-    }
-    if response.Error != "" {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not get can propose setting members.quorum: %s", response.Error)
-    }
-    return response, nil
-}
-func (c *Client) CanProposeTNDAOSettingMembersRplBond(bondAmountWei *big.Int) (api.CanProposeTNDAOSettingResponse, error) {
-    responseBytes, err := c.callAPI(fmt.Sprintf("odao can-propose-members-rplbond %s", bondAmountWei.String()))
-    if err != nil {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not get can propose setting members.rplbond: %w", err)
-    }
-    var response api.CanProposeTNDAOSettingResponse
-    if err := json.Unmarshal(responseBytes, &response); err != nil {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not decode can propose setting members.rplbond response: %w", err)
-    }
-    if response.Error != "" {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not get can propose setting members.rplbond: %s", response.Error)
-    }
-    return response, nil
-}
-func (c *Client) CanProposeTNDAOSettingMinipoolUnbondedMax(unbondedMinipoolMax uint64) (api.CanProposeTNDAOSettingResponse, error) {
-    responseBytes, err := c.callAPI(fmt.Sprintf("odao can-propose-members-minipool-unbonded-max %d", unbondedMinipoolMax))
-    if err != nil {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not get can propose setting members.minipool.unbonded.max: %w", err)
-    }
-    var response api.CanProposeTNDAOSettingResponse
-    if err := json.Unmarshal(responseBytes, &response); err != nil {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not decode can propose setting members.minipool.unbonded.max response: %w", err)
-    }
-    if response.Error != "" {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not get can propose setting members.minipool.unbonded.max: %s", response.Error)
-    }
-    return response, nil
-}
-func (c *Client) CanProposeTNDAOSettingProposalCooldown(proposalCooldownTimespan uint64) (api.CanProposeTNDAOSettingResponse, error) {
-    responseBytes, err := c.callAPI(fmt.Sprintf("odao can-propose-proposal-cooldown %d", proposalCooldownTimespan))
-    if err != nil {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not get can propose setting proposal.cooldown.time: %w", err)
-    }
-    var response api.CanProposeTNDAOSettingResponse
-    if err := json.Unmarshal(responseBytes, &response); err != nil {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not decode can propose setting proposal.cooldown.time response: %w", err)
-    }
-    if response.Error != "" {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not get can propose setting proposal.cooldown.time: %s", response.Error)
-    }
-    return response, nil
-}
-func (c *Client) CanProposeTNDAOSettingProposalVoteTimespan(proposalVoteTimespan uint64) (api.CanProposeTNDAOSettingResponse, error) {
-    responseBytes, err := c.callAPI(fmt.Sprintf("odao can-propose-proposal-vote-timespan %d", proposalVoteTimespan))
-    if err != nil {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not get can propose setting proposal.vote.time: %w", err)
-    }
-    var response api.CanProposeTNDAOSettingResponse
-    if err := json.Unmarshal(responseBytes, &response); err != nil {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not decode can propose setting proposal.vote.time response: %w", err)
-    }
-    if response.Error != "" {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not get can propose setting proposal.vote.time: %s", response.Error)
-    }
-    return response, nil
-}
-func (c *Client) CanProposeTNDAOSettingProposalVoteDelayTimespan(proposalDelayTimespan uint64) (api.CanProposeTNDAOSettingResponse, error) {
-    responseBytes, err := c.callAPI(fmt.Sprintf("odao can-propose-proposal-vote-delay-timespan %d", proposalDelayTimespan))
-    if err != nil {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not get can propose setting proposal.vote.delay.time: %w", err)
-    }
-    var response api.CanProposeTNDAOSettingResponse
-    if err := json.Unmarshal(responseBytes, &response); err != nil {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not decode can propose setting proposal.vote.delay.time response: %w", err)
-    }
-    if response.Error != "" {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not get can propose setting proposal.vote.delay.time: %s", response.Error)
-    }
-    return response, nil
-}
-func (c *Client) CanProposeTNDAOSettingProposalExecuteTimespan(proposalExecuteTimespan uint64) (api.CanProposeTNDAOSettingResponse, error) {
-    responseBytes, err := c.callAPI(fmt.Sprintf("odao can-propose-proposal-execute-timespan %d", proposalExecuteTimespan))
-    if err != nil {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not get can propose setting proposal.execute.time: %w", err)
-    }
-    var response api.CanProposeTNDAOSettingResponse
-    if err := json.Unmarshal(responseBytes, &response); err != nil {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not decode can propose setting proposal.execute.time response: %w", err)
-    }
-    if response.Error != "" {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not get can propose setting proposal.execute.time: %s", response.Error)
-    }
-    return response, nil
-}
-func (c *Client) CanProposeTNDAOSettingProposalActionTimespan(proposalActionTimespan uint64) (api.CanProposeTNDAOSettingResponse, error) {
-    responseBytes, err := c.callAPI(fmt.Sprintf("odao can-propose-proposal-action-timespan %d", proposalActionTimespan))
+func (c *Client) CanProposeTNDAOSetting(name string, value *big.Int) (api.CanProposeTNDAOSettingResponse, error) {
+    responseBytes, err := c.callAPI(fmt.Sprintf("odao can-propose-setting %s %s", name, value.String()))
     if err != nil {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not get can propose setting proposal.action.time: %w", err)
+        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not get can propose setting %s status: %w", name, err)
     }
     var response api.CanProposeTNDAOSettingResponse
     if err := json.Unmarshal(responseBytes, &response); err != nil {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not decode can propose setting proposal.action.time response: %w", err)
+        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not decode can propose setting %s response: %w", name, err)
     }
     if response.Error != "" {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not get can propose setting proposal.action.time: %s", response.Error)
-    }
-    return response, nil
-}
-func (c *Client) CanProposeTNDAOSettingScrubPeriod(scrubPeriod uint64) (api.CanProposeTNDAOSettingResponse, error) {
-    responseBytes, err := c.callAPI(fmt.Sprintf("odao can-propose-scrub-period %d", scrubPeriod))
-    if err != nil {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not get can propose setting minipool.scrub.period: %w", err)
-    }
-    var response api.CanProposeTNDAOSettingResponse
-    if err := json.Unmarshal(responseBytes, &response); err != nil {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not decode can propose setting minipool.scrub.period response: %w", err)
-    }
-    if response.Error != "" {
-        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not get can propose setting minipool.scrub.period: %s", response.Error)
+        return api.CanProposeTNDAOSettingResponse{}, fmt.Errorf("Could not get can propose setting %s status: %s", name, response.Error)
     }
     return response, nil
 }
 
 
 // Propose a setting update
-func (c *Client) ProposeTNDAOSettingMembersQuorum(quorum float64) (api.ProposeTNDAOSettingMembersQuorumResponse, error) {
-    responseBytes, err := c.callAPI(fmt.Sprintf("odao propose-members-quorum %f", quorum))
-    if err != nil {
-        return api.ProposeTNDAOSettingMembersQuorumResponse{}, fmt.Errorf("Could not propose oracle DAO setting members.quorum: %w", err)
-    }
-    var response api.ProposeTNDAOSettingMembersQuorumResponse
-    if err := json.Unmarshal(responseBytes, &response); err != nil {
-        return api.ProposeTNDAOSettingMembersQuorumResponse{}, fmt.Errorf("Could not decode propose oracle DAO setting members.quorum response: %w", err)
-    }
-    if response.Error != "" {
-        return api.ProposeTNDAOSettingMembersQuorumResponse{}, fmt.Errorf("Could not propose oracle DAO setting members.quorum: %s", response.Error)
-    }
-    return response, nil
-}
-func (c *Client) ProposeTNDAOSettingMembersRplBond(bondAmountWei *big.Int) (api.ProposeTNDAOSettingMembersRplBondResponse, error) {
-    responseBytes, err := c.callAPI(fmt.Sprintf("odao propose-members-rplbond %s", bondAmountWei.String()))
-    if err != nil {
-        return api.ProposeTNDAOSettingMembersRplBondResponse{}, fmt.Errorf("Could not propose oracle DAO setting members.rplbond: %w", err)
-    }
-    var response api.ProposeTNDAOSettingMembersRplBondResponse
-    if err := json.Unmarshal(responseBytes, &response); err != nil {
-        return api.ProposeTNDAOSettingMembersRplBondResponse{}, fmt.Errorf("Could not decode propose oracle DAO setting members.rplbond response: %w", err)
-    }
-    if response.Error != "" {
-        return api.ProposeTNDAOSettingMembersRplBondResponse{}, fmt.Errorf("Could not propose oracle DAO setting members.rplbond: %s", response.Error)
-    }
-    return response, nil
-}
-func (c *Client) ProposeTNDAOSettingMinipoolUnbondedMax(unbondedMinipoolMax uint64) (api.ProposeTNDAOSettingMinipoolUnbondedMaxResponse, error) {
-    responseBytes, err := c.callAPI(fmt.Sprintf("odao propose-members-minipool-unbonded-max %d", unbondedMinipoolMax))
-    if err != nil {
-        return api.ProposeTNDAOSettingMinipoolUnbondedMaxResponse{}, fmt.Errorf("Could not propose oracle DAO setting members.minipool.unbonded.max: %w", err)
-    }
-    var response api.ProposeTNDAOSettingMinipoolUnbondedMaxResponse
-    if err := json.Unmarshal(responseBytes, &response); err != nil {
-        return api.ProposeTNDAOSettingMinipoolUnbondedMaxResponse{}, fmt.Errorf("Could not decode propose oracle DAO setting members.minipool.unbonded.max response: %w", err)
-    }
-    if response.Error != "" {
-        return api.ProposeTNDAOSettingMinipoolUnbondedMaxResponse{}, fmt.Errorf("Could not propose oracle DAO setting members.minipool.unbonded.max: %s", response.Error)
-    }
-    return response, nil
-}
-func (c *Client) ProposeTNDAOSettingProposalCooldown(proposalCooldownTimespan uint64) (api.ProposeTNDAOSettingProposalCooldownResponse, error) {
-    responseBytes, err := c.callAPI(fmt.Sprintf("odao propose-proposal-cooldown %d", proposalCooldownTimespan))
-    if err != nil {
-        return api.ProposeTNDAOSettingProposalCooldownResponse{}, fmt.Errorf("Could not propose oracle DAO setting proposal.cooldown.time: %w", err)
-    }
-    var response api.ProposeTNDAOSettingProposalCooldownResponse
-    if err := json.Unmarshal(responseBytes, &response); err != nil {
-        return api.ProposeTNDAOSettingProposalCooldownResponse{}, fmt.Errorf("Could not decode propose oracle DAO setting proposal.cooldown.time response: %w", err)
-    }
-    if response.Error != "" {
-        return api.ProposeTNDAOSettingProposalCooldownResponse{}, fmt.Errorf("Could not propose oracle DAO setting proposal.cooldown.time: %s", response.Error)
-    }
-    return response, nil
-}
-func (c *Client) ProposeTNDAOSettingProposalVoteTimespan(proposalVoteTimespan uint64) (api.ProposeTNDAOSettingProposalVoteTimespanResponse, error) {
-    responseBytes, err := c.callAPI(fmt.Sprintf("odao propose-proposal-vote-timespan %d", proposalVoteTimespan))
+func (c *Client) ProposeTNDAOSetting(name string, value *big.Int) (api.ProposeTNDAOSettingResponse, error) {
+    responseBytes, err := c.callAPI(fmt.Sprintf("odao propose-setting %s %s", name, value.String()))
     if err != nil {
-        return api.ProposeTNDAOSettingProposalVoteTimespanResponse{}, fmt.Errorf("Could not propose oracle DAO setting proposal.vote.time: %w", err)
+        return api.ProposeTNDAOSettingResponse{}, fmt.Errorf("Could not propose oracle DAO setting %s: %w", name, err)
     }
-    var response api.ProposeTNDAOSettingProposalVoteTimespanResponse
+    var response api.ProposeTNDAOSettingResponse
     if err := json.Unmarshal(responseBytes, &response); err != nil {
-        return api.ProposeTNDAOSettingProposalVoteTimespanResponse{}, fmt.Errorf("Could not decode propose oracle DAO setting proposal.vote.time response: %w", err)
+        return api.ProposeTNDAOSettingResponse{}, fmt.Errorf("Could not decode propose oracle DAO setting %s response: %w", name, err)
     }
     if response.Error != "" {
-        return api.ProposeTNDAOSettingProposalVoteTimespanResponse{}, fmt.Errorf("Could not propose oracle DAO setting proposal.vote.time: %s", response.Error)
+        return api.ProposeTNDAOSettingResponse{}, fmt.Errorf("Could not propose oracle DAO setting %s: %s", name, response.Error)
     }
     return response, nil
 }
-func (c *Client) ProposeTNDAOSettingProposalVoteDelayTimespan(proposalDelayTimespan uint64) (api.ProposeTNDAOSettingProposalVoteDelayTimespanResponse, error) {
-    responseBytes, err := c.callAPI(fmt.Sprintf("odao propose-proposal-vote-delay-timespan %d", proposalDelayTimespan))
-    if err != nil {
-        return api.ProposeTNDAOSettingProposalVoteDelayTimespanResponse{}, fmt.Errorf("Could not propose oracle DAO setting proposal.vote.delay.time: %w", err)
-    }
-    var response api.ProposeTNDAOSettingProposalVoteDelayTimespanResponse
-    if err := json.Unmarshal(responseBytes, &response); err != nil {
-        return api.ProposeTNDAOSettingProposalVoteDelayTimespanResponse{}, fmt.Errorf("Could not decode propose oracle DAO setting proposal.vote.delay.time response: %w", err)
-    }
-    if response.Error != "" {
-        return api.ProposeTNDAOSettingProposalVoteDelayTimespanResponse{}, fmt.Errorf("Could not propose oracle DAO setting proposal.vote.delay.time: %s", response.Error)
-    }
-    return response, nil
-}
-func (c *Client) ProposeTNDAOSettingProposalExecuteTimespan(proposalExecuteTimespan uint64) (api.ProposeTNDAOSettingProposalExecuteTimespanResponse, error) {
-    responseBytes, err := c.callAPI(fmt.Sprintf("odao propose-proposal-execute-timespan %d", proposalExecuteTimespan))
+
+
+// Get the current oracle DAO setting values
+func (c *Client) GetTNDAOSettings() (api.GetTNDAOSettingsResponse, error) {
+    responseBytes, err := c.callAPI("odao get-settings")
     if err != nil {
-        return api.ProposeTNDAOSettingProposalExecuteTimespanResponse{}, fmt.Errorf("Could not propose oracle DAO setting proposal.execute.time: %w", err)
+        return api.GetTNDAOSettingsResponse{}, fmt.Errorf("Could not get oracle DAO settings: %w", err)
     }
-    var response api.ProposeTNDAOSettingProposalExecuteTimespanResponse
+    var response api.GetTNDAOSettingsResponse
     if err := json.Unmarshal(responseBytes, &response); err != nil {
-        return api.ProposeTNDAOSettingProposalExecuteTimespanResponse{}, fmt.Errorf("Could not decode propose oracle DAO setting proposal.execute.time response: %w", err)
+        return api.GetTNDAOSettingsResponse{}, fmt.Errorf("Could not decode oracle DAO settings response: %w", err)
     }
     if response.Error != "" {
-        return api.ProposeTNDAOSettingProposalExecuteTimespanResponse{}, fmt.Errorf("Could not propose oracle DAO setting proposal.execute.time: %s", response.Error)
+        return api.GetTNDAOSettingsResponse{}, fmt.Errorf("Could not get oracle DAO settings: %s", response.Error)
     }
     return response, nil
 }
-func (c *Client) ProposeTNDAOSettingProposalActionTimespan(proposalActionTimespan uint64) (api.ProposeTNDAOSettingProposalActionTimespanResponse, error) {
-    responseBytes, err := c.callAPI(fmt.Sprintf("odao propose-proposal-action-timespan %d", proposalActionTimespan))
+
+
+// Get the active challenges against oracle DAO members
+func (c *Client) TNDAOChallenges() (api.TNDAOChallengesResponse, error) {
+    responseBytes, err := c.callAPI("odao challenges")
     if err != nil {
-        return api.ProposeTNDAOSettingProposalActionTimespanResponse{}, fmt.Errorf("Could not propose oracle DAO setting proposal.action.time: %w", err)
+        return api.TNDAOChallengesResponse{}, fmt.Errorf("Could not get oracle DAO challenges: %w", err)
     }
-    var response api.ProposeTNDAOSettingProposalActionTimespanResponse
+    var response api.TNDAOChallengesResponse
     if err := json.Unmarshal(responseBytes, &response); err != nil {
-        return api.ProposeTNDAOSettingProposalActionTimespanResponse{}, fmt.Errorf("Could not decode propose oracle DAO setting proposal.action.time response: %w", err)
+        return api.TNDAOChallengesResponse{}, fmt.Errorf("Could not decode oracle DAO challenges response: %w", err)
     }
     if response.Error != "" {
-        return api.ProposeTNDAOSettingProposalActionTimespanResponse{}, fmt.Errorf("Could not propose oracle DAO setting proposal.action.time: %s", response.Error)
+        return api.TNDAOChallengesResponse{}, fmt.Errorf("Could not get oracle DAO challenges: %s", response.Error)
     }
     return response, nil
 }
-func (c *Client) ProposeTNDAOSettingScrubPeriod(scrubPeriod uint64) (api.ProposeTNDAOSettingScrubPeriodResponse, error) {
-    responseBytes, err := c.callAPI(fmt.Sprintf("odao propose-scrub-period %d", scrubPeriod))
+
+
+// Check whether the node can challenge a member
+func (c *Client) CanMakeTNDAOChallenge(memberAddress common.Address) (api.CanMakeTNDAOChallengeResponse, error) {
+    responseBytes, err := c.callAPI("odao can-make-challenge", memberAddress.Hex())
     if err != nil {
-        return api.ProposeTNDAOSettingScrubPeriodResponse{}, fmt.Errorf("Could not propose oracle DAO setting minipool.scrub.period: %w", err)
+        return api.CanMakeTNDAOChallengeResponse{}, fmt.Errorf("Could not get can make oracle DAO challenge status: %w", err)
     }
-    var response api.ProposeTNDAOSettingScrubPeriodResponse
+    var response api.CanMakeTNDAOChallengeResponse
     if err := json.Unmarshal(responseBytes, &response); err != nil {
-        return api.ProposeTNDAOSettingScrubPeriodResponse{}, fmt.Errorf("Could not decode propose oracle DAO setting minipool.scrub.period response: %w", err)
+        return api.CanMakeTNDAOChallengeResponse{}, fmt.Errorf("Could not decode can make oracle DAO challenge response: %w", err)
     }
     if response.Error != "" {
-        return api.ProposeTNDAOSettingScrubPeriodResponse{}, fmt.Errorf("Could not propose oracle DAO setting minipool.scrub.period: %s", response.Error)
+        return api.CanMakeTNDAOChallengeResponse{}, fmt.Errorf("Could not get can make oracle DAO challenge status: %s", response.Error)
     }
+    if response.ChallengeCost == nil { response.ChallengeCost = big.NewInt(0) }
     return response, nil
 }
 
 
-// Get the member settings
-func (c *Client) GetTNDAOMemberSettings() (api.GetTNDAOMemberSettingsResponse, error) {
-    responseBytes, err := c.callAPI(fmt.Sprintf("odao get-member-settings"))
+// Challenge a member
+func (c *Client) MakeTNDAOChallenge(memberAddress common.Address) (api.MakeTNDAOChallengeResponse, error) {
+    responseBytes, err := c.callAPI("odao make-challenge", memberAddress.Hex())
     if err != nil {
-        return api.GetTNDAOMemberSettingsResponse{}, fmt.Errorf("Could not get oracle DAO member settings: %w", err)
+        return api.MakeTNDAOChallengeResponse{}, fmt.Errorf("Could not challenge oracle DAO member: %w", err)
     }
-    var response api.GetTNDAOMemberSettingsResponse
+    var response api.MakeTNDAOChallengeResponse
     if err := json.Unmarshal(responseBytes, &response); err != nil {
-        return api.GetTNDAOMemberSettingsResponse{}, fmt.Errorf("Could not decode oracle DAO member settings response: %w", err)
+        return api.MakeTNDAOChallengeResponse{}, fmt.Errorf("Could not decode make oracle DAO challenge response: %w", err)
     }
     if response.Error != "" {
-        return api.GetTNDAOMemberSettingsResponse{}, fmt.Errorf("Could not get oracle DAO member settings: %s", response.Error)
+        return api.MakeTNDAOChallengeResponse{}, fmt.Errorf("Could not challenge oracle DAO member: %s", response.Error)
     }
-    if response.RPLBond == nil { response.RPLBond = big.NewInt(0) }
-    if response.ChallengeCost == nil { response.ChallengeCost = big.NewInt(0) }
     return response, nil
 }
 
 
-// Get the proposal settings
-func (c *Client) GetTNDAOProposalSettings() (api.GetTNDAOProposalSettingsResponse, error) {
-    responseBytes, err := c.callAPI(fmt.Sprintf("odao get-proposal-settings"))
+// Audit the oracle DAO members' balances or prices submissions over a block range
+func (c *Client) TNDAOSubmissions(submissionType string, fromBlock, toBlock, lateBlocks uint64) (api.TNDAOSubmissionsResponse, error) {
+    responseBytes, err := c.callAPI(fmt.Sprintf("odao submissions %s %d %d %d", submissionType, fromBlock, toBlock, lateBlocks))
     if err != nil {
-        return api.GetTNDAOProposalSettingsResponse{}, fmt.Errorf("Could not get oracle DAO proposal settings: %w", err)
+        return api.TNDAOSubmissionsResponse{}, fmt.Errorf("Could not get oracle DAO submissions: %w", err)
     }
-    var response api.GetTNDAOProposalSettingsResponse
+    var response api.TNDAOSubmissionsResponse
     if err := json.Unmarshal(responseBytes, &response); err != nil {
-        return api.GetTNDAOProposalSettingsResponse{}, fmt.Errorf("Could not decode oracle DAO proposal settings response: %w", err)
+        return api.TNDAOSubmissionsResponse{}, fmt.Errorf("Could not decode oracle DAO submissions response: %w", err)
     }
     if response.Error != "" {
-        return api.GetTNDAOProposalSettingsResponse{}, fmt.Errorf("Could not get oracle DAO proposal settings: %s", response.Error)
+        return api.TNDAOSubmissionsResponse{}, fmt.Errorf("Could not get oracle DAO submissions: %s", response.Error)
     }
     return response, nil
 }
 
 
-// Get the proposal settings
-func (c *Client) GetTNDAOMinipoolSettings() (api.GetTNDAOMinipoolSettingsResponse, error) {
-    responseBytes, err := c.callAPI(fmt.Sprintf("odao get-minipool-settings"))
+// Get the evidence reports for the minipools the watchtower has voted to scrub
+func (c *Client) TNDAOScrubReports() (api.TNDAOScrubReportsResponse, error) {
+    responseBytes, err := c.callAPI("odao scrub-reports")
     if err != nil {
-        return api.GetTNDAOMinipoolSettingsResponse{}, fmt.Errorf("Could not get oracle DAO minipool settings: %w", err)
+        return api.TNDAOScrubReportsResponse{}, fmt.Errorf("Could not get scrub reports: %w", err)
     }
-    var response api.GetTNDAOMinipoolSettingsResponse
+    var response api.TNDAOScrubReportsResponse
     if err := json.Unmarshal(responseBytes, &response); err != nil {
-        return api.GetTNDAOMinipoolSettingsResponse{}, fmt.Errorf("Could not decode oracle DAO minipool settings response: %w", err)
+        return api.TNDAOScrubReportsResponse{}, fmt.Errorf("Could not decode scrub reports response: %w", err)
     }
     if response.Error != "" {
-        return api.GetTNDAOMinipoolSettingsResponse{}, fmt.Errorf("Could not get oracle DAO minipool settings: %s", response.Error)
+        return api.TNDAOScrubReportsResponse{}, fmt.Errorf("Could not get scrub reports: %s", response.Error)
     }
     return response, nil
 }
-
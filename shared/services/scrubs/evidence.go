@@ -0,0 +1,203 @@
+package scrubs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/rocketpool-go/types"
+)
+
+// Config
+const (
+    FileMode = 0600
+    DirMode = 0700
+)
+
+// Scrub check steps
+const (
+    StepBeacon = "beacon"
+    StepPrestake = "prestake"
+    StepDepositContract = "depositContract"
+    StepSafety = "safety"
+)
+
+
+// Persisted evidence reports for the minipools the watchtower votes to scrub, stored as one file per minipool
+type EvidenceStore struct {
+    path string
+    lock sync.Mutex
+}
+
+
+// The evidence for a scrub vote, recording the step that failed and the data it was based on
+type Evidence struct {
+    Minipool common.Address                         `json:"minipool"`
+    NodeAddress common.Address                      `json:"nodeAddress"`
+    Pubkey types.ValidatorPubkey                    `json:"pubkey"`
+    Step string                                     `json:"step"`
+    Reason string                                   `json:"reason"`
+    DetectedTime time.Time                          `json:"detectedTime"`
+    ExpectedWithdrawalCredentials common.Hash       `json:"expectedWithdrawalCredentials"`
+    ObservedWithdrawalCredentials *common.Hash      `json:"observedWithdrawalCredentials,omitempty"`
+    SignatureError string                           `json:"signatureError,omitempty"`
+    Prestake *PrestakeEvidence                      `json:"prestake,omitempty"`
+    Deposit *DepositEvidence                        `json:"deposit,omitempty"`
+    InvalidDeposits []DepositEvidence               `json:"invalidDeposits,omitempty"`
+    PrelaunchTime *time.Time                        `json:"prelaunchTime,omitempty"`
+    LatestBlockTime *time.Time                      `json:"latestBlockTime,omitempty"`
+    SafetyPeriod string                             `json:"safetyPeriod,omitempty"`
+    DryRun bool                                     `json:"dryRun"`
+    VoteTxHash *common.Hash                         `json:"voteTxHash,omitempty"`
+    VoteError string                                `json:"voteError,omitempty"`
+}
+type PrestakeEvidence struct {
+    WithdrawalCredentials common.Hash               `json:"withdrawalCredentials"`
+    Amount uint64                                   `json:"amount"`
+    Signature types.ValidatorSignature              `json:"signature"`
+    Time time.Time                                  `json:"time"`
+}
+type DepositEvidence struct {
+    TxHash common.Hash                              `json:"txHash"`
+    BlockNumber uint64                              `json:"blockNumber"`
+    TxIndex uint                                    `json:"txIndex"`
+    DepositIndex int                                `json:"depositIndex"`
+    Amount uint64                                   `json:"amount"`
+    WithdrawalCredentials common.Hash               `json:"withdrawalCredentials"`
+    Signature types.ValidatorSignature              `json:"signature"`
+    Error string                                    `json:"error,omitempty"`
+}
+
+
+// Create new evidence store
+func NewEvidenceStore(path string) *EvidenceStore {
+    return &EvidenceStore{
+        path: path,
+    }
+}
+
+
+// Save a minipool's scrub evidence, replacing any previous report for the minipool
+func (s *EvidenceStore) Save(evidence Evidence) error {
+
+    // Encode evidence
+    evidenceBytes, err := json.MarshalIndent(evidence, "", "  ")
+    if err != nil {
+        return fmt.Errorf("Could not encode scrub evidence for minipool %s: %w", evidence.Minipool.Hex(), err)
+    }
+
+    // Write evidence
+    s.lock.Lock()
+    defer s.lock.Unlock()
+    if err := os.MkdirAll(s.path, DirMode); err != nil {
+        return fmt.Errorf("Could not create scrub evidence folder: %w", err)
+    }
+    path := s.getPath(evidence.Minipool)
+    if err := ioutil.WriteFile(path, evidenceBytes, FileMode); err != nil {
+        return fmt.Errorf("Could not write scrub evidence to %s: %w", path, err)
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Get a minipool's scrub evidence; returns false if there is no report for the minipool
+func (s *EvidenceStore) Get(minipoolAddress common.Address) (Evidence, bool, error) {
+    s.lock.Lock()
+    defer s.lock.Unlock()
+    evidence, err := s.load(s.getPath(minipoolAddress))
+    if os.IsNotExist(err) {
+        return Evidence{}, false, nil
+    }
+    if err != nil {
+        return Evidence{}, false, err
+    }
+    return evidence, true, nil
+}
+
+
+// Get all scrub evidence reports, most recent first
+func (s *EvidenceStore) GetAll() ([]Evidence, error) {
+
+    // Get evidence files
+    s.lock.Lock()
+    defer s.lock.Unlock()
+    files, err := ioutil.ReadDir(s.path)
+    if os.IsNotExist(err) {
+        return []Evidence{}, nil
+    }
+    if err != nil {
+        return nil, fmt.Errorf("Could not read scrub evidence folder: %w", err)
+    }
+
+    // Load evidence
+    reports := []Evidence{}
+    for _, file := range files {
+        if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+            continue
+        }
+        evidence, err := s.load(filepath.Join(s.path, file.Name()))
+        if err != nil {
+            return nil, err
+        }
+        reports = append(reports, evidence)
+    }
+
+    // Sort & return
+    sort.Slice(reports, func(i, j int) bool { return reports[i].DetectedTime.After(reports[j].DetectedTime) })
+    return reports, nil
+
+}
+
+
+// Get a short description of the evidence, for notifications & logs
+func (e Evidence) Summary() string {
+    lines := []string{
+        fmt.Sprintf("Node: %s", e.NodeAddress.Hex()),
+        fmt.Sprintf("Validator: %s", e.Pubkey.Hex()),
+        fmt.Sprintf("Failed check: %s - %s", e.Step, e.Reason),
+        fmt.Sprintf("Expected withdrawal credentials: %s", e.ExpectedWithdrawalCredentials.Hex()),
+    }
+    if e.ObservedWithdrawalCredentials != nil {
+        lines = append(lines, fmt.Sprintf("Observed withdrawal credentials: %s", e.ObservedWithdrawalCredentials.Hex()))
+    }
+    if e.Deposit != nil {
+        lines = append(lines, fmt.Sprintf("Deposit: transaction %s in block %d", e.Deposit.TxHash.Hex(), e.Deposit.BlockNumber))
+    }
+    if e.SignatureError != "" {
+        lines = append(lines, fmt.Sprintf("Prestake signature error: %s", e.SignatureError))
+    }
+    if e.PrelaunchTime != nil {
+        lines = append(lines, fmt.Sprintf("In prelaunch since %s (safety scrub period %s)", e.PrelaunchTime.Format(time.RFC822), e.SafetyPeriod))
+    }
+    return strings.Join(lines, "\n")
+}
+
+
+// Get the path of a minipool's evidence file
+func (s *EvidenceStore) getPath(minipoolAddress common.Address) string {
+    return filepath.Join(s.path, minipoolAddress.Hex() + ".json")
+}
+
+
+// Load an evidence file
+func (s *EvidenceStore) load(path string) (Evidence, error) {
+    evidenceBytes, err := ioutil.ReadFile(path)
+    if err != nil {
+        return Evidence{}, err
+    }
+    var evidence Evidence
+    if err := json.Unmarshal(evidenceBytes, &evidence); err != nil {
+        return Evidence{}, fmt.Errorf("Could not decode scrub evidence at %s: %w", path, err)
+    }
+    return evidence, nil
+}
@@ -0,0 +1,135 @@
+package scrubs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/rocketpool-go/types"
+)
+
+
+// Get test evidence for a minipool
+func getTestEvidence(minipool string, detectedTime time.Time) Evidence {
+    observed := common.HexToHash("0x010000000000000000000000bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+    return Evidence{
+        Minipool: common.HexToAddress(minipool),
+        NodeAddress: common.HexToAddress("0x1111111111111111111111111111111111111111"),
+        Pubkey: types.BytesToValidatorPubkey(common.FromHex("0x" + strings.Repeat("ab", types.ValidatorPubkeyLength))),
+        Step: StepDepositContract,
+        Reason: "conflicting deposit",
+        DetectedTime: detectedTime.UTC(),
+        ExpectedWithdrawalCredentials: common.HexToHash("0x010000000000000000000000aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"),
+        ObservedWithdrawalCredentials: &observed,
+        Deposit: &DepositEvidence{
+            TxHash: common.HexToHash("0x1234"),
+            BlockNumber: 140,
+            Amount: 1000000000,
+            WithdrawalCredentials: observed,
+        },
+        DryRun: true,
+    }
+}
+
+
+func TestEvidenceStore(t *testing.T) {
+    dir, err := ioutil.TempDir("", "scrubs")
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer os.RemoveAll(dir)
+    store := NewEvidenceStore(filepath.Join(dir, "evidence"))
+
+    // Missing evidence
+    if _, exists, err := store.Get(common.HexToAddress("0x01")); err != nil || exists {
+        t.Fatalf("missing evidence exists: %t, %v", exists, err)
+    }
+    if reports, err := store.GetAll(); err != nil || len(reports) != 0 {
+        t.Fatalf("missing evidence folder has reports %v, %v", reports, err)
+    }
+
+    // Save & get evidence
+    detectedTime := time.Date(2021, 11, 1, 12, 0, 0, 0, time.UTC)
+    first := getTestEvidence("0x01", detectedTime)
+    second := getTestEvidence("0x02", detectedTime.Add(time.Hour))
+    for _, evidence := range []Evidence{first, second} {
+        if err := store.Save(evidence); err != nil {
+            t.Fatal(err)
+        }
+    }
+    evidence, exists, err := store.Get(first.Minipool)
+    if err != nil || !exists {
+        t.Fatalf("saved evidence exists: %t, %v", exists, err)
+    }
+    if !reflect.DeepEqual(evidence, first) {
+        t.Errorf("evidence is %+v, expected %+v", evidence, first)
+    }
+    if info, err := os.Stat(store.getPath(first.Minipool)); err != nil || info.Mode().Perm() != FileMode {
+        t.Errorf("evidence file is %v, %v; expected mode %o", info, err, FileMode)
+    }
+
+    // Saving replaces a minipool's evidence
+    first.VoteError = "out of gas"
+    if err := store.Save(first); err != nil {
+        t.Fatal(err)
+    }
+    if evidence, _, err := store.Get(first.Minipool); err != nil || evidence.VoteError != "out of gas" {
+        t.Errorf("replaced evidence is %+v, %v", evidence, err)
+    }
+
+    // All reports are listed most recent first, ignoring other files
+    if err := ioutil.WriteFile(filepath.Join(dir, "evidence", "notes.txt"), []byte("notes"), FileMode); err != nil {
+        t.Fatal(err)
+    }
+    reports, err := store.GetAll()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(reports) != 2 || reports[0].Minipool != second.Minipool || reports[1].Minipool != first.Minipool {
+        t.Errorf("reports are %+v", reports)
+    }
+
+    // Corrupt reports are errors
+    if err := ioutil.WriteFile(store.getPath(second.Minipool), []byte("{"), FileMode); err != nil {
+        t.Fatal(err)
+    }
+    if _, _, err := store.Get(second.Minipool); err == nil || !strings.Contains(err.Error(), "Could not decode scrub evidence") {
+        t.Errorf("expected a decode error, got %v", err)
+    }
+    if _, err := store.GetAll(); err == nil {
+        t.Error("expected a decode error listing reports")
+    }
+
+}
+
+
+func TestEvidenceSummary(t *testing.T) {
+    evidence := getTestEvidence("0x01", time.Now())
+    summary := evidence.Summary()
+    for _, line := range []string{
+        "Node: 0x1111111111111111111111111111111111111111",
+        "Failed check: depositContract - conflicting deposit",
+        "Observed withdrawal credentials: 0x010000000000000000000000bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+        "Deposit: transaction 0x0000000000000000000000000000000000000000000000000000000000001234 in block 140",
+    } {
+        if !strings.Contains(summary, line) {
+            t.Errorf("summary is missing '%s':\n%s", line, summary)
+        }
+    }
+
+    // Optional details are only included when set
+    prelaunchTime := time.Date(2021, 11, 1, 12, 0, 0, 0, time.UTC)
+    evidence = Evidence{Step: StepSafety, Reason: "prelaunch too long", PrelaunchTime: &prelaunchTime, SafetyPeriod: "48h0m0s"}
+    summary = evidence.Summary()
+    if strings.Contains(summary, "Observed") || strings.Contains(summary, "Deposit:") || strings.Contains(summary, "signature error") {
+        t.Errorf("summary includes unset details:\n%s", summary)
+    }
+    if !strings.Contains(summary, "In prelaunch since 01 Nov 21 12:00 UTC (safety scrub period 48h0m0s)") {
+        t.Errorf("summary is missing the prelaunch time:\n%s", summary)
+    }
+}
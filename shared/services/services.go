@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"math/big"
 	"os"
+	"strings"
 	"sync"
 
 	"github.com/docker/docker/client"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/rocket-pool/rocketpool-go/rocketpool"
 	"github.com/urfave/cli"
 
@@ -19,16 +21,25 @@ import (
 	"github.com/rocket-pool/smartnode/shared/services/beacon/teku"
 	"github.com/rocket-pool/smartnode/shared/services/config"
 	"github.com/rocket-pool/smartnode/shared/services/contracts"
+	"github.com/rocket-pool/smartnode/shared/services/deposits"
+	"github.com/rocket-pool/smartnode/shared/services/multicall"
+	"github.com/rocket-pool/smartnode/shared/services/notify"
+	"github.com/rocket-pool/smartnode/shared/services/orchestrator"
 	"github.com/rocket-pool/smartnode/shared/services/passwords"
+	"github.com/rocket-pool/smartnode/shared/services/prices"
+	"github.com/rocket-pool/smartnode/shared/services/scrubs"
+	"github.com/rocket-pool/smartnode/shared/services/txlog"
 	"github.com/rocket-pool/smartnode/shared/services/wallet"
 	lhkeystore "github.com/rocket-pool/smartnode/shared/services/wallet/keystore/lighthouse"
 	nmkeystore "github.com/rocket-pool/smartnode/shared/services/wallet/keystore/nimbus"
 	prkeystore "github.com/rocket-pool/smartnode/shared/services/wallet/keystore/prysm"
 	tkkeystore "github.com/rocket-pool/smartnode/shared/services/wallet/keystore/teku"
+	apiutils "github.com/rocket-pool/smartnode/shared/utils/api"
+	"github.com/rocket-pool/smartnode/shared/utils/log"
 )
 
 // Config
-const DockerAPIVersion = "1.40"
+const DockerAPIVersion = orchestrator.APIVersion
 
 
 // Service instances & initializers
@@ -37,12 +48,19 @@ var (
     passwordManager *passwords.PasswordManager
     nodeWallet *wallet.Wallet
     ethClient *ethclient.Client
+    ethRPCClient *rpc.Client
     mainnetEthClient *ethclient.Client
     rocketPool *rocketpool.RocketPool
     oneInchOracle *contracts.OneInchOracle
     rplFaucet *contracts.RPLFaucet
     beaconClient beacon.Client
     docker *client.Client
+    txLog *txlog.TxLog
+    multiCaller *multicall.MultiCaller
+    notifier *notify.Notifier
+    rplPriceAggregator *prices.Aggregator
+    scrubEvidence *scrubs.EvidenceStore
+    depositIndexer *deposits.Indexer
 
     initCfg sync.Once
     initPasswordManager sync.Once
@@ -54,6 +72,12 @@ var (
     initRplFaucet sync.Once
     initBeaconClient sync.Once
     initDocker sync.Once
+    initTxLog sync.Once
+    initMultiCaller sync.Once
+    initNotifier sync.Once
+    initRplPriceAggregator sync.Once
+    initScrubEvidence sync.Once
+    initDepositIndexer sync.Once
 )
 
 
@@ -67,6 +91,21 @@ func GetConfig(c *cli.Context) (config.RocketPoolConfig, error) {
 }
 
 
+func ConfigureLogging(c *cli.Context, name string) error {
+    cfg, err := getConfig(c)
+    if err != nil {
+        return err
+    }
+    return log.Configure(name, log.Options{
+        Level: cfg.Logging.Level,
+        Format: cfg.Logging.Format,
+        Path: os.ExpandEnv(cfg.Logging.Path),
+        MaxSize: cfg.Logging.MaxSize,
+        MaxBackups: cfg.Logging.MaxBackups,
+    })
+}
+
+
 func GetPasswordManager(c *cli.Context) (*passwords.PasswordManager, error) {
     cfg, err := getConfig(c)
     if err != nil {
@@ -82,7 +121,7 @@ func GetWallet(c *cli.Context) (*wallet.Wallet, error) {
         return nil, err
     }
     pm := getPasswordManager(cfg)
-    return getWallet(cfg, pm)
+    return getWallet(cfg, pm, getTxOrigin(c))
 }
 
 
@@ -95,6 +134,19 @@ func GetEthClient(c *cli.Context) (*ethclient.Client, error) {
 }
 
 
+// Get the raw RPC client underlying the eth client, for calls it doesn't support (e.g. state overrides & tracing)
+func GetEthRPCClient(c *cli.Context) (*rpc.Client, error) {
+    cfg, err := getConfig(c)
+    if err != nil {
+        return nil, err
+    }
+    if _, err := getEthClient(cfg); err != nil {
+        return nil, err
+    }
+    return ethRPCClient, nil
+}
+
+
 func GetRocketPool(c *cli.Context) (*rocketpool.RocketPool, error) {
     cfg, err := getConfig(c)
     if err != nil {
@@ -121,6 +173,19 @@ func GetOneInchOracle(c *cli.Context) (*contracts.OneInchOracle, error) {
 }
 
 
+func GetMultiCaller(c *cli.Context) (*multicall.MultiCaller, error) {
+    cfg, err := getConfig(c)
+    if err != nil {
+        return nil, err
+    }
+    ec, err := getEthClient(cfg)
+    if err != nil {
+        return nil, err
+    }
+    return getMultiCaller(cfg, ec)
+}
+
+
 func GetRplFaucet(c *cli.Context) (*contracts.RPLFaucet, error) {
     cfg, err := getConfig(c)
     if err != nil {
@@ -148,6 +213,67 @@ func GetDocker(c *cli.Context) (*client.Client, error) {
 }
 
 
+func GetTxLog(c *cli.Context) (*txlog.TxLog, error) {
+    cfg, err := getConfig(c)
+    if err != nil {
+        return nil, err
+    }
+    return getTxLog(cfg), nil
+}
+
+
+func GetScrubEvidenceStore(c *cli.Context) (*scrubs.EvidenceStore, error) {
+    cfg, err := getConfig(c)
+    if err != nil {
+        return nil, err
+    }
+    return getScrubEvidenceStore(cfg), nil
+}
+
+
+func GetDepositIndexer(c *cli.Context) (*deposits.Indexer, error) {
+    cfg, err := getConfig(c)
+    if err != nil {
+        return nil, err
+    }
+    ec, err := getEthClient(cfg)
+    if err != nil {
+        return nil, err
+    }
+    rp, err := getRocketPool(cfg, ec)
+    if err != nil {
+        return nil, err
+    }
+    return getDepositIndexer(cfg, rp)
+}
+
+
+func GetNotifier(c *cli.Context) (*notify.Notifier, error) {
+    cfg, err := getConfig(c)
+    if err != nil {
+        return nil, err
+    }
+    return getNotifier(cfg), nil
+}
+
+
+func GetRplPriceAggregator(c *cli.Context) (*prices.Aggregator, error) {
+    cfg, err := getConfig(c)
+    if err != nil {
+        return nil, err
+    }
+    ec, err := getEthClient(cfg)
+    if err != nil {
+        return nil, err
+    }
+    oio, err := getOneInchOracle(cfg, ec)
+    if err != nil {
+        return nil, err
+    }
+    return getRplPriceAggregator(cfg, ec, oio)
+}
+
+
 //
 // Service instance getters
 //
@@ -170,7 +296,7 @@ func getPasswordManager(cfg config.RocketPoolConfig) *passwords.PasswordManager
 }
 
 
-func getWallet(cfg config.RocketPoolConfig, pm *passwords.PasswordManager) (*wallet.Wallet, error) {
+func getWallet(cfg config.RocketPoolConfig, pm *passwords.PasswordManager, txOrigin string) (*wallet.Wallet, error) {
     var err error
     initNodeWallet.Do(func() {
         var maxFee *big.Int
@@ -192,6 +318,8 @@ func getWallet(cfg config.RocketPoolConfig, pm *passwords.PasswordManager) (*wal
         nodeWallet.AddKeystore("nimbus", nimbusKeystore)
         nodeWallet.AddKeystore("prysm", prysmKeystore)
         nodeWallet.AddKeystore("teku", tekuKeystore)
+        nodeWallet.SetTxLog(getTxLog(cfg))
+        nodeWallet.SetTxOrigin(txOrigin)
     })
     return nodeWallet, err
 }
@@ -200,7 +328,10 @@ func getWallet(cfg config.RocketPoolConfig, pm *passwords.PasswordManager) (*wal
 func getEthClient(cfg config.RocketPoolConfig) (*ethclient.Client, error) {
     var err error
     initEthClient.Do(func() {
-        ethClient, err = ethclient.Dial(cfg.Chains.Eth1.Provider)
+        ethRPCClient, err = rpc.Dial(cfg.Chains.Eth1.Provider)
+        if err == nil {
+            ethClient = ethclient.NewClient(ethRPCClient)
+        }
     })
     return ethClient, err
 }
@@ -224,6 +355,15 @@ func getOneInchOracle(cfg config.RocketPoolConfig, client *ethclient.Client) (*c
 }
 
 
+func getRplPriceAggregator(cfg config.RocketPoolConfig, client *ethclient.Client, oio *contracts.OneInchOracle) (*prices.Aggregator, error) {
+    var err error
+    initRplPriceAggregator.Do(func() {
+        rplPriceAggregator, err = prices.NewAggregator(cfg, client, oio)
+    })
+    return rplPriceAggregator, err
+}
+
+
 func getRplFaucet(cfg config.RocketPoolConfig, client *ethclient.Client) (*contracts.RPLFaucet, error) {
     var err error
     initRplFaucet.Do(func() {
@@ -233,6 +373,15 @@ func getRplFaucet(cfg config.RocketPoolConfig, client *ethclient.Client) (*contr
 }
 
 
+func getMultiCaller(cfg config.RocketPoolConfig, client *ethclient.Client) (*multicall.MultiCaller, error) {
+    var err error
+    initMultiCaller.Do(func() {
+        multiCaller, err = multicall.NewMultiCaller(client, multicall.GetAddress(cfg.Rocketpool.MulticallAddress, cfg.Chains.Eth1.ChainID))
+    })
+    return multiCaller, err
+}
+
+
 func getBeaconClient(cfg config.RocketPoolConfig) (beacon.Client, error) {
     var err error
     initBeaconClient.Do(func() {
@@ -260,3 +409,50 @@ func getDocker() (*client.Client, error) {
     })
     return docker, err
 }
+
+
+func getTxLog(cfg config.RocketPoolConfig) *txlog.TxLog {
+    initTxLog.Do(func() {
+        txLog = txlog.NewTxLog(cfg.GetTxHistoryPath())
+    })
+    return txLog
+}
+
+
+func getScrubEvidenceStore(cfg config.RocketPoolConfig) *scrubs.EvidenceStore {
+    initScrubEvidence.Do(func() {
+        scrubEvidence = scrubs.NewEvidenceStore(cfg.GetScrubEvidencePath())
+    })
+    return scrubEvidence
+}
+
+
+func getDepositIndexer(cfg config.RocketPoolConfig, rp *rocketpool.RocketPool) (*deposits.Indexer, error) {
+    var err error
+    initDepositIndexer.Do(func() {
+        var eventLogInterval *big.Int
+        eventLogInterval, err = apiutils.GetEventLogInterval(cfg)
+        if err == nil {
+            depositIndexer = deposits.NewIndexer(rp, cfg.GetDepositIndexPath(), eventLogInterval)
+        }
+    })
+    return depositIndexer, err
+}
+
+
+func getNotifier(cfg config.RocketPoolConfig) *notify.Notifier {
+    initNotifier.Do(func() {
+        notifier = notify.NewNotifier(os.ExpandEnv(cfg.Smartnode.NotificationUrl))
+    })
+    return notifier
+}
+
+
+// Get the origin recorded with transactions sent by a command, without the binary name
+func getTxOrigin(c *cli.Context) string {
+    origin := strings.TrimSpace(fmt.Sprintf("%s %s", c.App.Name, c.Command.Name))
+    if parts := strings.SplitN(origin, " ", 2); len(parts) == 2 {
+        return parts[1]
+    }
+    return origin
+}
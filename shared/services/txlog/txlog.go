@@ -0,0 +1,347 @@
+package txlog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Config
+const (
+    FileMode = 0600
+    DirMode = 0700
+)
+
+// Transaction statuses
+const (
+    StatusPending = "pending"
+    StatusSuccess = "success"
+    StatusFailed = "failed"
+    StatusDropped = "dropped"
+)
+
+// ERC20 Transfer(address,address,uint256) event topic
+var transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+
+// Append-only log of the transactions sent by the node account
+// Signed transactions are held in memory until they are confirmed to have been sent
+type TxLog struct {
+    path string
+    lock sync.Mutex
+    signedLock sync.Mutex
+    signed []Record
+}
+
+
+// A transaction sent by the node account
+// Each transaction is appended once when it is sent, and again with its result once it has been mined
+type Record struct {
+    Time time.Time                      `json:"time"`
+    Origin string                       `json:"origin,omitempty"`
+    Hash common.Hash                    `json:"hash"`
+    From common.Address                 `json:"from"`
+    To *common.Address                  `json:"to,omitempty"`
+    Nonce uint64                        `json:"nonce"`
+    Value *big.Int                      `json:"value,omitempty"`
+    Status string                       `json:"status"`
+    BlockNumber uint64                  `json:"blockNumber,omitempty"`
+    BlockTime time.Time                 `json:"blockTime,omitempty"`
+    GasUsed uint64                      `json:"gasUsed,omitempty"`
+    EffectiveGasPrice *big.Int          `json:"effectiveGasPrice,omitempty"`
+    TokenTransfers []TokenTransfer      `json:"tokenTransfers,omitempty"`
+}
+type TokenTransfer struct {
+    Token common.Address                `json:"token"`
+    From common.Address                 `json:"from"`
+    To common.Address                   `json:"to"`
+    Amount *big.Int                     `json:"amount"`
+}
+
+
+// Create new transaction log
+func NewTxLog(path string) *TxLog {
+    return &TxLog{
+        path: path,
+    }
+}
+
+
+// Hold a signed transaction which is about to be sent until it is confirmed to have been sent
+func (l *TxLog) AddSignedTransaction(origin string, from common.Address, tx *types.Transaction) {
+    l.signedLock.Lock()
+    defer l.signedLock.Unlock()
+    l.signed = append(l.signed, Record{
+        Time: time.Now(),
+        Origin: origin,
+        Hash: tx.Hash(),
+        From: from,
+        To: tx.To(),
+        Nonce: tx.Nonce(),
+        Value: tx.Value(),
+        Status: StatusPending,
+    })
+}
+
+
+// Record the signed transactions which the eth1 client has received as pending
+// Transactions which failed to send are unknown to the client and are discarded
+func (l *TxLog) RecordSentTransactions(ec *ethclient.Client) error {
+
+    // Take the signed transactions
+    l.signedLock.Lock()
+    signed := l.signed
+    l.signed = nil
+    l.signedLock.Unlock()
+
+    // Record the transactions which were sent
+    for _, record := range signed {
+        if _, _, err := ec.TransactionByHash(context.Background(), record.Hash); err == ethereum.NotFound {
+            continue
+        } else if err != nil {
+            return fmt.Errorf("Could not get transaction %s: %w", record.Hash.Hex(), err)
+        }
+        if err := l.append(record); err != nil {
+            return err
+        }
+    }
+    return nil
+
+}
+
+
+// Check whether there are signed transactions which have not been recorded yet
+func (l *TxLog) HasSignedTransactions() bool {
+    l.signedLock.Lock()
+    defer l.signedLock.Unlock()
+    return len(l.signed) > 0
+}
+
+
+// Record the result of a mined transaction
+func (l *TxLog) AddReceipt(ec *ethclient.Client, receipt *types.Receipt) error {
+
+    // Get the transaction and the block it was mined in
+    tx, _, err := ec.TransactionByHash(context.Background(), receipt.TxHash)
+    if err != nil {
+        return fmt.Errorf("Could not get transaction %s: %w", receipt.TxHash.Hex(), err)
+    }
+    header, err := ec.HeaderByHash(context.Background(), receipt.BlockHash)
+    if err != nil {
+        return fmt.Errorf("Could not get block %s: %w", receipt.BlockHash.Hex(), err)
+    }
+
+    // Get the effective gas price; dynamic fee transactions pay the base fee plus the capped tip
+    effectiveGasPrice := tx.GasPrice()
+    if tx.Type() == types.DynamicFeeTxType && header.BaseFee != nil {
+        effectiveGasPrice = new(big.Int).Add(header.BaseFee, tx.GasTipCap())
+        if effectiveGasPrice.Cmp(tx.GasFeeCap()) > 0 {
+            effectiveGasPrice = tx.GasFeeCap()
+        }
+    }
+
+    // Get token transfers
+    tokenTransfers := []TokenTransfer{}
+    for _, log := range receipt.Logs {
+        if len(log.Topics) != 3 || log.Topics[0] != transferTopic || len(log.Data) != 32 {
+            continue
+        }
+        tokenTransfers = append(tokenTransfers, TokenTransfer{
+            Token: log.Address,
+            From: common.BytesToAddress(log.Topics[1].Bytes()),
+            To: common.BytesToAddress(log.Topics[2].Bytes()),
+            Amount: new(big.Int).SetBytes(log.Data),
+        })
+    }
+
+    // Get status
+    status := StatusSuccess
+    if receipt.Status == types.ReceiptStatusFailed {
+        status = StatusFailed
+    }
+
+    // Append record
+    return l.append(Record{
+        Time: time.Now(),
+        Hash: receipt.TxHash,
+        Status: status,
+        BlockNumber: receipt.BlockNumber.Uint64(),
+        BlockTime: time.Unix(int64(header.Time), 0),
+        GasUsed: receipt.GasUsed,
+        EffectiveGasPrice: effectiveGasPrice,
+        TokenTransfers: tokenTransfers,
+    })
+
+}
+
+
+// Record that a transaction was never mined
+func (l *TxLog) AddDropped(hash common.Hash) error {
+    return l.append(Record{
+        Time: time.Now(),
+        Hash: hash,
+        Status: StatusDropped,
+    })
+}
+
+
+// Get all recorded transactions, merged by hash and ordered by the time they were sent
+func (l *TxLog) GetRecords() ([]Record, error) {
+
+    // Lock
+    l.lock.Lock()
+    defer l.lock.Unlock()
+
+    // Open log file
+    file, err := os.Open(l.path)
+    if os.IsNotExist(err) {
+        return []Record{}, nil
+    }
+    if err != nil {
+        return []Record{}, fmt.Errorf("Could not open transaction log at %s: %w", l.path, err)
+    }
+    defer file.Close()
+
+    // Read & merge records
+    records := []*Record{}
+    recordsByHash := make(map[common.Hash]*Record)
+    scanner := bufio.NewScanner(file)
+    for scanner.Scan() {
+        var entry Record
+        if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+            return []Record{}, fmt.Errorf("Could not decode transaction log entry: %w", err)
+        }
+        record, exists := recordsByHash[entry.Hash]
+        if !exists {
+            record = &entry
+            recordsByHash[entry.Hash] = record
+            records = append(records, record)
+            continue
+        }
+        if entry.Origin != "" {
+            record.Time = entry.Time
+            record.Origin = entry.Origin
+            record.From = entry.From
+            record.To = entry.To
+            record.Nonce = entry.Nonce
+            record.Value = entry.Value
+        }
+        if entry.Status != StatusPending {
+            record.Status = entry.Status
+            record.BlockNumber = entry.BlockNumber
+            record.BlockTime = entry.BlockTime
+            record.GasUsed = entry.GasUsed
+            record.EffectiveGasPrice = entry.EffectiveGasPrice
+            record.TokenTransfers = entry.TokenTransfers
+        }
+    }
+    if err := scanner.Err(); err != nil {
+        return []Record{}, fmt.Errorf("Could not read transaction log at %s: %w", l.path, err)
+    }
+
+    // Sort by send time
+    sort.SliceStable(records, func(i, j int) bool {
+        return records[i].Time.Before(records[j].Time)
+    })
+    result := make([]Record, len(records))
+    for ri, record := range records {
+        result[ri] = *record
+    }
+
+    // Return
+    return result, nil
+
+}
+
+
+// Look up the results of any pending transactions and record them
+func (l *TxLog) ResolvePending(ec *ethclient.Client) error {
+
+    // Get records
+    records, err := l.GetRecords()
+    if err != nil {
+        return err
+    }
+
+    // Check pending transactions
+    for _, record := range records {
+        if record.Status != StatusPending {
+            continue
+        }
+
+        // Record the receipt if the transaction has been mined
+        receipt, err := ec.TransactionReceipt(context.Background(), record.Hash)
+        if err == nil {
+            if err := l.AddReceipt(ec, receipt); err != nil {
+                return err
+            }
+            continue
+        }
+        if err != ethereum.NotFound {
+            return fmt.Errorf("Could not get receipt for transaction %s: %w", record.Hash.Hex(), err)
+        }
+
+        // Mark the transaction as dropped if its nonce has been used by another mined transaction
+        nonce, err := ec.NonceAt(context.Background(), record.From, nil)
+        if err != nil {
+            return fmt.Errorf("Could not get account nonce: %w", err)
+        }
+        if record.Nonce < nonce {
+            if _, _, err := ec.TransactionByHash(context.Background(), record.Hash); err == ethereum.NotFound {
+                if err := l.AddDropped(record.Hash); err != nil {
+                    return err
+                }
+            }
+        }
+
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Append a record to the log file
+func (l *TxLog) append(record Record) error {
+
+    // Encode record
+    recordBytes, err := json.Marshal(record)
+    if err != nil {
+        return fmt.Errorf("Could not encode transaction log entry: %w", err)
+    }
+
+    // Lock
+    l.lock.Lock()
+    defer l.lock.Unlock()
+
+    // Append record to file
+    if err := os.MkdirAll(filepath.Dir(l.path), DirMode); err != nil {
+        return fmt.Errorf("Could not create transaction log folder: %w", err)
+    }
+    file, err := os.OpenFile(l.path, os.O_APPEND | os.O_CREATE | os.O_WRONLY, FileMode)
+    if err != nil {
+        return fmt.Errorf("Could not open transaction log at %s: %w", l.path, err)
+    }
+    defer file.Close()
+    if _, err := file.Write(append(recordBytes, '\n')); err != nil {
+        return fmt.Errorf("Could not write to transaction log at %s: %w", l.path, err)
+    }
+
+    // Return
+    return nil
+
+}
@@ -0,0 +1,252 @@
+package txlog
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+
+// Create a transaction log in a temporary folder with the given entries
+func newTestTxLog(t *testing.T, entries ...Record) (*TxLog, func()) {
+    dir, err := ioutil.TempDir("", "txlog")
+    if err != nil {
+        t.Fatal(err)
+    }
+    l := NewTxLog(filepath.Join(dir, "txlog", "transactions.jsonl"))
+    for _, entry := range entries {
+        if err := l.append(entry); err != nil {
+            os.RemoveAll(dir)
+            t.Fatal(err)
+        }
+    }
+    return l, func() { os.RemoveAll(dir) }
+}
+
+
+func TestGetRecords(t *testing.T) {
+    sentAt := time.Date(2021, 11, 1, 12, 0, 0, 0, time.UTC)
+    from := common.HexToAddress("0x1111111111111111111111111111111111111111")
+    to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+    hashA := common.HexToHash("0xaa")
+    hashB := common.HexToHash("0xbb")
+    hashC := common.HexToHash("0xcc")
+
+    l, cleanup := newTestTxLog(t,
+        Record{Time: sentAt.Add(time.Minute), Origin: "node stake", Hash: hashB, From: from, To: &to, Nonce: 2, Value: big.NewInt(0), Status: StatusPending},
+        Record{Time: sentAt, Origin: "node deposit", Hash: hashA, From: from, To: &to, Nonce: 1, Value: big.NewInt(16), Status: StatusPending},
+        Record{Time: sentAt.Add(time.Hour), Hash: hashA, Status: StatusSuccess, BlockNumber: 100, GasUsed: 21000, EffectiveGasPrice: big.NewInt(5)},
+        Record{Time: sentAt.Add(time.Hour), Hash: hashC, Status: StatusFailed, BlockNumber: 101},
+        Record{Time: sentAt.Add(2 * time.Minute), Origin: "node withdraw", Hash: hashC, From: from, Nonce: 3, Status: StatusPending},
+        Record{Time: sentAt.Add(2 * time.Hour), Hash: hashB, Status: StatusDropped},
+    )
+    defer cleanup()
+
+    // Get records
+    records, err := l.GetRecords()
+    if err != nil {
+        t.Fatal(err)
+    }
+    expected := []struct {
+        hash common.Hash
+        origin string
+        nonce uint64
+        status string
+        blockNumber uint64
+    }{
+        {hashA, "node deposit", 1, StatusSuccess, 100},
+        {hashB, "node stake", 2, StatusDropped, 0},
+        {hashC, "node withdraw", 3, StatusFailed, 101},
+    }
+    if len(records) != len(expected) {
+        t.Fatalf("got %d records, expected %d", len(records), len(expected))
+    }
+    for ri, record := range records {
+        e := expected[ri]
+        if record.Hash != e.hash || record.Origin != e.origin || record.Nonce != e.nonce || record.Status != e.status || record.BlockNumber != e.blockNumber {
+            t.Errorf("record %d is %s %s nonce %d %s block %d, expected %s %s nonce %d %s block %d", ri,
+                record.Hash.Hex(), record.Origin, record.Nonce, record.Status, record.BlockNumber,
+                e.hash.Hex(), e.origin, e.nonce, e.status, e.blockNumber)
+        }
+    }
+
+    // Send details are kept from the send entry, and results from the receipt entry
+    if !records[0].Time.Equal(sentAt) || records[0].Value.Cmp(big.NewInt(16)) != 0 || records[0].EffectiveGasPrice.Cmp(big.NewInt(5)) != 0 {
+        t.Errorf("merged record is %+v", records[0])
+    }
+    if !records[2].Time.Equal(sentAt.Add(2 * time.Minute)) {
+        t.Errorf("record sent at %s, expected the send time %s", records[2].Time, sentAt.Add(2 * time.Minute))
+    }
+
+}
+
+
+func TestGetRecordsFile(t *testing.T) {
+
+    // Missing logs have no records
+    l, cleanup := newTestTxLog(t)
+    defer cleanup()
+    records, err := l.GetRecords()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(records) != 0 {
+        t.Errorf("got %d records from a missing log, expected none", len(records))
+    }
+
+    // Corrupt entries are reported
+    if err := os.MkdirAll(filepath.Dir(l.path), DirMode); err != nil {
+        t.Fatal(err)
+    }
+    if err := ioutil.WriteFile(l.path, []byte("{\"hash\":\"0xaa\"}\nnot json\n"), FileMode); err != nil {
+        t.Fatal(err)
+    }
+    if _, err := l.GetRecords(); err == nil || !strings.Contains(err.Error(), "Could not decode transaction log entry") {
+        t.Errorf("expected a decode error, got %v", err)
+    }
+
+}
+
+
+// A fake eth1 client serving transactions, receipts and headers for the transaction log
+type fakeEthClient struct {
+    nonce uint64
+    transactions map[common.Hash]*types.Transaction
+    receipts map[common.Hash]*types.Receipt
+    header *types.Header
+}
+
+
+// Serve a JSON-RPC request
+func (e *fakeEthClient) serve(w http.ResponseWriter, r *http.Request) {
+    var request struct {
+        ID json.RawMessage              `json:"id"`
+        Method string                   `json:"method"`
+        Params []json.RawMessage        `json:"params"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+    var hash common.Hash
+    if len(request.Params) > 0 {
+        _ = json.Unmarshal(request.Params[0], &hash)
+    }
+    var result interface{}
+    switch request.Method {
+        case "eth_getTransactionCount":
+            result = hexUint64(e.nonce)
+        case "eth_getTransactionByHash":
+            if tx, ok := e.transactions[hash]; ok {
+                result = tx
+            }
+        case "eth_getTransactionReceipt":
+            if receipt, ok := e.receipts[hash]; ok {
+                result = receipt
+            }
+        case "eth_getBlockByHash":
+            result = e.header
+    }
+    w.Header().Set("Content-Type", "application/json")
+    _ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": request.ID, "result": result})
+}
+
+
+// Encode a uint64 as a JSON-RPC quantity
+func hexUint64(value uint64) string {
+    return "0x" + big.NewInt(0).SetUint64(value).Text(16)
+}
+
+
+func TestResolvePending(t *testing.T) {
+
+    // Sign transactions with nonces 1 to 4
+    key, err := crypto.GenerateKey()
+    if err != nil {
+        t.Fatal(err)
+    }
+    from := crypto.PubkeyToAddress(key.PublicKey)
+    signer := types.NewEIP155Signer(big.NewInt(1))
+    txs := make([]*types.Transaction, 4)
+    for ti := range txs {
+        tx, err := types.SignTx(types.NewTransaction(uint64(ti + 1), common.Address{}, big.NewInt(0), 21000, big.NewInt(1), nil), signer, key)
+        if err != nil {
+            t.Fatal(err)
+        }
+        txs[ti] = tx
+    }
+
+    // Transaction 1 was mined, 2 was replaced, 3 is still in the mempool with a used nonce, and 4 is waiting for its nonce
+    header := &types.Header{Number: big.NewInt(100), Time: 1635768000, Difficulty: big.NewInt(0)}
+    eth := &fakeEthClient{
+        nonce: 4,
+        transactions: map[common.Hash]*types.Transaction{txs[0].Hash(): txs[0], txs[2].Hash(): txs[2], txs[3].Hash(): txs[3]},
+        receipts: map[common.Hash]*types.Receipt{txs[0].Hash(): {
+            Status: types.ReceiptStatusSuccessful,
+            TxHash: txs[0].Hash(),
+            BlockHash: header.Hash(),
+            BlockNumber: big.NewInt(100),
+            GasUsed: 21000,
+            Logs: []*types.Log{},
+        }},
+        header: header,
+    }
+    server := httptest.NewServer(http.HandlerFunc(eth.serve))
+    defer server.Close()
+    ec, err := ethclient.Dial(server.URL)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer ec.Close()
+
+    // Record the transactions as sent
+    entries := make([]Record, len(txs))
+    for ti, tx := range txs {
+        entries[ti] = Record{Time: time.Unix(int64(ti), 0), Origin: "test", Hash: tx.Hash(), From: from, Nonce: tx.Nonce(), Status: StatusPending}
+    }
+    l, cleanup := newTestTxLog(t, entries...)
+    defer cleanup()
+
+    // Resolve
+    if err := l.ResolvePending(ec); err != nil {
+        t.Fatal(err)
+    }
+    records, err := l.GetRecords()
+    if err != nil {
+        t.Fatal(err)
+    }
+    expected := []string{StatusSuccess, StatusDropped, StatusPending, StatusPending}
+    for ri, record := range records {
+        if record.Status != expected[ri] {
+            t.Errorf("transaction with nonce %d is %s, expected %s", record.Nonce, record.Status, expected[ri])
+        }
+    }
+    if records[0].BlockNumber != 100 || records[0].GasUsed != 21000 || records[0].EffectiveGasPrice.Cmp(big.NewInt(1)) != 0 {
+        t.Errorf("mined transaction record is %+v", records[0])
+    }
+
+    // Resolved transactions are not recorded again
+    if err := l.ResolvePending(ec); err != nil {
+        t.Fatal(err)
+    }
+    data, err := ioutil.ReadFile(l.path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if lines := strings.Count(string(data), "\n"); lines != 6 {
+        t.Errorf("log has %d entries, expected 6", lines)
+    }
+
+}
@@ -1,6 +1,7 @@
 package keystore
 
 import (
+	rptypes "github.com/rocket-pool/rocketpool-go/types"
 	"github.com/sethvargo/go-password/password"
 	eth2types "github.com/wealdtech/go-eth2-types/v2"
 )
@@ -21,5 +22,6 @@ func GenerateRandomPassword() (string, error) {
 // Validator keystore interface
 type Keystore interface {
     StoreValidatorKey(key *eth2types.BLSPrivateKey, derivationPath string) error
+    HasValidatorKey(pubkey rptypes.ValidatorPubkey) (bool, error)
 }
 
@@ -114,3 +114,16 @@ func (ks *Keystore) StoreValidatorKey(key *eth2types.BLSPrivateKey, derivationPa
     return nil
 
 }
+
+
+// Check whether a validator key is stored
+func (ks *Keystore) HasValidatorKey(pubkey rptypes.ValidatorPubkey) (bool, error) {
+    _, err := os.Stat(filepath.Join(ks.keystorePath, KeystoreDir, ValidatorsDir, hexutil.AddPrefix(pubkey.Hex())+".json"))
+    if os.IsNotExist(err) {
+        return false, nil
+    }
+    if err != nil {
+        return false, fmt.Errorf("Could not check for validator key: %w", err)
+    }
+    return true, nil
+}
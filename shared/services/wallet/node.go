@@ -10,6 +10,8 @@ import (
 	"github.com/btcsuite/btcutil/hdkeychain"
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
@@ -70,6 +72,22 @@ func (w *Wallet) GetNodeAccountTransactor() (*bind.TransactOpts, error) {
     transactor.GasTipCap = w.maxPriorityFee
     transactor.GasLimit = w.gasLimit
     transactor.Context = context.Background()
+
+    // Hold signed transactions in the transaction log until they are confirmed to have been sent
+    if err == nil && w.txLog != nil {
+        signer := transactor.Signer
+        txLog := w.txLog
+        origin := w.txOrigin
+        transactor.Signer = func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+            signedTx, err := signer(address, tx)
+            if err != nil {
+                return nil, err
+            }
+            txLog.AddSignedTransaction(origin, address, signedTx)
+            return signedTx, nil
+        }
+    }
+
     return transactor, err
 
 }
@@ -47,6 +47,16 @@ func (w *Wallet) GetValidatorKeyAt(index uint) (*eth2types.BLSPrivateKey, error)
 }
 
 
+// Check whether a validator key is stored in a named keystore
+func (w *Wallet) HasValidatorKey(keystoreName string, pubkey rptypes.ValidatorPubkey) (bool, error) {
+    ks, ok := w.keystores[keystoreName]
+    if !ok {
+        return false, fmt.Errorf("Unknown validator keystore '%s'", keystoreName)
+    }
+    return ks.HasValidatorKey(pubkey)
+}
+
+
 // Get a validator key by public key
 func (w *Wallet) GetValidatorKeyByPubkey(pubkey rptypes.ValidatorPubkey) (*eth2types.BLSPrivateKey, error) {
 
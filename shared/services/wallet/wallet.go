@@ -16,6 +16,7 @@ import (
 	eth2ks "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
 
 	"github.com/rocket-pool/smartnode/shared/services/passwords"
+	"github.com/rocket-pool/smartnode/shared/services/txlog"
 	"github.com/rocket-pool/smartnode/shared/services/wallet/keystore"
 )
 
@@ -58,6 +59,10 @@ type Wallet struct {
     maxPriorityFee *big.Int
     gasLimit uint64
 
+    // Transaction log
+    txLog *txlog.TxLog
+    txOrigin string
+
 }
 
 
@@ -118,6 +123,24 @@ func (w *Wallet) AddKeystore(name string, ks keystore.Keystore) {
 }
 
 
+// Set the log which sent transactions are recorded to
+func (w *Wallet) SetTxLog(txLog *txlog.TxLog) {
+    w.txLog = txLog
+}
+
+
+// Get the log which sent transactions are recorded to
+func (w *Wallet) GetTxLog() *txlog.TxLog {
+    return w.txLog
+}
+
+
+// Set the origin (daemon task or command) recorded with sent transactions
+func (w *Wallet) SetTxOrigin(origin string) {
+    w.txOrigin = origin
+}
+
+
 // Check if the wallet has been initialized
 func (w *Wallet) IsInitialized() bool {
     return (w.ws != nil && w.seed != nil && w.mk != nil)
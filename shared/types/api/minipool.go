@@ -35,6 +35,7 @@ type MinipoolDetails struct {
     Delegate common.Address                 `json:"delegate"`
     PreviousDelegate common.Address         `json:"previousDelegate"`
     EffectiveDelegate common.Address        `json:"effectiveDelegate"`
+    DepositCheck *MinipoolDepositCheck      `json:"depositCheck,omitempty"`
 }
 type ValidatorDetails struct {
     Exists bool                     `json:"exists"`
@@ -43,6 +44,16 @@ type ValidatorDetails struct {
     Balance *big.Int                `json:"balance"`
     NodeBalance *big.Int            `json:"nodeBalance"`
 }
+type MinipoolDepositCheck struct {
+    DepositCount int                                `json:"depositCount"`
+    ValidDepositFound bool                          `json:"validDepositFound"`
+    TxHash common.Hash                              `json:"txHash"`
+    BlockNumber uint64                              `json:"blockNumber"`
+    WithdrawalCredentials common.Hash               `json:"withdrawalCredentials"`
+    ExpectedWithdrawalCredentials common.Hash       `json:"expectedWithdrawalCredentials"`
+    WithdrawalCredentialsMatch bool                 `json:"withdrawalCredentialsMatch"`
+    Error string                                    `json:"error,omitempty"`
+}
 
 
 type CanRefundMinipoolResponse struct {
@@ -9,6 +9,8 @@ import (
 	"github.com/rocket-pool/rocketpool-go/rocketpool"
 	"github.com/rocket-pool/rocketpool-go/tokens"
 	rptypes "github.com/rocket-pool/rocketpool-go/types"
+
+	"github.com/rocket-pool/smartnode/shared/services/txlog"
 )
 
 
@@ -250,6 +252,33 @@ type NodeSyncProgressResponse struct {
 }
 
 
+type NodeDoctorResponse struct {
+    Status string                       `json:"status"`
+    Error string                        `json:"error"`
+    ValidatorKeystore string            `json:"validatorKeystore"`
+    ValidatorKeys []ValidatorKeyDetails `json:"validatorKeys"`
+    ClockTime uint64                    `json:"clockTime"`
+    GenesisTime uint64                  `json:"genesisTime"`
+    SecondsPerEpoch uint64              `json:"secondsPerEpoch"`
+    HeadEpoch uint64                    `json:"headEpoch"`
+}
+type ValidatorKeyDetails struct {
+    Pubkey rptypes.ValidatorPubkey      `json:"pubkey"`
+    Stored bool                         `json:"stored"`
+}
+
+
+type NodeValidatorLivenessResponse struct {
+    Status string                       `json:"status"`
+    Error string                        `json:"error"`
+    FromEpoch uint64                    `json:"fromEpoch"`
+    ToEpoch uint64                      `json:"toEpoch"`
+    WindowEpochs uint64                 `json:"windowEpochs"`
+    WaitSeconds uint64                  `json:"waitSeconds"`
+    LiveValidators []rptypes.ValidatorPubkey `json:"liveValidators"`
+}
+
+
 type CanNodeClaimRplResponse struct {
     Status string                       `json:"status"`
     Error string                        `json:"error"`
@@ -292,3 +321,46 @@ type DepositContractInfoResponse struct {
     BeaconNetwork uint64                    `json:"beaconNetwork"`
     SufficientSync bool                     `json:"sufficientSync"`
 }
+
+type NodeTxHistoryResponse struct {
+    Status string                           `json:"status"`
+    Error string                            `json:"error"`
+    AccountAddress common.Address           `json:"accountAddress"`
+    Transactions []NodeTransaction          `json:"transactions"`
+}
+type NodeTransaction struct {
+    txlog.Record
+    GasCost *big.Int                        `json:"gasCost"`
+    RplSent *big.Int                        `json:"rplSent"`
+    RplReceived *big.Int                    `json:"rplReceived"`
+}
+
+type NodeReportResponse struct {
+    Status string                           `json:"status"`
+    Error string                            `json:"error"`
+    AccountAddress common.Address           `json:"accountAddress"`
+    From time.Time                          `json:"from"`
+    To time.Time                            `json:"to"`
+    FromBlock uint64                        `json:"fromBlock"`
+    ToBlock uint64                          `json:"toBlock"`
+    FromEpoch uint64                        `json:"fromEpoch"`
+    ToEpoch uint64                          `json:"toEpoch"`
+    Entries []NodeReportEntry               `json:"entries"`
+    Totals map[string]NodeReportTotal       `json:"totals"`
+}
+type NodeReportEntry struct {
+    Time time.Time                          `json:"time"`
+    Block uint64                            `json:"block"`
+    Type string                             `json:"type"`
+    Minipool *common.Address                `json:"minipool,omitempty"`
+    TxHash *common.Hash                     `json:"txHash,omitempty"`
+    EthAmount *big.Int                      `json:"ethAmount"`
+    RplAmount *big.Int                      `json:"rplAmount"`
+    RplPrice *big.Int                       `json:"rplPrice"`
+    EthValue *big.Int                       `json:"ethValue"`
+}
+type NodeReportTotal struct {
+    EthAmount *big.Int                      `json:"ethAmount"`
+    RplAmount *big.Int                      `json:"rplAmount"`
+    EthValue *big.Int                       `json:"ethValue"`
+}
@@ -7,6 +7,9 @@ import (
 	"github.com/rocket-pool/rocketpool-go/dao"
 	tn "github.com/rocket-pool/rocketpool-go/dao/trustednode"
 	"github.com/rocket-pool/rocketpool-go/rocketpool"
+	"github.com/rocket-pool/rocketpool-go/types"
+
+	"github.com/rocket-pool/smartnode/shared/services/scrubs"
 )
 
 
@@ -105,6 +108,7 @@ type CanProposeTNDAOKickResponse struct {
     CanPropose bool                 `json:"canPropose"`
     ProposalCooldownActive bool     `json:"proposalCooldownActive"`
     InsufficientRplBond bool        `json:"insufficientRplBond"`
+    Simulation TNDAOProposalSimulation `json:"simulation"`
     GasInfo rocketpool.GasInfo      `json:"gasInfo"`
 }
 type ProposeTNDAOKickResponse struct {
@@ -219,86 +223,119 @@ type CanProposeTNDAOSettingResponse struct {
     Error string                    `json:"error"`
     CanPropose bool                 `json:"canPropose"`
     ProposalCooldownActive bool     `json:"proposalCooldownActive"`
+    Simulation TNDAOProposalSimulation `json:"simulation"`
     GasInfo rocketpool.GasInfo      `json:"gasInfo"`
 }
-type ProposeTNDAOSettingMembersQuorumResponse struct {
+type ProposeTNDAOSettingResponse struct {
     Status string                   `json:"status"`
     Error string                    `json:"error"`
     ProposalId uint64               `json:"proposalId"`
     TxHash common.Hash              `json:"txHash"`
 }
-type ProposeTNDAOSettingMembersRplBondResponse struct {
+
+
+type GetTNDAOSettingsResponse struct {
     Status string                   `json:"status"`
     Error string                    `json:"error"`
-    ProposalId uint64               `json:"proposalId"`
-    TxHash common.Hash              `json:"txHash"`
+    Settings []TNDAOSettingValue    `json:"settings"`
 }
-type ProposeTNDAOSettingMinipoolUnbondedMaxResponse struct {
-    Status string                   `json:"status"`
-    Error string                    `json:"error"`
-    ProposalId uint64               `json:"proposalId"`
-    TxHash common.Hash              `json:"txHash"`
+type TNDAOSettingValue struct {
+    Name string                     `json:"name"`
+    Value *big.Int                  `json:"value"`
 }
-type ProposeTNDAOSettingProposalCooldownResponse struct {
+
+
+type SimulateTNDAOProposalResponse struct {
     Status string                   `json:"status"`
     Error string                    `json:"error"`
-    ProposalId uint64               `json:"proposalId"`
-    TxHash common.Hash              `json:"txHash"`
+    Simulation TNDAOProposalSimulation `json:"simulation"`
 }
-type ProposeTNDAOSettingProposalVoteTimespanResponse struct {
-    Status string                   `json:"status"`
-    Error string                    `json:"error"`
-    ProposalId uint64               `json:"proposalId"`
-    TxHash common.Hash              `json:"txHash"`
+type TNDAOProposalSimulation struct {
+    Simulated bool                  `json:"simulated"`
+    Executable bool                 `json:"executable"`
+    RevertReason string             `json:"revertReason"`
+    Changes []TNDAOProposalChange   `json:"changes"`
 }
-type ProposeTNDAOSettingProposalVoteDelayTimespanResponse struct {
-    Status string                   `json:"status"`
-    Error string                    `json:"error"`
-    ProposalId uint64               `json:"proposalId"`
-    TxHash common.Hash              `json:"txHash"`
+type TNDAOProposalChange struct {
+    Item string                     `json:"item"`
+    Before string                   `json:"before"`
+    After string                    `json:"after"`
 }
-type ProposeTNDAOSettingProposalExecuteTimespanResponse struct {
+
+
+type TNDAOChallengesResponse struct {
     Status string                   `json:"status"`
     Error string                    `json:"error"`
-    ProposalId uint64               `json:"proposalId"`
-    TxHash common.Hash              `json:"txHash"`
+    ChallengeWindow uint64          `json:"challengeWindow"`
+    Challenges []TNDAOChallenge     `json:"challenges"`
+}
+type TNDAOChallenge struct {
+    MemberAddress common.Address    `json:"memberAddress"`
+    MemberId string                 `json:"memberId"`
+    ChallengerAddress common.Address `json:"challengerAddress"`
+    ChallengedBlock uint64          `json:"challengedBlock"`
+    DeadlineBlock uint64            `json:"deadlineBlock"`
+    BlocksRemaining uint64          `json:"blocksRemaining"`
+    Expired bool                    `json:"expired"`
 }
-type ProposeTNDAOSettingProposalActionTimespanResponse struct {
+
+
+type CanMakeTNDAOChallengeResponse struct {
     Status string                   `json:"status"`
     Error string                    `json:"error"`
-    ProposalId uint64               `json:"proposalId"`
-    TxHash common.Hash              `json:"txHash"`
+    CanChallenge bool               `json:"canChallenge"`
+    MemberDoesNotExist bool         `json:"memberDoesNotExist"`
+    ChallengingSelf bool            `json:"challengingSelf"`
+    AlreadyChallenged bool          `json:"alreadyChallenged"`
+    ChallengeCooldownActive bool    `json:"challengeCooldownActive"`
+    InsufficientBalance bool        `json:"insufficientBalance"`
+    ChallengeCost *big.Int          `json:"challengeCost"`
+    GasInfo rocketpool.GasInfo      `json:"gasInfo"`
 }
-type ProposeTNDAOSettingScrubPeriodResponse struct {
+type MakeTNDAOChallengeResponse struct {
     Status string                   `json:"status"`
     Error string                    `json:"error"`
-    ProposalId uint64               `json:"proposalId"`
     TxHash common.Hash              `json:"txHash"`
 }
 
 
-type GetTNDAOMemberSettingsResponse struct {
+type TNDAOSubmissionsResponse struct {
     Status string                   `json:"status"`
     Error string                    `json:"error"`
-    Quorum float64                  `json:"quorum"`
-    RPLBond *big.Int                `json:"rplBond"`
-    MinipoolUnbondedMax uint64      `json:"minipoolUnbondedMax"`
-    ChallengeCooldown uint64        `json:"challengeCooldown"`
-    ChallengeWindow uint64          `json:"challengeWindow"`
-    ChallengeCost *big.Int          `json:"challengeCost"`
+    Audit TNDAOSubmissionsAudit     `json:"audit"`
 }
-type GetTNDAOProposalSettingsResponse struct {
-    Status string                   `json:"status"`
-    Error string                    `json:"error"`
-    Cooldown uint64                 `json:"cooldown"`
-    VoteTime uint64                 `json:"voteTime"`
-    VoteDelayTime uint64            `json:"voteDelayTime"`
-    ExecuteTime uint64              `json:"executeTime"`
-    ActionTime uint64               `json:"actionTime"`
+type TNDAOSubmissionsAudit struct {
+    Type string                     `json:"type"`
+    FromBlock uint64                `json:"fromBlock"`
+    ToBlock uint64                  `json:"toBlock"`
+    ValueNames []string             `json:"valueNames"`
+    Rounds []TNDAOSubmissionRound   `json:"rounds"`
+    Members []TNDAOMemberSubmissions `json:"members"`
 }
-type GetTNDAOMinipoolSettingsResponse struct {
+type TNDAOSubmissionRound struct {
+    Block uint64                    `json:"block"`
+    Time uint64                     `json:"time"`
+    Consensus bool                  `json:"consensus"`
+    ConsensusBlock uint64           `json:"consensusBlock"`
+    Values []*big.Int               `json:"values"`
+}
+type TNDAOMemberSubmissions struct {
+    Address common.Address          `json:"address"`
+    Id string                       `json:"id"`
+    Results []string                `json:"results"`
+    OnTime int                      `json:"onTime"`
+    Late int                        `json:"late"`
+    Diverged int                    `json:"diverged"`
+    Missing int                     `json:"missing"`
+    LatestSubmittedBlock uint64     `json:"latestSubmittedBlock"`
+}
+
+type TNDAOScrubReportsResponse struct {
     Status string                   `json:"status"`
     Error string                    `json:"error"`
-    ScrubPeriod uint64              `json:"scrubPeriod"`
+    Reports []TNDAOScrubReport      `json:"reports"`
+}
+type TNDAOScrubReport struct {
+    scrubs.Evidence
+    MinipoolStatus types.MinipoolStatus `json:"minipoolStatus"`
 }
-
@@ -7,9 +7,14 @@ import (
     "reflect"
 
     "github.com/rocket-pool/smartnode/shared/types/api"
+    "github.com/rocket-pool/smartnode/shared/utils/log"
 )
 
 
+// Logger for API errors
+var logger = log.NewLogger("api", 0)
+
+
 // Print an API response
 // response must be a pointer to a struct type with Error and Status string fields
 func PrintResponse(response interface{}, responseError error) {
@@ -45,6 +50,7 @@ func PrintResponse(response interface{}, responseError error) {
         sf.SetString("success")
     } else {
         sf.SetString("error")
+        logger.Error(ef.String())
     }
 
     // Encode
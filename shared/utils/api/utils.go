@@ -12,6 +12,7 @@ import (
 	"github.com/rocket-pool/rocketpool-go/utils"
 	"github.com/rocket-pool/rocketpool-go/utils/eth"
 	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/txlog"
 	"github.com/rocket-pool/smartnode/shared/utils/log"
 	"github.com/rocket-pool/smartnode/shared/utils/math"
 )
@@ -21,19 +22,19 @@ const TimeoutSafetyFactor int = 2
 
 
 // Print the gas price and cost of a TX
-func PrintAndCheckGasInfo(gasInfo rocketpool.GasInfo, checkThreshold bool, gasThresholdGwei float64, logger log.ColorLogger, maxFeeWei *big.Int, gasLimit uint64) (bool) {
+func PrintAndCheckGasInfo(gasInfo rocketpool.GasInfo, checkThreshold bool, gasThresholdGwei float64, logger log.Logger, maxFeeWei *big.Int, gasLimit uint64) (bool) {
 
     // Check the gas threshold if requested
     if checkThreshold {
         gasThresholdWei := math.RoundUp(gasThresholdGwei * eth.WeiPerGwei, 0)
         gasThreshold := new(big.Int).SetUint64(uint64(gasThresholdWei))
         if maxFeeWei.Cmp(gasThreshold) != -1 {
-            logger.Printlnf("Current network gas price is %.2f Gwei, which is higher than the set threshold of %.2f Gwei. " + 
+            logger.Infof("Current network gas price is %.2f Gwei, which is higher than the set threshold of %.2f Gwei. " + 
                 "Aborting the transaction.", eth.WeiToGwei(maxFeeWei), gasThresholdGwei)
             return false
         } 
     } else {
-        logger.Println("This transaction does not check the gas threshold limit, continuing...")
+        logger.Info("This transaction does not check the gas threshold limit, continuing...")
     }
     
     // Print the total TX cost
@@ -48,7 +49,7 @@ func PrintAndCheckGasInfo(gasInfo rocketpool.GasInfo, checkThreshold bool, gasTh
     }
     totalGasWei := new(big.Int).Mul(maxFeeWei, gas)
     totalSafeGasWei := new(big.Int).Mul(maxFeeWei, safeGas)
-    logger.Printlnf("This transaction will use a gas price of %.6f Gwei, for a total of %.6f to %.6f ETH.",
+    logger.Infof("This transaction will use a gas price of %.6f Gwei, for a total of %.6f to %.6f ETH.",
         eth.WeiToGwei(maxFeeWei),
         math.RoundDown(eth.WeiToEth(totalGasWei), 6),
         math.RoundDown(eth.WeiToEth(totalSafeGasWei), 6))
@@ -58,20 +59,37 @@ func PrintAndCheckGasInfo(gasInfo rocketpool.GasInfo, checkThreshold bool, gasTh
 
 
 // Print a TX's details to the logger and waits for it to be mined.
-func PrintAndWaitForTransaction(config config.RocketPoolConfig, hash common.Hash, ec *ethclient.Client, logger log.ColorLogger) (error) {
+// The transaction and its result are recorded to the transaction log if one is set.
+func PrintAndWaitForTransaction(config config.RocketPoolConfig, hash common.Hash, ec *ethclient.Client, txLog *txlog.TxLog, logger log.Logger) (error) {
 
     txWatchUrl := config.Smartnode.TxWatchUrl
     hashString := hash.String()
+    logger = logger.WithTx(hash)
 
-    logger.Printlnf("Transaction has been submitted with hash %s.", hashString)
+    logger.Infof("Transaction has been submitted with hash %s.", hashString)
     if txWatchUrl != "" {
-        logger.Printlnf("You may follow its progress by visiting:")
-        logger.Printlnf("%s/%s\n", txWatchUrl, hashString)
+        logger.Infof("You may follow its progress by visiting:")
+        logger.Infof("%s/%s", txWatchUrl, hashString)
+    }
+    logger.Info("Waiting for the transaction to be mined...")
+
+    // Record the sent transaction in the transaction log
+    if txLog != nil {
+        if err := txLog.RecordSentTransactions(ec); err != nil {
+            logger.Warnf("Could not record sent transaction: %s", err.Error())
+        }
     }
-    logger.Println("Waiting for the transaction to be mined...")
 
     // Wait for the TX to be mined
-    if _, err := utils.WaitForTransaction(ec, hash); err != nil {
+    receipt, err := utils.WaitForTransaction(ec, hash)
+
+    // Record the result in the transaction log
+    if txLog != nil && receipt != nil {
+        if err := txLog.AddReceipt(ec, receipt); err != nil {
+            logger.Warnf("Could not record transaction result: %s", err.Error())
+        }
+    }
+    if err != nil {
         return fmt.Errorf("Error mining transaction: %w", err)
     }
 
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services/rocketpool"
+)
+
+// Get a node's row in an --all-nodes table; called concurrently for each node
+type NodeRowFunc func(rp *rocketpool.Client) ([]string, error)
+
+
+// Run a command against every node profile in parallel and print the results as a single table
+// Nodes which could not be queried are listed with their errors below the table
+func PrintAllNodes(c *cli.Context, columns []string, getRow NodeRowFunc) error {
+
+    // Check options
+    if c.GlobalString("node") != "" || c.GlobalString("host") != "" {
+        return errors.New("The --all-nodes option cannot be used with --node or --host.")
+    }
+
+    // Get node profiles
+    profiles, err := rocketpool.LoadNodeProfilesFromCtx(c)
+    if err != nil { return err }
+    if len(profiles.Nodes) == 0 {
+        return fmt.Errorf("There are no nodes in %s; please add them with 'rocketpool nodes add'.", c.GlobalString("nodes-file"))
+    }
+
+    // Query nodes
+    rows := make([][]string, len(profiles.Nodes))
+    rowErrors := make([]error, len(profiles.Nodes))
+    wg := new(sync.WaitGroup)
+    wg.Add(len(profiles.Nodes))
+    for pi, profile := range profiles.Nodes {
+        go func(pi int, profile rocketpool.NodeProfile) {
+            defer wg.Done()
+            rp, err := rocketpool.NewClientForNode(c, profile)
+            if err != nil {
+                rowErrors[pi] = err
+                return
+            }
+            defer rp.Close()
+            rows[pi], rowErrors[pi] = getRow(rp)
+        }(pi, profile)
+    }
+    wg.Wait()
+
+    // Print table
+    writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+    fmt.Fprintf(writer, "Node\t%s\n", strings.Join(columns, "\t"))
+    failed := 0
+    for pi, profile := range profiles.Nodes {
+        if rowErrors[pi] != nil {
+            failed++
+            fmt.Fprintf(writer, "%s\tERROR\n", profile.Name)
+        } else {
+            fmt.Fprintf(writer, "%s\t%s\n", profile.Name, strings.Join(rows[pi], "\t"))
+        }
+    }
+    if err := writer.Flush(); err != nil {
+        return err
+    }
+
+    // Print errors
+    if failed == 0 {
+        return nil
+    }
+    fmt.Println("")
+    for pi, profile := range profiles.Nodes {
+        if rowErrors[pi] != nil {
+            fmt.Printf("%s%s: %s%s\n", colorRed, profile.Name, rowErrors[pi].Error(), colorReset)
+        }
+    }
+    return fmt.Errorf("%d of %d nodes could not be queried.", failed, len(profiles.Nodes))
+
+}
+
+
+// Get the name of the network a node is on
+func GetNetworkName(rp *rocketpool.Client) (string, error) {
+    cfg, err := rp.LoadGlobalConfig()
+    if err != nil {
+        return "", fmt.Errorf("Error loading global config: %w", err)
+    }
+    switch cfg.Chains.Eth1.ChainID {
+        case "1": return "Mainnet", nil
+        case "5": return "Prater", nil
+    }
+    return fmt.Sprintf("Chain ID %s", cfg.Chains.Eth1.ChainID), nil
+}
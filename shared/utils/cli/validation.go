@@ -7,8 +7,10 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	rptypes "github.com/rocket-pool/rocketpool-go/types"
 	"github.com/tyler-smith/go-bip39"
 	"github.com/urfave/cli"
 
@@ -78,6 +80,23 @@ func ValidateAddress(name, value string) (common.Address, error) {
 }
 
 
+// Validate a comma-separated list of validator pubkeys
+func ValidatePubkeys(name, value string) ([]rptypes.ValidatorPubkey, error) {
+    pubkeys := []rptypes.ValidatorPubkey{}
+    if value == "" {
+        return pubkeys, nil
+    }
+    for _, pubkeyString := range strings.Split(value, ",") {
+        pubkey, err := rptypes.HexToValidatorPubkey(strings.TrimPrefix(pubkeyString, "0x"))
+        if err != nil {
+            return nil, fmt.Errorf("Invalid %s '%s'", name, pubkeyString)
+        }
+        pubkeys = append(pubkeys, pubkey)
+    }
+    return pubkeys, nil
+}
+
+
 // Validate a wei amount
 func ValidateWeiAmount(name, value string) (*big.Int, error) {
     val := new(big.Int)
@@ -138,6 +157,49 @@ func ValidateProposalType(name, value string) (string, error) {
 }
 
 
+// Validate an oracle DAO submission type
+func ValidateSubmissionType(name, value string) (string, error) {
+    val := strings.ToLower(value)
+    if !(val == "balances" || val == "prices") {
+        return "", fmt.Errorf("Invalid %s '%s' - valid types are 'balances' and 'prices'", name, value)
+    }
+    return val, nil
+}
+
+
+// Validate an export format
+func ValidateExportFormat(name, value string) (string, error) {
+    val := strings.ToLower(value)
+    if !(val == "table" || val == "csv" || val == "json") {
+        return "", fmt.Errorf("Invalid %s '%s' - valid formats are 'table', 'csv', and 'json'", name, value)
+    }
+    return val, nil
+}
+
+
+// Validate a command output format
+func ValidateOutputFormat(name, value string) (string, error) {
+    val := strings.ToLower(value)
+    if !(val == "text" || val == "json") {
+        return "", fmt.Errorf("Invalid %s '%s' - valid formats are 'text' and 'json'", name, value)
+    }
+    return val, nil
+}
+
+
+// Validate a date, given as YYYY-MM-DD (UTC) or an RFC3339 time
+func ValidateDate(name, value string) (time.Time, error) {
+    if val, err := time.Parse("2006-01-02", value); err == nil {
+        return val, nil
+    }
+    val, err := time.Parse(time.RFC3339, value)
+    if err != nil {
+        return time.Time{}, fmt.Errorf("Invalid %s '%s' - must be a date in the format YYYY-MM-DD or an RFC3339 time", name, value)
+    }
+    return val, nil
+}
+
+
 //
 // Command specific types
 //
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/rocket-pool/smartnode/shared/services"
 	"github.com/urfave/cli"
 )
@@ -56,3 +58,37 @@ func CheckForNonceOverride(c *cli.Context, opts *bind.TransactOpts) error {
 
 }
 
+
+
+// Get the number of the latest block mined at or before a time
+func GetBlockAtTime(ec *ethclient.Client, t time.Time) (uint64, error) {
+
+    // Get the latest block
+    latestHeader, err := ec.HeaderByNumber(context.Background(), nil)
+    if err != nil {
+        return 0, fmt.Errorf("Could not get latest block: %w", err)
+    }
+    if latestHeader.Time <= uint64(t.Unix()) {
+        return latestHeader.Number.Uint64(), nil
+    }
+
+    // Binary search for the block
+    low := uint64(0)
+    high := latestHeader.Number.Uint64()
+    for low < high {
+        mid := (low + high + 1) / 2
+        header, err := ec.HeaderByNumber(context.Background(), new(big.Int).SetUint64(mid))
+        if err != nil {
+            return 0, fmt.Errorf("Could not get block %d: %w", mid, err)
+        }
+        if header.Time <= uint64(t.Unix()) {
+            low = mid
+        } else {
+            high = mid - 1
+        }
+    }
+
+    // Return
+    return low, nil
+
+}
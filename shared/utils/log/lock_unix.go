@@ -0,0 +1,26 @@
+// +build !windows
+
+package log
+
+import (
+    "fmt"
+    "os"
+    "syscall"
+)
+
+
+// Take an exclusive lock on a lock file, returning a function which releases it
+func lockFile(path string) (func(), error) {
+    file, err := os.OpenFile(path, os.O_CREATE | os.O_RDWR, 0644)
+    if err != nil {
+        return nil, fmt.Errorf("Could not open log lock file %s: %w", path, err)
+    }
+    if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+        _ = file.Close()
+        return nil, fmt.Errorf("Could not lock log lock file %s: %w", path, err)
+    }
+    return func() {
+        _ = syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+        _ = file.Close()
+    }, nil
+}
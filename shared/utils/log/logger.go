@@ -1,50 +1,126 @@
 package log
 
 import (
-    "log"
+    "fmt"
+    "time"
 
+    "github.com/ethereum/go-ethereum/common"
     "github.com/fatih/color"
+    "github.com/rocket-pool/rocketpool-go/types"
 )
 
 
-// Logger with ANSI color output
-type ColorLogger struct {
-    Color color.Attribute
-    sprintFunc func(a ...interface{}) string
-    sprintfFunc func(format string, a ...interface{}) string
+// A structured, levelled logger for a daemon task
+// Each entry is tagged with the task name and any fields added to the logger
+type Logger struct {
+    task string
+    color color.Attribute
+    fields []Field
 }
 
 
-// Create new color logger
-func NewColorLogger(colorAttr color.Attribute) ColorLogger {
-    return ColorLogger{
-        Color: colorAttr,
-        sprintFunc: color.New(colorAttr).SprintFunc(),
-        sprintfFunc: color.New(colorAttr).SprintfFunc(),
+// A field attached to log entries
+type Field struct {
+    Key string
+    Value interface{}
+}
+
+
+// Create new logger for a task; the color is used for text output
+func NewLogger(task string, colorAttr color.Attribute) Logger {
+    return Logger{
+        task: task,
+        color: colorAttr,
+    }
+}
+
+
+// Get a logger which adds a field to each entry
+func (l Logger) With(key string, value interface{}) Logger {
+    fields := make([]Field, len(l.fields), len(l.fields) + 1)
+    copy(fields, l.fields)
+    return Logger{
+        task: l.task,
+        color: l.color,
+        fields: append(fields, Field{Key: key, Value: value}),
     }
 }
 
 
-// Print values
-func (l *ColorLogger) Print(v ...interface{}) {
-    log.Print(l.sprintFunc(v...))
+// Get a logger which adds a minipool address to each entry
+func (l Logger) WithMinipool(address common.Address) Logger {
+    return l.With(MinipoolField, address.Hex())
+}
+
+
+// Get a logger which adds a transaction hash to each entry
+func (l Logger) WithTx(hash common.Hash) Logger {
+    return l.With(TxField, hash.Hex())
+}
+
+
+// Get a logger which adds a validator pubkey to each entry
+func (l Logger) WithPubkey(pubkey types.ValidatorPubkey) Logger {
+    return l.With(PubkeyField, pubkey.Hex())
+}
+
+
+// Log values at debug level
+func (l Logger) Debug(v ...interface{}) {
+    l.log(LevelDebug, fmt.Sprint(v...))
+}
+
+
+// Log a formatted string at debug level
+func (l Logger) Debugf(format string, v ...interface{}) {
+    l.log(LevelDebug, fmt.Sprintf(format, v...))
 }
 
 
-// Print values with a newline
-func (l *ColorLogger) Println(v ...interface{}) {
-    log.Println(l.sprintFunc(v...))
+// Log values at info level
+func (l Logger) Info(v ...interface{}) {
+    l.log(LevelInfo, fmt.Sprint(v...))
 }
 
 
-// Print a formatted string
-func (l *ColorLogger) Printf(format string, v ...interface{}) {
-    log.Print(l.sprintfFunc(format, v...))
+// Log a formatted string at info level
+func (l Logger) Infof(format string, v ...interface{}) {
+    l.log(LevelInfo, fmt.Sprintf(format, v...))
 }
 
 
-// Print a formatted string with a newline
-func (l *ColorLogger) Printlnf(format string, v ...interface{}) {
-    log.Println(l.sprintfFunc(format, v...))
+// Log values at warning level
+func (l Logger) Warn(v ...interface{}) {
+    l.log(LevelWarn, fmt.Sprint(v...))
 }
 
+
+// Log a formatted string at warning level
+func (l Logger) Warnf(format string, v ...interface{}) {
+    l.log(LevelWarn, fmt.Sprintf(format, v...))
+}
+
+
+// Log values at error level
+func (l Logger) Error(v ...interface{}) {
+    l.log(LevelError, fmt.Sprint(v...))
+}
+
+
+// Log a formatted string at error level
+func (l Logger) Errorf(format string, v ...interface{}) {
+    l.log(LevelError, fmt.Sprintf(format, v...))
+}
+
+
+// Write an entry to the configured output
+func (l Logger) log(level Level, message string) {
+    output.write(entry{
+        time: time.Now(),
+        level: level,
+        task: l.task,
+        color: l.color,
+        message: message,
+        fields: l.fields,
+    })
+}
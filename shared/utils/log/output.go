@@ -0,0 +1,301 @@
+package log
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "io"
+    stdlog "log"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/fatih/color"
+)
+
+
+// Log levels
+type Level int
+const (
+    LevelDebug Level = iota
+    LevelInfo
+    LevelWarn
+    LevelError
+)
+
+
+// Log output formats
+const (
+    FormatText = "text"
+    FormatJSON = "json"
+    FormatLogfmt = "logfmt"
+)
+
+
+// Standard entry fields
+const (
+    TimeField = "time"
+    LevelField = "level"
+    TaskField = "task"
+    MessageField = "msg"
+    MinipoolField = "minipool"
+    TxField = "tx"
+    PubkeyField = "pubkey"
+)
+
+
+// Defaults
+const (
+    DefaultMaxSize = 100
+    DefaultMaxBackups = 5
+    FileExtension = ".log"
+    textTimeLayout = "2006/01/02 15:04:05"
+)
+
+
+// Log output options
+// If a path is set, logs are written to <path>/<name>.log and rotated when they reach the max size in MB
+// Each process name has its own file, so the node, watchtower and API can share a log path; processes with the same name
+// (such as concurrent API commands) append to the same file and coordinate its rotation
+type Options struct {
+    Level string
+    Format string
+    Path string
+    MaxSize uint64
+    MaxBackups uint64
+}
+
+
+// A log entry
+type entry struct {
+    time time.Time
+    level Level
+    task string
+    color color.Attribute
+    message string
+    fields []Field
+}
+
+
+// The log output shared by all loggers
+type sink struct {
+    lock sync.Mutex
+    out io.Writer
+    format string
+    level Level
+    colored bool
+}
+var output = &sink{
+    out: os.Stderr,
+    format: FormatText,
+    level: LevelInfo,
+    colored: true,
+}
+
+
+// Configure the log output for a process
+// Output from the standard log package is also written as info level entries
+func Configure(name string, options Options) error {
+
+    // Parse options
+    level := LevelInfo
+    if options.Level != "" {
+        var err error
+        if level, err = ParseLevel(options.Level); err != nil {
+            return err
+        }
+    }
+    format := FormatText
+    if options.Format != "" {
+        if !IsValidFormat(options.Format) {
+            return fmt.Errorf("Invalid log format '%s' - valid formats are '%s', '%s' and '%s'", options.Format, FormatText, FormatJSON, FormatLogfmt)
+        }
+        format = options.Format
+    }
+
+    // Get output writer
+    var out io.Writer = os.Stderr
+    colored := true
+    if options.Path != "" {
+        maxSize := options.MaxSize
+        if maxSize == 0 {
+            maxSize = DefaultMaxSize
+        }
+        maxBackups := options.MaxBackups
+        if maxBackups == 0 {
+            maxBackups = DefaultMaxBackups
+        }
+        file, err := newRotatingFile(filepath.Join(options.Path, name + FileExtension), maxSize * 1024 * 1024, maxBackups)
+        if err != nil {
+            return err
+        }
+        out = file
+        colored = false
+    }
+
+    // Update output
+    output.lock.Lock()
+    output.out = out
+    output.format = format
+    output.level = level
+    output.colored = colored
+    output.lock.Unlock()
+
+    // Redirect the standard logger
+    stdlog.SetFlags(0)
+    stdlog.SetOutput(stdWriter{})
+    return nil
+
+}
+
+
+// Parse a log level name
+func ParseLevel(value string) (Level, error) {
+    switch strings.ToLower(value) {
+        case "debug": return LevelDebug, nil
+        case "info": return LevelInfo, nil
+        case "warn", "warning": return LevelWarn, nil
+        case "error": return LevelError, nil
+    }
+    return LevelInfo, fmt.Errorf("Invalid log level '%s' - valid levels are 'debug', 'info', 'warn' and 'error'", value)
+}
+
+
+// Check whether a log format is valid
+func IsValidFormat(value string) bool {
+    return value == FormatText || value == FormatJSON || value == FormatLogfmt
+}
+
+
+// Get the name of a log level
+func (l Level) String() string {
+    switch l {
+        case LevelDebug: return "debug"
+        case LevelWarn: return "warn"
+        case LevelError: return "error"
+    }
+    return "info"
+}
+
+
+// Write an entry in the configured format
+func (s *sink) write(e entry) {
+    s.lock.Lock()
+    defer s.lock.Unlock()
+    if e.level < s.level {
+        return
+    }
+    var line []byte
+    switch s.format {
+        case FormatJSON: line = formatJSON(e)
+        case FormatLogfmt: line = formatLogfmt(e)
+        default: line = formatText(e, s.colored)
+    }
+    _, _ = s.out.Write(line)
+}
+
+
+// Format an entry as text, coloring the message by task or level
+func formatText(e entry, colored bool) []byte {
+    var buf bytes.Buffer
+    buf.WriteString(e.time.Format(textTimeLayout))
+    buf.WriteString(" ")
+    if e.level >= LevelWarn {
+        buf.WriteString(strings.ToUpper(e.level.String()))
+        buf.WriteString(": ")
+    }
+    message := e.message
+    if colored {
+        colorAttr := e.color
+        if e.level == LevelError {
+            colorAttr = color.FgRed
+        }
+        if colorAttr != 0 {
+            message = color.New(colorAttr).Sprint(message)
+        }
+    }
+    buf.WriteString(message)
+    for _, field := range e.fields {
+        buf.WriteString(fmt.Sprintf(" %s=%v", field.Key, field.Value))
+    }
+    buf.WriteString("\n")
+    return buf.Bytes()
+}
+
+
+// Format an entry as a JSON object
+func formatJSON(e entry) []byte {
+    var buf bytes.Buffer
+    buf.WriteString("{")
+    writeJSONField(&buf, TimeField, e.time.Format(time.RFC3339Nano), true)
+    writeJSONField(&buf, LevelField, e.level.String(), false)
+    if e.task != "" {
+        writeJSONField(&buf, TaskField, e.task, false)
+    }
+    writeJSONField(&buf, MessageField, e.message, false)
+    for _, field := range e.fields {
+        writeJSONField(&buf, field.Key, field.Value, false)
+    }
+    buf.WriteString("}\n")
+    return buf.Bytes()
+}
+func writeJSONField(buf *bytes.Buffer, key string, value interface{}, first bool) {
+    if !first {
+        buf.WriteString(",")
+    }
+    keyBytes, _ := json.Marshal(key)
+    valueBytes, err := json.Marshal(value)
+    if err != nil {
+        valueBytes, _ = json.Marshal(fmt.Sprint(value))
+    }
+    buf.Write(keyBytes)
+    buf.WriteString(":")
+    buf.Write(valueBytes)
+}
+
+
+// Format an entry as logfmt key=value pairs
+func formatLogfmt(e entry) []byte {
+    var buf bytes.Buffer
+    writeLogfmtField(&buf, TimeField, e.time.Format(time.RFC3339Nano), true)
+    writeLogfmtField(&buf, LevelField, e.level.String(), false)
+    if e.task != "" {
+        writeLogfmtField(&buf, TaskField, e.task, false)
+    }
+    writeLogfmtField(&buf, MessageField, e.message, false)
+    for _, field := range e.fields {
+        writeLogfmtField(&buf, field.Key, field.Value, false)
+    }
+    buf.WriteString("\n")
+    return buf.Bytes()
+}
+func writeLogfmtField(buf *bytes.Buffer, key string, value interface{}, first bool) {
+    if !first {
+        buf.WriteString(" ")
+    }
+    valueString := fmt.Sprint(value)
+    if valueString == "" || strings.ContainsAny(valueString, " =\"\\\t\r\n") {
+        valueString = strconv.Quote(valueString)
+    }
+    buf.WriteString(key)
+    buf.WriteString("=")
+    buf.WriteString(valueString)
+}
+
+
+// A writer which logs output from the standard log package as info level entries
+type stdWriter struct {}
+func (w stdWriter) Write(p []byte) (int, error) {
+    message := strings.TrimRight(string(p), "\n")
+    if message != "" {
+        output.write(entry{
+            time: time.Now(),
+            level: LevelInfo,
+            message: message,
+        })
+    }
+    return len(p), nil
+}
@@ -0,0 +1,125 @@
+package log
+
+import (
+    "fmt"
+    "os"
+    "path/filepath"
+    "sync"
+)
+
+
+// A log file which is rotated when it reaches a maximum size
+// Rotated files are renamed to <path>.1, <path>.2 etc, with the oldest removed once the max backup count is reached
+// At least one backup is always kept, so rotation never discards the entries just written
+// Several processes may append to the same file (e.g. concurrent API commands); rotation is serialized between them with a
+// lock file at <path>.lock, and a process whose file was rotated by another reopens it instead of rotating again
+type rotatingFile struct {
+    lock sync.Mutex
+    path string
+    maxSize uint64
+    maxBackups uint64
+    file *os.File
+}
+
+
+// Open a rotating log file
+func newRotatingFile(path string, maxSize, maxBackups uint64) (*rotatingFile, error) {
+    if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+        return nil, fmt.Errorf("Could not create log folder %s: %w", filepath.Dir(path), err)
+    }
+    if maxBackups == 0 {
+        maxBackups = 1
+    }
+    f := &rotatingFile{
+        path: path,
+        maxSize: maxSize,
+        maxBackups: maxBackups,
+    }
+    if err := f.open(); err != nil {
+        return nil, err
+    }
+    return f, nil
+}
+
+
+// Write to the log file, rotating it first if the write would exceed the max size
+// The size is read from the file, as other processes may also be writing to it
+func (f *rotatingFile) Write(p []byte) (int, error) {
+    f.lock.Lock()
+    defer f.lock.Unlock()
+    info, err := f.file.Stat()
+    if err != nil {
+        return 0, fmt.Errorf("Could not get log file %s info: %w", f.path, err)
+    }
+    if info.Size() > 0 && uint64(info.Size()) + uint64(len(p)) > f.maxSize {
+        if err := f.rotate(); err != nil {
+            return 0, err
+        }
+    }
+    return f.file.Write(p)
+}
+
+
+// Open the log file for appending
+func (f *rotatingFile) open() error {
+    file, err := os.OpenFile(f.path, os.O_CREATE | os.O_WRONLY | os.O_APPEND, 0644)
+    if err != nil {
+        return fmt.Errorf("Could not open log file %s: %w", f.path, err)
+    }
+    f.file = file
+    return nil
+}
+
+
+// Check whether the open log file has been rotated by another process
+func (f *rotatingFile) isRotated() (bool, error) {
+    openInfo, err := f.file.Stat()
+    if err != nil {
+        return false, fmt.Errorf("Could not get log file %s info: %w", f.path, err)
+    }
+    pathInfo, err := os.Stat(f.path)
+    if os.IsNotExist(err) {
+        return true, nil
+    }
+    if err != nil {
+        return false, fmt.Errorf("Could not get log file %s info: %w", f.path, err)
+    }
+    return !os.SameFile(openInfo, pathInfo), nil
+}
+
+
+// Rotate the log file and open a new one
+func (f *rotatingFile) rotate() error {
+
+    // Lock rotation against other processes
+    unlock, err := lockFile(f.path + ".lock")
+    if err != nil {
+        return err
+    }
+    defer unlock()
+
+    // Reopen the log file if it has already been rotated
+    rotated, err := f.isRotated()
+    if err != nil {
+        return err
+    }
+    if err := f.file.Close(); err != nil {
+        return fmt.Errorf("Could not close log file %s: %w", f.path, err)
+    }
+    if rotated {
+        return f.open()
+    }
+
+    // Rotate backups
+    for i := f.maxBackups - 1; i > 0; i-- {
+        from := fmt.Sprintf("%s.%d", f.path, i)
+        if err := os.Rename(from, fmt.Sprintf("%s.%d", f.path, i + 1)); err != nil && !os.IsNotExist(err) {
+            return fmt.Errorf("Could not rotate log file %s: %w", from, err)
+        }
+    }
+    if err := os.Rename(f.path, f.path + ".1"); err != nil && !os.IsNotExist(err) {
+        return fmt.Errorf("Could not rotate log file %s: %w", f.path, err)
+    }
+    return f.open()
+
+}
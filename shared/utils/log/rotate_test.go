@@ -0,0 +1,141 @@
+package log
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+
+// Read the log file and its backups
+func readLogFiles(t *testing.T, dir string) map[string]string {
+    files, err := ioutil.ReadDir(dir)
+    if err != nil {
+        t.Fatal(err)
+    }
+    contents := map[string]string{}
+    for _, file := range files {
+        if filepath.Ext(file.Name()) == ".lock" { continue }
+        data, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+        if err != nil {
+            t.Fatal(err)
+        }
+        contents[file.Name()] = string(data)
+    }
+    return contents
+}
+
+
+func TestRotatingFile(t *testing.T) {
+    tests := []struct {
+        name string
+        existing string
+        maxBackups uint64
+        writes []string
+        expected map[string]string
+    }{
+        {
+            name: "within max size",
+            maxBackups: 2,
+            writes: []string{"aaaa", "bbbb"},
+            expected: map[string]string{"test.log": "aaaabbbb"},
+        },
+        {
+            name: "rotates at max size",
+            maxBackups: 2,
+            writes: []string{"aaaa", "bbbb", "cccc", "dddd", "eeee"},
+            expected: map[string]string{"test.log": "eeee", "test.log.1": "ccccdddd", "test.log.2": "aaaabbbb"},
+        },
+        {
+            name: "removes the oldest backup",
+            maxBackups: 1,
+            writes: []string{"aaaa", "bbbb", "cccc", "dddd", "eeee"},
+            expected: map[string]string{"test.log": "eeee", "test.log.1": "ccccdddd"},
+        },
+        {
+            name: "keeps a backup with no max backups",
+            maxBackups: 0,
+            writes: []string{"aaaa", "bbbb", "cccc"},
+            expected: map[string]string{"test.log": "cccc", "test.log.1": "aaaabbbb"},
+        },
+        {
+            name: "appends to an existing file",
+            existing: "zzzzzz",
+            maxBackups: 2,
+            writes: []string{"aa", "bbbb"},
+            expected: map[string]string{"test.log": "bbbb", "test.log.1": "zzzzzzaa"},
+        },
+        {
+            name: "oversized write",
+            maxBackups: 2,
+            writes: []string{"aaaaaaaaaaaa", "bb"},
+            expected: map[string]string{"test.log": "bb", "test.log.1": "aaaaaaaaaaaa"},
+        },
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            dir, err := ioutil.TempDir("", "log-rotate")
+            if err != nil { t.Fatal(err) }
+            defer os.RemoveAll(dir)
+            path := filepath.Join(dir, "test.log")
+            if test.existing != "" {
+                if err := ioutil.WriteFile(path, []byte(test.existing), 0644); err != nil { t.Fatal(err) }
+            }
+
+            // Write
+            f, err := newRotatingFile(path, 8, test.maxBackups)
+            if err != nil {
+                t.Fatal(err)
+            }
+            for _, write := range test.writes {
+                if _, err := f.Write([]byte(write)); err != nil {
+                    t.Fatal(err)
+                }
+            }
+            _ = f.file.Close()
+
+            // Check files
+            if contents := readLogFiles(t, dir); !reflect.DeepEqual(contents, test.expected) {
+                t.Errorf("log files are %q, expected %q", contents, test.expected)
+            }
+        })
+    }
+}
+
+
+func TestRotatingFileSharedByProcesses(t *testing.T) {
+    dir, err := ioutil.TempDir("", "log-rotate")
+    if err != nil { t.Fatal(err) }
+    defer os.RemoveAll(dir)
+    path := filepath.Join(dir, "api.log")
+
+    // Open the file from two writers, as two processes would
+    first, err := newRotatingFile(path, 8, 2)
+    if err != nil { t.Fatal(err) }
+    defer first.file.Close()
+    second, err := newRotatingFile(path, 8, 2)
+    if err != nil { t.Fatal(err) }
+    defer second.file.Close()
+
+    // Both writers see the file reach the max size, but it is only rotated once
+    writes := []struct {
+        writer *rotatingFile
+        data string
+    }{
+        {first, "aaaa"},
+        {second, "bbbb"},
+        {first, "cccc"},
+        {second, "dddd"},
+    }
+    for _, write := range writes {
+        if _, err := write.writer.Write([]byte(write.data)); err != nil {
+            t.Fatal(err)
+        }
+    }
+    expected := map[string]string{"api.log": "ccccdddd", "api.log.1": "aaaabbbb"}
+    if contents := readLogFiles(t, dir); !reflect.DeepEqual(contents, expected) {
+        t.Errorf("log files are %q, expected %q", contents, expected)
+    }
+}
@@ -2,49 +2,133 @@ package rp
 
 import (
     "bytes"
+    "math/big"
 
     "github.com/ethereum/go-ethereum/accounts/abi/bind"
     "github.com/ethereum/go-ethereum/common"
     "github.com/rocket-pool/rocketpool-go/minipool"
     "github.com/rocket-pool/rocketpool-go/rocketpool"
     "github.com/rocket-pool/rocketpool-go/types"
-    "golang.org/x/sync/errgroup"
 
     "github.com/rocket-pool/smartnode/shared/services/beacon"
+    "github.com/rocket-pool/smartnode/shared/services/multicall"
 )
 
 
-// Settings
-const MinipoolPubkeyBatchSize = 50
+// Get all minipool addresses
+func GetMinipoolAddresses(rp *rocketpool.RocketPool, mc *multicall.MultiCaller, opts *bind.CallOpts) ([]common.Address, error) {
 
+    // Get minipool count
+    minipoolCount, err := minipool.GetMinipoolCount(rp, opts)
+    if err != nil {
+        return []common.Address{}, err
+    }
 
-// Get minipool validator statuses
-func GetMinipoolValidators(rp *rocketpool.RocketPool, bc beacon.Client, addresses []common.Address, callOpts *bind.CallOpts, validatorStatusOpts *beacon.ValidatorStatusOptions) (map[common.Address]beacon.ValidatorStatus, error) {
+    // Get minipool manager contract
+    rocketMinipoolManager, err := rp.GetContract("rocketMinipoolManager")
+    if err != nil {
+        return []common.Address{}, err
+    }
 
-    // Load minipool validator pubkeys in batches
-    pubkeys := make([]types.ValidatorPubkey, len(addresses))
-    for bsi := 0; bsi < len(addresses); bsi += MinipoolPubkeyBatchSize {
-
-        // Get batch start & end index
-        msi := bsi
-        mei := bsi + MinipoolPubkeyBatchSize
-        if mei > len(addresses) { mei = len(addresses) }
-
-        // Load details
-        var wg errgroup.Group
-        for mi := msi; mi < mei; mi++ {
-            mi := mi
-            wg.Go(func() error {
-                address := addresses[mi]
-                pubkey, err := minipool.GetMinipoolPubkey(rp, address, callOpts)
-                if err == nil { pubkeys[mi] = pubkey }
-                return err
-            })
+    // Load minipool addresses
+    addresses := make([]common.Address, minipoolCount)
+    batch := mc.NewBatch()
+    for mi := uint64(0); mi < minipoolCount; mi++ {
+        if err := batch.AddCall(rocketMinipoolManager, &addresses[mi], "getMinipoolAt", new(big.Int).SetUint64(mi)); err != nil {
+            return []common.Address{}, err
         }
-        if err := wg.Wait(); err != nil {
-            return map[common.Address]beacon.ValidatorStatus{}, err
+    }
+    if err := batch.Execute(opts); err != nil {
+        return []common.Address{}, err
+    }
+
+    // Return
+    return addresses, nil
+
+}
+
+
+// Get a node's minipool addresses
+func GetNodeMinipoolAddresses(rp *rocketpool.RocketPool, mc *multicall.MultiCaller, nodeAddress common.Address, opts *bind.CallOpts) ([]common.Address, error) {
+
+    // Get minipool count
+    minipoolCount, err := minipool.GetNodeMinipoolCount(rp, nodeAddress, opts)
+    if err != nil {
+        return []common.Address{}, err
+    }
+
+    // Get minipool manager contract
+    rocketMinipoolManager, err := rp.GetContract("rocketMinipoolManager")
+    if err != nil {
+        return []common.Address{}, err
+    }
+
+    // Load minipool addresses
+    addresses := make([]common.Address, minipoolCount)
+    batch := mc.NewBatch()
+    for mi := uint64(0); mi < minipoolCount; mi++ {
+        if err := batch.AddCall(rocketMinipoolManager, &addresses[mi], "getNodeMinipoolAt", nodeAddress, new(big.Int).SetUint64(mi)); err != nil {
+            return []common.Address{}, err
         }
+    }
+    if err := batch.Execute(opts); err != nil {
+        return []common.Address{}, err
+    }
 
+    // Return
+    return addresses, nil
+
+}
+
+
+// Get minipool contracts
+func GetMinipools(rp *rocketpool.RocketPool, addresses []common.Address) ([]*minipool.Minipool, error) {
+    minipools := make([]*minipool.Minipool, len(addresses))
+    for mi, address := range addresses {
+        mp, err := minipool.NewMinipool(rp, address)
+        if err != nil {
+            return []*minipool.Minipool{}, err
+        }
+        minipools[mi] = mp
+    }
+    return minipools, nil
+}
+
+
+// Get minipool validator pubkeys
+func GetMinipoolPubkeys(rp *rocketpool.RocketPool, mc *multicall.MultiCaller, addresses []common.Address, opts *bind.CallOpts) ([]types.ValidatorPubkey, error) {
+
+    // Get minipool manager contract
+    rocketMinipoolManager, err := rp.GetContract("rocketMinipoolManager")
+    if err != nil {
+        return []types.ValidatorPubkey{}, err
+    }
+
+    // Load pubkeys
+    pubkeys := make([]types.ValidatorPubkey, len(addresses))
+    batch := mc.NewBatch()
+    for mi, address := range addresses {
+        if err := batch.AddCall(rocketMinipoolManager, &pubkeys[mi], "getMinipoolPubkey", address); err != nil {
+            return []types.ValidatorPubkey{}, err
+        }
+    }
+    if err := batch.Execute(opts); err != nil {
+        return []types.ValidatorPubkey{}, err
+    }
+
+    // Return
+    return pubkeys, nil
+
+}
+
+
+// Get minipool validator statuses
+func GetMinipoolValidators(rp *rocketpool.RocketPool, mc *multicall.MultiCaller, bc beacon.Client, addresses []common.Address, callOpts *bind.CallOpts, validatorStatusOpts *beacon.ValidatorStatusOptions) (map[common.Address]beacon.ValidatorStatus, error) {
+
+    // Load minipool validator pubkeys
+    pubkeys, err := GetMinipoolPubkeys(rp, mc, addresses, callOpts)
+    if err != nil {
+        return map[common.Address]beacon.ValidatorStatus{}, err
     }
 
     // Filter out null and duplicate pubkeys
@@ -0,0 +1,110 @@
+package rp
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rocket-pool/rocketpool-go/dao/trustednode"
+	"github.com/rocket-pool/rocketpool-go/rocketpool"
+	tnsettings "github.com/rocket-pool/rocketpool-go/settings/trustednode"
+
+	"github.com/rocket-pool/smartnode/shared/services/multicall"
+	"github.com/rocket-pool/smartnode/shared/types/api"
+)
+
+
+// Get the active challenges against oracle DAO members
+// A challenged member must respond within the challenge window (in blocks); once it has passed, anyone can decide the challenge and remove the member
+func GetTNDAOChallenges(rp *rocketpool.RocketPool, mc *multicall.MultiCaller, opts *bind.CallOpts) ([]api.TNDAOChallenge, uint64, error) {
+
+    // Get call context
+    ctx := context.Background()
+    if opts != nil && opts.Context != nil {
+        ctx = opts.Context
+    }
+    var blockNumber *big.Int
+    if opts != nil {
+        blockNumber = opts.BlockNumber
+    }
+
+    // Get member addresses & challenge window
+    memberAddresses, err := trustednode.GetMemberAddresses(rp, opts)
+    if err != nil {
+        return []api.TNDAOChallenge{}, 0, err
+    }
+    challengeWindow, err := tnsettings.GetChallengeWindow(rp, opts)
+    if err != nil {
+        return []api.TNDAOChallenge{}, 0, err
+    }
+
+    // Get member challenge states
+    rocketDAONodeTrusted, err := rp.GetContract("rocketDAONodeTrusted")
+    if err != nil {
+        return []api.TNDAOChallenge{}, 0, err
+    }
+    isChallenged := make([]bool, len(memberAddresses))
+    memberIds := make([]string, len(memberAddresses))
+    challengedBlocks := make([]*big.Int, len(memberAddresses))
+    challengerAddresses := make([]common.Address, len(memberAddresses))
+    batch := mc.NewBatch()
+    for mi, memberAddress := range memberAddresses {
+        if err := batch.AddCall(rocketDAONodeTrusted, &isChallenged[mi], "getMemberIsChallenged", memberAddress); err != nil {
+            return []api.TNDAOChallenge{}, 0, err
+        }
+        if err := batch.AddCall(rocketDAONodeTrusted, &memberIds[mi], "getMemberID", memberAddress); err != nil {
+            return []api.TNDAOChallenge{}, 0, err
+        }
+        if err := batch.AddCall(rp.RocketStorageContract, &challengedBlocks[mi], "getUint", crypto.Keccak256Hash([]byte("dao.trustednodes.member.challenged.block"), memberAddress.Bytes())); err != nil {
+            return []api.TNDAOChallenge{}, 0, err
+        }
+        if err := batch.AddCall(rp.RocketStorageContract, &challengerAddresses[mi], "getAddress", crypto.Keccak256Hash([]byte("dao.trustednodes.member.challenged.by"), memberAddress.Bytes())); err != nil {
+            return []api.TNDAOChallenge{}, 0, err
+        }
+    }
+    if err := batch.Execute(opts); err != nil {
+        return []api.TNDAOChallenge{}, 0, fmt.Errorf("Could not get oracle DAO member challenge states: %w", err)
+    }
+
+    // Get the current block
+    header, err := rp.Client.HeaderByNumber(ctx, blockNumber)
+    if err != nil {
+        return []api.TNDAOChallenge{}, 0, fmt.Errorf("Could not get the current block header: %w", err)
+    }
+
+    // Build challenges
+    challenges := []api.TNDAOChallenge{}
+    for mi, memberAddress := range memberAddresses {
+        if !isChallenged[mi] {
+            continue
+        }
+        challengedBlock := challengedBlocks[mi].Uint64()
+        deadlineBlock, blocksRemaining := getTNDAOChallengeDeadline(challengedBlock, challengeWindow, header.Number.Uint64())
+        challenges = append(challenges, api.TNDAOChallenge{
+            MemberAddress: memberAddress,
+            MemberId: memberIds[mi],
+            ChallengerAddress: challengerAddresses[mi],
+            ChallengedBlock: challengedBlock,
+            DeadlineBlock: deadlineBlock,
+            BlocksRemaining: blocksRemaining,
+            Expired: (blocksRemaining == 0),
+        })
+    }
+
+    // Return
+    return challenges, challengeWindow, nil
+
+}
+
+
+// Get the block a challenge must be responded to by, and the blocks remaining until it at the current block
+func getTNDAOChallengeDeadline(challengedBlock, challengeWindow, currentBlock uint64) (uint64, uint64) {
+    deadlineBlock := challengedBlock + challengeWindow
+    if deadlineBlock > currentBlock {
+        return deadlineBlock, deadlineBlock - currentBlock
+    }
+    return deadlineBlock, 0
+}
@@ -0,0 +1,27 @@
+package rp
+
+import (
+	"testing"
+)
+
+
+func TestGetTNDAOChallengeDeadline(t *testing.T) {
+    tests := []struct {
+        name string
+        currentBlock uint64
+        blocksRemaining uint64
+    }{
+        {"challenged", 1000, 100},
+        {"in window", 1099, 1},
+        {"at deadline", 1100, 0},
+        {"past deadline", 5000, 0},
+    }
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            deadlineBlock, blocksRemaining := getTNDAOChallengeDeadline(1000, 100, test.currentBlock)
+            if deadlineBlock != 1100 || blocksRemaining != test.blocksRemaining {
+                t.Errorf("deadline is block %d with %d blocks remaining, expected block 1100 with %d", deadlineBlock, blocksRemaining, test.blocksRemaining)
+            }
+        })
+    }
+}
@@ -0,0 +1,121 @@
+package rp
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/rocketpool-go/rocketpool"
+)
+
+// Oracle DAO proposal action types
+const (
+    TNDAOProposalTypeInvite = "invite"
+    TNDAOProposalTypeLeave = "leave"
+    TNDAOProposalTypeReplace = "replace"
+    TNDAOProposalTypeKick = "kick"
+    TNDAOProposalTypeSetting = "setting"
+    TNDAOProposalTypeUpgrade = "upgrade"
+)
+
+
+// The action an oracle DAO proposal will perform if executed, decoded from its payload
+type TNDAOProposalAction struct {
+    Type string
+    Member *common.Address
+    NewMember *common.Address
+    MemberId string
+    MemberUrl string
+    RplFine *big.Int
+    SettingContractName string
+    SettingName string
+    SettingValue *big.Int
+    UpgradeType string
+    UpgradeContractName string
+    UpgradeContractAddress *common.Address
+}
+
+
+// Decode an oracle DAO proposal payload
+func DecodeTNDAOProposalPayload(rp *rocketpool.RocketPool, payload []byte) (TNDAOProposalAction, error) {
+
+    // Get proposals contract
+    rocketDAONodeTrustedProposals, err := rp.GetContract("rocketDAONodeTrustedProposals")
+    if err != nil {
+        return TNDAOProposalAction{}, err
+    }
+
+    // Get payload method & arguments
+    if len(payload) < 4 {
+        return TNDAOProposalAction{}, fmt.Errorf("Invalid proposal payload: too short")
+    }
+    method, err := rocketDAONodeTrustedProposals.ABI.MethodById(payload[:4])
+    if err != nil {
+        return TNDAOProposalAction{}, fmt.Errorf("Could not get proposal payload method: %w", err)
+    }
+    args, err := method.Inputs.Unpack(payload[4:])
+    if err != nil {
+        return TNDAOProposalAction{}, fmt.Errorf("Could not decode proposal payload %s arguments: %w", method.Name, err)
+    }
+
+    // Decode action
+    var action TNDAOProposalAction
+    var success bool
+    switch method.Name {
+        case "proposalInvite":
+            action.Type = TNDAOProposalTypeInvite
+            action.MemberId, success = args[0].(string)
+            if success { action.MemberUrl, success = args[1].(string) }
+            if success { action.Member, success = getAddressArg(args[2]) }
+        case "proposalLeave":
+            action.Type = TNDAOProposalTypeLeave
+            action.Member, success = getAddressArg(args[0])
+        case "proposalReplace":
+            action.Type = TNDAOProposalTypeReplace
+            action.Member, success = getAddressArg(args[0])
+            if success { action.MemberId, success = args[1].(string) }
+            if success { action.MemberUrl, success = args[2].(string) }
+            if success { action.NewMember, success = getAddressArg(args[3]) }
+        case "proposalKick":
+            action.Type = TNDAOProposalTypeKick
+            action.Member, success = getAddressArg(args[0])
+            if success { action.RplFine, success = args[1].(*big.Int) }
+        case "proposalSettingUint":
+            action.Type = TNDAOProposalTypeSetting
+            action.SettingContractName, success = args[0].(string)
+            if success { action.SettingName, success = args[1].(string) }
+            if success { action.SettingValue, success = args[2].(*big.Int) }
+        case "proposalSettingBool":
+            action.Type = TNDAOProposalTypeSetting
+            action.SettingContractName, success = args[0].(string)
+            if success { action.SettingName, success = args[1].(string) }
+            var value bool
+            if success { value, success = args[2].(bool) }
+            action.SettingValue = big.NewInt(0)
+            if value { action.SettingValue.SetUint64(1) }
+        case "proposalUpgrade":
+            action.Type = TNDAOProposalTypeUpgrade
+            action.UpgradeType, success = args[0].(string)
+            if success { action.UpgradeContractName, success = args[1].(string) }
+            if success { action.UpgradeContractAddress, success = getAddressArg(args[3]) }
+        default:
+            return TNDAOProposalAction{}, fmt.Errorf("Unknown proposal payload method %s", method.Name)
+    }
+    if !success {
+        return TNDAOProposalAction{}, fmt.Errorf("Could not decode proposal payload %s arguments", method.Name)
+    }
+
+    // Return
+    return action, nil
+
+}
+
+
+// Get an address from a decoded ABI argument
+func getAddressArg(arg interface{}) (*common.Address, bool) {
+    address, success := arg.(common.Address)
+    if !success {
+        return nil, false
+    }
+    return &address, true
+}
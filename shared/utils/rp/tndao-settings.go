@@ -0,0 +1,327 @@
+package rp
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/rocket-pool/rocketpool-go/rocketpool"
+	"github.com/rocket-pool/rocketpool-go/settings/trustednode"
+	"github.com/rocket-pool/rocketpool-go/utils/eth"
+
+	"github.com/rocket-pool/smartnode/shared/services/multicall"
+)
+
+
+// Oracle DAO setting value types
+type TNDAOSettingType string
+const (
+    TNDAOSettingTypeCount TNDAOSettingType = "count"
+    TNDAOSettingTypeFraction TNDAOSettingType = "fraction"
+    TNDAOSettingTypeAmount TNDAOSettingType = "amount"
+    TNDAOSettingTypeDuration TNDAOSettingType = "duration"
+    TNDAOSettingTypeBool TNDAOSettingType = "bool"
+)
+
+
+// An oracle DAO setting
+// Aliases include the single-letter names of the original propose setting subcommands
+// Values are handled in their raw on-chain form: fractions and amounts in wei, durations in seconds, and bools as 0 or 1
+// Counts may have units (e.g. blocks) which are shown with their values
+type TNDAOSetting struct {
+    Name string
+    Aliases []string
+    ContractName string
+    Type TNDAOSettingType
+    Units string
+    Min *big.Int
+    Max *big.Int
+    Description string
+}
+
+
+// The oracle DAO settings registry
+var tndaoSettings = []TNDAOSetting{
+    {
+        Name: trustednode.QuorumSettingPath,
+        Aliases: []string{"members-quorum", "q"},
+        ContractName: trustednode.MembersSettingsContractName,
+        Type: TNDAOSettingTypeFraction,
+        Min: big.NewInt(0),
+        Max: eth.EthToWei(1),
+        Description: "The member vote quorum threshold required to pass a proposal",
+    },
+    {
+        Name: trustednode.RPLBondSettingPath,
+        Aliases: []string{"members-rplbond", "b"},
+        ContractName: trustednode.MembersSettingsContractName,
+        Type: TNDAOSettingTypeAmount,
+        Units: "RPL",
+        Description: "The RPL bond required to join the oracle DAO",
+    },
+    {
+        Name: trustednode.MinipoolUnbondedMaxSettingPath,
+        Aliases: []string{"members-minipool-unbonded-max", "u"},
+        ContractName: trustednode.MembersSettingsContractName,
+        Type: TNDAOSettingTypeCount,
+        Description: "The maximum number of unbonded minipools a member can run",
+    },
+    {
+        Name: trustednode.MinipoolUnbondedMinFeeSettingPath,
+        Aliases: []string{"members-minipool-unbonded-min-fee"},
+        ContractName: trustednode.MembersSettingsContractName,
+        Type: TNDAOSettingTypeFraction,
+        Min: big.NewInt(0),
+        Max: eth.EthToWei(1),
+        Description: "The minimum node commission rate at which members can create unbonded minipools",
+    },
+    {
+        Name: trustednode.ChallengeCooldownSettingPath,
+        Aliases: []string{"members-challenge-cooldown"},
+        ContractName: trustednode.MembersSettingsContractName,
+        Type: TNDAOSettingTypeCount,
+        Units: "blocks",
+        Description: "The number of blocks a member must wait between challenges",
+    },
+    {
+        Name: trustednode.ChallengeWindowSettingPath,
+        Aliases: []string{"members-challenge-window"},
+        ContractName: trustednode.MembersSettingsContractName,
+        Type: TNDAOSettingTypeCount,
+        Units: "blocks",
+        Description: "The number of blocks a challenged member has to respond before they can be removed",
+    },
+    {
+        Name: trustednode.ChallengeCostSettingPath,
+        Aliases: []string{"members-challenge-cost"},
+        ContractName: trustednode.MembersSettingsContractName,
+        Type: TNDAOSettingTypeAmount,
+        Units: "ETH",
+        Description: "The ETH fee non-members pay to challenge a member",
+    },
+    {
+        Name: trustednode.CooldownTimeSettingPath,
+        Aliases: []string{"proposal-cooldown", "c"},
+        ContractName: trustednode.ProposalsSettingsContractName,
+        Type: TNDAOSettingTypeDuration,
+        Description: "The period a member must wait between proposals",
+    },
+    {
+        Name: trustednode.VoteTimeSettingPath,
+        Aliases: []string{"proposal-vote-timespan", "v"},
+        ContractName: trustednode.ProposalsSettingsContractName,
+        Type: TNDAOSettingTypeDuration,
+        Description: "The period a proposal can be voted on",
+    },
+    {
+        Name: trustednode.VoteDelayTimeSettingPath,
+        Aliases: []string{"proposal-vote-delay-timespan", "d"},
+        ContractName: trustednode.ProposalsSettingsContractName,
+        Type: TNDAOSettingTypeDuration,
+        Description: "The delay after a proposal is created before voting on it is allowed",
+    },
+    {
+        Name: trustednode.ExecuteTimeSettingPath,
+        Aliases: []string{"proposal-execute-timespan", "x"},
+        ContractName: trustednode.ProposalsSettingsContractName,
+        Type: TNDAOSettingTypeDuration,
+        Description: "The period a successful proposal can be executed in",
+    },
+    {
+        Name: trustednode.ActionTimeSettingPath,
+        Aliases: []string{"proposal-action-timespan", "a"},
+        ContractName: trustednode.ProposalsSettingsContractName,
+        Type: TNDAOSettingTypeDuration,
+        Description: "The period an executed proposal can be acted on in",
+    },
+    {
+        Name: trustednode.ScrubPeriodPath,
+        Aliases: []string{"scrub-period", "s"},
+        ContractName: trustednode.MinipoolSettingsContractName,
+        Type: TNDAOSettingTypeDuration,
+        Description: "The period after a minipool's prelaunch during which it can be scrubbed",
+    },
+    {
+        Name: trustednode.ScrubPenaltyEnabledPath,
+        Aliases: []string{"scrub-penalty-enabled"},
+        ContractName: trustednode.MinipoolSettingsContractName,
+        Type: TNDAOSettingTypeBool,
+        Description: "Whether scrubbed minipools have their node's RPL penalised",
+    },
+}
+
+
+// Get all registered oracle DAO settings
+func GetTNDAOSettings() []TNDAOSetting {
+    settings := make([]TNDAOSetting, len(tndaoSettings))
+    copy(settings, tndaoSettings)
+    return settings
+}
+
+
+// Get a registered oracle DAO setting by name or alias
+func GetTNDAOSetting(name string) (TNDAOSetting, error) {
+    for _, setting := range tndaoSettings {
+        if setting.Name == name {
+            return setting, nil
+        }
+        for _, alias := range setting.Aliases {
+            if alias == name {
+                return setting, nil
+            }
+        }
+    }
+    return TNDAOSetting{}, fmt.Errorf("Unknown oracle DAO setting '%s'", name)
+}
+
+
+// Get the current values of a set of oracle DAO settings
+func GetTNDAOSettingValues(rp *rocketpool.RocketPool, mc *multicall.MultiCaller, settings []TNDAOSetting, opts *bind.CallOpts) ([]*big.Int, error) {
+
+    // Load values
+    uintValues := make([]*big.Int, len(settings))
+    boolValues := make([]bool, len(settings))
+    batch := mc.NewBatch()
+    for si, setting := range settings {
+        contract, err := rp.GetContract(setting.ContractName)
+        if err != nil {
+            return []*big.Int{}, err
+        }
+        if setting.Type == TNDAOSettingTypeBool {
+            err = batch.AddCall(contract, &boolValues[si], "getSettingBool", setting.Name)
+        } else {
+            err = batch.AddCall(contract, &uintValues[si], "getSettingUint", setting.Name)
+        }
+        if err != nil {
+            return []*big.Int{}, err
+        }
+    }
+    if err := batch.Execute(opts); err != nil {
+        return []*big.Int{}, fmt.Errorf("Could not get oracle DAO setting values: %w", err)
+    }
+
+    // Convert bool values
+    for si, setting := range settings {
+        if setting.Type == TNDAOSettingTypeBool {
+            uintValues[si] = big.NewInt(0)
+            if boolValues[si] { uintValues[si].SetUint64(1) }
+        }
+    }
+
+    // Return
+    return uintValues, nil
+
+}
+
+
+// Parse a setting value from its display units into its raw value
+func (s TNDAOSetting) ParseValue(value string) (*big.Int, error) {
+    value = strings.TrimSpace(value)
+    switch s.Type {
+
+        case TNDAOSettingTypeCount:
+            count, success := big.NewInt(0).SetString(value, 10)
+            if !success || count.Sign() < 0 {
+                return nil, fmt.Errorf("Invalid %s value '%s' - must be %s", s.Name, value, s.GetValueFormat())
+            }
+            return count, nil
+
+        case TNDAOSettingTypeFraction:
+            percent, success := new(big.Rat).SetString(strings.TrimSuffix(value, "%"))
+            if !success {
+                return nil, fmt.Errorf("Invalid %s value '%s' - must be a percentage (e.g. 51)", s.Name, value)
+            }
+            return ratToWei(percent.Quo(percent, big.NewRat(100, 1))), nil
+
+        case TNDAOSettingTypeAmount:
+            amount, success := new(big.Rat).SetString(value)
+            if !success {
+                return nil, fmt.Errorf("Invalid %s value '%s' - must be an amount of %s (e.g. 5000)", s.Name, value, s.Units)
+            }
+            return ratToWei(amount), nil
+
+        case TNDAOSettingTypeDuration:
+            duration, err := time.ParseDuration(value)
+            if err != nil || duration < 0 {
+                return nil, fmt.Errorf("Invalid %s value '%s' - must be a duration (e.g. 1h30m45s)", s.Name, value)
+            }
+            return big.NewInt(int64(duration / time.Second)), nil
+
+        case TNDAOSettingTypeBool:
+            enabled, err := strconv.ParseBool(value)
+            if err != nil {
+                return nil, fmt.Errorf("Invalid %s value '%s' - must be 'true' or 'false'", s.Name, value)
+            }
+            if enabled {
+                return big.NewInt(1), nil
+            }
+            return big.NewInt(0), nil
+
+    }
+    return nil, fmt.Errorf("Unknown %s setting type '%s'", s.Name, s.Type)
+}
+
+
+// Format a raw setting value in its display units
+func (s TNDAOSetting) FormatValue(value *big.Int) string {
+    if value == nil {
+        return "unknown"
+    }
+    switch s.Type {
+        case TNDAOSettingTypeFraction:
+            return strconv.FormatFloat(eth.WeiToEth(value) * 100, 'f', -1, 64) + "%"
+        case TNDAOSettingTypeAmount:
+            return strconv.FormatFloat(eth.WeiToEth(value), 'f', -1, 64) + " " + s.Units
+        case TNDAOSettingTypeDuration:
+            return (time.Duration(value.Int64()) * time.Second).String()
+        case TNDAOSettingTypeBool:
+            return strconv.FormatBool(value.Sign() != 0)
+    }
+    if s.Units != "" {
+        return value.String() + " " + s.Units
+    }
+    return value.String()
+}
+
+
+// Get a description of the setting's value format
+func (s TNDAOSetting) GetValueFormat() string {
+    switch s.Type {
+        case TNDAOSettingTypeFraction: return "a percentage (e.g. 51)"
+        case TNDAOSettingTypeAmount: return fmt.Sprintf("an amount of %s (e.g. 5000)", s.Units)
+        case TNDAOSettingTypeDuration: return "a duration (e.g. 1h30m45s)"
+        case TNDAOSettingTypeBool: return "'true' or 'false'"
+    }
+    if s.Units != "" {
+        return fmt.Sprintf("a whole number of %s (e.g. 100)", s.Units)
+    }
+    return "a whole number (e.g. 100)"
+}
+
+
+// Check that a raw setting value is within the setting's bounds
+func (s TNDAOSetting) ValidateValue(value *big.Int) error {
+    if value == nil || value.Sign() < 0 {
+        return fmt.Errorf("Invalid %s value - must not be negative", s.Name)
+    }
+    if s.Type == TNDAOSettingTypeBool && value.Cmp(big.NewInt(1)) > 0 {
+        return fmt.Errorf("Invalid %s value - must be 0 or 1", s.Name)
+    }
+    if s.Min != nil && value.Cmp(s.Min) < 0 {
+        return fmt.Errorf("Invalid %s value %s - must be at least %s", s.Name, s.FormatValue(value), s.FormatValue(s.Min))
+    }
+    if s.Max != nil && value.Cmp(s.Max) > 0 {
+        return fmt.Errorf("Invalid %s value %s - must be at most %s", s.Name, s.FormatValue(value), s.FormatValue(s.Max))
+    }
+    return nil
+}
+
+
+// Convert an ether-denominated rational to wei, rounding down
+func ratToWei(value *big.Rat) *big.Int {
+    wei := new(big.Rat).Mul(value, new(big.Rat).SetInt(eth.EthToWei(1)))
+    return new(big.Int).Quo(wei.Num(), wei.Denom())
+}
@@ -0,0 +1,139 @@
+package rp
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/rocket-pool/rocketpool-go/utils/eth"
+)
+
+
+func TestTNDAOSettingParseValue(t *testing.T) {
+    tests := []struct {
+        setting string
+        value string
+        expected *big.Int
+        valid bool
+    }{
+        {"members-minipool-unbonded-max", "30", big.NewInt(30), true},
+        {"members-minipool-unbonded-max", " 30 ", big.NewInt(30), true},
+        {"members-minipool-unbonded-max", "-1", nil, false},
+        {"members-minipool-unbonded-max", "1.5", nil, false},
+        {"members-challenge-window", "43204", big.NewInt(43204), true},
+        {"members-challenge-cooldown", "7d", nil, false},
+        {"members-quorum", "51", eth.EthToWei(0.51), true},
+        {"members-quorum", "51%", eth.EthToWei(0.51), true},
+        {"members-quorum", "0.5", big.NewInt(5000000000000000), true},
+        {"members-quorum", "half", nil, false},
+        {"members-rplbond", "1750", eth.EthToWei(1750), true},
+        {"members-rplbond", "0.000000000000000001", big.NewInt(1), true},
+        {"members-rplbond", "lots", nil, false},
+        {"proposal-vote-timespan", "1h30m", big.NewInt(5400), true},
+        {"proposal-vote-timespan", "-1h", nil, false},
+        {"proposal-vote-timespan", "3600", nil, false},
+        {"scrub-penalty-enabled", "true", big.NewInt(1), true},
+        {"scrub-penalty-enabled", "false", big.NewInt(0), true},
+        {"scrub-penalty-enabled", "yes", nil, false},
+    }
+    for _, test := range tests {
+        setting, err := GetTNDAOSetting(test.setting)
+        if err != nil {
+            t.Fatal(err)
+        }
+        value, err := setting.ParseValue(test.value)
+        if !test.valid {
+            if err == nil {
+                t.Errorf("%s: expected '%s' to be invalid, got %s", test.setting, test.value, value.String())
+            }
+            continue
+        }
+        if err != nil {
+            t.Errorf("%s: could not parse '%s': %s", test.setting, test.value, err)
+        } else if value.Cmp(test.expected) != 0 {
+            t.Errorf("%s: parsed '%s' as %s, expected %s", test.setting, test.value, value.String(), test.expected.String())
+        }
+    }
+}
+
+
+func TestTNDAOSettingFormatValue(t *testing.T) {
+    tests := []struct {
+        setting string
+        value *big.Int
+        expected string
+    }{
+        {"members-minipool-unbonded-max", big.NewInt(30), "30"},
+        {"members-challenge-window", big.NewInt(43204), "43204 blocks"},
+        {"members-quorum", eth.EthToWei(0.51), "51%"},
+        {"members-rplbond", eth.EthToWei(1750), "1750 RPL"},
+        {"members-challenge-cost", eth.EthToWei(1), "1 ETH"},
+        {"proposal-vote-timespan", big.NewInt(5400), "1h30m0s"},
+        {"scrub-penalty-enabled", big.NewInt(1), "true"},
+        {"scrub-penalty-enabled", big.NewInt(0), "false"},
+        {"members-quorum", nil, "unknown"},
+    }
+    for _, test := range tests {
+        setting, err := GetTNDAOSetting(test.setting)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if formatted := setting.FormatValue(test.value); formatted != test.expected {
+            t.Errorf("%s: formatted %v as '%s', expected '%s'", test.setting, test.value, formatted, test.expected)
+        }
+    }
+}
+
+
+func TestTNDAOSettingValidateValue(t *testing.T) {
+    tests := []struct {
+        setting string
+        value *big.Int
+        valid bool
+    }{
+        {"members-quorum", eth.EthToWei(1), true},
+        {"members-quorum", new(big.Int).Add(eth.EthToWei(1), big.NewInt(1)), false},
+        {"members-quorum", big.NewInt(-1), false},
+        {"scrub-penalty-enabled", big.NewInt(2), false},
+        {"members-challenge-window", big.NewInt(0), true},
+    }
+    for _, test := range tests {
+        setting, err := GetTNDAOSetting(test.setting)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if err := setting.ValidateValue(test.value); (err == nil) != test.valid {
+            t.Errorf("%s: validating %s returned %v, expected valid=%t", test.setting, test.value.String(), err, test.valid)
+        }
+    }
+}
+
+
+func TestGetTNDAOSetting(t *testing.T) {
+    tests := []struct {
+        name string
+        expected string
+    }{
+        {"members.quorum", "members.quorum"},
+        {"members-quorum", "members.quorum"},
+        {"q", "members.quorum"},
+        {"b", "members.rplbond"},
+        {"u", "members.minipool.unbonded.max"},
+        {"c", "proposal.cooldown.time"},
+        {"v", "proposal.vote.time"},
+        {"d", "proposal.vote.delay.time"},
+        {"x", "proposal.execute.time"},
+        {"a", "proposal.action.time"},
+        {"s", "minipool.scrub.period"},
+    }
+    for _, test := range tests {
+        setting, err := GetTNDAOSetting(test.name)
+        if err != nil {
+            t.Errorf("%s: %s", test.name, err)
+        } else if setting.Name != test.expected {
+            t.Errorf("%s: got setting %s, expected %s", test.name, setting.Name, test.expected)
+        }
+    }
+    if _, err := GetTNDAOSetting("z"); err == nil {
+        t.Error("expected an unknown setting error for 'z'")
+    }
+}
@@ -0,0 +1,460 @@
+package rp
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/rocket-pool/rocketpool-go/dao/trustednode"
+	"github.com/rocket-pool/rocketpool-go/rocketpool"
+	tnsettings "github.com/rocket-pool/rocketpool-go/settings/trustednode"
+	"github.com/rocket-pool/rocketpool-go/tokens"
+	"github.com/rocket-pool/rocketpool-go/utils/eth"
+
+	"github.com/rocket-pool/smartnode/shared/types/api"
+)
+
+
+// Reads contract state for a proposal simulation, either before or after the proposal's execution
+type tndaoStateReader func(contract *rocketpool.Contract, output interface{}, method string, params ...interface{}) error
+
+
+// An item of chain state changed by a proposal
+// Expected is the value derived from the decoded payload, used when the post-execution state can't be read
+type tndaoStateItem struct {
+    name string
+    expected string
+    get func(read tndaoStateReader) (string, error)
+}
+
+
+// An eth_call state override for an account
+type stateOverride struct {
+    Balance *hexutil.Big                    `json:"balance,omitempty"`
+    StateDiff map[common.Hash]common.Hash   `json:"stateDiff,omitempty"`
+}
+
+
+// The state changes of a call, as traced by prestateTracer in diff mode
+type tracedAccount struct {
+    Balance *hexutil.Big                    `json:"balance"`
+    Storage map[common.Hash]common.Hash     `json:"storage"`
+}
+type tracedStateDiff struct {
+    Pre map[common.Address]tracedAccount    `json:"pre"`
+    Post map[common.Address]tracedAccount   `json:"post"`
+}
+
+
+// Encode an oracle DAO proposal payload
+func EncodeTNDAOProposalPayload(rp *rocketpool.RocketPool, method string, args ...interface{}) ([]byte, error) {
+    rocketDAONodeTrustedProposals, err := rp.GetContract("rocketDAONodeTrustedProposals")
+    if err != nil {
+        return nil, err
+    }
+    payload, err := rocketDAONodeTrustedProposals.ABI.Pack(method, args...)
+    if err != nil {
+        return nil, fmt.Errorf("Could not encode %s proposal payload: %w", method, err)
+    }
+    return payload, nil
+}
+
+
+// Encode the payload of a proposal to update an oracle DAO setting
+func EncodeTNDAOSettingProposalPayload(rp *rocketpool.RocketPool, setting TNDAOSetting, value *big.Int) ([]byte, error) {
+    if setting.Type == TNDAOSettingTypeBool {
+        return EncodeTNDAOProposalPayload(rp, "proposalSettingBool", setting.ContractName, setting.Name, (value.Sign() != 0))
+    }
+    return EncodeTNDAOProposalPayload(rp, "proposalSettingUint", setting.ContractName, setting.Name, value)
+}
+
+
+// Simulate the execution of an oracle DAO proposal and describe the changes it would make
+// The payload is traced from the DAO proposal contract against the current chain state, as the proposal contract calls it when the
+// proposal is executed; the affected settings and member state are then read again with the traced state changes applied as overrides.
+// If the execution client can't trace calls (debug_traceCall with prestateTracer diff mode), Simulated is false and the changes are
+// derived from the decoded payload instead.
+func SimulateTNDAOProposal(rp *rocketpool.RocketPool, client *rpc.Client, payload []byte, opts *bind.CallOpts) (api.TNDAOProposalSimulation, error) {
+
+    // Get call options
+    if opts == nil {
+        opts = &bind.CallOpts{}
+    }
+    ctx := opts.Context
+    if ctx == nil {
+        ctx = context.Background()
+    }
+
+    // Get contract addresses
+    rocketDAOProposalAddress, err := rp.GetAddress("rocketDAOProposal")
+    if err != nil {
+        return api.TNDAOProposalSimulation{}, err
+    }
+    rocketDAONodeTrustedProposalsAddress, err := rp.GetAddress("rocketDAONodeTrustedProposals")
+    if err != nil {
+        return api.TNDAOProposalSimulation{}, err
+    }
+
+    // Check execution
+    simulation := api.TNDAOProposalSimulation{Executable: true}
+    if _, err := rp.Client.CallContract(ctx, ethereum.CallMsg{
+        From: *rocketDAOProposalAddress,
+        To: rocketDAONodeTrustedProposalsAddress,
+        Data: payload,
+    }, opts.BlockNumber); err != nil {
+        simulation.Executable = false
+        simulation.RevertReason = err.Error()
+    }
+
+    // Decode payload & get the state it changes
+    action, err := DecodeTNDAOProposalPayload(rp, payload)
+    if err != nil {
+        return api.TNDAOProposalSimulation{}, err
+    }
+    items, err := getTNDAOProposalStateItems(rp, action, opts)
+    if err != nil {
+        return api.TNDAOProposalSimulation{}, err
+    }
+
+    // Trace execution to get the post-execution state
+    var overrides map[common.Address]stateOverride
+    if simulation.Executable {
+        overrides, simulation.Simulated = traceStateOverrides(ctx, client, *rocketDAOProposalAddress, *rocketDAONodeTrustedProposalsAddress, payload, opts.BlockNumber)
+    }
+
+    // Get changes
+    readBefore := func(contract *rocketpool.Contract, output interface{}, method string, params ...interface{}) error {
+        return contract.Call(opts, output, method, params...)
+    }
+    readAfter := func(contract *rocketpool.Contract, output interface{}, method string, params ...interface{}) error {
+        return callWithOverrides(ctx, client, overrides, opts.BlockNumber, contract, output, method, params...)
+    }
+    simulation.Changes = make([]api.TNDAOProposalChange, len(items))
+    for ii, item := range items {
+        before, err := item.get(readBefore)
+        if err != nil {
+            return api.TNDAOProposalSimulation{}, fmt.Errorf("Could not get %s: %w", item.name, err)
+        }
+        after := item.expected
+        if simulation.Simulated {
+            after, err = item.get(readAfter)
+            if err != nil {
+                return api.TNDAOProposalSimulation{}, fmt.Errorf("Could not get simulated %s: %w", item.name, err)
+            }
+        }
+        simulation.Changes[ii] = api.TNDAOProposalChange{Item: item.name, Before: before, After: after}
+    }
+
+    // Return
+    return simulation, nil
+
+}
+
+
+// Trace a call and convert its state changes into eth_call state overrides
+// Returns false if the execution client doesn't support tracing state diffs
+func traceStateOverrides(ctx context.Context, client *rpc.Client, from, to common.Address, data []byte, blockNumber *big.Int) (map[common.Address]stateOverride, bool) {
+
+    // Trace call
+    var diff tracedStateDiff
+    if err := client.CallContext(ctx, &diff, "debug_traceCall", map[string]interface{}{
+        "from": from,
+        "to": to,
+        "data": hexutil.Bytes(data),
+    }, toBlockArg(blockNumber), map[string]interface{}{
+        "tracer": "prestateTracer",
+        "tracerConfig": map[string]interface{}{"diffMode": true},
+    }); err != nil {
+        return nil, false
+    }
+    if diff.Pre == nil && diff.Post == nil {
+        return nil, false
+    }
+
+    // Build overrides; storage slots which were cleared are only present in the pre-execution state
+    overrides := make(map[common.Address]stateOverride)
+    for address, account := range diff.Post {
+        overrides[address] = stateOverride{Balance: account.Balance, StateDiff: account.Storage}
+    }
+    for address, account := range diff.Pre {
+        override := overrides[address]
+        for slot := range account.Storage {
+            if _, ok := override.StateDiff[slot]; ok {
+                continue
+            }
+            if override.StateDiff == nil {
+                override.StateDiff = make(map[common.Hash]common.Hash)
+            }
+            override.StateDiff[slot] = common.Hash{}
+        }
+        if override.Balance != nil || len(override.StateDiff) > 0 {
+            overrides[address] = override
+        }
+    }
+
+    // Return
+    return overrides, true
+
+}
+
+
+// Call a contract method against the chain state with overrides applied
+func callWithOverrides(ctx context.Context, client *rpc.Client, overrides map[common.Address]stateOverride, blockNumber *big.Int, contract *rocketpool.Contract, output interface{}, method string, params ...interface{}) error {
+    input, err := contract.ABI.Pack(method, params...)
+    if err != nil {
+        return fmt.Errorf("Could not encode %s call input: %w", method, err)
+    }
+    var result hexutil.Bytes
+    if err := client.CallContext(ctx, &result, "eth_call", map[string]interface{}{
+        "to": contract.Address,
+        "data": hexutil.Bytes(input),
+    }, toBlockArg(blockNumber), overrides); err != nil {
+        return fmt.Errorf("Could not call %s: %w", method, err)
+    }
+    if err := contract.ABI.UnpackIntoInterface(output, method, result); err != nil {
+        return fmt.Errorf("Could not decode %s call output: %w", method, err)
+    }
+    return nil
+}
+
+
+// Get the state an oracle DAO proposal action changes
+func getTNDAOProposalStateItems(rp *rocketpool.RocketPool, action TNDAOProposalAction, opts *bind.CallOpts) ([]tndaoStateItem, error) {
+
+    // Get contracts
+    rocketDAONodeTrusted, err := rp.GetContract("rocketDAONodeTrusted")
+    if err != nil {
+        return nil, err
+    }
+
+    switch action.Type {
+
+        case TNDAOProposalTypeSetting:
+            setting, err := GetTNDAOSetting(action.SettingName)
+            if err != nil {
+                setting = TNDAOSetting{Name: action.SettingName, Type: TNDAOSettingTypeCount}
+            }
+            settingsContract, err := rp.GetContract(action.SettingContractName)
+            if err != nil {
+                return nil, err
+            }
+            return []tndaoStateItem{
+                {
+                    name: fmt.Sprintf("Setting %s", setting.Name),
+                    expected: setting.FormatValue(action.SettingValue),
+                    get: func(read tndaoStateReader) (string, error) {
+                        if setting.Type == TNDAOSettingTypeBool {
+                            value := new(bool)
+                            if err := read(settingsContract, value, "getSettingBool", setting.Name); err != nil {
+                                return "", err
+                            }
+                            return strconv.FormatBool(*value), nil
+                        }
+                        value := new(*big.Int)
+                        if err := read(settingsContract, value, "getSettingUint", setting.Name); err != nil {
+                            return "", err
+                        }
+                        return setting.FormatValue(*value), nil
+                    },
+                },
+            }, nil
+
+        case TNDAOProposalTypeKick:
+            rocketTokenRPL, err := rp.GetContract("rocketTokenRPL")
+            if err != nil {
+                return nil, err
+            }
+            memberCount, err := trustednode.GetMemberCount(rp, opts)
+            if err != nil {
+                return nil, err
+            }
+            rplBond, err := trustednode.GetMemberRPLBondAmount(rp, *action.Member, opts)
+            if err != nil {
+                return nil, err
+            }
+            rplBalance, err := tokens.GetRPLBalance(rp, *action.Member, opts)
+            if err != nil {
+                return nil, err
+            }
+            refund := new(big.Int).Sub(rplBond, action.RplFine)
+            if refund.Sign() < 0 { refund.SetUint64(0) }
+            return []tndaoStateItem{
+                {
+                    name: fmt.Sprintf("Member %s", action.Member.Hex()),
+                    expected: "removed",
+                    get: func(read tndaoStateReader) (string, error) {
+                        exists := new(bool)
+                        if err := read(rocketDAONodeTrusted, exists, "getMemberIsValid", *action.Member); err != nil {
+                            return "", err
+                        }
+                        if *exists {
+                            return "member", nil
+                        }
+                        return "removed", nil
+                    },
+                },
+                {
+                    name: fmt.Sprintf("Member %s RPL bond", action.Member.Hex()),
+                    expected: formatRpl(big.NewInt(0)),
+                    get: getRplAmountItem(rocketDAONodeTrusted, "getMemberRPLBondAmount", *action.Member),
+                },
+                {
+                    name: fmt.Sprintf("Member %s RPL balance (%s fined)", action.Member.Hex(), formatRpl(action.RplFine)),
+                    expected: formatRpl(new(big.Int).Add(rplBalance, refund)),
+                    get: getRplAmountItem(rocketTokenRPL, "balanceOf", *action.Member),
+                },
+                {
+                    name: "Oracle DAO member count",
+                    expected: strconv.FormatUint(memberCount - 1, 10),
+                    get: func(read tndaoStateReader) (string, error) {
+                        count := new(*big.Int)
+                        if err := read(rocketDAONodeTrusted, count, "getMemberCount"); err != nil {
+                            return "", err
+                        }
+                        return (*count).String(), nil
+                    },
+                },
+            }, nil
+
+        case TNDAOProposalTypeInvite:
+            rplBond, err := tnsettings.GetRPLBond(rp, opts)
+            if err != nil {
+                return nil, err
+            }
+            actionTime, err := tnsettings.GetProposalActionTime(rp, opts)
+            if err != nil {
+                return nil, err
+            }
+            status := fmt.Sprintf("invited; can join within %s by bonding %s", formatSeconds(actionTime), formatRpl(rplBond))
+            return []tndaoStateItem{
+                {
+                    name: fmt.Sprintf("Node %s as '%s' (%s)", action.Member.Hex(), action.MemberId, action.MemberUrl),
+                    expected: status,
+                    get: getProposalExecutedItem(rocketDAONodeTrusted, "invited", *action.Member, "not invited", status),
+                },
+            }, nil
+
+        case TNDAOProposalTypeLeave:
+            rplBond, err := trustednode.GetMemberRPLBondAmount(rp, *action.Member, opts)
+            if err != nil {
+                return nil, err
+            }
+            actionTime, err := tnsettings.GetProposalActionTime(rp, opts)
+            if err != nil {
+                return nil, err
+            }
+            status := fmt.Sprintf("can leave within %s and be refunded its %s bond", formatSeconds(actionTime), formatRpl(rplBond))
+            return []tndaoStateItem{
+                {
+                    name: fmt.Sprintf("Member %s", action.Member.Hex()),
+                    expected: status,
+                    get: getProposalExecutedItem(rocketDAONodeTrusted, "leave", *action.Member, "member", status),
+                },
+            }, nil
+
+        case TNDAOProposalTypeReplace:
+            actionTime, err := tnsettings.GetProposalActionTime(rp, opts)
+            if err != nil {
+                return nil, err
+            }
+            status := fmt.Sprintf("can be replaced as '%s' (%s) within %s", action.MemberId, action.MemberUrl, formatSeconds(actionTime))
+            return []tndaoStateItem{
+                {
+                    name: fmt.Sprintf("Member %s", action.Member.Hex()),
+                    expected: status,
+                    get: getProposalExecutedItem(rocketDAONodeTrusted, "replace", *action.Member, "member", status),
+                },
+                {
+                    name: fmt.Sprintf("Member %s replacement", action.Member.Hex()),
+                    expected: action.NewMember.Hex(),
+                    get: func(read tndaoStateReader) (string, error) {
+                        replacement := new(common.Address)
+                        if err := read(rocketDAONodeTrusted, replacement, "getMemberReplacedAddress", "new", *action.Member); err != nil {
+                            return "", err
+                        }
+                        if *replacement == (common.Address{}) {
+                            return "none", nil
+                        }
+                        return replacement.Hex(), nil
+                    },
+                },
+            }, nil
+
+        case TNDAOProposalTypeUpgrade:
+            return []tndaoStateItem{
+                {
+                    name: fmt.Sprintf("Contract %s (%s)", action.UpgradeContractName, action.UpgradeType),
+                    expected: action.UpgradeContractAddress.Hex(),
+                    get: func(read tndaoStateReader) (string, error) {
+                        address := new(common.Address)
+                        if err := read(rp.RocketStorageContract, address, "getAddress", crypto.Keccak256Hash([]byte("contract.address"), []byte(action.UpgradeContractName))); err != nil {
+                            return "", err
+                        }
+                        if *address == (common.Address{}) {
+                            return "none", nil
+                        }
+                        return address.Hex(), nil
+                    },
+                },
+            }, nil
+
+    }
+    return []tndaoStateItem{}, nil
+
+}
+
+
+// Get a state item reader for an RPL amount
+func getRplAmountItem(contract *rocketpool.Contract, method string, address common.Address) func(read tndaoStateReader) (string, error) {
+    return func(read tndaoStateReader) (string, error) {
+        amount := new(*big.Int)
+        if err := read(contract, amount, method, address); err != nil {
+            return "", err
+        }
+        return formatRpl(*amount), nil
+    }
+}
+
+
+// Get a state item reader for whether a member proposal has been executed
+func getProposalExecutedItem(rocketDAONodeTrusted *rocketpool.Contract, proposalType string, address common.Address, pendingStatus, executedStatus string) func(read tndaoStateReader) (string, error) {
+    return func(read tndaoStateReader) (string, error) {
+        executedTime := new(*big.Int)
+        if err := read(rocketDAONodeTrusted, executedTime, "getMemberProposalExecutedTime", proposalType, address); err != nil {
+            return "", err
+        }
+        if (*executedTime).Sign() == 0 {
+            return pendingStatus, nil
+        }
+        return fmt.Sprintf("%s (executed %s)", executedStatus, time.Unix((*executedTime).Int64(), 0).UTC().Format(time.RFC822)), nil
+    }
+}
+
+
+// Get the JSON-RPC block argument for a block number
+func toBlockArg(blockNumber *big.Int) string {
+    if blockNumber == nil {
+        return "latest"
+    }
+    return hexutil.EncodeBig(blockNumber)
+}
+
+
+// Format an RPL amount
+func formatRpl(amount *big.Int) string {
+    return strconv.FormatFloat(eth.WeiToEth(amount), 'f', -1, 64) + " RPL"
+}
+
+
+// Format a number of seconds
+func formatSeconds(seconds uint64) string {
+    return (time.Duration(seconds) * time.Second).String()
+}
@@ -0,0 +1,120 @@
+package rp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+
+// Serve a fixed debug_traceCall result over JSON-RPC
+func newTraceServer(t *testing.T, result string) *rpc.Client {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        var request struct {
+            ID json.RawMessage  `json:"id"`
+            Method string       `json:"method"`
+        }
+        if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+            t.Fatal(err)
+        }
+        w.Header().Set("Content-Type", "application/json")
+        if request.Method != "debug_traceCall" {
+            fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32601,"message":"method not found"}}`, request.ID)
+            return
+        }
+        fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, request.ID, result)
+    }))
+    t.Cleanup(server.Close)
+    client, err := rpc.Dial(server.URL)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return client
+}
+
+
+func TestTraceStateOverrides(t *testing.T) {
+    storage := common.HexToAddress("0x1d8f8f00cfa6758d7bE78336684788Fb0ee0Fa46")
+    member := common.HexToAddress("0x2222222222222222222222222222222222222222")
+    changedSlot := common.HexToHash("0x01")
+    clearedSlot := common.HexToHash("0x02")
+
+    tests := []struct {
+        name string
+        result string
+        simulated bool
+        check func(t *testing.T, overrides map[common.Address]stateOverride)
+    }{
+        {
+            name: "diff",
+            result: `{
+                "pre": {
+                    "` + storage.Hex() + `": {"storage": {"` + changedSlot.Hex() + `": "0x` + fmt.Sprintf("%064x", 1) + `", "` + clearedSlot.Hex() + `": "0x` + fmt.Sprintf("%064x", 5) + `"}},
+                    "` + member.Hex() + `": {"balance": "0x1"}
+                },
+                "post": {
+                    "` + storage.Hex() + `": {"storage": {"` + changedSlot.Hex() + `": "0x` + fmt.Sprintf("%064x", 2) + `"}},
+                    "` + member.Hex() + `": {"balance": "0x10"}
+                }
+            }`,
+            simulated: true,
+            check: func(t *testing.T, overrides map[common.Address]stateOverride) {
+                if len(overrides) != 2 {
+                    t.Fatalf("expected 2 overridden accounts, got %d", len(overrides))
+                }
+                if value := overrides[storage].StateDiff[changedSlot]; value != common.BigToHash(common.Big2) {
+                    t.Errorf("changed slot override is %s", value.Hex())
+                }
+                if value, ok := overrides[storage].StateDiff[clearedSlot]; !ok || value != (common.Hash{}) {
+                    t.Errorf("cleared slot override is %s (present: %t)", value.Hex(), ok)
+                }
+                if balance := overrides[member].Balance; balance == nil || balance.ToInt().Uint64() != 16 {
+                    t.Errorf("balance override is %v", balance)
+                }
+                if overrides[member].StateDiff != nil {
+                    t.Errorf("unexpected storage override for %s", member.Hex())
+                }
+            },
+        },
+        {
+            name: "no changes",
+            result: `{"pre": {}, "post": {}}`,
+            simulated: true,
+            check: func(t *testing.T, overrides map[common.Address]stateOverride) {
+                if len(overrides) != 0 {
+                    t.Errorf("expected no overrides, got %d", len(overrides))
+                }
+            },
+        },
+        {
+            name: "prestate without diff mode",
+            result: `{"` + storage.Hex() + `": {"balance": "0x0", "storage": {}}}`,
+            simulated: false,
+        },
+    }
+
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            client := newTraceServer(t, test.result)
+            overrides, simulated := traceStateOverrides(context.Background(), client, member, storage, []byte{0x01}, nil)
+            if simulated != test.simulated {
+                t.Fatalf("simulated is %t, expected %t", simulated, test.simulated)
+            }
+            if test.check != nil {
+                test.check(t, overrides)
+            }
+        })
+    }
+
+    // Clients without tracing support aren't simulated
+    client := newTraceServer(t, `null`)
+    if _, simulated := traceStateOverrides(context.Background(), client, member, storage, []byte{0x01}, nil); simulated {
+        t.Error("expected a null trace result not to be simulated")
+    }
+}
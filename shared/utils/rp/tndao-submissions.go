@@ -0,0 +1,402 @@
+package rp
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/rocketpool-go/dao/trustednode"
+	"github.com/rocket-pool/rocketpool-go/rocketpool"
+	"github.com/rocket-pool/rocketpool-go/settings/protocol"
+	"github.com/rocket-pool/rocketpool-go/utils/eth"
+
+	"github.com/rocket-pool/smartnode/shared/types/api"
+)
+
+// Oracle DAO submission types
+const (
+    TNDAOSubmissionTypeBalances = "balances"
+    TNDAOSubmissionTypePrices = "prices"
+)
+
+// Oracle DAO member submission results
+const (
+    TNDAOSubmissionOnTime = "ok"
+    TNDAOSubmissionLate = "late"
+    TNDAOSubmissionDiverged = "diverged"
+    TNDAOSubmissionMissing = "missing"
+    TNDAOSubmissionPending = "pending"
+    TNDAOSubmissionNotMember = "n/a"
+)
+
+// The number of submission rounds audited if no start block is given
+const TNDAOSubmissionsDefaultRounds = 10
+
+// The default number of blocks after a round's block that a submission is considered late
+// Submissions made after consensus is reached are rejected, so late members usually appear as missing instead
+const TNDAOSubmissionsDefaultLateBlocks = 150
+
+
+// A member submission for a single round
+type tndaoSubmission struct {
+    values []*big.Int
+    logBlock uint64
+}
+
+// A single submission round, identified by the block the submitted values are for; its time is that block's timestamp
+type tndaoSubmissionRound struct {
+    block uint64
+    time uint64
+    consensus bool
+    consensusLogBlock uint64
+    consensusValues []*big.Int
+    submissions map[common.Address]tndaoSubmission
+}
+
+
+// Audit the oracle DAO members' balances or prices submissions over a block range
+// Each member's submission for each round is compared against the consensus value, and is late if mined more than lateBlocks after the round's block;
+// a zero start or end block audits the latest rounds. Rounds for blocks before the start block are excluded, as their submissions may have been
+// made before the audited range.
+func AuditTNDAOSubmissions(rp *rocketpool.RocketPool, submissionType string, fromBlock, toBlock, lateBlocks uint64, intervalSize *big.Int) (api.TNDAOSubmissionsAudit, error) {
+
+    // Get the submission contract & events
+    contract, submittedEvent, updatedEvent, frequency, err := getTNDAOSubmissionContract(rp, submissionType)
+    if err != nil {
+        return api.TNDAOSubmissionsAudit{}, err
+    }
+
+    // Get the block range
+    if toBlock == 0 {
+        toBlock, err = rp.Client.BlockNumber(context.Background())
+        if err != nil {
+            return api.TNDAOSubmissionsAudit{}, fmt.Errorf("Could not get the latest block number: %w", err)
+        }
+    }
+    if fromBlock == 0 && toBlock > (TNDAOSubmissionsDefaultRounds * frequency) {
+        fromBlock = toBlock - (TNDAOSubmissionsDefaultRounds * frequency)
+    }
+    if fromBlock > toBlock {
+        return api.TNDAOSubmissionsAudit{}, fmt.Errorf("The start block %d is after the end block %d", fromBlock, toBlock)
+    }
+
+    // Get the submitted value names
+    valueNames := getSubmissionValueNames(submittedEvent)
+
+    // Get event logs
+    if intervalSize != nil {
+        intervalSize = new(big.Int).Set(intervalSize)
+    }
+    addressFilter := []common.Address{*contract.Address}
+    topicFilter := [][]common.Hash{{submittedEvent.ID, updatedEvent.ID}}
+    logs, err := eth.GetLogs(rp, addressFilter, topicFilter, intervalSize, new(big.Int).SetUint64(fromBlock), new(big.Int).SetUint64(toBlock), nil)
+    if err != nil {
+        return api.TNDAOSubmissionsAudit{}, fmt.Errorf("Could not get %s submission events: %w", submissionType, err)
+    }
+
+    // Group events into rounds
+    rounds := map[uint64]*tndaoSubmissionRound{}
+    for _, log := range logs {
+
+        // Decode event
+        if len(log.Topics) == 0 {
+            continue
+        }
+        values := make(map[string]interface{})
+        isSubmission := (log.Topics[0] == submittedEvent.ID)
+        if isSubmission {
+            err = submittedEvent.Inputs.UnpackIntoMap(values, log.Data)
+        } else {
+            err = updatedEvent.Inputs.UnpackIntoMap(values, log.Data)
+        }
+        if err != nil {
+            return api.TNDAOSubmissionsAudit{}, fmt.Errorf("Could not decode %s submission event in transaction %s: %w", submissionType, log.TxHash.Hex(), err)
+        }
+        eventValues, err := getSubmissionEventValues(values, valueNames)
+        if err != nil {
+            return api.TNDAOSubmissionsAudit{}, fmt.Errorf("Could not decode %s submission event in transaction %s: %w", submissionType, log.TxHash.Hex(), err)
+        }
+        blockValue, success := values["block"].(*big.Int)
+        if !success {
+            return api.TNDAOSubmissionsAudit{}, fmt.Errorf("Could not decode %s submission event in transaction %s: missing block", submissionType, log.TxHash.Hex())
+        }
+        if blockValue.Uint64() < fromBlock {
+            continue
+        }
+
+        // Add to round
+        round, exists := rounds[blockValue.Uint64()]
+        if !exists {
+            round = &tndaoSubmissionRound{block: blockValue.Uint64(), submissions: map[common.Address]tndaoSubmission{}}
+            rounds[round.block] = round
+        }
+        if isSubmission {
+            if len(log.Topics) < 2 {
+                continue
+            }
+            memberAddress := common.BytesToAddress(log.Topics[1].Bytes())
+            if _, exists := round.submissions[memberAddress]; !exists {
+                round.submissions[memberAddress] = tndaoSubmission{values: eventValues, logBlock: log.BlockNumber}
+            }
+        } else {
+            round.consensus = true
+            round.consensusLogBlock = log.BlockNumber
+            round.consensusValues = eventValues
+        }
+
+    }
+
+    // Sort rounds, get their block times & use the most common submission as the reference value for rounds without consensus
+    sortedRounds := make([]*tndaoSubmissionRound, 0, len(rounds))
+    for _, round := range rounds {
+        header, err := rp.Client.HeaderByNumber(context.Background(), new(big.Int).SetUint64(round.block))
+        if err != nil {
+            return api.TNDAOSubmissionsAudit{}, fmt.Errorf("Could not get block %d: %w", round.block, err)
+        }
+        round.time = header.Time
+        if !round.consensus {
+            round.consensusValues = getMostCommonSubmission(round.submissions)
+        }
+        sortedRounds = append(sortedRounds, round)
+    }
+    sort.Slice(sortedRounds, func(i, j int) bool { return sortedRounds[i].block < sortedRounds[j].block })
+
+    // Get members, including former members with submissions in range
+    members, err := trustednode.GetMembers(rp, nil)
+    if err != nil {
+        return api.TNDAOSubmissionsAudit{}, err
+    }
+    auditRounds, auditMembers := auditTNDAOSubmissionRounds(sortedRounds, members, lateBlocks)
+    audit := api.TNDAOSubmissionsAudit{
+        Type: submissionType,
+        FromBlock: fromBlock,
+        ToBlock: toBlock,
+        ValueNames: valueNames,
+        Rounds: auditRounds,
+        Members: auditMembers,
+    }
+
+    // Return
+    return audit, nil
+
+}
+
+
+// Classify each member's submission for each round, in block order, as on time, late, diverged from the consensus value, or missing
+// Former members with submissions in the rounds are included after the current members
+func auditTNDAOSubmissionRounds(rounds []*tndaoSubmissionRound, members []trustednode.MemberDetails, lateBlocks uint64) ([]api.TNDAOSubmissionRound, []api.TNDAOMemberSubmissions) {
+
+    // Get members, including former members with submissions
+    memberJoinedTimes := map[common.Address]uint64{}
+    auditRounds := make([]api.TNDAOSubmissionRound, len(rounds))
+    auditMembers := []api.TNDAOMemberSubmissions{}
+    for _, member := range members {
+        memberJoinedTimes[member.Address] = member.JoinedTime
+        auditMembers = append(auditMembers, api.TNDAOMemberSubmissions{Address: member.Address, Id: member.ID})
+    }
+    for _, round := range rounds {
+        for memberAddress := range round.submissions {
+            if _, exists := memberJoinedTimes[memberAddress]; !exists {
+                memberJoinedTimes[memberAddress] = 0
+                auditMembers = append(auditMembers, api.TNDAOMemberSubmissions{Address: memberAddress})
+            }
+        }
+    }
+
+    // Audit submissions
+    for ri, round := range rounds {
+        auditRounds[ri] = api.TNDAOSubmissionRound{
+            Block: round.block,
+            Time: round.time,
+            Consensus: round.consensus,
+            ConsensusBlock: round.consensusLogBlock,
+            Values: round.consensusValues,
+        }
+        isLatestRound := (ri == len(rounds) - 1)
+        for mi := range auditMembers {
+            member := &auditMembers[mi]
+            submission, submitted := round.submissions[member.Address]
+            var result string
+            switch {
+                case !submitted && memberJoinedTimes[member.Address] > round.time:
+                    result = TNDAOSubmissionNotMember
+                case !submitted && !round.consensus && isLatestRound:
+                    result = TNDAOSubmissionPending
+                case !submitted:
+                    result = TNDAOSubmissionMissing
+                    member.Missing++
+                case !submissionValuesEqual(submission.values, round.consensusValues):
+                    result = TNDAOSubmissionDiverged
+                    member.Diverged++
+                case submission.logBlock > round.block + lateBlocks:
+                    result = TNDAOSubmissionLate
+                    member.Late++
+                default:
+                    result = TNDAOSubmissionOnTime
+                    member.OnTime++
+            }
+            if submitted && round.block > member.LatestSubmittedBlock {
+                member.LatestSubmittedBlock = round.block
+            }
+            member.Results = append(member.Results, result)
+        }
+    }
+
+    // Return
+    return auditRounds, auditMembers
+
+}
+
+
+// Get the values a member submitted for a balances or prices round, identified by the block the values are for
+// Submissions are searched for from the round's block until one reporting interval later; returns false if the member did not submit
+func GetTNDAOMemberSubmission(rp *rocketpool.RocketPool, submissionType string, memberAddress common.Address, block uint64, intervalSize *big.Int) ([]string, []*big.Int, bool, error) {
+
+    // Get the submission contract & event
+    contract, submittedEvent, _, frequency, err := getTNDAOSubmissionContract(rp, submissionType)
+    if err != nil {
+        return nil, nil, false, err
+    }
+    valueNames := getSubmissionValueNames(submittedEvent)
+
+    // Get the block range
+    latestBlock, err := rp.Client.BlockNumber(context.Background())
+    if err != nil {
+        return nil, nil, false, fmt.Errorf("Could not get the latest block number: %w", err)
+    }
+    toBlock := block + frequency
+    if toBlock > latestBlock {
+        toBlock = latestBlock
+    }
+    if block > toBlock {
+        return valueNames, nil, false, nil
+    }
+
+    // Get the member's submission events
+    if intervalSize != nil {
+        intervalSize = new(big.Int).Set(intervalSize)
+    }
+    addressFilter := []common.Address{*contract.Address}
+    topicFilter := [][]common.Hash{{submittedEvent.ID}, {common.BytesToHash(memberAddress.Bytes())}}
+    logs, err := eth.GetLogs(rp, addressFilter, topicFilter, intervalSize, new(big.Int).SetUint64(block), new(big.Int).SetUint64(toBlock), nil)
+    if err != nil {
+        return nil, nil, false, fmt.Errorf("Could not get %s submission events: %w", submissionType, err)
+    }
+
+    // Find the submission for the round
+    for _, log := range logs {
+        values := make(map[string]interface{})
+        if err := submittedEvent.Inputs.UnpackIntoMap(values, log.Data); err != nil {
+            return nil, nil, false, fmt.Errorf("Could not decode %s submission event in transaction %s: %w", submissionType, log.TxHash.Hex(), err)
+        }
+        blockValue, success := values["block"].(*big.Int)
+        if !success || blockValue.Uint64() != block {
+            continue
+        }
+        eventValues, err := getSubmissionEventValues(values, valueNames)
+        if err != nil {
+            return nil, nil, false, fmt.Errorf("Could not decode %s submission event in transaction %s: %w", submissionType, log.TxHash.Hex(), err)
+        }
+        return valueNames, eventValues, true, nil
+    }
+
+    // Return
+    return valueNames, nil, false, nil
+
+}
+
+
+// Get the contract, submitted & updated events, and submission frequency for a submission type
+func getTNDAOSubmissionContract(rp *rocketpool.RocketPool, submissionType string) (*rocketpool.Contract, abi.Event, abi.Event, uint64, error) {
+    var contractName, eventPrefix string
+    var frequency uint64
+    var err error
+    switch submissionType {
+        case TNDAOSubmissionTypeBalances:
+            contractName, eventPrefix = "rocketNetworkBalances", "Balances"
+            frequency, err = protocol.GetSubmitBalancesFrequency(rp, nil)
+        case TNDAOSubmissionTypePrices:
+            contractName, eventPrefix = "rocketNetworkPrices", "Prices"
+            frequency, err = protocol.GetSubmitPricesFrequency(rp, nil)
+        default:
+            return nil, abi.Event{}, abi.Event{}, 0, fmt.Errorf("Unknown submission type '%s'; must be '%s' or '%s'", submissionType, TNDAOSubmissionTypeBalances, TNDAOSubmissionTypePrices)
+    }
+    if err != nil {
+        return nil, abi.Event{}, abi.Event{}, 0, err
+    }
+    contract, err := rp.GetContract(contractName)
+    if err != nil {
+        return nil, abi.Event{}, abi.Event{}, 0, err
+    }
+    submittedEvent, exists := contract.ABI.Events[eventPrefix + "Submitted"]
+    if !exists {
+        return nil, abi.Event{}, abi.Event{}, 0, fmt.Errorf("Contract %s has no %sSubmitted event", contractName, eventPrefix)
+    }
+    updatedEvent, exists := contract.ABI.Events[eventPrefix + "Updated"]
+    if !exists {
+        return nil, abi.Event{}, abi.Event{}, 0, fmt.Errorf("Contract %s has no %sUpdated event", contractName, eventPrefix)
+    }
+    return contract, submittedEvent, updatedEvent, frequency, nil
+}
+
+
+// Get the submitted value names from a submission event; all non-indexed event fields except the block & time
+func getSubmissionValueNames(submittedEvent abi.Event) []string {
+    valueNames := []string{}
+    for _, input := range submittedEvent.Inputs {
+        if !input.Indexed && input.Name != "block" && input.Name != "time" {
+            valueNames = append(valueNames, input.Name)
+        }
+    }
+    return valueNames
+}
+
+
+// Get the submitted values from a decoded submission event
+func getSubmissionEventValues(values map[string]interface{}, valueNames []string) ([]*big.Int, error) {
+    eventValues := make([]*big.Int, len(valueNames))
+    for vi, name := range valueNames {
+        value, success := values[name].(*big.Int)
+        if !success {
+            return nil, fmt.Errorf("missing %s value", name)
+        }
+        eventValues[vi] = value
+    }
+    return eventValues, nil
+}
+
+
+// Get the most common set of submitted values in a round
+func getMostCommonSubmission(submissions map[common.Address]tndaoSubmission) []*big.Int {
+    counts := map[string]int{}
+    var mostCommon []*big.Int
+    var mostCommonKey string
+    for _, submission := range submissions {
+        key := getSubmissionKey(submission.values)
+        counts[key]++
+        if mostCommon == nil || counts[key] > counts[mostCommonKey] || (counts[key] == counts[mostCommonKey] && key < mostCommonKey) {
+            mostCommon, mostCommonKey = submission.values, key
+        }
+    }
+    return mostCommon
+}
+
+
+// Check whether two sets of submitted values are equal
+func submissionValuesEqual(a, b []*big.Int) bool {
+    return getSubmissionKey(a) == getSubmissionKey(b)
+}
+
+
+// Get a comparable key for a set of submitted values
+func getSubmissionKey(values []*big.Int) string {
+    parts := make([]string, len(values))
+    for vi, value := range values {
+        parts[vi] = value.String()
+    }
+    return strings.Join(parts, ",")
+}
@@ -0,0 +1,163 @@
+package rp
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/rocketpool-go/dao/trustednode"
+)
+
+
+func TestAuditTNDAOSubmissionRounds(t *testing.T) {
+    memberA := common.HexToAddress("0x1111111111111111111111111111111111111111")
+    memberB := common.HexToAddress("0x2222222222222222222222222222222222222222")
+    memberC := common.HexToAddress("0x3333333333333333333333333333333333333333")
+    formerMember := common.HexToAddress("0x4444444444444444444444444444444444444444")
+    members := []trustednode.MemberDetails{
+        {Address: memberA, ID: "a", JoinedTime: 1000},
+        {Address: memberB, ID: "b", JoinedTime: 1000},
+        {Address: memberC, ID: "c", JoinedTime: 3500},
+    }
+    values := func(v ...int64) []*big.Int {
+        result := make([]*big.Int, len(v))
+        for vi, value := range v {
+            result[vi] = big.NewInt(value)
+        }
+        return result
+    }
+
+    // Rounds at blocks 100, 200 & 300; the latest has not reached consensus
+    rounds := []*tndaoSubmissionRound{
+        {
+            block: 100,
+            time: 2000,
+            consensus: true,
+            consensusLogBlock: 110,
+            consensusValues: values(1, 2),
+            submissions: map[common.Address]tndaoSubmission{
+                memberA: {values: values(1, 2), logBlock: 105},
+                memberB: {values: values(1, 2), logBlock: 160},
+                formerMember: {values: values(1, 2), logBlock: 104},
+            },
+        },
+        {
+            block: 200,
+            time: 3000,
+            consensus: true,
+            consensusLogBlock: 210,
+            consensusValues: values(3, 4),
+            submissions: map[common.Address]tndaoSubmission{
+                memberA: {values: values(3, 5), logBlock: 205},
+                memberB: {values: values(3, 4), logBlock: 250},
+            },
+        },
+        {
+            block: 300,
+            time: 4000,
+            consensusValues: values(5, 6),
+            submissions: map[common.Address]tndaoSubmission{
+                memberA: {values: values(5, 6), logBlock: 301},
+            },
+        },
+    }
+    auditRounds, auditMembers := auditTNDAOSubmissionRounds(rounds, members, 50)
+
+    // Check rounds
+    if len(auditRounds) != 3 {
+        t.Fatalf("got %d rounds, expected 3", len(auditRounds))
+    }
+    if round := auditRounds[0]; round.Block != 100 || round.Time != 2000 || !round.Consensus || round.ConsensusBlock != 110 || !submissionValuesEqual(round.Values, values(1, 2)) {
+        t.Errorf("first round is %+v", round)
+    }
+    if auditRounds[2].Consensus {
+        t.Error("latest round has consensus, expected none")
+    }
+
+    // Check member results; submissions more than 50 blocks after the round are late, and members who joined after a round are not counted
+    expected := []struct {
+        address common.Address
+        id string
+        results []string
+        onTime int
+        late int
+        diverged int
+        missing int
+        latestSubmittedBlock uint64
+    }{
+        {memberA, "a", []string{TNDAOSubmissionOnTime, TNDAOSubmissionDiverged, TNDAOSubmissionOnTime}, 2, 0, 1, 0, 300},
+        {memberB, "b", []string{TNDAOSubmissionLate, TNDAOSubmissionOnTime, TNDAOSubmissionPending}, 1, 1, 0, 0, 200},
+        {memberC, "c", []string{TNDAOSubmissionNotMember, TNDAOSubmissionNotMember, TNDAOSubmissionPending}, 0, 0, 0, 0, 0},
+        {formerMember, "", []string{TNDAOSubmissionOnTime, TNDAOSubmissionMissing, TNDAOSubmissionPending}, 1, 0, 0, 1, 100},
+    }
+    if len(auditMembers) != len(expected) {
+        t.Fatalf("got %d members, expected %d", len(auditMembers), len(expected))
+    }
+    for mi, member := range auditMembers {
+        e := expected[mi]
+        if member.Address != e.address || member.Id != e.id {
+            t.Errorf("member %d is %s (%s), expected %s (%s)", mi, member.Address.Hex(), member.Id, e.address.Hex(), e.id)
+            continue
+        }
+        if !reflect.DeepEqual(member.Results, e.results) {
+            t.Errorf("member %s results are %v, expected %v", e.id, member.Results, e.results)
+        }
+        if member.OnTime != e.onTime || member.Late != e.late || member.Diverged != e.diverged || member.Missing != e.missing {
+            t.Errorf("member %s counts are %d on time, %d late, %d diverged, %d missing; expected %d, %d, %d, %d", e.id,
+                member.OnTime, member.Late, member.Diverged, member.Missing, e.onTime, e.late, e.diverged, e.missing)
+        }
+        if member.LatestSubmittedBlock != e.latestSubmittedBlock {
+            t.Errorf("member %s latest submitted block is %d, expected %d", e.id, member.LatestSubmittedBlock, e.latestSubmittedBlock)
+        }
+    }
+
+}
+
+
+func TestAuditTNDAOSubmissionRoundsMissing(t *testing.T) {
+    member := common.HexToAddress("0x1111111111111111111111111111111111111111")
+    members := []trustednode.MemberDetails{{Address: member, ID: "a"}}
+
+    // Members missing the latest round are pending until it reaches consensus
+    round := &tndaoSubmissionRound{block: 100, time: 2000, submissions: map[common.Address]tndaoSubmission{}}
+    _, auditMembers := auditTNDAOSubmissionRounds([]*tndaoSubmissionRound{round}, members, 50)
+    if results := auditMembers[0].Results; !reflect.DeepEqual(results, []string{TNDAOSubmissionPending}) || auditMembers[0].Missing != 0 {
+        t.Errorf("results without consensus are %v with %d missing, expected pending", results, auditMembers[0].Missing)
+    }
+    round.consensus = true
+    _, auditMembers = auditTNDAOSubmissionRounds([]*tndaoSubmissionRound{round}, members, 50)
+    if results := auditMembers[0].Results; !reflect.DeepEqual(results, []string{TNDAOSubmissionMissing}) || auditMembers[0].Missing != 1 {
+        t.Errorf("results with consensus are %v with %d missing, expected missing", results, auditMembers[0].Missing)
+    }
+
+    // Submissions at the late limit are on time
+    round.submissions[member] = tndaoSubmission{logBlock: 150}
+    _, auditMembers = auditTNDAOSubmissionRounds([]*tndaoSubmissionRound{round}, members, 50)
+    if results := auditMembers[0].Results; !reflect.DeepEqual(results, []string{TNDAOSubmissionOnTime}) {
+        t.Errorf("results at the late limit are %v, expected on time", results)
+    }
+
+}
+
+
+func TestGetMostCommonSubmission(t *testing.T) {
+    submissions := map[common.Address]tndaoSubmission{
+        common.HexToAddress("0x01"): {values: []*big.Int{big.NewInt(2)}},
+        common.HexToAddress("0x02"): {values: []*big.Int{big.NewInt(1)}},
+        common.HexToAddress("0x03"): {values: []*big.Int{big.NewInt(2)}},
+    }
+    if mostCommon := getMostCommonSubmission(submissions); !submissionValuesEqual(mostCommon, []*big.Int{big.NewInt(2)}) {
+        t.Errorf("most common submission is %v, expected [2]", mostCommon)
+    }
+
+    // Ties are broken by value
+    delete(submissions, common.HexToAddress("0x03"))
+    if mostCommon := getMostCommonSubmission(submissions); !submissionValuesEqual(mostCommon, []*big.Int{big.NewInt(1)}) {
+        t.Errorf("most common tied submission is %v, expected [1]", mostCommon)
+    }
+    if mostCommon := getMostCommonSubmission(map[common.Address]tndaoSubmission{}); mostCommon != nil {
+        t.Errorf("most common of no submissions is %v, expected none", mostCommon)
+    }
+
+}